@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// ErrNoMorePages is returned by Pager.Next once every page has been read
+var ErrNoMorePages = errors.New("no more pages")
+
+// PageFunc fetches one offset-based page
+type PageFunc[T any] func(ctx context.Context, offset, limit int) (*models.Paging[T], error)
+
+// CursorPageFunc fetches one cursor-based page, starting after the given cursor
+type CursorPageFunc[T any] func(ctx context.Context, after string, limit int) (*models.CursorPaging[T], error)
+
+// Pager walks a paginated endpoint page by page. It works with both
+// offset-based (Paging) and cursor-based (CursorPaging) endpoints.
+type Pager[T any] struct {
+	fetch    func(ctx context.Context, limit int) ([]T, bool, int, error)
+	pageSize int
+	maxItems int
+	fetched  int
+	total    int
+	done     bool
+}
+
+// NewPager creates a pager for an offset-based endpoint starting at offset
+func NewPager[T any](pageSize, offset int, fetch PageFunc[T]) *Pager[T] {
+	p := &Pager[T]{pageSize: pageSize, total: -1}
+
+	p.fetch = func(ctx context.Context, limit int) ([]T, bool, int, error) {
+		page, err := fetch(ctx, offset, limit)
+		if err != nil {
+			return nil, false, 0, err
+		}
+
+		offset += len(page.Items)
+		return page.Items, page.Next != "", page.Total, nil
+	}
+
+	return p
+}
+
+// NewCursorPager creates a pager for a cursor-based endpoint starting after
+// the given cursor ("" for the first page)
+func NewCursorPager[T any](pageSize int, after string, fetch CursorPageFunc[T]) *Pager[T] {
+	p := &Pager[T]{pageSize: pageSize, total: -1}
+
+	p.fetch = func(ctx context.Context, limit int) ([]T, bool, int, error) {
+		page, err := fetch(ctx, after, limit)
+		if err != nil {
+			return nil, false, 0, err
+		}
+
+		after = page.Cursors.After
+		total := page.Total
+		if total == 0 {
+			total = -1
+		}
+		return page.Items, page.Next != "" && after != "", total, nil
+	}
+
+	return p
+}
+
+// WithMaxItems stops the pager after n items. Zero means no limit.
+func (p *Pager[T]) WithMaxItems(n int) *Pager[T] {
+	p.maxItems = n
+	return p
+}
+
+// HasNext reports whether another call to Next may return items
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Total returns the total number of items reported by the API, or -1 if it
+// is not known yet
+func (p *Pager[T]) Total() int {
+	return p.total
+}
+
+// Fetched returns the number of items returned so far
+func (p *Pager[T]) Fetched() int {
+	return p.fetched
+}
+
+// Next fetches the next page. It returns ErrNoMorePages once the endpoint
+// is exhausted or the item limit has been reached.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+
+	limit := p.pageSize
+	if p.maxItems > 0 && p.maxItems-p.fetched < limit {
+		limit = p.maxItems - p.fetched
+	}
+
+	items, hasNext, total, err := p.fetch(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if total >= 0 {
+		p.total = total
+	}
+
+	if p.maxItems > 0 && p.fetched+len(items) > p.maxItems {
+		items = items[:p.maxItems-p.fetched]
+	}
+	p.fetched += len(items)
+
+	if !hasNext || len(items) == 0 || (p.maxItems > 0 && p.fetched >= p.maxItems) {
+		p.done = true
+	}
+
+	return items, nil
+}
+
+// All reads every remaining page and returns the combined items
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	if p.total > 0 {
+		all = make([]T, 0, p.total)
+	}
+
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+
+	return all, nil
+}
+
+// Each calls fn for every remaining item, stopping at the first error
+func (p *Pager[T]) Each(ctx context.Context, fn func(item T) error) error {
+	for p.HasNext() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
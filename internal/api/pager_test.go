@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// fakePages serves items in offset-based pages the way Spotify does
+func fakePages(items []int, calls *int) PageFunc[int] {
+	return func(ctx context.Context, offset, limit int) (*models.Paging[int], error) {
+		*calls++
+		end := offset + limit
+		if end > len(items) {
+			end = len(items)
+		}
+
+		page := &models.Paging[int]{Items: items[offset:end], Offset: offset, Limit: limit, Total: len(items)}
+		if end < len(items) {
+			page.Next = fmt.Sprintf("https://api.spotify.com/v1/me/tracks?offset=%d&limit=%d", end, limit)
+		}
+		return page, nil
+	}
+}
+
+func sequence(n int) []int {
+	items := make([]int, n)
+	for i := range items {
+		items[i] = i
+	}
+	return items
+}
+
+func TestPager_All(t *testing.T) {
+	calls := 0
+	pager := NewPager(10, 0, fakePages(sequence(25), &calls))
+
+	items, err := pager.All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(items) != 25 {
+		t.Fatalf("Expected 25 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if item != i {
+			t.Fatalf("Expected item %d at position %d, got %d", i, i, item)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 page requests, got %d", calls)
+	}
+	if pager.Total() != 25 || pager.Fetched() != 25 {
+		t.Errorf("Expected total and fetched of 25, got %d and %d", pager.Total(), pager.Fetched())
+	}
+
+	if _, err := pager.Next(context.Background()); !errors.Is(err, ErrNoMorePages) {
+		t.Errorf("Expected ErrNoMorePages after the last page, got %v", err)
+	}
+}
+
+func TestPager_MaxItems(t *testing.T) {
+	calls := 0
+	pager := NewPager(10, 0, fakePages(sequence(100), &calls)).WithMaxItems(15)
+
+	items, err := pager.All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(items) != 15 {
+		t.Errorf("Expected 15 items, got %d", len(items))
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 page requests, got %d", calls)
+	}
+}
+
+func TestPager_StartOffset(t *testing.T) {
+	calls := 0
+	items, err := NewPager(10, 20, fakePages(sequence(25), &calls)).All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+
+	if len(items) != 5 || items[0] != 20 {
+		t.Errorf("Expected items 20-24, got %v", items)
+	}
+}
+
+func TestPager_Error(t *testing.T) {
+	calls := 0
+	pager := NewPager(10, 0, func(ctx context.Context, offset, limit int) (*models.Paging[int], error) {
+		calls++
+		if offset > 0 {
+			return nil, errors.New("boom")
+		}
+		return &models.Paging[int]{Items: sequence(10), Next: "next"}, nil
+	})
+
+	items, err := pager.All(context.Background())
+	if err == nil {
+		t.Fatal("Expected error from second page")
+	}
+	if len(items) != 10 {
+		t.Errorf("Expected items from the first page to be returned, got %d", len(items))
+	}
+}
+
+func TestPager_EmptyPageStops(t *testing.T) {
+	calls := 0
+	pager := NewPager(10, 0, func(ctx context.Context, offset, limit int) (*models.Paging[int], error) {
+		calls++
+		return &models.Paging[int]{Next: "next"}, nil
+	})
+
+	if _, err := pager.All(context.Background()); err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected an empty page to stop paging, got %d requests", calls)
+	}
+}
+
+func TestCursorPager(t *testing.T) {
+	pages := map[string]*models.CursorPaging[string]{
+		"":   {Items: []string{"a", "b"}, Next: "next", Cursors: models.CursorPagingObj{After: "b"}, Total: 3},
+		"b":  {Items: []string{"c"}, Cursors: models.CursorPagingObj{After: ""}, Total: 3},
+		"zz": {Items: []string{"unexpected"}},
+	}
+
+	var cursors []string
+	pager := NewCursorPager(2, "", func(ctx context.Context, after string, limit int) (*models.CursorPaging[string], error) {
+		cursors = append(cursors, after)
+		return pages[after], nil
+	})
+
+	var items []string
+	err := pager.Each(context.Background(), func(item string) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Each failed: %v", err)
+	}
+
+	if len(items) != 3 || items[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", items)
+	}
+	if len(cursors) != 2 || cursors[1] != "b" {
+		t.Errorf("Expected the after cursor to be followed, got %v", cursors)
+	}
+	if pager.Total() != 3 {
+		t.Errorf("Expected total 3, got %d", pager.Total())
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -155,6 +156,17 @@ func (rb *RequestBuilder) Put(ctx context.Context, endpoint string, body interfa
 	return rb.responseHandler.ParseResponse(resp, result)
 }
 
+// PutRaw performs a PUT request with a body that is sent as is, with the
+// given content type
+func (rb *RequestBuilder) PutRaw(ctx context.Context, endpoint string, body []byte, contentType string, result interface{}) error {
+	resp, err := rb.client.PutWithContentType(ctx, endpoint, bytes.NewReader(body), contentType)
+	if err != nil {
+		return err
+	}
+
+	return rb.responseHandler.ParseResponse(resp, result)
+}
+
 // Delete performs a DELETE request
 func (rb *RequestBuilder) Delete(ctx context.Context, endpoint string, params QueryParams) error {
 	url := rb.buildURL(endpoint, params)
@@ -102,17 +102,17 @@ func (rh *ResponseHandler) ParsePaginatedResponse(resp *http.Response, v interfa
 func (rh *ResponseHandler) handleErrorResponse(resp *http.Response) error {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return errors.NewAPIError(fmt.Sprintf("HTTP %d: failed to read error response", resp.StatusCode))
+		return errors.NewStatusError(resp.StatusCode, fmt.Sprintf("HTTP %d: failed to read error response", resp.StatusCode))
 	}
 
 	// Try to parse Spotify error format
 	var errorResp models.ErrorResponse
 	if err := json.Unmarshal(body, &errorResp); err == nil {
-		return errors.NewAPIError(fmt.Sprintf("HTTP %d: %s", resp.StatusCode, errorResp.Error.Message))
+		return errors.NewStatusError(resp.StatusCode, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, errorResp.Error.Message))
 	}
 
 	// Fallback to generic error message
-	return errors.NewAPIError(fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)))
+	return errors.NewStatusError(resp.StatusCode, fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)))
 }
 
 // PaginationInfo contains pagination metadata
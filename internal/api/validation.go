@@ -21,6 +21,10 @@ func NewValidator() *Validator {
 var (
 	spotifyIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
 	spotifyURIPattern = regexp.MustCompile(`^spotify:([a-z]+):([0-9A-Za-z]{22})$`)
+
+	// User IDs are usernames, not base62 IDs: older accounts have the name
+	// picked at sign-up, newer ones a generated 25-28 character string
+	spotifyUserIDPattern = regexp.MustCompile(`^[0-9A-Za-z._-]{1,64}$`)
 )
 
 // ValidateSpotifyID validates a Spotify ID format
@@ -99,6 +103,53 @@ func (v *Validator) ValidateMarket(market string) error {
 	return nil
 }
 
+// ValidateMarketAvailable checks a market code against the markets Spotify
+// reports as available, such as the list returned by GET /markets
+func (v *Validator) ValidateMarketAvailable(market string, available []string) error {
+	if err := v.ValidateMarket(market); err != nil {
+		return err
+	}
+
+	if market == "" || market == "from_token" {
+		return nil
+	}
+
+	market = strings.ToUpper(market)
+	for _, code := range available {
+		if strings.ToUpper(code) == market {
+			return nil
+		}
+	}
+
+	return errors.NewValidationError(fmt.Sprintf("Spotify is not available in market %s", market))
+}
+
+// ValidateGenreSeeds checks recommendation genre seeds against the genres
+// Spotify accepts, such as the list returned by
+// GET /recommendations/available-genre-seeds
+func (v *Validator) ValidateGenreSeeds(genres []string, available []string) error {
+	known := make(map[string]bool, len(available))
+	for _, genre := range available {
+		known[genre] = true
+	}
+
+	var unknown []string
+	for _, genre := range genres {
+		if genre == "" {
+			return errors.NewValidationError("genre seed cannot be empty")
+		}
+		if !known[genre] {
+			unknown = append(unknown, genre)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return errors.NewValidationError(fmt.Sprintf("unknown genre seed(s): %s (run 'spotify-cli genres' for the list)", strings.Join(unknown, ", ")))
+	}
+
+	return nil
+}
+
 // ValidateLimit validates limit parameters for pagination
 func (v *Validator) ValidateLimit(limit, min, max int) error {
 	if limit < min {
@@ -266,4 +317,28 @@ func (v *Validator) NormalizeAndValidateIDs(input []string) ([]string, error) {
 	}
 
 	return normalized, nil
-}
\ No newline at end of file
+}
+
+// NormalizeUserIDs normalizes and validates a list of user IDs, spotify:user
+// URIs or open.spotify.com/user URLs
+func (v *Validator) NormalizeUserIDs(input []string) ([]string, error) {
+	if len(input) == 0 {
+		return nil, errors.NewValidationError("at least one user ID is required")
+	}
+
+	normalized := make([]string, len(input))
+
+	for i, item := range input {
+		id := strings.TrimPrefix(item, "spotify:user:")
+		if u, err := url.Parse(item); err == nil && u.Host == "open.spotify.com" && strings.HasPrefix(u.Path, "/user/") {
+			id = strings.TrimPrefix(u.Path, "/user/")
+		}
+
+		if !spotifyUserIDPattern.MatchString(id) {
+			return nil, errors.NewValidationError(fmt.Sprintf("invalid Spotify user ID: %s", item))
+		}
+		normalized[i] = id
+	}
+
+	return normalized, nil
+}
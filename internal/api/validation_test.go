@@ -133,6 +133,57 @@ func TestValidator_ValidateMarket(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateMarketAvailable(t *testing.T) {
+	validator := NewValidator()
+	available := []string{"GB", "SE", "US"}
+
+	tests := []struct {
+		name    string
+		market  string
+		wantErr bool
+	}{
+		{"available market", "SE", false},
+		{"lowercase available market", "se", false},
+		{"well-formed but unavailable", "XX", true},
+		{"from_token", "from_token", false},
+		{"invalid format", "SWE", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateMarketAvailable(tt.market, available)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMarketAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateGenreSeeds(t *testing.T) {
+	validator := NewValidator()
+	available := []string{"acoustic", "hip-hop", "rock"}
+
+	tests := []struct {
+		name    string
+		genres  []string
+		wantErr bool
+	}{
+		{"known genres", []string{"rock", "hip-hop"}, false},
+		{"unknown genre", []string{"rock", "hiphop"}, true},
+		{"empty genre", []string{""}, true},
+		{"no genres", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateGenreSeeds(tt.genres, available)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGenreSeeds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidator_ValidateLimit(t *testing.T) {
 	validator := NewValidator()
 
@@ -372,4 +423,30 @@ func TestValidator_NormalizeAndValidateIDs(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestValidator_NormalizeUserIDs(t *testing.T) {
+	validator := NewValidator()
+
+	result, err := validator.NormalizeUserIDs([]string{
+		"jmperezperez",
+		"spotify:user:smedjan",
+		"https://open.spotify.com/user/31l77fd5tjhuuwdlbbgxevsmxgsq?si=abc",
+		"first.last-1",
+	})
+	if err != nil {
+		t.Fatalf("NormalizeUserIDs() error = %v", err)
+	}
+
+	expected := []string{"jmperezperez", "smedjan", "31l77fd5tjhuuwdlbbgxevsmxgsq", "first.last-1"}
+	for i, id := range result {
+		if id != expected[i] {
+			t.Errorf("NormalizeUserIDs() result[%d] = %v, expected %v", i, id, expected[i])
+		}
+	}
+
+	for _, invalid := range [][]string{{}, {""}, {"a,b"}, {"spotify:user:"}, {"with space"}} {
+		if _, err := validator.NormalizeUserIDs(invalid); err == nil {
+			t.Errorf("NormalizeUserIDs(%q) expected an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+// Package apikey issues and checks restricted API keys for the local control
+// server. A key only permits the operations it was minted with, so a
+// home-automation integration holding a "player" key can skip tracks but can
+// never touch playlists.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// Operation is a single action a key may be allowed to perform
+type Operation string
+
+// Operations understood by the control server
+const (
+	OpStatus         Operation = "status"
+	OpPlay           Operation = "play"
+	OpPause          Operation = "pause"
+	OpNext           Operation = "next"
+	OpPrevious       Operation = "previous"
+	OpSeek           Operation = "seek"
+	OpVolume         Operation = "volume"
+	OpShuffle        Operation = "shuffle"
+	OpRepeat         Operation = "repeat"
+	OpQueueAdd       Operation = "queue:add"
+	OpTransfer       Operation = "transfer"
+	OpPlaylistRead   Operation = "playlist:read"
+	OpPlaylistModify Operation = "playlist:modify"
+)
+
+// AllOperations lists every operation, in display order
+var AllOperations = []Operation{
+	OpStatus, OpPlay, OpPause, OpNext, OpPrevious, OpSeek, OpVolume, OpShuffle,
+	OpRepeat, OpQueueAdd, OpTransfer, OpPlaylistRead, OpPlaylistModify,
+}
+
+// Roles are named operation presets
+var Roles = map[string][]Operation{
+	"status": {OpStatus},
+	"player": {OpStatus, OpPlay, OpPause, OpNext, OpPrevious, OpSeek, OpVolume, OpShuffle, OpRepeat},
+	"dj":     {OpStatus, OpPlay, OpPause, OpNext, OpPrevious, OpSeek, OpVolume, OpShuffle, OpRepeat, OpQueueAdd, OpTransfer, OpPlaylistRead},
+	"full":   AllOperations,
+}
+
+// StoreName is the state store holding API keys
+const StoreName = "apikeys"
+
+const tokenPrefix = "spk_"
+
+var (
+	// ErrInvalidKey is returned for unknown, malformed or revoked keys
+	ErrInvalidKey = errors.New("invalid API key")
+	// ErrExpiredKey is returned for keys past their expiry time
+	ErrExpiredKey = errors.New("API key has expired")
+	// ErrForbidden is returned when a valid key does not permit an operation
+	ErrForbidden = errors.New("API key does not permit this operation")
+)
+
+// Key is a stored API key. Only a hash of the secret is kept.
+type Key struct {
+	ID         string      `json:"id" yaml:"id"`
+	Name       string      `json:"name" yaml:"name"`
+	Role       string      `json:"role,omitempty" yaml:"role,omitempty"`
+	Operations []Operation `json:"operations" yaml:"operations"`
+	Hash       string      `json:"hash,omitempty" yaml:"-"`
+	CreatedAt  time.Time   `json:"created_at" yaml:"created_at"`
+	ExpiresAt  *time.Time  `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty" yaml:"last_used_at,omitempty"`
+}
+
+// Public returns a copy of the key without its hash, for display
+func (k *Key) Public() *Key {
+	public := *k
+	public.Hash = ""
+	return &public
+}
+
+// Allows reports whether the key permits op
+func (k *Key) Allows(op Operation) bool {
+	for _, allowed := range k.Operations {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the key has expired at now
+func (k *Key) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
+
+// ParseOperations resolves a role name and/or explicit operations into a
+// sorted, de-duplicated operation list
+func ParseOperations(role string, ops []string) ([]Operation, error) {
+	set := map[Operation]bool{}
+
+	if role != "" {
+		preset, ok := Roles[role]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %q (available: %s)", role, strings.Join(RoleNames(), ", "))
+		}
+		for _, op := range preset {
+			set[op] = true
+		}
+	}
+
+	for _, name := range ops {
+		op := Operation(strings.TrimSpace(name))
+		if !isOperation(op) {
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+		set[op] = true
+	}
+
+	if len(set) == 0 {
+		return nil, fmt.Errorf("a key needs a role or at least one operation")
+	}
+
+	var result []Operation
+	for _, op := range AllOperations {
+		if set[op] {
+			result = append(result, op)
+		}
+	}
+	return result, nil
+}
+
+// RoleNames returns the role names in sorted order
+func RoleNames() []string {
+	names := make([]string, 0, len(Roles))
+	for name := range Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isOperation(op Operation) bool {
+	for _, known := range AllOperations {
+		if known == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Store manages API keys in a state directory
+type Store struct {
+	dir *state.Dir
+	now func() time.Time
+}
+
+type keyFile struct {
+	Keys []*Key `json:"keys"`
+}
+
+// NewStore returns a key store backed by dir
+func NewStore(dir *state.Dir) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// Create mints a key with the given operations. The returned token is the
+// only copy of the secret; it cannot be recovered later. A zero ttl creates a
+// key that never expires.
+func (s *Store) Create(name, role string, ops []Operation, ttl time.Duration) (string, *Key, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, fmt.Errorf("key name is required")
+	}
+
+	id, err := randomHex(4)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return "", nil, err
+	}
+	token := tokenPrefix + id + "_" + secret
+
+	key := &Key{
+		ID:         id,
+		Name:       name,
+		Role:       role,
+		Operations: ops,
+		Hash:       hashToken(token),
+		CreatedAt:  s.now().UTC(),
+	}
+	if ttl > 0 {
+		expires := key.CreatedAt.Add(ttl)
+		key.ExpiresAt = &expires
+	}
+
+	var file keyFile
+	err = s.dir.Update(StoreName, &file, func() error {
+		for _, existing := range file.Keys {
+			if existing.Name == name {
+				return fmt.Errorf("a key named %q already exists", name)
+			}
+		}
+		file.Keys = append(file.Keys, key)
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, key, nil
+}
+
+// List returns every key, oldest first
+func (s *Store) List() ([]*Key, error) {
+	var file keyFile
+	if err := s.dir.Read(StoreName, &file); err != nil {
+		return nil, err
+	}
+	return file.Keys, nil
+}
+
+// Revoke deletes the key with the given ID or name
+func (s *Store) Revoke(idOrName string) (*Key, error) {
+	var revoked *Key
+	var file keyFile
+
+	err := s.dir.Update(StoreName, &file, func() error {
+		for i, key := range file.Keys {
+			if key.ID == idOrName || key.Name == idOrName {
+				revoked = key
+				file.Keys = append(file.Keys[:i], file.Keys[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("no API key %q", idOrName)
+	})
+
+	return revoked, err
+}
+
+// Authenticate checks a token and returns its key
+func (s *Store) Authenticate(token string) (*Key, error) {
+	id, ok := tokenID(token)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	keys, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashToken(token)
+	for _, key := range keys {
+		if key.ID != id {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash)) != 1 {
+			return nil, ErrInvalidKey
+		}
+		if key.Expired(s.now()) {
+			return nil, ErrExpiredKey
+		}
+		s.touch(key)
+		return key, nil
+	}
+
+	return nil, ErrInvalidKey
+}
+
+// Authorize checks a token and that its key permits op
+func (s *Store) Authorize(token string, op Operation) (*Key, error) {
+	key, err := s.Authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Allows(op) {
+		return key, fmt.Errorf("%w: %s", ErrForbidden, op)
+	}
+	return key, nil
+}
+
+// touch records when a key was last used, at most once a minute so busy
+// integrations do not rewrite the store on every request
+func (s *Store) touch(key *Key) {
+	now := s.now().UTC()
+	if key.LastUsedAt != nil && now.Sub(*key.LastUsedAt) < time.Minute {
+		return
+	}
+
+	var file keyFile
+	s.dir.Update(StoreName, &file, func() error {
+		for _, stored := range file.Keys {
+			if stored.ID == key.ID {
+				stored.LastUsedAt = &now
+			}
+		}
+		return nil
+	})
+}
+
+func tokenID(token string) (string, bool) {
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, tokenPrefix), "_")
+	return id, ok && id != "" && secret != ""
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
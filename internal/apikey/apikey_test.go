@@ -0,0 +1,125 @@
+package apikey
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func newTestStore(t *testing.T) *Store {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	return NewStore(dir)
+}
+
+func TestParseOperations(t *testing.T) {
+	ops, err := ParseOperations("status", []string{"pause", "next", "pause"})
+	if err != nil {
+		t.Fatalf("ParseOperations failed: %v", err)
+	}
+	if len(ops) != 3 || ops[0] != OpStatus || ops[1] != OpPause || ops[2] != OpNext {
+		t.Errorf("Expected status, pause, next in display order, got %v", ops)
+	}
+
+	if _, err := ParseOperations("admin", nil); err == nil {
+		t.Error("Expected unknown role to be rejected")
+	}
+	if _, err := ParseOperations("", []string{"delete-everything"}); err == nil {
+		t.Error("Expected unknown operation to be rejected")
+	}
+	if _, err := ParseOperations("", nil); err == nil {
+		t.Error("Expected a key without operations to be rejected")
+	}
+}
+
+func TestStore_Authorize(t *testing.T) {
+	store := newTestStore(t)
+
+	token, key, err := store.Create("home-assistant", "player", Roles["player"], 0)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := store.Authorize(token, OpNext); err != nil {
+		t.Errorf("Expected player key to allow next, got %v", err)
+	}
+	if _, err := store.Authorize(token, OpPlaylistModify); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected player key to be forbidden from modifying playlists, got %v", err)
+	}
+	if _, err := store.Authorize(token+"x", OpNext); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected tampered key to be invalid, got %v", err)
+	}
+
+	keys, _ := store.List()
+	if len(keys) != 1 || keys[0].LastUsedAt == nil || keys[0].Hash == token {
+		t.Errorf("Expected one hashed key with a last-used time, got %+v", keys)
+	}
+
+	if _, _, err := store.Create("home-assistant", "", Roles["status"], 0); err == nil {
+		t.Error("Expected duplicate key names to be rejected")
+	}
+
+	if _, err := store.Revoke(key.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, err := store.Authorize(token, OpNext); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected revoked key to be invalid, got %v", err)
+	}
+}
+
+func TestStore_ExpiredKey(t *testing.T) {
+	store := newTestStore(t)
+
+	token, _, err := store.Create("temp", "", []Operation{OpStatus}, time.Hour)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := store.Authenticate(token); !errors.Is(err, ErrExpiredKey) {
+		t.Errorf("Expected expired key error, got %v", err)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	store := newTestStore(t)
+	token, _, err := store.Create("deck", "", []Operation{OpPause}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		op       Operation
+		header   string
+		value    string
+		expected int
+	}{
+		{OpPause, "Authorization", "Bearer " + token, http.StatusNoContent},
+		{OpPause, "X-API-Key", token, http.StatusNoContent},
+		{OpPlay, "Authorization", "Bearer " + token, http.StatusForbidden},
+		{OpPause, "Authorization", "Bearer spk_bad_key", http.StatusUnauthorized},
+		{OpPause, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/v1/pause", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		store.Require(tt.op, ok).ServeHTTP(rec, req)
+
+		if rec.Code != tt.expected {
+			t.Errorf("%s with %s: expected %d, got %d", tt.op, tt.header, tt.expected, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Require wraps next so it only runs for requests carrying a key that
+// permits op. Keys are read from "Authorization: Bearer <key>" or the
+// X-API-Key header.
+func (s *Store) Require(op Operation, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := RequestToken(r)
+		if token == "" {
+			writeError(w, http.StatusUnauthorized, "missing API key")
+			return
+		}
+
+		if _, err := s.Authorize(token, op); err != nil {
+			switch {
+			case errors.Is(err, ErrForbidden):
+				writeError(w, http.StatusForbidden, err.Error())
+			case errors.Is(err, ErrInvalidKey), errors.Is(err, ErrExpiredKey):
+				writeError(w, http.StatusUnauthorized, err.Error())
+			default:
+				writeError(w, http.StatusInternalServerError, "failed to check API key")
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestToken extracts the API key from a request
+func RequestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,206 @@
+// Package audit keeps an append-only log of every request that changed
+// Spotify data: library saves, follows and playlist edits. Each entry records
+// enough about the request to describe it later and, where possible, the
+// requests that reverse it, which is what 'audit undo' replays.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the audit log, one JSON entry per line
+const StoreName = "audit.jsonl"
+
+// Request is an API request, used to describe how to undo an entry
+type Request struct {
+	Method   string          `json:"method" yaml:"method"`
+	Endpoint string          `json:"endpoint" yaml:"endpoint"`
+	Body     json.RawMessage `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// Entry is one recorded write operation
+type Entry struct {
+	ID         int       `json:"id" yaml:"id"`
+	Time       time.Time `json:"time" yaml:"time"`
+	Command    string    `json:"command,omitempty" yaml:"command,omitempty"`
+	Method     string    `json:"method" yaml:"method"`
+	Endpoint   string    `json:"endpoint" yaml:"endpoint"`
+	Action     string    `json:"action" yaml:"action"`
+	Target     string    `json:"target,omitempty" yaml:"target,omitempty"`
+	Items      []string  `json:"items,omitempty" yaml:"items,omitempty"`
+	ItemCount  int       `json:"item_count" yaml:"item_count"`
+	SnapshotID string    `json:"snapshot_id,omitempty" yaml:"snapshot_id,omitempty"`
+	Status     int       `json:"status" yaml:"status"`
+	Error      string    `json:"error,omitempty" yaml:"error,omitempty"`
+	Undo       []Request `json:"undo,omitempty" yaml:"undo,omitempty"`
+	// Undoes is the ID of the entry this one reversed
+	Undoes int `json:"undoes,omitempty" yaml:"undoes,omitempty"`
+}
+
+// Succeeded reports whether the request was accepted by the API
+func (e *Entry) Succeeded() bool {
+	return e.Error == "" && e.Status >= 200 && e.Status < 300
+}
+
+// Undoable reports whether the entry can be reversed
+func (e *Entry) Undoable() bool {
+	return e.Succeeded() && len(e.Undo) > 0
+}
+
+// Log is the audit log of a state directory
+type Log struct {
+	dir *state.Dir
+}
+
+// Open returns the audit log kept in dir
+func Open(dir *state.Dir) *Log {
+	return &Log{dir: dir}
+}
+
+// Append assigns the next ID to entry and appends it to the log
+func (l *Log) Append(entry *Entry) error {
+	return l.dir.WithLock(StoreName, func() error {
+		entries, err := l.readUnlocked()
+		if err != nil {
+			return err
+		}
+
+		entry.ID = 1
+		if len(entries) > 0 {
+			entry.ID = entries[len(entries)-1].ID + 1
+		}
+		if entry.Time.IsZero() {
+			entry.Time = time.Now().UTC()
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit entry: %w", err)
+		}
+
+		file, err := os.OpenFile(l.dir.Path(StoreName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write audit log: %w", err)
+		}
+		return file.Close()
+	})
+}
+
+// Entries returns every entry, oldest first
+func (l *Log) Entries() ([]Entry, error) {
+	var entries []Entry
+	err := l.dir.WithLock(StoreName, func() error {
+		var err error
+		entries, err = l.readUnlocked()
+		return err
+	})
+	return entries, err
+}
+
+// Get returns the entry with the given ID
+func (l *Log) Get(id int) (*Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no audit entry %d", id)
+}
+
+// UndoneBy returns the ID of the entry that reversed id, or 0
+func UndoneBy(entries []Entry, id int) int {
+	for _, entry := range entries {
+		if entry.Undoes == id && entry.Succeeded() {
+			return entry.ID
+		}
+	}
+	return 0
+}
+
+// Filter selects entries for List
+type Filter struct {
+	Target string
+	Action string
+	Since  time.Time
+	Limit  int
+}
+
+// Matches reports whether entry passes the filter
+func (f Filter) Matches(entry Entry) bool {
+	if f.Target != "" && entry.Target != f.Target {
+		return false
+	}
+	if f.Action != "" && !strings.HasPrefix(entry.Action, f.Action) {
+		return false
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	return true
+}
+
+// List returns matching entries, newest first
+func (l *Log) List(filter Filter) ([]Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !filter.Matches(entries[i]) {
+			continue
+		}
+		result = append(result, entries[i])
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (l *Log) readUnlocked() ([]Entry, error) {
+	data, err := os.ReadFile(l.dir.Path(StoreName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(text, &entry); err != nil {
+			// A torn final line from a crash should not hide the rest of the log
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
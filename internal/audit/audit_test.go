@@ -0,0 +1,172 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func openTestLog(t *testing.T) (*Log, *state.Dir) {
+	t.Helper()
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	return Open(dir), dir
+}
+
+func TestLog_AppendAndList(t *testing.T) {
+	log, dir := openTestLog(t)
+
+	for _, target := range []string{"tracks", "playlist1", "tracks"} {
+		if err := log.Append(&Entry{Method: "PUT", Action: "library.save", Target: target, Status: 200}); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+
+	entries, err := log.List(Filter{})
+	if err != nil {
+		t.Fatalf("Failed to list: %v", err)
+	}
+	if len(entries) != 3 || entries[0].ID != 3 || entries[2].ID != 1 {
+		t.Fatalf("Expected entries 3,2,1, got %+v", entries)
+	}
+
+	tracks, _ := log.List(Filter{Target: "tracks", Limit: 1})
+	if len(tracks) != 1 || tracks[0].ID != 3 {
+		t.Errorf("Expected only entry 3, got %+v", tracks)
+	}
+
+	// A torn final line is skipped and the next entry still gets a fresh ID
+	file, _ := os.OpenFile(dir.Path(StoreName), os.O_WRONLY|os.O_APPEND, 0600)
+	file.WriteString(`{"id":4,"meth`)
+	file.Close()
+
+	entry := &Entry{Method: "DELETE", Action: "library.remove", Status: 200}
+	if err := log.Append(entry); err != nil {
+		t.Fatalf("Failed to append after torn line: %v", err)
+	}
+	if entry.ID != 4 {
+		t.Errorf("Expected ID 4 after torn line, got %d", entry.ID)
+	}
+
+	got, err := log.Get(2)
+	if err != nil || got.Target != "playlist1" {
+		t.Errorf("Expected entry 2 for playlist1, got %+v, %v", got, err)
+	}
+	if _, err := log.Get(99); err == nil {
+		t.Error("Expected error for missing entry")
+	}
+}
+
+func TestNewEntry_Library(t *testing.T) {
+	entry := NewEntry("spotify-cli library save", "PUT", "/me/tracks?ids=a,b", nil, 200, nil, nil)
+	if entry.Action != "library.save" || entry.Target != "tracks" || entry.ItemCount != 2 {
+		t.Fatalf("Unexpected entry %+v", entry)
+	}
+	if len(entry.Undo) != 1 || entry.Undo[0].Method != "DELETE" || entry.Undo[0].Endpoint != "/me/tracks?ids=a,b" {
+		t.Errorf("Unexpected undo %+v", entry.Undo)
+	}
+
+	follow := NewEntry("", "PUT", "/me/following", []byte(`{"type":"artist","ids":"x,y"}`), 204, nil, nil)
+	if follow.Action != "follow" || follow.Target != "artist" || follow.ItemCount != 2 {
+		t.Fatalf("Unexpected entry %+v", follow)
+	}
+	if follow.Undo[0].Method != "DELETE" || follow.Undo[0].Endpoint != "/me/following?ids=x%2Cy&type=artist" {
+		t.Errorf("Unexpected undo %+v", follow.Undo)
+	}
+
+	failed := NewEntry("", "PUT", "/me/tracks?ids=a", nil, 403, nil, errors.New("forbidden"))
+	if failed.Undoable() {
+		t.Error("Expected failed entry not to be undoable")
+	}
+
+	if NewEntry("", "PUT", "/me/player/play", nil, 204, nil, nil) != nil {
+		t.Error("Expected playback control not to be recorded")
+	}
+}
+
+func TestNewEntry_Playlist(t *testing.T) {
+	add := NewEntry("", "POST", "/playlists/p1/tracks", []byte(`{"uris":["spotify:track:a","spotify:track:b"]}`), 201, []byte(`{"snapshot_id":"snap1"}`), nil)
+	if add.Action != "playlist.add" || add.Target != "p1" || add.SnapshotID != "snap1" || add.ItemCount != 2 {
+		t.Fatalf("Unexpected entry %+v", add)
+	}
+	if add.Undo[0].Method != "DELETE" || string(add.Undo[0].Body) != `{"tracks":[{"uri":"spotify:track:a"},{"uri":"spotify:track:b"}]}` {
+		t.Errorf("Unexpected undo %+v", add.Undo)
+	}
+
+	remove := NewEntry("", "DELETE", "/playlists/p1/tracks",
+		[]byte(`{"tracks":[{"uri":"spotify:track:b","positions":[5]},{"uri":"spotify:track:a","positions":[2]},{"uri":"spotify:track:c"}]}`), 200, nil, nil)
+	if len(remove.Undo) != 3 {
+		t.Fatalf("Expected 3 undo requests, got %+v", remove.Undo)
+	}
+	wantBodies := []string{
+		`{"position":2,"uris":["spotify:track:a"]}`,
+		`{"position":5,"uris":["spotify:track:b"]}`,
+		`{"uris":["spotify:track:c"]}`,
+	}
+	for i, want := range wantBodies {
+		if string(remove.Undo[i].Body) != want {
+			t.Errorf("Undo %d: expected %s, got %s", i, want, remove.Undo[i].Body)
+		}
+	}
+
+	create := NewEntry("", "POST", "/users/me/playlists", []byte(`{"name":"New"}`), 201, []byte(`{"id":"p9"}`), nil)
+	if create.Action != "playlist.create" || create.Undo[0].Endpoint != "/playlists/p9/followers" {
+		t.Errorf("Unexpected entry %+v", create)
+	}
+
+	replace := NewEntry("", "PUT", "/playlists/p1/tracks", []byte(`{"uris":["spotify:track:a"]}`), 200, nil, nil)
+	if replace.Action != "playlist.replace" || replace.Undoable() {
+		t.Errorf("Expected replace not to be undoable, got %+v", replace)
+	}
+}
+
+func TestNewEntry_ReorderUndo(t *testing.T) {
+	tests := []struct {
+		start, before, length int
+	}{
+		{start: 5, before: 2, length: 1},
+		{start: 1, before: 6, length: 2},
+		{start: 0, before: 4, length: 3},
+	}
+
+	for _, tt := range tests {
+		list := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		body, _ := json.Marshal(map[string]int{"range_start": tt.start, "insert_before": tt.before, "range_length": tt.length})
+		entry := NewEntry("", "PUT", "/playlists/p1/tracks", body, 200, nil, nil)
+		if entry.Action != "playlist.reorder" || len(entry.Undo) != 1 {
+			t.Fatalf("Unexpected entry %+v", entry)
+		}
+
+		var undo struct {
+			RangeStart   int `json:"range_start"`
+			InsertBefore int `json:"insert_before"`
+			RangeLength  int `json:"range_length"`
+		}
+		json.Unmarshal(entry.Undo[0].Body, &undo)
+
+		moved := reorder(reorder(list, tt.start, tt.before, tt.length), undo.RangeStart, undo.InsertBefore, undo.RangeLength)
+		for i := range list {
+			if moved[i] != list[i] {
+				t.Errorf("Reorder %+v undone by %+v gave %v", tt, undo, moved)
+				break
+			}
+		}
+	}
+}
+
+// reorder applies a playlist reorder the way the Spotify API does
+func reorder(list []int, start, before, length int) []int {
+	block := append([]int(nil), list[start:start+length]...)
+	rest := append(append([]int(nil), list[:start]...), list[start+length:]...)
+	if before > start {
+		before -= length
+	}
+	result := append(append([]int(nil), rest[:before]...), block...)
+	return append(result, rest[before:]...)
+}
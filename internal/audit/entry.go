@@ -0,0 +1,305 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// librarySections are the /me collections that are saved with PUT and
+// removed with DELETE
+var librarySections = map[string]bool{
+	"tracks":   true,
+	"albums":   true,
+	"shows":    true,
+	"episodes": true,
+}
+
+// NewEntry builds an audit entry for a completed write request. It returns
+// nil for requests that are not recorded: reads, and playback control under
+// /me/player, which changes what is playing rather than any stored data.
+func NewEntry(command, method, endpoint string, requestBody []byte, status int, responseBody []byte, err error) *Entry {
+	if method == http.MethodGet || method == "" {
+		return nil
+	}
+
+	path, query := splitEndpoint(endpoint)
+	if path == "/me/player" || strings.HasPrefix(path, "/me/player/") {
+		return nil
+	}
+
+	entry := &Entry{
+		Time:     time.Now().UTC(),
+		Command:  command,
+		Method:   method,
+		Endpoint: endpoint,
+		Action:   strings.ToLower(method),
+		Status:   status,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	var response struct {
+		ID         string `json:"id"`
+		SnapshotID string `json:"snapshot_id"`
+	}
+	if len(responseBody) > 0 {
+		json.Unmarshal(responseBody, &response)
+	}
+	entry.SnapshotID = response.SnapshotID
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(segments) == 2 && segments[0] == "me" && librarySections[segments[1]]:
+		describeLibrary(entry, segments[1], path, query, requestBody)
+	case len(segments) == 2 && segments[0] == "me" && segments[1] == "following":
+		describeFollowing(entry, path, query, requestBody)
+	case len(segments) == 3 && segments[0] == "users" && segments[2] == "playlists" && method == http.MethodPost:
+		entry.Action = "playlist.create"
+		entry.Target = response.ID
+		if response.ID != "" {
+			entry.Undo = []Request{{Method: http.MethodDelete, Endpoint: "/playlists/" + response.ID + "/followers"}}
+		}
+	case len(segments) >= 2 && segments[0] == "playlists":
+		describePlaylist(entry, segments, query, requestBody)
+	default:
+		entry.Target = path
+	}
+
+	entry.ItemCount = len(entry.Items)
+	return entry
+}
+
+// describeLibrary fills in a save or remove of library items
+func describeLibrary(entry *Entry, section, path string, query url.Values, body []byte) {
+	entry.Target = section
+	entry.Items = requestIDs(query, body)
+
+	undoMethod := http.MethodDelete
+	switch entry.Method {
+	case http.MethodPut:
+		entry.Action = "library.save"
+	case http.MethodDelete:
+		entry.Action = "library.remove"
+		undoMethod = http.MethodPut
+	default:
+		return
+	}
+
+	if len(entry.Items) > 0 {
+		entry.Undo = []Request{{Method: undoMethod, Endpoint: path + "?ids=" + strings.Join(entry.Items, ",")}}
+	}
+}
+
+// describeFollowing fills in a follow or unfollow of artists or users
+func describeFollowing(entry *Entry, path string, query url.Values, body []byte) {
+	followType := query.Get("type")
+	if followType == "" {
+		var params struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(body, &params)
+		followType = params.Type
+	}
+
+	entry.Target = followType
+	entry.Items = requestIDs(query, body)
+
+	undoMethod := http.MethodDelete
+	switch entry.Method {
+	case http.MethodPut:
+		entry.Action = "follow"
+	case http.MethodDelete:
+		entry.Action = "unfollow"
+		undoMethod = http.MethodPut
+	default:
+		return
+	}
+
+	if len(entry.Items) > 0 && followType != "" {
+		values := url.Values{"type": {followType}, "ids": {strings.Join(entry.Items, ",")}}
+		entry.Undo = []Request{{Method: undoMethod, Endpoint: path + "?" + values.Encode()}}
+	}
+}
+
+// describePlaylist fills in a change to a playlist's details, followers or items
+func describePlaylist(entry *Entry, segments []string, query url.Values, body []byte) {
+	playlistID := segments[1]
+	entry.Target = playlistID
+
+	if len(segments) == 2 {
+		entry.Action = "playlist.update"
+		return
+	}
+
+	switch segments[2] {
+	case "followers":
+		endpoint := "/playlists/" + playlistID + "/followers"
+		switch entry.Method {
+		case http.MethodPut:
+			entry.Action = "playlist.follow"
+			entry.Undo = []Request{{Method: http.MethodDelete, Endpoint: endpoint}}
+		case http.MethodDelete:
+			entry.Action = "playlist.unfollow"
+			entry.Undo = []Request{{Method: http.MethodPut, Endpoint: endpoint}}
+		}
+	case "images":
+		entry.Action = "playlist.cover"
+	case "tracks":
+		describePlaylistItems(entry, playlistID, query, body)
+	}
+}
+
+// removedItem is one entry of a playlist item removal request
+type removedItem struct {
+	URI       string `json:"uri"`
+	Positions []int  `json:"positions"`
+}
+
+// describePlaylistItems fills in an add, remove, reorder or replace of
+// playlist items
+func describePlaylistItems(entry *Entry, playlistID string, query url.Values, body []byte) {
+	endpoint := "/playlists/" + playlistID + "/tracks"
+
+	var request struct {
+		URIs         []string      `json:"uris"`
+		Tracks       []removedItem `json:"tracks"`
+		Position     *int          `json:"position"`
+		RangeStart   *int          `json:"range_start"`
+		InsertBefore *int          `json:"insert_before"`
+		RangeLength  *int          `json:"range_length"`
+	}
+	json.Unmarshal(body, &request)
+	if len(request.URIs) == 0 && query.Get("uris") != "" {
+		request.URIs = strings.Split(query.Get("uris"), ",")
+	}
+
+	switch entry.Method {
+	case http.MethodPost:
+		entry.Action = "playlist.add"
+		entry.Items = request.URIs
+		if len(request.URIs) > 0 {
+			tracks := make([]map[string]string, len(request.URIs))
+			for i, uri := range request.URIs {
+				tracks[i] = map[string]string{"uri": uri}
+			}
+			entry.Undo = []Request{newRequest(http.MethodDelete, endpoint, map[string]interface{}{"tracks": tracks})}
+		}
+
+	case http.MethodDelete:
+		entry.Action = "playlist.remove"
+		for _, track := range request.Tracks {
+			entry.Items = append(entry.Items, track.URI)
+		}
+		entry.Undo = restoreRemovedItems(endpoint, request.Tracks)
+
+	case http.MethodPut:
+		if request.RangeStart == nil || request.InsertBefore == nil {
+			// Replacing every item loses the old list, so it cannot be undone
+			entry.Action = "playlist.replace"
+			entry.Items = request.URIs
+			return
+		}
+
+		entry.Action = "playlist.reorder"
+		start, before, length := *request.RangeStart, *request.InsertBefore, 1
+		if request.RangeLength != nil {
+			length = *request.RangeLength
+		}
+
+		// Move the block back from where it landed to where it came from
+		undo := map[string]interface{}{"range_length": length}
+		if before > start {
+			undo["range_start"] = before - length
+			undo["insert_before"] = start
+		} else {
+			undo["range_start"] = before
+			undo["insert_before"] = start + length
+		}
+		entry.Undo = []Request{newRequest(http.MethodPut, endpoint, undo)}
+	}
+}
+
+// restoreRemovedItems returns the requests that put removed items back.
+// Items removed from known positions are re-inserted one at a time in
+// ascending position order, which rebuilds the original order; the rest are
+// appended.
+func restoreRemovedItems(endpoint string, tracks []removedItem) []Request {
+	type placed struct {
+		uri      string
+		position int
+	}
+
+	var positioned []placed
+	var appended []string
+	for _, track := range tracks {
+		if len(track.Positions) == 0 {
+			appended = append(appended, track.URI)
+			continue
+		}
+		for _, position := range track.Positions {
+			positioned = append(positioned, placed{uri: track.URI, position: position})
+		}
+	}
+
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return positioned[i].position < positioned[j].position
+	})
+
+	var requests []Request
+	for _, item := range positioned {
+		requests = append(requests, newRequest(http.MethodPost, endpoint, map[string]interface{}{
+			"uris":     []string{item.uri},
+			"position": item.position,
+		}))
+	}
+	if len(appended) > 0 {
+		requests = append(requests, newRequest(http.MethodPost, endpoint, map[string]interface{}{"uris": appended}))
+	}
+	return requests
+}
+
+func newRequest(method, endpoint string, body interface{}) Request {
+	data, _ := json.Marshal(body)
+	return Request{Method: method, Endpoint: endpoint, Body: data}
+}
+
+// splitEndpoint separates an endpoint into its path and query parameters
+func splitEndpoint(endpoint string) (string, url.Values) {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		query = url.Values{}
+	}
+	return path, query
+}
+
+// requestIDs returns the IDs a request acted on, passed either as an ids
+// query parameter or in the JSON body as a list or comma-separated string
+func requestIDs(query url.Values, body []byte) []string {
+	if ids := query.Get("ids"); ids != "" {
+		return strings.Split(ids, ",")
+	}
+
+	var params struct {
+		IDs json.RawMessage `json:"ids"`
+	}
+	if json.Unmarshal(body, &params) != nil || len(params.IDs) == 0 {
+		return nil
+	}
+
+	var list []string
+	if json.Unmarshal(params.IDs, &list) == nil {
+		return list
+	}
+
+	var joined string
+	if json.Unmarshal(params.IDs, &joined) == nil && joined != "" {
+		return strings.Split(joined, ",")
+	}
+	return nil
+}
@@ -59,6 +59,11 @@ func NewClient(clientID, clientSecret, redirectURI string) *Client {
 	}
 }
 
+// SetHTTPClient replaces the HTTP client used for token requests
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
 // ClientCredentials performs the Client Credentials flow
 // This is used for accessing public data that doesn't require user authorization
 func (c *Client) ClientCredentials() (*Token, error) {
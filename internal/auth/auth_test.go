@@ -71,4 +71,23 @@ func TestBasicAuth(t *testing.T) {
 	if auth != expected {
 		t.Errorf("Expected basic auth %s, got %s", expected, auth)
 	}
-}
\ No newline at end of file
+}
+func TestMemoryTokenStore(t *testing.T) {
+	store := NewMemoryTokenStore(nil)
+
+	token, err := store.Load()
+	if err != nil || token != nil {
+		t.Fatalf("Expected an empty store, got %+v, %v", token, err)
+	}
+
+	original := &Token{AccessToken: "a", RefreshToken: "r"}
+	if err := store.Save(original); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	original.AccessToken = "changed"
+
+	token, _ = store.Load()
+	if token.AccessToken != "a" || token.RefreshToken != "r" {
+		t.Errorf("Expected the saved copy to be unaffected, got %+v", token)
+	}
+}
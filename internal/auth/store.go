@@ -0,0 +1,49 @@
+package auth
+
+import "sync"
+
+// TokenStore loads and saves tokens, so a client can pick up an earlier
+// session and keep refreshed tokens. The CLI keeps them in its config file;
+// a browser build can keep them in localStorage.
+type TokenStore interface {
+	// Load returns the stored token, or nil if there is none
+	Load() (*Token, error)
+	Save(token *Token) error
+}
+
+// MemoryTokenStore keeps a token in memory for the life of the process
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewMemoryTokenStore creates a token store holding token, which may be nil
+func NewMemoryTokenStore(token *Token) *MemoryTokenStore {
+	return &MemoryTokenStore{token: token}
+}
+
+// Load returns a copy of the stored token
+func (s *MemoryTokenStore) Load() (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return nil, nil
+	}
+	token := *s.token
+	return &token, nil
+}
+
+// Save replaces the stored token
+func (s *MemoryTokenStore) Save(token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token == nil {
+		s.token = nil
+		return nil
+	}
+	copied := *token
+	s.token = &copied
+	return nil
+}
@@ -0,0 +1,186 @@
+// Package beets reads the library of beets, the music organizer, to tell
+// which Spotify tracks are already in a local collection.
+package beets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+// ErrNoSQLite is returned by Load for a database when the sqlite3 command
+// is not installed
+var ErrNoSQLite = errors.New("reading a beets database needs the sqlite3 command; install it, or export the library with 'beet export -i title,artist,albumartist,album,length,path > library.json' and pass that file")
+
+// sqliteHeader starts every SQLite database file
+const sqliteHeader = "SQLite format 3\x00"
+
+// itemsQuery selects the fields Item has. Paths are stored as blobs.
+const itemsQuery = "SELECT title, artist, albumartist, album, length, CAST(path AS TEXT) AS path FROM items"
+
+// Item is a track in the library
+type Item struct {
+	Title       string  `json:"title"`
+	Artist      string  `json:"artist"`
+	AlbumArtist string  `json:"albumartist"`
+	Album       string  `json:"album"`
+	Length      Seconds `json:"length"`
+	Path        string  `json:"path"`
+}
+
+// Seconds is a length in seconds. The database holds a number, while 'beet
+// export' writes it formatted, as "4:24".
+type Seconds float64
+
+// UnmarshalJSON reads a number of seconds or a "m:ss" or "h:mm:ss" string
+func (s *Seconds) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		var n float64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid length %s", data)
+		}
+		*s = Seconds(n)
+		return nil
+	}
+
+	var total float64
+	for _, part := range strings.Split(strings.TrimSpace(text), ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			// Lengths beets could not read are left unknown
+			*s = 0
+			return nil
+		}
+		total = total*60 + n
+	}
+	*s = Seconds(total)
+	return nil
+}
+
+// Duration returns the length as a time.Duration
+func (s Seconds) Duration() time.Duration {
+	return time.Duration(float64(s) * float64(time.Second))
+}
+
+// Load reads the items of a library from its database or from the JSON
+// written by 'beet export', as an array or one item per line
+func Load(ctx context.Context, path string) ([]Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header, err := r.Peek(len(sqliteHeader))
+	if err == nil && string(header) == sqliteHeader {
+		return readDatabase(ctx, path)
+	}
+	return readJSON(r)
+}
+
+// readDatabase queries a library database with the sqlite3 command, read
+// only, so a running beets import is not disturbed
+func readDatabase(ctx context.Context, path string) ([]Item, error) {
+	sqlite, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, ErrNoSQLite
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, sqlite, "-readonly", "-json", path, itemsQuery)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to read beets database: %s", msg)
+		}
+		return nil, fmt.Errorf("failed to read beets database: %w", err)
+	}
+	// An empty table gives no output at all
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	return readJSON(bytes.NewReader(output))
+}
+
+// readJSON reads an array of items or, as 'beet export -f jsonlines'
+// writes, a stream of them
+func readJSON(r io.Reader) ([]Item, error) {
+	decoder := json.NewDecoder(r)
+	var items []Item
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read beets export: %w", err)
+		}
+
+		if bytes.HasPrefix(value, []byte("[")) {
+			var batch []Item
+			if err := json.Unmarshal(value, &batch); err != nil {
+				return nil, fmt.Errorf("failed to read beets export: %w", err)
+			}
+			items = append(items, batch...)
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(value, &item); err != nil {
+			return nil, fmt.Errorf("failed to read beets export: %w", err)
+		}
+		items = append(items, item)
+	}
+}
+
+// Library indexes items by title for matching
+type Library struct {
+	items   []Item
+	byTitle map[string][]int
+}
+
+// NewLibrary indexes items
+func NewLibrary(items []Item) *Library {
+	l := &Library{items: items, byTitle: make(map[string][]int)}
+	for i, item := range items {
+		key := trackmatch.Normalize(item.Title)
+		l.byTitle[key] = append(l.byTitle[key], i)
+	}
+	return l
+}
+
+// Len returns the number of items in the library
+func (l *Library) Len() int {
+	return len(l.items)
+}
+
+// Find returns the item that is track, as trackmatch.Best matches them, or
+// nil if the library does not have it
+func (l *Library) Find(track trackmatch.Track) *Item {
+	indexes := l.byTitle[trackmatch.Normalize(track.Title)]
+	songs := make([]trackmatch.Song, len(indexes))
+	for i, index := range indexes {
+		item := l.items[index]
+		artist := item.Artist
+		if artist == "" {
+			artist = item.AlbumArtist
+		}
+		songs[i] = trackmatch.Song{Title: item.Title, Artist: artist, Album: item.Album, Duration: item.Length.Duration()}
+	}
+	if i := trackmatch.Best(track, songs); i >= 0 {
+		return &l.items[indexes[i]]
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package beets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+func TestReadJSON(t *testing.T) {
+	// 'beet export' writes lengths formatted
+	exported := `[
+		{"title": "Karma Police", "artist": "Radiohead", "albumartist": "Radiohead", "album": "OK Computer", "length": "4:24", "path": "/music/06.flac"},
+		{"title": "Hurt", "artist": "Johnny Cash", "album": "American IV", "length": "1:03:05"}
+	]`
+	items, err := readJSON(strings.NewReader(exported))
+	if err != nil {
+		t.Fatalf("readJSON() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Title != "Karma Police" || items[0].Path != "/music/06.flac" {
+		t.Fatalf("readJSON() = %+v", items)
+	}
+	if items[0].Length.Duration() != 264*time.Second || items[1].Length.Duration() != 3785*time.Second {
+		t.Errorf("Unexpected lengths %v and %v", items[0].Length.Duration(), items[1].Length.Duration())
+	}
+
+	// sqlite3 -json and 'beet export -f jsonlines' write numbers and lines
+	lines := "{\"title\": \"Airbag\", \"length\": 284.5}\n{\"title\": \"Lucky\", \"length\": \"\"}\n"
+	items, err = readJSON(strings.NewReader(lines))
+	if err != nil {
+		t.Fatalf("readJSON() error = %v", err)
+	}
+	if len(items) != 2 || items[0].Length != 284.5 || items[1].Length != 0 {
+		t.Errorf("readJSON() = %+v", items)
+	}
+
+	if _, err := readJSON(strings.NewReader(`[{"title": 1}]`)); err == nil {
+		t.Error("Expected an invalid export to be an error")
+	}
+}
+
+func TestLoadDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.db")
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		if err := os.WriteFile(path, []byte(sqliteHeader+"rest of the file"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(context.Background(), path); !errors.Is(err, ErrNoSQLite) {
+			t.Errorf("Expected ErrNoSQLite without sqlite3, got %v", err)
+		}
+		return
+	}
+
+	schema := "CREATE TABLE items (title TEXT, artist TEXT, albumartist TEXT, album TEXT, length REAL, path BLOB);" +
+		"INSERT INTO items VALUES ('Karma Police', 'Radiohead', 'Radiohead', 'OK Computer', 264.1, CAST('/music/06.flac' AS BLOB));"
+	if output, err := exec.Command("sqlite3", path, schema).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create database: %v: %s", err, output)
+	}
+	items, err := Load(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Path != "/music/06.flac" || items[0].Length != 264.1 {
+		t.Errorf("Load() = %+v", items)
+	}
+}
+
+func TestLibraryFind(t *testing.T) {
+	library := NewLibrary([]Item{
+		{Title: "Karma Police", Artist: "Radiohead", Album: "OK Computer", Length: 264},
+		{Title: "Hurt", Artist: "Nine Inch Nails", Album: "The Downward Spiral"},
+		{Title: "Hurt", AlbumArtist: "Johnny Cash", Album: "American IV"},
+	})
+	if library.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", library.Len())
+	}
+
+	if item := library.Find(trackmatch.Track{Title: "Karma Police - Remastered", Artists: []string{"Radiohead"}}); item == nil || item.Album != "OK Computer" {
+		t.Errorf("Find() = %+v, want Karma Police", item)
+	}
+	if item := library.Find(trackmatch.Track{Title: "Hurt", Artists: []string{"Johnny Cash"}}); item == nil || item.Album != "American IV" {
+		t.Errorf("Find() = %+v, want the item matched by its album artist", item)
+	}
+	if item := library.Find(trackmatch.Track{Title: "Airbag", Artists: []string{"Radiohead"}}); item != nil {
+		t.Errorf("Find() = %+v, want nil for a missing track", item)
+	}
+}
@@ -0,0 +1,171 @@
+// Package checkpoint records the progress of long batch jobs, such as
+// importing hundreds of artists into Lidarr. A job that dies partway leaves
+// its checkpoint behind under a short token, and running the same command
+// with that token picks up the items that had not finished.
+package checkpoint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding checkpoints
+const StoreName = "checkpoints"
+
+// MaxAge is how long an unfinished checkpoint is kept
+const MaxAge = 30 * 24 * time.Hour
+
+// ErrNotFound is returned for a token with no checkpoint
+var ErrNotFound = errors.New("no such resume token")
+
+// Checkpoint is one batch job and the items it has finished
+type Checkpoint struct {
+	Token   string `json:"token"`
+	Command string `json:"command"`
+	// Items are all the items of the job, in the order they were given
+	Items     []string        `json:"items"`
+	Done      map[string]bool `json:"done,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Remaining returns the items that have not finished, in their original
+// order
+func (c *Checkpoint) Remaining() []string {
+	var remaining []string
+	for _, item := range c.Items {
+		if !c.Done[item] {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
+
+type store struct {
+	Checkpoints map[string]*Checkpoint `json:"checkpoints"`
+}
+
+func (s *store) init() {
+	if s.Checkpoints == nil {
+		s.Checkpoints = make(map[string]*Checkpoint)
+	}
+}
+
+// prune drops checkpoints not touched for MaxAge
+func (s *store) prune(now time.Time) {
+	for token, c := range s.Checkpoints {
+		if now.Sub(c.UpdatedAt) > MaxAge {
+			delete(s.Checkpoints, token)
+		}
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate resume token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Create starts a checkpoint for command over items
+func Create(dir *state.Dir, command string, items []string, now time.Time) (*Checkpoint, error) {
+	var data store
+	var created *Checkpoint
+	err := dir.Update(StoreName, &data, func() error {
+		data.init()
+		data.prune(now)
+
+		token, err := newToken()
+		if err != nil {
+			return err
+		}
+		for data.Checkpoints[token] != nil {
+			if token, err = newToken(); err != nil {
+				return err
+			}
+		}
+
+		created = &Checkpoint{
+			Token:     token,
+			Command:   command,
+			Items:     append([]string(nil), items...),
+			Done:      make(map[string]bool),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		data.Checkpoints[token] = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Load returns the checkpoint for token
+func Load(dir *state.Dir, token string) (*Checkpoint, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	c := data.Checkpoints[token]
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, token)
+	}
+	if c.Done == nil {
+		c.Done = make(map[string]bool)
+	}
+	return c, nil
+}
+
+// Unfinished returns the checkpoints left by command, newest first
+func Unfinished(dir *state.Dir, command string) ([]Checkpoint, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+
+	var found []Checkpoint
+	for _, c := range data.Checkpoints {
+		if c.Command == command {
+			found = append(found, *c)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].UpdatedAt.After(found[j].UpdatedAt) })
+	return found, nil
+}
+
+// MarkDone records items of the checkpoint for token as finished
+func MarkDone(dir *state.Dir, token string, now time.Time, items ...string) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		c := data.Checkpoints[token]
+		if c == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, token)
+		}
+		if c.Done == nil {
+			c.Done = make(map[string]bool)
+		}
+		for _, item := range items {
+			c.Done[item] = true
+		}
+		c.UpdatedAt = now
+		return nil
+	})
+}
+
+// Remove deletes the checkpoint for token, once its job has finished
+func Remove(dir *state.Dir, token string) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		delete(data.Checkpoints, token)
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package checkpoint
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestCheckpointLifecycle(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	c, err := Create(dir, "spotify-cli lidarr add-artists", []string{"A", "B", "C"}, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if c.Token == "" {
+		t.Fatal("Expected a token")
+	}
+
+	if err := MarkDone(dir, c.Token, now.Add(time.Minute), "B"); err != nil {
+		t.Fatalf("MarkDone() error = %v", err)
+	}
+	loaded, err := Load(dir, c.Token)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Remaining(); !reflect.DeepEqual(got, []string{"A", "C"}) {
+		t.Errorf("Remaining() = %v, want [A C]", got)
+	}
+
+	unfinished, err := Unfinished(dir, "spotify-cli lidarr add-artists")
+	if err != nil || len(unfinished) != 1 || unfinished[0].Token != c.Token {
+		t.Errorf("Unfinished() = %+v, %v", unfinished, err)
+	}
+
+	if err := Remove(dir, c.Token); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := Load(dir, c.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load() after Remove error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCreatePrunesOld(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	old, err := Create(dir, "cmd", []string{"A"}, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := Create(dir, "cmd", []string{"B"}, now.Add(MaxAge+time.Hour)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := Load(dir, old.Token); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a checkpoint older than MaxAge to be pruned, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/apikey"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apikeyRole    string
+	apikeyAllow   []string
+	apikeyExpires time.Duration
+)
+
+// apikeyCmd represents the apikey command
+var apikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage restricted API keys for the control server",
+	Long: `Mint and revoke API keys for 'spotify-cli serve'.
+
+Each key only permits the operations it was created with, either from a role
+preset or an explicit --allow list. Give home-automation integrations a
+"player" key so a leaked key can control playback but never edit playlists.
+
+Keys are shown once when created; only a hash is stored.`,
+	Example: `  # Playback control only (play, pause, next, volume, ...)
+  spotify-cli apikey create home-assistant --role player
+
+  # Just pause and skip, expiring in 30 days
+  spotify-cli apikey create stream-deck --allow pause,next --expires 720h
+
+  spotify-cli apikey list
+  spotify-cli apikey revoke stream-deck`,
+}
+
+var apikeyCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAPIKeyCreate(args[0])
+	},
+}
+
+var apikeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAPIKeyList()
+	},
+}
+
+var apikeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id|name>",
+	Short: "Revoke an API key",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAPIKeyRevoke(args[0])
+	},
+}
+
+var apikeyRolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "List roles and the operations they allow",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAPIKeyRoles()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apikeyCmd)
+	apikeyCmd.AddCommand(apikeyCreateCmd)
+	apikeyCmd.AddCommand(apikeyListCmd)
+	apikeyCmd.AddCommand(apikeyRevokeCmd)
+	apikeyCmd.AddCommand(apikeyRolesCmd)
+
+	apikeyCreateCmd.Flags().StringVar(&apikeyRole, "role", "", "Role preset (status, player, dj, full)")
+	apikeyCreateCmd.Flags().StringSliceVar(&apikeyAllow, "allow", nil, "Operations to allow in addition to the role")
+	apikeyCreateCmd.Flags().DurationVar(&apikeyExpires, "expires", 0, "Expire the key after this long (e.g. 720h); default never")
+}
+
+// openAPIKeys opens the API key store of the current profile
+func openAPIKeys() (*apikey.Store, error) {
+	dir, err := openState()
+	if err != nil {
+		return nil, err
+	}
+	return apikey.NewStore(dir), nil
+}
+
+func runAPIKeyCreate(name string) error {
+	ops, err := apikey.ParseOperations(apikeyRole, apikeyAllow)
+	if err != nil {
+		return err
+	}
+
+	store, err := openAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	token, key, err := store.Create(name, apikeyRole, ops, apikeyExpires)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"key":   token,
+			"id":    key.ID,
+			"name":  key.Name,
+			"allow": key.Operations,
+		})
+	}
+
+	utils.PrintSuccess("Created API key %q (%s)", key.Name, key.ID)
+	fmt.Printf("\n  %s\n\n", token)
+	fmt.Printf("Allows: %s\n", joinOperations(key.Operations))
+	if key.ExpiresAt != nil {
+		fmt.Printf("Expires: %s\n", key.ExpiresAt.Local().Format("2006-01-02 15:04"))
+	}
+	utils.PrintWarning("Store this key now; it cannot be shown again")
+	return nil
+}
+
+func runAPIKeyList() error {
+	store, err := openAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	keys, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		public := make([]*apikey.Key, len(keys))
+		for i, key := range keys {
+			public[i] = key.Public()
+		}
+		return utils.Output(public)
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No API keys. Create one with 'spotify-cli apikey create <name> --role player'.")
+		return nil
+	}
+
+	fmt.Printf("%-10s %-20s %-16s %-16s %s\n", "ID", "NAME", "CREATED", "LAST USED", "ALLOWS")
+	fmt.Println(strings.Repeat("-", 100))
+	now := time.Now()
+	for _, key := range keys {
+		lastUsed := "never"
+		if key.LastUsedAt != nil {
+			lastUsed = key.LastUsedAt.Local().Format("2006-01-02 15:04")
+		}
+
+		allows := joinOperations(key.Operations)
+		if key.Role != "" {
+			allows = key.Role + ": " + allows
+		}
+		if key.Expired(now) {
+			allows = "[expired] " + allows
+		}
+
+		fmt.Printf("%-10s %-20s %-16s %-16s %s\n",
+			key.ID,
+			truncateString(key.Name, 20),
+			key.CreatedAt.Local().Format("2006-01-02 15:04"),
+			lastUsed,
+			allows)
+	}
+
+	return nil
+}
+
+func runAPIKeyRevoke(idOrName string) error {
+	store, err := openAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	key, err := store.Revoke(idOrName)
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Revoked API key %q (%s)", key.Name, key.ID)
+	return nil
+}
+
+func runAPIKeyRoles() error {
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(apikey.Roles)
+	}
+
+	for _, name := range apikey.RoleNames() {
+		fmt.Printf("%-8s %s\n", name, joinOperations(apikey.Roles[name]))
+	}
+	return nil
+}
+
+func joinOperations(ops []apikey.Operation) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,352 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/audit"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	spotifyclient "github.com/bambithedeer/spotify-api/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditLimit  int
+	auditTarget string
+	auditAction string
+	auditSince  string
+	auditDryRun bool
+
+	// auditUndoing is the entry being reversed by 'audit undo', so the
+	// requests it makes are linked back to it
+	auditUndoing int
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Review and undo changes made to your Spotify account",
+	Long: `Every command that changes your Spotify data (saving to the library, following,
+editing playlists) is recorded in an append-only audit log with the endpoint,
+target, items, resulting snapshot ID and the command that made the change.
+Playback control is not recorded.
+
+Most changes can be reversed with 'audit undo'. Replacing a playlist's items
+and editing its details cannot, because the previous contents are not known.`,
+	Example: `  # Show recent changes
+  spotify-cli audit list
+
+  # Show everything done to one playlist in the last day
+  spotify-cli audit list --target 37i9dQZF1DXcBWIGoYBM5M --since 24h
+
+  # Show one entry in full and undo it
+  spotify-cli audit show 42
+  spotify-cli audit undo 42`,
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded changes, newest first",
+	Example: `  spotify-cli audit list
+  spotify-cli audit list --action playlist --limit 50
+  spotify-cli audit list --since 168h`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuditList()
+	},
+}
+
+var auditShowCmd = &cobra.Command{
+	Use:     "show <id>",
+	Short:   "Show one recorded change",
+	Example: `  spotify-cli audit show 42`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuditShow(args[0])
+	},
+}
+
+var auditUndoCmd = &cobra.Command{
+	Use:   "undo <id>",
+	Short: "Reverse a recorded change",
+	Long: `Send the requests that reverse a recorded change. Items removed from a
+playlist are put back at their original positions when the removal named them.
+The undo is itself recorded, and an entry can only be undone once.`,
+	Example: `  spotify-cli audit undo 42
+  spotify-cli audit undo 42 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuditUndo(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditListCmd)
+	auditCmd.AddCommand(auditShowCmd)
+	auditCmd.AddCommand(auditUndoCmd)
+
+	auditListCmd.Flags().IntVarP(&auditLimit, "limit", "l", 20, "Maximum number of entries to show (0 for all)")
+	auditListCmd.Flags().StringVar(&auditTarget, "target", "", "Only show changes to this playlist ID or library section")
+	auditListCmd.Flags().StringVar(&auditAction, "action", "", "Only show actions starting with this (e.g. playlist, library.save)")
+	auditListCmd.Flags().StringVar(&auditSince, "since", "", "Only show changes made within this duration (e.g. 24h)")
+
+	auditUndoCmd.Flags().BoolVar(&auditDryRun, "dry-run", false, "Show the requests without sending them")
+}
+
+// startAuditLog records the write requests made by the running command
+func startAuditLog(command string) {
+	client.SetWriteObserver(func(event spotifyclient.WriteEvent) {
+		entry := audit.NewEntry(command, event.Method, event.Endpoint, event.RequestBody, event.StatusCode, event.ResponseBody, event.Err)
+		if entry == nil {
+			return
+		}
+		entry.Undoes = auditUndoing
+
+		dir, err := openState()
+		if err == nil {
+			err = audit.Open(dir).Append(entry)
+		}
+		if err != nil {
+			// The change has already been made; losing its record must not fail the command
+			utils.PrintWarning("Failed to record change in audit log: %v", err)
+		}
+	})
+}
+
+// openAuditLog opens the audit log of the current profile
+func openAuditLog() (*audit.Log, error) {
+	dir, err := openState()
+	if err != nil {
+		return nil, err
+	}
+	return audit.Open(dir), nil
+}
+
+func runAuditList() error {
+	log, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	filter := audit.Filter{Target: auditTarget, Action: auditAction, Limit: auditLimit}
+	if auditSince != "" {
+		since, err := utils.ParseDuration(auditSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since %q: %w", auditSince, err)
+		}
+		filter.Since = time.Now().Add(-since)
+	}
+
+	entries, err := log.List(filter)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No changes recorded")
+		return nil
+	}
+
+	all, err := log.Entries()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-6s %-17s %-18s %-24s %-6s %s\n", "ID", "TIME", "ACTION", "TARGET", "ITEMS", "STATUS")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, entry := range entries {
+		fmt.Printf("%-6d %-17s %-18s %-24s %-6d %s\n",
+			entry.ID,
+			entry.Time.Local().Format("2006-01-02 15:04"),
+			truncateString(entry.Action, 18),
+			truncateString(entry.Target, 24),
+			entry.ItemCount,
+			auditStatus(entry, all))
+	}
+
+	return nil
+}
+
+// auditStatus summarises whether an entry succeeded and can still be undone
+func auditStatus(entry audit.Entry, all []audit.Entry) string {
+	switch {
+	case !entry.Succeeded():
+		return "failed"
+	case entry.Undoes != 0:
+		return fmt.Sprintf("undo of #%d", entry.Undoes)
+	}
+
+	if by := audit.UndoneBy(all, entry.ID); by != 0 {
+		return fmt.Sprintf("undone by #%d", by)
+	}
+	if entry.Undoable() {
+		return "ok"
+	}
+	return "ok (no undo)"
+}
+
+func parseAuditID(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid audit entry ID %q", arg)
+	}
+	return id, nil
+}
+
+func runAuditShow(arg string) error {
+	id, err := parseAuditID(arg)
+	if err != nil {
+		return err
+	}
+
+	log, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	entry, err := log.Get(id)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(entry)
+	}
+
+	all, err := log.Entries()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Entry:     #%d\n", entry.ID)
+	fmt.Printf("Time:      %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"))
+	if entry.Command != "" {
+		fmt.Printf("Command:   %s\n", entry.Command)
+	}
+	fmt.Printf("Action:    %s\n", entry.Action)
+	fmt.Printf("Request:   %s %s\n", entry.Method, entry.Endpoint)
+	if entry.Target != "" {
+		fmt.Printf("Target:    %s\n", entry.Target)
+	}
+	if entry.SnapshotID != "" {
+		fmt.Printf("Snapshot:  %s\n", entry.SnapshotID)
+	}
+	fmt.Printf("Status:    %d, %s\n", entry.Status, auditStatus(*entry, all))
+	if entry.Error != "" {
+		fmt.Printf("Error:     %s\n", entry.Error)
+	}
+
+	if len(entry.Items) > 0 {
+		fmt.Printf("\nItems (%d):\n", entry.ItemCount)
+		for _, item := range entry.Items {
+			fmt.Printf("  %s\n", item)
+		}
+	}
+
+	if len(entry.Undo) > 0 {
+		fmt.Printf("\nUndo:\n")
+		printAuditRequests(entry.Undo)
+	}
+
+	return nil
+}
+
+func printAuditRequests(requests []audit.Request) {
+	for _, request := range requests {
+		if len(request.Body) > 0 {
+			fmt.Printf("  %s %s %s\n", request.Method, request.Endpoint, request.Body)
+		} else {
+			fmt.Printf("  %s %s\n", request.Method, request.Endpoint)
+		}
+	}
+}
+
+func runAuditUndo(arg string) error {
+	id, err := parseAuditID(arg)
+	if err != nil {
+		return err
+	}
+
+	log, err := openAuditLog()
+	if err != nil {
+		return err
+	}
+
+	entry, err := log.Get(id)
+	if err != nil {
+		return err
+	}
+
+	all, err := log.Entries()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !entry.Succeeded():
+		return fmt.Errorf("entry #%d failed, so there is nothing to undo", id)
+	case !entry.Undoable():
+		return fmt.Errorf("entry #%d (%s) cannot be undone", id, entry.Action)
+	}
+	if by := audit.UndoneBy(all, id); by != 0 {
+		return fmt.Errorf("entry #%d was already undone by #%d", id, by)
+	}
+
+	if auditDryRun {
+		fmt.Printf("Would send %d request%s to undo #%d:\n", len(entry.Undo), pluralize(len(entry.Undo)), id)
+		printAuditRequests(entry.Undo)
+		return nil
+	}
+
+	spotifyClient, err := newUserClient("your account")
+	if err != nil {
+		return err
+	}
+
+	auditUndoing = id
+	defer func() { auditUndoing = 0 }()
+
+	rb := api.NewRequestBuilder(spotifyClient.GetClient())
+	ctx := GetCommandContext()
+	for i, request := range entry.Undo {
+		var body interface{}
+		if len(request.Body) > 0 {
+			body = request.Body
+		}
+
+		switch request.Method {
+		case "POST":
+			err = rb.Post(ctx, request.Endpoint, body, nil)
+		case "PUT":
+			err = rb.Put(ctx, request.Endpoint, body, nil)
+		case "DELETE":
+			if body == nil {
+				err = rb.Delete(ctx, request.Endpoint, nil)
+			} else {
+				err = rb.DeleteWithBody(ctx, request.Endpoint, body, nil)
+			}
+		default:
+			err = fmt.Errorf("unsupported method %s", request.Method)
+		}
+		if err != nil {
+			if i > 0 {
+				return fmt.Errorf("undo of #%d stopped after %d of %d requests: %w", id, i, len(entry.Undo), err)
+			}
+			return fmt.Errorf("failed to undo #%d: %w", id, err)
+		}
+	}
+
+	utils.PrintSuccess("Undid #%d (%s)", id, entry.Action)
+	return nil
+}
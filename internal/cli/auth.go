@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -21,6 +22,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var loginNoBrowser bool
+
 // authCmd represents the auth command
 var authCmd = &cobra.Command{
 	Use:   "auth",
@@ -69,8 +72,13 @@ This command will:
 3. Exchange the code for access and refresh tokens
 4. Save the tokens for future use
 
+On a machine without a browser, such as a Raspberry Pi over SSH, use
+--no-browser: open the printed URL on any other device, then paste back the
+address the browser is redirected to.
+
 Requires API credentials to be set up first with 'auth setup'.`,
-	Example: `  spotify-cli auth login`,
+	Example: `  spotify-cli auth login
+  spotify-cli auth login --no-browser`,
 	RunE:    runLogin,
 }
 
@@ -130,6 +138,8 @@ func init() {
 	authCmd.AddCommand(clientCredentialsCmd)
 	authCmd.AddCommand(statusCmd)
 	authCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().BoolVar(&loginNoBrowser, "no-browser", false, "Log in from another device by pasting back the redirected address")
 }
 
 func runSetup(cmd *cobra.Command, args []string) error {
@@ -197,11 +207,36 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loginScopes are requested by 'auth login' for full user access
+var loginScopes = []string{
+	"user-read-private",
+	"user-read-email",
+	"user-library-read",
+	"user-library-modify",
+	"user-read-playback-state",
+	"user-modify-playback-state",
+	"user-read-currently-playing",
+	"user-read-playback-position",
+	"playlist-read-private",
+	"playlist-read-collaborative",
+	"playlist-modify-public",
+	"playlist-modify-private",
+	"user-follow-read",
+	"user-follow-modify",
+	"user-read-recently-played",
+	"user-top-read",
+	"ugc-image-upload",
+}
+
 func runLogin(cmd *cobra.Command, args []string) error {
 	if !config.HasCredentials() {
 		return fmt.Errorf("credentials not configured. Run 'spotify-cli auth setup' first")
 	}
 
+	if loginNoBrowser {
+		return runHeadlessLogin(os.Stdin)
+	}
+
 	cfg := config.Get()
 	authClient := auth.NewClient(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI)
 
@@ -218,27 +253,8 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	// Define scopes for full user access
-	scopes := []string{
-		"user-read-private",
-		"user-read-email",
-		"user-library-read",
-		"user-library-modify",
-		"user-read-playback-state",
-		"user-modify-playback-state",
-		"user-read-currently-playing",
-		"playlist-read-private",
-		"playlist-read-collaborative",
-		"playlist-modify-public",
-		"playlist-modify-private",
-		"user-follow-read",
-		"user-follow-modify",
-		"user-read-recently-played",
-		"user-top-read",
-	}
-
 	// Get authorization URL
-	authURL := authClient.GetAuthorizationURL(scopes, state)
+	authURL := authClient.GetAuthorizationURL(loginScopes, state)
 
 	fmt.Println("Opening browser for Spotify authorization...")
 	fmt.Println()
@@ -321,6 +337,11 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	// Shutdown server
 	server.Shutdown(context.Background())
 
+	return exchangeLoginCode(authClient, code)
+}
+
+// exchangeLoginCode exchanges an authorization code for tokens and saves them
+func exchangeLoginCode(authClient *auth.Client, code string) error {
 	fmt.Println("Authorization code received, exchanging for tokens...")
 
 	// Exchange code for tokens
@@ -353,6 +374,60 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runHeadlessLogin runs the authorization code flow without a browser or
+// callback server on this machine: the URL is opened on any other device and
+// the address the browser ends up on is pasted back, code and all.
+func runHeadlessLogin(in io.Reader) error {
+	cfg := config.Get()
+	authClient := auth.NewClient(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI)
+
+	state, err := generateRandomString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate state: %w", err)
+	}
+
+	fmt.Println("Open this URL in a browser on any device and approve access:")
+	fmt.Println(authClient.GetAuthorizationURL(loginScopes, state))
+	fmt.Println()
+	fmt.Printf("Your browser will then try to open %s, which may fail to load.\n", cfg.RedirectURI)
+	fmt.Print("Paste the full address from the browser's address bar here: ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("failed to read the redirected address: %w", err)
+	}
+
+	code, err := authCodeFromRedirect(line, state)
+	if err != nil {
+		return err
+	}
+
+	return exchangeLoginCode(authClient, code)
+}
+
+// authCodeFromRedirect extracts the authorization code from the address the
+// browser was redirected to, checking that it answers our state
+func authCodeFromRedirect(redirected, state string) (string, error) {
+	redirectURL, err := url.Parse(strings.TrimSpace(redirected))
+	if err != nil {
+		return "", fmt.Errorf("invalid redirected address: %w", err)
+	}
+
+	query := redirectURL.Query()
+	if errorParam := query.Get("error"); errorParam != "" {
+		return "", fmt.Errorf("authorization error: %s", errorParam)
+	}
+	if query.Get("state") != state {
+		return "", fmt.Errorf("invalid state parameter. Paste the address from this login attempt")
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("no authorization code in the redirected address")
+	}
+	return code, nil
+}
+
 func runClientCredentials(cmd *cobra.Command, args []string) error {
 	if !config.HasCredentials() {
 		return fmt.Errorf("credentials not configured. Run 'spotify-cli auth setup' first")
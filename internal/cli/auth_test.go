@@ -95,6 +95,32 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+func TestAuthCodeFromRedirect(t *testing.T) {
+	tests := []struct {
+		redirected string
+		code       string
+		wantErr    bool
+	}{
+		{"http://127.0.0.1:8080/callback?code=abc123&state=s1\n", "abc123", false},
+		{"  http://127.0.0.1:8080/callback?state=s1&code=xyz  ", "xyz", false},
+		{"http://127.0.0.1:8080/callback?code=abc123&state=other", "", true},
+		{"http://127.0.0.1:8080/callback?error=access_denied&state=s1", "", true},
+		{"http://127.0.0.1:8080/callback?state=s1", "", true},
+		{"not a url%", "", true},
+	}
+
+	for _, test := range tests {
+		code, err := authCodeFromRedirect(test.redirected, "s1")
+		if (err != nil) != test.wantErr {
+			t.Errorf("authCodeFromRedirect(%q) error = %v, wantErr %v", test.redirected, err, test.wantErr)
+			continue
+		}
+		if code != test.code {
+			t.Errorf("authCodeFromRedirect(%q) = %q, expected %q", test.redirected, code, test.code)
+		}
+	}
+}
+
 func TestAuthCommands_Integration(t *testing.T) {
 	// Create temporary config for testing
 	tmpDir := t.TempDir()
@@ -0,0 +1,409 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backupOut         string
+	backupIncremental bool
+	backupFrom        string
+	backupDryRun      bool
+	backupConcurrency int
+)
+
+// backupManifestFile lists the playlists in a backup directory
+const backupManifestFile = "manifest.json"
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore your playlists",
+	Long: `Snapshot every playlist in your library, with its details and full track
+list, to a directory on disk, and recreate the ones that have since gone
+missing from your account.
+
+A backup directory holds a manifest.json and one JSON file per playlist
+under playlists/, in the same format as 'playlist export'. For the local
+state directory (history, tags, ratings and the like) see 'state backup'.`,
+	Example: `  spotify-cli backup create --out ~/spotify-backup
+  spotify-cli backup create --out ~/spotify-backup --incremental
+  spotify-cli backup restore --from ~/spotify-backup --dry-run
+  spotify-cli backup restore --from ~/spotify-backup "Road Trip"`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot every playlist to a directory",
+	Long: `Write every playlist you own or follow to --out. With --incremental,
+playlists whose snapshot ID matches the one already in the directory are
+not fetched again, so a nightly backup only downloads what changed.
+Playlists no longer in your library are kept in the backup.
+
+Up to --concurrency playlists are read at once, and fewer while Spotify
+answers with rate limits or server errors.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackupCreate()
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore [name-or-id...]",
+	Short: "Recreate playlists missing from your library",
+	Long: `Compare a backup with your library and bring back the playlists that are
+gone. Your own playlists are recreated as private playlists with the same
+name, description and tracks; playlists by others are followed again if
+they still exist, and recreated as your own otherwise.
+
+A playlist counts as missing when neither its ID nor, for your own
+playlists, its name is in your library, so restoring twice does nothing
+the second time. Give names or IDs to restore only those. Local files
+cannot be added back and are skipped.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBackupRestore(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupCreateCmd.Flags().StringVarP(&backupOut, "out", "O", "", "Directory to write the backup to (required)")
+	backupCreateCmd.Flags().BoolVar(&backupIncremental, "incremental", false, "Skip playlists unchanged since the last backup in --out")
+	backupCreateCmd.Flags().IntVar(&backupConcurrency, "concurrency", 4, "Most playlists to read at once")
+	backupCreateCmd.MarkFlagRequired("out")
+
+	backupRestoreCmd.Flags().StringVar(&backupFrom, "from", "", "Backup directory to restore from (required)")
+	backupRestoreCmd.Flags().BoolVar(&backupDryRun, "dry-run", false, "Show what would be restored without changing anything")
+	backupRestoreCmd.MarkFlagRequired("from")
+}
+
+// backupManifest describes the contents of a backup directory
+type backupManifest struct {
+	CreatedAt time.Time             `json:"created_at"`
+	User      string                `json:"user"`
+	Playlists []backupManifestEntry `json:"playlists"`
+}
+
+// backupManifestEntry is one playlist in a backup
+type backupManifestEntry struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	OwnerID    string `json:"owner_id"`
+	SnapshotID string `json:"snapshot_id"`
+	File       string `json:"file"`
+	Tracks     int    `json:"tracks"`
+}
+
+// backupPlaylistFile is where a playlist lives, relative to the backup directory
+func backupPlaylistFile(id string) string {
+	return filepath.Join("playlists", id+".json")
+}
+
+// readBackupManifest loads the manifest of a backup directory. A directory
+// without one yields an empty manifest.
+func readBackupManifest(dir string) (*backupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, backupManifestFile))
+	if os.IsNotExist(err) {
+		return &backupManifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+
+	var manifest backupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest %s: %w", filepath.Join(dir, backupManifestFile), err)
+	}
+	return &manifest, nil
+}
+
+func writeBackupJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return state.WriteFileAtomic(path, append(data, '\n'), 0644)
+}
+
+func runBackupCreate() error {
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	user, err := spotifyClient.Users.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	playlists, err := spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playlists: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(backupOut, "playlists"), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	previous, err := readBackupManifest(backupOut)
+	if err != nil {
+		return err
+	}
+	previousByID := make(map[string]backupManifestEntry, len(previous.Playlists))
+	for _, entry := range previous.Playlists {
+		previousByID[entry.ID] = entry
+	}
+
+	// Entries keep library order; the ones to fetch are filled in below
+	manifest := backupManifest{CreatedAt: time.Now().UTC(), User: user.ID}
+	seen := make(map[string]bool, len(playlists))
+	var fetch []models.Playlist
+	var fetchAt []int
+	skipped := 0
+	for _, playlist := range playlists {
+		if seen[playlist.ID] {
+			continue
+		}
+		seen[playlist.ID] = true
+
+		if entry, ok := previousByID[playlist.ID]; ok && backupIncremental && entry.SnapshotID == playlist.SnapshotID {
+			if _, err := os.Stat(filepath.Join(backupOut, entry.File)); err == nil {
+				utils.PrintVerbose("Unchanged: %s", playlist.Name)
+				entry.Name = playlist.Name
+				manifest.Playlists = append(manifest.Playlists, entry)
+				skipped++
+				continue
+			}
+		}
+
+		fetch = append(fetch, playlist)
+		fetchAt = append(fetchAt, len(manifest.Playlists))
+		manifest.Playlists = append(manifest.Playlists, backupManifestEntry{})
+	}
+
+	limiter := ratelimit.NewAdaptiveLimiter(backupConcurrency)
+	err = limiter.ForEach(ctx, len(fetch), func(ctx context.Context, i int) error {
+		playlist := fetch[i]
+		export, err := fetchPlaylistExport(ctx, spotifyClient, playlist.ID)
+		if err != nil {
+			return fmt.Errorf("failed to back up %q: %w", playlist.Name, err)
+		}
+		file := backupPlaylistFile(playlist.ID)
+		if err := writeBackupJSON(filepath.Join(backupOut, file), export); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+
+		utils.PrintVerbose("Backed up %s (%d track%s)", export.Name, len(export.Tracks), pluralize(len(export.Tracks)))
+		manifest.Playlists[fetchAt[i]] = backupManifestEntry{
+			ID:         export.ID,
+			Name:       export.Name,
+			OwnerID:    export.OwnerID,
+			SnapshotID: export.SnapshotID,
+			File:       file,
+			Tracks:     len(export.Tracks),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if c := limiter.Stats(); c.Decreases > 0 {
+		utils.PrintVerbose("Concurrency reduced %d time%s (lowest %d of %d) after %d overloaded request%s",
+			c.Decreases, pluralize(c.Decreases), c.Lowest, c.Max, c.Overloads, pluralize(c.Overloads))
+	}
+	written := len(fetch)
+
+	// Playlists that have left the library stay in the backup, which is
+	// what makes them restorable
+	kept := 0
+	for _, entry := range previous.Playlists {
+		if !seen[entry.ID] {
+			manifest.Playlists = append(manifest.Playlists, entry)
+			kept++
+		}
+	}
+
+	if err := writeBackupJSON(filepath.Join(backupOut, backupManifestFile), manifest); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+
+	summary := fmt.Sprintf("Backed up %d playlist%s to %s", written, pluralize(written), backupOut)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d unchanged", skipped)
+	}
+	if kept > 0 {
+		summary += fmt.Sprintf(", %d no longer in your library", kept)
+	}
+	utils.PrintSuccess("%s", summary)
+	return nil
+}
+
+// backupMissing returns the entries of a backup that are gone from the
+// library. Own playlists also match by name, so one already restored under
+// a new ID is not restored again.
+func backupMissing(entries []backupManifestEntry, current []models.Playlist, userID string) []backupManifestEntry {
+	ids := make(map[string]bool, len(current))
+	ownedNames := make(map[string]bool)
+	for _, playlist := range current {
+		ids[playlist.ID] = true
+		if playlist.Owner.ID == userID {
+			ownedNames[strings.ToLower(playlist.Name)] = true
+		}
+	}
+
+	var missing []backupManifestEntry
+	for _, entry := range entries {
+		if ids[entry.ID] {
+			continue
+		}
+		if entry.OwnerID == userID && ownedNames[strings.ToLower(entry.Name)] {
+			continue
+		}
+		missing = append(missing, entry)
+	}
+	return missing
+}
+
+// backupSelect keeps the entries named by refs, matching IDs exactly and
+// names without regard to case
+func backupSelect(entries []backupManifestEntry, refs []string) ([]backupManifestEntry, error) {
+	if len(refs) == 0 {
+		return entries, nil
+	}
+
+	var selected []backupManifestEntry
+	for _, ref := range refs {
+		found := false
+		for _, entry := range entries {
+			if entry.ID == ref || strings.EqualFold(entry.Name, ref) {
+				selected = append(selected, entry)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%q is not in the backup", ref)
+		}
+	}
+	return selected, nil
+}
+
+func runBackupRestore(refs []string) error {
+	manifest, err := readBackupManifest(backupFrom)
+	if err != nil {
+		return err
+	}
+	if len(manifest.Playlists) == 0 {
+		return fmt.Errorf("no backup found in %s", backupFrom)
+	}
+
+	selected, err := backupSelect(manifest.Playlists, refs)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	user, err := spotifyClient.Users.GetCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %w", err)
+	}
+	current, err := spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playlists: %w", err)
+	}
+
+	missing := backupMissing(selected, current, user.ID)
+	if len(missing) == 0 {
+		fmt.Println("Nothing to restore: every playlist in the backup is in your library.")
+		return nil
+	}
+
+	restored := 0
+	for _, entry := range missing {
+		var export playlistExport
+		data, err := os.ReadFile(filepath.Join(backupFrom, entry.File))
+		if err == nil {
+			err = json.Unmarshal(data, &export)
+		}
+		if err != nil {
+			utils.PrintWarning("Skipping %q: %v", entry.Name, err)
+			continue
+		}
+
+		if err := restorePlaylist(spotifyClient, &export, user.ID); err != nil {
+			utils.PrintWarning("Failed to restore %q: %v", entry.Name, err)
+			continue
+		}
+		restored++
+	}
+
+	if backupDryRun {
+		fmt.Printf("Dry run: %d playlist%s would be restored\n", restored, pluralize(restored))
+		return nil
+	}
+	commandReport.Add("playlists restored", restored)
+	commandReport.Add("playlists failed", len(missing)-restored)
+	utils.PrintSuccess("Restored %d of %d missing playlist%s", restored, len(missing), pluralize(len(missing)))
+	return nil
+}
+
+// restorePlaylist brings one backed up playlist back into the library
+func restorePlaylist(spotifyClient *client.SpotifyClient, export *playlistExport, userID string) error {
+	ctx := GetCommandContext()
+
+	// Someone else's playlist is followed again if it still exists
+	if export.OwnerID != "" && export.OwnerID != userID {
+		if _, err := spotifyClient.Playlists.GetPlaylist(ctx, export.ID, &spotify.PlaylistOptions{Fields: "id"}); err == nil {
+			if backupDryRun {
+				fmt.Printf("Would follow %q by %s\n", export.Name, export.Owner)
+				return nil
+			}
+			if err := spotifyClient.Playlists.FollowPlaylist(ctx, export.ID, false); err != nil {
+				return err
+			}
+			utils.PrintSuccess("Followed %q by %s", export.Name, export.Owner)
+			return nil
+		}
+	}
+
+	var uris []string
+	for _, track := range export.Tracks {
+		if !track.IsLocal {
+			uris = append(uris, track.URI)
+		}
+	}
+
+	if backupDryRun {
+		fmt.Printf("Would recreate %q with %d track%s\n", export.Name, len(uris), pluralize(len(uris)))
+		return nil
+	}
+
+	playlist, err := createPlaylistWithTracks(spotifyClient, export.Name, export.Description, uris)
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Recreated %q with %d track%s (%s)", playlist.Name, len(uris), pluralize(len(uris)), playlist.ID)
+	return nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func TestBackupMissing(t *testing.T) {
+	entries := []backupManifestEntry{
+		{ID: "present", Name: "Still Here", OwnerID: "me"},
+		{ID: "deleted", Name: "Gone", OwnerID: "me"},
+		{ID: "renumbered", Name: "Restored Before", OwnerID: "me"},
+		{ID: "unfollowed", Name: "Someone Else's", OwnerID: "them"},
+	}
+	current := []models.Playlist{
+		{ID: "present", Name: "Still Here", Owner: models.User{ID: "me"}},
+		{ID: "new-id", Name: "restored before", Owner: models.User{ID: "me"}},
+	}
+
+	missing := backupMissing(entries, current, "me")
+	if len(missing) != 2 || missing[0].ID != "deleted" || missing[1].ID != "unfollowed" {
+		t.Errorf("Unexpected missing playlists %+v", missing)
+	}
+
+	selected, err := backupSelect(entries, []string{"gone", "unfollowed"})
+	if err != nil || len(selected) != 2 {
+		t.Errorf("Expected to select by name and ID, got %+v (%v)", selected, err)
+	}
+	if _, err := backupSelect(entries, []string{"nope"}); err == nil {
+		t.Error("Expected an unknown playlist to be rejected")
+	}
+}
+
+func TestBackupManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest, err := readBackupManifest(dir)
+	if err != nil || len(manifest.Playlists) != 0 {
+		t.Fatalf("Expected an empty manifest for a new directory, got %+v (%v)", manifest, err)
+	}
+
+	manifest.Playlists = []backupManifestEntry{{ID: "abc", SnapshotID: "s1", File: backupPlaylistFile("abc")}}
+	if err := writeBackupJSON(filepath.Join(dir, backupManifestFile), manifest); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := readBackupManifest(dir)
+	if err != nil || len(loaded.Playlists) != 1 || loaded.Playlists[0].SnapshotID != "s1" {
+		t.Errorf("Manifest did not round trip: %+v (%v)", loaded, err)
+	}
+}
@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/beets"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	beetsLibrary   string
+	beetsPlaylists []string
+	beetsSaved     bool
+	beetsAlbums    bool
+	beetsFormat    string
+)
+
+var beetsCmd = &cobra.Command{
+	Use:   "beets",
+	Short: "beets music library integration commands",
+	Long:  `Commands for comparing your Spotify library with a local collection managed by beets.`,
+}
+
+var beetsMatchCmd = &cobra.Command{
+	Use:   "match",
+	Short: "List Spotify tracks missing from your beets library",
+	Long: `Look the tracks you have saved on Spotify, or those of --playlist, up in a
+local beets library and list the ones it does not have, as a shopping list
+for Lidarr, Soulseek or a record shop.
+
+--library is the beets database, library.db, or a file written by
+'beet export'. Reading the database needs the sqlite3 command; it is opened
+read only. Without --library the database is looked for where beets keeps
+it, in $BEETSDIR or ~/.config/beets.
+
+Tracks are matched as 'navidrome push-playlist' matches them: by title and
+one of the artists, preferring the same album and length. --albums lists
+the albums the missing tracks are on instead, which is what Lidarr
+acquires. --format text writes one "Artist - Title" line per track, ready
+for a Soulseek search list.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli beets match --library ~/.config/beets/library.db
+  spotify-cli beets match --playlist 37i9dQZF1DXcBWIGoYBM5M --playlist 37i9dQZF1DX0XUsuxWHRQd
+  spotify-cli beets match --library library.json --albums --format csv > missing.csv`,
+	Args: cobra.NoArgs,
+	RunE: runBeetsMatch,
+}
+
+func init() {
+	beetsMatchCmd.Flags().StringVar(&beetsLibrary, "library", "", "beets library database or 'beet export' JSON (default: $BEETSDIR/library.db or ~/.config/beets/library.db)")
+	beetsMatchCmd.Flags().StringArrayVar(&beetsPlaylists, "playlist", nil, "Check the tracks of this playlist (repeatable)")
+	beetsMatchCmd.Flags().BoolVar(&beetsSaved, "saved", false, "Check your saved tracks (the default without --playlist)")
+	beetsMatchCmd.Flags().BoolVar(&beetsAlbums, "albums", false, "List the albums of the missing tracks instead of the tracks")
+	beetsMatchCmd.Flags().StringVarP(&beetsFormat, "format", "f", "table", "Output format (table, text, csv, json, yaml)")
+
+	beetsCmd.AddCommand(beetsMatchCmd)
+	rootCmd.AddCommand(beetsCmd)
+}
+
+// beetsMissingTrack is a Spotify track the library does not have, and where
+// on Spotify it was found
+type beetsMissingTrack struct {
+	Name    string   `json:"name" yaml:"name"`
+	Artists []string `json:"artists" yaml:"artists"`
+	Album   string   `json:"album,omitempty" yaml:"album,omitempty"`
+	URI     string   `json:"uri" yaml:"uri"`
+	Sources []string `json:"sources" yaml:"sources"`
+}
+
+// beetsMissingAlbum is an album with missing tracks
+type beetsMissingAlbum struct {
+	Artist  string `json:"artist" yaml:"artist"`
+	Album   string `json:"album" yaml:"album"`
+	Missing int    `json:"missing" yaml:"missing"`
+}
+
+// beetsMatchResult is the outcome of a match
+type beetsMatchResult struct {
+	Library       string              `json:"library" yaml:"library"`
+	LibraryTracks int                 `json:"library_tracks" yaml:"library_tracks"`
+	Checked       int                 `json:"checked" yaml:"checked"`
+	Found         int                 `json:"found" yaml:"found"`
+	Missing       []beetsMissingTrack `json:"missing,omitempty" yaml:"missing,omitempty"`
+	Albums        []beetsMissingAlbum `json:"albums,omitempty" yaml:"albums,omitempty"`
+}
+
+// beetsSource is a set of Spotify tracks to check
+type beetsSource struct {
+	name   string
+	tracks []playlistExportTrack
+}
+
+func runBeetsMatch(cmd *cobra.Command, args []string) error {
+	switch beetsFormat {
+	case "table", "text", "csv", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, text, csv, json, yaml", beetsFormat)
+	}
+
+	path := beetsLibrary
+	if path == "" {
+		path = defaultBeetsLibrary()
+	}
+	ctx := GetCommandContext()
+	items, err := beets.Load(ctx, path)
+	if err != nil {
+		return fmt.Errorf("failed to read beets library %s: %w", path, err)
+	}
+	library := beets.NewLibrary(items)
+	utils.PrintVerbose("Read %d track%s from %s", library.Len(), pluralize(library.Len()), path)
+
+	sources, err := beetsSources()
+	if err != nil {
+		return err
+	}
+
+	result := &beetsMatchResult{Library: path, LibraryTracks: library.Len()}
+	seen := make(map[string]int)
+	for _, source := range sources {
+		for _, track := range source.tracks {
+			if track.IsLocal || !strings.HasPrefix(track.URI, "spotify:track:") {
+				continue
+			}
+			if i, ok := seen[track.URI]; ok {
+				if i >= 0 {
+					result.Missing[i].Sources = append(result.Missing[i].Sources, source.name)
+				}
+				continue
+			}
+
+			result.Checked++
+			found := library.Find(trackmatch.Track{
+				Title:    track.Name,
+				Artists:  track.Artists,
+				Album:    track.Album,
+				Duration: time.Duration(track.DurationMs) * time.Millisecond,
+			})
+			if found != nil {
+				result.Found++
+				seen[track.URI] = -1
+				continue
+			}
+			seen[track.URI] = len(result.Missing)
+			result.Missing = append(result.Missing, beetsMissingTrack{
+				Name:    track.Name,
+				Artists: track.Artists,
+				Album:   track.Album,
+				URI:     track.URI,
+				Sources: []string{source.name},
+			})
+		}
+	}
+	commandReport.Add("tracks found", result.Found)
+	commandReport.Add("tracks missing", len(result.Missing))
+
+	if beetsAlbums {
+		result.Albums = missingAlbums(result.Missing)
+		result.Missing = nil
+	}
+	return outputBeetsMatch(result)
+}
+
+// defaultBeetsLibrary is where beets keeps its database unless told otherwise
+func defaultBeetsLibrary() string {
+	dir := os.Getenv("BEETSDIR")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config", "beets")
+	}
+	return filepath.Join(dir, "library.db")
+}
+
+// beetsSources reads the tracks to check: the playlists asked for and the
+// saved tracks, which are the default
+func beetsSources() ([]beetsSource, error) {
+	spotifyClient, err := newUserClient("your saved tracks and playlists")
+	if err != nil {
+		return nil, err
+	}
+	ctx := GetCommandContext()
+
+	var sources []beetsSource
+	if beetsSaved || len(beetsPlaylists) == 0 {
+		saved, err := spotifyClient.Library.SavedTracksPager(nil).All(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get saved tracks: %w", err)
+		}
+		source := beetsSource{name: "saved tracks"}
+		for _, item := range saved {
+			track := playlistExportTrack{
+				Name:       item.Track.Name,
+				Artists:    make([]string, 0, len(item.Track.Artists)),
+				URI:        item.Track.URI,
+				DurationMs: item.Track.DurationMs,
+				IsLocal:    item.Track.IsLocal,
+			}
+			for _, artist := range item.Track.Artists {
+				track.Artists = append(track.Artists, artist.Name)
+			}
+			if item.Track.Album != nil {
+				track.Album = item.Track.Album.Name
+			}
+			source.tracks = append(source.tracks, track)
+		}
+		sources = append(sources, source)
+	}
+
+	for _, ref := range beetsPlaylists {
+		playlistID, err := normalizePlaylistID(ref)
+		if err != nil {
+			return nil, err
+		}
+		export, err := fetchPlaylistExport(ctx, spotifyClient, playlistID)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, beetsSource{name: export.Name, tracks: export.Tracks})
+	}
+	return sources, nil
+}
+
+// missingAlbums groups missing tracks by album, those missing most first
+func missingAlbums(missing []beetsMissingTrack) []beetsMissingAlbum {
+	var albums []beetsMissingAlbum
+	index := make(map[string]int)
+	for _, track := range missing {
+		artist := ""
+		if len(track.Artists) > 0 {
+			artist = track.Artists[0]
+		}
+		key := strings.ToLower(artist + "\x00" + track.Album)
+		if i, ok := index[key]; ok {
+			albums[i].Missing++
+			continue
+		}
+		index[key] = len(albums)
+		albums = append(albums, beetsMissingAlbum{Artist: artist, Album: track.Album, Missing: 1})
+	}
+	sort.SliceStable(albums, func(i, j int) bool { return albums[i].Missing > albums[j].Missing })
+	return albums
+}
+
+func outputBeetsMatch(result *beetsMatchResult) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := beetsFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	switch outputFormat {
+	case "json", "yaml":
+		return utils.Output(result)
+	case "text":
+		for _, track := range result.Missing {
+			fmt.Printf("%s - %s\n", strings.Join(track.Artists, ", "), track.Name)
+		}
+		for _, album := range result.Albums {
+			fmt.Printf("%s - %s\n", album.Artist, album.Album)
+		}
+		return nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		if beetsAlbums {
+			w.Write([]string{"artist", "album", "missing"})
+			for _, album := range result.Albums {
+				w.Write([]string{album.Artist, album.Album, fmt.Sprint(album.Missing)})
+			}
+		} else {
+			w.Write([]string{"name", "artists", "album", "uri", "sources"})
+			for _, track := range result.Missing {
+				w.Write([]string{track.Name, strings.Join(track.Artists, ", "), track.Album, track.URI, strings.Join(track.Sources, ", ")})
+			}
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	missing := result.Checked - result.Found
+	fmt.Printf("%d of %d Spotify track%s missing from beets (%d tracks in the library)\n",
+		missing, result.Checked, pluralize(result.Checked), result.LibraryTracks)
+	if missing == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	if beetsAlbums {
+		fmt.Printf("%-30s %-40s %s\n", "ARTIST", "ALBUM", "MISSING")
+		fmt.Println(strings.Repeat("-", 80))
+		for _, album := range result.Albums {
+			fmt.Printf("%-30s %-40s %d\n", truncateString(album.Artist, 28), truncateString(album.Album, 38), album.Missing)
+		}
+		return nil
+	}
+	fmt.Printf("%-35s %-25s %-30s %s\n", "TRACK", "ARTIST", "ALBUM", "URI")
+	fmt.Println(strings.Repeat("-", 130))
+	for _, track := range result.Missing {
+		fmt.Printf("%-35s %-25s %-30s %s\n",
+			truncateString(track.Name, 33),
+			truncateString(strings.Join(track.Artists, ", "), 23),
+			truncateString(track.Album, 28),
+			track.URI)
+	}
+	return nil
+}
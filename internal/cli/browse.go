@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/cli/client"
@@ -17,6 +18,11 @@ var (
 	browseLimit   int
 	browseOffset  int
 	browseCountry string
+	browseLocale  string
+	browseFormat  string
+
+	browseTimestamp string
+	browsePlay      int
 )
 
 // browseCmd represents the browse command
@@ -31,7 +37,11 @@ Use 'auth login' or 'auth client-credentials' to authenticate first.`,
   spotify-cli browse new-releases
 
   # Browse featured playlists
-  spotify-cli browse featured-playlists
+  spotify-cli browse featured
+
+  # List categories and the playlists in one
+  spotify-cli browse categories
+  spotify-cli browse category toplists
 
   # Browse with specific country/market
   spotify-cli browse new-releases --country US`,
@@ -49,27 +59,68 @@ var newReleasesCmd = &cobra.Command{
 }
 
 var featuredPlaylistsCmd = &cobra.Command{
-	Use:   "featured-playlists",
-	Short: "Browse featured playlists",
-	Long:  `Browse Spotify's featured playlists and editorial recommendations.`,
-	Example: `  spotify-cli browse featured-playlists
-  spotify-cli browse featured-playlists --limit 20 --country GB`,
+	Use:     "featured",
+	Aliases: []string{"featured-playlists"},
+	Short:   "Browse featured playlists",
+	Long: `Browse Spotify's featured playlists and editorial recommendations.
+
+The selection changes through the day; use --timestamp to see what is featured
+at another time. Use --play to start one of the listed playlists.`,
+	Example: `  spotify-cli browse featured
+  spotify-cli browse featured --limit 20 --country GB --locale en_GB
+  spotify-cli browse featured --timestamp 2024-06-01T08:00
+  spotify-cli browse featured --play 1`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runBrowseFeaturedPlaylists()
 	},
 }
 
+var categoriesCmd = &cobra.Command{
+	Use:   "categories",
+	Short: "List browse categories",
+	Long:  `List the categories used to tag playlists in Spotify's browse tab, such as moods and genres.`,
+	Example: `  spotify-cli browse categories
+  spotify-cli browse categories --country SE --locale sv_SE
+  spotify-cli browse categories --limit 50 --offset 50`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBrowseCategories()
+	},
+}
+
+var categoryCmd = &cobra.Command{
+	Use:   "category <id>",
+	Short: "Show a browse category and its playlists",
+	Long: `Show a browse category and the playlists tagged with it. Category IDs are
+listed by 'browse categories'.`,
+	Example: `  spotify-cli browse category toplists
+  spotify-cli browse category 0JQ5DAqbMKFQ00XGBls6ym --country GB --format list`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBrowseCategory(args[0])
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(browseCmd)
 	browseCmd.AddCommand(newReleasesCmd)
 	browseCmd.AddCommand(featuredPlaylistsCmd)
+	browseCmd.AddCommand(categoriesCmd)
+	browseCmd.AddCommand(categoryCmd)
 
 	// Add flags to browse commands
-	for _, cmd := range []*cobra.Command{newReleasesCmd, featuredPlaylistsCmd} {
+	for _, cmd := range []*cobra.Command{newReleasesCmd, featuredPlaylistsCmd, categoriesCmd, categoryCmd} {
 		cmd.Flags().IntVarP(&browseLimit, "limit", "l", 20, "Number of results to return (1-50)")
 		cmd.Flags().IntVarP(&browseOffset, "offset", "", 0, "Offset for pagination")
 		cmd.Flags().StringVarP(&browseCountry, "country", "c", "", "Country/market code (e.g., US, GB)")
 	}
+
+	featuredPlaylistsCmd.Flags().StringVar(&browseTimestamp, "timestamp", "", "Local time to get featured playlists for (e.g., 2024-06-01T08:00)")
+	featuredPlaylistsCmd.Flags().IntVar(&browsePlay, "play", 0, "Play the Nth listed playlist")
+
+	for _, cmd := range []*cobra.Command{featuredPlaylistsCmd, categoriesCmd, categoryCmd} {
+		cmd.Flags().StringVar(&browseLocale, "locale", "", "Language for category names (e.g., es_MX)")
+		cmd.Flags().StringVarP(&browseFormat, "format", "f", "table", "Output format (table, list, json, yaml)")
+	}
 }
 
 func runBrowseNewReleases() error {
@@ -97,14 +148,253 @@ func runBrowseNewReleases() error {
 	return outputBrowseResults("new releases", albums, pagination)
 }
 
+func runBrowseCategories() error {
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	options := &spotify.CategoriesOptions{
+		Country: browseCountry,
+		Locale:  browseLocale,
+		Limit:   browseLimit,
+		Offset:  browseOffset,
+	}
+
+	categories, pagination, err := spotifyClient.Browse.GetCategories(GetCommandContext(), options)
+	if err != nil {
+		return fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	if format := browseOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"results":    categories,
+			"pagination": pagination,
+		})
+	}
+
+	if len(categories.Items) == 0 {
+		fmt.Println("No categories found.")
+		return nil
+	}
+
+	fmt.Printf("Categories - %d total", categories.Total)
+	if pagination != nil {
+		fmt.Printf(" (showing %d-%d)", pagination.Offset+1, pagination.Offset+len(categories.Items))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if browseFormat == "list" {
+		for i, category := range categories.Items {
+			fmt.Printf("%d. %s\n", i+1, category.Name)
+			fmt.Printf("   ID: %s\n", category.ID)
+			fmt.Println()
+		}
+	} else {
+		fmt.Printf("%-30s %s\n", "ID", "NAME")
+		fmt.Println(strings.Repeat("-", 70))
+
+		for _, category := range categories.Items {
+			fmt.Printf("%-30s %s\n", category.ID, truncateString(category.Name, 38))
+		}
+	}
+
+	printNextOffset(pagination)
+	return nil
+}
+
+func runBrowseCategory(categoryID string) error {
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	category, err := spotifyClient.Browse.GetCategory(ctx, categoryID, browseCountry, browseLocale)
+	if err != nil {
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	options := &spotify.CategoryPlaylistsOptions{
+		Country: browseCountry,
+		Limit:   browseLimit,
+		Offset:  browseOffset,
+	}
+
+	playlists, pagination, err := spotifyClient.Browse.GetCategoryPlaylists(ctx, category.ID, options)
+	if err != nil {
+		return fmt.Errorf("failed to get category playlists: %w", err)
+	}
+
+	if format := browseOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"category":   category,
+			"results":    playlists,
+			"pagination": pagination,
+		})
+	}
+
+	fmt.Printf("%s - %d playlist%s\n", category.Name, playlists.Total, pluralize(playlists.Total))
+	if pagination != nil && len(playlists.Items) > 0 {
+		fmt.Printf("(showing %d-%d)\n", pagination.Offset+1, pagination.Offset+len(playlists.Items))
+	}
+	fmt.Println()
+
+	if len(playlists.Items) == 0 {
+		fmt.Println("No playlists found.")
+		return nil
+	}
+
+	printBrowsePlaylists(playlists.Items)
+
+	printNextOffset(pagination)
+	return nil
+}
+
+// newBrowseClient creates a client for browse endpoints, which accept either
+// user or client credentials authentication
+func newBrowseClient() (*client.SpotifyClient, error) {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return nil, fmt.Errorf("authentication required. Run 'spotify-cli auth login' or 'spotify-cli auth client-credentials'")
+	}
+
+	return spotifyClient, nil
+}
+
+func browseOutputFormat() string {
+	cfg := config.Get()
+	if browseFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		return cfg.DefaultOutput
+	}
+	return browseFormat
+}
+
+func playlistOwnerName(owner models.User) string {
+	if owner.DisplayName != "" {
+		return owner.DisplayName
+	}
+	return owner.ID
+}
+
 func runBrowseFeaturedPlaylists() error {
-	// Note: This would require implementing the browse endpoints in the API client
-	// For now, return a message indicating this is not yet implemented
-	fmt.Println("Featured playlists browsing is not yet implemented.")
-	fmt.Println("This feature requires the Spotify Browse API endpoints to be implemented.")
+	options := &spotify.FeaturedPlaylistsOptions{
+		Country: browseCountry,
+		Locale:  browseLocale,
+		Limit:   browseLimit,
+		Offset:  browseOffset,
+	}
+
+	if browseTimestamp != "" {
+		timestamp, err := parseBrowseTimestamp(browseTimestamp)
+		if err != nil {
+			return err
+		}
+		options.Timestamp = timestamp
+	}
+
+	var spotifyClient *client.SpotifyClient
+	var err error
+	if browsePlay > 0 {
+		spotifyClient, err = newUserClient("playback")
+	} else {
+		spotifyClient, err = newBrowseClient()
+	}
+	if err != nil {
+		return err
+	}
+
+	featured, pagination, err := spotifyClient.Browse.GetFeaturedPlaylists(GetCommandContext(), options)
+	if err != nil {
+		return fmt.Errorf("failed to get featured playlists: %w", err)
+	}
+
+	if browsePlay > 0 {
+		if browsePlay > len(featured.Playlists.Items) {
+			return fmt.Errorf("--play %d is out of range: %d playlists listed", browsePlay, len(featured.Playlists.Items))
+		}
+
+		playlist := featured.Playlists.Items[browsePlay-1]
+		if err := spotifyClient.Player.Play(GetCommandContext(), &spotify.PlayOptions{ContextURI: playlist.URI}); err != nil {
+			return fmt.Errorf("failed to play %s: %w", playlist.Name, err)
+		}
+
+		utils.PrintSuccess("Playing %s by %s", playlist.Name, playlistOwnerName(playlist.Owner))
+		return nil
+	}
+
+	if format := browseOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"message":    featured.Message,
+			"results":    featured.Playlists,
+			"pagination": pagination,
+		})
+	}
+
+	if featured.Message != "" {
+		fmt.Println(featured.Message)
+	} else {
+		fmt.Println("Featured Playlists")
+	}
+	if pagination != nil && len(featured.Playlists.Items) > 0 {
+		fmt.Printf("%d total (showing %d-%d)\n", featured.Playlists.Total, pagination.Offset+1, pagination.Offset+len(featured.Playlists.Items))
+	}
+	fmt.Println()
+
+	if len(featured.Playlists.Items) == 0 {
+		fmt.Println("No featured playlists found.")
+		return nil
+	}
+
+	printBrowsePlaylists(featured.Playlists.Items)
+
+	fmt.Println()
+	fmt.Println("Use --play <n> to start a playlist")
+	printNextOffset(pagination)
 	return nil
 }
 
+// parseBrowseTimestamp accepts a local time with or without seconds, or a
+// full RFC 3339 timestamp
+func parseBrowseTimestamp(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+		if timestamp, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --timestamp %q: expected a time such as 2024-06-01T08:00", value)
+}
+
+// printBrowsePlaylists lists playlists numbered from 1, in the chosen format
+func printBrowsePlaylists(playlists []models.SimplePlaylist) {
+	if browseFormat == "list" {
+		for i, playlist := range playlists {
+			fmt.Printf("%d. %s\n", i+1, playlist.Name)
+			fmt.Printf("   ID: %s\n", playlist.ID)
+			fmt.Printf("   by %s, %d tracks\n", playlistOwnerName(playlist.Owner), playlist.Tracks.Total)
+			fmt.Println()
+		}
+		return
+	}
+
+	fmt.Printf("%-4s %-22s %-40s %-20s %s\n", "#", "ID", "PLAYLIST", "OWNER", "TRACKS")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for i, playlist := range playlists {
+		fmt.Printf("%-4d %-22s %-40s %-20s %d\n",
+			i+1,
+			playlist.ID,
+			truncateString(playlist.Name, 38),
+			truncateString(playlistOwnerName(playlist.Owner), 18),
+			playlist.Tracks.Total)
+	}
+}
+
 func outputBrowseResults(browseType string, results interface{}, pagination *api.PaginationInfo) error {
 	cfg := config.Get()
 
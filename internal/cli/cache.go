@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/client"
+	"github.com/bambithedeer/spotify-api/internal/musicbrainz"
+	"github.com/bambithedeer/spotify-api/internal/trackcache"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the API response cache",
+	Long: `Manage the on-disk cache of Spotify catalog responses.
+
+Responses for catalog endpoints (search, albums, artists, tracks, browse) are
+cached and revalidated with ETag / If-None-Match, so repeated lookups are
+served locally. Your library, playlists and playback state are never cached.
+Shows and episodes carry your resume points, and market=from_token results
+depend on your account, so each profile keeps its own responses.
+
+Pass --no-cache to any command to bypass the cache, or set cache_enabled:
+false in the config file to turn it off. cache_ttl controls how long
+responses without Cache-Control headers are considered fresh.
+
+The Lidarr commands also keep the MusicBrainz matches they find for artist
+and album names, so a repeated import only searches for names it has not
+seen. 'playlist search' and 'library search' keep the tracks they read,
+by snapshot ID, so searching again needs only one request. 'cache clear'
+removes those too.`,
+	Example: `  spotify-cli cache info
+  spotify-cli cache clear
+  spotify-cli search "radiohead" --no-cache`,
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:     "info",
+	Short:   "Show cache location and size",
+	Example: `  spotify-cli cache info`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCacheInfo()
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:     "clear",
+	Short:   "Remove all cached responses, MusicBrainz lookups and tracks",
+	Example: `  spotify-cli cache clear`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCacheClear()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+// httpCacheDir returns the response cache directory of the profile, even
+// when caching is disabled for this invocation. Responses may hold data of
+// the account they were fetched for, so profiles do not share them.
+func httpCacheDir() string {
+	return filepath.Join(cacheDir, "http", profileName)
+}
+
+func runCacheInfo() error {
+	cache, err := client.NewDiskCache(httpCacheDir())
+	if err != nil {
+		return err
+	}
+
+	entries, size, err := cache.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+
+	lookups, err := musicbrainz.NewCache(musicBrainzCacheFile()).Len()
+	if err != nil {
+		return err
+	}
+	collections, err := trackcache.New(trackCacheDir()).Len()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	enabled := config.CacheDir() != ""
+
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"path":                cache.Dir(),
+			"enabled":             enabled,
+			"ttl":                 cfg.CacheTTL,
+			"entries":             entries,
+			"bytes":               size,
+			"musicbrainz_path":    musicBrainzCacheFile(),
+			"musicbrainz_lookups": lookups,
+			"tracks_path":         trackCacheDir(),
+			"track_collections":   collections,
+		})
+	}
+
+	fmt.Printf("Path:    %s\n", cache.Dir())
+	fmt.Printf("Enabled: %t\n", enabled)
+	fmt.Printf("TTL:     %s\n", cfg.CacheTTL)
+	fmt.Printf("Entries: %d\n", entries)
+	fmt.Printf("Size:    %s\n", formatBytes(size))
+	fmt.Printf("\nMusicBrainz lookups: %d (kept %d days, in %s)\n", lookups, int(musicbrainz.CacheTTL.Hours()/24), musicBrainzCacheFile())
+	fmt.Printf("Track lists kept for search: %d (in %s)\n", collections, trackCacheDir())
+
+	return nil
+}
+
+func runCacheClear() error {
+	cache, err := client.NewDiskCache(httpCacheDir())
+	if err != nil {
+		return err
+	}
+
+	removed, err := cache.Clear()
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	lookups, err := musicbrainz.NewCache(musicBrainzCacheFile()).Clear()
+	if err != nil {
+		return err
+	}
+	collections, err := trackcache.New(trackCacheDir()).Clear()
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Removed %d cached response%s, %d MusicBrainz lookup%s and %d track list%s",
+		removed, pluralize(removed), lookups, pluralize(lookups), collections, pluralize(collections))
+	return nil
+}
+
+// musicBrainzCacheFile returns the file of MusicBrainz lookups kept for
+// Lidarr imports
+func musicBrainzCacheFile() string {
+	return filepath.Join(cacheDir, "musicbrainz.json")
+}
+
+// musicBrainzCache returns the cache of MusicBrainz lookups, or nil when
+// caching is disabled for this invocation
+func musicBrainzCache() *musicbrainz.Cache {
+	if config.CacheDir() == "" {
+		return nil
+	}
+	return musicbrainz.NewCache(musicBrainzCacheFile())
+}
+
+// trackCacheDir returns the directory of tracks kept for local searches
+func trackCacheDir() string {
+	return filepath.Join(cacheDir, "tracks")
+}
+
+// trackCache returns the cache of tracks kept for local searches, or nil
+// when caching is disabled for this invocation
+func trackCache() *trackcache.Cache {
+	if config.CacheDir() == "" {
+		return nil
+	}
+	return trackcache.New(trackCacheDir())
+}
+
+// formatBytes formats a byte count with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -2,12 +2,16 @@ package client
 
 import (
 	"fmt"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/auth"
 	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
 	"github.com/bambithedeer/spotify-api/internal/client"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 	"github.com/bambithedeer/spotify-api/internal/spotify"
 )
 
@@ -24,26 +28,91 @@ type SpotifyClient struct {
 	Library   *spotify.LibraryService
 	Users     *spotify.UsersService
 	Player    *spotify.PlayerService
+	Shows     *spotify.ShowsService
+	Episodes  *spotify.EpisodesService
+	Browse    *spotify.BrowseService
+	Markets   *spotify.MarketsService
 }
 
+// writeObserver is given every write request made by clients created with
+// NewSpotifyClient
+var writeObserver func(client.WriteEvent)
+
+// SetWriteObserver sets the function told about every write request, used to
+// keep the audit log
+func SetWriteObserver(fn func(client.WriteEvent)) {
+	writeObserver = fn
+}
+
+var (
+	clientsMu sync.Mutex
+	// clients are the clients created by NewSpotifyClient, totalled by Usage
+	clients []*client.Client
+)
+
 // NewSpotifyClient creates a new Spotify client for CLI use
 func NewSpotifyClient() (*SpotifyClient, error) {
-	cfg := config.Get()
+	return newSpotifyClient(config.Get(), configTokenStore{})
+}
 
-	if !config.HasCredentials() {
+// NewProfileClient creates a client for a profile other than the current
+// one, for commands that work with two accounts at once. Its tokens are
+// loaded from and refreshed into that profile's own config.
+func NewProfileClient(configDir, name string) (*SpotifyClient, error) {
+	cfg, err := config.LoadProfile(configDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("Spotify API credentials not configured for profile %q. Run 'spotify-cli --profile %s auth setup' first", name, name)
+	}
+
+	return newSpotifyClient(cfg, &profileTokenStore{configDir: configDir, name: name, cfg: cfg})
+}
+
+func newSpotifyClient(cfg *config.Config, tokenStore auth.TokenStore) (*SpotifyClient, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
 		return nil, fmt.Errorf("Spotify API credentials not configured. Run 'spotify-cli auth setup' first")
 	}
 
-	// Create the underlying client
-	spotifyClient := client.NewClient(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI)
+	retryConfig, err := retryConfigFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []client.Option{
+		client.WithRetryConfig(retryConfig),
+		client.WithRetryObserver(reportRetry),
+		client.WithTokenStore(tokenStore),
+	}
+	if writeObserver != nil {
+		options = append(options, client.WithWriteObserver(writeObserver))
+	}
 
-	// Set token if available
-	if config.IsAuthenticated() {
-		token, err := parseToken(cfg)
+	if dir := config.CacheDir(); dir != "" {
+		cache, ttl, err := newResponseCache(dir, cfg.CacheTTL)
 		if err != nil {
+			// The cache is an optimisation; carry on without it
+			utils.PrintVerbose("Response cache disabled: %v", err)
+		} else {
+			options = append(options, client.WithCache(cache, ttl))
+		}
+	}
+
+	// Create the underlying client
+	spotifyClient := client.NewClient(cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI, options...)
+
+	clientsMu.Lock()
+	clients = append(clients, spotifyClient)
+	clientsMu.Unlock()
+
+	// The token store loads the saved token; check it here so a malformed
+	// one is reported rather than treated as logged out
+	if cfg.HasValidToken() {
+		if _, err := parseToken(cfg); err != nil {
 			return nil, fmt.Errorf("invalid token configuration: %w", err)
 		}
-		spotifyClient.SetToken(token)
 	}
 
 	// Create service instances
@@ -56,6 +125,98 @@ func NewSpotifyClient() (*SpotifyClient, error) {
 	return sc, nil
 }
 
+// retryConfigFromConfig builds the retry policy from the CLI configuration
+func retryConfigFromConfig(cfg *config.Config) (*ratelimit.RetryConfig, error) {
+	retryConfig := ratelimit.DefaultRetryConfig()
+
+	if cfg.MaxRetries < 0 {
+		return nil, fmt.Errorf("invalid max_retries %d: must not be negative", cfg.MaxRetries)
+	}
+	if cfg.MaxRetries > 0 {
+		retryConfig.MaxRetries = cfg.MaxRetries
+	}
+
+	if cfg.MaxRetryWait != "" {
+		wait, err := time.ParseDuration(cfg.MaxRetryWait)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_retry_wait %q: %w", cfg.MaxRetryWait, err)
+		}
+		retryConfig.MaxRetryAfter = wait
+	}
+
+	return retryConfig, nil
+}
+
+// newResponseCache creates the in-memory and on-disk response cache
+func newResponseCache(dir, ttl string) (client.Cache, time.Duration, error) {
+	fallbackTTL := time.Hour
+	if ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid cache_ttl %q: %w", ttl, err)
+		}
+		fallbackTTL = parsed
+	}
+
+	disk, err := client.NewDiskCache(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return client.NewLayeredCache(client.NewMemoryCache(0), disk), fallbackTTL, nil
+}
+
+// reportRetry tells the user why a request is being retried
+func reportRetry(event client.RetryEvent) {
+	delay := event.Delay.Round(time.Second)
+	if delay < time.Second {
+		delay = event.Delay.Round(100 * time.Millisecond)
+	}
+
+	// Written to stderr so it never mixes with JSON or YAML output
+	if event.RateLimited {
+		fmt.Fprintf(os.Stderr, "rate limited, retrying in %s (attempt %d/%d)\n", delay, event.Attempt, event.MaxRetries)
+		return
+	}
+
+	if event.StatusCode != 0 {
+		utils.PrintVerbose("Request failed with status %d, retrying in %s (attempt %d/%d)", event.StatusCode, delay, event.Attempt, event.MaxRetries)
+		return
+	}
+
+	utils.PrintVerbose("Request failed (%v), retrying in %s (attempt %d/%d)", event.Err, delay, event.Attempt, event.MaxRetries)
+}
+
+// Usage totals the request and cache counters of every client created by
+// NewSpotifyClient in this process
+func Usage() (client.RateLimitStats, client.CacheStats) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	var stats client.RateLimitStats
+	var cacheStats client.CacheStats
+	for _, c := range clients {
+		s := c.RateLimitStats()
+		stats.Requests += s.Requests
+		stats.RateLimited += s.RateLimited
+		stats.Retries += s.Retries
+		stats.TotalBackoff += s.TotalBackoff
+		if s.LastRateLimit.After(stats.LastRateLimit) {
+			stats.LastRateLimit = s.LastRateLimit
+		}
+		if s.RetryAfter.After(stats.RetryAfter) {
+			stats.RetryAfter = s.RetryAfter
+		}
+
+		cs := c.CacheStats()
+		cacheStats.Hits += cs.Hits
+		cacheStats.Revalidated += cs.Revalidated
+		cacheStats.Misses += cs.Misses
+	}
+
+	return stats, cacheStats
+}
+
 // NewUnauthenticatedClient creates a client that can be used for authentication
 func NewUnauthenticatedClient() (*SpotifyClient, error) {
 	cfg := config.Get()
@@ -95,6 +256,21 @@ func (sc *SpotifyClient) SaveToken() error {
 		return fmt.Errorf("no token to save")
 	}
 
+	return configTokenStore{}.Save(token)
+}
+
+// configTokenStore keeps the token in the CLI configuration, so tokens
+// refreshed during one command are reused by the next
+type configTokenStore struct{}
+
+func (configTokenStore) Load() (*auth.Token, error) {
+	if !config.IsAuthenticated() {
+		return nil, nil
+	}
+	return parseToken(config.Get())
+}
+
+func (configTokenStore) Save(token *auth.Token) error {
 	expiresAt := ""
 	if !token.Expiry.IsZero() {
 		expiresAt = token.Expiry.Format(time.RFC3339)
@@ -104,6 +280,32 @@ func (sc *SpotifyClient) SaveToken() error {
 	return config.Save()
 }
 
+// profileTokenStore keeps the token in the config of a profile that is not
+// the current one
+type profileTokenStore struct {
+	configDir string
+	name      string
+	cfg       *config.Config
+}
+
+func (s *profileTokenStore) Load() (*auth.Token, error) {
+	if !s.cfg.HasValidToken() {
+		return nil, nil
+	}
+	return parseToken(s.cfg)
+}
+
+func (s *profileTokenStore) Save(token *auth.Token) error {
+	s.cfg.AccessToken = token.AccessToken
+	s.cfg.RefreshToken = token.RefreshToken
+	s.cfg.TokenType = token.TokenType
+	s.cfg.ExpiresAt = ""
+	if !token.Expiry.IsZero() {
+		s.cfg.ExpiresAt = token.Expiry.Format(time.RFC3339)
+	}
+	return config.SaveProfile(s.configDir, s.name, s.cfg)
+}
+
 // initServices initializes all service instances
 func (sc *SpotifyClient) initServices() {
 	requestBuilder := api.NewRequestBuilder(sc.client)
@@ -116,6 +318,10 @@ func (sc *SpotifyClient) initServices() {
 	sc.Library = spotify.NewLibraryService(requestBuilder)
 	sc.Users = spotify.NewUsersService(requestBuilder)
 	sc.Player = spotify.NewPlayerService(requestBuilder)
+	sc.Shows = spotify.NewShowsService(requestBuilder)
+	sc.Episodes = spotify.NewEpisodesService(requestBuilder)
+	sc.Browse = spotify.NewBrowseService(requestBuilder)
+	sc.Markets = spotify.NewMarketsService(requestBuilder)
 }
 
 // parseToken converts config token data to auth.Token
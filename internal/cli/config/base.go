@@ -0,0 +1,328 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// BaseConfigTTL is how long a fetched base config is used before it is
+// fetched again
+const BaseConfigTTL = time.Hour
+
+// DefaultBaseConfigFile is the file read from a git repository when the
+// base config source names none
+const DefaultBaseConfigFile = "spotify-cli.yaml"
+
+const (
+	baseCacheFile = "base_config.yaml"
+	baseRepoDir   = "base_config.git"
+	maxBaseSize   = 1 << 20
+	fetchTimeout  = 15 * time.Second
+)
+
+// baseHTTPClient fetches base configs over HTTPS
+var baseHTTPClient = http.DefaultClient
+
+// localOnlyKeys are never taken from a base config, so a shared file cannot
+// log anyone in or out, or point at yet another base
+var localOnlyKeys = map[string]bool{
+	"schema_version": true,
+	"base_config":    true,
+	"access_token":   true,
+	"refresh_token":  true,
+	"token_type":     true,
+	"expires_at":     true,
+}
+
+// BaseSource is a parsed base_config setting
+type BaseSource struct {
+	// URL is the HTTPS URL of the file, or the git repository to clone
+	URL string
+	// Git is set for a repository, given as git+<url>[#path]
+	Git bool
+	// Path is the file within the repository
+	Path string
+}
+
+// ParseBaseSource parses a base_config value: either an HTTPS URL of a YAML
+// file, or git+<repository URL> with an optional #path to the file in it
+func ParseBaseSource(value string) (BaseSource, error) {
+	if rest, ok := strings.CutPrefix(value, "git+"); ok {
+		repo, path, _ := strings.Cut(rest, "#")
+		if path == "" {
+			path = DefaultBaseConfigFile
+		}
+		if filepath.IsAbs(path) || strings.Contains(filepath.ToSlash(filepath.Clean(path)), "../") || filepath.Clean(path) == ".." {
+			return BaseSource{}, fmt.Errorf("invalid base config path %q: must be inside the repository", path)
+		}
+		if repo == "" {
+			return BaseSource{}, fmt.Errorf("invalid base config %q: missing repository", value)
+		}
+		return BaseSource{URL: repo, Git: true, Path: path}, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return BaseSource{}, fmt.Errorf("invalid base config %q: use an https:// URL or git+<repository>", value)
+	}
+	return BaseSource{URL: value}, nil
+}
+
+// baseState is the base config merged into the current configuration
+var baseState struct {
+	// inherited are the fields that came from the base config, with the
+	// local values they replaced, so Save writes those back instead
+	inherited map[int]reflect.Value
+	values    *Config
+}
+
+// mergeBase fills in the settings config leaves at their defaults from the
+// base config data. It returns the fields taken, by index, with the local
+// values they replaced.
+func mergeBase(config *Config, data []byte) (map[int]reflect.Value, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse base config: %w", err)
+	}
+	for key := range localOnlyKeys {
+		delete(doc, key)
+	}
+	cleaned, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	base := Default()
+	if err := yaml.Unmarshal(cleaned, base); err != nil {
+		return nil, fmt.Errorf("failed to parse base config: %w", err)
+	}
+	defaults := Default()
+
+	inherited := make(map[int]reflect.Value)
+	local := reflect.ValueOf(config).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	defaultValue := reflect.ValueOf(defaults).Elem()
+	for i := 0; i < local.NumField(); i++ {
+		key := strings.Split(local.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if localOnlyKeys[key] {
+			continue
+		}
+		field := local.Field(i)
+		if !reflect.DeepEqual(field.Interface(), defaultValue.Field(i).Interface()) {
+			continue
+		}
+		if reflect.DeepEqual(baseValue.Field(i).Interface(), defaultValue.Field(i).Interface()) {
+			continue
+		}
+		original := reflect.New(field.Type()).Elem()
+		original.Set(field)
+		inherited[i] = original
+		field.Set(baseValue.Field(i))
+	}
+	return inherited, nil
+}
+
+// withoutBase returns a copy of config with the settings still holding their
+// base config values put back to what the local file had
+func withoutBase(config *Config) *Config {
+	if len(baseState.inherited) == 0 || baseState.values == nil {
+		return config
+	}
+
+	local := *config
+	value := reflect.ValueOf(&local).Elem()
+	base := reflect.ValueOf(baseState.values).Elem()
+	for i, original := range baseState.inherited {
+		if reflect.DeepEqual(value.Field(i).Interface(), base.Field(i).Interface()) {
+			value.Field(i).Set(original)
+		}
+	}
+	return &local
+}
+
+// applyBase merges the base config named by config.BaseConfig under the
+// local settings. A base config that cannot be fetched falls back to the
+// last copy fetched, and failing that is skipped with a warning, so an
+// unreachable server never stops the CLI from working.
+func applyBase(config *Config, dir string) {
+	baseState.inherited, baseState.values = nil, nil
+	if config.BaseConfig == "" {
+		return
+	}
+
+	data, err := LoadBase(dir, config.BaseConfig, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: base config unavailable: %v\n", err)
+		return
+	}
+
+	before := *config
+	inherited, err := mergeBase(config, data)
+	if err != nil {
+		*config = before
+		fmt.Fprintf(os.Stderr, "Warning: ignoring base config: %v\n", err)
+		return
+	}
+	merged := *config
+	baseState.inherited, baseState.values = inherited, &merged
+}
+
+// LoadBase returns the base config from source, fetching it when the copy
+// kept in dir is older than BaseConfigTTL or refresh is set. A fetch that
+// fails falls back to the copy kept, unless refresh asked for a fresh one.
+func LoadBase(dir, source string, refresh bool) ([]byte, error) {
+	parsed, err := ParseBaseSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath := filepath.Join(dir, baseCacheFile)
+	if parsed.Git {
+		cachePath = filepath.Join(dir, baseRepoDir, filepath.FromSlash(parsed.Path))
+	}
+
+	if !refresh {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < BaseConfigTTL {
+			return os.ReadFile(cachePath)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	var data []byte
+	if parsed.Git {
+		data, err = fetchBaseRepo(ctx, filepath.Join(dir, baseRepoDir), parsed)
+	} else {
+		data, err = fetchBaseURL(ctx, parsed.URL)
+		if err == nil {
+			err = state.WriteFileAtomic(cachePath, data, 0600)
+		}
+	}
+	if err == nil || refresh {
+		return data, err
+	}
+
+	// Fall back to the last copy, however old
+	if cached, cacheErr := os.ReadFile(cachePath); cacheErr == nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Using cached base config: %v\n", err)
+		}
+		return cached, nil
+	}
+	return nil, err
+}
+
+func fetchBaseURL(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := baseHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBaseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if len(data) > maxBaseSize {
+		return nil, fmt.Errorf("base config %s is larger than %d bytes", rawURL, maxBaseSize)
+	}
+	return data, nil
+}
+
+// fetchBaseRepo clones the repository on first use and pulls it after that.
+// A clone of another repository is replaced rather than pulled.
+func fetchBaseRepo(ctx context.Context, repoDir string, source BaseSource) ([]byte, error) {
+	var cmd *exec.Cmd
+	if repoOrigin(ctx, repoDir) == source.URL {
+		cmd = exec.CommandContext(ctx, "git", "-C", repoDir, "pull", "--ff-only", "--quiet")
+	} else {
+		os.RemoveAll(repoDir)
+		// The URL comes from the config file, so it must not be taken for
+		// an option
+		cmd = exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--quiet", "--", source.URL, repoDir)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git failed for %s: %v: %s", source.URL, err, strings.TrimSpace(string(output)))
+	}
+
+	path := filepath.Join(repoDir, filepath.FromSlash(source.Path))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("base config %s not found in %s", source.Path, source.URL)
+	}
+	// Mark the copy fresh, since a pull that changed nothing keeps its time
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return data, nil
+}
+
+// repoOrigin returns the URL the clone in repoDir was made from, or "" when
+// there is no clone
+func repoOrigin(ctx context.Context, repoDir string) string {
+	if _, err := os.Stat(filepath.Join(repoDir, ".git")); err != nil {
+		return ""
+	}
+	output, err := exec.CommandContext(ctx, "git", "-C", repoDir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// BaseSettings returns the settings the current configuration takes from
+// its base config, by YAML key
+func BaseSettings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	if baseState.values == nil {
+		return settings
+	}
+	value := reflect.ValueOf(baseState.values).Elem()
+	for i := range baseState.inherited {
+		key := strings.Split(value.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		settings[key] = value.Field(i).Interface()
+	}
+	return settings
+}
+
+// SetBase makes source the base config of the current configuration and
+// saves it, fetching it first so a bad URL is reported straight away. An
+// empty source removes the base config.
+func SetBase(source string) error {
+	config := Get()
+	dir := filepath.Dir(configFile)
+	if source != "" {
+		if _, err := LoadBase(dir, source, true); err != nil {
+			return err
+		}
+	}
+
+	config.BaseConfig = source
+	if err := Save(); err != nil {
+		return err
+	}
+	if source == "" {
+		os.Remove(filepath.Join(dir, baseCacheFile))
+		os.RemoveAll(filepath.Join(dir, baseRepoDir))
+	}
+	return nil
+}
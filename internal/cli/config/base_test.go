@@ -0,0 +1,136 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBaseSource(t *testing.T) {
+	source, err := ParseBaseSource("git+https://example.com/team.git#spotify/cli.yaml")
+	if err != nil {
+		t.Fatalf("ParseBaseSource() error = %v", err)
+	}
+	if !source.Git || source.URL != "https://example.com/team.git" || source.Path != "spotify/cli.yaml" {
+		t.Errorf("Unexpected git source: %+v", source)
+	}
+
+	source, err = ParseBaseSource("git+git@example.com:team.git")
+	if err != nil || source.Path != DefaultBaseConfigFile {
+		t.Errorf("Expected default file for git source, got %+v, %v", source, err)
+	}
+
+	invalid := []string{"http://example.com/base.yaml", "/etc/base.yaml", "git+", "git+https://example.com/team.git#../secret"}
+	for _, value := range invalid {
+		if _, err := ParseBaseSource(value); err == nil {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestBaseConfig_MergeAndSave(t *testing.T) {
+	defer Reset()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("client_id: shared-id\naccess_token: not-yours\ndefault_output: json\nmax_retries: 7\n"))
+	}))
+	defer server.Close()
+	baseHTTPClient = server.Client()
+	defer func() { baseHTTPClient = http.DefaultClient }()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	local := "schema_version: " + strconv.Itoa(SchemaVersion()) + "\nmax_retries: 2\naccess_token: mine\nbase_config: " + server.URL + "/base.yaml\n"
+	if err := os.WriteFile(path, []byte(local), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("SPOTIFY_CLIENT_ID")
+
+	if err := Init(path, false, ""); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	cfg := Get()
+	if cfg.ClientID != "shared-id" || cfg.DefaultOutput != "json" {
+		t.Errorf("Expected settings from the base config, got client_id %q, output %q", cfg.ClientID, cfg.DefaultOutput)
+	}
+	if cfg.MaxRetries != 2 {
+		t.Errorf("Expected local max_retries to win, got %d", cfg.MaxRetries)
+	}
+	if cfg.AccessToken != "mine" {
+		t.Errorf("Expected local token to be kept, got %q", cfg.AccessToken)
+	}
+	if settings := BaseSettings(); len(settings) != 2 {
+		t.Errorf("Expected 2 inherited settings, got %v", settings)
+	}
+
+	// A setting changed locally is saved; inherited ones are not
+	cfg.DefaultOutput = "yaml"
+	if err := Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "shared-id") {
+		t.Errorf("Expected base client_id not to be saved locally:\n%s", data)
+	}
+	if !strings.Contains(string(data), "default_output: yaml") {
+		t.Errorf("Expected changed default_output to be saved:\n%s", data)
+	}
+
+	// With the server gone, the cached copy is used, but not when a fresh
+	// one was asked for
+	server.Close()
+	Reset()
+	if _, err := LoadBase(dir, server.URL+"/base.yaml", true); err == nil {
+		t.Error("Expected an error refreshing the base config with the server down")
+	}
+	old := time.Now().Add(-2 * BaseConfigTTL)
+	if err := os.Chtimes(filepath.Join(dir, baseCacheFile), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBase(dir, server.URL+"/base.yaml", false); err != nil {
+		t.Errorf("Expected cached base config when the server is down, got %v", err)
+	}
+}
+
+func TestLoadBase_GitSourceChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := func(content string) string {
+		dir := t.TempDir()
+		for _, args := range [][]string{
+			{"init", "--quiet"},
+			{"add", DefaultBaseConfigFile},
+			{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "base"},
+		} {
+			if args[0] == "add" {
+				if err := os.WriteFile(filepath.Join(dir, DefaultBaseConfigFile), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if output, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v: %s", args, err, output)
+			}
+		}
+		return "file://" + dir
+	}
+	first, second := repo("default_output: json\n"), repo("default_output: yaml\n")
+
+	dir := t.TempDir()
+	if data, err := LoadBase(dir, "git+"+first, true); err != nil || string(data) != "default_output: json\n" {
+		t.Fatalf("Expected the first repository's base config, got %q, %v", data, err)
+	}
+	if data, err := LoadBase(dir, "git+"+second, true); err != nil || string(data) != "default_output: yaml\n" {
+		t.Errorf("Expected a new source to be cloned afresh, got %q, %v", data, err)
+	}
+}
@@ -12,6 +12,9 @@ import (
 
 // Config represents the CLI configuration
 type Config struct {
+	// SchemaVersion is the config file format version, see migrations.go
+	SchemaVersion int `yaml:"schema_version" json:"schema_version"`
+
 	// Spotify API Configuration
 	ClientID     string `yaml:"client_id" json:"client_id"`
 	ClientSecret string `yaml:"client_secret" json:"client_secret"`
@@ -31,11 +34,21 @@ type Config struct {
 	// Cache Settings
 	CacheEnabled bool   `yaml:"cache_enabled" json:"cache_enabled"`
 	CacheTTL     string `yaml:"cache_ttl" json:"cache_ttl"`
+
+	// Retry Settings
+	MaxRetries   int    `yaml:"max_retries,omitempty" json:"max_retries,omitempty"`
+	MaxRetryWait string `yaml:"max_retry_wait,omitempty" json:"max_retry_wait,omitempty"`
+
+	// BaseConfig is a shared, read-only config merged under this one, see
+	// base.go
+	BaseConfig string `yaml:"base_config,omitempty" json:"base_config,omitempty"`
 }
 
 var (
 	current    *Config
 	configFile string
+	cacheDir   string
+	noCache    bool
 	verbose    bool
 	output     string
 )
@@ -46,6 +59,7 @@ func Default() *Config {
 	godotenv.Load()
 
 	config := &Config{
+		SchemaVersion: SchemaVersion(),
 		RedirectURI:   "http://127.0.0.1:4000",
 		DefaultOutput: "text",
 		Verbose:       false,
@@ -92,6 +106,22 @@ func Init(cfgFile string, verboseFlag bool, outputFlag string) error {
 	return nil
 }
 
+// SetCacheDir sets the directory used for cached API responses. An empty dir
+// or disabled=true turns response caching off for this invocation.
+func SetCacheDir(dir string, disabled bool) {
+	cacheDir = dir
+	noCache = disabled
+}
+
+// CacheDir returns the directory for cached API responses, or "" when caching
+// is disabled
+func CacheDir() string {
+	if noCache || !Get().CacheEnabled {
+		return ""
+	}
+	return cacheDir
+}
+
 // Get returns the current configuration
 func Get() *Config {
 	if current == nil {
@@ -112,7 +142,7 @@ func Save() error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(current)
+	data, err := yaml.Marshal(withoutBase(current))
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -158,8 +188,12 @@ func ClearTokens() {
 
 // IsAuthenticated returns true if the user is authenticated with a valid token
 func IsAuthenticated() bool {
-	config := Get()
+	return Get().HasValidToken()
+}
 
+// HasValidToken returns true if the config holds an access token that has
+// not expired
+func (config *Config) HasValidToken() bool {
 	// Check if access token exists
 	if config.AccessToken == "" {
 		return false
@@ -198,14 +232,10 @@ func load() (*Config, error) {
 		return config, nil
 	}
 
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	if err := yaml.Unmarshal(data, config); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	if err := loadFile(configFile, config); err != nil {
+		return nil, err
 	}
+	applyBase(config, filepath.Dir(configFile))
 
 	return config, nil
 }
@@ -250,4 +280,5 @@ func IsTokenExpiringSoon() bool {
 // Reset clears the current configuration (useful for testing)
 func Reset() {
 	current = nil
+	baseState.inherited, baseState.values = nil, nil
 }
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bambithedeer/spotify-api/internal/migrate"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// backupsDirName is where config files are copied before being migrated
+const backupsDirName = "backups"
+
+// configMigrations upgrades config files written by older releases. Append
+// new migrations to the end; never edit or reorder released ones.
+var configMigrations = migrate.NewSet("config",
+	migrate.Migration{
+		Version:     1,
+		Description: "add schema version",
+		Apply:       func(doc map[string]interface{}) error { return nil },
+	},
+)
+
+// SchemaVersion returns the config schema version written by this release
+func SchemaVersion() int {
+	return configMigrations.Latest()
+}
+
+// migrateConfig upgrades raw config YAML to the current schema, returning the
+// migrated YAML. data is returned unchanged when it is already current.
+func migrateConfig(data []byte) ([]byte, migrate.Result, error) {
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, migrate.Result{}, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+
+	result, err := configMigrations.Migrate(doc)
+	if err != nil || !result.Changed() {
+		return data, result, err
+	}
+
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, result, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	return migrated, result, nil
+}
+
+// loadFile reads the config file at path into config, migrating it to the
+// current schema first. The original file is backed up before it is rewritten.
+func loadFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	migrated, result, err := migrateConfig(data)
+	if err != nil {
+		return fmt.Errorf("cannot load %s: %w", path, err)
+	}
+
+	if result.Changed() {
+		backup, err := migrate.BackupFile(path, filepath.Join(filepath.Dir(path), backupsDirName), result.From)
+		if err != nil {
+			return err
+		}
+
+		if err := state.WriteFileAtomic(path, migrated, 0600); err != nil {
+			return fmt.Errorf("failed to write migrated config: %w", err)
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Migrated %s from schema v%d to v%d (backup: %s)\n", path, result.From, result.To, backup)
+		}
+	}
+
+	if err := yaml.Unmarshal(migrated, config); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/migrate"
+)
+
+func TestLoadProfile_MigratesUnversionedConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := ProfilePath(dir, DefaultProfile)
+	if err := os.WriteFile(path, []byte("client_id: abc\ndefault_output: json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProfile(dir, DefaultProfile)
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+	if cfg.ClientID != "abc" || cfg.SchemaVersion != SchemaVersion() {
+		t.Errorf("Unexpected config: client_id=%q schema_version=%d", cfg.ClientID, cfg.SchemaVersion)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "schema_version: 1") {
+		t.Errorf("Expected migrated file to be stamped with the schema version, got:\n%s", data)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "backups", "config.yaml.v0.*.bak"))
+	if len(backups) != 1 {
+		t.Errorf("Expected one backup of the original config, got %v", backups)
+	}
+}
+
+func TestLoadProfile_RejectsNewerConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := ProfilePath(dir, DefaultProfile)
+	if err := os.WriteFile(path, []byte("schema_version: 99\nclient_id: abc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadProfile(dir, DefaultProfile); !errors.Is(err, migrate.ErrTooNew) {
+		t.Fatalf("Expected ErrTooNew, got %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "schema_version: 99") {
+		t.Error("Expected newer config to be left untouched")
+	}
+}
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the profile used when none is selected. Its configuration
+// lives in the top level config.yaml so existing setups keep working.
+const DefaultProfile = "default"
+
+const (
+	profilesDirName   = "profiles"
+	activeProfileFile = "active_profile"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]{0,63}$`)
+
+// ValidateProfileName checks that a profile name is safe to use as a directory name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, numbers, '-' and '_' (max 64 characters)", name)
+	}
+	return nil
+}
+
+// ProfileDir returns the directory holding the files of a profile
+func ProfileDir(configDir, name string) string {
+	if name == "" || name == DefaultProfile {
+		return configDir
+	}
+	return filepath.Join(configDir, profilesDirName, name)
+}
+
+// ProfilePath returns the config file path for a profile
+func ProfilePath(configDir, name string) string {
+	return filepath.Join(ProfileDir(configDir, name), "config.yaml")
+}
+
+// ActiveProfile returns the profile selected with 'profile use', or the
+// default profile if none has been selected
+func ActiveProfile(configDir string) string {
+	data, err := os.ReadFile(filepath.Join(configDir, activeProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+
+	name := strings.TrimSpace(string(data))
+	if name == "" || ValidateProfileName(name) != nil {
+		return DefaultProfile
+	}
+
+	return name
+}
+
+// SetActiveProfile records the profile used by subsequent commands
+func SetActiveProfile(configDir, name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	if !ProfileExists(configDir, name) {
+		return fmt.Errorf("profile %q does not exist. Create it with 'spotify-cli profile create %s'", name, name)
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, activeProfileFile), []byte(name+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write active profile: %w", err)
+	}
+
+	return nil
+}
+
+// ProfileExists returns true if the profile has a config file. The default
+// profile always exists.
+func ProfileExists(configDir, name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+
+	_, err := os.Stat(ProfilePath(configDir, name))
+	return err == nil
+}
+
+// ListProfiles returns all profile names, sorted, with the default profile first
+func ListProfiles(configDir string) ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(configDir, profilesDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == DefaultProfile || ValidateProfileName(entry.Name()) != nil {
+			continue
+		}
+		if ProfileExists(configDir, entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return append(profiles, names...), nil
+}
+
+// CreateProfile creates a new profile. If base is not nil its API credentials
+// are copied into the new profile; tokens are never copied so each profile
+// authenticates against its own account.
+func CreateProfile(configDir, name string, base *Config) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	if name == DefaultProfile {
+		return fmt.Errorf("the %q profile always exists", DefaultProfile)
+	}
+
+	if ProfileExists(configDir, name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	profileConfig := Default()
+	if base != nil {
+		profileConfig.ClientID = base.ClientID
+		profileConfig.ClientSecret = base.ClientSecret
+		profileConfig.RedirectURI = base.RedirectURI
+	}
+
+	path := ProfilePath(configDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(profileConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write profile config: %w", err)
+	}
+
+	return nil
+}
+
+// LoadProfile loads a profile's configuration without making it the current
+// configuration
+func LoadProfile(configDir, name string) (*Config, error) {
+	if !ProfileExists(configDir, name) {
+		return nil, fmt.Errorf("profile %q does not exist", name)
+	}
+
+	config := Default()
+
+	path := ProfilePath(configDir, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return config, nil
+	}
+
+	if err := loadFile(path, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// SaveProfile writes a profile's configuration, such as after refreshing the
+// tokens of a profile loaded with LoadProfile
+func SaveProfile(configDir, name string, config *Config) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	path := ProfilePath(configDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := state.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write profile config: %w", err)
+	}
+
+	return nil
+}
+
+// WithoutSecrets returns a copy of the config with the client secret and
+// tokens removed, suitable for sharing or moving between machines
+func (c *Config) WithoutSecrets() *Config {
+	stripped := *c
+	stripped.ClientSecret = ""
+	stripped.AccessToken = ""
+	stripped.RefreshToken = ""
+	stripped.TokenType = ""
+	stripped.ExpiresAt = ""
+	return &stripped
+}
+
+// ImportProfile replaces a profile's config with data, migrating it to the
+// current schema. Secrets missing from data are kept from the existing config,
+// so importing an export made without secrets does not log the profile out.
+func ImportProfile(configDir, name string, data []byte) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+
+	path := ProfilePath(configDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return fmt.Errorf("cannot import config: %w", err)
+	}
+
+	imported := Default()
+	if err := yaml.Unmarshal(migrated, imported); err != nil {
+		return fmt.Errorf("failed to parse imported config: %w", err)
+	}
+
+	if existing, err := LoadProfile(configDir, name); err == nil {
+		if imported.ClientSecret == "" && imported.ClientID == existing.ClientID {
+			imported.ClientSecret = existing.ClientSecret
+		}
+		if imported.RefreshToken == "" && imported.ClientID == existing.ClientID {
+			imported.AccessToken = existing.AccessToken
+			imported.RefreshToken = existing.RefreshToken
+			imported.TokenType = existing.TokenType
+			imported.ExpiresAt = existing.ExpiresAt
+		}
+	}
+
+	out, err := yaml.Marshal(imported)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := state.WriteFileAtomic(path, out, 0600); err != nil {
+		return fmt.Errorf("failed to write profile config: %w", err)
+	}
+
+	return nil
+}
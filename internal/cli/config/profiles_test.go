@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	valid := []string{"default", "work", "personal-2", "my_profile"}
+	for _, name := range valid {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", name, err)
+		}
+	}
+
+	invalid := []string{"", "-work", "../etc", "a/b", "with space"}
+	for _, name := range invalid {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestProfilePath(t *testing.T) {
+	dir := "/tmp/spotify-cli"
+
+	if path := ProfilePath(dir, DefaultProfile); path != filepath.Join(dir, "config.yaml") {
+		t.Errorf("Expected default profile at top level config, got %s", path)
+	}
+
+	if path := ProfilePath(dir, "work"); path != filepath.Join(dir, "profiles", "work", "config.yaml") {
+		t.Errorf("Expected work profile under profiles directory, got %s", path)
+	}
+}
+
+func TestCreateAndListProfiles(t *testing.T) {
+	dir := t.TempDir()
+
+	profiles, err := ListProfiles(dir)
+	if err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0] != DefaultProfile {
+		t.Fatalf("Expected only the default profile, got %v", profiles)
+	}
+
+	base := &Config{ClientID: "client", ClientSecret: "secret", RedirectURI: "http://127.0.0.1:5000", AccessToken: "token", RefreshToken: "refresh"}
+	if err := CreateProfile(dir, "work", base); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+	if err := CreateProfile(dir, "alpha", nil); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	if err := CreateProfile(dir, "work", nil); err == nil {
+		t.Error("Expected error creating a duplicate profile")
+	}
+	if err := CreateProfile(dir, DefaultProfile, nil); err == nil {
+		t.Error("Expected error creating the default profile")
+	}
+
+	profiles, err = ListProfiles(dir)
+	if err != nil {
+		t.Fatalf("Failed to list profiles: %v", err)
+	}
+	expected := []string{DefaultProfile, "alpha", "work"}
+	if len(profiles) != len(expected) {
+		t.Fatalf("Expected profiles %v, got %v", expected, profiles)
+	}
+	for i := range expected {
+		if profiles[i] != expected[i] {
+			t.Errorf("Expected profile %s at position %d, got %s", expected[i], i, profiles[i])
+		}
+	}
+
+	work, err := LoadProfile(dir, "work")
+	if err != nil {
+		t.Fatalf("Failed to load profile: %v", err)
+	}
+	if work.ClientID != "client" || work.RedirectURI != "http://127.0.0.1:5000" {
+		t.Errorf("Expected credentials to be copied, got client ID %q and redirect URI %q", work.ClientID, work.RedirectURI)
+	}
+	if work.AccessToken != "" || work.RefreshToken != "" {
+		t.Error("Expected tokens not to be copied into a new profile")
+	}
+}
+
+func TestActiveProfile(t *testing.T) {
+	dir := t.TempDir()
+
+	if active := ActiveProfile(dir); active != DefaultProfile {
+		t.Errorf("Expected default active profile, got %s", active)
+	}
+
+	if err := SetActiveProfile(dir, "missing"); err == nil {
+		t.Error("Expected error activating a profile that doesn't exist")
+	}
+
+	if err := CreateProfile(dir, "work", nil); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	if err := SetActiveProfile(dir, "work"); err != nil {
+		t.Fatalf("Failed to set active profile: %v", err)
+	}
+
+	if active := ActiveProfile(dir); active != "work" {
+		t.Errorf("Expected active profile 'work', got %s", active)
+	}
+}
+
+func TestImportProfile_KeepsExistingSecrets(t *testing.T) {
+	dir := t.TempDir()
+	existing := []byte("client_id: abc\nclient_secret: secret\nrefresh_token: refresh\ndefault_output: text\n")
+	if err := os.WriteFile(ProfilePath(dir, DefaultProfile), existing, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := &Config{ClientID: "abc", ClientSecret: "secret", RefreshToken: "refresh", DefaultOutput: "json"}
+	data, err := yaml.Marshal(exported.WithoutSecrets())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ImportProfile(dir, DefaultProfile, data); err != nil {
+		t.Fatalf("ImportProfile failed: %v", err)
+	}
+
+	cfg, err := LoadProfile(dir, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultOutput != "json" {
+		t.Errorf("Expected imported settings, got default_output=%q", cfg.DefaultOutput)
+	}
+	if cfg.ClientSecret != "secret" || cfg.RefreshToken != "refresh" {
+		t.Errorf("Expected existing secrets to be kept, got %+v", cfg)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	dir := t.TempDir()
+	if err := CreateProfile(dir, "brand", &Config{ClientID: "abc", ClientSecret: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProfile(dir, "brand")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.AccessToken = "access"
+	cfg.RefreshToken = "refresh"
+	cfg.ExpiresAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+	if err := SaveProfile(dir, "brand", cfg); err != nil {
+		t.Fatalf("SaveProfile failed: %v", err)
+	}
+
+	reloaded, err := LoadProfile(dir, "brand")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.RefreshToken != "refresh" || !reloaded.HasValidToken() {
+		t.Errorf("Expected the tokens to be saved, got %+v", reloaded)
+	}
+
+	info, err := os.Stat(ProfilePath(dir, "brand"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the profile config to be private, got %v", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	daemonManager     string
+	daemonForce       bool
+	daemonPrint       bool
+	daemonWatchdogSec int
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run spotify-cli commands as background services",
+	Long: `Manage long-running spotify-cli commands as background services.
+
+Every long-running command accepts --health-addr to expose /healthz and
+/readyz endpoints, and reports readiness to systemd via sd_notify when it is
+started by a unit installed with 'daemon install'.
+
+Pass --once to run a single pass and exit, for scheduling with cron. The exit
+code then describes the outcome, using the codes listed in 'spotify-cli
+--help'; 75 means the next scheduled run may well succeed.
+
+--summary-json writes a machine-readable summary of the runs to a file, or to
+stdout when given '-'. For a daemon it counts every run and what each one
+reported.`,
+	Example: `  # Install a systemd user unit (or launchd agent on macOS) for a daemon
+  spotify-cli daemon install history -- history record --health-addr 127.0.0.1:8089
+
+  # Preview the unit without writing it
+  spotify-cli daemon install history --print -- history record
+
+  # Run a single pass from cron and keep the summary
+  */30 * * * * spotify-cli history record --once --summary-json /tmp/history.json`,
+}
+
+var daemonInstallCmd = &cobra.Command{
+	Use:   "install [name] -- [command...]",
+	Short: "Install a user service for a daemon command",
+	Long: `Write a systemd user unit (Linux) or launchd agent (macOS) that runs the given
+spotify-cli command in the background and restarts it on failure.
+
+The service runs the current spotify-cli executable with the arguments after
+'--'. When a non-default profile is selected it is passed to the service
+through SPOTIFY_CLI_PROFILE.`,
+	Args: cobra.MinimumNArgs(2),
+	Example: `  spotify-cli daemon install history -- history record
+  spotify-cli --profile work daemon install lidarr-sync -- lidarr sync --watch --interval 6h
+  spotify-cli daemon install history --manager launchd --print -- history record`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 1 {
+			return fmt.Errorf("usage: daemon install <name> -- <command...>")
+		}
+		return runDaemonInstall(args[0], args[1:])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.AddCommand(daemonInstallCmd)
+
+	daemonInstallCmd.Flags().StringVar(&daemonManager, "manager", "", "Service manager (systemd, launchd); default depends on the OS")
+	daemonInstallCmd.Flags().BoolVar(&daemonForce, "force", false, "Overwrite an existing service file")
+	daemonInstallCmd.Flags().BoolVar(&daemonPrint, "print", false, "Print the service file instead of writing it")
+	daemonInstallCmd.Flags().IntVar(&daemonWatchdogSec, "watchdog-sec", 0, "Enable the systemd watchdog with this timeout in seconds")
+}
+
+// addDaemonFlags registers the flags shared by every long-running command.
+// Each command keeps its own values, so every one has its own default
+// interval; runDaemonLoop reads them from the command being run.
+func addDaemonFlags(cmd *cobra.Command, defaultInterval time.Duration) {
+	cmd.Flags().String("health-addr", "", "Serve /healthz and /readyz on this address (e.g. 127.0.0.1:8089)")
+	cmd.Flags().Duration("interval", defaultInterval, "Time between runs")
+	cmd.Flags().Bool("once", false, "Run a single pass and exit with a status code (for cron)")
+}
+
+// daemonOptions reads the daemon flags of cmd
+func daemonOptions(cmd *cobra.Command, name string) daemon.Options {
+	flags := cmd.Flags()
+	interval, _ := flags.GetDuration("interval")
+	healthAddr, _ := flags.GetString("health-addr")
+	once, _ := flags.GetBool("once")
+	return daemon.Options{Name: name, Interval: interval, HealthAddr: healthAddr, Once: once}
+}
+
+// runDaemonLoop runs task repeatedly until interrupted, wiring in the health
+// endpoints and service manager notifications. With --once the task runs a
+// single time and a failure is returned as an ExitError.
+func runDaemonLoop(cmd *cobra.Command, name string, task daemon.Task) error {
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts := daemonOptions(cmd, name)
+	opts.OnError = func(err error) {
+		if !opts.Once {
+			utils.PrintWarning("%s: %v", name, err)
+		}
+	}
+	opts.OnHealthServe = func(addr string) {
+		utils.PrintVerbose("Health endpoints listening on http://%s", addr)
+	}
+	summary, err := daemon.Run(ctx, opts, task)
+
+	// Written with the exit code once the command returns
+	daemonSummary = summary
+
+	if err != nil && opts.Once {
+		return &ExitError{Code: daemon.ExitCode(err), Err: err}
+	}
+
+	return err
+}
+
+func runDaemonInstall(name string, args []string) error {
+	manager := daemonManager
+	if manager == "" {
+		var err error
+		manager, err = daemon.ManagerForOS(runtime.GOOS)
+		if err != nil {
+			return err
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate spotify-cli executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	spec := daemon.ServiceSpec{
+		Name:        name,
+		Executable:  executable,
+		Args:        args,
+		WatchdogSec: daemonWatchdogSec,
+		LogDir:      filepath.Join(configDir, "logs"),
+	}
+
+	if profileName != "" && profileName != config.DefaultProfile {
+		spec.Environment = map[string]string{"SPOTIFY_CLI_PROFILE": profileName}
+	}
+
+	if daemonPrint {
+		contents, err := daemon.Render(manager, spec)
+		if err != nil {
+			return err
+		}
+		fmt.Print(contents)
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	path, err := daemon.Install(manager, home, spec, daemonForce)
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Installed %s service: %s", manager, path)
+
+	serviceName := daemon.ServiceName(manager, name)
+	if manager == daemon.ManagerLaunchd {
+		os.MkdirAll(spec.LogDir, 0755)
+		fmt.Printf("Start it with: launchctl load -w %s\n", path)
+	} else {
+		fmt.Println("Start it with:")
+		fmt.Println("  systemctl --user daemon-reload")
+		fmt.Printf("  systemctl --user enable --now %s\n", serviceName)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDaemonFlagsPerCommand(t *testing.T) {
+	often := &cobra.Command{Use: "often"}
+	rarely := &cobra.Command{Use: "rarely"}
+	addDaemonFlags(often, 15*time.Second)
+	addDaemonFlags(rarely, 6*time.Hour)
+
+	if err := rarely.ParseFlags([]string{"--once", "--health-addr", "127.0.0.1:8089"}); err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+
+	opts := daemonOptions(often, "often")
+	if opts.Interval != 15*time.Second || opts.Once || opts.HealthAddr != "" {
+		t.Errorf("Expected often to keep its own defaults, got %+v", opts)
+	}
+	opts = daemonOptions(rarely, "rarely")
+	if opts.Interval != 6*time.Hour || !opts.Once || opts.HealthAddr != "127.0.0.1:8089" {
+		t.Errorf("Expected rarely's defaults and flags, got %+v", opts)
+	}
+
+	// The registered commands keep the intervals their help shows
+	for cmd, want := range map[*cobra.Command]time.Duration{
+		historyRecordCmd: 30 * time.Minute,
+		digestCmd:        7 * 24 * time.Hour,
+		jukeboxCmd:       15 * time.Second,
+		lidarrSyncCmd:    6 * time.Hour,
+	} {
+		if got := daemonOptions(cmd, cmd.Name()).Interval; got != want {
+			t.Errorf("%s: expected an interval of %s, got %s", cmd.CommandPath(), want, got)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/desktop"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	desktopInterval    time.Duration
+	desktopNoMediaKeys bool
+	desktopNoNotify    bool
+)
+
+// desktopCmd represents the desktop command
+var desktopCmd = &cobra.Command{
+	Use:   "desktop",
+	Short: "Control playback with media keys and show track notifications",
+	Long: `Run in the background, controlling playback with the keyboard's media keys
+and showing a notification whenever a new track starts.
+
+Play/pause, next, previous and stop control whichever device is playing
+through the Web API, so they work for playback on a phone or speaker too.
+Track changes are noticed by polling, every --interval. No notifications are
+shown during quiet hours (see 'spotify-cli quiet').
+
+Windows only for now. Media keys are registered as global hotkeys, which
+takes them from other applications, including the Spotify desktop app, while
+this runs; use --no-media-keys to keep only the notifications. Linux (MPRIS)
+support is planned.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli desktop
+  spotify-cli desktop --no-media-keys --interval 10s
+  spotify-cli daemon install desktop -- desktop`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDesktop()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(desktopCmd)
+
+	desktopCmd.Flags().DurationVar(&desktopInterval, "interval", 5*time.Second, "How often to check for track changes")
+	desktopCmd.Flags().BoolVar(&desktopNoMediaKeys, "no-media-keys", false, "Do not handle media keys")
+	desktopCmd.Flags().BoolVar(&desktopNoNotify, "no-notify", false, "Do not show track notifications")
+}
+
+func runDesktop() error {
+	if !desktop.Supported {
+		return fmt.Errorf("%w. Only Windows is supported so far", desktop.ErrUnsupported)
+	}
+	if desktopNoMediaKeys && desktopNoNotify {
+		return fmt.Errorf("nothing to do with both --no-media-keys and --no-notify")
+	}
+	if desktopInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	hours, err := loadQuietHours()
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("playback control")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 2)
+	running := 0
+
+	if !desktopNoMediaKeys {
+		running++
+		go func() {
+			errs <- desktop.ListenMediaKeys(ctx, func(key desktop.Key) {
+				if err := handleMediaKey(ctx, spotifyClient, key); err != nil {
+					utils.PrintWarning("Media key %s: %v", key, err)
+				}
+			})
+		}()
+	}
+
+	if !desktopNoNotify {
+		running++
+		go func() {
+			errs <- desktop.WatchTracks(ctx, func(ctx context.Context) (*models.PlaylistItem, error) {
+				return currentItem(ctx, spotifyClient)
+			}, desktopInterval, func(item *models.PlaylistItem) {
+				// A track that started in the night is old news by morning,
+				// so its notification is dropped rather than held
+				if hours.Active(time.Now()) {
+					return
+				}
+				if err := desktop.Notify(ctx, desktop.TrackNotification(item)); err != nil {
+					utils.PrintWarning("%v", err)
+				}
+			}, func(err error) {
+				utils.PrintVerbose("Failed to check the current track: %v", err)
+			})
+		}()
+	}
+
+	utils.PrintSuccess("Desktop integration running, press Ctrl+C to stop")
+
+	// Either part failing stops both
+	for ; running > 0; running-- {
+		if err := <-errs; err != nil && ctx.Err() == nil {
+			stop()
+			return err
+		}
+	}
+	return nil
+}
+
+func handleMediaKey(ctx context.Context, spotifyClient *client.SpotifyClient, key desktop.Key) error {
+	player := spotifyClient.Player
+
+	switch key {
+	case desktop.KeyNext:
+		return player.Next(ctx, "")
+	case desktop.KeyPrevious:
+		return player.Previous(ctx, "")
+	case desktop.KeyStop:
+		return player.Pause(ctx, "")
+	default:
+		state, err := player.GetPlaybackState(ctx, "")
+		if err != nil {
+			return err
+		}
+		if state != nil && state.IsPlaying {
+			return player.Pause(ctx, "")
+		}
+		return player.Play(ctx, nil)
+	}
+}
+
+// currentItem returns the track or episode loaded in the player, paused or
+// not, so resuming does not count as a new track. It is nil when nothing is.
+func currentItem(ctx context.Context, spotifyClient *client.SpotifyClient) (*models.PlaylistItem, error) {
+	playing, err := spotifyClient.Player.GetCurrentlyPlaying(ctx, &spotify.CurrentlyPlayingOptions{AdditionalTypes: []string{"track", "episode"}})
+	if err != nil {
+		return nil, err
+	}
+	if playing == nil || playing.Item == nil {
+		return nil, nil
+	}
+
+	return decodePlayingItem(playing.Item)
+}
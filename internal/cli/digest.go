@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/digest"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestDays     int
+	digestTop      int
+	digestFormat   string
+	digestOutput   string
+	digestSMTPAddr string
+	digestSMTPUser string
+	digestFrom     string
+	digestTo       []string
+	digestWebhook  string
+)
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Send a summary of your recent listening",
+	Long: `Build a digest of the last --days: tracks you saved, your most played tracks
+and new releases from artists you follow.
+
+The digest is printed as Markdown, HTML or JSON, or written to --output.
+With --to it is emailed through the --smtp-addr server as both HTML and plain
+text; the SMTP password is read from SPOTIFY_CLI_SMTP_PASSWORD. With
+--webhook it is posted as JSON whose "text" field holds the Markdown, which
+Slack and Mattermost incoming webhooks display as is.
+
+Most played counts come from Spotify's recently played list, which only
+covers the last 50 plays.
+
+Run it weekly from cron with --once, or leave it running with the default
+weekly --interval. A digest due to be emailed or posted during quiet hours
+(see 'spotify-cli quiet') is held until they end.`,
+	Example: `  spotify-cli digest --days 7
+  spotify-cli digest --format html --output digest.html
+  spotify-cli digest --webhook https://hooks.slack.com/services/... --once
+  0 9 * * 1 SPOTIFY_CLI_SMTP_PASSWORD=secret spotify-cli digest --once --smtp-addr smtp.example.com:587 --smtp-user me@example.com --to me@example.com`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDigest(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().IntVar(&digestDays, "days", 7, "Number of days the digest covers")
+	digestCmd.Flags().IntVar(&digestTop, "top", 10, "Number of most played tracks to list")
+	digestCmd.Flags().StringVarP(&digestFormat, "format", "f", "markdown", "Output format (markdown, html, json)")
+	digestCmd.Flags().StringVarP(&digestOutput, "output", "O", "", "Write the digest to a file instead of stdout")
+	digestCmd.Flags().StringVar(&digestSMTPAddr, "smtp-addr", "", "SMTP server as host:port")
+	digestCmd.Flags().StringVar(&digestSMTPUser, "smtp-user", "", "SMTP username, if the server needs a login")
+	digestCmd.Flags().StringVar(&digestFrom, "from", "", "Sender address (default is the SMTP username)")
+	digestCmd.Flags().StringSliceVar(&digestTo, "to", nil, "Email the digest to these addresses")
+	digestCmd.Flags().StringVar(&digestWebhook, "webhook", "", "Post the digest to this webhook URL")
+	addDaemonFlags(digestCmd, 7*24*time.Hour)
+}
+
+func runDigest(cmd *cobra.Command) error {
+	if digestDays < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+	if digestTop < 0 {
+		return fmt.Errorf("--top cannot be negative")
+	}
+	switch digestFormat {
+	case "markdown", "html", "json":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: markdown, html, json", digestFormat)
+	}
+
+	var smtpConfig *digest.SMTPConfig
+	if len(digestTo) > 0 {
+		if digestSMTPAddr == "" {
+			return fmt.Errorf("--smtp-addr is required with --to")
+		}
+		from := digestFrom
+		if from == "" {
+			from = digestSMTPUser
+		}
+		if from == "" {
+			return fmt.Errorf("--from is required when there is no --smtp-user")
+		}
+		smtpConfig = &digest.SMTPConfig{
+			Addr:     digestSMTPAddr,
+			Username: digestSMTPUser,
+			Password: os.Getenv("SPOTIFY_CLI_SMTP_PASSWORD"),
+			From:     from,
+			To:       digestTo,
+		}
+	}
+
+	hours, err := loadQuietHours()
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your library and listening history")
+	if err != nil {
+		return err
+	}
+
+	return runDaemonLoop(cmd, "digest", func(ctx context.Context, report *daemon.Report) error {
+		if now := time.Now(); (smtpConfig != nil || digestWebhook != "") && hours.Active(now) {
+			utils.PrintWarning("Quiet hours until %s: holding the digest until then", hours.Until(now).Format("Mon 15:04"))
+			if err := hours.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		d, err := buildDigest(ctx, spotifyClient, time.Now())
+		if err != nil {
+			return err
+		}
+		report.Add("saves", len(d.NewSaves))
+		report.Add("plays", len(d.TopPlays))
+		report.Add("releases", len(d.NewReleases))
+
+		delivered := false
+		if smtpConfig != nil {
+			if err := digest.SendMail(*smtpConfig, d); err != nil {
+				return err
+			}
+			utils.PrintSuccess("Emailed digest to %s", strings.Join(smtpConfig.To, ", "))
+			delivered = true
+		}
+		if digestWebhook != "" {
+			if err := digest.PostWebhook(ctx, digestWebhook, d); err != nil {
+				return err
+			}
+			utils.PrintSuccess("Posted digest to webhook")
+			delivered = true
+		}
+
+		if delivered && digestOutput == "" {
+			return nil
+		}
+		return writeDigest(d)
+	})
+}
+
+// errDigestSavesDone stops paging saved tracks once they predate the digest
+var errDigestSavesDone = errors.New("digest saves done")
+
+// buildDigest gathers the activity between now minus --days and now
+func buildDigest(ctx context.Context, spotifyClient *client.SpotifyClient, now time.Time) (*digest.Digest, error) {
+	since := now.AddDate(0, 0, -digestDays)
+	d := &digest.Digest{Since: since, Until: now}
+
+	// Saved tracks come newest first, so stop at the first one before since
+	var saved []models.SavedTrack
+	err := spotifyClient.Library.SavedTracksPager(nil).Each(ctx, func(item models.SavedTrack) error {
+		if added, err := time.Parse(time.RFC3339, item.AddedAt); err == nil && added.Before(since) {
+			return errDigestSavesDone
+		}
+		saved = append(saved, item)
+		return nil
+	})
+	if err != nil && err != errDigestSavesDone {
+		return nil, err
+	}
+	d.NewSaves = digest.SavedSince(saved, since)
+
+	history, err := spotifyClient.Player.GetRecentlyPlayed(ctx, &spotify.RecentlyPlayedOptions{Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+	d.TopPlays = digest.TopPlays(history.Items, since, digestTop)
+
+	artists, err := spotifyClient.Users.FollowedArtistsPager(nil).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var albums []models.SimpleAlbum
+	for _, artist := range artists {
+		// Albums come newest first, so the first page covers any recent release
+		page, _, err := spotifyClient.Artists.GetArtistAlbums(ctx, artist.ID, &spotify.ArtistAlbumsOptions{
+			IncludeGroups: []string{"album", "single"},
+			Limit:         10,
+		})
+		if err != nil {
+			utils.PrintVerbose("Skipping releases from %s: %v", artist.Name, err)
+			continue
+		}
+		for _, album := range page.Items {
+			albums = append(albums, simpleAlbum(album))
+		}
+	}
+	d.NewReleases = digest.ReleasedSince(albums, since)
+
+	return d, nil
+}
+
+func simpleAlbum(album models.Album) models.SimpleAlbum {
+	return models.SimpleAlbum{
+		AlbumType:            album.AlbumType,
+		Artists:              album.Artists,
+		AvailableMarkets:     album.AvailableMarkets,
+		ExternalURLs:         album.ExternalURLs,
+		Href:                 album.Href,
+		ID:                   album.ID,
+		Images:               album.Images,
+		Name:                 album.Name,
+		ReleaseDatePrecision: album.ReleaseDatePrecision,
+		Restrictions:         album.Restrictions,
+		Type:                 album.Type,
+		URI:                  album.URI,
+		TotalTracks:          album.TotalTracks,
+	}
+}
+
+// writeDigest prints the digest in --format, or writes it to --output
+func writeDigest(d *digest.Digest) error {
+	var contents string
+	switch digestFormat {
+	case "html":
+		html, err := d.HTML()
+		if err != nil {
+			return err
+		}
+		contents = html
+	case "json":
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode digest: %w", err)
+		}
+		contents = string(data) + "\n"
+	default:
+		contents = d.Markdown()
+	}
+
+	if digestOutput == "" {
+		fmt.Print(contents)
+		return nil
+	}
+	if err := os.WriteFile(digestOutput, []byte(contents), 0644); err != nil {
+		return fmt.Errorf("failed to write digest: %w", err)
+	}
+	utils.PrintSuccess("Wrote digest to %s", digestOutput)
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var episodeMarket string
+
+// episodeCmd represents the episode command
+var episodeCmd = &cobra.Command{
+	Use:   "episode",
+	Short: "Look up podcast episodes",
+	Long:  `Look up podcast episodes by ID. Use 'show episodes' to list the episodes of a show.`,
+	Example: `  spotify-cli episode get 512ojhOuo1ktJprKbVcKyQ
+  spotify-cli episode get 512ojhOuo1ktJprKbVcKyQ 4rOoJ6Egrf8K2IrywzwOMk`,
+}
+
+var episodeGetCmd = &cobra.Command{
+	Use:   "get <episode-id> [episode-id...]",
+	Short: "Get episode details",
+	Long:  `Get details for one episode, or a table of up to 50 episodes.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEpisodeGet(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(episodeCmd)
+	episodeCmd.AddCommand(episodeGetCmd)
+
+	episodeGetCmd.Flags().StringVarP(&episodeMarket, "market", "m", "", "Market/country code (e.g., US, GB)")
+}
+
+func runEpisodeGet(ids []string) error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' or 'spotify-cli auth client-credentials' first")
+	}
+
+	ctx := GetCommandContext()
+	cfg := config.Get()
+	structured := cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml"
+
+	if len(ids) > 1 {
+		episodes, err := spotifyClient.Episodes.GetEpisodes(ctx, ids, episodeMarket)
+		if err != nil {
+			return fmt.Errorf("failed to get episodes: %w", err)
+		}
+		if structured {
+			return utils.Output(episodes)
+		}
+		printEpisodesTable(episodes)
+		return nil
+	}
+
+	episode, err := spotifyClient.Episodes.GetEpisode(ctx, ids[0], episodeMarket)
+	if err != nil {
+		return fmt.Errorf("failed to get episode: %w", err)
+	}
+	if structured {
+		return utils.Output(episode)
+	}
+
+	printEpisodeDetails(episode)
+	return nil
+}
+
+func printEpisodeDetails(episode *models.Episode) {
+	fmt.Printf("%s\n", episode.Name)
+	fmt.Printf("   ID: %s\n", episode.ID)
+	if episode.Show != nil {
+		fmt.Printf("   from %s", episode.Show.Name)
+		if episode.Show.Publisher != "" {
+			fmt.Printf(" by %s", episode.Show.Publisher)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("   released %s, %s\n", episode.ReleaseDate, utils.FormatDuration(episode.DurationMs))
+	if progress := episodeProgress(*episode); progress != "" {
+		fmt.Printf("   %s\n", progress)
+	}
+	printNotes(loadNotes(), episode.URI, "   ")
+	if episode.Description != "" {
+		fmt.Printf("\n%s\n", episode.Description)
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/estimate"
+	"github.com/spf13/cobra"
+)
+
+// addEstimateFlags adds the cost estimate flags to a command that can make
+// a large number of API requests
+func addEstimateFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("max-requests", 0, "Abort if the operation is estimated to need more API requests than this (0 = no limit)")
+	cmd.Flags().Bool("estimate", false, "Print the estimated number of requests and duration, then exit")
+}
+
+// reviewEstimate prints the estimated cost of an operation and enforces
+// --max-requests. It reports whether the command should stop because only an
+// estimate was asked for.
+func reviewEstimate(cmd *cobra.Command, e *estimate.Estimate) (bool, error) {
+	printEstimate(e)
+
+	maxRequests, _ := cmd.Flags().GetInt("max-requests")
+	if err := e.Check(maxRequests); err != nil {
+		return true, fmt.Errorf("%w; raise --max-requests or use --limit to do less", err)
+	}
+
+	onlyEstimate, _ := cmd.Flags().GetBool("estimate")
+	return onlyEstimate, nil
+}
+
+func printEstimate(e *estimate.Estimate) {
+	prefix := ""
+	if e.Approximate() {
+		prefix = "~"
+	}
+
+	fmt.Printf("Estimated cost: %s%d request%s, about %s\n", prefix, e.Requests(), pluralize(e.Requests()), formatEstimateDuration(e.Duration()))
+	for _, step := range e.Steps {
+		requests := fmt.Sprintf("%d", step.Requests)
+		if step.Approximate {
+			requests = "~" + requests
+		}
+		fmt.Printf("  %-12s %-40s %6s\n", step.Service, truncateString(step.Description, 40), requests)
+	}
+	fmt.Println(strings.Repeat("-", 62))
+}
+
+// formatEstimateDuration rounds a duration to the precision an estimate has
+func formatEstimateDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return "1s"
+	case d < time.Minute:
+		d = d.Round(time.Second)
+	case d < time.Hour:
+		d = d.Round(10 * time.Second)
+	default:
+		d = d.Round(time.Minute)
+	}
+
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	return s
+}
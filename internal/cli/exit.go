@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+// ExitError carries a specific process exit code for a command failure
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute.
+// Errors without a code of their own get the one for their failure class,
+// the same codes daemons exit with under --once, so a missing playlist exits
+// 1 while a rate limit or an outage exits 75.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return daemon.ExitCode(err)
+}
+
+// markUsageErrors makes bad flags and arguments to cmd or any command under
+// it exit with daemon.ExitUsage. Cobra reports them as plain errors, which
+// would otherwise be indistinguishable from a failed run.
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &ExitError{Code: daemon.ExitUsage, Err: err}
+	})
+
+	var mark func(*cobra.Command)
+	mark = func(c *cobra.Command) {
+		if args := c.Args; args != nil {
+			c.Args = func(c *cobra.Command, a []string) error {
+				if err := args(c, a); err != nil {
+					return &ExitError{Code: daemon.ExitUsage, Err: err}
+				}
+				return nil
+			}
+		}
+		for _, sub := range c.Commands() {
+			mark(sub)
+		}
+	}
+	mark(cmd)
+}
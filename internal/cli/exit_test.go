@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
+	"github.com/bambithedeer/spotify-api/internal/client"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, daemon.ExitOK},
+		{errors.New("boom"), daemon.ExitFailure},
+		{&ExitError{Code: 3, Err: errors.New("custom")}, 3},
+		{apierrors.NewConfigError("missing client id"), daemon.ExitConfig},
+		{apierrors.WrapAPIError(apierrors.NewStatusError(502, "HTTP 502: Bad gateway"), "failed to get playlist"), daemon.ExitTempFail},
+		{fmt.Errorf("failed to get playlist: %w", apierrors.NewStatusError(404, "HTTP 404: Not found")), daemon.ExitFailure},
+		{&ExitError{Code: daemon.ExitUsage, Err: apierrors.NewStatusError(503, "HTTP 503: Unavailable")}, daemon.ExitUsage},
+	}
+
+	for _, tt := range tests {
+		if code := ExitCode(tt.err); code != tt.expected {
+			t.Errorf("ExitCode(%v) = %d, expected %d", tt.err, code, tt.expected)
+		}
+	}
+}
+
+// TestExitCode_APIStatus runs 'playlist get' the way the command does against
+// a server answering with each status
+func TestExitCode_APIStatus(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected int
+	}{
+		{http.StatusNotFound, daemon.ExitFailure},
+		{http.StatusBadRequest, daemon.ExitUsage},
+		{http.StatusTooManyRequests, daemon.ExitTempFail},
+		{http.StatusServiceUnavailable, daemon.ExitTempFail},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"error": {"status": 0, "message": "Resource not found"}}`))
+		}))
+
+		apiClient := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+		apiClient.SetBaseURL(server.URL)
+		apiClient.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+		apiClient.SetRetryConfig(&ratelimit.RetryConfig{})
+		playlists := spotify.NewPlaylistsService(api.NewRequestBuilder(apiClient))
+
+		_, err := playlists.GetPlaylist(context.Background(), "37i9dQZF1DXcBWIGoYBM5M", nil)
+		err = fmt.Errorf("failed to get playlist: %w", err)
+		if code := ExitCode(err); code != tt.expected {
+			t.Errorf("HTTP %d: exit code %d, expected %d (%v)", tt.status, code, tt.expected, err)
+		}
+		server.Close()
+	}
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	newRoot := func() *cobra.Command {
+		root := &cobra.Command{Use: "root", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(&cobra.Command{
+			Use:  "one",
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return errors.New("failed")
+			},
+		})
+		markUsageErrors(root)
+		return root
+	}
+
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"one"}, daemon.ExitUsage},
+		{[]string{"one", "--bogus", "x"}, daemon.ExitUsage},
+		{[]string{"one", "x"}, daemon.ExitFailure},
+	}
+
+	for _, tt := range tests {
+		root := newRoot()
+		root.SetArgs(tt.args)
+		if code := ExitCode(root.Execute()); code != tt.expected {
+			t.Errorf("%v: exit code %d, expected %d", tt.args, code, tt.expected)
+		}
+	}
+}
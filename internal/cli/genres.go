@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var genresCheck []string
+
+// genresCmd represents the genres command
+var genresCmd = &cobra.Command{
+	Use:   "genres",
+	Short: "List the genres available as recommendation seeds",
+	Long: `List the genres Spotify accepts as recommendation seeds, as reported by the
+API. Only these values are valid for --seed-genre.
+
+With --check, report whether each given genre is a valid seed and exit with an
+error if any is not.`,
+	Example: `  spotify-cli genres
+  spotify-cli genres --output json
+  spotify-cli genres --check rock,hip-hop`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGenres()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(genresCmd)
+
+	genresCmd.Flags().StringSliceVar(&genresCheck, "check", nil, "Genres to check (comma-separated)")
+}
+
+func runGenres() error {
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	genres, err := spotifyClient.Tracks.GetAvailableGenreSeeds(GetCommandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get genres: %w", err)
+	}
+	sort.Strings(genres)
+
+	if len(genresCheck) > 0 {
+		return checkGenres(genres)
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"genres": genres,
+			"total":  len(genres),
+		})
+	}
+
+	fmt.Printf("Available Genre Seeds - %d total\n\n", len(genres))
+
+	const perRow = 6
+	for i := 0; i < len(genres); i += perRow {
+		end := i + perRow
+		if end > len(genres) {
+			end = len(genres)
+		}
+		row := make([]string, 0, perRow)
+		for _, genre := range genres[i:end] {
+			row = append(row, fmt.Sprintf("%-18s", genre))
+		}
+		fmt.Println(strings.TrimRight(strings.Join(row, " "), " "))
+	}
+
+	return nil
+}
+
+// checkGenres reports which of the --check genres are valid seeds
+func checkGenres(genres []string) error {
+	validator := api.NewValidator()
+
+	results := make(map[string]bool, len(genresCheck))
+	var unknown []string
+	for _, genre := range genresCheck {
+		genre = strings.ToLower(strings.TrimSpace(genre))
+		ok := validator.ValidateGenreSeeds([]string{genre}, genres) == nil
+		results[genre] = ok
+		if !ok {
+			unknown = append(unknown, genre)
+		}
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		if err := utils.Output(results); err != nil {
+			return err
+		}
+	} else {
+		for _, genre := range genresCheck {
+			genre = strings.ToLower(strings.TrimSpace(genre))
+			if results[genre] {
+				fmt.Printf("%s valid\n", genre)
+			} else {
+				fmt.Printf("%s not a genre seed\n", genre)
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown genre seeds: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/listens"
+	"github.com/bambithedeer/spotify-api/internal/notifier"
+	"github.com/bambithedeer/spotify-api/internal/playcounts"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyTopLimit    int
+	historySkipsBy     string
+	historySkipsMin    int
+	historySkipsRate   float64
+	historySkipsFormat string
+	historyLogSince    string
+	historyLogLimit    int
+	historyLogFormat   string
+	historyNotify      bool
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Record your listening history",
+	Long: `Keep your listening history beyond the 50 most recent plays Spotify
+remembers. 'history record' polls the recently played list, logs every
+play and counts the plays and skips of every track in the local state
+directory. 'history log' shows or exports the logged plays.
+
+Play counts then show up elsewhere, such as the PLAYS and LAST PLAYED
+columns of 'library tracks', which can also sort by them.`,
+	Example: `  spotify-cli history record
+  spotify-cli history record --once
+  spotify-cli history log --since 2024-06-01 --format jsonl
+  spotify-cli history top --limit 20
+  spotify-cli history skips --by artist
+  spotify-cli library tracks --sort playcount`,
+}
+
+var historyRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Count plays from the recently played list",
+	Long: `Poll the recently played list every --interval, append each play not seen
+before to the play log and count it. Polls overlap, and plays are only
+taken once. Spotify keeps only the last 50 plays, so the interval must be
+short enough that no more than 50 tracks play between polls; the default
+of 30 minutes leaves plenty of room.
+
+With --notify each poll also posts track.saved events for tracks saved and
+playlist.changed events for playlists changed since the last one to the
+webhooks in config.yaml; see 'spotify-cli notify'.
+
+Run it as a service with 'daemon install', or from cron with --once.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistoryRecord(cmd)
+	},
+}
+
+var historySkipsCmd = &cobra.Command{
+	Use:   "skips",
+	Short: "Show the tracks and artists you skip most",
+	Long: `List skip rates: the share of plays that stopped before half the track had
+played, judged from when each play ended and the one before it. Spotify
+does not list plays under 30 seconds, so the quickest skips are not
+counted, and the rates are only as complete as 'history record' has been.
+
+Tracks you skip most of the time are candidates for pruning; --format uris
+prints them one per line for other commands to act on.`,
+	Example: `  spotify-cli history skips
+  spotify-cli history skips --by artist --min-plays 10
+  spotify-cli history skips --min-rate 0.6 --format uris | xargs spotify-cli library remove track`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistorySkips()
+	},
+}
+
+var historyLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the plays logged by 'history record'",
+	Long: `List every play logged by 'history record', oldest first, with the time
+it ended. --format jsonl prints the log as it is kept, one JSON object per
+play, for loading into other tools.`,
+	Example: `  spotify-cli history log --limit 50
+  spotify-cli history log --since 2024-06-01
+  spotify-cli history log --format jsonl > listens.jsonl`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistoryLog()
+	},
+}
+
+var historyTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "List your most played tracks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistoryTop()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyRecordCmd)
+	historyCmd.AddCommand(historyTopCmd)
+	historyCmd.AddCommand(historySkipsCmd)
+	historyCmd.AddCommand(historyLogCmd)
+
+	addDaemonFlags(historyRecordCmd, 30*time.Minute)
+	historyRecordCmd.Flags().BoolVar(&historyNotify, "notify", false, "Post track.saved and playlist.changed events to the webhooks in config.yaml")
+	historyTopCmd.Flags().IntVarP(&historyTopLimit, "limit", "l", 20, "Number of tracks to list (0 for all)")
+
+	historySkipsCmd.Flags().StringVar(&historySkipsBy, "by", "track", "Report skip rates by track or artist")
+	historySkipsCmd.Flags().IntVar(&historySkipsMin, "min-plays", playcounts.MinRatePlays, "Only include items played at least this many times")
+	historySkipsCmd.Flags().Float64Var(&historySkipsRate, "min-rate", 0, "Only include items skipped at least this share of the time (0-1)")
+	historySkipsCmd.Flags().IntVarP(&historyTopLimit, "limit", "l", 20, "Number of items to list (0 for all)")
+	historySkipsCmd.Flags().StringVarP(&historySkipsFormat, "format", "f", "table", "Output format (table, uris, json, yaml)")
+
+	historyLogCmd.Flags().StringVar(&historyLogSince, "since", "", "Only list plays from this date on (YYYY-MM-DD)")
+	historyLogCmd.Flags().IntVarP(&historyLogLimit, "limit", "l", 0, "Number of most recent plays to list (0 for all)")
+	historyLogCmd.Flags().StringVarP(&historyLogFormat, "format", "f", "table", "Output format (table, jsonl, json, yaml)")
+}
+
+func runHistoryRecord(cmd *cobra.Command) error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	var n *notifier.Notifier
+	var hours *quiet.Hours
+	if historyNotify {
+		if n, err = loadNotifier(); err != nil {
+			return err
+		}
+		if hours, err = loadQuietHours(); err != nil {
+			return err
+		}
+	}
+
+	spotifyClient, err := newUserClient("your listening history")
+	if err != nil {
+		return err
+	}
+
+	return runDaemonLoop(cmd, "history-record", func(ctx context.Context, report *daemon.Report) error {
+		cursor, err := playcounts.Cursor(dir)
+		if err != nil {
+			return err
+		}
+
+		options := &spotify.RecentlyPlayedOptions{Limit: 50}
+		if !cursor.IsZero() {
+			options.After = cursor.UnixMilli()
+		}
+		history, err := spotifyClient.Player.GetRecentlyPlayed(ctx, options)
+		if err != nil {
+			return err
+		}
+
+		plays := make([]playcounts.Play, 0, len(history.Items))
+		logged := make([]listens.Listen, 0, len(history.Items))
+		for _, item := range history.Items {
+			playedAt, err := time.Parse(time.RFC3339, item.PlayedAt)
+			if err != nil {
+				utils.PrintVerbose("Skipping play of %s with bad time %q", item.Track.URI, item.PlayedAt)
+				continue
+			}
+			play := playcounts.Play{URI: item.Track.URI, PlayedAt: playedAt, DurationMs: item.Track.DurationMs}
+			for _, artist := range item.Track.Artists {
+				play.Artists = append(play.Artists, artist.Name)
+			}
+			plays = append(plays, play)
+
+			listen := listens.Listen{
+				PlayedAt:   playedAt,
+				URI:        item.Track.URI,
+				Name:       item.Track.Name,
+				Artists:    play.Artists,
+				DurationMs: item.Track.DurationMs,
+				Context:    item.Context.URI,
+			}
+			if item.Track.Album != nil {
+				listen.Album = item.Track.Album.Name
+			}
+			logged = append(logged, listen)
+		}
+
+		// The log keeps its own place, so it fills in from the first poll
+		// after it was added even where plays were counted before
+		appended, err := listens.Append(dir, logged)
+		if err != nil {
+			return err
+		}
+		report.Add("logged", appended)
+
+		counted, err := playcounts.Record(dir, plays)
+		if err != nil {
+			return err
+		}
+		report.Add("plays", counted)
+
+		// A full page after the cursor means plays may have been missed
+		if !cursor.IsZero() && len(history.Items) == 50 {
+			utils.PrintWarning("50 plays since the last poll; some may have been missed. Use a shorter --interval")
+		}
+		utils.PrintVerbose("Counted %d new play%s", counted, pluralize(counted))
+
+		if n != nil {
+			return postLibraryEvents(ctx, spotifyClient, dir, n, hours, report)
+		}
+		return nil
+	})
+}
+
+func runHistoryLog() error {
+	switch historyLogFormat {
+	case "table", "jsonl", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, jsonl, json, yaml", historyLogFormat)
+	}
+	var since time.Time
+	if historyLogSince != "" {
+		var err error
+		if since, err = time.ParseInLocation("2006-01-02", historyLogSince, time.Local); err != nil {
+			return fmt.Errorf("invalid --since %q: use YYYY-MM-DD", historyLogSince)
+		}
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	plays, err := listens.Since(dir, since)
+	if err != nil {
+		return err
+	}
+	if historyLogLimit > 0 && len(plays) > historyLogLimit {
+		plays = plays[len(plays)-historyLogLimit:]
+	}
+
+	if historyLogFormat == "jsonl" {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, play := range plays {
+			if err := encoder.Encode(play); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cfg := config.Get()
+	outputFormat := historyLogFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(plays)
+	}
+
+	if len(plays) == 0 {
+		fmt.Println("No plays logged yet. Start logging with 'spotify-cli history record'.")
+		return nil
+	}
+
+	fmt.Printf("%-17s %-30s %s\n", "PLAYED", "ARTIST", "TRACK")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, play := range plays {
+		artist := ""
+		if len(play.Artists) > 0 {
+			artist = play.Artists[0]
+		}
+		fmt.Printf("%-17s %-30s %s\n", formatTime(play.PlayedAt), truncateString(artist, 28), truncateString(play.Name, 40))
+	}
+	fmt.Printf("\n%d play%s\n", len(plays), pluralize(len(plays)))
+	return nil
+}
+
+func runHistoryTop() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	counts, err := playcounts.All(dir)
+	if err != nil {
+		return err
+	}
+	top := playcounts.Top(counts, historyTopLimit)
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(top)
+	}
+
+	if len(top) == 0 {
+		fmt.Println("No plays recorded yet. Start counting with 'spotify-cli history record'.")
+		return nil
+	}
+
+	ids := make([]string, len(top))
+	for i, count := range top {
+		ids[i] = count.URI
+	}
+	names, err := playedTrackNames(ids)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-6s %-50s %s\n", "PLAYS", "TRACK", "LAST PLAYED")
+	fmt.Println(strings.Repeat("-", 75))
+	for _, count := range top {
+		name := names[count.URI]
+		if name == "" {
+			name = count.URI
+		}
+		fmt.Printf("%-6d %-50s %s\n", count.Plays, truncateString(name, 48), formatTime(count.LastPlayed))
+	}
+	return nil
+}
+
+func runHistorySkips() error {
+	switch historySkipsFormat {
+	case "table", "uris", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, uris, json, yaml", historySkipsFormat)
+	}
+	if historySkipsBy != "track" && historySkipsBy != "artist" {
+		return fmt.Errorf("invalid --by %q. Must be track or artist", historySkipsBy)
+	}
+	if historySkipsFormat == "uris" && historySkipsBy != "track" {
+		return fmt.Errorf("--format uris only lists tracks")
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	counts, err := playcounts.All(dir)
+	if err != nil {
+		return err
+	}
+
+	var rates []playcounts.Rate
+	if historySkipsBy == "artist" {
+		rates = playcounts.ArtistRates(counts, historySkipsMin)
+	} else {
+		rates = playcounts.TrackRates(counts, historySkipsMin)
+	}
+	kept := rates[:0]
+	for _, rate := range rates {
+		if rate.Rate >= historySkipsRate {
+			kept = append(kept, rate)
+		}
+	}
+	rates = kept
+	if historyTopLimit > 0 && len(rates) > historyTopLimit {
+		rates = rates[:historyTopLimit]
+	}
+
+	if historySkipsFormat == "uris" {
+		for _, rate := range rates {
+			fmt.Println(rate.Key)
+		}
+		return nil
+	}
+
+	cfg := config.Get()
+	outputFormat := historySkipsFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(rates)
+	}
+
+	if len(rates) == 0 {
+		fmt.Printf("No %ss played %d or more times yet.\n", historySkipsBy, historySkipsMin)
+		return nil
+	}
+
+	names := map[string]string{}
+	if historySkipsBy == "track" {
+		uris := make([]string, len(rates))
+		for i, rate := range rates {
+			uris[i] = rate.Key
+		}
+		if names, err = playedTrackNames(uris); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%-6s %-6s %-6s %s\n", "SKIPS", "PLAYS", "RATE", strings.ToUpper(historySkipsBy))
+	fmt.Println(strings.Repeat("-", 75))
+	for _, rate := range rates {
+		name := rate.Key
+		if names[rate.Key] != "" {
+			name = names[rate.Key]
+		}
+		fmt.Printf("%-6d %-6d %-6s %s\n", rate.Skips, rate.Plays, fmt.Sprintf("%.0f%%", rate.Rate*100), truncateString(name, 55))
+	}
+	return nil
+}
+
+// playedTrackNames looks up "Artist - Track" names for track URIs
+func playedTrackNames(uris []string) (map[string]string, error) {
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(uris))
+	for i, uri := range uris {
+		ids[i] = uri[strings.LastIndex(uri, ":")+1:]
+	}
+	tracks, err := getTracksByID(spotifyClient, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(tracks))
+	for _, track := range tracks {
+		name := track.Name
+		if len(track.Artists) > 0 {
+			name = track.Artists[0].Name + " - " + track.Name
+		}
+		names[track.URI] = name
+	}
+	return names, nil
+}
+
+// loadPlayCounts reads the recorded play counts for showing alongside other
+// output. Counts that cannot be read are left out rather than failing.
+func loadPlayCounts() map[string]playcounts.Count {
+	dir, err := openState()
+	if err != nil {
+		utils.PrintVerbose("Play counts unavailable: %v", err)
+		return nil
+	}
+	counts, err := playcounts.All(dir)
+	if err != nil {
+		utils.PrintVerbose("Play counts unavailable: %v", err)
+		return nil
+	}
+	return counts
+}
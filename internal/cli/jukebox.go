@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/jukebox"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jukeboxDevice  string
+	jukeboxContext string
+	jukeboxSocket  string
+)
+
+// jukeboxCmd represents the jukebox command
+var jukeboxCmd = &cobra.Command{
+	Use:   "jukebox",
+	Short: "Keep a Connect device playing, for headless players like a Raspberry Pi",
+	Long: `Run a headless jukebox: bind to one Spotify Connect device, such as a
+Raspberry Pi running librespot or raspotify, and keep it playing a context.
+
+Every --interval the jukebox checks playback. When nothing is playing it
+resumes the context on the device, or starts it from the top if the device
+has moved on. Playback someone has moved to another device is left alone.
+
+Commands sent to the control socket act on the bound device, one per line:
+  ` + strings.Join(jukebox.Commands, ", ") + `
+
+A pause holds playback until the next play, so the watch loop does not undo
+it. During quiet hours (see 'spotify-cli quiet') playback that stops stays
+stopped, and the volume is kept under their cap. Wire GPIO buttons to 'spotify-cli jukebox ctl <command>', or to
+'echo <command> | nc -U <socket>' where spotify-cli is not installed.
+
+On first run without a user login, the jukebox asks for one on the terminal
+(see 'auth login --no-browser'). API credentials can come from
+SPOTIFY_CLIENT_ID and SPOTIFY_CLIENT_SECRET instead of 'auth setup'. Run it as
+a service with 'daemon install'.`,
+	Example: `  spotify-cli jukebox --device raspotify --context spotify:playlist:37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli jukebox ctl next
+  spotify-cli daemon install jukebox -- jukebox --device raspotify --context spotify:album:4aawyAB9vmqN3uQ7FjRGTy`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runJukebox(cmd)
+	},
+}
+
+var jukeboxCtlCmd = &cobra.Command{
+	Use:   "ctl [command...]",
+	Short: "Send a command to a running jukebox",
+	Long: `Send a command to a running jukebox through its control socket and print
+the reply. Commands: ` + strings.Join(jukebox.Commands, ", ") + `.`,
+	Args: cobra.MinimumNArgs(1),
+	Example: `  spotify-cli jukebox ctl toggle
+  spotify-cli jukebox ctl volume up`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runJukeboxCtl(strings.Join(args, " "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(jukeboxCmd)
+	jukeboxCmd.AddCommand(jukeboxCtlCmd)
+
+	jukeboxCmd.Flags().StringVar(&jukeboxDevice, "device", "", "Name of the Connect device to play on (required)")
+	jukeboxCmd.Flags().StringVar(&jukeboxContext, "context", "", "Album, playlist or artist URI to keep playing (required)")
+	jukeboxCmd.MarkFlagRequired("device")
+	jukeboxCmd.MarkFlagRequired("context")
+	addDaemonFlags(jukeboxCmd, 15*time.Second)
+
+	jukeboxCmd.PersistentFlags().StringVar(&jukeboxSocket, "socket", "", "Control socket path (default is jukebox.sock in the config directory)")
+}
+
+func runJukebox(cmd *cobra.Command) error {
+	if !strings.HasPrefix(jukeboxContext, "spotify:") {
+		return fmt.Errorf("--context must be a Spotify URI such as spotify:playlist:<id>")
+	}
+
+	if err := ensureJukeboxLogin(); err != nil {
+		return err
+	}
+
+	hours, err := loadQuietHours()
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("playback control")
+	if err != nil {
+		return err
+	}
+
+	box := jukebox.New(jukeboxPlayer{spotifyClient.Player}, jukebox.Config{
+		Device:  jukeboxDevice,
+		Context: jukeboxContext,
+	})
+
+	path := jukeboxSocketPath()
+	if err := removeStaleSocket(path); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to open control socket: %w", err)
+	}
+	defer os.Remove(path)
+
+	ctx, cancel := context.WithCancel(GetCommandContext())
+	defer cancel()
+	go func() {
+		if err := box.Serve(ctx, listener); err != nil {
+			utils.PrintWarning("Control socket stopped: %v", err)
+		}
+	}()
+
+	utils.PrintSuccess("Jukebox playing %s on %q, control socket %s", jukeboxContext, jukeboxDevice, path)
+
+	return runDaemonLoop(cmd, "jukebox", func(ctx context.Context, report *daemon.Report) error {
+		if now := time.Now(); hours.Active(now) {
+			lowered, err := enforceQuietVolume(ctx, spotifyClient, hours, now)
+			if lowered {
+				report.Add("volume lowered", 1)
+			}
+			return err
+		}
+
+		action, err := box.Check(ctx)
+		if err != nil {
+			return err
+		}
+		if action == jukebox.ActionRestarted {
+			report.Add("restarts", 1)
+			utils.PrintVerbose("Playback had stopped, restarted %s", jukeboxContext)
+		}
+		return nil
+	})
+}
+
+// ensureJukeboxLogin asks for a user login on the terminal when there is none,
+// so setting up a fresh Pi is a single run over SSH
+func ensureJukeboxLogin() error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("credentials not configured. Run 'spotify-cli auth setup' or set SPOTIFY_CLIENT_ID and SPOTIFY_CLIENT_SECRET")
+	}
+	if config.Get().RefreshToken != "" {
+		return nil
+	}
+
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("user login required. Run 'spotify-cli auth login --no-browser' once on this machine")
+	}
+
+	fmt.Println("The jukebox needs a user login to control playback.")
+	return runHeadlessLogin(os.Stdin)
+}
+
+func runJukeboxCtl(command string) error {
+	reply, err := jukebox.Send(jukeboxSocketPath(), command)
+	if err != nil {
+		return err
+	}
+	fmt.Println(reply)
+	return nil
+}
+
+func jukeboxSocketPath() string {
+	if jukeboxSocket != "" {
+		return jukeboxSocket
+	}
+	return filepath.Join(configDir, "jukebox.sock")
+}
+
+// jukeboxPlayer adapts the player service to the jukebox
+type jukeboxPlayer struct {
+	player *spotify.PlayerService
+}
+
+func (p jukeboxPlayer) Devices(ctx context.Context) ([]models.Device, error) {
+	devices, err := p.player.GetDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return devices.Devices, nil
+}
+
+func (p jukeboxPlayer) State(ctx context.Context) (*models.PlaybackState, error) {
+	return p.player.GetPlaybackState(ctx, "")
+}
+
+func (p jukeboxPlayer) Play(ctx context.Context, deviceID, contextURI string) error {
+	return p.player.Play(ctx, &spotify.PlayOptions{DeviceID: deviceID, ContextURI: contextURI})
+}
+
+func (p jukeboxPlayer) Pause(ctx context.Context, deviceID string) error {
+	return p.player.Pause(ctx, deviceID)
+}
+
+func (p jukeboxPlayer) Next(ctx context.Context, deviceID string) error {
+	return p.player.Next(ctx, deviceID)
+}
+
+func (p jukeboxPlayer) Previous(ctx context.Context, deviceID string) error {
+	return p.player.Previous(ctx, deviceID)
+}
+
+func (p jukeboxPlayer) SetVolume(ctx context.Context, deviceID string, percent int) error {
+	return p.player.SetVolume(ctx, percent, deviceID)
+}
+
+// removeStaleSocket removes a control socket left behind by a crash, which
+// would make Listen fail. Anything at path that is not a socket is left
+// alone, since --socket may name any file.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check control socket: %w", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket; choose another --socket", path)
+	}
+	if _, err := jukebox.Send(path, "status"); err == nil {
+		return fmt.Errorf("a jukebox is already running on %s", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+	return nil
+}
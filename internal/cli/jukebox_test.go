@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("Expected no error for a missing socket, got %v", err)
+	}
+
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("keep me"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeStaleSocket(notes); err == nil {
+		t.Error("Expected an error for a path that is not a socket")
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Errorf("Expected a file that is not a socket to be kept, got %v", err)
+	}
+
+	// A socket nobody listens on any more is removed
+	stale := filepath.Join(dir, "stale.sock")
+	listener, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	if err := removeStaleSocket(stale); err != nil {
+		t.Fatalf("Expected a stale socket to be removed, got %v", err)
+	}
+	if _, err := os.Lstat(stale); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale socket to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/karaoke"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	karaokeMaxSpeechiness float64
+	karaokeLimit          int
+	karaokeMarket         string
+	karaokeDeviceID       string
+	karaokeDryRun         bool
+	karaokeFormat         string
+)
+
+// karaokeCmd represents the karaoke command
+var karaokeCmd = &cobra.Command{
+	Use:   "karaoke",
+	Short: "Find karaoke and instrumental versions of songs",
+	Long: `Find karaoke and instrumental versions of songs to sing along to.
+
+Requires user authentication to queue tracks, or client credentials with --dry-run.`,
+	Example: `  spotify-cli karaoke find "Don't Stop Me Now"
+  spotify-cli karaoke find 7hQJA50XrCWABAu5v6QZ4i --dry-run`,
+}
+
+var karaokeFindCmd = &cobra.Command{
+	Use:   "find <song>",
+	Short: "Find and queue a vocal-free version of a song",
+	Long: `Find a karaoke or instrumental version of a song and add the best match to
+the playback queue.
+
+The song can be a track ID or URI, or a search such as "artist title".
+Several searches are run for versions labelled karaoke, instrumental or
+"in the style of", and the results are matched to the original by title and
+duration. Candidates Spotify hears as speech-heavy, above --max-speechiness,
+are dropped since they likely still have vocals; the rest are ranked with
+the most instrumental first.`,
+	Args: cobra.MinimumNArgs(1),
+	Example: `  spotify-cli karaoke find bohemian rhapsody queen
+  spotify-cli karaoke find spotify:track:7hQJA50XrCWABAu5v6QZ4i
+  spotify-cli karaoke find "dancing queen" --dry-run --limit 10
+  spotify-cli karaoke find "hey jude" --max-speechiness 0.05 --device <device-id>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runKaraokeFind(strings.Join(args, " "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(karaokeCmd)
+	karaokeCmd.AddCommand(karaokeFindCmd)
+
+	karaokeFindCmd.Flags().Float64Var(&karaokeMaxSpeechiness, "max-speechiness", karaoke.DefaultMaxSpeechiness, "Drop candidates with more speechiness than this (0-1)")
+	karaokeFindCmd.Flags().IntVarP(&karaokeLimit, "limit", "l", 5, "Number of candidates to show")
+	karaokeFindCmd.Flags().StringVarP(&karaokeMarket, "market", "m", "", "Market/country code (e.g., US, GB)")
+	karaokeFindCmd.Flags().StringVarP(&karaokeDeviceID, "device", "d", "", "Target device ID")
+	karaokeFindCmd.Flags().BoolVar(&karaokeDryRun, "dry-run", false, "List candidates without queueing the best one")
+	karaokeFindCmd.Flags().StringVarP(&karaokeFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+func runKaraokeFind(song string) error {
+	if karaokeMaxSpeechiness < 0 || karaokeMaxSpeechiness > 1 {
+		return fmt.Errorf("max speechiness must be between 0 and 1")
+	}
+
+	var spotifyClient *client.SpotifyClient
+	var err error
+	if karaokeDryRun {
+		spotifyClient, err = newBrowseClient()
+	} else {
+		spotifyClient, err = newUserClient("playback")
+	}
+	if err != nil {
+		return err
+	}
+
+	original, err := resolveKaraokeSong(spotifyClient, song)
+	if err != nil {
+		return err
+	}
+	utils.PrintVerbose("Looking for versions of %s by %s", original.Name, utils.FormatSimpleArtists(original.Artists))
+
+	candidates, err := findKaraokeCandidates(spotifyClient, original)
+	if err != nil {
+		return err
+	}
+
+	ranked := karaoke.Rank(candidates, karaokeMaxSpeechiness)
+	if len(ranked) > karaokeLimit && karaokeLimit > 0 {
+		ranked = ranked[:karaokeLimit]
+	}
+
+	if err := outputKaraokeCandidates(original, ranked); err != nil {
+		return err
+	}
+	if len(ranked) == 0 || karaokeDryRun {
+		return nil
+	}
+
+	best := ranked[0].Track
+	if err := spotifyClient.Player.AddToQueue(GetCommandContext(), best.URI, karaokeDeviceID); err != nil {
+		return playerCommandError(spotifyClient, "add to queue", err)
+	}
+	utils.PrintSuccess("Queued %s by %s", best.Name, utils.FormatSimpleArtists(best.Artists))
+	return nil
+}
+
+// resolveKaraokeSong looks up the original track from an ID or URI,
+// or from the best search match that is not itself a karaoke version
+func resolveKaraokeSong(spotifyClient *client.SpotifyClient, song string) (*models.Track, error) {
+	ctx := GetCommandContext()
+
+	if ids, err := api.NewValidator().NormalizeAndValidateIDs([]string{song}); err == nil {
+		track, err := spotifyClient.Tracks.GetTrack(ctx, ids[0], karaokeMarket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get track: %w", err)
+		}
+		return track, nil
+	}
+
+	results, _, err := spotifyClient.Search.SearchTracks(ctx, song, &api.PaginationOptions{Limit: 10})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for %q: %w", song, err)
+	}
+	for i := range results.Items {
+		if !karaoke.IsKaraokeTitle(results.Items[i].Name) {
+			return &results.Items[i], nil
+		}
+	}
+	if len(results.Items) > 0 {
+		return &results.Items[0], nil
+	}
+	return nil, fmt.Errorf("no tracks found for %q", song)
+}
+
+// findKaraokeCandidates runs the karaoke searches for original and returns
+// the distinct matches with their audio features
+func findKaraokeCandidates(spotifyClient *client.SpotifyClient, original *models.Track) ([]karaoke.Candidate, error) {
+	ctx := GetCommandContext()
+
+	var candidates []karaoke.Candidate
+	seen := make(map[string]bool)
+	for _, query := range karaoke.Queries(*original) {
+		utils.PrintVerbose("Searching for %q", query)
+
+		results, _, err := spotifyClient.Search.SearchTracks(ctx, query, &api.PaginationOptions{Limit: 20})
+		if err != nil {
+			// Later queries may still find something
+			utils.PrintWarning("Search for %q failed: %v", query, err)
+			continue
+		}
+
+		for _, track := range results.Items {
+			if seen[track.ID] {
+				continue
+			}
+			seen[track.ID] = true
+
+			if score, ok := karaoke.Match(*original, track); ok {
+				candidates = append(candidates, karaoke.Candidate{Track: track, Score: score})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	tracks := make([]models.Track, len(candidates))
+	for i, candidate := range candidates {
+		tracks[i] = candidate.Track
+	}
+	withFeatures, err := withAudioFeatures(spotifyClient, tracks)
+	if err != nil {
+		// Audio features are only used to refine the ranking
+		utils.PrintWarning("Ranking by title and duration only: %v", err)
+		return candidates, nil
+	}
+	for i := range candidates {
+		candidates[i].Features = withFeatures[i].Features
+	}
+
+	return candidates, nil
+}
+
+func outputKaraokeCandidates(original *models.Track, candidates []karaoke.Candidate) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := karaokeFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"original":   original,
+			"candidates": candidates,
+		})
+	}
+
+	fmt.Printf("Versions of %s by %s (%s)\n\n", original.Name, utils.FormatSimpleArtists(original.Artists), utils.FormatDuration(original.DurationMs))
+
+	if len(candidates) == 0 {
+		fmt.Println("No karaoke or instrumental versions found.")
+		return nil
+	}
+
+	fmt.Printf("%-22s %-40s %-25s %8s %7s %6s %5s\n", "ID", "TRACK", "ARTIST", "DURATION", "SPEECH", "INSTR", "SCORE")
+	fmt.Println(strings.Repeat("-", 120))
+
+	for _, candidate := range candidates {
+		speech, instrumental := "-", "-"
+		if f := candidate.Features; f != nil {
+			speech = fmt.Sprintf("%.2f", f.Speechiness)
+			instrumental = fmt.Sprintf("%.2f", f.Instrumentalness)
+		}
+		fmt.Printf("%-22s %-40s %-25s %8s %7s %6s %5.2f\n",
+			candidate.Track.ID,
+			truncateString(candidate.Track.Name, 38),
+			truncateString(utils.FormatSimpleArtists(candidate.Track.Artists), 23),
+			utils.FormatDuration(candidate.Track.DurationMs),
+			speech, instrumental, candidate.Score)
+	}
+
+	return nil
+}
@@ -2,30 +2,35 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/cli/client"
 	"github.com/bambithedeer/spotify-api/internal/cli/config"
 	"github.com/bambithedeer/spotify-api/internal/cli/utils"
 	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/playcounts"
 	"github.com/bambithedeer/spotify-api/internal/spotify"
 	"github.com/spf13/cobra"
 )
 
 var (
-	libraryLimit   int
-	libraryOffset  int
-	libraryMarket  string
-	libraryFormat  string
+	libraryLimit    int
+	libraryOffset   int
+	libraryMarket   string
+	libraryFormat   string
+	librarySort     string
+	libraryMinPlays int
 )
 
 // libraryCmd represents the library command
 var libraryCmd = &cobra.Command{
 	Use:   "library",
 	Short: "Manage your Spotify library",
-	Long: `Manage your saved tracks, albums, and library content.
+	Long: `Manage your saved tracks, albums, podcast episodes and shows.
 
 Requires user authentication. Use 'auth login' to authenticate with user account first.
 Client credentials authentication does not provide access to user library data.`,
@@ -35,6 +40,9 @@ Client credentials authentication does not provide access to user library data.`
   # List saved albums
   spotify-cli library albums
 
+  # List saved podcast episodes
+  spotify-cli library episodes
+
   # List followed artists
   spotify-cli library follows
 
@@ -51,10 +59,16 @@ Client credentials authentication does not provide access to user library data.`
 var libraryTracksCmd = &cobra.Command{
 	Use:   "tracks",
 	Short: "List saved tracks",
-	Long:  `List tracks saved in your Spotify library.`,
+	Long: `List tracks saved in your Spotify library.
+
+Tracks played since 'history record' started counting show their play
+count and when they were last played. --sort and --min-plays work on the
+whole library, which is fetched in full before --limit and --offset apply.`,
 	Example: `  spotify-cli library tracks
   spotify-cli library tracks --limit 50
-  spotify-cli library tracks --format list`,
+  spotify-cli library tracks --format list
+  spotify-cli library tracks --sort playcount --limit 25
+  spotify-cli library tracks --sort lastplayed --min-plays 1`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runLibraryTracks()
 	},
@@ -71,16 +85,39 @@ var libraryAlbumsCmd = &cobra.Command{
 	},
 }
 
+var libraryEpisodesCmd = &cobra.Command{
+	Use:   "episodes",
+	Short: "List saved podcast episodes",
+	Long:  `List podcast episodes saved in your Spotify library, with how far you got through each.`,
+	Example: `  spotify-cli library episodes
+  spotify-cli library episodes --limit 50 --market US`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLibraryEpisodes()
+	},
+}
+
+var libraryShowsCmd = &cobra.Command{
+	Use:   "shows",
+	Short: "List saved podcast shows",
+	Long:  `List podcast shows saved in your Spotify library.`,
+	Example: `  spotify-cli library shows
+  spotify-cli library shows --format list`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLibraryShows()
+	},
+}
+
 var librarySaveCmd = &cobra.Command{
 	Use:   "save [type] [id...]",
-	Short: "Save tracks or albums to library",
-	Long: `Save one or more tracks or albums to your Spotify library.
+	Short: "Save tracks, albums, episodes or shows to library",
+	Long: `Save one or more tracks, albums, episodes or shows to your Spotify library.
 
-Type must be either 'track' or 'album'.
+Type must be 'track', 'album', 'episode' or 'show'.
 You can provide multiple IDs to save multiple items at once (up to 50).`,
 	Args: cobra.MinimumNArgs(2),
 	Example: `  spotify-cli library save track 4iV5W9uYEdYUVa79Axb7Rh
   spotify-cli library save album 1DFixLWuPkv3KT3TnV35m3
+  spotify-cli library save episode 512ojhOuo1ktJprKbVcKyQ
   spotify-cli library save track id1 id2 id3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runLibrarySave(args[0], args[1:])
@@ -89,10 +126,10 @@ You can provide multiple IDs to save multiple items at once (up to 50).`,
 
 var libraryRemoveCmd = &cobra.Command{
 	Use:   "remove [type] [id...]",
-	Short: "Remove tracks or albums from library",
-	Long: `Remove one or more tracks or albums from your Spotify library.
+	Short: "Remove tracks, albums, episodes or shows from library",
+	Long: `Remove one or more tracks, albums, episodes or shows from your Spotify library.
 
-Type must be either 'track' or 'album'.
+Type must be 'track', 'album', 'episode' or 'show'.
 You can provide multiple IDs to remove multiple items at once (up to 50).`,
 	Args: cobra.MinimumNArgs(2),
 	Example: `  spotify-cli library remove track 4iV5W9uYEdYUVa79Axb7Rh
@@ -105,14 +142,15 @@ You can provide multiple IDs to remove multiple items at once (up to 50).`,
 
 var libraryCheckCmd = &cobra.Command{
 	Use:   "check [type] [id...]",
-	Short: "Check if tracks or albums are saved",
-	Long: `Check whether one or more tracks or albums are saved in your library.
+	Short: "Check if tracks, albums, episodes or shows are saved",
+	Long: `Check whether one or more tracks, albums, episodes or shows are saved in your library.
 
-Type must be either 'track' or 'album'.
+Type must be 'track', 'album', 'episode' or 'show'.
 You can check multiple IDs at once (up to 50).`,
 	Args: cobra.MinimumNArgs(2),
 	Example: `  spotify-cli library check track 4iV5W9uYEdYUVa79Axb7Rh
   spotify-cli library check album 1DFixLWuPkv3KT3TnV35m3
+  spotify-cli library check show 38bS44xjbVVZ3No3ByF1dJ
   spotify-cli library check track id1 id2 id3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runLibraryCheck(args[0], args[1:])
@@ -137,18 +175,22 @@ func init() {
 	rootCmd.AddCommand(libraryCmd)
 	libraryCmd.AddCommand(libraryTracksCmd)
 	libraryCmd.AddCommand(libraryAlbumsCmd)
+	libraryCmd.AddCommand(libraryEpisodesCmd)
+	libraryCmd.AddCommand(libraryShowsCmd)
 	libraryCmd.AddCommand(librarySaveCmd)
 	libraryCmd.AddCommand(libraryRemoveCmd)
 	libraryCmd.AddCommand(libraryCheckCmd)
 	libraryCmd.AddCommand(libraryFollowsCmd)
 
 	// Add flags to list commands
-	for _, cmd := range []*cobra.Command{libraryTracksCmd, libraryAlbumsCmd, libraryFollowsCmd} {
+	for _, cmd := range []*cobra.Command{libraryTracksCmd, libraryAlbumsCmd, libraryEpisodesCmd, libraryShowsCmd, libraryFollowsCmd} {
 		cmd.Flags().IntVarP(&libraryLimit, "limit", "l", 20, "Number of results to return (1-50)")
 		cmd.Flags().IntVarP(&libraryOffset, "offset", "", 0, "Offset for pagination")
 		cmd.Flags().StringVarP(&libraryMarket, "market", "m", "", "Market/country code (e.g., US, GB)")
 		cmd.Flags().StringVarP(&libraryFormat, "format", "f", "table", "Output format (table, list, json, yaml)")
 	}
+	libraryTracksCmd.Flags().StringVar(&librarySort, "sort", "", "Sort the whole library by playcount, lastplayed or added")
+	libraryTracksCmd.Flags().IntVar(&libraryMinPlays, "min-plays", 0, "Only list tracks played at least this many times")
 }
 
 func runLibraryTracks() error {
@@ -167,6 +209,10 @@ func runLibraryTracks() error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access your personal library")
 	}
 
+	if librarySort != "" || libraryMinPlays > 0 {
+		return runLibraryTracksSorted(spotifyClient)
+	}
+
 	// Create pagination options
 	paginationOpts := &api.PaginationOptions{
 		Limit:  libraryLimit,
@@ -181,6 +227,73 @@ func runLibraryTracks() error {
 	return outputLibraryResults("saved tracks", tracks, pagination)
 }
 
+// runLibraryTracksSorted lists saved tracks sorted or filtered by play
+// counts, which needs the whole library rather than a single page
+func runLibraryTracksSorted(spotifyClient *client.SpotifyClient) error {
+	switch librarySort {
+	case "", "playcount", "lastplayed", "added":
+	default:
+		return fmt.Errorf("invalid sort %q. Valid sorts: playcount, lastplayed, added", librarySort)
+	}
+
+	items, err := spotifyClient.Library.SavedTracksPager(nil).All(GetCommandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+	items = sortSavedTracks(items, loadPlayCounts(), librarySort, libraryMinPlays)
+
+	total := len(items)
+	start := min(max(libraryOffset, 0), total)
+	end := min(start+libraryLimit, total)
+	page := &models.Paging[models.SavedTrack]{
+		Items:  items[start:end],
+		Limit:  libraryLimit,
+		Offset: start,
+		Total:  total,
+	}
+	pagination := &api.PaginationInfo{Total: total, Limit: libraryLimit, Offset: start}
+	if end < total {
+		pagination.Next = fmt.Sprintf("?offset=%d&limit=%d", end, libraryLimit)
+	}
+
+	return outputLibraryResults("saved tracks", page, pagination)
+}
+
+// sortSavedTracks drops tracks played fewer than minPlays times and orders
+// the rest: by plays or last play, most first, or by when they were saved.
+// Ties keep the library order, newest saves first.
+func sortSavedTracks(items []models.SavedTrack, counts map[string]playcounts.Count, by string, minPlays int) []models.SavedTrack {
+	kept := make([]models.SavedTrack, 0, len(items))
+	for _, item := range items {
+		if counts[item.Track.URI].Plays >= minPlays {
+			kept = append(kept, item)
+		}
+	}
+
+	switch by {
+	case "playcount":
+		sort.SliceStable(kept, func(i, j int) bool {
+			return counts[kept[i].Track.URI].Plays > counts[kept[j].Track.URI].Plays
+		})
+	case "lastplayed":
+		sort.SliceStable(kept, func(i, j int) bool {
+			return counts[kept[i].Track.URI].LastPlayed.After(counts[kept[j].Track.URI].LastPlayed)
+		})
+	}
+	return kept
+}
+
+// playCountsOf returns the counts of the given tracks that have been played
+func playCountsOf(counts map[string]playcounts.Count, uris []string) map[string]playcounts.Count {
+	played := make(map[string]playcounts.Count)
+	for _, uri := range uris {
+		if count, ok := counts[uri]; ok {
+			played[uri] = count
+		}
+	}
+	return played
+}
+
 func runLibraryAlbums() error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -206,6 +319,36 @@ func runLibraryAlbums() error {
 	return outputLibraryResults("saved albums", albums, pagination)
 }
 
+func runLibraryEpisodes() error {
+	spotifyClient, err := newUserClient("your saved episodes")
+	if err != nil {
+		return err
+	}
+
+	options := &api.PaginationOptions{Limit: libraryLimit, Offset: libraryOffset}
+	episodes, pagination, err := spotifyClient.Library.GetSavedEpisodes(GetCommandContext(), options, libraryMarket)
+	if err != nil {
+		return fmt.Errorf("failed to get saved episodes: %w", err)
+	}
+
+	return outputLibraryResults("saved episodes", episodes, pagination)
+}
+
+func runLibraryShows() error {
+	spotifyClient, err := newUserClient("your saved shows")
+	if err != nil {
+		return err
+	}
+
+	options := &api.PaginationOptions{Limit: libraryLimit, Offset: libraryOffset}
+	shows, pagination, err := spotifyClient.Library.GetSavedShows(GetCommandContext(), options)
+	if err != nil {
+		return fmt.Errorf("failed to get saved shows: %w", err)
+	}
+
+	return outputLibraryResults("saved shows", shows, pagination)
+}
+
 func runLibrarySave(itemType string, ids []string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -235,8 +378,22 @@ func runLibrarySave(itemType string, ids []string) error {
 		}
 		utils.PrintSuccess(fmt.Sprintf("Successfully saved %d album(s) to library", len(ids)))
 
+	case "episode", "episodes":
+		err = spotifyClient.Library.SaveEpisodes(GetCommandContext(), ids)
+		if err != nil {
+			return fmt.Errorf("failed to save episodes: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Successfully saved %d episode(s) to library", len(ids)))
+
+	case "show", "shows":
+		err = spotifyClient.Library.SaveShows(GetCommandContext(), ids)
+		if err != nil {
+			return fmt.Errorf("failed to save shows: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Successfully saved %d show(s) to library", len(ids)))
+
 	default:
-		return fmt.Errorf("invalid type '%s'. Must be 'track' or 'album'", itemType)
+		return fmt.Errorf("invalid type '%s'. Must be 'track', 'album', 'episode' or 'show'", itemType)
 	}
 
 	return nil
@@ -271,8 +428,22 @@ func runLibraryRemove(itemType string, ids []string) error {
 		}
 		utils.PrintSuccess(fmt.Sprintf("Successfully removed %d album(s) from library", len(ids)))
 
+	case "episode", "episodes":
+		err = spotifyClient.Library.RemoveEpisodes(GetCommandContext(), ids)
+		if err != nil {
+			return fmt.Errorf("failed to remove episodes: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Successfully removed %d episode(s) from library", len(ids)))
+
+	case "show", "shows":
+		err = spotifyClient.Library.RemoveShows(GetCommandContext(), ids)
+		if err != nil {
+			return fmt.Errorf("failed to remove shows: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Successfully removed %d show(s) from library", len(ids)))
+
 	default:
-		return fmt.Errorf("invalid type '%s'. Must be 'track' or 'album'", itemType)
+		return fmt.Errorf("invalid type '%s'. Must be 'track', 'album', 'episode' or 'show'", itemType)
 	}
 
 	return nil
@@ -302,8 +473,14 @@ func runLibraryCheck(itemType string, ids []string) error {
 	case "album", "albums":
 		saved, err = spotifyClient.Library.CheckSavedAlbums(GetCommandContext(), ids)
 		checkType = "album"
+	case "episode", "episodes":
+		saved, err = spotifyClient.Library.CheckSavedEpisodes(GetCommandContext(), ids)
+		checkType = "episode"
+	case "show", "shows":
+		saved, err = spotifyClient.Library.CheckSavedShows(GetCommandContext(), ids)
+		checkType = "show"
 	default:
-		return fmt.Errorf("invalid type '%s'. Must be 'track' or 'album'", itemType)
+		return fmt.Errorf("invalid type '%s'. Must be 'track', 'album', 'episode' or 'show'", itemType)
 	}
 
 	if err != nil {
@@ -324,7 +501,7 @@ func outputLibraryResults(libraryType string, results interface{}, pagination *a
 
 	// For structured output, return the data directly
 	if outputFormat == "json" || outputFormat == "yaml" {
-		return utils.Output(map[string]interface{}{
+		output := map[string]interface{}{
 			"results":    results,
 			"pagination": pagination,
 			"library_info": map[string]interface{}{
@@ -333,7 +510,16 @@ func outputLibraryResults(libraryType string, results interface{}, pagination *a
 				"offset": libraryOffset,
 				"market": libraryMarket,
 			},
-		})
+		}
+		if noted := loadNotes().Subset(libraryResultURIs(results)); len(noted) > 0 {
+			output["notes"] = noted
+		}
+		if _, ok := results.(*models.Paging[models.SavedTrack]); ok {
+			if played := playCountsOf(loadPlayCounts(), libraryResultURIs(results)); len(played) > 0 {
+				output["play_counts"] = played
+			}
+		}
+		return utils.Output(output)
 	}
 
 	// Text-based output
@@ -342,11 +528,39 @@ func outputLibraryResults(libraryType string, results interface{}, pagination *a
 		return outputSavedTracksTable(v, pagination)
 	case *models.Paging[models.SavedAlbum]:
 		return outputSavedAlbumsTable(v, pagination)
+	case *models.Paging[models.SavedEpisode]:
+		return outputSavedEpisodesTable(v, pagination)
+	case *models.Paging[models.SavedShow]:
+		return outputSavedShowsTable(v, pagination)
 	default:
 		return fmt.Errorf("unsupported result type")
 	}
 }
 
+// libraryResultURIs returns the URIs of the items in a page of library results
+func libraryResultURIs(results interface{}) []string {
+	var uris []string
+	switch v := results.(type) {
+	case *models.Paging[models.SavedTrack]:
+		for _, item := range v.Items {
+			uris = append(uris, item.Track.URI)
+		}
+	case *models.Paging[models.SavedAlbum]:
+		for _, item := range v.Items {
+			uris = append(uris, item.Album.URI)
+		}
+	case *models.Paging[models.SavedEpisode]:
+		for _, item := range v.Items {
+			uris = append(uris, item.Episode.URI)
+		}
+	case *models.Paging[models.SavedShow]:
+		for _, item := range v.Items {
+			uris = append(uris, item.Show.URI)
+		}
+	}
+	return uris
+}
+
 func outputSavedTracksTable(savedTracks *models.Paging[models.SavedTrack], pagination *api.PaginationInfo) error {
 	if len(savedTracks.Items) == 0 {
 		fmt.Println("No saved tracks found.")
@@ -361,7 +575,9 @@ func outputSavedTracksTable(savedTracks *models.Paging[models.SavedTrack], pagin
 	fmt.Println()
 	fmt.Println()
 
+	counts := loadPlayCounts()
 	if libraryFormat == "list" {
+		noted := loadNotes()
 		for i, savedTrack := range savedTracks.Items {
 			track := savedTrack.Track
 			fmt.Printf("%d. %s\n", i+1, track.Name)
@@ -377,18 +593,22 @@ func outputSavedTracksTable(savedTracks *models.Paging[models.SavedTrack], pagin
 				fmt.Printf("   from %s\n", track.Album.Name)
 			}
 			if track.DurationMs > 0 {
-				duration := formatTrackDuration(track.DurationMs)
+				duration := utils.FormatDuration(track.DurationMs)
 				fmt.Printf("   ⏱ %s\n", duration)
 			}
 			if savedTrack.AddedAt != "" {
-				fmt.Printf("   📅 Added %s\n", formatDate(savedTrack.AddedAt))
+				fmt.Printf("   📅 Added %s\n", formatTimestamp(savedTrack.AddedAt))
+			}
+			if count, ok := counts[track.URI]; ok {
+				fmt.Printf("   ▶ Played %d time%s, last %s\n", count.Plays, pluralize(count.Plays), formatTime(count.LastPlayed))
 			}
+			printNotes(noted, track.URI, "   ")
 			fmt.Println()
 		}
 	} else {
 		// Table format
-		fmt.Printf("%-22s %-40s %-25s %-25s %-8s %s\n", "ID", "TRACK", "ARTIST", "ALBUM", "DURATION", "ADDED")
-		fmt.Println(strings.Repeat("-", 140))
+		fmt.Printf("%-22s %-40s %-25s %-25s %-8s %-16s %-5s %s\n", "ID", "TRACK", "ARTIST", "ALBUM", "DURATION", "ADDED", "PLAYS", "LAST PLAYED")
+		fmt.Println(strings.Repeat("-", 160))
 
 		for _, savedTrack := range savedTracks.Items {
 			track := savedTrack.Track
@@ -408,21 +628,29 @@ func outputSavedTracksTable(savedTracks *models.Paging[models.SavedTrack], pagin
 
 			duration := ""
 			if track.DurationMs > 0 {
-				duration = formatTrackDuration(track.DurationMs)
+				duration = utils.FormatDuration(track.DurationMs)
 			}
 
 			added := ""
 			if savedTrack.AddedAt != "" {
-				added = formatDate(savedTrack.AddedAt)
+				added = formatTimestamp(savedTrack.AddedAt)
 			}
 
-			fmt.Printf("%-22s %-40s %-25s %-25s %-8s %s\n",
+			plays, lastPlayed := "", ""
+			if count, ok := counts[track.URI]; ok {
+				plays = strconv.Itoa(count.Plays)
+				lastPlayed = formatTime(count.LastPlayed)
+			}
+
+			fmt.Printf("%-22s %-40s %-25s %-25s %-8s %-16s %-5s %s\n",
 				track.ID,
 				truncateString(track.Name, 38),
 				truncateString(artists, 23),
 				truncateString(album, 23),
 				duration,
-				added)
+				added,
+				plays,
+				lastPlayed)
 		}
 	}
 
@@ -453,6 +681,7 @@ func outputSavedAlbumsTable(savedAlbums *models.Paging[models.SavedAlbum], pagin
 	fmt.Println()
 
 	if libraryFormat == "list" {
+		noted := loadNotes()
 		for i, savedAlbum := range savedAlbums.Items {
 			album := savedAlbum.Album
 			fmt.Printf("%d. %s\n", i+1, album.Name)
@@ -471,8 +700,9 @@ func outputSavedAlbumsTable(savedAlbums *models.Paging[models.SavedAlbum], pagin
 				fmt.Printf("   %d tracks\n", album.TotalTracks)
 			}
 			if savedAlbum.AddedAt != "" {
-				fmt.Printf("   📅 Added %s\n", formatDate(savedAlbum.AddedAt))
+				fmt.Printf("   📅 Added %s\n", formatTimestamp(savedAlbum.AddedAt))
 			}
+			printNotes(noted, album.URI, "   ")
 			fmt.Println()
 		}
 	} else {
@@ -503,7 +733,7 @@ func outputSavedAlbumsTable(savedAlbums *models.Paging[models.SavedAlbum], pagin
 
 			added := ""
 			if savedAlbum.AddedAt != "" {
-				added = formatDate(savedAlbum.AddedAt)
+				added = formatTimestamp(savedAlbum.AddedAt)
 			}
 
 			fmt.Printf("%-22s %-30s %-25s %-12s %-6s %s\n",
@@ -528,6 +758,116 @@ func outputSavedAlbumsTable(savedAlbums *models.Paging[models.SavedAlbum], pagin
 	return nil
 }
 
+func outputSavedEpisodesTable(savedEpisodes *models.Paging[models.SavedEpisode], pagination *api.PaginationInfo) error {
+	if len(savedEpisodes.Items) == 0 {
+		fmt.Println("No saved episodes found.")
+		return nil
+	}
+
+	fmt.Printf("Your Saved Episodes - %d total", savedEpisodes.Total)
+	if pagination != nil {
+		fmt.Printf(" (showing %d-%d)", pagination.Offset+1, pagination.Offset+len(savedEpisodes.Items))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if libraryFormat == "list" {
+		for i, savedEpisode := range savedEpisodes.Items {
+			episode := savedEpisode.Episode
+			fmt.Printf("%d. %s\n", i+1, episode.Name)
+			fmt.Printf("   ID: %s\n", episode.ID)
+			if episode.Show != nil {
+				fmt.Printf("   from %s\n", episode.Show.Name)
+			}
+			if episode.DurationMs > 0 {
+				fmt.Printf("   ⏱ %s\n", utils.FormatDuration(episode.DurationMs))
+			}
+			if progress := episodeProgress(episode); progress != "" {
+				fmt.Printf("   ▶ %s\n", progress)
+			}
+			if savedEpisode.AddedAt != "" {
+				fmt.Printf("   📅 Added %s\n", formatTimestamp(savedEpisode.AddedAt))
+			}
+			fmt.Println()
+		}
+	} else {
+		fmt.Printf("%-22s %-35s %-25s %-8s %-10s %s\n", "ID", "EPISODE", "SHOW", "DURATION", "PROGRESS", "ADDED")
+		fmt.Println(strings.Repeat("-", 125))
+
+		for _, savedEpisode := range savedEpisodes.Items {
+			episode := savedEpisode.Episode
+			show := ""
+			if episode.Show != nil {
+				show = episode.Show.Name
+			}
+
+			duration := ""
+			if episode.DurationMs > 0 {
+				duration = utils.FormatDuration(episode.DurationMs)
+			}
+
+			fmt.Printf("%-22s %-35s %-25s %-8s %-10s %s\n",
+				episode.ID,
+				truncateString(episode.Name, 33),
+				truncateString(show, 23),
+				duration,
+				episodeProgress(episode),
+				formatTimestamp(savedEpisode.AddedAt))
+		}
+	}
+
+	printNextOffset(pagination)
+	return nil
+}
+
+func outputSavedShowsTable(savedShows *models.Paging[models.SavedShow], pagination *api.PaginationInfo) error {
+	if len(savedShows.Items) == 0 {
+		fmt.Println("No saved shows found.")
+		return nil
+	}
+
+	fmt.Printf("Your Saved Shows - %d total", savedShows.Total)
+	if pagination != nil {
+		fmt.Printf(" (showing %d-%d)", pagination.Offset+1, pagination.Offset+len(savedShows.Items))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if libraryFormat == "list" {
+		for i, savedShow := range savedShows.Items {
+			show := savedShow.Show
+			fmt.Printf("%d. %s\n", i+1, show.Name)
+			fmt.Printf("   ID: %s\n", show.ID)
+			if show.Publisher != "" {
+				fmt.Printf("   by %s\n", show.Publisher)
+			}
+			if show.TotalEpisodes > 0 {
+				fmt.Printf("   %d episodes\n", show.TotalEpisodes)
+			}
+			if savedShow.AddedAt != "" {
+				fmt.Printf("   📅 Added %s\n", formatTimestamp(savedShow.AddedAt))
+			}
+			fmt.Println()
+		}
+	} else {
+		fmt.Printf("%-22s %-35s %-25s %-8s %s\n", "ID", "SHOW", "PUBLISHER", "EPISODES", "ADDED")
+		fmt.Println(strings.Repeat("-", 110))
+
+		for _, savedShow := range savedShows.Items {
+			show := savedShow.Show
+			fmt.Printf("%-22s %-35s %-25s %-8d %s\n",
+				show.ID,
+				truncateString(show.Name, 33),
+				truncateString(show.Publisher, 23),
+				show.TotalEpisodes,
+				formatTimestamp(savedShow.AddedAt))
+		}
+	}
+
+	printNextOffset(pagination)
+	return nil
+}
+
 func outputLibraryCheckResults(itemType string, ids []string, saved []bool) error {
 	cfg := config.Get()
 
@@ -573,12 +913,15 @@ func outputLibraryCheckResults(itemType string, ids []string, saved []bool) erro
 
 // Helper functions
 
-func formatDate(dateStr string) string {
-	// Simple date formatting - just take the date part if it's ISO format
-	if len(dateStr) >= 10 {
-		return dateStr[:10]
-	}
-	return dateStr
+// formatTimestamp shows an API time such as added_at relative to now, or in
+// local time with --absolute
+func formatTimestamp(value string) string {
+	return utils.FormatTimestamp(value, time.Now(), absoluteTimes)
+}
+
+// formatTime is formatTimestamp for times kept in the state stores
+func formatTime(t time.Time) string {
+	return utils.FormatTime(t, time.Now(), absoluteTimes)
 }
 
 func pluralize(count int) string {
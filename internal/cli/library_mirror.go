@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/playlistsync"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// likedSongsSource stands in for a playlist ID when the source of a sync is
+// the saved tracks, which have none
+const likedSongsSource = "library:tracks"
+
+var (
+	libraryMirrorName   string
+	libraryMirrorPublic bool
+	libraryMirrorDryRun bool
+	libraryMirrorForce  bool
+)
+
+var libraryToPlaylistCmd = &cobra.Command{
+	Use:   "to-playlist",
+	Short: "Mirror your Liked Songs into a regular playlist",
+	Long: `Copy your saved tracks into a playlist of your own, newest first like
+Liked Songs, so they can be followed, shared or used anywhere a playlist
+can.
+
+The playlist named by --name is created the first time. Later runs only
+add the tracks liked since and remove the ones no longer liked, so the
+rest keep their place and date added. When the only change is new likes,
+just those are read; nothing is requested beyond the newest saved track
+while neither side has changed since the last run. --force compares every
+track anyway.`,
+	Example: `  spotify-cli library to-playlist --name "Liked Songs Mirror"
+  spotify-cli library to-playlist --name "Liked Songs Mirror" --dry-run
+  spotify-cli library to-playlist --name "Everything I Like" --public`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLibraryToPlaylist(cmd.Flags().Changed("public"))
+	},
+}
+
+func init() {
+	libraryCmd.AddCommand(libraryToPlaylistCmd)
+	addQuietFlag(libraryToPlaylistCmd)
+
+	libraryToPlaylistCmd.Flags().StringVar(&libraryMirrorName, "name", "Liked Songs Mirror", "Name of the mirror playlist")
+	libraryToPlaylistCmd.Flags().BoolVar(&libraryMirrorPublic, "public", false, "Make the mirror playlist public")
+	libraryToPlaylistCmd.Flags().BoolVar(&libraryMirrorDryRun, "dry-run", false, "Show the changes without making them")
+	libraryToPlaylistCmd.Flags().BoolVar(&libraryMirrorForce, "force", false, "Compare the tracks even if nothing has changed since the last run")
+}
+
+func runLibraryToPlaylist(publicChanged bool) error {
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+
+	librarySnapshot, total, err := savedTracksVersion(ctx, spotifyClient)
+	if err != nil {
+		return err
+	}
+
+	backend := &queryBackend{spotifyClient: spotifyClient, validator: api.NewValidator()}
+	mirror, err := backend.findPlaylist(ctx, libraryMirrorName, true)
+	if err != nil {
+		return err
+	}
+
+	if mirror != nil && !libraryMirrorForce {
+		last, err := playlistsync.Last(dir, likedSongsSource, mirror.ID)
+		if err != nil {
+			return err
+		}
+		if last.InSync(librarySnapshot, mirror.SnapshotID, false) {
+			fmt.Printf("Already up to date: nothing has changed since %s\n", last.SyncedAt.Local().Format("2006-01-02 15:04"))
+			return nil
+		}
+		if done, err := appendNewLikes(ctx, spotifyClient, dir, mirror, last, total, librarySnapshot); done || err != nil {
+			return err
+		}
+	}
+
+	saved, err := spotifyClient.Library.SavedTracksPager(&api.PaginationOptions{Limit: 50}).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+	var uris []string
+	var tracks []playlistExportTrack
+	for _, item := range saved {
+		if item.Track.IsLocal || item.Track.URI == "" {
+			continue
+		}
+		uris = append(uris, item.Track.URI)
+		artists := make([]string, 0, len(item.Track.Artists))
+		for _, artist := range item.Track.Artists {
+			artists = append(artists, artist.Name)
+		}
+		track := playlistExportTrack{
+			Name:    item.Track.Name,
+			Artists: artists,
+			URI:     item.Track.URI,
+			AddedAt: item.AddedAt,
+		}
+		if item.Track.Album != nil {
+			track.Album = item.Track.Album.Name
+		}
+		tracks = append(tracks, track)
+	}
+
+	var targetSnapshot string
+	if mirror == nil {
+		if libraryMirrorDryRun {
+			fmt.Printf("Dry run: would create %q with %d track%s\n", libraryMirrorName, len(uris), pluralize(len(uris)))
+			return nil
+		}
+
+		mirror, err = createPlaylistWithTracks(spotifyClient, libraryMirrorName, "Your Liked Songs, mirrored by spotify-cli", uris)
+		if err != nil {
+			return err
+		}
+		if targetSnapshot, err = playlistSnapshotID(ctx, spotifyClient, mirror.ID); err != nil {
+			return fmt.Errorf("failed to read mirror playlist: %w", err)
+		}
+		utils.PrintSuccess("Created %q with %d track%s", libraryMirrorName, len(uris), pluralize(len(uris)))
+		printSnapshotID(targetSnapshot)
+	} else {
+		items, targetTracks, err := fetchSyncTarget(ctx, spotifyClient, mirror.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read mirror playlist: %w", err)
+		}
+
+		plan := playlistsync.Compute(uris, items, false)
+		if libraryMirrorDryRun {
+			printSyncPlan(plan, tracks, targetTracks)
+			return nil
+		}
+
+		targetSnapshot = mirror.SnapshotID
+		if plan.Empty() {
+			fmt.Println("Already up to date")
+		} else {
+			// Tracks added to the mirror by hand are removed, so keep them
+			backup := ""
+			if len(plan.Remove) > 0 {
+				if backup, err = backupPlaylist(ctx, spotifyClient, mirror.ID); err != nil {
+					return err
+				}
+			}
+			targetSnapshot, err = applySyncPlan(ctx, spotifyClient, mirror.ID, targetSnapshot, plan)
+			if err != nil {
+				return err
+			}
+			utils.PrintSuccess("Updated %q: %d added, %d removed", mirror.Name, plan.Added(), len(plan.Remove))
+			printSnapshotID(targetSnapshot)
+			printUndoHint(mirror.ID, backup)
+		}
+	}
+
+	// Only touch visibility when asked, so a mirror made public by hand stays so
+	if publicChanged && mirror.Public != libraryMirrorPublic {
+		public := libraryMirrorPublic
+		if err := spotifyClient.Playlists.UpdatePlaylist(ctx, mirror.ID, &spotify.UpdatePlaylistRequest{Public: &public}); err != nil {
+			return fmt.Errorf("failed to update playlist: %w", err)
+		}
+		if targetSnapshot, err = playlistSnapshotID(ctx, spotifyClient, mirror.ID); err != nil {
+			return fmt.Errorf("failed to read mirror playlist: %w", err)
+		}
+	}
+	fmt.Printf("Playlist ID: %s\n", mirror.ID)
+
+	return playlistsync.Record(dir, playlistsync.Pair{
+		Source:         likedSongsSource,
+		Target:         mirror.ID,
+		SourceSnapshot: librarySnapshot,
+		TargetSnapshot: targetSnapshot,
+		SyncedAt:       time.Now().UTC(),
+	})
+}
+
+// appendNewLikes brings the mirror up to date by reading only the tracks
+// liked since the last run, when that is all that changed: the mirror is as
+// it was left, and the library grew by exactly the tracks liked since. It
+// reports whether it did so.
+func appendNewLikes(ctx context.Context, spotifyClient *client.SpotifyClient, dir *state.Dir, mirror *models.Playlist, last *playlistsync.Pair, total int, librarySnapshot string) (bool, error) {
+	if last == nil || last.TargetSnapshot != mirror.SnapshotID {
+		return false, nil
+	}
+	lastTotal, lastAddedAt, ok := strings.Cut(last.SourceSnapshot, "@")
+	watermark, err := time.Parse(time.RFC3339, lastAddedAt)
+	if !ok || err != nil {
+		return false, nil
+	}
+	previous, err := strconv.Atoi(lastTotal)
+	if err != nil {
+		return false, nil
+	}
+
+	liked, err := spotifyClient.Library.GetSavedTracksSince(ctx, watermark)
+	if err != nil {
+		return false, fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+	if len(liked) != total-previous {
+		// Something was unliked too, so a full comparison is needed
+		return false, nil
+	}
+
+	var uris []string
+	for _, item := range liked {
+		if !item.Track.IsLocal && item.Track.URI != "" {
+			uris = append(uris, item.Track.URI)
+		}
+	}
+	if libraryMirrorDryRun {
+		fmt.Printf("Dry run: would add %d newly liked track%s to %q\n", len(uris), pluralize(len(uris)), mirror.Name)
+		return true, nil
+	}
+
+	// New likes go first, as in Liked Songs
+	var plan playlistsync.Plan
+	for start := 0; start < len(uris); start += playlistsync.BatchSize {
+		end := min(start+playlistsync.BatchSize, len(uris))
+		plan.Insert = append(plan.Insert, playlistsync.Insertion{Position: start, URIs: uris[start:end]})
+	}
+	snapshotID, err := applySyncPlan(ctx, spotifyClient, mirror.ID, mirror.SnapshotID, plan)
+	if err != nil {
+		return false, err
+	}
+	utils.PrintSuccess("Updated %q: %d added, 0 removed", mirror.Name, len(uris))
+	fmt.Printf("Playlist ID: %s\n", mirror.ID)
+	printSnapshotID(snapshotID)
+
+	return true, playlistsync.Record(dir, playlistsync.Pair{
+		Source:         likedSongsSource,
+		Target:         mirror.ID,
+		SourceSnapshot: librarySnapshot,
+		TargetSnapshot: snapshotID,
+		SyncedAt:       time.Now().UTC(),
+	})
+}
+
+// savedTracksVersion stands in for the snapshot ID saved tracks lack: the
+// count and newest addition change whenever a track is liked or unliked. It
+// also returns the count.
+func savedTracksVersion(ctx context.Context, spotifyClient *client.SpotifyClient) (string, int, error) {
+	newest, _, err := spotifyClient.Library.GetSavedTracks(ctx, &api.PaginationOptions{Limit: 1})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+	var newestAddedAt string
+	if len(newest.Items) > 0 {
+		newestAddedAt = newest.Items[0].AddedAt
+	}
+	return fmt.Sprintf("%d@%s", newest.Total, newestAddedAt), newest.Total, nil
+}
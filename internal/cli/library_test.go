@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/playcounts"
+)
+
+func TestSortSavedTracks(t *testing.T) {
+	saved := func(uri string) models.SavedTrack {
+		return models.SavedTrack{Track: models.Track{URI: uri}}
+	}
+	items := []models.SavedTrack{saved("spotify:track:new"), saved("spotify:track:mid"), saved("spotify:track:old")}
+	now := time.Now()
+	counts := map[string]playcounts.Count{
+		"spotify:track:mid": {Plays: 5, LastPlayed: now.Add(-time.Hour)},
+		"spotify:track:old": {Plays: 2, LastPlayed: now},
+	}
+
+	uris := func(items []models.SavedTrack) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Track.URI)
+		}
+		return out
+	}
+
+	for _, tt := range []struct {
+		by       string
+		minPlays int
+		want     []string
+	}{
+		{"playcount", 0, []string{"spotify:track:mid", "spotify:track:old", "spotify:track:new"}},
+		{"lastplayed", 0, []string{"spotify:track:old", "spotify:track:mid", "spotify:track:new"}},
+		{"added", 1, []string{"spotify:track:mid", "spotify:track:old"}},
+		{"playcount", 3, []string{"spotify:track:mid"}},
+	} {
+		got := uris(sortSavedTracks(items, counts, tt.by, tt.minPlays))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s/%d: got %v, want %v", tt.by, tt.minPlays, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s/%d: got %v, want %v", tt.by, tt.minPlays, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -8,14 +8,15 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/client"
 	"github.com/bambithedeer/spotify-api/internal/config"
+	"github.com/bambithedeer/spotify-api/internal/estimate"
 	"github.com/bambithedeer/spotify-api/internal/integration"
 	"github.com/bambithedeer/spotify-api/internal/lidarr"
 	"github.com/bambithedeer/spotify-api/internal/logger"
-	"github.com/bambithedeer/spotify-api/internal/models"
 	"github.com/bambithedeer/spotify-api/internal/musicbrainz"
 	"github.com/bambithedeer/spotify-api/internal/spotify"
 	"github.com/spf13/cobra"
@@ -24,7 +25,12 @@ import (
 var lidarrCmd = &cobra.Command{
 	Use:   "lidarr",
 	Short: "Lidarr integration commands",
-	Long:  "Commands for integrating Spotify data with Lidarr music management",
+	Long: `Commands for integrating Spotify data with Lidarr music management.
+
+The import commands save their progress as they go. One that stops partway,
+from a network outage or Ctrl-C, prints a resume token or mentions it on the
+next run; the same command with --resume <token> retries only the artists or
+albums that did not finish.`,
 }
 
 var lidarrAddArtistsCmd = &cobra.Command{
@@ -48,6 +54,33 @@ var lidarrImportSavedCmd = &cobra.Command{
 	RunE:  runLidarrImportSaved,
 }
 
+var lidarrImportRelatedCmd = &cobra.Command{
+	Use:   "import-related <artist-id>...",
+	Short: "Import artists related to the given artists to Lidarr",
+	Long: `Discover artists by expanding Spotify's related artists of the given
+artists, up to --depth levels away, and add them to Lidarr. The given
+artists themselves are not added. An artist reached from several of them
+is added once, and the best-connected are added first when --max limits
+how many.`,
+	Example: `  spotify-cli lidarr import-related 4Z8W4fKeB5YxbusRsdQVPb
+  spotify-cli lidarr import-related 4Z8W4fKeB5YxbusRsdQVPb spotify:artist:0oSGxfWSnnOXhD2fKuz2Gy --depth 2 --max 25`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runLidarrImportRelated,
+}
+
+var lidarrImportTopCmd = &cobra.Command{
+	Use:   "import-top-artists",
+	Short: "Import your top artists to Lidarr",
+	Long: `Add the artists you listen to most, as ranked by Spotify for --time-range,
+to Lidarr. Spotify ranks up to 1000 artists; --limit takes the top ones.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli lidarr import-top-artists --limit 100
+  spotify-cli lidarr import-top-artists --time-range short_term --limit 20`,
+	Args: cobra.NoArgs,
+	RunE: runLidarrImportTop,
+}
+
 var lidarrTestCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Test Lidarr connection and configuration",
@@ -67,25 +100,41 @@ func init() {
 	lidarrAddArtistsCmd.Flags().StringP("file", "f", "", "File containing list of artist names (one per line)")
 	lidarrAddArtistsCmd.Flags().StringSliceP("artists", "a", nil, "Artist names to add (can be used multiple times)")
 	lidarrAddArtistsCmd.Flags().BoolP("interactive", "i", false, "Interactive mode to enter artists manually")
-	lidarrAddArtistsCmd.Flags().IntP("concurrency", "c", 3, "Number of concurrent requests (1-10)")
+	lidarrAddArtistsCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
 
 	// Add flags for import-from-playlist command
 	lidarrImportPlaylistCmd.Flags().StringP("playlist-id", "p", "", "Spotify playlist ID")
 	lidarrImportPlaylistCmd.Flags().IntP("limit", "l", 0, "Limit number of tracks to process (0 = all)")
-	lidarrImportPlaylistCmd.Flags().IntP("concurrency", "c", 3, "Number of concurrent requests (1-10)")
+	lidarrImportPlaylistCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
 
 	// Add flags for import-saved-artists command
 	lidarrImportSavedCmd.Flags().IntP("limit", "l", 50, "Limit number of saved tracks to process")
-	lidarrImportSavedCmd.Flags().IntP("concurrency", "c", 3, "Number of concurrent requests (1-10)")
+	lidarrImportSavedCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+
+	// Add flags for import-related command
+	lidarrImportRelatedCmd.Flags().Int("depth", 1, fmt.Sprintf("How many levels of related artists to expand (1-%d)", spotify.MaxRelatedDepth))
+	lidarrImportRelatedCmd.Flags().Int("max", 50, "Maximum number of artists to add (0 = all found)")
+	lidarrImportRelatedCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+
+	// Add flags for import-top-artists command
+	lidarrImportTopCmd.Flags().String("time-range", "medium_term", "Time range of the ranking (short_term, medium_term, long_term)")
+	lidarrImportTopCmd.Flags().IntP("limit", "l", 50, fmt.Sprintf("Number of top artists to import (up to %d)", spotify.TopItemsCap))
+	lidarrImportTopCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+
+	for _, cmd := range []*cobra.Command{lidarrAddArtistsCmd, lidarrImportPlaylistCmd, lidarrImportSavedCmd, lidarrImportRelatedCmd, lidarrImportTopCmd, lidarrAddAlbumsCmd, lidarrImportSavedAlbumsCmd} {
+		addEstimateFlags(cmd)
+		addLidarrDryRunFlag(cmd)
+		addResumeFlag(cmd)
+	}
 
 	// Override Lidarr config via flags
-	for _, cmd := range []*cobra.Command{lidarrAddArtistsCmd, lidarrImportPlaylistCmd, lidarrImportSavedCmd, lidarrTestCmd} {
+	for _, cmd := range []*cobra.Command{lidarrAddArtistsCmd, lidarrImportPlaylistCmd, lidarrImportSavedCmd, lidarrImportRelatedCmd, lidarrImportTopCmd, lidarrAddAlbumsCmd, lidarrImportSavedAlbumsCmd, lidarrSyncCmd, lidarrTestCmd} {
 		cmd.Flags().String("lidarr-url", "", "Lidarr URL (overrides config)")
 		cmd.Flags().String("api-key", "", "Lidarr API key (overrides config)")
 		cmd.Flags().String("root-folder", "", "Root folder path (overrides config)")
 		cmd.Flags().Int("quality-profile", 0, "Quality profile ID (overrides config)")
 		cmd.Flags().Int("metadata-profile", 0, "Metadata profile ID (overrides config)")
-		cmd.Flags().Bool("monitor", true, "Monitor added artists")
+		cmd.Flags().Bool("monitor", true, "Monitor added artists or albums")
 		cmd.Flags().Bool("search", true, "Search for missing albums after adding")
 	}
 
@@ -93,6 +142,8 @@ func init() {
 	lidarrCmd.AddCommand(lidarrAddArtistsCmd)
 	lidarrCmd.AddCommand(lidarrImportPlaylistCmd)
 	lidarrCmd.AddCommand(lidarrImportSavedCmd)
+	lidarrCmd.AddCommand(lidarrImportRelatedCmd)
+	lidarrCmd.AddCommand(lidarrImportTopCmd)
 	lidarrCmd.AddCommand(lidarrTestCmd)
 	lidarrCmd.AddCommand(lidarrConfigCmd)
 
@@ -100,6 +151,20 @@ func init() {
 	rootCmd.AddCommand(lidarrCmd)
 }
 
+// Rates used to estimate Lidarr imports. MusicBrainz allows one request a
+// second however many run at once; Lidarr is local and not rate limited.
+var (
+	musicBrainzRate = estimate.Rate{Service: "MusicBrainz", Burst: 1, Interval: musicbrainz.RateLimit, Latency: 500 * time.Millisecond}
+	lidarrRate      = estimate.Rate{Service: "Lidarr", Latency: 300 * time.Millisecond}
+)
+
+// estimateArtistImport adds the per-artist cost of adding artists to Lidarr:
+// one MusicBrainz search, then a Lidarr lookup and add
+func estimateArtistImport(e *estimate.Estimate, artists int, approximate bool) {
+	e.Calls(musicBrainzRate, "look up artists", artists).Approximate = approximate
+	e.Calls(lidarrRate, "look up and add artists", artists*2).Approximate = approximate
+}
+
 func createLidarrIntegration(cmd *cobra.Command) (*integration.LidarrIntegration, error) {
 	cfg, err := config.Load()
 	if err != nil {
@@ -128,6 +193,7 @@ func createLidarrIntegration(cmd *cobra.Command) (*integration.LidarrIntegration
 	if search, _ := cmd.Flags().GetBool("search"); cmd.Flags().Changed("search") {
 		cfg.Lidarr.SearchForMissing = search
 	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 	// Create clients
 	lidarrClient := lidarr.NewClient(lidarr.Config{
@@ -136,6 +202,7 @@ func createLidarrIntegration(cmd *cobra.Command) (*integration.LidarrIntegration
 	})
 
 	mbClient := musicbrainz.NewClient()
+	mbClient.SetCache(musicBrainzCache())
 
 	log := logger.NewLogger(&logger.Config{
 		Level:  cfg.Logging.Level,
@@ -149,6 +216,7 @@ func createLidarrIntegration(cmd *cobra.Command) (*integration.LidarrIntegration
 		MetadataProfileID: cfg.Lidarr.MetadataProfileID,
 		Monitor:           cfg.Lidarr.Monitor,
 		SearchForMissing:  cfg.Lidarr.SearchForMissing,
+		DryRun:            dryRun,
 	}
 
 	return integration.NewLidarrIntegration(lidarrClient, mbClient, integrationConfig, log), nil
@@ -172,7 +240,22 @@ func createSpotifyClientWithServices(cfg *config.Config) (*spotify.PlaylistsServ
 	return playlistsService, libraryService, nil
 }
 
+func createSpotifyArtistsService(cfg *config.Config) (*spotify.ArtistsService, error) {
+	spotifyClient := client.NewClient(cfg.Spotify.ClientID, cfg.Spotify.ClientSecret, cfg.Spotify.RedirectURI)
+
+	// Related artists are public, so client credentials are enough
+	if err := spotifyClient.AuthenticateClientCredentials(); err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	return spotify.NewArtistsService(api.NewRequestBuilder(spotifyClient)), nil
+}
+
 func runLidarrAddArtists(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, false)
+	}
+
 	integration, err := createLidarrIntegration(cmd)
 	if err != nil {
 		return err
@@ -214,27 +297,31 @@ func runLidarrAddArtists(cmd *cobra.Command, args []string) error {
 	// Remove duplicates
 	artistNames = removeDuplicates(artistNames)
 
-	fmt.Printf("Adding %d artists to Lidarr...\n", len(artistNames))
-
 	concurrency, _ := cmd.Flags().GetInt("concurrency")
 	if concurrency < 1 || concurrency > 10 {
 		concurrency = 3
 	}
 
-	// Process artists
-	result := integration.AddArtistsBatch(artistNames, concurrency)
-
-	// Print results
-	printBatchResults(result)
+	cost := estimate.New(concurrency)
+	estimateArtistImport(cost, len(artistNames), false)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
 
-	if result.Failures > 0 {
-		return fmt.Errorf("%d artists failed to add", result.Failures)
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Looking up %d artists (dry run, Lidarr will not be changed)...\n", len(artistNames))
+	} else {
+		fmt.Printf("Adding %d artists to Lidarr...\n", len(artistNames))
 	}
 
-	return nil
+	return runArtistBatch(integration, artistNames, concurrency, startCheckpoint(cmd, artistNames))
 }
 
 func runLidarrImportPlaylist(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, false)
+	}
+
 	playlistID, _ := cmd.Flags().GetString("playlist-id")
 	if playlistID == "" {
 		return fmt.Errorf("playlist ID is required (use --playlist-id)")
@@ -252,58 +339,50 @@ func runLidarrImportPlaylist(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create Spotify client: %w", err)
 	}
 
-	fmt.Printf("Fetching playlist tracks from Spotify...\n")
-
-	// Get playlist tracks with pagination
 	ctx := context.Background()
 	limit, _ := cmd.Flags().GetInt("limit")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 || concurrency > 10 {
+		concurrency = 3
+	}
 
-	var allTracks []models.PlaylistTrack
-	offset := 0
-	pageLimit := 50 // Spotify API max per request
-
-	for {
-		options := &spotify.PlaylistTracksOptions{
-			Limit:  pageLimit,
-			Offset: offset,
-		}
-
-		paging, _, err := playlistsService.GetPlaylistTracks(ctx, playlistID, options)
-		if err != nil {
-			return fmt.Errorf("failed to get playlist tracks: %w", err)
-		}
-
-
-		allTracks = append(allTracks, paging.Items...)
+	// Size the job before fetching anything large; every track is assumed
+	// to bring one new artist until the real count is known
+	playlist, err := playlistsService.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "tracks.total"})
+	if err != nil {
+		return fmt.Errorf("failed to get playlist: %w", err)
+	}
+	trackCount := playlist.Tracks.Total
+	if limit > 0 && limit < trackCount {
+		trackCount = limit
+	}
 
-		// Check if we've hit our limit or reached the end
-		if limit > 0 && len(allTracks) >= limit {
-			allTracks = allTracks[:limit]
-			break
-		}
+	cost := estimate.New(concurrency)
+	cost.Pages(estimate.Spotify, "fetch playlist tracks", trackCount, 50)
+	estimateArtistImport(cost, trackCount, true)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
 
-		if paging.Next == "" || len(paging.Items) == 0 {
-			break
-		}
+	fmt.Printf("Fetching playlist tracks from Spotify...\n")
 
-		offset += pageLimit
+	// Get playlist tracks with pagination
+	allTracks, err := playlistsService.PlaylistTracksPager(playlistID, &spotify.PlaylistTracksOptions{Limit: 50}).
+		WithMaxItems(limit).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
 	}
 
 	// Extract unique artists
 	artistSet := make(map[string]bool)
 	for _, playlistTrack := range allTracks {
-		// Handle JSON track data (map[string]interface{})
-		if trackMap, ok := playlistTrack.Track.(map[string]interface{}); ok {
-			if artistsData, exists := trackMap["artists"]; exists {
-				if artistsSlice, ok := artistsData.([]interface{}); ok {
-					for _, artistData := range artistsSlice {
-						if artistMap, ok := artistData.(map[string]interface{}); ok {
-							if name, ok := artistMap["name"].(string); ok && name != "" {
-								artistSet[name] = true
-							}
-						}
-					}
-				}
+		if !playlistTrack.Track.IsTrack() {
+			continue
+		}
+		for _, artist := range playlistTrack.Track.Track.Artists {
+			if artist.Name != "" {
+				artistSet[artist.Name] = true
 			}
 		}
 	}
@@ -319,6 +398,13 @@ func runLidarrImportPlaylist(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no artists found in playlist")
 	}
 
+	// Check the limit again now that the number of artists is known
+	cost = estimate.New(concurrency)
+	estimateArtistImport(cost, len(artistNames), false)
+	if _, err := reviewEstimate(cmd, cost); err != nil {
+		return err
+	}
+
 	// Create Lidarr integration
 	integration, err := createLidarrIntegration(cmd)
 	if err != nil {
@@ -331,25 +417,14 @@ func runLidarrImportPlaylist(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
-	concurrency, _ := cmd.Flags().GetInt("concurrency")
-	if concurrency < 1 || concurrency > 10 {
-		concurrency = 3
-	}
-
-	// Process artists
-	result := integration.AddArtistsBatch(artistNames, concurrency)
-
-	// Print results
-	printBatchResults(result)
-
-	if result.Failures > 0 {
-		return fmt.Errorf("%d artists failed to add", result.Failures)
-	}
-
-	return nil
+	return runArtistBatch(integration, artistNames, concurrency, startCheckpoint(cmd, artistNames))
 }
 
 func runLidarrImportSaved(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, false)
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -362,40 +437,39 @@ func runLidarrImportSaved(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create Spotify client: %w", err)
 	}
 
-	fmt.Printf("Fetching saved tracks from Spotify...\n")
-
-	// Get saved tracks with pagination
 	ctx := context.Background()
 	limit, _ := cmd.Flags().GetInt("limit")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 || concurrency > 10 {
+		concurrency = 3
+	}
 
-	var allSavedTracks []models.SavedTrack
-	offset := 0
-	pageLimit := 50 // Spotify API max per request
-
-	for {
-		options := &api.PaginationOptions{
-			Limit:  pageLimit,
-			Offset: offset,
-		}
-
-		paging, _, err := libraryService.GetSavedTracks(ctx, options)
-		if err != nil {
-			return fmt.Errorf("failed to get saved tracks: %w", err)
-		}
-
-		allSavedTracks = append(allSavedTracks, paging.Items...)
+	// Size the job from the library total, assuming one new artist per track
+	// until the real count is known
+	firstPage, _, err := libraryService.GetSavedTracks(ctx, &api.PaginationOptions{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to get saved tracks: %w", err)
+	}
+	trackCount := firstPage.Total
+	if limit > 0 && limit < trackCount {
+		trackCount = limit
+	}
 
-		// Check if we've hit our limit or reached the end
-		if limit > 0 && len(allSavedTracks) >= limit {
-			allSavedTracks = allSavedTracks[:limit]
-			break
-		}
+	cost := estimate.New(concurrency)
+	cost.Pages(estimate.Spotify, "fetch saved tracks", trackCount, 50)
+	estimateArtistImport(cost, trackCount, true)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
 
-		if paging.Next == "" || len(paging.Items) == 0 {
-			break
-		}
+	fmt.Printf("Fetching saved tracks from Spotify...\n")
 
-		offset += pageLimit
+	// Get saved tracks with pagination
+	allSavedTracks, err := libraryService.SavedTracksPager(&api.PaginationOptions{Limit: 50}).
+		WithMaxItems(limit).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get saved tracks: %w", err)
 	}
 
 	// Extract unique artists
@@ -419,6 +493,13 @@ func runLidarrImportSaved(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no artists found in saved tracks")
 	}
 
+	// Check the limit again now that the number of artists is known
+	cost = estimate.New(concurrency)
+	estimateArtistImport(cost, len(artistNames), false)
+	if _, err := reviewEstimate(cmd, cost); err != nil {
+		return err
+	}
+
 	// Create Lidarr integration
 	integration, err := createLidarrIntegration(cmd)
 	if err != nil {
@@ -431,22 +512,7 @@ func runLidarrImportSaved(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
-	concurrency, _ := cmd.Flags().GetInt("concurrency")
-	if concurrency < 1 || concurrency > 10 {
-		concurrency = 3
-	}
-
-	// Process artists
-	result := integration.AddArtistsBatch(artistNames, concurrency)
-
-	// Print results
-	printBatchResults(result)
-
-	if result.Failures > 0 {
-		return fmt.Errorf("%d artists failed to add", result.Failures)
-	}
-
-	return nil
+	return runArtistBatch(integration, artistNames, concurrency, startCheckpoint(cmd, artistNames))
 }
 
 func runLidarrTest(cmd *cobra.Command, args []string) error {
@@ -612,6 +678,7 @@ func runLidarrConfig(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\n✅ Configuration saved to %s\n", configPath)
+	fmt.Printf("   API key saved to %s (readable only by you)\n", config.CredentialsPath())
 	return nil
 }
 
@@ -675,15 +742,28 @@ func removeDuplicates(artists []string) []string {
 }
 
 func printBatchResults(result *integration.BatchResult) {
+	if result.DryRun {
+		printLidarrPlan(artistPlan(result))
+		return
+	}
+
+	commandReport.Add("artists added", result.Successes)
+	commandReport.Add("artists existing", result.Existing)
+	commandReport.Add("artists failed", result.Failures)
 	fmt.Printf("\n📊 Results Summary:\n")
 	fmt.Printf("  Total: %d\n", result.Total)
 	fmt.Printf("  ✅ Successes: %d\n", result.Successes)
+	fmt.Printf("  ⏭️  Already in Lidarr: %d\n", result.Existing)
 	fmt.Printf("  ❌ Failures: %d\n", result.Failures)
+	if c := result.Concurrency; c.Decreases > 0 {
+		fmt.Printf("  ⏬ Concurrency reduced %d time%s (lowest %d of %d) after %d overloaded request%s\n",
+			c.Decreases, pluralize(c.Decreases), c.Lowest, c.Max, c.Overloads, pluralize(c.Overloads))
+	}
 
 	if result.Failures > 0 {
 		fmt.Println("\n❌ Failed Artists:")
 		for _, artistResult := range result.Results {
-			if !artistResult.Success {
+			if !artistResult.Success && !artistResult.AlreadyExists {
 				fmt.Printf("  - %s: %v\n", artistResult.SpotifyName, artistResult.Error)
 			}
 		}
@@ -698,3 +778,147 @@ func printBatchResults(result *integration.BatchResult) {
 		}
 	}
 }
+
+func runLidarrImportRelated(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, false)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	depth, _ := cmd.Flags().GetInt("depth")
+	maxArtists, _ := cmd.Flags().GetInt("max")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 || concurrency > 10 {
+		concurrency = 3
+	}
+
+	artistsService, err := createSpotifyArtistsService(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	// The first level costs one request per seed, and each level after it
+	// at most spotify.RelatedFrontier more
+	expansions := len(args) + (depth-1)*spotify.RelatedFrontier
+	cost := estimate.New(concurrency)
+	cost.Calls(estimate.Spotify, "fetch related artists", expansions).Approximate = depth > 1
+	guess := maxArtists
+	if guess == 0 {
+		guess = expansions * 20
+	}
+	estimateArtistImport(cost, guess, true)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
+
+	fmt.Printf("Expanding related artists of %d artist%s, %d level%s deep...\n", len(args), pluralize(len(args)), depth, pluralize(depth))
+
+	related, err := artistsService.GetRelatedForMany(context.Background(), args, depth)
+	if err != nil {
+		return fmt.Errorf("failed to get related artists: %w", err)
+	}
+	if len(related) == 0 {
+		return fmt.Errorf("no related artists found")
+	}
+
+	found := len(related)
+	if maxArtists > 0 && len(related) > maxArtists {
+		related = related[:maxArtists]
+	}
+
+	var artistNames []string
+	for _, artist := range related {
+		if artist.Artist.Name != "" {
+			artistNames = append(artistNames, artist.Artist.Name)
+		}
+	}
+	fmt.Printf("Found %d related artists, adding %d\n", found, len(artistNames))
+
+	cost = estimate.New(concurrency)
+	estimateArtistImport(cost, len(artistNames), false)
+	if _, err := reviewEstimate(cmd, cost); err != nil {
+		return err
+	}
+
+	integration, err := createLidarrIntegration(cmd)
+	if err != nil {
+		return err
+	}
+	defer integration.Close()
+
+	if err := integration.ValidateConfig(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return runArtistBatch(integration, artistNames, concurrency, startCheckpoint(cmd, artistNames))
+}
+
+func runLidarrImportTop(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, false)
+	}
+
+	timeRange, _ := cmd.Flags().GetString("time-range")
+	limit, _ := cmd.Flags().GetInt("limit")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 || concurrency > 10 {
+		concurrency = 3
+	}
+	if limit < 1 || limit > spotify.TopItemsCap {
+		return fmt.Errorf("limit must be between 1 and %d", spotify.TopItemsCap)
+	}
+
+	// Top artists are personal, so this needs the logged-in user rather than
+	// the client credentials the other imports use
+	spotifyClient, err := newUserClient("your top artists")
+	if err != nil {
+		return err
+	}
+
+	cost := estimate.New(concurrency)
+	cost.Pages(estimate.Spotify, "fetch top artists", limit, 50)
+	estimateArtistImport(cost, limit, true)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
+
+	fmt.Printf("Fetching top artists from Spotify...\n")
+
+	top, err := spotifyClient.Users.GetAllTopArtists(GetCommandContext(), timeRange, limit)
+	if err != nil {
+		return fmt.Errorf("failed to get top artists: %w", err)
+	}
+	if len(top.Items) == 0 {
+		return fmt.Errorf("no top artists found for %s", timeRange)
+	}
+
+	var artistNames []string
+	for _, artist := range top.Items {
+		if artist.Name != "" {
+			artistNames = append(artistNames, artist.Name)
+		}
+	}
+	fmt.Printf("Found %d of your %d top artists\n", len(artistNames), top.Total)
+
+	cost = estimate.New(concurrency)
+	estimateArtistImport(cost, len(artistNames), false)
+	if _, err := reviewEstimate(cmd, cost); err != nil {
+		return err
+	}
+
+	integration, err := createLidarrIntegration(cmd)
+	if err != nil {
+		return err
+	}
+	defer integration.Close()
+
+	if err := integration.ValidateConfig(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	return runArtistBatch(integration, artistNames, concurrency, startCheckpoint(cmd, artistNames))
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/estimate"
+	"github.com/bambithedeer/spotify-api/internal/integration"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var lidarrAddAlbumsCmd = &cobra.Command{
+	Use:   "add-albums",
+	Short: "Add specific albums to Lidarr",
+	Long: `Add albums to Lidarr by looking up their release group in MusicBrainz.
+Albums are given as "Artist - Album". An album's artist is added along with
+it if Lidarr does not have them, but monitors no other albums, so only the
+albums asked for are wanted. --monitor=false adds them unmonitored.`,
+	Example: `  spotify-cli lidarr add-albums --albums "Pink Floyd - Animals" --albums "Radiohead - Kid A"
+  spotify-cli lidarr add-albums --file albums.txt --monitor=false`,
+	Args: cobra.NoArgs,
+	RunE: runLidarrAddAlbums,
+}
+
+var lidarrImportSavedAlbumsCmd = &cobra.Command{
+	Use:   "import-saved-albums",
+	Short: "Import your saved albums to Lidarr",
+	Long: `Add the albums saved in your Spotify library to Lidarr, each album on its
+own rather than the whole discography of its artist. Edition suffixes such
+as "(Deluxe Edition)" are dropped before the MusicBrainz lookup.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli lidarr import-saved-albums
+  spotify-cli lidarr import-saved-albums --limit 20 --search=false`,
+	Args: cobra.NoArgs,
+	RunE: runLidarrImportSavedAlbums,
+}
+
+func init() {
+	lidarrAddAlbumsCmd.Flags().StringP("file", "f", "", `File containing albums as "Artist - Album" (one per line)`)
+	lidarrAddAlbumsCmd.Flags().StringArrayP("albums", "a", nil, `Albums to add as "Artist - Album" (can be used multiple times)`)
+	lidarrAddAlbumsCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+
+	lidarrImportSavedAlbumsCmd.Flags().IntP("limit", "l", 0, "Limit number of saved albums to import (0 = all)")
+	lidarrImportSavedAlbumsCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+
+	lidarrCmd.AddCommand(lidarrAddAlbumsCmd)
+	lidarrCmd.AddCommand(lidarrImportSavedAlbumsCmd)
+}
+
+// estimateAlbumImport adds the per-album cost of adding albums to Lidarr:
+// one MusicBrainz search, then a Lidarr lookup and add
+func estimateAlbumImport(e *estimate.Estimate, albums int, approximate bool) {
+	e.Calls(musicBrainzRate, "look up albums", albums).Approximate = approximate
+	e.Calls(lidarrRate, "look up and add albums", albums*2).Approximate = approximate
+}
+
+func runLidarrAddAlbums(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, true)
+	}
+
+	var lines []string
+	if file, _ := cmd.Flags().GetString("file"); file != "" {
+		fromFile, err := readArtistsFromFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read albums from file: %w", err)
+		}
+		lines = append(lines, fromFile...)
+	}
+	if albums, _ := cmd.Flags().GetStringArray("albums"); len(albums) > 0 {
+		lines = append(lines, albums...)
+	}
+	if len(lines) == 0 {
+		return fmt.Errorf("no albums specified. Use --file or --albums")
+	}
+
+	var requests []integration.AlbumRequest
+	for _, line := range removeDuplicates(lines) {
+		request, err := integration.ParseAlbumRequest(line)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, request)
+	}
+
+	cost := estimate.New(lidarrConcurrency(cmd))
+	estimateAlbumImport(cost, len(requests), false)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
+
+	return addAlbumsToLidarr(cmd, requests)
+}
+
+func runLidarrImportSavedAlbums(cmd *cobra.Command, args []string) error {
+	if token := resumeToken(cmd); token != "" {
+		return resumeLidarrBatch(cmd, token, true)
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	// Saved albums are personal, so this needs the logged-in user
+	spotifyClient, err := newUserClient("your saved albums")
+	if err != nil {
+		return err
+	}
+	ctx := GetCommandContext()
+
+	firstPage, _, err := spotifyClient.Library.GetSavedAlbums(ctx, &spotify.SavedAlbumsOptions{Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to get saved albums: %w", err)
+	}
+	albumCount := firstPage.Total
+	if limit > 0 && limit < albumCount {
+		albumCount = limit
+	}
+
+	cost := estimate.New(lidarrConcurrency(cmd))
+	cost.Pages(estimate.Spotify, "fetch saved albums", albumCount, 50)
+	estimateAlbumImport(cost, albumCount, false)
+	if stop, err := reviewEstimate(cmd, cost); stop || err != nil {
+		return err
+	}
+
+	fmt.Printf("Fetching saved albums from Spotify...\n")
+
+	saved, err := spotifyClient.Library.SavedAlbumsPager(nil).WithMaxItems(limit).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get saved albums: %w", err)
+	}
+
+	var requests []integration.AlbumRequest
+	for _, s := range saved {
+		if s.Album.Name == "" || len(s.Album.Artists) == 0 {
+			continue
+		}
+		requests = append(requests, integration.AlbumRequest{Artist: s.Album.Artists[0].Name, Title: s.Album.Name})
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("no saved albums found")
+	}
+	fmt.Printf("Found %d saved albums\n", len(requests))
+
+	return addAlbumsToLidarr(cmd, requests)
+}
+
+// lidarrConcurrency reads --concurrency, falling back to 3 when out of range
+func lidarrConcurrency(cmd *cobra.Command) int {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 || concurrency > 10 {
+		concurrency = 3
+	}
+	return concurrency
+}
+
+// addAlbumsToLidarr adds albums whose cost has been reviewed and prints how
+// it went
+func addAlbumsToLidarr(cmd *cobra.Command, requests []integration.AlbumRequest) error {
+	integration, err := createLidarrIntegration(cmd)
+	if err != nil {
+		return err
+	}
+	defer integration.Close()
+
+	if err := integration.ValidateConfig(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Looking up %d albums (dry run, Lidarr will not be changed)...\n", len(requests))
+	} else {
+		fmt.Printf("Adding %d albums to Lidarr...\n", len(requests))
+	}
+
+	items := make([]string, len(requests))
+	for i, request := range requests {
+		items[i] = albumItem(request)
+	}
+	return runAlbumBatch(integration, requests, lidarrConcurrency(cmd), startCheckpoint(cmd, items))
+}
+
+func printAlbumBatchResults(result *integration.AlbumBatchResult) {
+	if result.DryRun {
+		printLidarrPlan(albumPlan(result))
+		return
+	}
+
+	commandReport.Add("albums added", result.Successes)
+	commandReport.Add("albums existing", result.Existing)
+	commandReport.Add("albums failed", result.Failures)
+	fmt.Printf("\n📊 Results Summary:\n")
+	fmt.Printf("  Total: %d\n", result.Total)
+	fmt.Printf("  ✅ Successes: %d\n", result.Successes)
+	fmt.Printf("  ⏭️  Already in Lidarr: %d\n", result.Existing)
+	fmt.Printf("  ❌ Failures: %d\n", result.Failures)
+	if c := result.Concurrency; c.Decreases > 0 {
+		fmt.Printf("  ⏬ Concurrency reduced %d time%s (lowest %d of %d) after %d overloaded request%s\n",
+			c.Decreases, pluralize(c.Decreases), c.Lowest, c.Max, c.Overloads, pluralize(c.Overloads))
+	}
+
+	if result.Failures > 0 {
+		fmt.Println("\n❌ Failed Albums:")
+		for _, albumResult := range result.Results {
+			if !albumResult.Success && !albumResult.AlreadyExists {
+				fmt.Printf("  - %s: %v\n", albumResult.Request, albumResult.Error)
+			}
+		}
+	}
+
+	if result.Successes > 0 {
+		fmt.Println("\n✅ Successfully Added:")
+		for _, albumResult := range result.Results {
+			if albumResult.Success {
+				fmt.Printf("  - %s → %s - %s (MBID: %s)\n", albumResult.Request, albumResult.ArtistName, albumResult.AlbumTitle, albumResult.MBID)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/integration"
+	"github.com/spf13/cobra"
+)
+
+// Statuses of an item in a dry run
+const (
+	lidarrPlanAdd      = "add"
+	lidarrPlanExisting = "existing"
+	lidarrPlanFailed   = "failed"
+)
+
+// lidarrPlanItem is one artist or album of a dry run and what a real run
+// would do with it
+type lidarrPlanItem struct {
+	Request string `json:"request" yaml:"request"`
+	Match   string `json:"match,omitempty" yaml:"match,omitempty"`
+	MBID    string `json:"mbid,omitempty" yaml:"mbid,omitempty"`
+	Status  string `json:"status" yaml:"status"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func addLidarrDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Look items up in MusicBrainz and check Lidarr, but only list what would be added")
+}
+
+func planStatus(success, existing bool, err error) (string, string) {
+	switch {
+	case success:
+		return lidarrPlanAdd, ""
+	case existing:
+		return lidarrPlanExisting, ""
+	case err != nil:
+		return lidarrPlanFailed, err.Error()
+	}
+	return lidarrPlanFailed, ""
+}
+
+func artistPlan(result *integration.BatchResult) []lidarrPlanItem {
+	plan := make([]lidarrPlanItem, 0, len(result.Results))
+	for _, r := range result.Results {
+		item := lidarrPlanItem{Request: r.SpotifyName, Match: r.ArtistName, MBID: r.MBID}
+		item.Status, item.Error = planStatus(r.Success, r.AlreadyExists, r.Error)
+		plan = append(plan, item)
+	}
+	return plan
+}
+
+func albumPlan(result *integration.AlbumBatchResult) []lidarrPlanItem {
+	plan := make([]lidarrPlanItem, 0, len(result.Results))
+	for _, r := range result.Results {
+		item := lidarrPlanItem{Request: r.Request.String(), MBID: r.MBID}
+		if r.AlbumTitle != "" {
+			item.Match = fmt.Sprintf("%s - %s", r.ArtistName, r.AlbumTitle)
+		}
+		item.Status, item.Error = planStatus(r.Success, r.AlreadyExists, r.Error)
+		plan = append(plan, item)
+	}
+	return plan
+}
+
+// printLidarrPlan lists a dry run's items, those to be added first, as a
+// table or in the configured structured format
+func printLidarrPlan(plan []lidarrPlanItem) {
+	order := map[string]int{lidarrPlanAdd: 0, lidarrPlanExisting: 1, lidarrPlanFailed: 2}
+	sort.SliceStable(plan, func(i, j int) bool {
+		if order[plan[i].Status] != order[plan[j].Status] {
+			return order[plan[i].Status] < order[plan[j].Status]
+		}
+		return plan[i].Request < plan[j].Request
+	})
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		if err := utils.Output(plan); err != nil {
+			utils.PrintWarning("Failed to write the dry run: %v", err)
+		}
+		return
+	}
+
+	counts := make(map[string]int)
+	fmt.Printf("\n%-10s %-40s %-40s %s\n", "STATUS", "REQUESTED", "MUSICBRAINZ MATCH", "MBID")
+	for _, item := range plan {
+		counts[item.Status]++
+		match, mbid := item.Match, item.MBID
+		if item.Status == lidarrPlanFailed {
+			match, mbid = item.Error, ""
+		}
+		fmt.Printf("%-10s %-40s %-40s %s\n", item.Status, truncateString(item.Request, 38), truncateString(match, 38), mbid)
+	}
+	fmt.Printf("\nDry run: %d would be added, %d already in Lidarr, %d failed\n",
+		counts[lidarrPlanAdd], counts[lidarrPlanExisting], counts[lidarrPlanFailed])
+}
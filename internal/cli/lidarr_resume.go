@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/integration"
+	"github.com/spf13/cobra"
+)
+
+// albumItem is how an album is kept in a checkpoint. A tab cannot appear
+// in either name the way " - " can.
+func albumItem(request integration.AlbumRequest) string {
+	return request.Artist + "\t" + request.Title
+}
+
+func parseAlbumItem(item string) integration.AlbumRequest {
+	artist, title, _ := strings.Cut(item, "\t")
+	return integration.AlbumRequest{Artist: artist, Title: title}
+}
+
+// runArtistBatch adds artists to Lidarr, saving progress in cp as each one
+// finishes, and prints how it went
+func runArtistBatch(li *integration.LidarrIntegration, artistNames []string, concurrency int, cp *batchCheckpoint) error {
+	li.OnArtistResult(func(r integration.ArtistResult) {
+		if r.Success || r.AlreadyExists {
+			cp.done(r.SpotifyName)
+		}
+	})
+
+	result := li.AddArtistsBatch(artistNames, concurrency)
+	printBatchResults(result)
+	cp.finish(result.Failures)
+
+	if result.Failures > 0 {
+		return fmt.Errorf("%d artists failed to add", result.Failures)
+	}
+	return nil
+}
+
+// runAlbumBatch is runArtistBatch for albums
+func runAlbumBatch(li *integration.LidarrIntegration, requests []integration.AlbumRequest, concurrency int, cp *batchCheckpoint) error {
+	li.OnAlbumResult(func(r integration.AlbumResult) {
+		if r.Success || r.AlreadyExists {
+			cp.done(albumItem(r.Request))
+		}
+	})
+
+	result := li.AddAlbumsBatch(requests, concurrency)
+	printAlbumBatchResults(result)
+	cp.finish(result.Failures)
+
+	if result.Failures > 0 {
+		return fmt.Errorf("%d albums failed to add", result.Failures)
+	}
+	return nil
+}
+
+// resumeLidarrBatch carries on with the artists or albums a stopped run of
+// cmd left unfinished. Where they came from no longer matters, so Spotify
+// is not asked again.
+func resumeLidarrBatch(cmd *cobra.Command, token string, albums bool) error {
+	cp, remaining, err := loadCheckpoint(cmd, token)
+	if err != nil {
+		return err
+	}
+	if len(remaining) == 0 {
+		cp.finish(0)
+		fmt.Println("Nothing left to do")
+		return nil
+	}
+
+	li, err := createLidarrIntegration(cmd)
+	if err != nil {
+		return err
+	}
+	defer li.Close()
+
+	if err := li.ValidateConfig(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	if !albums {
+		return runArtistBatch(li, remaining, lidarrConcurrency(cmd), cp)
+	}
+	requests := make([]integration.AlbumRequest, len(remaining))
+	for i, item := range remaining {
+		requests[i] = parseAlbumItem(item)
+	}
+	return runAlbumBatch(li, requests, lidarrConcurrency(cmd), cp)
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/integration"
+	"github.com/bambithedeer/spotify-api/internal/lidarrsync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lidarrSyncWatch      bool
+	lidarrSyncArtists    bool
+	lidarrSyncAlbums     bool
+	lidarrSyncRetryAfter time.Duration
+)
+
+var lidarrSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Add newly followed artists and saved albums to Lidarr",
+	Long: `Add the artists you follow and the albums you have saved on Spotify to
+Lidarr, skipping whatever Lidarr already has and whatever an earlier sync
+dealt with. Only new items are looked up in MusicBrainz, so a sync that finds
+nothing new costs a few Spotify and Lidarr requests.
+
+Without --watch a single pass runs and the exit code reports how it went,
+which suits cron. With --watch the sync repeats every --interval. Items that
+fail to add are tried again on the first sync after --retry-after.
+--dry-run lists what would be added and leaves the sync state alone.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli lidarr sync
+  spotify-cli lidarr sync --watch --interval 6h
+  spotify-cli lidarr sync --albums=false --monitor=false`,
+	Args: cobra.NoArgs,
+	RunE: runLidarrSync,
+}
+
+func init() {
+	lidarrSyncCmd.Flags().BoolVarP(&lidarrSyncWatch, "watch", "w", false, "Keep running and sync every --interval")
+	lidarrSyncCmd.Flags().BoolVar(&lidarrSyncArtists, "artists", true, "Sync followed artists")
+	lidarrSyncCmd.Flags().BoolVar(&lidarrSyncAlbums, "albums", true, "Sync saved albums")
+	lidarrSyncCmd.Flags().DurationVar(&lidarrSyncRetryAfter, "retry-after", 7*24*time.Hour, "Wait this long before retrying items that failed to add")
+	lidarrSyncCmd.Flags().IntP("concurrency", "c", 3, "Maximum concurrent requests (1-10); lowered automatically when rate limited")
+	addLidarrDryRunFlag(lidarrSyncCmd)
+	addDaemonFlags(lidarrSyncCmd, 6*time.Hour)
+	// A single pass is the default here; --watch is the way to keep running
+	lidarrSyncCmd.Flags().MarkHidden("once")
+
+	lidarrCmd.AddCommand(lidarrSyncCmd)
+}
+
+// lidarrSyncPass collects what one pass found, keyed by Spotify ID
+type lidarrSyncPass struct {
+	now     time.Time
+	dryRun  bool
+	artists map[string]lidarrsync.Item
+	albums  map[string]lidarrsync.Item
+}
+
+// added describes the items added, or the ones that would be in a dry run
+func (p *lidarrSyncPass) added() string {
+	if p.dryRun {
+		return "to add"
+	}
+	return "added"
+}
+
+func runLidarrSync(cmd *cobra.Command, args []string) error {
+	if !lidarrSyncArtists && !lidarrSyncAlbums {
+		return fmt.Errorf("nothing to sync: --artists and --albums are both off")
+	}
+	if !lidarrSyncWatch {
+		cmd.Flags().Set("once", "true")
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your followed artists and saved albums")
+	if err != nil {
+		return err
+	}
+
+	integration, err := createLidarrIntegration(cmd)
+	if err != nil {
+		return err
+	}
+	defer integration.Close()
+
+	if err := integration.ValidateConfig(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	concurrency := lidarrConcurrency(cmd)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return runDaemonLoop(cmd, "lidarr-sync", func(ctx context.Context, report *daemon.Report) error {
+		synced, err := lidarrsync.Load(dir)
+		if err != nil {
+			return err
+		}
+		library, err := integration.Library()
+		if err != nil {
+			return err
+		}
+
+		pass := &lidarrSyncPass{
+			now:     time.Now(),
+			dryRun:  dryRun,
+			artists: make(map[string]lidarrsync.Item),
+			albums:  make(map[string]lidarrsync.Item),
+		}
+		if lidarrSyncArtists {
+			if err := pass.syncArtists(ctx, spotifyClient, integration, library, synced, concurrency, report); err != nil {
+				return err
+			}
+		}
+		if lidarrSyncAlbums {
+			if err := pass.syncAlbums(ctx, spotifyClient, integration, library, synced, concurrency, report); err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			return nil
+		}
+		return lidarrsync.Record(dir, pass.artists, pass.albums, pass.now)
+	})
+}
+
+func (p *lidarrSyncPass) syncArtists(ctx context.Context, spotifyClient *client.SpotifyClient, li *integration.LidarrIntegration, library *integration.Library, synced *lidarrsync.State, concurrency int, report *daemon.Report) error {
+	followed, err := spotifyClient.Users.FollowedArtistsPager(nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get followed artists: %w", err)
+	}
+
+	// Spotify can list two artists of one name; both resolve to a single
+	// MusicBrainz lookup
+	var names []string
+	ids := make(map[string][]string)
+	existing := 0
+	for _, artist := range followed {
+		if artist.Name == "" || !synced.ArtistDue(artist.ID, p.now, lidarrSyncRetryAfter) {
+			continue
+		}
+		if library.HasArtist(artist.Name) {
+			p.artists[artist.ID] = lidarrsync.Item{Name: artist.Name, SyncedAt: p.now}
+			existing++
+			continue
+		}
+		if _, ok := ids[artist.Name]; !ok {
+			names = append(names, artist.Name)
+		}
+		ids[artist.Name] = append(ids[artist.Name], artist.ID)
+	}
+
+	added, failed := 0, 0
+	if len(names) > 0 {
+		utils.PrintVerbose("Adding %d new artist%s to Lidarr", len(names), pluralize(len(names)))
+		result := li.AddArtistsBatch(names, concurrency)
+		if result.DryRun {
+			printBatchResults(result)
+		}
+		for _, r := range result.Results {
+			item := lidarrsync.Item{Name: r.SpotifyName, MBID: r.MBID, SyncedAt: p.now}
+			switch {
+			case r.Success:
+				added++
+				if !result.DryRun {
+					fmt.Printf("  + %s (MBID: %s)\n", r.SpotifyName, r.MBID)
+				}
+			case r.AlreadyExists:
+				existing++
+			default:
+				item.Error = r.Error.Error()
+				failed++
+				utils.PrintWarning("Could not add artist %s: %v", r.SpotifyName, r.Error)
+			}
+			for _, id := range ids[r.SpotifyName] {
+				p.artists[id] = item
+			}
+		}
+	}
+
+	report.Add("artists added", added)
+	report.Add("artists existing", existing)
+	report.Add("artists failed", failed)
+	utils.PrintSuccess("Artists: %d %s, %d already in Lidarr, %d failed, %d followed", added, p.added(), existing, failed, len(followed))
+	return nil
+}
+
+func (p *lidarrSyncPass) syncAlbums(ctx context.Context, spotifyClient *client.SpotifyClient, li *integration.LidarrIntegration, library *integration.Library, synced *lidarrsync.State, concurrency int, report *daemon.Report) error {
+	saved, err := spotifyClient.Library.SavedAlbumsPager(nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get saved albums: %w", err)
+	}
+
+	var requests []integration.AlbumRequest
+	ids := make(map[string][]string)
+	existing := 0
+	for _, s := range saved {
+		if s.Album.Name == "" || len(s.Album.Artists) == 0 || !synced.AlbumDue(s.Album.ID, p.now, lidarrSyncRetryAfter) {
+			continue
+		}
+		request := integration.AlbumRequest{Artist: s.Album.Artists[0].Name, Title: s.Album.Name}
+		if library.HasAlbum(request) {
+			p.albums[s.Album.ID] = lidarrsync.Item{Name: request.String(), SyncedAt: p.now}
+			existing++
+			continue
+		}
+		key := request.String()
+		if _, ok := ids[key]; !ok {
+			requests = append(requests, request)
+		}
+		ids[key] = append(ids[key], s.Album.ID)
+	}
+
+	added, failed := 0, 0
+	if len(requests) > 0 {
+		utils.PrintVerbose("Adding %d new album%s to Lidarr", len(requests), pluralize(len(requests)))
+		result := li.AddAlbumsBatch(requests, concurrency)
+		if result.DryRun {
+			printAlbumBatchResults(result)
+		}
+		for _, r := range result.Results {
+			item := lidarrsync.Item{Name: r.Request.String(), MBID: r.MBID, SyncedAt: p.now}
+			switch {
+			case r.Success:
+				added++
+				if !result.DryRun {
+					fmt.Printf("  + %s (MBID: %s)\n", r.Request, r.MBID)
+				}
+			case r.AlreadyExists:
+				existing++
+			default:
+				item.Error = r.Error.Error()
+				failed++
+				utils.PrintWarning("Could not add album %s: %v", r.Request, r.Error)
+			}
+			for _, id := range ids[r.Request.String()] {
+				p.albums[id] = item
+			}
+		}
+	}
+
+	report.Add("albums added", added)
+	report.Add("albums existing", existing)
+	report.Add("albums failed", failed)
+	utils.PrintSuccess("Albums: %d %s, %d already in Lidarr, %d failed, %d saved", added, p.added(), existing, failed, len(saved))
+	return nil
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var marketsCheck []string
+
+// marketsCmd represents the markets command
+var marketsCmd = &cobra.Command{
+	Use:   "markets",
+	Short: "List the markets where Spotify is available",
+	Long: `List the ISO 3166-1 alpha-2 country codes of the markets where Spotify is
+available, as reported by the API.
+
+With --check, report whether each given code is an available market and exit
+with an error if any is not, so scripts can validate --market and --country
+values before using them.`,
+	Example: `  spotify-cli markets
+  spotify-cli markets --output json
+  spotify-cli markets --check SE,GB
+  spotify-cli markets --check "$MARKET" && spotify-cli browse featured --country "$MARKET"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMarkets()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(marketsCmd)
+
+	marketsCmd.Flags().StringSliceVar(&marketsCheck, "check", nil, "Market codes to check (comma-separated)")
+}
+
+func runMarkets() error {
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	markets, err := spotifyClient.Markets.GetAvailableMarkets(GetCommandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get markets: %w", err)
+	}
+	sort.Strings(markets)
+
+	if len(marketsCheck) > 0 {
+		return checkMarkets(markets)
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"markets": markets,
+			"total":   len(markets),
+		})
+	}
+
+	fmt.Printf("Available Markets - %d total\n\n", len(markets))
+
+	const perRow = 15
+	for i := 0; i < len(markets); i += perRow {
+		end := i + perRow
+		if end > len(markets) {
+			end = len(markets)
+		}
+		fmt.Println(strings.Join(markets[i:end], " "))
+	}
+
+	return nil
+}
+
+// checkMarkets reports which of the --check codes are available markets
+func checkMarkets(markets []string) error {
+	validator := api.NewValidator()
+
+	results := make(map[string]bool, len(marketsCheck))
+	var unavailable []string
+	for _, code := range marketsCheck {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		ok := validator.ValidateMarketAvailable(code, markets) == nil
+		results[code] = ok
+		if !ok {
+			unavailable = append(unavailable, code)
+		}
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		if err := utils.Output(results); err != nil {
+			return err
+		}
+	} else {
+		for _, code := range marketsCheck {
+			code = strings.ToUpper(strings.TrimSpace(code))
+			if results[code] {
+				fmt.Printf("%s available\n", code)
+			} else {
+				fmt.Printf("%s not available\n", code)
+			}
+		}
+	}
+
+	if len(unavailable) > 0 {
+		return fmt.Errorf("not available: %s", strings.Join(unavailable, ", "))
+	}
+	return nil
+}
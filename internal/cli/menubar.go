@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/menubar"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	menubarPlaylists int
+	menubarPluginDir string
+	menubarRefresh   string
+)
+
+// menubarCmd represents the menubar command
+var menubarCmd = &cobra.Command{
+	Use:   "menubar",
+	Short: "Show the current track and playback controls in the macOS menu bar",
+	Long: `Print a menu bar plugin for xbar (https://xbarapp.com) or SwiftBar showing
+the current track, play/pause, next and previous, and your recently played
+playlists.
+
+The menu bar app runs this command every few seconds and calls spotify-cli
+again when a menu item is clicked. Run 'menubar install' to set up the plugin.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli menubar install
+  spotify-cli menubar install --plugin-dir ~/SwiftBar --refresh 5s
+  spotify-cli menubar`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMenubar()
+	},
+}
+
+var menubarInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the menu bar plugin",
+	Long: `Write a plugin script that runs 'spotify-cli menubar' into the xbar plugin
+folder, or the folder given with --plugin-dir for SwiftBar.
+
+The refresh interval is part of the plugin's file name, as both apps expect.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMenubarInstall()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(menubarCmd)
+	menubarCmd.AddCommand(menubarInstallCmd)
+
+	menubarCmd.Flags().IntVar(&menubarPlaylists, "playlists", 8, "Number of recent playlists to list")
+	menubarInstallCmd.Flags().StringVar(&menubarPluginDir, "plugin-dir", "", "Plugin folder (default is xbar's)")
+	menubarInstallCmd.Flags().StringVar(&menubarRefresh, "refresh", "10s", "How often the menu bar app refreshes the plugin, e.g. 5s or 1m")
+	menubarInstallCmd.Flags().IntVar(&menubarPlaylists, "playlists", 8, "Number of recent playlists to list")
+}
+
+func runMenubar() error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the spotify-cli executable: %w", err)
+	}
+
+	menu, err := buildMenubar(GetCommandContext(), self)
+	if err != nil {
+		// The app shows whatever is printed, so report failures in the menu
+		menu = menubar.Menu{
+			Title: "♫",
+			Items: []menubar.Item{{Title: "spotify-cli: " + err.Error(), Color: "red"}},
+		}
+	}
+
+	return menu.Render(os.Stdout)
+}
+
+func buildMenubar(ctx context.Context, self string) (menubar.Menu, error) {
+	spotifyClient, err := newUserClient("playback control")
+	if err != nil {
+		return menubar.Menu{}, err
+	}
+
+	state, err := spotifyClient.Player.GetPlaybackState(ctx, "")
+	if err != nil {
+		return menubar.Menu{}, err
+	}
+
+	item, err := currentItem(ctx, spotifyClient)
+	if err != nil {
+		return menubar.Menu{}, err
+	}
+
+	menu := menubar.Menu{Title: "♫"}
+	playing := state != nil && state.IsPlaying
+
+	if item != nil {
+		artists := strings.Join(item.ArtistNames(), ", ")
+		menu.Title = truncateString(item.Name(), 30)
+		if artists != "" {
+			menu.Title += " — " + truncateString(artists, 20)
+		}
+		if !playing {
+			menu.Title = "❚❚ " + menu.Title
+		}
+
+		menu.Items = append(menu.Items, menubar.Item{Title: item.Name()})
+		if album := item.AlbumName(); album != "" {
+			menu.Items = append(menu.Items, menubar.Item{Title: artists + " — " + album, Color: "gray"})
+		} else if artists != "" {
+			menu.Items = append(menu.Items, menubar.Item{Title: artists, Color: "gray"})
+		}
+		if state != nil && state.Device.Name != "" {
+			menu.Items = append(menu.Items, menubar.Item{Title: "On " + state.Device.Name, Color: "gray"})
+		}
+		menu.Items = append(menu.Items, menubar.Separator)
+	} else {
+		menu.Items = append(menu.Items, menubar.Item{Title: "Nothing playing", Color: "gray"}, menubar.Separator)
+	}
+
+	if playing {
+		menu.Items = append(menu.Items, menubar.Item{Title: "Pause", Command: []string{self, "player", "pause"}})
+	} else {
+		menu.Items = append(menu.Items, menubar.Item{Title: "Play", Command: []string{self, "player", "play"}})
+	}
+	menu.Items = append(menu.Items,
+		menubar.Item{Title: "Next", Command: []string{self, "player", "next"}},
+		menubar.Item{Title: "Previous", Command: []string{self, "player", "previous"}},
+	)
+
+	if menubarPlaylists > 0 {
+		playlists, err := recentPlaylists(ctx, spotifyClient, menubarPlaylists)
+		if err != nil {
+			utils.PrintVerbose("Failed to get recent playlists: %v", err)
+		}
+		if len(playlists) > 0 {
+			recent := menubar.Item{Title: "Recent playlists"}
+			for _, playlist := range playlists {
+				recent.Children = append(recent.Children, menubar.Item{
+					Title:   playlist.name,
+					Command: []string{self, "player", "play", "--context", playlist.uri},
+				})
+			}
+			menu.Items = append(menu.Items, menubar.Separator, recent)
+		}
+	}
+
+	return menu, nil
+}
+
+// refreshPattern matches the intervals xbar and SwiftBar read from plugin names
+var refreshPattern = regexp.MustCompile(`^[1-9][0-9]*[smhd]$`)
+
+type menubarPlaylist struct {
+	name string
+	uri  string
+}
+
+// recentPlaylists lists playlists from the recently played tracks, most
+// recent first, topped up from the library's own order. Names come from the
+// library so the menu costs two requests however many playlists it shows.
+func recentPlaylists(ctx context.Context, spotifyClient *client.SpotifyClient, n int) ([]menubarPlaylist, error) {
+	library, _, err := spotifyClient.Playlists.GetUserPlaylists(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(library.Items))
+	for _, playlist := range library.Items {
+		names[playlist.URI] = playlist.Name
+	}
+
+	var playlists []menubarPlaylist
+	seen := make(map[string]bool)
+	add := func(uri string) {
+		if name, ok := names[uri]; ok && !seen[uri] && len(playlists) < n {
+			seen[uri] = true
+			playlists = append(playlists, menubarPlaylist{name: name, uri: uri})
+		}
+	}
+
+	history, err := spotifyClient.Player.GetRecentlyPlayed(ctx, &spotify.RecentlyPlayedOptions{Limit: 50})
+	if err == nil {
+		for _, play := range history.Items {
+			if play.Context.Type == "playlist" {
+				add(play.Context.URI)
+			}
+		}
+	}
+
+	for _, playlist := range library.Items {
+		add(playlist.URI)
+	}
+
+	return playlists, err
+}
+
+func runMenubarInstall() error {
+	if !refreshPattern.MatchString(menubarRefresh) {
+		return fmt.Errorf("invalid --refresh %q: use a number of seconds, minutes, hours or days such as 10s or 1m", menubarRefresh)
+	}
+
+	dir := menubarPluginDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		dir = filepath.Join(home, "Library", "Application Support", "xbar", "plugins")
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the spotify-cli executable: %w", err)
+	}
+
+	args := []string{"menubar", fmt.Sprintf("--playlists=%d", menubarPlaylists)}
+	if configDir != "" {
+		args = append([]string{"--config-dir", configDir}, args...)
+	}
+	if profileName != "" {
+		args = append([]string{"--profile", profileName}, args...)
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+# <xbar.title>Spotify</xbar.title>
+# <xbar.desc>Current track and playback controls from spotify-cli</xbar.desc>
+exec %q %s
+`, self, strings.Join(args, " "))
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create plugin folder: %w", err)
+	}
+
+	path := filepath.Join(dir, "spotify-cli."+menubarRefresh+".sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write plugin: %w", err)
+	}
+
+	utils.PrintSuccess("Installed menu bar plugin %s", path)
+	fmt.Println("Refresh plugins in xbar or SwiftBar to show it")
+	return nil
+}
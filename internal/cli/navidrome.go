@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/subsonic"
+	"github.com/spf13/cobra"
+)
+
+var navidromeCmd = &cobra.Command{
+	Use:   "navidrome",
+	Short: "Navidrome (Subsonic) integration commands",
+	Long: `Commands for copying Spotify playlists to Navidrome, or any other server
+that speaks the Subsonic API.
+
+The server is set with navidrome.url and navidrome.username in config.yaml
+and navidrome.password in credentials.yaml, or with NAVIDROME_URL,
+NAVIDROME_USERNAME and NAVIDROME_PASSWORD. --navidrome-url and --username
+override them for one command.`,
+}
+
+var navidromeTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test the Navidrome connection and credentials",
+	Args:  cobra.NoArgs,
+	RunE:  runNavidromeTest,
+}
+
+var navidromePushPlaylistCmd = &cobra.Command{
+	Use:   "push-playlist <spotify-playlist-id>",
+	Short: "Copy a Spotify playlist to Navidrome",
+	Long: `Find the tracks of a Spotify playlist in your Navidrome library and save
+them as a Navidrome playlist of the same name, or --name. Pushing again
+replaces the songs of that playlist, so it follows the Spotify one.
+
+A track is found when a song has its title and one of its artists, ignoring
+case, punctuation and additions such as "(Live)" or "- Remastered"; among
+several, one on the same album and of the same length is preferred. Tracks
+not in the library are listed and left out. --dry-run lists the matches
+without saving anything.`,
+	Example: `  spotify-cli navidrome push-playlist 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli navidrome push-playlist 37i9dQZF1DXcBWIGoYBM5M --name "Daily Mix" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNavidromePushPlaylist(cmd, args[0])
+	},
+}
+
+func init() {
+	navidromePushPlaylistCmd.Flags().String("name", "", "Name of the Navidrome playlist (default: the Spotify playlist's name)")
+	navidromePushPlaylistCmd.Flags().Bool("dry-run", false, "Match the tracks but do not create or change the playlist")
+
+	for _, cmd := range []*cobra.Command{navidromeTestCmd, navidromePushPlaylistCmd} {
+		cmd.Flags().String("navidrome-url", "", "Navidrome URL (overrides config)")
+		cmd.Flags().String("username", "", "Navidrome username (overrides config)")
+	}
+
+	navidromeCmd.AddCommand(navidromeTestCmd)
+	navidromeCmd.AddCommand(navidromePushPlaylistCmd)
+	rootCmd.AddCommand(navidromeCmd)
+}
+
+func createSubsonicClient(cmd *cobra.Command) (*subsonic.Client, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if url, _ := cmd.Flags().GetString("navidrome-url"); url != "" {
+		cfg.Navidrome.URL = url
+	}
+	if username, _ := cmd.Flags().GetString("username"); username != "" {
+		cfg.Navidrome.Username = username
+	}
+
+	if cfg.Navidrome.URL == "" || cfg.Navidrome.Username == "" || cfg.Navidrome.Password == "" {
+		return nil, "", apierrors.NewConfigError(fmt.Sprintf("Navidrome is not configured: set navidrome.url and navidrome.username in config.yaml and navidrome.password in %s, or NAVIDROME_URL, NAVIDROME_USERNAME and NAVIDROME_PASSWORD", config.CredentialsPath()))
+	}
+
+	return subsonic.NewClient(subsonic.Config{
+		BaseURL:  cfg.Navidrome.URL,
+		Username: cfg.Navidrome.Username,
+		Password: cfg.Navidrome.Password,
+	}), cfg.Navidrome.Username, nil
+}
+
+func runNavidromeTest(cmd *cobra.Command, args []string) error {
+	navidrome, username, err := createSubsonicClient(cmd)
+	if err != nil {
+		return err
+	}
+	if err := navidrome.Ping(); err != nil {
+		return fmt.Errorf("failed to connect to Navidrome: %w", err)
+	}
+	utils.PrintSuccess("Connected to Navidrome as %s", username)
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/subsonic"
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+	"github.com/spf13/cobra"
+)
+
+func runNavidromePushPlaylist(cmd *cobra.Command, playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+	navidrome, username, err := createSubsonicClient(cmd)
+	if err != nil {
+		return err
+	}
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	export, err := fetchPlaylistExport(GetCommandContext(), spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		name = export.Name
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	matches, err := matchPlaylistTracks(export, func(track trackmatch.Track) (string, string, error) {
+		song, err := navidrome.FindSong(track)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to search Navidrome: %w", err)
+		}
+		if song == nil {
+			return "", "", nil
+		}
+		return song.ID, fmt.Sprintf("%s - %s (%s)", song.Artist, song.Title, song.Album), nil
+	})
+	if err != nil {
+		return err
+	}
+	result := newPushResult(name, matches, dryRun)
+
+	if !dryRun {
+		if result.Matched == 0 {
+			return fmt.Errorf("none of the %d track%s are in your Navidrome library", len(matches), pluralize(len(matches)))
+		}
+		if err := savePushedPlaylist(navidrome, username, result); err != nil {
+			return err
+		}
+	}
+	return printPushResult("Navidrome", result)
+}
+
+// savePushedPlaylist replaces the songs of the user's playlist of that
+// name, or creates it if there is none
+func savePushedPlaylist(navidrome *subsonic.Client, username string, result *pushResult) error {
+	existing, err := findNavidromePlaylist(navidrome, result.Playlist, username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		result.PlaylistID = existing.ID
+		if err := navidrome.ReplacePlaylist(existing.ID, result.songIDs()); err != nil {
+			return fmt.Errorf("failed to update Navidrome playlist: %w", err)
+		}
+		return nil
+	}
+
+	created, err := navidrome.CreatePlaylist(result.Playlist, result.songIDs())
+	if err != nil {
+		return fmt.Errorf("failed to create Navidrome playlist: %w", err)
+	}
+	result.PlaylistID, result.Created = created.ID, true
+	return nil
+}
+
+// findNavidromePlaylist returns the user's own playlist of that name, or nil
+func findNavidromePlaylist(navidrome *subsonic.Client, name, username string) (*subsonic.Playlist, error) {
+	playlists, err := navidrome.Playlists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Navidrome playlists: %w", err)
+	}
+	for i, playlist := range playlists {
+		if playlist.Name == name && (playlist.Owner == "" || strings.EqualFold(playlist.Owner, username)) {
+			return &playlists[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/notes"
+	"github.com/spf13/cobra"
+)
+
+var notePlayedAt string
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Keep personal notes on tracks, albums and plays",
+	Long: `Attach free-text notes to tracks, albums and other Spotify items, or to a
+single play of a track with --played-at. Notes are stored locally and never
+sent to Spotify.
+
+Notes show up under their items in 'library tracks --format list',
+'library albums --format list' and 'episode get', and under "notes" in the
+JSON and YAML output of the library listings. They are part of the state
+directory, so 'state export' backs them up with the rest of your local data.`,
+	Example: `  spotify-cli note add spotify:track:4iV5W9uYEdYUVa79Axb7Rh "great bassline"
+  spotify-cli note add spotify:album:4aawyAB9vmqN3uQ7FjRGTy "best on vinyl"
+  spotify-cli note add spotify:track:4iV5W9uYEdYUVa79Axb7Rh "heard it at the wedding" --played-at "2024-06-01 21:30"
+  spotify-cli note list
+  spotify-cli note remove 3`,
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:   "add <uri> <text...>",
+	Short: "Add a note to an item",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteAdd(args[0], strings.Join(args[1:], " "))
+	},
+}
+
+var noteListCmd = &cobra.Command{
+	Use:   "list [uri]",
+	Short: "List notes, optionally only those on one item",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uri := ""
+		if len(args) == 1 {
+			uri = args[0]
+		}
+		return runNoteList(uri)
+	},
+}
+
+var noteRemoveCmd = &cobra.Command{
+	Use:   "remove <id...>",
+	Short: "Remove notes by ID",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runNoteRemove(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+	noteCmd.AddCommand(noteAddCmd)
+	noteCmd.AddCommand(noteListCmd)
+	noteCmd.AddCommand(noteRemoveCmd)
+
+	noteAddCmd.Flags().StringVar(&notePlayedAt, "played-at", "", "Attach the note to the play at this time (RFC 3339, or YYYY-MM-DD HH:MM local time)")
+}
+
+// parsePlayedAt accepts an RFC 3339 timestamp or a local date and time
+func parsePlayedAt(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid --played-at %q, expected RFC 3339 or YYYY-MM-DD HH:MM", value)
+}
+
+func runNoteAdd(uri, text string) error {
+	playedAt, err := parsePlayedAt(notePlayedAt)
+	if err != nil {
+		return err
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	note, err := notes.Add(dir, uri, text, playedAt, time.Now())
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(note)
+	}
+
+	utils.PrintSuccess("Added note %d to %s", note.ID, note.URI)
+	return nil
+}
+
+func runNoteList(uri string) error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	var list []notes.Note
+	if uri != "" {
+		if err := notes.ValidateURI(uri); err != nil {
+			return err
+		}
+		list, err = notes.ForURI(dir, uri)
+	} else {
+		list, err = notes.All(dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No notes found.")
+		return nil
+	}
+
+	fmt.Printf("%-5s %-9s %-38s %-16s %s\n", "ID", "KIND", "URI", "DATE", "NOTE")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, note := range list {
+		fmt.Printf("%-5d %-9s %-38s %-16s %s\n", note.ID, note.Kind(), note.URI, noteDate(note), note.Text)
+	}
+	return nil
+}
+
+func runNoteRemove(args []string) error {
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id < 1 {
+			return fmt.Errorf("invalid note ID %q", arg)
+		}
+		ids[i] = id
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := notes.Remove(dir, id); err != nil {
+			return err
+		}
+	}
+
+	utils.PrintSuccess("Removed %d note%s", len(ids), pluralize(len(ids)))
+	return nil
+}
+
+// noteDate is when a note's play happened, or else when it was written
+func noteDate(note notes.Note) string {
+	if note.PlayedAt != nil {
+		return note.PlayedAt.Local().Format("2006-01-02 15:04")
+	}
+	return note.CreatedAt.Local().Format("2006-01-02 15:04")
+}
+
+// loadNotes reads the notes index, treating an unreadable store as empty so
+// a listing never fails because of local annotations
+func loadNotes() notes.Index {
+	dir, err := openState()
+	if err != nil {
+		utils.PrintVerbose("Notes unavailable: %v", err)
+		return nil
+	}
+	index, err := notes.Load(dir)
+	if err != nil {
+		utils.PrintVerbose("Notes unavailable: %v", err)
+		return nil
+	}
+	return index
+}
+
+// printNotes prints the notes on an item below its listing entry
+func printNotes(index notes.Index, uri, indent string) {
+	for _, note := range index.For(uri) {
+		if note.PlayedAt != nil {
+			fmt.Printf("%s📝 %s (played %s)\n", indent, note.Text, formatTime(*note.PlayedAt))
+			continue
+		}
+		fmt.Printf("%s📝 %s\n", indent, note.Text)
+	}
+}
@@ -0,0 +1,301 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/notifier"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var notifyTestEvent string
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Post playback and library events to webhooks",
+	Long: `Tell other services what happens on your account: post an event to each
+webhook under webhooks in config.yaml when a track starts playing, a track
+is saved or a playlist changes. Home Assistant, Slack, ntfy and anything
+else that takes an HTTP POST can react to them.
+
+  webhooks:
+    - name: home-assistant
+      events: [track.started]
+    - name: slack
+      events: [track.saved, playlist.changed]
+    - name: ntfy
+      template: "{{.Text}}"
+
+Webhook URLs and headers often carry tokens, so they are kept by name in
+the credentials file, ~/.config/spotify-cli/credentials.yaml (or
+$SPOTIFY_CLI_CREDENTIALS), which only you may read:
+
+  webhooks:
+    home-assistant:
+      url: http://homeassistant.local:8123/api/webhook/spotify
+    slack:
+      url: $SLACK_WEBHOOK_URL
+    ntfy:
+      url: https://ntfy.sh/my-spotify
+      headers:
+        Authorization: Bearer ${NTFY_TOKEN}
+
+Events are track.started, track.saved and playlist.changed; a webhook
+without events gets them all. Each is posted as a JSON object with its
+type, time and text, a sentence describing it that Slack shows as the
+message, and the track (uri, name, artists, album, url) or the playlist
+(id, name, snapshot_id, tracks, url) it is about. A template replaces that
+body with a Go template over the event, such as {{.Track.Name}}, with join
+to list artists and json to quote values inside a JSON template. URLs and
+headers may name environment variables to keep tokens out of files too.
+
+Deliveries failing with a network error, a 5xx or a 429 are retried three
+times with backoff; a failed event is reported and then dropped.
+
+During quiet hours, see 'spotify-cli quiet', track.started events are
+dropped, and track.saved and playlist.changed events are held and posted
+by the first poll after quiet hours end.
+
+Events are sent by the commands that watch for them:
+
+  - 'player watch --notify' posts track.started as the playing item changes
+  - 'history record --notify' posts track.saved and playlist.changed for
+    what changed since its last poll`,
+	Example: `  spotify-cli notify test
+  spotify-cli notify test --event playlist.changed
+  spotify-cli player watch --notify
+  spotify-cli daemon install history -- history record --notify`,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample event to the webhooks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := loadNotifier()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		track := notifier.Track{
+			URI:     "spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+			Name:    "Never Gonna Give You Up",
+			Artists: []string{"Rick Astley"},
+			Album:   "Whenever You Need Somebody",
+			URL:     "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+		}
+		var event notifier.Event
+		switch notifyTestEvent {
+		case notifier.TrackStarted, notifier.TrackSaved:
+			event = notifier.NewTrackEvent(notifyTestEvent, track, now)
+		case notifier.PlaylistChanged:
+			event = notifier.NewPlaylistEvent(notifier.Playlist{
+				ID:         "37i9dQZF1DXcBWIGoYBM5M",
+				Name:       "Today's Top Hits",
+				SnapshotID: "test",
+				Tracks:     50,
+				URL:        "https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M",
+			}, now)
+		default:
+			return fmt.Errorf("invalid --event %q. Valid events: %s", notifyTestEvent, strings.Join(notifier.Types, ", "))
+		}
+
+		if err := n.Send(GetCommandContext(), event); err != nil {
+			return fmt.Errorf("failed to send the test event: %w", err)
+		}
+		utils.PrintSuccess("Sent a test %s event", event.Type)
+		return nil
+	},
+}
+
+func init() {
+	notifyTestCmd.Flags().StringVar(&notifyTestEvent, "event", notifier.TrackStarted, "Event type to send ("+strings.Join(notifier.Types, ", ")+")")
+
+	notifyCmd.AddCommand(notifyTestCmd)
+	rootCmd.AddCommand(notifyCmd)
+}
+
+// loadNotifier reads the webhooks from the config file
+func loadNotifier() (*notifier.Notifier, error) {
+	cfg, err := config.LoadSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Webhooks) == 0 {
+		return nil, apierrors.NewConfigError("no webhooks are configured: add them under webhooks in config.yaml, see 'spotify-cli notify --help'")
+	}
+
+	hooks := make([]notifier.Hook, 0, len(cfg.Webhooks))
+	for _, w := range cfg.Webhooks {
+		if w.URL == "" {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("webhook %q has no url: set it under webhooks in %s", w.Name, config.CredentialsPath()))
+		}
+		hook := notifier.Hook{
+			Name:        w.Name,
+			URL:         os.ExpandEnv(w.URL),
+			Events:      w.Events,
+			Template:    w.Template,
+			ContentType: w.ContentType,
+			Headers:     make(map[string]string, len(w.Headers)),
+		}
+		for name, value := range w.Headers {
+			hook.Headers[name] = os.ExpandEnv(value)
+		}
+		hooks = append(hooks, hook)
+	}
+	n, err := notifier.New(hooks)
+	if err != nil {
+		return nil, apierrors.NewConfigError(err.Error())
+	}
+	return n, nil
+}
+
+// sendNotification posts an event, warning rather than failing when a
+// webhook cannot be reached, and reports whether every webhook took it
+func sendNotification(ctx context.Context, n *notifier.Notifier, event notifier.Event) bool {
+	if err := n.Send(ctx, event); err != nil {
+		if ctx.Err() == nil {
+			utils.PrintWarning("Failed to post %s: %v", event.Type, err)
+		}
+		return false
+	}
+	utils.PrintVerbose("Posted %s", event.Text)
+	return true
+}
+
+// startNotifications posts track.started events in the background, in
+// order, so a slow webhook does not hold up the watch
+func startNotifications(ctx context.Context, n *notifier.Notifier, hours *quiet.Hours) chan<- notifier.Event {
+	events := make(chan notifier.Event, 16)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-events:
+				// As with desktop notifications, a track that started in
+				// the night is old news by morning, so it is not held
+				if hours.Active(time.Now()) {
+					utils.PrintVerbose("Quiet hours, dropping %s", event.Text)
+					continue
+				}
+				sendNotification(ctx, n, event)
+			}
+		}
+	}()
+	return events
+}
+
+// watchStartedEvent is the track.started event for a watch observation
+// whose item differs from the last one posted
+func watchStartedEvent(event playerWatchEvent) notifier.Event {
+	item := event.Item
+	track := notifier.Track{
+		URI:     item.URI,
+		Name:    item.Name,
+		Artists: item.Artists,
+		Album:   item.Album,
+	}
+	if item.Type != "" && item.ID != "" {
+		track.URL = "https://open.spotify.com/" + item.Type + "/" + item.ID
+	}
+	started := notifier.NewTrackEvent(notifier.TrackStarted, track, event.Time)
+	if event.Device != nil {
+		started.Device = event.Device.Name
+	}
+	return started
+}
+
+// postLibraryEvents posts track.saved for the tracks saved and
+// playlist.changed for the playlists changed since the last check. The
+// first check only takes note of the library. During quiet hours nothing is
+// checked, so the first check after them posts what changed meanwhile.
+func postLibraryEvents(ctx context.Context, spotifyClient *client.SpotifyClient, dir *state.Dir, n *notifier.Notifier, hours *quiet.Hours, report *daemon.Report) error {
+	now := time.Now()
+	if hours.Active(now) {
+		utils.PrintVerbose("Quiet hours until %s: holding library events until then", hours.Until(now).Format("Mon 15:04"))
+		return nil
+	}
+
+	seen, err := notifier.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	var events []notifier.Event
+	if seen.SavedAt.IsZero() {
+		seen.SavedAt = now
+	} else {
+		saved, err := spotifyClient.Library.GetSavedTracksSince(ctx, seen.SavedAt)
+		if err != nil {
+			return fmt.Errorf("failed to get saved tracks: %w", err)
+		}
+		// Oldest first, so the events arrive in the order the tracks were saved
+		for i := len(saved) - 1; i >= 0; i-- {
+			addedAt, err := time.Parse(time.RFC3339, saved[i].AddedAt)
+			if err != nil {
+				continue
+			}
+			seen.SavedAt = addedAt
+			if saved[i].Track.IsLocal {
+				continue
+			}
+			events = append(events, notifier.NewTrackEvent(notifier.TrackSaved, notifierTrack(saved[i].Track), addedAt))
+		}
+	}
+
+	playlists, err := spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playlists: %w", err)
+	}
+	current := make([]notifier.Playlist, 0, len(playlists))
+	for _, p := range playlists {
+		current = append(current, notifier.Playlist{
+			ID:         p.ID,
+			Name:       p.Name,
+			SnapshotID: p.SnapshotID,
+			Tracks:     p.Tracks.Total,
+			URL:        p.ExternalURLs.Spotify,
+		})
+	}
+	for _, p := range seen.PlaylistChanges(current) {
+		events = append(events, notifier.NewPlaylistEvent(p, now))
+	}
+
+	// What was seen is recorded before posting, so an unreachable webhook
+	// drops events rather than repeating them on every poll
+	if err := notifier.Record(dir, seen); err != nil {
+		return err
+	}
+	for _, event := range events {
+		if sendNotification(ctx, n, event) {
+			report.Add("notifications sent", 1)
+		} else {
+			report.Add("notifications failed", 1)
+		}
+	}
+	return nil
+}
+
+func notifierTrack(t models.Track) notifier.Track {
+	track := notifier.Track{URI: t.URI, Name: t.Name, URL: t.ExternalURLs.Spotify}
+	for _, artist := range t.Artists {
+		track.Artists = append(track.Artists, artist.Name)
+	}
+	if t.Album != nil {
+		track.Album = t.Album.Name
+	}
+	return track
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/notifier"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestNotifications_QuietHours(t *testing.T) {
+	window, err := schedule.NewWindow(nil, "00:00", "24:00")
+	if err != nil {
+		t.Fatalf("NewWindow failed: %v", err)
+	}
+	hours := &quiet.Hours{Windows: []schedule.Window{window}, MaxVolume: -1}
+
+	var posted int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posted, 1)
+	}))
+	defer server.Close()
+	n, err := notifier.New([]notifier.Hook{{URL: server.URL}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := startNotifications(ctx, n, hours)
+	events <- notifier.NewTrackEvent(notifier.TrackStarted, notifier.Track{Name: "Song"}, time.Now())
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&posted); got != 0 {
+		t.Errorf("Expected track.started to be dropped during quiet hours, got %d posts", got)
+	}
+
+	// Held library events leave the client untouched and nothing recorded,
+	// so the first poll after quiet hours picks them up
+	dir, err := state.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if err := postLibraryEvents(ctx, nil, dir, n, hours, daemon.NewReport()); err != nil {
+		t.Fatalf("postLibraryEvents failed: %v", err)
+	}
+	seen, err := notifier.Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !seen.SavedAt.IsZero() {
+		t.Errorf("Expected nothing recorded during quiet hours, got %v", seen.SavedAt)
+	}
+}
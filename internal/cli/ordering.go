@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+	"github.com/spf13/cobra"
+)
+
+// orderingFlags are the flags of commands that order tracks with the
+// ordering engine, named as in 'queue build'
+type orderingFlags struct {
+	artistSpacing int
+	noAlbumRepeat bool
+	seed          int64
+}
+
+func addOrderingFlags(cmd *cobra.Command, flags *orderingFlags, artistSpacing int) {
+	cmd.Flags().IntVar(&flags.artistSpacing, "artist-spacing", artistSpacing, "Minimum number of tracks between two tracks by the same artist")
+	cmd.Flags().BoolVar(&flags.noAlbumRepeat, "no-album-repeat", false, "Never place two tracks from the same album back to back")
+	cmd.Flags().Int64Var(&flags.seed, "seed", 0, "Random seed for a reproducible order (default is time based)")
+}
+
+// engine creates an ordering engine from the flags
+func (f *orderingFlags) engine() (*ordering.Engine, error) {
+	if f.artistSpacing < 0 {
+		return nil, fmt.Errorf("artist spacing cannot be negative")
+	}
+
+	seed := f.seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	utils.PrintVerbose("Ordering tracks with seed %d", seed)
+
+	return ordering.NewEngine(ordering.Constraints{
+		ArtistSpacing:   f.artistSpacing,
+		NoAdjacentAlbum: f.noAlbumRepeat,
+	}, seed), nil
+}
+
+// trackKeys identifies a track to the ordering engine by its first artist
+// and its album, as queue candidates are
+func trackKeys(track models.Track) ordering.Keys {
+	var keys ordering.Keys
+	if len(track.Artists) > 0 {
+		keys.Artist = track.Artists[0].ID
+	}
+	if track.Album != nil {
+		keys.Album = track.Album.ID
+	}
+	return keys
+}
@@ -1,7 +1,10 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/bambithedeer/spotify-api/internal/cli/config"
 	"github.com/bambithedeer/spotify-api/internal/cli/utils"
 	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/palette"
 	"github.com/bambithedeer/spotify-api/internal/spotify"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +29,11 @@ var (
 	playerURI        string
 	playerURIs       []string
 	playerContext    string
+	playerStartTrack string
+	playerSeekClamp  bool
+	playerStrict     bool
+	playerMarket     string
+	playerAutoPlay   bool
 )
 
 // playerCmd represents the player command
@@ -49,7 +58,13 @@ Client credentials authentication does not provide access to playback control.`,
 
   # Control shuffle and repeat
   spotify-cli player shuffle on
-  spotify-cli player repeat track`,
+  spotify-cli player repeat track
+
+  # Move playback to another device
+  spotify-cli player transfer "Kitchen Speaker"
+
+  # Play the same playlist in two rooms, on two accounts
+  spotify-cli player group start --devices "Kitchen,work:Office" --context spotify:playlist:37i9dQZF1DXcBWIGoYBM5M`,
 }
 
 var playerStatusCmd = &cobra.Command{
@@ -66,8 +81,13 @@ var playerStatusCmd = &cobra.Command{
 var playerCurrentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Get currently playing track",
-	Long:  `Get information about the currently playing track.`,
-	Example: `  spotify-cli player current`,
+	Long: `Get information about the currently playing track.
+
+On terminals with 24-bit color (COLORTERM=truecolor), the output is colored
+after the album art. Use --theme static for the standard colors, or
+--theme none for plain text.`,
+	Example: `  spotify-cli player current
+  spotify-cli player current --theme static`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runPlayerCurrent()
 	},
@@ -92,9 +112,15 @@ You can provide:
 - Track URIs: spotify:track:4iV5W9uYEdYUVa79Axb7Rh
 - Track IDs: 4iV5W9uYEdYUVa79Axb7Rh
 - Album/Playlist URIs: spotify:album:4aawyAB9vmqN3uQ7FjRGTy
+- open.spotify.com links, starting at the link's ?t= timestamp if it has one
 - Context URI with --context flag
 - Search queries: artist:"queen", track:"bohemian rhapsody", album:"greatest hits"
-- Saved content: saved:tracks, saved:albums, my:playlists, followed:artists`,
+- Saved content: saved:tracks, saved:albums, my:playlists, followed:artists
+
+Search results are re-ranked before one is picked: exact title and artist
+matches come first, then results playable in --market, then the most
+popular. With --strict, a query that does not single out one result is an
+error listing the candidates instead of a guess.`,
 	Example: `  # Resume playback
   spotify-cli player play
 
@@ -102,9 +128,16 @@ You can provide:
   spotify-cli player play spotify:track:4iV5W9uYEdYUVa79Axb7Rh
   spotify-cli player play 4iV5W9uYEdYUVa79Axb7Rh
 
+  # Play a shared link from its timestamp (1:35 in)
+  spotify-cli player play "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?t=95"
+
   # Play from context (album/playlist)
   spotify-cli player play --context spotify:album:4aawyAB9vmqN3uQ7FjRGTy
 
+  # Start an album or playlist at its fifth track, or at a given track
+  spotify-cli player play --context spotify:album:4aawyAB9vmqN3uQ7FjRGTy --start-track 5
+  spotify-cli player play --context spotify:playlist:37i9dQZF1DXcBWIGoYBM5M --start-track spotify:track:4iV5W9uYEdYUVa79Axb7Rh
+
   # Search and play
   spotify-cli player play artist:"queen"
   spotify-cli player play track:"bohemian rhapsody"
@@ -193,26 +226,65 @@ var playerRepeatCmd = &cobra.Command{
 var playerSeekCmd = &cobra.Command{
 	Use:   "seek [position]",
 	Short: "Seek to position in track",
-	Long:  `Seek to a specific position in the currently playing track. Position can be in seconds or MM:SS format.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Seek to a specific position in the currently playing track. Position can be in seconds, MM:SS,
+H:MM:SS or a duration like 1m30s.
+
+A position past the end of the track is an error, since Spotify would skip
+to the next track; with --clamp it seeks to the last second instead.`,
+	Args: cobra.ExactArgs(1),
 	Example: `  spotify-cli player seek 120
-  spotify-cli player seek 2:30`,
+  spotify-cli player seek 2:30
+  spotify-cli player seek 99:00 --clamp`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runPlayerSeek(args[0])
 	},
 }
 
+var playerTransferCmd = &cobra.Command{
+	Use:   "transfer <device>",
+	Short: "Move playback to another device",
+	Long: `Move playback to another device, given by its ID or name as shown by 'player devices'.
+Names match case-insensitively, and a unique part of a name is enough.
+
+Playback keeps its current state unless --play is given, which starts playing on the new device.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli player transfer "Kitchen Speaker"
+  spotify-cli player transfer phone --play
+  spotify-cli player transfer 0d1841b0976bae2a3a310dd74c0f3df354899bc8`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlayerTransfer(args[0])
+	},
+}
+
 var playerQueueCmd = &cobra.Command{
 	Use:   "queue [uri]",
 	Short: "Add track to queue",
-	Long:  `Add a track to the playback queue.`,
-	Args:  cobra.ExactArgs(1),
-	Example: `  spotify-cli player queue spotify:track:4iV5W9uYEdYUVa79Axb7Rh`,
+	Long: `Add a track to the playback queue.
+
+Use 'player queue list' to see what is queued.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli player queue spotify:track:4iV5W9uYEdYUVa79Axb7Rh
+  spotify-cli player queue list`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runPlayerQueue(args[0])
 	},
 }
 
+var playerQueueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the upcoming tracks in the queue",
+	Long: `Show the currently playing item and the tracks and episodes queued after it.
+
+Spotify returns the next items from the queue, including the rest of the
+current album or playlist, not only tracks added with 'player queue'.`,
+	Args: cobra.NoArgs,
+	Example: `  spotify-cli player queue list
+  spotify-cli player queue list --format list`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlayerQueueList()
+	},
+}
+
 var playerRecentCmd = &cobra.Command{
 	Use:   "recent",
 	Short: "Get recently played tracks",
@@ -237,7 +309,9 @@ func init() {
 	playerCmd.AddCommand(playerShuffleCmd)
 	playerCmd.AddCommand(playerRepeatCmd)
 	playerCmd.AddCommand(playerSeekCmd)
+	playerCmd.AddCommand(playerTransferCmd)
 	playerCmd.AddCommand(playerQueueCmd)
+	playerQueueCmd.AddCommand(playerQueueListCmd)
 	playerCmd.AddCommand(playerRecentCmd)
 
 	// Global flags for all player commands
@@ -250,13 +324,24 @@ func init() {
 	}
 
 	// Format flags for display commands
-	for _, cmd := range []*cobra.Command{playerStatusCmd, playerCurrentCmd, playerDevicesCmd, playerRecentCmd} {
+	for _, cmd := range []*cobra.Command{playerStatusCmd, playerCurrentCmd, playerDevicesCmd, playerRecentCmd, playerQueueListCmd} {
 		cmd.Flags().StringVarP(&playerFormat, "format", "f", "table", "Output format (table, list, json, yaml)")
 	}
 
+	for _, cmd := range []*cobra.Command{playerStatusCmd, playerCurrentCmd} {
+		cmd.Flags().StringVar(&playerTheme, "theme", "auto", "Color theme: auto (from the album art on truecolor terminals), static or none")
+	}
+
 	// Play command specific flags
 	playerPlayCmd.Flags().StringVarP(&playerContext, "context", "c", "", "Context URI (album, playlist, etc.)")
 	playerPlayCmd.Flags().IntVarP(&playerPosition, "position", "p", 0, "Start position in milliseconds")
+	playerSeekCmd.Flags().BoolVar(&playerSeekClamp, "clamp", false, "Seek to the last second when the position is past the end of the track")
+	playerPlayCmd.Flags().BoolVar(&playerStrict, "strict", false, "Fail instead of guessing when a search matches more than one result; plays only the match")
+	playerPlayCmd.Flags().StringVarP(&playerMarket, "market", "m", "", "Market/country code to prefer playable search results in (e.g., US, GB)")
+	playerPlayCmd.Flags().StringVar(&playerStartTrack, "start-track", "", "Track to start the context or tracks at: a URI, an ID, or its number counting from 1")
+
+	// Transfer command specific flags
+	playerTransferCmd.Flags().BoolVar(&playerAutoPlay, "play", false, "Start playback on the new device")
 
 	// Recent tracks flags
 	playerRecentCmd.Flags().IntVarP(&playerLimit, "limit", "l", 20, "Number of results to return (1-50)")
@@ -323,12 +408,12 @@ func runPlayerDevices() error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
-	devices, err := spotifyClient.Player.GetDevices(GetCommandContext())
+	devices, err := playerDevices(spotifyClient)
 	if err != nil {
 		return fmt.Errorf("failed to get devices: %w", err)
 	}
 
-	return outputDevices(devices)
+	return outputDevices(&models.DevicesResponse{Devices: devices})
 }
 
 func runPlayerPlay(uris []string) error {
@@ -351,8 +436,27 @@ func runPlayerPlay(uris []string) error {
 		PositionMs: playerPosition,
 	}
 
-	if playerContext != "" {
-		options.ContextURI = playerContext
+	// Links become URIs here; playback starts at the first timestamp one
+	// carries, unless --position says otherwise
+	contextURI := playerContext
+	refs := append([]string{contextURI}, uris...)
+	for i, ref := range refs {
+		uri, positionMs, ok, err := parseSpotifyLink(ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		refs[i] = uri
+		if options.PositionMs == 0 && positionMs > 0 {
+			options.PositionMs = positionMs
+		}
+	}
+	contextURI, uris = refs[0], refs[1:]
+
+	if contextURI != "" {
+		options.ContextURI = contextURI
 	} else if len(uris) > 0 {
 		// Check if this is a search query
 		query := strings.Join(uris, " ")
@@ -367,7 +471,7 @@ func runPlayerPlay(uris []string) error {
 			uri := uris[0]
 			if strings.HasPrefix(uri, "spotify:") {
 				// Already a URI - check type
-				if strings.Contains(uri, ":playlist:") || strings.Contains(uri, ":album:") || strings.Contains(uri, ":artist:") {
+				if strings.Contains(uri, ":playlist:") || strings.Contains(uri, ":album:") || strings.Contains(uri, ":artist:") || strings.Contains(uri, ":show:") {
 					options.ContextURI = uri
 				} else {
 					options.URIs = []string{uri}
@@ -400,11 +504,30 @@ func runPlayerPlay(uris []string) error {
 		}
 	}
 
+	if playerStartTrack != "" {
+		if options.ContextURI == "" && len(options.URIs) == 0 {
+			return fmt.Errorf("--start-track needs --context or tracks to play")
+		}
+		offset, err := parseStartTrack(playerStartTrack)
+		if err != nil {
+			return err
+		}
+		options.Offset = offset
+	}
+
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.Play(GetCommandContext(), options)
 	if err != nil {
-		return fmt.Errorf("failed to start playback: %w", err)
+		return playerCommandError(spotifyClient, "start playback", err)
 	}
 
+	at := ""
+	if options.PositionMs > 0 {
+		at = " at " + utils.FormatDuration(options.PositionMs)
+	}
 	if options.ContextURI != "" {
 		// Extract type from context URI
 		contextType := "content"
@@ -414,11 +537,13 @@ func runPlayerPlay(uris []string) error {
 			contextType = "album"
 		} else if strings.Contains(options.ContextURI, ":artist:") {
 			contextType = "artist"
+		} else if strings.Contains(options.ContextURI, ":show:") {
+			contextType = "show"
 		}
-		utils.PrintSuccess(fmt.Sprintf("Started playback of %s", contextType))
+		utils.PrintSuccess(fmt.Sprintf("Started playback of %s%s", contextType, at))
 	} else if len(options.URIs) > 0 {
-		utils.PrintSuccess(fmt.Sprintf("Started playback of %d track(s)", len(options.URIs)))
-	} else if playerContext != "" {
+		utils.PrintSuccess(fmt.Sprintf("Started playback of %d track(s)%s", len(options.URIs), at))
+	} else if contextURI != "" {
 		utils.PrintSuccess("Started playback from context")
 	} else {
 		utils.PrintSuccess("Resumed playback")
@@ -427,6 +552,55 @@ func runPlayerPlay(uris []string) error {
 	return nil
 }
 
+// spotifyLinkTypes are the kinds of open.spotify.com link that can be played
+var spotifyLinkTypes = []string{"track", "episode", "album", "playlist", "artist", "show"}
+
+// parseSpotifyLink turns an open.spotify.com link into the URI it points at.
+// ok is false for anything that is not a link. The t parameter the Spotify
+// apps add when sharing from a timestamp is returned in milliseconds.
+func parseSpotifyLink(ref string) (uri string, positionMs int, ok bool, err error) {
+	u, parseErr := url.Parse(ref)
+	if parseErr != nil || u.Host != "open.spotify.com" {
+		return "", 0, false, nil
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	// Localised links start with the locale, as in /intl-de/track/<id>
+	if len(parts) == 3 && strings.HasPrefix(parts[0], "intl-") {
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || !slices.Contains(spotifyLinkTypes, parts[0]) || parts[1] == "" {
+		return "", 0, true, fmt.Errorf("unsupported Spotify link %q: expected a track, episode, album, playlist, artist or show", ref)
+	}
+
+	if t := u.Query().Get("t"); t != "" {
+		d, parseErr := utils.ParseDuration(t)
+		if parseErr != nil {
+			return "", 0, true, fmt.Errorf("invalid timestamp in %q: %w", ref, parseErr)
+		}
+		positionMs = int(d.Milliseconds())
+	}
+	return fmt.Sprintf("spotify:%s:%s", parts[0], parts[1]), positionMs, true, nil
+}
+
+// parseStartTrack reads --start-track: a number counts from 1, anything else
+// is a track URI, or a bare ID taken as a track's
+func parseStartTrack(value string) (*spotify.Offset, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 1 {
+			return nil, fmt.Errorf("invalid --start-track %d: tracks are numbered from 1", n)
+		}
+		return &spotify.Offset{Position: n - 1}, nil
+	}
+	if strings.HasPrefix(value, "spotify:") {
+		return &spotify.Offset{URI: value}, nil
+	}
+	if len(value) == 22 {
+		return &spotify.Offset{URI: "spotify:track:" + value}, nil
+	}
+	return nil, fmt.Errorf("invalid --start-track %q: use a track URI, a track ID or a number", value)
+}
+
 func runPlayerPause() error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -442,9 +616,13 @@ func runPlayerPause() error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.Pause(GetCommandContext(), playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to pause playback: %w", err)
+		return playerCommandError(spotifyClient, "pause playback", err)
 	}
 
 	utils.PrintSuccess("Paused playback")
@@ -466,9 +644,13 @@ func runPlayerNext() error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.Next(GetCommandContext(), playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to skip to next track: %w", err)
+		return playerCommandError(spotifyClient, "skip to next track", err)
 	}
 
 	utils.PrintSuccess("Skipped to next track")
@@ -490,9 +672,13 @@ func runPlayerPrevious() error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.Previous(GetCommandContext(), playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to skip to previous track: %w", err)
+		return playerCommandError(spotifyClient, "skip to previous track", err)
 	}
 
 	utils.PrintSuccess("Skipped to previous track")
@@ -514,7 +700,22 @@ func runPlayerVolume(volume int) error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	caps, known := targetCapabilities(spotifyClient, true)
+	if known && caps.IsRestricted {
+		return restrictedDeviceError(caps.Name)
+	}
+	if known && !caps.SupportsVolume {
+		utils.PrintWarning("%s doesn't support volume control, skipping. Use the device's own volume control", caps.Name)
+		return nil
+	}
+
 	err = spotifyClient.Player.SetVolume(GetCommandContext(), volume, playerDeviceID)
+	if device := forbiddenOnDevice(spotifyClient, err); device != nil {
+		// Grouped and Cast speakers keep their own volume; not being able to
+		// set it here is not worth failing a script over
+		utils.PrintWarning("%s (%s) does not support volume control through Spotify Connect, skipping. Use the device's own volume control", device.Name, limitedDeviceKind(device))
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to set volume: %w", err)
 	}
@@ -548,9 +749,13 @@ func runPlayerShuffle(state string) error {
 		return fmt.Errorf("invalid shuffle state: %s (use 'on' or 'off')", state)
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.SetShuffle(GetCommandContext(), shuffle, playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to set shuffle: %w", err)
+		return playerCommandError(spotifyClient, "set shuffle", err)
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("Set shuffle %s", map[bool]string{true: "on", false: "off"}[shuffle]))
@@ -572,9 +777,13 @@ func runPlayerRepeat(state string) error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.SetRepeat(GetCommandContext(), strings.ToLower(state), playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to set repeat: %w", err)
+		return playerCommandError(spotifyClient, "set repeat", err)
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("Set repeat mode to %s", state))
@@ -596,20 +805,127 @@ func runPlayerSeek(position string) error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
-	positionMs, err := parsePosition(position)
+	d, err := utils.ParseDuration(position)
 	if err != nil {
 		return fmt.Errorf("invalid position: %w", err)
 	}
+	positionMs := int(d.Milliseconds())
 
-	err = spotifyClient.Player.Seek(GetCommandContext(), positionMs, playerDeviceID)
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
+	seeked, err := spotifyClient.Player.SeekInItem(GetCommandContext(), positionMs, &spotify.SeekOptions{
+		DeviceID: playerDeviceID,
+		Clamp:    playerSeekClamp,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to seek: %w", err)
+		return playerCommandError(spotifyClient, "seek", err)
 	}
 
+	if seeked != positionMs {
+		utils.PrintSuccess("Seeked to %s, the end of the track", utils.FormatDuration(seeked))
+		return nil
+	}
 	utils.PrintSuccess(fmt.Sprintf("Seeked to %s", position))
 	return nil
 }
 
+func runPlayerTransfer(ref string) error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' for user account access")
+	}
+
+	cfg := config.Get()
+	if cfg.RefreshToken == "" {
+		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
+	}
+
+	devices, err := playerDevices(spotifyClient)
+	if err != nil {
+		return fmt.Errorf("failed to get devices: %w", err)
+	}
+
+	device, err := resolveDevice(devices, ref)
+	if err != nil {
+		return err
+	}
+
+	if device.IsActive && !playerAutoPlay {
+		utils.PrintSuccess("Already playing on %s", device.Name)
+		return nil
+	}
+
+	request := &spotify.TransferPlaybackRequest{DeviceIDs: []string{device.ID}}
+	if playerAutoPlay {
+		request.Play = &playerAutoPlay
+	}
+
+	err = spotifyClient.Player.TransferPlayback(GetCommandContext(), request)
+	if err != nil {
+		return fmt.Errorf("failed to transfer playback: %w", err)
+	}
+
+	utils.PrintSuccess("Transferred playback to %s (%s)", device.Name, device.Type)
+	return nil
+}
+
+// resolveDevice finds a device by exact ID, then by case-insensitive name,
+// then by a part of a name that matches only one device
+func resolveDevice(devices []models.Device, ref string) (*models.Device, error) {
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no devices available. Open Spotify on the device you want to use and try again")
+	}
+
+	for i := range devices {
+		if devices[i].ID != "" && devices[i].ID == ref {
+			return checkTransferable(&devices[i])
+		}
+	}
+
+	lower := strings.ToLower(ref)
+	var partial []*models.Device
+	for i := range devices {
+		name := strings.ToLower(devices[i].Name)
+		if name == lower {
+			return checkTransferable(&devices[i])
+		}
+		if strings.Contains(name, lower) {
+			partial = append(partial, &devices[i])
+		}
+	}
+
+	names := make([]string, 0, len(devices))
+	for _, device := range devices {
+		names = append(names, fmt.Sprintf("%q", device.Name))
+	}
+
+	switch len(partial) {
+	case 1:
+		return checkTransferable(partial[0])
+	case 0:
+		return nil, fmt.Errorf("no device matches %q. Available devices: %s", ref, strings.Join(names, ", "))
+	default:
+		matches := make([]string, 0, len(partial))
+		for _, device := range partial {
+			matches = append(matches, fmt.Sprintf("%q", device.Name))
+		}
+		return nil, fmt.Errorf("%q matches several devices: %s. Use the full name or the device ID", ref, strings.Join(matches, ", "))
+	}
+}
+
+func checkTransferable(device *models.Device) (*models.Device, error) {
+	if device.ID == "" || device.IsRestricted {
+		return nil, fmt.Errorf("device %q cannot be controlled through the Web API", device.Name)
+	}
+	return device, nil
+}
+
 func runPlayerQueue(uri string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -625,15 +941,42 @@ func runPlayerQueue(uri string) error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
 	}
 
+	if err := checkPlayerDevice(spotifyClient); err != nil {
+		return err
+	}
+
 	err = spotifyClient.Player.AddToQueue(GetCommandContext(), uri, playerDeviceID)
 	if err != nil {
-		return fmt.Errorf("failed to add to queue: %w", err)
+		return playerCommandError(spotifyClient, "add to queue", err)
 	}
 
 	utils.PrintSuccess("Added track to queue")
 	return nil
 }
 
+func runPlayerQueueList() error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' for user account access")
+	}
+
+	cfg := config.Get()
+	if cfg.RefreshToken == "" {
+		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access playback control")
+	}
+
+	queue, err := spotifyClient.Player.GetQueue(GetCommandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get queue: %w", err)
+	}
+
+	return outputQueue(queue)
+}
+
 func runPlayerRecent() error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -681,10 +1024,15 @@ func outputPlaybackState(state *models.PlaybackState) error {
 		return nil
 	}
 
+	theme, err := playerThemeFor(state.Item)
+	if err != nil {
+		return err
+	}
+
 	// Convert interface{} to Track using type assertion on map
 	if itemMap, ok := state.Item.(map[string]interface{}); ok {
 		if trackName, exists := itemMap["name"].(string); exists {
-			fmt.Printf("Now Playing: %s\n", trackName)
+			fmt.Printf("Now Playing: %s\n", theme.Paint(palette.Title, trackName))
 
 			// Extract artist information
 			if artistsData, exists := itemMap["artists"].([]interface{}); exists && len(artistsData) > 0 {
@@ -697,22 +1045,23 @@ func outputPlaybackState(state *models.PlaybackState) error {
 					}
 				}
 				if len(artists) > 0 {
-					fmt.Printf("Artist(s): %s\n", strings.Join(artists, ", "))
+					fmt.Printf("Artist(s): %s\n", theme.Paint(palette.Detail, strings.Join(artists, ", ")))
 				}
 			}
 
 			// Extract album information
 			if albumData, exists := itemMap["album"].(map[string]interface{}); exists {
 				if albumName, ok := albumData["name"].(string); ok {
-					fmt.Printf("Album: %s\n", albumName)
+					fmt.Printf("Album: %s\n", theme.Paint(palette.Detail, albumName))
 				}
 			}
 
 			// Extract duration
 			if durationMs, exists := itemMap["duration_ms"].(float64); exists {
-				fmt.Printf("Progress: %s / %s\n",
-					formatPlayerDuration(state.ProgressMs),
-					formatPlayerDuration(int(durationMs)))
+				fmt.Printf("Progress: %s / %s %s\n",
+					utils.FormatDuration(state.ProgressMs),
+					utils.FormatDuration(int(durationMs)),
+					theme.Paint(palette.Bar, progressBar(state.ProgressMs, int(durationMs), 20)))
 			}
 		}
 	} else {
@@ -750,10 +1099,15 @@ func outputCurrentlyPlaying(playing *models.CurrentlyPlaying) error {
 		return nil
 	}
 
+	theme, err := playerThemeFor(playing.Item)
+	if err != nil {
+		return err
+	}
+
 	// Convert interface{} to Track using type assertion on map
 	if itemMap, ok := playing.Item.(map[string]interface{}); ok {
 		if trackName, exists := itemMap["name"].(string); exists {
-			fmt.Printf("Currently Playing: %s\n", trackName)
+			fmt.Printf("Currently Playing: %s\n", theme.Paint(palette.Title, trackName))
 
 			// Extract artist information
 			if artistsData, exists := itemMap["artists"].([]interface{}); exists && len(artistsData) > 0 {
@@ -766,14 +1120,14 @@ func outputCurrentlyPlaying(playing *models.CurrentlyPlaying) error {
 					}
 				}
 				if len(artists) > 0 {
-					fmt.Printf("Artist(s): %s\n", strings.Join(artists, ", "))
+					fmt.Printf("Artist(s): %s\n", theme.Paint(palette.Detail, strings.Join(artists, ", ")))
 				}
 			}
 
 			// Extract album information
 			if albumData, exists := itemMap["album"].(map[string]interface{}); exists {
 				if albumName, ok := albumData["name"].(string); ok {
-					fmt.Printf("Album: %s\n", albumName)
+					fmt.Printf("Album: %s\n", theme.Paint(palette.Detail, albumName))
 				}
 			}
 
@@ -781,9 +1135,10 @@ func outputCurrentlyPlaying(playing *models.CurrentlyPlaying) error {
 
 			// Extract duration
 			if durationMs, exists := itemMap["duration_ms"].(float64); exists {
-				fmt.Printf("Progress: %s / %s\n",
-					formatPlayerDuration(playing.ProgressMs),
-					formatPlayerDuration(int(durationMs)))
+				fmt.Printf("Progress: %s / %s %s\n",
+					utils.FormatDuration(playing.ProgressMs),
+					utils.FormatDuration(int(durationMs)),
+					theme.Paint(palette.Bar, progressBar(playing.ProgressMs, int(durationMs), 20)))
 			}
 		}
 	} else {
@@ -883,12 +1238,12 @@ func outputRecentlyPlayed(playHistory *models.CursorPaging[models.PlayHistory])
 			if item.Track.Album != nil {
 				fmt.Printf("   from %s\n", item.Track.Album.Name)
 			}
-			fmt.Printf("   played at %s\n", item.PlayedAt)
+			fmt.Printf("   played %s\n", formatTimestamp(item.PlayedAt))
 			fmt.Println()
 		}
 	} else {
 		// Table format
-		fmt.Printf("%-40s %-30s %-25s %s\n", "TRACK", "ARTIST", "ALBUM", "PLAYED AT")
+		fmt.Printf("%-40s %-30s %-25s %s\n", "TRACK", "ARTIST", "ALBUM", "PLAYED")
 		fmt.Println(strings.Repeat("-", 120))
 
 		for _, item := range playHistory.Items {
@@ -910,49 +1265,95 @@ func outputRecentlyPlayed(playHistory *models.CursorPaging[models.PlayHistory])
 				truncateString(item.Track.Name, 38),
 				truncateString(artists, 28),
 				truncateString(album, 23),
-				item.PlayedAt)
+				formatTimestamp(item.PlayedAt))
 		}
 	}
 
 	return nil
 }
 
-// Utility functions
+func outputQueue(queue *models.Queue) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := playerFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
 
-func parsePosition(position string) (int, error) {
-	// Try parsing as seconds first
-	if seconds, err := strconv.Atoi(position); err == nil {
-		return seconds * 1000, nil
+	// For structured output
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(queue)
 	}
 
-	// Try parsing as MM:SS format
-	if strings.Contains(position, ":") {
-		parts := strings.Split(position, ":")
-		if len(parts) != 2 {
-			return 0, fmt.Errorf("invalid time format, use seconds or MM:SS")
-		}
+	// Text output
+	if queue.CurrentlyPlaying.URI() == "" && len(queue.Queue) == 0 {
+		fmt.Println("Nothing is playing and the queue is empty")
+		return nil
+	}
 
-		minutes, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return 0, fmt.Errorf("invalid minutes: %s", parts[0])
+	if current := queue.CurrentlyPlaying; current.URI() != "" {
+		fmt.Printf("Now Playing: %s", current.Name())
+		if artists := current.ArtistNames(); len(artists) > 0 {
+			fmt.Printf(" - %s", strings.Join(artists, ", "))
 		}
+		fmt.Println()
+		fmt.Println()
+	}
 
-		seconds, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid seconds: %s", parts[1])
-		}
+	if len(queue.Queue) == 0 {
+		fmt.Println("The queue is empty")
+		return nil
+	}
 
-		return (minutes*60 + seconds) * 1000, nil
+	fmt.Printf("Up Next (%d)\n\n", len(queue.Queue))
+
+	if playerFormat == "list" {
+		for i := range queue.Queue {
+			item := &queue.Queue[i]
+			fmt.Printf("%d. %s\n", i+1, item.Name())
+			if artists := item.ArtistNames(); len(artists) > 0 {
+				fmt.Printf("   by %s\n", strings.Join(artists, ", "))
+			}
+			if album := item.AlbumName(); album != "" {
+				fmt.Printf("   from %s\n", album)
+			}
+			fmt.Printf("   %s\n", item.URI())
+			fmt.Println()
+		}
+	} else {
+		// Table format
+		fmt.Printf("%-4s %-40s %-30s %-25s %s\n", "#", "TRACK", "ARTIST", "ALBUM", "DURATION")
+		fmt.Println(strings.Repeat("-", 110))
+
+		for i := range queue.Queue {
+			item := &queue.Queue[i]
+			fmt.Printf("%-4d %-40s %-30s %-25s %s\n",
+				i+1,
+				truncateString(item.Name(), 38),
+				truncateString(strings.Join(item.ArtistNames(), ", "), 28),
+				truncateString(item.AlbumName(), 23),
+				utils.FormatDuration(item.DurationMs()))
+		}
 	}
 
-	return 0, fmt.Errorf("invalid position format")
+	return nil
 }
 
-func formatPlayerDuration(ms int) string {
-	seconds := ms / 1000
-	minutes := seconds / 60
-	seconds = seconds % 60
-	return fmt.Sprintf("%d:%02d", minutes, seconds)
+// Utility functions
+
+// decodePlayingItem converts the generically decoded item of a playback
+// state into the typed track or episode union
+func decodePlayingItem(raw interface{}) (*models.PlaylistItem, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var item models.PlaylistItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
 }
 
 func min(a, b int) int {
@@ -1010,6 +1411,11 @@ func handlePlayerSearchQuery(spotifyClient *client.SpotifyClient, query string)
 	}
 }
 
+// playerMatchOptions ranks search results for play by --market and --strict
+func playerMatchOptions() *spotify.MatchOptions {
+	return &spotify.MatchOptions{Market: playerMarket, Strict: playerStrict}
+}
+
 func handleTrackSearch(spotifyClient *client.SpotifyClient, query string, opts *api.PaginationOptions) ([]string, error) {
 	tracks, _, err := spotifyClient.Search.SearchTracks(GetCommandContext(), query, opts)
 	if err != nil {
@@ -1020,10 +1426,19 @@ func handleTrackSearch(spotifyClient *client.SpotifyClient, query string, opts *
 		return nil, fmt.Errorf("no tracks found for query: %s", query)
 	}
 
-	// Return URIs for first few tracks
-	uris := make([]string, 0, min(5, len(tracks.Items)))
-	for i := 0; i < min(5, len(tracks.Items)); i++ {
-		uris = append(uris, tracks.Items[i].URI)
+	ranked, err := spotify.RankTracks(query, tracks.Items, playerMatchOptions())
+	if err != nil {
+		return nil, err
+	}
+	count := min(5, len(ranked))
+	if playerStrict {
+		count = 1
+	}
+
+	// Return URIs for the best few tracks
+	uris := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		uris = append(uris, ranked[i].URI)
 	}
 
 	fmt.Printf("Playing %d track(s) from search: %s\n", len(uris), query)
@@ -1040,15 +1455,24 @@ func handleArtistSearch(spotifyClient *client.SpotifyClient, query string, opts
 		return nil, fmt.Errorf("no artists found for query: %s", query)
 	}
 
-	// Get top tracks for the first artist
-	artistID := artists.Items[0].ID
-	topTracks, err := spotifyClient.Artists.GetArtistTopTracks(GetCommandContext(), artistID, "US")
+	ranked, err := spotify.RankArtists(query, artists.Items, playerMatchOptions())
+	if err != nil {
+		return nil, err
+	}
+	artist := ranked[0]
+
+	// Get top tracks for the best matching artist
+	market := "US"
+	if playerMarket != "" {
+		market = playerMarket
+	}
+	topTracks, err := spotifyClient.Artists.GetArtistTopTracks(GetCommandContext(), artist.ID, market)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get top tracks for artist: %w", err)
 	}
 
 	if len(topTracks) == 0 {
-		return nil, fmt.Errorf("no top tracks found for artist: %s", artists.Items[0].Name)
+		return nil, fmt.Errorf("no top tracks found for artist: %s", artist.Name)
 	}
 
 	// Return URIs for top tracks
@@ -1057,7 +1481,7 @@ func handleArtistSearch(spotifyClient *client.SpotifyClient, query string, opts
 		uris = append(uris, topTracks[i].URI)
 	}
 
-	fmt.Printf("Playing %d top track(s) by %s\n", len(uris), artists.Items[0].Name)
+	fmt.Printf("Playing %d top track(s) by %s\n", len(uris), artist.Name)
 	return uris, nil
 }
 
@@ -1071,8 +1495,13 @@ func handleAlbumSearch(spotifyClient *client.SpotifyClient, query string, opts *
 		return nil, fmt.Errorf("no albums found for query: %s", query)
 	}
 
+	ranked, err := spotify.RankAlbums(query, albums.Items, playerMatchOptions())
+	if err != nil {
+		return nil, err
+	}
+
 	// Return the album URI as context (will play the whole album)
-	album := albums.Items[0]
+	album := ranked[0]
 	fmt.Printf("Playing album: %s by %s\n", album.Name, album.Artists[0].Name)
 
 	// For albums, we need to get the tracks and return their URIs
@@ -1120,8 +1549,8 @@ func handlePlaylistSearch(spotifyClient *client.SpotifyClient, query string, opt
 	// Return URIs for playlist tracks (up to 50 to avoid overwhelming)
 	uris := make([]string, 0, min(50, len(tracks.Items)))
 	for i := 0; i < min(50, len(tracks.Items)); i++ {
-		if track, ok := tracks.Items[i].Track.(*models.Track); ok {
-			uris = append(uris, track.URI)
+		if item := tracks.Items[i].Track; item != nil && !tracks.Items[i].IsLocal {
+			uris = append(uris, item.URI())
 		}
 	}
 
@@ -1252,8 +1681,8 @@ func handleMyPlaylists(spotifyClient *client.SpotifyClient, opts *api.Pagination
 			if j >= 25 { // Limit tracks per playlist
 				break
 			}
-			if track, ok := item.Track.(*models.Track); ok {
-				allURIs = append(allURIs, track.URI)
+			if item.Track != nil && !item.IsLocal {
+				allURIs = append(allURIs, item.Track.URI())
 			}
 		}
 	}
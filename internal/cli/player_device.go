@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/devicecache"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// limitedDeviceKind names the receivers whose Connect support refuses some
+// Web API player commands with a 403, or "" for other devices. Chromecast
+// devices report a Cast type; Sonos speakers only show up as speakers.
+func limitedDeviceKind(device *models.Device) string {
+	switch {
+	case device == nil:
+		return ""
+	case device.Type == "CastAudio" || device.Type == "CastVideo":
+		return "Chromecast"
+	case strings.Contains(strings.ToLower(device.Name), "sonos"):
+		return "Sonos"
+	case device.Type == "Speaker" || device.Type == "AVR":
+		return "speaker"
+	}
+	return ""
+}
+
+// forbiddenOnDevice returns the device a player command was aimed at when the
+// command was refused with a 403 and the device is one of the limited
+// receivers, or nil otherwise
+func forbiddenOnDevice(spotifyClient *client.SpotifyClient, err error) *models.Device {
+	if !apierrors.IsForbidden(err) {
+		return nil
+	}
+
+	device := playerTargetDevice(spotifyClient)
+	if limitedDeviceKind(device) == "" {
+		return nil
+	}
+	return device
+}
+
+// playerTargetDevice looks up the device player commands act on: the one
+// given with --device, or the active one. It is nil when that is unknown.
+func playerTargetDevice(spotifyClient *client.SpotifyClient) *models.Device {
+	if playerDeviceID == "" {
+		state, err := spotifyClient.Player.GetPlaybackState(GetCommandContext(), "")
+		if err != nil || state == nil {
+			return nil
+		}
+		return &state.Device
+	}
+
+	devices, err := playerDevices(spotifyClient)
+	if err != nil {
+		return nil
+	}
+	for i := range devices {
+		if devices[i].ID == playerDeviceID {
+			return &devices[i]
+		}
+	}
+	return nil
+}
+
+// playerDevices lists the available devices and caches their capabilities
+func playerDevices(spotifyClient *client.SpotifyClient) ([]models.Device, error) {
+	devices, err := spotifyClient.Player.GetDevices(GetCommandContext())
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := openState()
+	if err == nil {
+		err = devicecache.Record(dir, devices.Devices, time.Now())
+	}
+	if err != nil {
+		utils.PrintVerbose("Failed to cache device capabilities: %v", err)
+	}
+
+	return devices.Devices, nil
+}
+
+// targetCapabilities returns what the device player commands act on can do.
+// A --device is looked up in the cache; with fetch the device list is read
+// when the cache has nothing fresh, which is also the only way to learn which
+// device is active.
+func targetCapabilities(spotifyClient *client.SpotifyClient, fetch bool) (devicecache.Capabilities, bool) {
+	now := time.Now()
+
+	if playerDeviceID != "" {
+		if dir, err := openState(); err == nil {
+			if caps, ok, err := devicecache.Lookup(dir, playerDeviceID, now); err == nil && ok {
+				return caps, true
+			}
+		}
+	}
+	if !fetch {
+		return devicecache.Capabilities{}, false
+	}
+
+	devices, err := playerDevices(spotifyClient)
+	if err != nil {
+		return devicecache.Capabilities{}, false
+	}
+	for _, device := range devices {
+		if device.ID != "" && (device.ID == playerDeviceID || (playerDeviceID == "" && device.IsActive)) {
+			return devicecache.Capabilities{
+				ID:             device.ID,
+				Name:           device.Name,
+				Type:           device.Type,
+				SupportsVolume: device.SupportsVolume,
+				IsRestricted:   device.IsRestricted,
+				SeenAt:         now,
+			}, true
+		}
+	}
+	return devicecache.Capabilities{}, false
+}
+
+// checkPlayerDevice refuses a command up front when the cache says the
+// --device is restricted. It sends no requests, so it never slows a command
+// down; devices that are not cached are left to the API to judge.
+func checkPlayerDevice(spotifyClient *client.SpotifyClient) error {
+	if playerDeviceID == "" {
+		return nil
+	}
+
+	caps, ok := targetCapabilities(spotifyClient, false)
+	if ok && caps.IsRestricted {
+		return restrictedDeviceError(caps.Name)
+	}
+	return nil
+}
+
+func restrictedDeviceError(name string) error {
+	return fmt.Errorf("device %q is restricted and cannot be controlled through the Web API right now", name)
+}
+
+// playerCommandError reports a failed player command. A 403 from a Cast or
+// Sonos receiver gets advice instead of the raw API error, since those
+// devices refuse commands the same account can run elsewhere.
+func playerCommandError(spotifyClient *client.SpotifyClient, action string, err error) error {
+	device := forbiddenOnDevice(spotifyClient, err)
+	if device == nil {
+		return fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	return fmt.Errorf("%s (%s) does not allow you to %s through Spotify Connect. Use the device's own app, or move playback to another device with 'spotify-cli player transfer <device>'",
+		device.Name, limitedDeviceKind(device), action)
+}
@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/multiroom"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playerGroupDevices   string
+	playerGroupContext   string
+	playerGroupTolerance time.Duration
+)
+
+var playerGroupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Play the same thing in several rooms",
+	Long: `Play the same music in several rooms on speakers that cannot be grouped
+natively, by starting each one in turn and keeping them in step.
+
+Spotify plays on one device per account at a time, so each room plays on
+its own account: give the devices of the other rooms as profile:device,
+for a profile logged in to that account (see 'spotify-cli profile'). Family
+plans work well for this.`,
+}
+
+var playerGroupStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the same context on several devices and keep them in step",
+	Long: `Start --context on the first of --devices, then start each of the others
+on the same track at the same position, allowing for how long each request
+takes. Without --context the others join whatever the first device plays.
+
+Every --interval the rooms are checked against the first, which leads:
+rooms more than --tolerance ahead or behind are moved back in step, rooms
+on another track join the leader's, and pausing or stopping the leader
+pauses the rest. Control playback from the leader; the other rooms follow
+at the next check. Sync is best effort, going through the Web API rather
+than the speakers, so expect rooms to sit a fraction of a second apart.
+
+Press Ctrl+C to stop keeping the rooms in step; they play on.
+
+Requires user authentication on every profile used. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli player group start --devices "Kitchen,work:Office" --context spotify:playlist:37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli player group start --devices "Living Room,kids:Bedroom"
+  spotify-cli player group start --devices "Kitchen,work:Office" --context https://open.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy --once`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlayerGroupStart(cmd)
+	},
+}
+
+func init() {
+	playerCmd.AddCommand(playerGroupCmd)
+	playerGroupCmd.AddCommand(playerGroupStartCmd)
+
+	playerGroupStartCmd.Flags().StringVar(&playerGroupDevices, "devices", "", "Comma-separated devices to play on, the leader first; profile:device for another account's")
+	playerGroupStartCmd.Flags().StringVar(&playerGroupContext, "context", "", "Playlist, album, artist, show, track or episode to play, as a URI or link (default is what the leader plays)")
+	playerGroupStartCmd.Flags().DurationVar(&playerGroupTolerance, "tolerance", time.Second, "How far apart rooms may drift before they are moved back in step")
+	playerGroupStartCmd.MarkFlagRequired("devices")
+	addDaemonFlags(playerGroupStartCmd, 15*time.Second)
+}
+
+// groupRoom is a device in the group and the account it plays on
+type groupRoom struct {
+	member   multiroom.Member
+	client   *client.SpotifyClient
+	deviceID string
+	name     string
+	// latency is half the time the last read of its playback took
+	latency time.Duration
+}
+
+func runPlayerGroupStart(cmd *cobra.Command) error {
+	members, err := multiroom.ParseMembers(playerGroupDevices)
+	if err != nil {
+		return err
+	}
+	if playerGroupTolerance < 100*time.Millisecond {
+		return fmt.Errorf("--tolerance must be at least 100ms")
+	}
+	var start *spotify.PlayOptions
+	if playerGroupContext != "" {
+		if start, err = groupPlayOptions(playerGroupContext); err != nil {
+			return err
+		}
+	}
+
+	ctx := GetCommandContext()
+	rooms := make([]*groupRoom, 0, len(members))
+	for _, m := range members {
+		room, err := openGroupRoom(ctx, m)
+		if err != nil {
+			return err
+		}
+		rooms = append(rooms, room)
+	}
+
+	started := false
+	return runDaemonLoop(cmd, "player-group", func(ctx context.Context, report *daemon.Report) error {
+		if !started {
+			if err := startGroupLeader(ctx, rooms[0], start); err != nil {
+				return err
+			}
+			started = true
+		}
+		return syncGroup(ctx, rooms, report)
+	})
+}
+
+// groupPlayOptions plays ref, which may be a context or a single track or
+// episode
+func groupPlayOptions(ref string) (*spotify.PlayOptions, error) {
+	uri, _, ok, err := parseSpotifyLink(ref)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		uri = ref
+	}
+	if strings.HasPrefix(uri, "spotify:track:") || strings.HasPrefix(uri, "spotify:episode:") {
+		return &spotify.PlayOptions{URIs: []string{uri}}, nil
+	}
+	if _, err := scheduleContext(uri); err != nil {
+		return nil, fmt.Errorf("--context %q is not a playlist, album, artist, show, track or episode", ref)
+	}
+	return &spotify.PlayOptions{ContextURI: uri}, nil
+}
+
+// openGroupRoom logs in to a room's account and finds its device
+func openGroupRoom(ctx context.Context, m multiroom.Member) (*groupRoom, error) {
+	spotifyClient, err := newProfileUserClient(m.Profile, "playback control")
+	if err != nil {
+		return nil, err
+	}
+	devices, err := spotifyClient.Player.GetDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the devices for %s: %w", m, err)
+	}
+	device, err := resolveDevice(devices.Devices, m.Device)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m, err)
+	}
+	return &groupRoom{member: m, client: spotifyClient, deviceID: device.ID, name: device.Name}, nil
+}
+
+// startGroupLeader starts playback on the leader, if there is something to
+// start, and waits for it to be playing so the others have a position to
+// join at
+func startGroupLeader(ctx context.Context, leader *groupRoom, start *spotify.PlayOptions) error {
+	if start != nil {
+		options := *start
+		options.DeviceID = leader.deviceID
+		if err := leader.client.Player.Play(ctx, &options); err != nil {
+			return playerCommandError(leader.client, "start playback on "+leader.name, err)
+		}
+	}
+
+	// A device takes a moment to report what it was just told to play
+	for wait := 0; wait < 10; wait++ {
+		sample, err := readGroupRoom(ctx, leader)
+		if err != nil {
+			return err
+		}
+		if sample.Playing && sample.Item != "" {
+			utils.PrintSuccess("Playing on %s, bringing in the other rooms", leader.name)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	if start == nil {
+		return fmt.Errorf("nothing is playing on %s: start playback there first or give --context", leader.name)
+	}
+	return fmt.Errorf("%s did not start playing", leader.name)
+}
+
+// readGroupRoom reads what a room plays. An account playing on some other
+// device counts as the room being stopped.
+func readGroupRoom(ctx context.Context, room *groupRoom) (multiroom.Sample, error) {
+	before := time.Now()
+	state, err := room.client.Player.GetPlaybackState(ctx, "")
+	room.latency = time.Since(before) / 2
+	if err != nil {
+		return multiroom.Sample{}, fmt.Errorf("failed to get playback state of %s: %w", room.name, err)
+	}
+
+	sample := multiroom.Sample{At: before.Add(room.latency)}
+	if state == nil || state.Item == nil || (state.Device.ID != "" && state.Device.ID != room.deviceID) {
+		return sample, nil
+	}
+	item, err := decodePlayingItem(state.Item)
+	if err != nil {
+		return sample, nil
+	}
+	sample.Item = item.URI()
+	sample.Playing = state.IsPlaying
+	sample.Progress = time.Duration(state.ProgressMs) * time.Millisecond
+	sample.Duration = time.Duration(item.DurationMs()) * time.Millisecond
+	if state.Context != nil {
+		sample.Context = state.Context.URI
+	}
+	return sample, nil
+}
+
+// syncGroup brings every room in line with the leader. A room that cannot
+// be reached is reported and the rest are still synced.
+func syncGroup(ctx context.Context, rooms []*groupRoom, report *daemon.Report) error {
+	leader := rooms[0]
+	lead, err := readGroupRoom(ctx, leader)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, room := range rooms[1:] {
+		follow, err := readGroupRoom(ctx, room)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		now := time.Now()
+		// A request takes about as long to arrive as the last read took to
+		// come back, so aim for where the leader will be by then
+		target := lead.Position(now.Add(room.latency))
+		player := room.client.Player
+		action := multiroom.Plan(lead, follow, now, playerGroupTolerance)
+		var counted, what string
+		switch action {
+		case multiroom.Join:
+			counted, what = "rooms joined", "start playback"
+			err = player.Play(ctx, groupJoinOptions(lead, room.deviceID, target))
+			if err == nil {
+				utils.PrintSuccess("%s joined %s at %s", room.name, leader.name, utils.FormatDuration(int(target.Milliseconds())))
+			}
+		case multiroom.Seek:
+			counted, what = "rooms resynced", "seek"
+			drift := multiroom.Drift(lead, follow, now)
+			err = player.Seek(ctx, int(target.Milliseconds()), room.deviceID)
+			if err == nil {
+				utils.PrintVerbose("%s was %dms off, moved to %s", room.name, drift.Milliseconds(), utils.FormatDuration(int(target.Milliseconds())))
+			}
+		case multiroom.Pause:
+			counted, what = "rooms paused", "pause playback"
+			err = player.Pause(ctx, room.deviceID)
+			if err == nil {
+				utils.PrintVerbose("%s paused with %s", room.name, leader.name)
+			}
+		default:
+			if lead.Playing {
+				utils.PrintVerbose("%s is %dms off", room.name, multiroom.Drift(lead, follow, now).Milliseconds())
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, playerCommandError(room.client, what+" on "+room.name, err))
+			continue
+		}
+		report.Add(counted, 1)
+	}
+	return errors.Join(errs...)
+}
+
+// groupJoinOptions plays the leader's item at position. Playing it within
+// the leader's context lets the room carry on to the same next track;
+// artist contexts cannot start at a given track, so the item is played on
+// its own there and the room rejoins when the leader moves on.
+func groupJoinOptions(lead multiroom.Sample, deviceID string, position time.Duration) *spotify.PlayOptions {
+	options := &spotify.PlayOptions{DeviceID: deviceID, PositionMs: int(position.Milliseconds())}
+	if lead.Context != "" && !strings.HasPrefix(lead.Context, "spotify:artist:") {
+		options.ContextURI = lead.Context
+		options.Offset = &spotify.Offset{URI: lead.Item}
+	} else {
+		options.URIs = []string{lead.Item}
+	}
+	return options
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/multiroom"
+)
+
+func TestResolveDevice(t *testing.T) {
+	devices := []models.Device{
+		{ID: "desk1", Name: "Desktop", Type: "Computer"},
+		{ID: "phone1", Name: "Pixel Phone", Type: "Smartphone"},
+		{ID: "spk1", Name: "Kitchen Speaker", Type: "Speaker"},
+		{ID: "spk2", Name: "Bedroom Speaker", Type: "Speaker"},
+		{ID: "", Name: "Car", Type: "Automobile", IsRestricted: true},
+	}
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr string
+	}{
+		{ref: "phone1", want: "phone1"},
+		{ref: "desktop", want: "desk1"},
+		{ref: "phone", want: "phone1"},
+		{ref: "kitchen speaker", want: "spk1"},
+		{ref: "speaker", wantErr: "several devices"},
+		{ref: "tv", wantErr: "no device matches"},
+		{ref: "car", wantErr: "cannot be controlled"},
+	}
+
+	for _, tt := range tests {
+		device, err := resolveDevice(devices, tt.ref)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveDevice(%q) error = %v, expected it to contain %q", tt.ref, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveDevice(%q) failed: %v", tt.ref, err)
+			continue
+		}
+		if device.ID != tt.want {
+			t.Errorf("resolveDevice(%q) = %s, expected %s", tt.ref, device.ID, tt.want)
+		}
+	}
+
+	if _, err := resolveDevice(nil, "desktop"); err == nil || !strings.Contains(err.Error(), "no devices available") {
+		t.Errorf("Expected an error with no devices, got %v", err)
+	}
+}
+
+func TestLimitedDeviceKind(t *testing.T) {
+	tests := []struct {
+		device *models.Device
+		want   string
+	}{
+		{&models.Device{Name: "Living Room TV", Type: "CastVideo"}, "Chromecast"},
+		{&models.Device{Name: "Nest Mini", Type: "CastAudio"}, "Chromecast"},
+		{&models.Device{Name: "Sonos Move", Type: "Speaker"}, "Sonos"},
+		{&models.Device{Name: "Kitchen", Type: "Speaker"}, "speaker"},
+		{&models.Device{Name: "Desktop", Type: "Computer"}, ""},
+		{nil, ""},
+	}
+
+	for _, test := range tests {
+		if got := limitedDeviceKind(test.device); got != test.want {
+			t.Errorf("limitedDeviceKind(%+v) = %q, expected %q", test.device, got, test.want)
+		}
+	}
+}
+
+func TestPlayerWatchEvent(t *testing.T) {
+	state := &models.PlaybackState{
+		IsPlaying:            true,
+		ProgressMs:           30000,
+		RepeatState:          "off",
+		Device:               models.Device{ID: "desk1", Name: "Desktop", Type: "Computer", VolumePercent: 50},
+		CurrentlyPlayingType: "track",
+		Item: map[string]interface{}{
+			"id":          "track1",
+			"uri":         "spotify:track:track1",
+			"name":        "Song",
+			"duration_ms": float64(180000),
+			"artists":     []interface{}{map[string]interface{}{"name": "Artist"}},
+			"album":       map[string]interface{}{"name": "Album"},
+		},
+	}
+
+	event := newPlayerWatchEvent(state, time.Now())
+	if event.Item == nil || event.Item.Name != "Song" || event.Item.Album != "Album" || event.Item.DurationMs != 180000 {
+		t.Fatalf("Unexpected item %+v", event.Item)
+	}
+	if len(event.Item.Artists) != 1 || event.Item.Artists[0] != "Artist" {
+		t.Errorf("Expected the artist to be decoded, got %v", event.Item.Artists)
+	}
+
+	key := event.changeKey()
+
+	state.ProgressMs = 32000
+	if newPlayerWatchEvent(state, time.Now()).changeKey() != key {
+		t.Error("Expected progress alone not to count as a change")
+	}
+
+	state.Device.VolumePercent = 80
+	if newPlayerWatchEvent(state, time.Now()).changeKey() == key {
+		t.Error("Expected a volume change to be reported")
+	}
+
+	if empty := newPlayerWatchEvent(&models.PlaybackState{}, time.Now()); empty.Item != nil || empty.Device != nil {
+		t.Errorf("Expected no item or device when nothing is playing, got %+v", empty)
+	}
+}
+
+func TestParseStartTrack(t *testing.T) {
+	tests := []struct {
+		value    string
+		position int
+		uri      string
+		wantErr  bool
+	}{
+		{value: "1", position: 0},
+		{value: "12", position: 11},
+		{value: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", uri: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"},
+		{value: "4iV5W9uYEdYUVa79Axb7Rh", uri: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"},
+		{value: "0", wantErr: true},
+		{value: "-2", wantErr: true},
+		{value: "third", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		offset, err := parseStartTrack(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseStartTrack(%q): expected an error, got %+v", tt.value, offset)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseStartTrack(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if offset.Position != tt.position || offset.URI != tt.uri {
+			t.Errorf("parseStartTrack(%q) = %+v, want position %d, URI %q", tt.value, offset, tt.position, tt.uri)
+		}
+	}
+}
+
+func TestParseSpotifyLink(t *testing.T) {
+	tests := []struct {
+		ref        string
+		uri        string
+		positionMs int
+		notLink    bool
+		wantErr    bool
+	}{
+		{ref: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?t=95", uri: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", positionMs: 95000},
+		{ref: "https://open.spotify.com/episode/512ojhOuo1ktJprKbVcKyQ?si=abc&t=1:02", uri: "spotify:episode:512ojhOuo1ktJprKbVcKyQ", positionMs: 62000},
+		{ref: "https://open.spotify.com/intl-de/album/4aawyAB9vmqN3uQ7FjRGTy", uri: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy"},
+		{ref: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?t=1m5s", uri: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", positionMs: 65000},
+		{ref: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", notLink: true},
+		{ref: "artist:\"queen\"", notLink: true},
+		{ref: "https://open.spotify.com/user/someone", wantErr: true},
+		{ref: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?t=soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		uri, positionMs, ok, err := parseSpotifyLink(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSpotifyLink(%q): expected an error, got %q", tt.ref, uri)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSpotifyLink(%q) failed: %v", tt.ref, err)
+			continue
+		}
+		if ok == tt.notLink || uri != tt.uri || positionMs != tt.positionMs {
+			t.Errorf("parseSpotifyLink(%q) = %q, %d, %v; want %q, %d", tt.ref, uri, positionMs, ok, tt.uri, tt.positionMs)
+		}
+	}
+}
+
+func TestGroupJoinOptions(t *testing.T) {
+	lead := multiroom.Sample{Item: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", Context: "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"}
+	options := groupJoinOptions(lead, "spk1", 61500*time.Millisecond)
+	if options.ContextURI != lead.Context || options.Offset == nil || options.Offset.URI != lead.Item || options.PositionMs != 61500 || options.DeviceID != "spk1" {
+		t.Errorf("Expected to join the playlist at the track, got %+v", options)
+	}
+
+	// Artist contexts take no offset, so the track is played on its own
+	lead.Context = "spotify:artist:0OdUWJ0sBjDrqHygGUXeCF"
+	options = groupJoinOptions(lead, "spk1", 0)
+	if options.ContextURI != "" || options.Offset != nil || len(options.URIs) != 1 || options.URIs[0] != lead.Item {
+		t.Errorf("Expected to play the track alone, got %+v", options)
+	}
+}
+
+func TestGroupPlayOptions(t *testing.T) {
+	if options, err := groupPlayOptions("https://open.spotify.com/album/4aawyAB9vmqN3uQ7FjRGTy"); err != nil || options.ContextURI != "spotify:album:4aawyAB9vmqN3uQ7FjRGTy" {
+		t.Errorf("Expected an album context, got %+v, %v", options, err)
+	}
+	if options, err := groupPlayOptions("spotify:track:4iV5W9uYEdYUVa79Axb7Rh"); err != nil || len(options.URIs) != 1 {
+		t.Errorf("Expected a track to be played on its own, got %+v, %v", options, err)
+	}
+	if _, err := groupPlayOptions("spotify:user:someone"); err == nil {
+		t.Error("Expected an error for a user")
+	}
+}
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/notifier"
+	"github.com/bambithedeer/spotify-api/internal/palette"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playerWatchInterval time.Duration
+	playerWatchNotify   bool
+)
+
+var playerWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Monitor playback live",
+	Long: `Show what is playing and keep the view up to date: the track, a progress
+bar, the device, and the shuffle and repeat settings.
+
+On a terminal the view is redrawn in place every --interval. When output is
+piped, a new view is printed only when something changes. Use --format json
+to stream one JSON object per line each time the track, play/pause state,
+device, volume, shuffle or repeat changes, for scripts to consume.
+
+With --notify a track.started event is posted to the webhooks in config.yaml
+each time a new track or episode starts playing; see 'spotify-cli notify'.
+
+Press Ctrl+C to stop.`,
+	Example: `  spotify-cli player watch
+  spotify-cli player watch --interval 5s
+  spotify-cli player watch --format json | jq -r 'select(.item) | .item.name'
+  spotify-cli player watch --notify`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlayerWatch()
+	},
+}
+
+func init() {
+	playerCmd.AddCommand(playerWatchCmd)
+
+	playerWatchCmd.Flags().DurationVar(&playerWatchInterval, "interval", 2*time.Second, "How often to check the playback state")
+	playerWatchCmd.Flags().StringVarP(&playerFormat, "format", "f", "table", "Output format (table, json)")
+	playerWatchCmd.Flags().BoolVar(&playerWatchNotify, "notify", false, "Post track.started events to the webhooks in config.yaml")
+	playerWatchCmd.Flags().StringVar(&playerTheme, "theme", "auto", "Color theme: auto (from the album art on truecolor terminals), static or none")
+}
+
+// playerWatchItem is the part of a track or episode the watch reports
+type playerWatchItem struct {
+	ID         string   `json:"id"`
+	URI        string   `json:"uri"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists,omitempty"`
+	Album      string   `json:"album,omitempty"`
+	DurationMs int      `json:"duration_ms"`
+}
+
+// playerWatchEvent is one observation of the playback state
+type playerWatchEvent struct {
+	Time       time.Time        `json:"time"`
+	IsPlaying  bool             `json:"is_playing"`
+	Item       *playerWatchItem `json:"item"`
+	ProgressMs int              `json:"progress_ms"`
+	Device     *models.Device   `json:"device"`
+	Shuffle    bool             `json:"shuffle"`
+	Repeat     string           `json:"repeat"`
+
+	// rawItem is kept to pick the theme from the artwork
+	rawItem interface{}
+}
+
+func newPlayerWatchEvent(state *models.PlaybackState, now time.Time) playerWatchEvent {
+	event := playerWatchEvent{
+		Time:       now,
+		IsPlaying:  state.IsPlaying,
+		ProgressMs: state.ProgressMs,
+		Shuffle:    state.ShuffleState,
+		Repeat:     state.RepeatState,
+		rawItem:    state.Item,
+	}
+	if state.Device.ID != "" || state.Device.Name != "" {
+		device := state.Device
+		event.Device = &device
+	}
+
+	if state.Item != nil {
+		if item, err := decodePlayingItem(state.Item); err == nil && item.URI() != "" {
+			event.Item = &playerWatchItem{
+				ID:         item.ID(),
+				URI:        item.URI(),
+				Type:       state.CurrentlyPlayingType,
+				Name:       item.Name(),
+				Artists:    item.ArtistNames(),
+				Album:      item.AlbumName(),
+				DurationMs: item.DurationMs(),
+			}
+		}
+	}
+
+	return event
+}
+
+// changeKey identifies what a change is reported for. Progress is left out,
+// since it changes on every poll while playing.
+func (e playerWatchEvent) changeKey() string {
+	uri, device, volume := "", "", -1
+	if e.Item != nil {
+		uri = e.Item.URI
+	}
+	if e.Device != nil {
+		device, volume = e.Device.ID, e.Device.VolumePercent
+	}
+	return fmt.Sprintf("%s|%t|%s|%d|%t|%s", uri, e.IsPlaying, device, volume, e.Shuffle, e.Repeat)
+}
+
+func runPlayerWatch() error {
+	if playerFormat != "table" && playerFormat != "json" {
+		return fmt.Errorf("invalid format '%s'. Must be 'table' or 'json'", playerFormat)
+	}
+	if playerWatchInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	var notifications chan<- notifier.Event
+	var n *notifier.Notifier
+	var hours *quiet.Hours
+	if playerWatchNotify {
+		var err error
+		if n, err = loadNotifier(); err != nil {
+			return err
+		}
+		if hours, err = loadQuietHours(); err != nil {
+			return err
+		}
+	}
+
+	spotifyClient, err := newUserClient("playback state")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if n != nil {
+		notifications = startNotifications(ctx, n, hours)
+	}
+
+	ticker := time.NewTicker(playerWatchInterval)
+	defer ticker.Stop()
+
+	redraw := playerFormat == "table" && stdoutIsTerminal()
+	encoder := json.NewEncoder(os.Stdout)
+
+	lastKey, themeURI, startedURI := "", "", ""
+	theme, themed := palette.Theme{}, false
+	polled := false
+	for {
+		state, err := spotifyClient.Player.GetPlaybackState(ctx, "")
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil
+		case err != nil && !polled:
+			return playerCommandError(spotifyClient, "get playback state", err)
+		case err != nil:
+			// Keep the last view up and try again on the next tick
+			fmt.Fprintf(os.Stderr, "Failed to get playback state: %v\n", err)
+		default:
+			first := !polled
+			polled = true
+			event := newPlayerWatchEvent(state, time.Now())
+			key := event.changeKey()
+
+			// Resuming the same item is not a new start; the first poll
+			// only takes note of what is already playing
+			if uri := watchItemURI(event); event.IsPlaying && uri != "" && uri != startedURI {
+				if notifications != nil && !first {
+					select {
+					case notifications <- watchStartedEvent(event):
+					default:
+						utils.PrintVerbose("Webhooks are behind, dropping track.started for %s", uri)
+					}
+				}
+				startedURI = uri
+			}
+
+			switch {
+			case playerFormat == "json":
+				if key != lastKey {
+					if err := encoder.Encode(event); err != nil {
+						return err
+					}
+				}
+			case redraw || key != lastKey:
+				// Only fetch artwork for the theme when the item changes
+				if uri := watchItemURI(event); !themed || uri != themeURI {
+					themeURI, themed = uri, true
+					if theme, err = playerThemeFor(event.rawItem); err != nil {
+						return err
+					}
+				}
+				view := renderPlayerWatch(event, theme)
+				if redraw {
+					fmt.Print("\033[H\033[2J" + view)
+				} else {
+					fmt.Println(view)
+				}
+			}
+			lastKey = key
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchItemURI(event playerWatchEvent) string {
+	if event.Item == nil {
+		return ""
+	}
+	return event.Item.URI
+}
+
+// renderPlayerWatch draws the watch view for one observation
+func renderPlayerWatch(event playerWatchEvent, theme palette.Theme) string {
+	var b strings.Builder
+
+	if event.Item == nil {
+		b.WriteString("Nothing playing\n")
+	} else {
+		status := "▶"
+		if !event.IsPlaying {
+			status = "⏸"
+		}
+		fmt.Fprintf(&b, "%s %s\n", status, theme.Paint(palette.Title, event.Item.Name))
+		if len(event.Item.Artists) > 0 {
+			fmt.Fprintf(&b, "  %s\n", theme.Paint(palette.Detail, strings.Join(event.Item.Artists, ", ")))
+		}
+		if event.Item.Album != "" {
+			fmt.Fprintf(&b, "  %s\n", theme.Paint(palette.Detail, event.Item.Album))
+		}
+		fmt.Fprintf(&b, "\n  %s %s %s\n",
+			utils.FormatDuration(event.ProgressMs),
+			theme.Paint(palette.Bar, progressBar(event.ProgressMs, event.Item.DurationMs, 30)),
+			utils.FormatDuration(event.Item.DurationMs))
+	}
+
+	b.WriteString("\n")
+	if event.Device != nil {
+		fmt.Fprintf(&b, "  Device:  %s (%s), volume %d%%\n", event.Device.Name, event.Device.Type, event.Device.VolumePercent)
+	}
+	shuffle := "off"
+	if event.Shuffle {
+		shuffle = "on"
+	}
+	repeat := event.Repeat
+	if repeat == "" {
+		repeat = "off"
+	}
+	fmt.Fprintf(&b, "  Shuffle: %s   Repeat: %s\n", shuffle, repeat)
+
+	return b.String()
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -20,6 +21,12 @@ var (
 	playlistFormat string
 	playlistPublic bool
 	playlistDesc   string
+
+	playlistFollowPrivate bool
+	playlistUnfollowForce bool
+
+	playlistQuiet      bool
+	playlistIfSnapshot string
 )
 
 // playlistCmd represents the playlist command
@@ -46,7 +53,16 @@ Client credentials authentication does not provide access to user playlists.`,
   spotify-cli playlist add <playlist-id> <track-id> [track-id...]
 
   # Remove tracks from playlist
-  spotify-cli playlist remove <playlist-id> <track-id> [track-id...]`,
+  spotify-cli playlist remove <playlist-id> <track-id> [track-id...]
+
+  # Follow a playlist without showing it on your profile
+  spotify-cli playlist follow <playlist-id> --private
+
+  # Check whether friends follow a playlist
+  spotify-cli playlist followers check <playlist-id> <user-id> [user-id...]
+
+  # Set a playlist's cover image
+  spotify-cli playlist cover set <playlist-id> cover.jpg`,
 }
 
 var playlistListCmd = &cobra.Command{
@@ -102,10 +118,18 @@ var playlistRemoveCmd = &cobra.Command{
 	Short: "Remove tracks from playlist",
 	Long: `Remove one or more tracks from a playlist.
 
-You can provide multiple track IDs to remove multiple tracks at once (up to 100).`,
+You can provide multiple track IDs to remove multiple tracks at once (up to 100).
+
+With --if-snapshot, the tracks are only removed if the playlist is still at
+that snapshot ID, as printed by the edit that made it, so a change made
+somewhere else in the meantime is not clobbered.
+
+The current version is recorded first, and the command to restore it with
+'playlist rollback' is printed after the removal.`,
 	Args: cobra.MinimumNArgs(2),
 	Example: `  spotify-cli playlist remove 37i9dQZF1DXcBWIGoYBM5M 4iV5W9uYEdYUVa79Axb7Rh
-  spotify-cli playlist remove playlist-id track1 track2 track3`,
+  spotify-cli playlist remove playlist-id track1 track2 track3
+  spotify-cli playlist remove playlist-id track1 --if-snapshot MTAsZDVmZDczN2Q0`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runPlaylistRemove(args[0], args[1:])
 	},
@@ -127,6 +151,54 @@ Works with both your own playlists and public playlists from other users.`,
 	},
 }
 
+var playlistFollowCmd = &cobra.Command{
+	Use:   "follow [playlist-id]",
+	Short: "Follow a playlist",
+	Long: `Follow a playlist, adding it to your library.
+
+Followed playlists are shown on your profile unless you follow them with --private.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli playlist follow 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist follow https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M --private`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistFollow(args[0])
+	},
+}
+
+var playlistUnfollowCmd = &cobra.Command{
+	Use:   "unfollow [playlist-id]",
+	Short: "Unfollow a playlist",
+	Long: `Unfollow a playlist, removing it from your library.
+
+Unfollowing a playlist you own deletes it for you, so it needs --force.`,
+	Args:    cobra.ExactArgs(1),
+	Example: `  spotify-cli playlist unfollow 37i9dQZF1DXcBWIGoYBM5M`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistUnfollow(args[0])
+	},
+}
+
+var playlistFollowersCmd = &cobra.Command{
+	Use:   "followers",
+	Short: "Check who follows a playlist",
+	Long:  `Check whether users follow a playlist, for example a collaborative playlist you share.`,
+}
+
+var playlistFollowersCheckCmd = &cobra.Command{
+	Use:   "check [playlist-id] [user-id...]",
+	Short: "Check if users follow a playlist",
+	Long: `Check whether one or more users (up to 5) follow a playlist.
+
+Users are given by their Spotify user ID, the last part of their profile URL.
+Users who follow a playlist privately are reported as not following it.`,
+	Args: cobra.MinimumNArgs(2),
+	Example: `  spotify-cli playlist followers check 37i9dQZF1DXcBWIGoYBM5M jmperezperez
+  spotify-cli playlist followers check 37i9dQZF1DXcBWIGoYBM5M alice bob carol`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistFollowersCheck(args[0], args[1:])
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(playlistCmd)
 	playlistCmd.AddCommand(playlistListCmd)
@@ -135,6 +207,10 @@ func init() {
 	playlistCmd.AddCommand(playlistAddCmd)
 	playlistCmd.AddCommand(playlistRemoveCmd)
 	playlistCmd.AddCommand(playlistTracksCmd)
+	playlistCmd.AddCommand(playlistFollowCmd)
+	playlistCmd.AddCommand(playlistUnfollowCmd)
+	playlistCmd.AddCommand(playlistFollowersCmd)
+	playlistFollowersCmd.AddCommand(playlistFollowersCheckCmd)
 
 	// Add flags to list commands
 	for _, cmd := range []*cobra.Command{playlistListCmd, playlistGetCmd, playlistTracksCmd} {
@@ -146,6 +222,28 @@ func init() {
 	// Create playlist flags
 	playlistCreateCmd.Flags().StringVarP(&playlistDesc, "description", "d", "", "Playlist description")
 	playlistCreateCmd.Flags().BoolVarP(&playlistPublic, "public", "p", false, "Make playlist public")
+
+	// Follow flags
+	playlistFollowCmd.Flags().BoolVar(&playlistFollowPrivate, "private", false, "Follow without showing the playlist on your profile")
+	playlistUnfollowCmd.Flags().BoolVar(&playlistUnfollowForce, "force", false, "Unfollow even if you own the playlist")
+
+	addQuietFlag(playlistAddCmd)
+	addQuietFlag(playlistRemoveCmd)
+	playlistRemoveCmd.Flags().StringVar(&playlistIfSnapshot, "if-snapshot", "", "Only remove the tracks if the playlist is still at this snapshot ID")
+}
+
+// addQuietFlag adds --quiet to a command that edits a playlist
+func addQuietFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&playlistQuiet, "quiet", false, "Don't print the playlist's new snapshot ID")
+}
+
+// printSnapshotID prints the snapshot ID an edit left the playlist at, for
+// --if-snapshot on the next one, unless --quiet is set
+func printSnapshotID(snapshotID string) {
+	if playlistQuiet || snapshotID == "" {
+		return
+	}
+	fmt.Printf("Snapshot ID: %s\n", snapshotID)
 }
 
 func runPlaylistList() error {
@@ -196,6 +294,139 @@ func runPlaylistGet(playlistID string) error {
 	return outputSinglePlaylist(playlist)
 }
 
+func runPlaylistFollow(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	err = spotifyClient.Playlists.FollowPlaylist(GetCommandContext(), playlistID, !playlistFollowPrivate)
+	if err != nil {
+		return fmt.Errorf("failed to follow playlist: %w", err)
+	}
+
+	if playlistFollowPrivate {
+		utils.PrintSuccess("Followed playlist %s privately", playlistID)
+	} else {
+		utils.PrintSuccess("Followed playlist %s", playlistID)
+	}
+	return nil
+}
+
+func runPlaylistUnfollow(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "name,owner.id"})
+	if err != nil {
+		return fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	if !playlistUnfollowForce {
+		user, err := spotifyClient.Users.GetCurrentUser(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get current user: %w", err)
+		}
+		if playlist.Owner.ID == user.ID {
+			return fmt.Errorf("you own %q, so unfollowing it deletes it. Use --force to go ahead", playlist.Name)
+		}
+	}
+
+	if err := spotifyClient.Playlists.UnfollowPlaylist(ctx, playlistID); err != nil {
+		return fmt.Errorf("failed to unfollow playlist: %w", err)
+	}
+
+	utils.PrintSuccess("Unfollowed %q", playlist.Name)
+	return nil
+}
+
+func runPlaylistFollowersCheck(playlistRef string, userIDs []string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	if len(userIDs) > 5 {
+		return fmt.Errorf("cannot check more than 5 users at once")
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	following, err := spotifyClient.Playlists.CheckUsersFollowPlaylist(GetCommandContext(), playlistID, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to check playlist followers: %w", err)
+	}
+
+	return outputPlaylistFollowersResults(playlistID, userIDs, following)
+}
+
+func outputPlaylistFollowersResults(playlistID string, userIDs []string, following []bool) error {
+	cfg := config.Get()
+
+	// For structured output
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		results := make([]map[string]interface{}, len(userIDs))
+		for i, id := range userIDs {
+			results[i] = map[string]interface{}{
+				"user_id":   id,
+				"following": following[i],
+			}
+		}
+		return utils.Output(map[string]interface{}{
+			"playlist_id": playlistID,
+			"results":     results,
+		})
+	}
+
+	// Text output
+	fmt.Printf("Playlist Followers Check - %d user%s\n", len(userIDs), pluralize(len(userIDs)))
+	fmt.Println(strings.Repeat("-", 60))
+
+	followingCount := 0
+	for i, id := range userIDs {
+		status := "❌ Not following"
+		if following[i] {
+			status = "✅ Following"
+			followingCount++
+		}
+		fmt.Printf("%-40s %s\n", truncateString(id, 38), status)
+	}
+
+	fmt.Printf("\nSummary: %d/%d user%s following\n",
+		followingCount, len(userIDs), pluralize(len(userIDs)))
+
+	return nil
+}
+
+// normalizePlaylistID accepts a playlist ID, URI or open.spotify.com URL
+func normalizePlaylistID(ref string) (string, error) {
+	if u, err := url.Parse(ref); err == nil && u.Host == "open.spotify.com" && strings.HasPrefix(u.Path, "/playlist/") {
+		ref = strings.TrimPrefix(u.Path, "/playlist/")
+	}
+
+	ids, err := api.NewValidator().NormalizeAndValidateIDs([]string{ref})
+	if err != nil {
+		return "", fmt.Errorf("invalid playlist: %w", err)
+	}
+	return ids[0], nil
+}
+
 func runPlaylistCreate(name string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -234,6 +465,40 @@ func runPlaylistCreate(name string) error {
 	return nil
 }
 
+// createPlaylistWithTracks creates a private playlist for the current user
+// and fills it with tracks. The playlist returned has the snapshot ID of the
+// last batch added.
+func createPlaylistWithTracks(spotifyClient *client.SpotifyClient, name, description string, uris []string) (*models.Playlist, error) {
+	ctx := GetCommandContext()
+
+	user, err := spotifyClient.Users.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	public := false
+	playlist, err := spotifyClient.Playlists.CreatePlaylist(ctx, user.ID, &spotify.CreatePlaylistRequest{
+		Name:        name,
+		Description: description,
+		Public:      &public,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create playlist: %w", err)
+	}
+
+	// The API accepts at most 100 tracks per request
+	for start := 0; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		response, err := spotifyClient.Playlists.AddTracksToPlaylist(ctx, playlist.ID, &spotify.AddTracksRequest{URIs: uris[start:end]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		playlist.SnapshotID = response.SnapshotID
+	}
+
+	return playlist, nil
+}
+
 func runPlaylistAdd(playlistID string, trackIDs []string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
@@ -258,12 +523,13 @@ func runPlaylistAdd(playlistID string, trackIDs []string) error {
 		URIs: trackURIs,
 	}
 
-	_, err = spotifyClient.Playlists.AddTracksToPlaylist(GetCommandContext(), playlistID, request)
+	response, err := spotifyClient.Playlists.AddTracksToPlaylist(GetCommandContext(), playlistID, request)
 	if err != nil {
 		return fmt.Errorf("failed to add tracks to playlist: %w", err)
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("Successfully added %d track(s) to playlist", len(trackIDs)))
+	printSnapshotID(response.SnapshotID)
 	return nil
 }
 
@@ -293,12 +559,27 @@ func runPlaylistRemove(playlistID string, trackIDs []string) error {
 		Tracks: tracks,
 	}
 
-	_, err = spotifyClient.Playlists.RemoveTracksFromPlaylist(GetCommandContext(), playlistID, request)
+	backup, err := backupPlaylist(GetCommandContext(), spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+	if playlistIfSnapshot != "" {
+		// Spotify only uses the snapshot ID to place positions, so removals
+		// by URI go ahead whatever it is; check it first
+		if backup != playlistIfSnapshot {
+			return fmt.Errorf("playlist has changed since snapshot %s (it is now at %s); nothing was removed", playlistIfSnapshot, backup)
+		}
+		request.SnapshotID = &playlistIfSnapshot
+	}
+
+	response, err := spotifyClient.Playlists.RemoveTracksFromPlaylist(GetCommandContext(), playlistID, request)
 	if err != nil {
 		return fmt.Errorf("failed to remove tracks from playlist: %w", err)
 	}
 
 	utils.PrintSuccess(fmt.Sprintf("Successfully removed %d track(s) from playlist", len(trackIDs)))
+	printSnapshotID(response.SnapshotID)
+	printUndoHint(playlistID, backup)
 	return nil
 }
 
@@ -497,50 +778,35 @@ func outputPlaylistTracks(playlistID string, tracks *models.Paging[models.Playli
 
 	if playlistFormat == "list" {
 		for i, playlistTrack := range tracks.Items {
-			if playlistTrack.Track == nil {
+			item := playlistTrack.Track
+			if item == nil {
 				fmt.Printf("%d. [Unavailable Track]\n", i+1)
 				continue
 			}
 
-			// Type assert to Track
-			track, ok := playlistTrack.Track.(map[string]interface{})
-			if !ok {
-				fmt.Printf("%d. [Invalid Track Data]\n", i+1)
-				continue
+			name := item.Name()
+			if item.IsEpisode() {
+				name += " (episode)"
 			}
-
-			name, _ := track["name"].(string)
 			fmt.Printf("%d. %s\n", i+1, name)
 
-			if id, ok := track["id"].(string); ok {
+			if id := item.ID(); id != "" {
 				fmt.Printf("   ID: %s\n", id)
 			}
 
-			if artistsInterface, ok := track["artists"].([]interface{}); ok && len(artistsInterface) > 0 {
-				artistNames := make([]string, 0, len(artistsInterface))
-				for _, artistInterface := range artistsInterface {
-					if artist, ok := artistInterface.(map[string]interface{}); ok {
-						if artistName, ok := artist["name"].(string); ok {
-							artistNames = append(artistNames, artistName)
-						}
-					}
-				}
-				if len(artistNames) > 0 {
-					fmt.Printf("   by %s\n", strings.Join(artistNames, ", "))
-				}
+			if artistNames := item.ArtistNames(); len(artistNames) > 0 {
+				fmt.Printf("   by %s\n", strings.Join(artistNames, ", "))
 			}
 
-			if albumInterface, ok := track["album"].(map[string]interface{}); ok {
-				if albumName, ok := albumInterface["name"].(string); ok && albumName != "" {
-					fmt.Printf("   from %s\n", albumName)
-				}
+			if album := item.AlbumName(); album != "" {
+				fmt.Printf("   from %s\n", album)
 			}
 
-			if durationMs, ok := track["duration_ms"].(float64); ok && durationMs > 0 {
-				fmt.Printf("   Duration: %s\n", formatTrackDuration(int(durationMs)))
+			if durationMs := item.DurationMs(); durationMs > 0 {
+				fmt.Printf("   Duration: %s\n", utils.FormatDuration(durationMs))
 			}
 			if playlistTrack.AddedAt != "" {
-				fmt.Printf("   Added: %s\n", formatDate(playlistTrack.AddedAt))
+				fmt.Printf("   Added: %s\n", formatTimestamp(playlistTrack.AddedAt))
 			}
 			fmt.Println()
 		}
@@ -550,59 +816,36 @@ func outputPlaylistTracks(playlistID string, tracks *models.Paging[models.Playli
 		fmt.Println(strings.Repeat("-", 145))
 
 		for _, playlistTrack := range tracks.Items {
-			if playlistTrack.Track == nil {
+			item := playlistTrack.Track
+			if item == nil {
 				fmt.Printf("%-22s %-40s %-25s %-25s %-8s %s\n",
 					"—", "[Unavailable Track]", "—", "—", "—", "—")
 				continue
 			}
 
-			// Type assert to Track
-			track, ok := playlistTrack.Track.(map[string]interface{})
-			if !ok {
-				fmt.Printf("%-22s %-40s %-25s %-25s %-8s %s\n",
-					"—", "[Invalid Track Data]", "—", "—", "—", "—")
-				continue
-			}
-
-			// Extract track data
-			trackID, _ := track["id"].(string)
-			trackName, _ := track["name"].(string)
-
 			artists := "Unknown Artist"
-			if artistsInterface, ok := track["artists"].([]interface{}); ok && len(artistsInterface) > 0 {
-				artistNames := make([]string, 0, len(artistsInterface))
-				for _, artistInterface := range artistsInterface {
-					if artist, ok := artistInterface.(map[string]interface{}); ok {
-						if artistName, ok := artist["name"].(string); ok {
-							artistNames = append(artistNames, artistName)
-						}
-					}
-				}
-				if len(artistNames) > 0 {
-					artists = strings.Join(artistNames, ", ")
-				}
+			if artistNames := item.ArtistNames(); len(artistNames) > 0 {
+				artists = strings.Join(artistNames, ", ")
 			}
 
 			album := "Unknown Album"
-			if albumInterface, ok := track["album"].(map[string]interface{}); ok {
-				if albumName, ok := albumInterface["name"].(string); ok && albumName != "" {
-					album = albumName
-				}
+			if name := item.AlbumName(); name != "" {
+				album = name
 			}
 
 			duration := "—"
-			if durationMs, ok := track["duration_ms"].(float64); ok && durationMs > 0 {
-				duration = formatTrackDuration(int(durationMs))
+			if durationMs := item.DurationMs(); durationMs > 0 {
+				duration = utils.FormatDuration(durationMs)
 			}
 
 			added := "—"
 			if playlistTrack.AddedAt != "" {
-				added = formatDate(playlistTrack.AddedAt)
+				added = formatTimestamp(playlistTrack.AddedAt)
 			}
 
 			fmt.Printf("%-22s %-40s %-25s %-25s %-8s %s\n",
-				trackID,
-				truncateString(trackName, 38),
+				item.ID(),
+				truncateString(item.Name(), 38),
 				truncateString(artists, 23),
 				truncateString(album, 23),
 				duration,
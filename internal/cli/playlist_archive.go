@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/playlistarchive"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistArchiveTemplate string
+	playlistArchiveForce    bool
+	playlistArchiveDryRun   bool
+)
+
+var playlistArchiveCmd = &cobra.Command{
+	Use:   "archive <playlist-id>",
+	Short: "Keep a dated copy of a playlist Spotify rewrites",
+	Long: `Copy a playlist that Spotify replaces on a schedule, such as Discover
+Weekly or Release Radar, into a new private playlist named from
+--name-template, so this week's picks are still there next week.
+
+The template may use {{name}} (the playlist's name), {{date}} (2024-06-03),
+{{week}} (2024-W23), {{month}} (2024-06) and {{year}}.
+
+Running it again does nothing until the playlist changes: the version
+archived is remembered by its snapshot ID, and a playlist you already own
+with the new name is taken as an archive made earlier. That makes it safe
+to run from cron or a scheduled task as often as you like; --force makes a
+copy regardless.`,
+	Example: `  spotify-cli playlist archive 37i9dQZEVXcQ9COmYvdajy --name-template "Discover {{date}}"
+  spotify-cli playlist archive 37i9dQZEVXbn2gZJbGz3yH --name-template "Release Radar {{week}}"
+
+  # Every Monday morning, from crontab
+  0 9 * * 1 spotify-cli playlist archive 37i9dQZEVXcQ9COmYvdajy`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistArchive(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistArchiveCmd)
+	addQuietFlag(playlistArchiveCmd)
+
+	playlistArchiveCmd.Flags().StringVar(&playlistArchiveTemplate, "name-template", playlistarchive.DefaultTemplate, "Name of the archive playlist")
+	playlistArchiveCmd.Flags().BoolVar(&playlistArchiveForce, "force", false, "Archive even if this version was archived already")
+	playlistArchiveCmd.Flags().BoolVar(&playlistArchiveDryRun, "dry-run", false, "Show what would be archived without creating anything")
+}
+
+func runPlaylistArchive(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "name,snapshot_id"})
+	if err != nil {
+		return fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	now := time.Now()
+	name, err := playlistarchive.Render(playlistArchiveTemplate, playlist.Name, now)
+	if err != nil {
+		return err
+	}
+
+	if !playlistArchiveForce {
+		archived, err := playlistarchive.Find(dir, playlistID, playlist.SnapshotID, name)
+		if err != nil {
+			return err
+		}
+		if archived != nil {
+			fmt.Printf("Already archived as %q on %s\n", archived.Name, archived.ArchivedAt.Local().Format("2006-01-02"))
+			return nil
+		}
+
+		// An archive made on another machine, or before this was recorded
+		backend := &queryBackend{spotifyClient: spotifyClient, validator: api.NewValidator()}
+		existing, err := backend.findPlaylist(ctx, name, true)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			fmt.Printf("Already archived: you have a playlist named %q\n", name)
+			return playlistarchive.Record(dir, playlistID, playlistarchive.Entry{
+				SnapshotID: playlist.SnapshotID,
+				PlaylistID: existing.ID,
+				Name:       name,
+				ArchivedAt: now.UTC(),
+			})
+		}
+	}
+
+	export, err := fetchPlaylistExport(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+	var uris []string
+	for _, track := range export.Tracks {
+		if !track.IsLocal {
+			uris = append(uris, track.URI)
+		}
+	}
+
+	if playlistArchiveDryRun {
+		fmt.Printf("Dry run: would archive %d track%s from %q as %q\n", len(uris), pluralize(len(uris)), export.Name, name)
+		return nil
+	}
+
+	description := fmt.Sprintf("%s as of %s, archived with spotify-cli", export.Name, now.Format("2006-01-02"))
+	archive, err := createPlaylistWithTracks(spotifyClient, name, description, uris)
+	if err != nil {
+		return err
+	}
+	if err := playlistarchive.Record(dir, playlistID, playlistarchive.Entry{
+		SnapshotID: export.SnapshotID,
+		PlaylistID: archive.ID,
+		Name:       name,
+		Tracks:     len(uris),
+		ArchivedAt: now.UTC(),
+	}); err != nil {
+		utils.PrintWarning("Archived, but failed to remember it: %v", err)
+	}
+
+	utils.PrintSuccess("Archived %d track%s from %q as %q", len(uris), pluralize(len(uris)), export.Name, name)
+	fmt.Printf("Playlist ID: %s\n", archive.ID)
+	printSnapshotID(archive.SnapshotID)
+	return nil
+}
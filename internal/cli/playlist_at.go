@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistAtPlay     bool
+	playlistAtDeviceID string
+)
+
+var playlistAtCmd = &cobra.Command{
+	Use:   "at <playlist-id> <n>",
+	Short: "Show or play the track at a position in a playlist",
+	Long: `Show the nth track of a playlist, counting from 1. A negative n counts
+from the end, so -1 is the last track; put it after -- so it is not taken
+for a flag. Only that one track is requested, however long the playlist.
+
+With --play, playback of the playlist starts from that track.`,
+	Example: `  spotify-cli playlist at 37i9dQZF1DXcBWIGoYBM5M 12
+  spotify-cli playlist at 37i9dQZF1DXcBWIGoYBM5M -- -1
+  spotify-cli playlist at 37i9dQZF1DXcBWIGoYBM5M 5 --play`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistAt(args[0], args[1])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistAtCmd)
+
+	playlistAtCmd.Flags().BoolVar(&playlistAtPlay, "play", false, "Start playing the playlist from this track")
+	playlistAtCmd.Flags().StringVarP(&playlistAtDeviceID, "device", "d", "", "Target device ID for --play")
+}
+
+func runPlaylistAt(playlistRef, positionArg string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(positionArg)
+	if err != nil || n == 0 {
+		return fmt.Errorf("invalid position %q: use 1 for the first track, -1 for the last", positionArg)
+	}
+
+	newClient := newBrowseClient
+	if playlistAtPlay {
+		newClient = func() (*client.SpotifyClient, error) { return newUserClient("playback") }
+	}
+	spotifyClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	position := n - 1
+	if n < 0 {
+		playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "tracks.total"})
+		if err != nil {
+			return fmt.Errorf("failed to get playlist: %w", err)
+		}
+		total := playlist.Tracks.Total
+		position = total + n
+		if position < 0 {
+			return fmt.Errorf("position %d is before the start of the playlist (%d track%s)", n, total, pluralize(total))
+		}
+	}
+
+	item, total, err := spotifyClient.Playlists.GetPlaylistItem(ctx, playlistID, position, &spotify.PlaylistTracksOptions{
+		AdditionalTypes: []string{"track", "episode"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get track %d: %w", n, err)
+	}
+
+	if playlistAtPlay {
+		if err := spotifyClient.Player.Play(ctx, &spotify.PlayOptions{
+			DeviceID:   playlistAtDeviceID,
+			ContextURI: "spotify:playlist:" + playlistID,
+			Offset:     &spotify.Offset{Position: position},
+		}); err != nil {
+			return fmt.Errorf("failed to start playback: %w", err)
+		}
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"position": position + 1,
+			"total":    total,
+			"item":     item,
+		})
+	}
+
+	if item.Track == nil {
+		fmt.Printf("Track %d of %d is no longer available\n", position+1, total)
+		return nil
+	}
+	if playlistAtPlay {
+		utils.PrintSuccess("Playing track %d of %d", position+1, total)
+	} else {
+		fmt.Printf("Track %d of %d\n", position+1, total)
+	}
+	fmt.Printf("  %s\n", item.Track.Name())
+	if artists := item.Track.ArtistNames(); len(artists) > 0 {
+		fmt.Printf("  by %s\n", strings.Join(artists, ", "))
+	}
+	if album := item.Track.AlbumName(); album != "" {
+		fmt.Printf("  from %s\n", album)
+	}
+	if item.AddedAt != "" {
+		fmt.Printf("  added %s\n", item.AddedAt)
+	}
+	fmt.Printf("  %s\n", item.Track.URI())
+	return nil
+}
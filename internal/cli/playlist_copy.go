@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistCopyAs     string
+	playlistCopyName   string
+	playlistCopyUpdate bool
+	playlistCopyDryRun bool
+)
+
+var playlistCopyCmd = &cobra.Command{
+	Use:   "copy [playlist-id]",
+	Short: "Copy a playlist, optionally into another account",
+	Long: `Copy every track of a playlist into a new private playlist.
+
+With --as, the playlist is read with the current profile and written with
+another one, so a curator can publish a playlist to a brand or label
+account without logging out. Each profile keeps its own tokens; log in to
+the other account once with 'spotify-cli --profile <name> auth login'.
+
+With --update, a playlist of the same name already owned by the target
+account has its tracks replaced instead of a new one being created, which
+keeps a published copy in step with the original on every run. Local
+files cannot be copied and are skipped.`,
+	Example: `  spotify-cli playlist copy 37i9dQZF1DXcBWIGoYBM5M --name "My Copy"
+  spotify-cli playlist copy 37i9dQZF1DXcBWIGoYBM5M --as label
+  spotify-cli --profile personal playlist copy 37i9dQZF1DXcBWIGoYBM5M --as brand --name "Friday Picks" --update`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistCopy(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistCopyCmd)
+	addQuietFlag(playlistCopyCmd)
+
+	playlistCopyCmd.Flags().StringVar(&playlistCopyAs, "as", "", "Write the copy with this profile's account (default is the current profile)")
+	playlistCopyCmd.Flags().StringVar(&playlistCopyName, "name", "", "Name of the copy (default is the original name)")
+	playlistCopyCmd.Flags().BoolVar(&playlistCopyUpdate, "update", false, "Replace the tracks of an existing playlist with the same name")
+	playlistCopyCmd.Flags().BoolVar(&playlistCopyDryRun, "dry-run", false, "Show what would be copied without writing anything")
+}
+
+func runPlaylistCopy(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	source, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+	// Open the target before reading, so a profile that is not logged in
+	// fails fast
+	target, err := newProfileUserClient(playlistCopyAs, "its playlists")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	export, err := fetchPlaylistExport(ctx, source, playlistID)
+	if err != nil {
+		return err
+	}
+
+	name := playlistCopyName
+	if name == "" {
+		name = export.Name
+	}
+	var uris []string
+	for _, track := range export.Tracks {
+		if !track.IsLocal {
+			uris = append(uris, track.URI)
+		}
+	}
+	if skipped := len(export.Tracks) - len(uris); skipped > 0 {
+		utils.PrintWarning("Skipping %d local file%s", skipped, pluralize(skipped))
+	}
+
+	account := "your account"
+	if playlistCopyAs != "" && playlistCopyAs != profileName {
+		account = fmt.Sprintf("profile %q", playlistCopyAs)
+	}
+
+	if playlistCopyDryRun {
+		fmt.Printf("Dry run: would copy %d track%s from %q to %q in %s\n", len(uris), pluralize(len(uris)), export.Name, name, account)
+		return nil
+	}
+
+	if playlistCopyUpdate {
+		backend := &queryBackend{
+			spotifyClient: target,
+			validator:     api.NewValidator(),
+			description:   export.Description,
+		}
+		result, err := backend.WritePlaylist(ctx, name, uris, true)
+		if err != nil {
+			return err
+		}
+		if result.Created {
+			utils.PrintSuccess("Created %q in %s with %d track%s", name, account, len(uris), pluralize(len(uris)))
+		} else {
+			utils.PrintSuccess("Updated %q in %s with %d track%s", name, account, len(uris), pluralize(len(uris)))
+		}
+		fmt.Printf("Playlist ID: %s\n", result.PlaylistID)
+		printSnapshotID(result.SnapshotID)
+		printUndoHint(result.PlaylistID, result.BackupSnapshotID)
+		return nil
+	}
+
+	playlist, err := createPlaylistWithTracks(target, name, export.Description, uris)
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Copied %d track%s to %q in %s", len(uris), pluralize(len(uris)), playlist.Name, account)
+	fmt.Printf("Playlist ID: %s\n", playlist.ID)
+	printSnapshotID(playlist.SnapshotID)
+	return nil
+}
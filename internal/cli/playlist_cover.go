@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/mosaic"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistCoverFormat string
+	playlistCoverOutput string
+)
+
+var playlistCoverCmd = &cobra.Command{
+	Use:   "cover",
+	Short: "Get or set a playlist's cover image",
+	Long: `Get or set the cover image of a playlist.
+
+Setting a cover requires the ugc-image-upload scope; run 'auth login' again if
+you logged in before it was requested.`,
+	Example: `  spotify-cli playlist cover get 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist cover get 37i9dQZF1DXcBWIGoYBM5M cover.jpg
+  spotify-cli playlist cover set 37i9dQZF1DXcBWIGoYBM5M cover.jpg
+  spotify-cli playlist cover generate 37i9dQZF1DXcBWIGoYBM5M`,
+}
+
+var playlistCoverGetCmd = &cobra.Command{
+	Use:   "get [playlist-id] [file]",
+	Short: "Show or download a playlist's cover image",
+	Long: `List the sizes and URLs of a playlist's cover image, or save the largest
+one to a file.
+
+Playlists without a custom cover get a mosaic of their first albums' artwork.`,
+	Args: cobra.RangeArgs(1, 2),
+	Example: `  spotify-cli playlist cover get 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist cover get spotify:playlist:37i9dQZF1DXcBWIGoYBM5M cover.jpg`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := ""
+		if len(args) == 2 {
+			file = args[1]
+		}
+		return runPlaylistCoverGet(args[0], file)
+	},
+}
+
+var playlistCoverSetCmd = &cobra.Command{
+	Use:   "set [playlist-id] [file]",
+	Short: "Upload a JPEG as a playlist's cover image",
+	Long: `Replace a playlist's cover with a JPEG image.
+
+The image may be at most 256 KB once base64 encoded, which is about 190 KB on disk.
+You can only change the cover of playlists you own or collaborate on.`,
+	Args:    cobra.ExactArgs(2),
+	Example: `  spotify-cli playlist cover set 37i9dQZF1DXcBWIGoYBM5M cover.jpg`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistCoverSet(args[0], args[1])
+	},
+}
+
+var playlistCoverGenerateCmd = &cobra.Command{
+	Use:   "generate [playlist-id]",
+	Short: "Make a cover from the playlist's most frequent albums",
+	Long: `Build a 2x2 mosaic from the artwork of the four albums the playlist has the
+most tracks from, and upload it as the playlist's cover.
+
+Playlists drawing on fewer than four albums get the top album's artwork on its
+own. Use --output to save the image to a file for a look first instead of
+uploading it.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli playlist cover generate 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist cover generate 37i9dQZF1DXcBWIGoYBM5M --output preview.jpg`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistCoverGenerate(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistCoverCmd)
+	playlistCoverCmd.AddCommand(playlistCoverGetCmd)
+	playlistCoverCmd.AddCommand(playlistCoverSetCmd)
+	playlistCoverCmd.AddCommand(playlistCoverGenerateCmd)
+
+	playlistCoverGetCmd.Flags().StringVarP(&playlistCoverFormat, "format", "f", "table", "Output format (table, json, yaml)")
+	playlistCoverGenerateCmd.Flags().StringVarP(&playlistCoverOutput, "output", "O", "", "Save the cover to this file instead of uploading it")
+}
+
+func runPlaylistCoverGet(playlistRef, file string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	images, err := spotifyClient.Playlists.GetPlaylistCoverImage(GetCommandContext(), playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist cover: %w", err)
+	}
+
+	if file == "" {
+		return outputPlaylistCover(images)
+	}
+
+	if len(images) == 0 {
+		return fmt.Errorf("playlist has no cover image")
+	}
+
+	largest := images[0]
+	for _, image := range images[1:] {
+		if image.Width > largest.Width {
+			largest = image
+		}
+	}
+
+	size, err := downloadFile(largest.URL, file)
+	if err != nil {
+		return fmt.Errorf("failed to download cover: %w", err)
+	}
+
+	utils.PrintSuccess("Saved cover to %s (%s)", file, formatImageSize(largest, size))
+	return nil
+}
+
+func runPlaylistCoverSet(playlistRef, file string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+
+	spotifyClient, err := newUserClient("playlist covers")
+	if err != nil {
+		return err
+	}
+
+	if err := spotifyClient.Playlists.UploadPlaylistCoverImage(GetCommandContext(), playlistID, data); err != nil {
+		return fmt.Errorf("failed to upload cover: %w", err)
+	}
+
+	utils.PrintSuccess("Uploaded %s as the playlist cover", file)
+	fmt.Println("Spotify may take a few seconds to show the new cover")
+	return nil
+}
+
+func runPlaylistCoverGenerate(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("playlist covers")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	items, err := spotifyClient.Playlists.PlaylistTracksPager(playlistID, nil).All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	albums := mosaic.TopAlbums(items, mosaic.Tiles)
+	if len(albums) == 0 {
+		return fmt.Errorf("playlist has no tracks with album artwork")
+	}
+
+	tileSize := mosaic.DefaultSize
+	if len(albums) == mosaic.Tiles {
+		tileSize /= 2
+	}
+
+	covers := make([]image.Image, 0, len(albums))
+	for _, album := range albums {
+		art, _ := mosaic.PickImage(album.Images, tileSize)
+		cover, err := fetchImage(art.URL)
+		if err != nil {
+			return fmt.Errorf("failed to download artwork for %s: %w", album.Name, err)
+		}
+		covers = append(covers, cover)
+		utils.PrintVerbose("Using artwork of %s", album.Name)
+	}
+
+	img, err := mosaic.Compose(covers, mosaic.DefaultSize)
+	if err != nil {
+		return err
+	}
+
+	// The upload limit applies after base64 encoding, which grows data by a third
+	data, err := mosaic.EncodeJPEG(img, spotify.MaxCoverImageSize/4*3)
+	if err != nil {
+		return err
+	}
+
+	if playlistCoverOutput != "" {
+		if err := os.WriteFile(playlistCoverOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to save cover: %w", err)
+		}
+		utils.PrintSuccess("Saved cover from %d album%s to %s", len(albums), pluralize(len(albums)), playlistCoverOutput)
+		return nil
+	}
+
+	if err := spotifyClient.Playlists.UploadPlaylistCoverImage(ctx, playlistID, data); err != nil {
+		return fmt.Errorf("failed to upload cover: %w", err)
+	}
+
+	utils.PrintSuccess("Uploaded a cover made from %d album%s", len(albums), pluralize(len(albums)))
+	fmt.Println("Spotify may take a few seconds to show the new cover")
+	return nil
+}
+
+// fetchImage downloads and decodes a JPEG or PNG image
+func fetchImage(url string) (image.Image, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	img, _, err := image.Decode(resp.Body)
+	return img, err
+}
+
+func outputPlaylistCover(images []models.Image) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := playlistCoverFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(images)
+	}
+
+	if len(images) == 0 {
+		fmt.Println("Playlist has no cover image")
+		return nil
+	}
+
+	fmt.Printf("%-12s %s\n", "SIZE", "URL")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, image := range images {
+		size := "unknown"
+		if image.Width > 0 {
+			size = fmt.Sprintf("%dx%d", image.Width, image.Height)
+		}
+		fmt.Printf("%-12s %s\n", size, image.URL)
+	}
+
+	return nil
+}
+
+// downloadFile saves the body of url to path and returns its size
+func downloadFile(url, path string) (int64, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func formatImageSize(image models.Image, bytes int64) string {
+	if image.Width > 0 {
+		return fmt.Sprintf("%dx%d, %d KB", image.Width, image.Height, bytes/1024)
+	}
+	return fmt.Sprintf("%d KB", bytes/1024)
+}
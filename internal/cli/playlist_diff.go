@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var playlistDiffFormat string
+
+var playlistDiffCmd = &cobra.Command{
+	Use:   "diff <playlist-a> <playlist-b>",
+	Short: "Compare the tracks of two playlists",
+	Long: `Show the tracks only in the first playlist, only in the second, and in
+both. Tracks match by URI, or by ISRC when the same recording appears as a
+different release, as it often does in a copy made in another market.`,
+	Example: `  spotify-cli playlist diff 37i9dQZF1DXcBWIGoYBM5M 1a2B3c4D5e6F7g8H9i0JkL
+  spotify-cli playlist diff 37i9dQZF1DXcBWIGoYBM5M 1a2B3c4D5e6F7g8H9i0JkL --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistDiff(args[0], args[1])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistDiffCmd)
+
+	playlistDiffCmd.Flags().StringVarP(&playlistDiffFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+// playlistDiff is the comparison of two playlists
+type playlistDiff struct {
+	OnlyA  []playlistExportTrack `json:"only_a" yaml:"only_a"`
+	OnlyB  []playlistExportTrack `json:"only_b" yaml:"only_b"`
+	Common []playlistExportTrack `json:"common" yaml:"common"`
+}
+
+// diffPlaylistTracks compares two track lists, keeping the order of a for
+// common tracks. Duplicates within a list are compared once.
+func diffPlaylistTracks(a, b []playlistExportTrack) playlistDiff {
+	index := func(tracks []playlistExportTrack) (map[string]bool, map[string]bool) {
+		uris, isrcs := make(map[string]bool), make(map[string]bool)
+		for _, track := range tracks {
+			uris[track.URI] = true
+			if track.ISRC != "" {
+				isrcs[track.ISRC] = true
+			}
+		}
+		return uris, isrcs
+	}
+	aURIs, aISRCs := index(a)
+	bURIs, bISRCs := index(b)
+
+	diff := playlistDiff{OnlyA: []playlistExportTrack{}, OnlyB: []playlistExportTrack{}, Common: []playlistExportTrack{}}
+	seen := make(map[string]bool)
+	for _, track := range a {
+		if seen[track.URI] {
+			continue
+		}
+		seen[track.URI] = true
+		if bURIs[track.URI] || (track.ISRC != "" && bISRCs[track.ISRC]) {
+			diff.Common = append(diff.Common, track)
+		} else {
+			diff.OnlyA = append(diff.OnlyA, track)
+		}
+	}
+
+	seen = make(map[string]bool)
+	for _, track := range b {
+		if seen[track.URI] {
+			continue
+		}
+		seen[track.URI] = true
+		if !aURIs[track.URI] && (track.ISRC == "" || !aISRCs[track.ISRC]) {
+			diff.OnlyB = append(diff.OnlyB, track)
+		}
+	}
+	return diff
+}
+
+func runPlaylistDiff(refA, refB string) error {
+	switch playlistDiffFormat {
+	case "table", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, json, yaml", playlistDiffFormat)
+	}
+
+	idA, err := normalizePlaylistID(refA)
+	if err != nil {
+		return err
+	}
+	idB, err := normalizePlaylistID(refB)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	a, err := fetchPlaylistExport(ctx, spotifyClient, idA)
+	if err != nil {
+		return err
+	}
+	b, err := fetchPlaylistExport(ctx, spotifyClient, idB)
+	if err != nil {
+		return err
+	}
+	diff := diffPlaylistTracks(a.Tracks, b.Tracks)
+
+	cfg := config.Get()
+	outputFormat := playlistDiffFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"a":      map[string]string{"id": a.ID, "name": a.Name},
+			"b":      map[string]string{"id": b.ID, "name": b.Name},
+			"only_a": diff.OnlyA,
+			"only_b": diff.OnlyB,
+			"common": diff.Common,
+		})
+	}
+
+	fmt.Printf("A: %s (%s)\n", a.Name, a.ID)
+	fmt.Printf("B: %s (%s)\n", b.Name, b.ID)
+	printDiffSection("Only in A", diff.OnlyA)
+	printDiffSection("Only in B", diff.OnlyB)
+	printDiffSection("In both", diff.Common)
+	return nil
+}
+
+func printDiffSection(title string, tracks []playlistExportTrack) {
+	fmt.Printf("\n%s - %d track%s\n", title, len(tracks), pluralize(len(tracks)))
+	if len(tracks) == 0 {
+		return
+	}
+
+	fmt.Printf("%-40s %-30s %s\n", "TRACK", "ARTIST", "URI")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, track := range tracks {
+		fmt.Printf("%-40s %-30s %s\n",
+			truncateString(track.Name, 38),
+			truncateString(strings.Join(track.Artists, ", "), 28),
+			track.URI)
+	}
+}
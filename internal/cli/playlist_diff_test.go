@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffPlaylistTracks(t *testing.T) {
+	a := []playlistExportTrack{
+		{URI: "spotify:track:both"},
+		{URI: "spotify:track:onlya"},
+		{URI: "spotify:track:relinked-a", ISRC: "USRC17607839"},
+		{URI: "spotify:track:onlya"},
+	}
+	b := []playlistExportTrack{
+		{URI: "spotify:track:onlyb"},
+		{URI: "spotify:track:relinked-b", ISRC: "USRC17607839"},
+		{URI: "spotify:track:both"},
+	}
+
+	uris := func(tracks []playlistExportTrack) []string {
+		out := []string{}
+		for _, track := range tracks {
+			out = append(out, track.URI)
+		}
+		return out
+	}
+
+	diff := diffPlaylistTracks(a, b)
+	if got := uris(diff.OnlyA); !slices.Equal(got, []string{"spotify:track:onlya"}) {
+		t.Errorf("Only in A: %v", got)
+	}
+	if got := uris(diff.OnlyB); !slices.Equal(got, []string{"spotify:track:onlyb"}) {
+		t.Errorf("Only in B: %v", got)
+	}
+	if got := uris(diff.Common); !slices.Equal(got, []string{"spotify:track:both", "spotify:track:relinked-a"}) {
+		t.Errorf("Common: %v", got)
+	}
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/playlistsync"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/tracklist"
+	"github.com/spf13/cobra"
+)
+
+var playlistEditDryRun bool
+
+var playlistEditCmd = &cobra.Command{
+	Use:   "edit <playlist-id>",
+	Short: "Edit a playlist's tracks in your text editor",
+	Long: `Open the tracks of a playlist in $VISUAL or $EDITOR, one per line as a URI
+followed by the artist and title. Delete lines to remove tracks, move them
+to reorder, and add lines with a track URI, link or ID to add tracks. Text
+after the URI is only there to read and is ignored, as are lines starting
+with #.
+
+When the editor exits, the edited list is compared with the playlist and
+only the differences are applied, as with 'playlist sync'. Local files are
+listed as comments and stay where they are. Saving a file with no tracks
+cancels the edit. If the playlist changes on Spotify while you edit, or a
+line cannot be read, nothing is changed and the file is kept so your edits
+are not lost.
+
+The current version is recorded before anything is removed, so the edit can
+be undone with 'playlist rollback'.`,
+	Example: `  spotify-cli playlist edit 37i9dQZF1DXcBWIGoYBM5M
+  EDITOR="code --wait" spotify-cli playlist edit 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist edit 37i9dQZF1DXcBWIGoYBM5M --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistEdit(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistEditCmd)
+	addQuietFlag(playlistEditCmd)
+
+	playlistEditCmd.Flags().BoolVar(&playlistEditDryRun, "dry-run", false, "Show the changes the edit would make without making them")
+}
+
+func runPlaylistEdit(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	ctx := GetCommandContext()
+
+	playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "name,snapshot_id"})
+	if err != nil {
+		return fmt.Errorf("failed to get playlist: %w", err)
+	}
+	items, tracks, err := fetchSyncTarget(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "spotify-cli-playlist-*.txt")
+	if err != nil {
+		return fmt.Errorf("failed to create the file to edit: %w", err)
+	}
+	path := file.Name()
+	writeEditList(file, playlist.Name, items, tracks)
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to write the file to edit: %w", err)
+	}
+
+	// From here on the file holds the user's work; it is only removed once
+	// it has been applied or there was nothing to apply
+	keep := func(err error) error {
+		return fmt.Errorf("%w; your edits are in %s", err, path)
+	}
+
+	if err := runEditor(path); err != nil {
+		return keep(err)
+	}
+	edited, err := os.Open(path)
+	if err != nil {
+		return keep(fmt.Errorf("failed to read the edited file: %w", err))
+	}
+	uris, labels, err := parseEditList(edited)
+	edited.Close()
+	if err != nil {
+		return keep(err)
+	}
+	if len(uris) == 0 {
+		os.Remove(path)
+		fmt.Println("No tracks left in the file; the edit was cancelled")
+		return nil
+	}
+
+	plan := playlistsync.Compute(uris, items, false)
+	if plan.Empty() {
+		os.Remove(path)
+		fmt.Println("No changes")
+		return nil
+	}
+
+	if playlistEditDryRun {
+		printEditPlan(plan, tracks, labels)
+		fmt.Printf("\nYour edits are in %s\n", path)
+		return nil
+	}
+
+	// Removals go by position, so they are only right for the playlist as it
+	// was read
+	current, err := playlistSnapshotID(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return keep(fmt.Errorf("failed to read playlist: %w", err))
+	}
+	if current != playlist.SnapshotID {
+		return keep(fmt.Errorf("the playlist changed on Spotify while you were editing; nothing was changed"))
+	}
+
+	backup := ""
+	if len(plan.Remove) > 0 {
+		if backup, err = backupPlaylist(ctx, spotifyClient, playlistID); err != nil {
+			return keep(err)
+		}
+	}
+	snapshotID, err := applySyncPlan(ctx, spotifyClient, playlistID, playlist.SnapshotID, plan)
+	if err != nil {
+		return keep(err)
+	}
+	os.Remove(path)
+
+	commandReport.Add("tracks added", plan.Added())
+	commandReport.Add("tracks removed", len(plan.Remove))
+	utils.PrintSuccess("Edited %q: %d added, %d removed", playlist.Name, plan.Added(), len(plan.Remove))
+	printSnapshotID(snapshotID)
+	printUndoHint(playlistID, backup)
+	return nil
+}
+
+// writeEditList writes the playlist as the editor shows it: a comment
+// explaining the format, then a line per entry
+func writeEditList(w io.Writer, name string, items []playlistsync.Item, tracks map[string]playlistExportTrack) {
+	fmt.Fprintf(w, "# Editing %q (%d track%s).\n", name, len(items), pluralize(len(items)))
+	fmt.Fprintln(w, "# Delete lines to remove tracks, move them to reorder, and add a track")
+	fmt.Fprintln(w, "# URI, link or ID on a line of its own to add one.")
+	fmt.Fprintln(w, "# Text after the URI and lines starting with # are ignored, so lines of")
+	fmt.Fprintln(w, "# 'playlist export --format tracks' can be pasted in. Save a file with no")
+	fmt.Fprintln(w, "# tracks to cancel.")
+	fmt.Fprintln(w)
+
+	for _, item := range items {
+		entry := tracklist.Entry{URI: item.URI}
+		if track, known := tracks[item.URI]; known {
+			entry.Label = tracklist.Label(track.Artists, track.Name)
+		}
+		switch {
+		case item.URI == "":
+			fmt.Fprintln(w, "# (unavailable, stays in place)")
+		case item.Local:
+			fmt.Fprintf(w, "# %s (local file, stays in place)\n", tracklist.Line(entry))
+		default:
+			fmt.Fprintln(w, tracklist.Line(entry))
+		}
+	}
+}
+
+// parseEditList reads the edited list back, returning the track URIs in
+// order and the text that followed each one
+func parseEditList(r io.Reader) ([]string, map[string]string, error) {
+	var uris []string
+	labels := make(map[string]string)
+	validator := api.NewValidator()
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		ref, label := tracklist.Split(text)
+		uri, err := editLineURI(validator, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		uris = append(uris, uri)
+		if label != "" {
+			labels[uri] = label
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read the edited file: %w", err)
+	}
+	return uris, labels, nil
+}
+
+// editLineURI turns the start of a line into a track or episode URI. It
+// takes URIs, open.spotify.com links and bare track IDs.
+func editLineURI(validator *api.Validator, ref string) (string, error) {
+	if uri, _, ok, err := parseSpotifyLink(ref); ok {
+		if err != nil {
+			return "", err
+		}
+		ref = uri
+	}
+
+	if kind, id, found := strings.Cut(strings.TrimPrefix(ref, "spotify:"), ":"); found && strings.HasPrefix(ref, "spotify:") {
+		if kind != "track" && kind != "episode" {
+			return "", fmt.Errorf("%q is not a track or episode", ref)
+		}
+		if _, err := validator.NormalizeAndValidateIDs([]string{id}); err != nil {
+			return "", fmt.Errorf("invalid %s %q: %w", kind, ref, err)
+		}
+		return ref, nil
+	}
+
+	ids, err := validator.NormalizeAndValidateIDs([]string{ref})
+	if err != nil {
+		return "", fmt.Errorf("%q is not a track URI, link or ID", ref)
+	}
+	return "spotify:track:" + ids[0], nil
+}
+
+// editorCommand returns the editor to run: $VISUAL, then $EDITOR, then a
+// platform default. The variables may hold arguments, as in "code --wait".
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+func runEditor(path string) error {
+	editor := editorCommand()
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor[0], err)
+	}
+	return nil
+}
+
+func printEditPlan(plan playlistsync.Plan, tracks map[string]playlistExportTrack, labels map[string]string) {
+	var added, removed []playlistExportTrack
+	for _, insertion := range plan.Insert {
+		for _, uri := range insertion.URIs {
+			track, ok := tracks[uri]
+			if !ok {
+				track = playlistExportTrack{URI: uri, Name: labels[uri]}
+			}
+			added = append(added, track)
+		}
+	}
+	for _, removal := range plan.Remove {
+		track, ok := tracks[removal.URI]
+		if !ok {
+			track = playlistExportTrack{URI: removal.URI}
+		}
+		removed = append(removed, track)
+	}
+
+	requests := plan.Requests()
+	fmt.Printf("Dry run: would make %d request%s\n", requests, pluralize(requests))
+	printDiffSection("Would add", added)
+	printDiffSection("Would remove", removed)
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/playlistsync"
+)
+
+func TestEditListRoundTrip(t *testing.T) {
+	items := []playlistsync.Item{
+		{URI: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"},
+		{URI: "spotify:local:Artist:Album:Song:200", Local: true},
+		{URI: "spotify:track:1301WleyT98MSxVHPZCA6M"},
+		{Local: true},
+	}
+	tracks := map[string]playlistExportTrack{
+		"spotify:track:4iV5W9uYEdYUVa79Axb7Rh": {Name: "First", Artists: []string{"A", "B"}},
+		"spotify:track:1301WleyT98MSxVHPZCA6M": {Name: "Second", Artists: []string{"C"}},
+	}
+
+	var buf bytes.Buffer
+	writeEditList(&buf, "Mix", items, tracks)
+	if !strings.Contains(buf.String(), "spotify:track:4iV5W9uYEdYUVa79Axb7Rh\tA, B - First\n") {
+		t.Errorf("Expected a line per track with its artists and title, got:\n%s", buf.String())
+	}
+
+	uris, labels, err := parseEditList(&buf)
+	if err != nil {
+		t.Fatalf("parseEditList() error = %v", err)
+	}
+	want := []string{"spotify:track:4iV5W9uYEdYUVa79Axb7Rh", "spotify:track:1301WleyT98MSxVHPZCA6M"}
+	if strings.Join(uris, " ") != strings.Join(want, " ") {
+		t.Errorf("parseEditList() = %v, want %v without the local files", uris, want)
+	}
+	if labels[want[1]] != "C - Second" {
+		t.Errorf("Expected the text after the URI to be kept as a label, got %q", labels[want[1]])
+	}
+
+	// The unchanged list needs no edits
+	if plan := playlistsync.Compute(uris, items, false); !plan.Empty() {
+		t.Errorf("Expected no changes for an unedited list, got %+v", plan)
+	}
+}
+
+func TestParseEditList(t *testing.T) {
+	edited := `# comment
+spotify:track:1301WleyT98MSxVHPZCA6M  moved up
+
+https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh?si=abc
+3n3Ppam7vgaVa1iaRUc9Lp
+spotify:episode:512ojhOuo1ktJprKbVcKyQ
+`
+	uris, _, err := parseEditList(strings.NewReader(edited))
+	if err != nil {
+		t.Fatalf("parseEditList() error = %v", err)
+	}
+	want := []string{
+		"spotify:track:1301WleyT98MSxVHPZCA6M",
+		"spotify:track:4iV5W9uYEdYUVa79Axb7Rh",
+		"spotify:track:3n3Ppam7vgaVa1iaRUc9Lp",
+		"spotify:episode:512ojhOuo1ktJprKbVcKyQ",
+	}
+	if strings.Join(uris, " ") != strings.Join(want, " ") {
+		t.Errorf("parseEditList() = %v, want %v", uris, want)
+	}
+
+	for _, bad := range []string{"spotify:album:4aawyAB9vmqN3uQ7FjRGTy", "not a track", "https://open.spotify.com/concert/123"} {
+		if _, _, err := parseEditList(strings.NewReader("# x\n" + bad + "\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("parseEditList(%q) error = %v, want one naming line 2", bad, err)
+		}
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	if got := editorCommand(); strings.Join(got, " ") != "code --wait" {
+		t.Errorf("editorCommand() = %v", got)
+	}
+	t.Setenv("VISUAL", "nano")
+	if got := editorCommand(); strings.Join(got, " ") != "nano" {
+		t.Errorf("editorCommand() = %v, want $VISUAL first", got)
+	}
+}
@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/tags"
+	"github.com/bambithedeer/spotify-api/internal/tracklist"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistExportFormat    string
+	playlistExportOut       string
+	playlistExportCanonical bool
+)
+
+var playlistExportCmd = &cobra.Command{
+	Use:   "export [playlist-id]",
+	Short: "Export every track of a playlist to JSON, CSV, M3U or a track list",
+	Long: `Export all tracks of a playlist, following pagination to the end, with each
+track's name, artists, album, ISRC, URI and when it was added.
+
+Formats:
+  json   The playlist details and its tracks, for backups
+  csv    One row per track, for spreadsheets and importers such as TuneMyMusic
+  m3u8   An extended M3U playlist of open.spotify.com links
+  tracks One "URI<TAB>Artist - Title" line per track, for keeping in git
+
+A track list diffs cleanly: each change to the playlist is a change to the
+lines of the tracks added, removed or moved. Its header names the snapshot
+and the time of the export; --canonical leaves them out, so exporting an
+unchanged playlist rewrites the file byte for byte. 'playlist edit' and
+'sync m3u' read the same lines.
+
+The JSON export also carries each track's local tags (see 'tag --help').
+Podcast episodes are exported with the show as the album and its publisher as
+the artist. Local files keep their name but have no ISRC. Unavailable items
+are left out.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli playlist export 37i9dQZF1DXcBWIGoYBM5M --out backup.json
+  spotify-cli playlist export 37i9dQZF1DXcBWIGoYBM5M --format csv --out tracks.csv
+  spotify-cli playlist export https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M --format m3u8 > playlist.m3u8
+  spotify-cli playlist export 37i9dQZF1DXcBWIGoYBM5M --format tracks --canonical --out playlists/mix.txt`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistExport(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistExportCmd)
+
+	playlistExportCmd.Flags().StringVarP(&playlistExportFormat, "format", "f", "json", "Export format (json, csv, m3u8, tracks)")
+	playlistExportCmd.Flags().BoolVar(&playlistExportCanonical, "canonical", false, "Write a track list without the snapshot and export time, so unchanged playlists give identical files")
+	playlistExportCmd.Flags().StringVarP(&playlistExportOut, "out", "O", "", "Write to this file instead of stdout")
+}
+
+// playlistExportTrack is one exported playlist entry
+type playlistExportTrack struct {
+	Name       string   `json:"name"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album,omitempty"`
+	ISRC       string   `json:"isrc,omitempty"`
+	URI        string   `json:"uri"`
+	URL        string   `json:"url,omitempty"`
+	DurationMs int      `json:"duration_ms"`
+	AddedAt    string   `json:"added_at,omitempty"`
+	IsLocal    bool     `json:"is_local,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// playlistExport is the JSON export of a playlist
+type playlistExport struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Owner       string                `json:"owner"`
+	OwnerID     string                `json:"owner_id"`
+	Public      bool                  `json:"public"`
+	SnapshotID  string                `json:"snapshot_id"`
+	URI         string                `json:"uri"`
+	ExportedAt  time.Time             `json:"exported_at"`
+	Tracks      []playlistExportTrack `json:"tracks"`
+}
+
+func runPlaylistExport(playlistRef string) error {
+	switch playlistExportFormat {
+	case "json", "csv", "m3u8", "m3u", "tracks":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: json, csv, m3u8, tracks", playlistExportFormat)
+	}
+	if playlistExportCanonical && playlistExportFormat != "tracks" {
+		return fmt.Errorf("--canonical only applies to --format tracks")
+	}
+
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	export, err := fetchPlaylistExport(GetCommandContext(), spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+	addExportTags(export.Tracks)
+
+	out := io.Writer(os.Stdout)
+	if playlistExportOut != "" {
+		file, err := os.Create(playlistExportOut)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", playlistExportOut, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch playlistExportFormat {
+	case "csv":
+		err = writePlaylistCSV(out, export.Tracks)
+	case "m3u8", "m3u":
+		err = writePlaylistM3U(out, export.Name, export.Tracks)
+	case "tracks":
+		err = tracklist.Write(out, playlistTrackList(export), playlistExportCanonical)
+	default:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(export)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if playlistExportOut != "" {
+		utils.PrintSuccess("Exported %d track%s from %q to %s", len(export.Tracks), pluralize(len(export.Tracks)), export.Name, playlistExportOut)
+	}
+	return nil
+}
+
+// fetchPlaylistExport reads a playlist and every one of its tracks, and
+// records that version in the playlist history
+func fetchPlaylistExport(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID string) (*playlistExport, error) {
+	playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{
+		Fields: "id,name,description,owner(id,display_name),public,snapshot_id,uri",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist: %w", err)
+	}
+
+	items, err := spotifyClient.Playlists.PlaylistTracksPager(playlistID, &spotify.PlaylistTracksOptions{
+		AdditionalTypes: []string{"track", "episode"},
+	}).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	export := &playlistExport{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Owner:       playlist.Owner.DisplayName,
+		OwnerID:     playlist.Owner.ID,
+		Public:      playlist.Public,
+		SnapshotID:  playlist.SnapshotID,
+		URI:         playlist.URI,
+		ExportedAt:  time.Now().UTC(),
+		Tracks:      playlistExportTracks(items),
+	}
+	if export.Owner == "" {
+		export.Owner = playlist.Owner.ID
+	}
+	recordPlaylistSnapshot(export)
+	return export, nil
+}
+
+// playlistExportTracks flattens playlist entries, skipping unavailable ones
+func playlistExportTracks(items []models.PlaylistTrack) []playlistExportTrack {
+	tracks := make([]playlistExportTrack, 0, len(items))
+	for _, item := range items {
+		if item.Track == nil || item.Track.URI() == "" {
+			continue
+		}
+
+		track := playlistExportTrack{
+			Name:       item.Track.Name(),
+			Artists:    item.Track.ArtistNames(),
+			Album:      item.Track.AlbumName(),
+			URI:        item.Track.URI(),
+			DurationMs: item.Track.DurationMs(),
+			AddedAt:    item.AddedAt,
+			IsLocal:    item.IsLocal,
+		}
+		switch {
+		case item.Track.IsTrack():
+			track.ISRC = item.Track.Track.ExternalIDs.ISRC
+			track.URL = item.Track.Track.ExternalURLs.Spotify
+		case item.Track.IsEpisode():
+			track.URL = item.Track.Episode.ExternalURLs.Spotify
+		}
+		if track.Artists == nil {
+			track.Artists = []string{}
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks
+}
+
+// addExportTags copies local tags onto the exported tracks, so a JSON backup
+// keeps them. Tags that cannot be read are left out rather than failing the
+// export.
+func addExportTags(tracks []playlistExportTrack) {
+	dir, err := openState()
+	if err != nil {
+		return
+	}
+	taggings, err := tags.All(dir)
+	if err != nil {
+		utils.PrintVerbose("Tags unavailable: %v", err)
+		return
+	}
+
+	byURI := make(map[string][]string)
+	for _, t := range taggings {
+		byURI[t.URI] = append(byURI[t.URI], t.Tag)
+	}
+	for i := range tracks {
+		tracks[i].Tags = byURI[tracks[i].URI]
+	}
+}
+
+func writePlaylistCSV(w io.Writer, tracks []playlistExportTrack) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "artists", "album", "isrc", "uri", "duration_ms", "added_at"}); err != nil {
+		return err
+	}
+	for _, track := range tracks {
+		record := []string{
+			track.Name,
+			strings.Join(track.Artists, ", "),
+			track.Album,
+			track.ISRC,
+			track.URI,
+			strconv.Itoa(track.DurationMs),
+			track.AddedAt,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writePlaylistM3U writes an extended M3U playlist. Entries point at the
+// open.spotify.com link where there is one, which players that understand
+// Spotify links can open; local files have none and use their URI.
+func writePlaylistM3U(w io.Writer, name string, tracks []playlistExportTrack) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", m3uField(name))
+	for _, track := range tracks {
+		title := track.Name
+		if len(track.Artists) > 0 {
+			title = strings.Join(track.Artists, ", ") + " - " + track.Name
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s\n", (track.DurationMs+500)/1000, m3uField(title))
+		if track.Album != "" {
+			fmt.Fprintf(&b, "#EXTALB:%s\n", m3uField(track.Album))
+		}
+		location := track.URL
+		if location == "" {
+			location = track.URI
+		}
+		b.WriteString(location + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// playlistTrackList turns an export into a track list
+func playlistTrackList(export *playlistExport) *tracklist.List {
+	list := &tracklist.List{
+		Name: export.Name,
+		Comments: []string{
+			fmt.Sprintf("Exported from %s, snapshot %s", export.URI, export.SnapshotID),
+			fmt.Sprintf("Exported at %s", export.ExportedAt.UTC().Format(time.RFC3339)),
+		},
+		Entries: make([]tracklist.Entry, len(export.Tracks)),
+	}
+	for i, track := range export.Tracks {
+		list.Entries[i] = tracklist.Entry{URI: track.URI, Label: tracklist.Label(track.Artists, track.Name)}
+	}
+	return list
+}
+
+// m3uField keeps a value on one line
+func m3uField(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
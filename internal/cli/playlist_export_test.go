@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/tracklist"
+)
+
+func TestPlaylistExport(t *testing.T) {
+	items := []models.PlaylistTrack{
+		{AddedAt: "2024-01-02T03:04:05Z", Track: &models.PlaylistItem{Track: &models.Track{
+			Name:         "Song, With Comma",
+			URI:          "spotify:track:4iV5W9uYEdYUVa79Axb7Rh",
+			DurationMs:   185400,
+			Artists:      []models.SimpleArtist{{Name: "A"}, {Name: "B"}},
+			Album:        &models.SimpleAlbum{Name: "Album"},
+			ExternalIDs:  models.ExternalIDs{ISRC: "USRC17607839"},
+			ExternalURLs: models.ExternalURLs{Spotify: "https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh"},
+		}}},
+		{Track: nil},
+		{IsLocal: true, Track: &models.PlaylistItem{Track: &models.Track{
+			Name: "Home Demo", URI: "spotify:local:::Home+Demo:120", DurationMs: 120000,
+		}}},
+	}
+
+	tracks := playlistExportTracks(items)
+	if len(tracks) != 2 {
+		t.Fatalf("Expected unavailable items to be skipped, got %d tracks", len(tracks))
+	}
+	if tracks[1].Artists == nil || !tracks[1].IsLocal {
+		t.Errorf("Unexpected local track %+v", tracks[1])
+	}
+
+	var buf bytes.Buffer
+	if err := writePlaylistCSV(&buf, tracks); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	want := []string{"Song, With Comma", "A, B", "Album", "USRC17607839", "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", "185400", "2024-01-02T03:04:05Z"}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("CSV row = %q, expected %q", records[1], want)
+	}
+
+	buf.Reset()
+	if err := writePlaylistM3U(&buf, "Mix\nTape", tracks); err != nil {
+		t.Fatal(err)
+	}
+	m3u := buf.String()
+	for _, line := range []string{
+		"#EXTM3U",
+		"#PLAYLIST:Mix Tape",
+		"#EXTINF:185,A, B - Song, With Comma",
+		"https://open.spotify.com/track/4iV5W9uYEdYUVa79Axb7Rh",
+		"#EXTINF:120,Home Demo",
+		"spotify:local:::Home+Demo:120",
+	} {
+		if !strings.Contains(m3u, line+"\n") {
+			t.Errorf("Expected M3U to contain %q:\n%s", line, m3u)
+		}
+	}
+
+	buf.Reset()
+	export := &playlistExport{Name: "Mix", URI: "spotify:playlist:x", SnapshotID: "abc", Tracks: tracks}
+	if err := tracklist.Write(&buf, playlistTrackList(export), true); err != nil {
+		t.Fatal(err)
+	}
+	wantList := "#PLAYLIST:Mix\n" +
+		"spotify:track:4iV5W9uYEdYUVa79Axb7Rh\tA, B - Song, With Comma\n" +
+		"spotify:local:::Home+Demo:120\tHome Demo\n"
+	if buf.String() != wantList {
+		t.Errorf("Canonical track list =\n%s\nwant\n%s", buf.String(), wantList)
+	}
+}
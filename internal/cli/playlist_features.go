@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var playlistFeaturesCmd = &cobra.Command{
+	Use:   "features [playlist-id]",
+	Short: "Show audio features of a playlist's tracks",
+	Long: `Show danceability, energy, valence, tempo and key for every track in a
+playlist, followed by the minimum, average and maximum of each column.
+
+Episodes and local files are skipped. Features are requested 100 tracks at a
+time, so long playlists take a few requests.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli playlist features 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist features https://open.spotify.com/playlist/37i9dQZF1DXcBWIGoYBM5M --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistFeatures(args[0])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistFeaturesCmd)
+
+	playlistFeaturesCmd.Flags().StringVarP(&trackFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+func runPlaylistFeatures(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	items, err := spotifyClient.Playlists.PlaylistTracksPager(playlistID, nil).All(GetCommandContext())
+	if err != nil {
+		return fmt.Errorf("failed to get playlist tracks: %w", err)
+	}
+
+	var tracks []models.Track
+	for _, item := range items {
+		if item.IsLocal || !item.Track.IsTrack() {
+			continue
+		}
+		tracks = append(tracks, *item.Track.Track)
+	}
+	if len(tracks) == 0 {
+		fmt.Println("Playlist has no tracks with audio features.")
+		return nil
+	}
+
+	results, err := withAudioFeatures(spotifyClient, tracks)
+	if err != nil {
+		return err
+	}
+
+	return outputTrackFeatures(results, true)
+}
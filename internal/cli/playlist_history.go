@@ -0,0 +1,410 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/snapshots"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistHistoryLimit  int
+	playlistHistoryFormat string
+	playlistRollbackTo    string
+	playlistRollbackDry   bool
+)
+
+var playlistHistoryCmd = &cobra.Command{
+	Use:   "history <playlist-id>",
+	Short: "Show the recorded versions of a playlist",
+	Long: `List the versions of a playlist recorded locally, newest first, with how
+many tracks each added and removed.
+
+Spotify keeps only the current version of a playlist, so versions are
+recorded as spotify-cli sees them: whenever a command reads a playlist in
+full (export, backup, copy, diff, sync and this one), before any command
+removes or replaces tracks, and on every pass of 'playlist snapshot'. Run
+that as a service to catch every change.`,
+	Example: `  spotify-cli playlist history 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli playlist history 37i9dQZF1DXcBWIGoYBM5M --limit 5 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistHistory(args[0])
+	},
+}
+
+var playlistRollbackCmd = &cobra.Command{
+	Use:   "rollback <playlist-id>",
+	Short: "Restore a playlist to a recorded version",
+	Long: `Replace the tracks of a playlist with those of an earlier version from
+'playlist history'. --to takes the snapshot ID of a version, or a time, in
+which case the version in effect at that time is restored.
+
+The current version is recorded first, so a rollback can itself be rolled
+back. Local files cannot be restored and are left out.`,
+	Example: `  spotify-cli playlist rollback 37i9dQZF1DXcBWIGoYBM5M --to 2024-06-01
+  spotify-cli playlist rollback 37i9dQZF1DXcBWIGoYBM5M --to "2024-06-01 18:30" --dry-run
+  spotify-cli playlist rollback 37i9dQZF1DXcBWIGoYBM5M --to MTcsYjdhZDBlNmM5ZDQ2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistRollback(args[0])
+	},
+}
+
+var playlistSnapshotCmd = &cobra.Command{
+	Use:   "snapshot [playlist-id...]",
+	Short: "Record the current version of playlists",
+	Long: `Record the current version of the given playlists, or of every playlist in
+your library, for 'playlist history' and 'playlist rollback'. Playlists
+whose snapshot ID has not changed since they were last recorded are not
+read again, so a pass over a large library is cheap.
+
+Runs every --interval until interrupted; run it as a service with 'daemon
+install', or from cron with --once.`,
+	Example: `  spotify-cli playlist snapshot --once
+  spotify-cli playlist snapshot 37i9dQZF1DXcBWIGoYBM5M --interval 15m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistSnapshot(cmd, args)
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistHistoryCmd)
+	playlistCmd.AddCommand(playlistRollbackCmd)
+	addQuietFlag(playlistRollbackCmd)
+	playlistCmd.AddCommand(playlistSnapshotCmd)
+
+	playlistHistoryCmd.Flags().IntVarP(&playlistHistoryLimit, "limit", "l", 20, "Number of versions to show (0 for all)")
+	playlistHistoryCmd.Flags().StringVarP(&playlistHistoryFormat, "format", "f", "table", "Output format (table, json, yaml)")
+
+	playlistRollbackCmd.Flags().StringVar(&playlistRollbackTo, "to", "", "Snapshot ID or time to restore (RFC 3339, YYYY-MM-DD or YYYY-MM-DD HH:MM)")
+	playlistRollbackCmd.Flags().BoolVar(&playlistRollbackDry, "dry-run", false, "Show what would change without changing anything")
+	playlistRollbackCmd.MarkFlagRequired("to")
+
+	addDaemonFlags(playlistSnapshotCmd, time.Hour)
+}
+
+// recordPlaylistSnapshot keeps the version of a playlist just read in the
+// local history. It is best effort: history is a convenience, and failing
+// to record it must not fail the command that read the playlist.
+func recordPlaylistSnapshot(export *playlistExport) {
+	dir, err := openState()
+	if err != nil {
+		utils.PrintVerbose("Not recording playlist history: %v", err)
+		return
+	}
+	if _, err := snapshots.Record(dir, snapshotOf(export)); err != nil {
+		utils.PrintVerbose("Not recording playlist history: %v", err)
+	}
+}
+
+// backupPlaylist records the current version of a playlist before an edit
+// that removes or replaces tracks, and returns its snapshot ID for the undo
+// hint. Unlike the history recorded as playlists are read, failing to keep
+// the backup stops the edit. A version already recorded is not read again.
+func backupPlaylist(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID string) (string, error) {
+	dir, err := openState()
+	if err != nil {
+		return "", fmt.Errorf("failed to back up playlist: %w", err)
+	}
+	current, err := playlistSnapshotID(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read playlist: %w", err)
+	}
+	latest, err := snapshots.Latest(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to back up playlist: %w", err)
+	}
+	if latest[playlistID] == current {
+		return current, nil
+	}
+
+	export, err := fetchPlaylistExport(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return "", err
+	}
+	if _, err := snapshots.Record(dir, snapshotOf(export)); err != nil {
+		return "", fmt.Errorf("failed to back up playlist: %w", err)
+	}
+	return export.SnapshotID, nil
+}
+
+// printUndoHint shows how to go back to the version backupPlaylist kept,
+// unless --quiet is set
+func printUndoHint(playlistID, snapshotID string) {
+	if playlistQuiet || snapshotID == "" {
+		return
+	}
+	fmt.Printf("Undo with: spotify-cli playlist rollback %s --to %s\n", playlistID, snapshotID)
+}
+
+func snapshotOf(export *playlistExport) snapshots.Snapshot {
+	tracks := make([]string, len(export.Tracks))
+	for i, track := range export.Tracks {
+		tracks[i] = track.URI
+	}
+	return snapshots.Snapshot{
+		PlaylistID: export.ID,
+		Name:       export.Name,
+		SnapshotID: export.SnapshotID,
+		RecordedAt: export.ExportedAt,
+		Tracks:     tracks,
+	}
+}
+
+func runPlaylistHistory(playlistRef string) error {
+	switch playlistHistoryFormat {
+	case "table", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, json, yaml", playlistHistoryFormat)
+	}
+
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	// Reading the playlist records its current version
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+	if _, err := fetchPlaylistExport(GetCommandContext(), spotifyClient, playlistID); err != nil {
+		return err
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	history, err := snapshots.History(dir, playlistID)
+	if err != nil {
+		return err
+	}
+
+	type version struct {
+		RecordedAt time.Time        `json:"recorded_at" yaml:"recorded_at"`
+		SnapshotID string           `json:"snapshot_id" yaml:"snapshot_id"`
+		Name       string           `json:"name" yaml:"name"`
+		Tracks     int              `json:"tracks" yaml:"tracks"`
+		Change     snapshots.Change `json:"change" yaml:"change"`
+	}
+	versions := make([]version, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		v := version{
+			RecordedAt: history[i].RecordedAt,
+			SnapshotID: history[i].SnapshotID,
+			Name:       history[i].Name,
+			Tracks:     len(history[i].Tracks),
+		}
+		if i > 0 {
+			v.Change = snapshots.Diff(history[i-1].Tracks, history[i].Tracks)
+		} else {
+			v.Change.Added = len(history[i].Tracks)
+		}
+		versions = append(versions, v)
+		if playlistHistoryLimit > 0 && len(versions) >= playlistHistoryLimit {
+			break
+		}
+	}
+
+	cfg := config.Get()
+	outputFormat := playlistHistoryFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(versions)
+	}
+
+	fmt.Printf("%s - %d version%s recorded\n\n", history[len(history)-1].Name, len(history), pluralize(len(history)))
+	fmt.Printf("%-17s %-8s %-14s %s\n", "RECORDED", "TRACKS", "CHANGE", "SNAPSHOT")
+	fmt.Println(strings.Repeat("-", 80))
+	for i, v := range versions {
+		change := fmt.Sprintf("+%d -%d", v.Change.Added, v.Change.Removed)
+		switch {
+		case i == len(versions)-1 && len(versions) == len(history):
+			change = "first seen"
+		case v.Change.Reordered:
+			change = "reordered"
+		}
+		fmt.Printf("%-17s %-8d %-14s %s\n", v.RecordedAt.Local().Format("2006-01-02 15:04"), v.Tracks, change, v.SnapshotID)
+	}
+	return nil
+}
+
+// parseRollbackTime accepts an RFC 3339 timestamp or a local date, with or
+// without a time. A bare date means the end of that day.
+func parseRollbackTime(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), true
+	}
+	return time.Time{}, false
+}
+
+func runPlaylistRollback(playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	ctx := GetCommandContext()
+	current, err := fetchPlaylistExport(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	history, err := snapshots.History(dir, playlistID)
+	if err != nil {
+		return err
+	}
+
+	target := snapshots.Find(history, playlistRollbackTo)
+	if target == nil {
+		at, ok := parseRollbackTime(playlistRollbackTo)
+		if !ok {
+			return fmt.Errorf("--to %q is neither a recorded snapshot ID nor a time (RFC 3339, YYYY-MM-DD or YYYY-MM-DD HH:MM)", playlistRollbackTo)
+		}
+		target = snapshots.At(history, at)
+		if target == nil {
+			return fmt.Errorf("no version of this playlist was recorded by %s; see 'spotify-cli playlist history %s'", at.Format("2006-01-02 15:04"), playlistID)
+		}
+	}
+
+	if target.SnapshotID == current.SnapshotID {
+		fmt.Printf("%q is already at that version\n", current.Name)
+		return nil
+	}
+
+	var uris []string
+	for _, uri := range target.Tracks {
+		if !strings.HasPrefix(uri, "spotify:local:") {
+			uris = append(uris, uri)
+		}
+	}
+	change := snapshots.Diff(snapshotOf(current).Tracks, uris)
+
+	if playlistRollbackDry {
+		fmt.Printf("Dry run: would restore %q to the version recorded %s (%d track%s: +%d -%d)\n",
+			current.Name, target.RecordedAt.Local().Format("2006-01-02 15:04"), len(uris), pluralize(len(uris)), change.Added, change.Removed)
+		return nil
+	}
+
+	snapshotID, err := replacePlaylistTracks(ctx, spotifyClient, playlistID, uris)
+	if err != nil {
+		return err
+	}
+	utils.PrintSuccess("Restored %q to the version recorded %s: %d added, %d removed",
+		current.Name, target.RecordedAt.Local().Format("2006-01-02 15:04"), change.Added, change.Removed)
+	printSnapshotID(snapshotID)
+	printUndoHint(playlistID, current.SnapshotID)
+	if skipped := len(target.Tracks) - len(uris); skipped > 0 {
+		utils.PrintWarning("Left out %d local file%s", skipped, pluralize(skipped))
+	}
+	return nil
+}
+
+// replacePlaylistTracks sets the tracks of a playlist to uris and returns
+// the new snapshot ID. The API replaces at most 100 tracks per request, so
+// the rest are appended in batches.
+func replacePlaylistTracks(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID string, uris []string) (string, error) {
+	first := min(100, len(uris))
+	response, err := spotifyClient.Playlists.ReplacePlaylistTracks(ctx, playlistID, uris[:first])
+	if err != nil {
+		return "", fmt.Errorf("failed to replace playlist tracks: %w", err)
+	}
+	snapshotID := response.SnapshotID
+
+	for start := first; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		response, err := spotifyClient.Playlists.AddTracksToPlaylist(ctx, playlistID, &spotify.AddTracksRequest{URIs: uris[start:end]})
+		if err != nil {
+			return "", fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		snapshotID = response.SnapshotID
+	}
+	return snapshotID, nil
+}
+
+func runPlaylistSnapshot(cmd *cobra.Command, refs []string) error {
+	ids := make([]string, len(refs))
+	for i, ref := range refs {
+		id, err := normalizePlaylistID(ref)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	return runDaemonLoop(cmd, "playlist-snapshot", func(ctx context.Context, report *daemon.Report) error {
+		latest, err := snapshots.Latest(dir)
+		if err != nil {
+			return err
+		}
+
+		current := make(map[string]string)
+		if len(ids) == 0 {
+			playlists, err := spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get playlists: %w", err)
+			}
+			for _, playlist := range playlists {
+				current[playlist.ID] = playlist.SnapshotID
+			}
+		} else {
+			for _, id := range ids {
+				snapshotID, err := playlistSnapshotID(ctx, spotifyClient, id)
+				if err != nil {
+					return fmt.Errorf("failed to read playlist %s: %w", id, err)
+				}
+				current[id] = snapshotID
+			}
+		}
+
+		for id, snapshotID := range current {
+			if latest[id] == snapshotID {
+				report.Add("unchanged", 1)
+				continue
+			}
+			export, err := fetchPlaylistExport(ctx, spotifyClient, id)
+			if err != nil {
+				report.Add("failed", 1)
+				utils.PrintWarning("Failed to read playlist %s: %v", id, err)
+				continue
+			}
+			utils.PrintVerbose("Recorded %q at %s", export.Name, export.SnapshotID)
+			report.Add("recorded", 1)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistMergeDedupe  bool
+	playlistMergeByAdded bool
+	playlistMergeNew     bool
+	playlistMergeAs      string
+	playlistMergeDryRun  bool
+	playlistMergeShuffle bool
+	playlistMergeOrder   orderingFlags
+)
+
+var playlistMergeCmd = &cobra.Command{
+	Use:   "merge <target> <source...>",
+	Short: "Combine several playlists into one",
+	Long: `Append the tracks of every source playlist to the target playlist, in the
+order the sources are given.
+
+With --new, the target is the name of a new private playlist to create
+instead of an existing playlist. Tracks appearing more than once, or
+already in the target, are added only once unless --dedupe=false. With
+--by-added, the merged tracks are ordered by when they were added to their
+playlist, oldest first, rather than source by source. With --shuffle they
+are shuffled instead, keeping the same artist at least --artist-spacing
+tracks apart and, with --no-album-repeat, never playing two tracks from the
+same album back to back, whenever the sources allow it.
+
+Local files cannot be added and are skipped. Use --as to write the result
+with another profile's account (see 'playlist copy --help').`,
+	Example: `  spotify-cli playlist merge 37i9dQZF1DXcBWIGoYBM5M 37i9dQZF1DX0XUsuxWHRQd 37i9dQZF1DX4dyzvuaRJ0n
+  spotify-cli playlist merge --new "Everything" 37i9dQZF1DXcBWIGoYBM5M 37i9dQZF1DX0XUsuxWHRQd --by-added
+  spotify-cli playlist merge 37i9dQZF1DXcBWIGoYBM5M 37i9dQZF1DX0XUsuxWHRQd --dedupe=false --dry-run
+  spotify-cli playlist merge --new "Shuffled" 37i9dQZF1DXcBWIGoYBM5M 37i9dQZF1DX0XUsuxWHRQd --shuffle --seed 42`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistMerge(args[0], args[1:])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistMergeCmd)
+	addQuietFlag(playlistMergeCmd)
+
+	playlistMergeCmd.Flags().BoolVar(&playlistMergeDedupe, "dedupe", true, "Add each track only once, skipping tracks already in the target")
+	playlistMergeCmd.Flags().BoolVar(&playlistMergeByAdded, "by-added", false, "Order merged tracks by when they were added, oldest first")
+	playlistMergeCmd.Flags().BoolVar(&playlistMergeNew, "new", false, "Create a new playlist named <target> instead of appending to one")
+	playlistMergeCmd.Flags().StringVar(&playlistMergeAs, "as", "", "Write with this profile's account (default is the current profile)")
+	playlistMergeCmd.Flags().BoolVar(&playlistMergeDryRun, "dry-run", false, "Show what would be added without changing anything")
+	playlistMergeCmd.Flags().BoolVar(&playlistMergeShuffle, "shuffle", false, "Shuffle the merged tracks, spacing out artists")
+	addOrderingFlags(playlistMergeCmd, &playlistMergeOrder, 3)
+	playlistMergeCmd.MarkFlagsMutuallyExclusive("shuffle", "by-added")
+}
+
+func runPlaylistMerge(target string, sourceRefs []string) error {
+	var engine *ordering.Engine
+	if playlistMergeShuffle {
+		var err error
+		if engine, err = playlistMergeOrder.engine(); err != nil {
+			return err
+		}
+	}
+
+	targetID := ""
+	if !playlistMergeNew {
+		id, err := normalizePlaylistID(target)
+		if err != nil {
+			return err
+		}
+		targetID = id
+	}
+	sourceIDs := make([]string, len(sourceRefs))
+	for i, ref := range sourceRefs {
+		id, err := normalizePlaylistID(ref)
+		if err != nil {
+			return err
+		}
+		sourceIDs[i] = id
+	}
+
+	reader, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+	writer, err := newProfileUserClient(playlistMergeAs, "its playlists")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	sources := make([][]playlistExportTrack, len(sourceIDs))
+	for i, id := range sourceIDs {
+		export, err := fetchPlaylistExport(ctx, reader, id)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", sourceRefs[i], err)
+		}
+		utils.PrintVerbose("Read %d track%s from %q", len(export.Tracks), pluralize(len(export.Tracks)), export.Name)
+		sources[i] = export.Tracks
+	}
+
+	var existing []string
+	targetName := target
+	if targetID != "" {
+		export, err := fetchPlaylistExport(ctx, writer, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to read target playlist: %w", err)
+		}
+		targetName = export.Name
+		for _, track := range export.Tracks {
+			existing = append(existing, track.URI)
+		}
+	}
+
+	uris := mergePlaylistTracks(sources, existing, playlistMergeDedupe, playlistMergeByAdded, engine)
+
+	if playlistMergeDryRun {
+		fmt.Printf("Dry run: would add %d track%s from %d playlist%s to %q\n", len(uris), pluralize(len(uris)), len(sources), pluralize(len(sources)), targetName)
+		return nil
+	}
+
+	if targetID == "" {
+		playlist, err := createPlaylistWithTracks(writer, target, "Merged with spotify-cli playlist merge", uris)
+		if err != nil {
+			return err
+		}
+		utils.PrintSuccess("Created %q with %d track%s", playlist.Name, len(uris), pluralize(len(uris)))
+		fmt.Printf("Playlist ID: %s\n", playlist.ID)
+		printSnapshotID(playlist.SnapshotID)
+		return nil
+	}
+
+	if len(uris) == 0 {
+		fmt.Printf("Nothing to add: %q already has every track\n", targetName)
+		return nil
+	}
+
+	// The API accepts at most 100 tracks per request
+	var snapshotID string
+	for start := 0; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		response, err := writer.Playlists.AddTracksToPlaylist(ctx, targetID, &spotify.AddTracksRequest{URIs: uris[start:end]})
+		if err != nil {
+			return fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		snapshotID = response.SnapshotID
+	}
+	commandReport.Add("tracks added", len(uris))
+	utils.PrintSuccess("Added %d track%s to %q", len(uris), pluralize(len(uris)), targetName)
+	printSnapshotID(snapshotID)
+	return nil
+}
+
+// mergePlaylistTracks returns the URIs to add to a playlist already holding
+// existing, from each source in turn, ordered by when each track was added
+// with byAdded, or shuffled by engine when it is set. Local files are left
+// out, since they cannot be added.
+func mergePlaylistTracks(sources [][]playlistExportTrack, existing []string, dedupe, byAdded bool, engine *ordering.Engine) []string {
+	var tracks []playlistExportTrack
+	for _, source := range sources {
+		for _, track := range source {
+			if !track.IsLocal {
+				tracks = append(tracks, track)
+			}
+		}
+	}
+
+	if byAdded {
+		// Added times are RFC 3339 in UTC, so they sort as strings; tracks
+		// without one go last
+		sort.SliceStable(tracks, func(i, j int) bool {
+			a, b := tracks[i].AddedAt, tracks[j].AddedAt
+			if a == "" || b == "" {
+				return b == "" && a != ""
+			}
+			return a < b
+		})
+	}
+
+	seen := make(map[string]bool)
+	if dedupe {
+		for _, uri := range existing {
+			seen[uri] = true
+		}
+	}
+
+	merged := make([]playlistExportTrack, 0, len(tracks))
+	for _, track := range tracks {
+		if dedupe {
+			if seen[track.URI] {
+				continue
+			}
+			seen[track.URI] = true
+		}
+		merged = append(merged, track)
+	}
+
+	if engine != nil {
+		merged = ordering.Shuffle(engine, merged, exportTrackKeys)
+	}
+
+	uris := make([]string, len(merged))
+	for i, track := range merged {
+		uris[i] = track.URI
+	}
+	return uris
+}
+
+// exportTrackKeys identifies an exported track to the ordering engine. Exports
+// only have names, so albums are told apart by their artist too.
+func exportTrackKeys(track playlistExportTrack) ordering.Keys {
+	var keys ordering.Keys
+	if len(track.Artists) > 0 {
+		keys.Artist = track.Artists[0]
+	}
+	if track.Album != "" {
+		keys.Album = keys.Artist + "\x00" + track.Album
+	}
+	return keys
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+)
+
+func TestMergePlaylistTracks(t *testing.T) {
+	first := []playlistExportTrack{
+		{URI: "spotify:track:a", AddedAt: "2024-03-01T00:00:00Z"},
+		{URI: "spotify:track:b", AddedAt: "2024-01-01T00:00:00Z"},
+		{URI: "spotify:local:::Demo:100", IsLocal: true},
+	}
+	second := []playlistExportTrack{
+		{URI: "spotify:track:b", AddedAt: "2023-06-01T00:00:00Z"},
+		{URI: "spotify:track:c"},
+		{URI: "spotify:track:d", AddedAt: "2024-02-01T00:00:00Z"},
+	}
+	sources := [][]playlistExportTrack{first, second}
+
+	for _, tt := range []struct {
+		name     string
+		existing []string
+		dedupe   bool
+		byAdded  bool
+		want     []string
+	}{
+		{"in source order", nil, true, false, []string{"spotify:track:a", "spotify:track:b", "spotify:track:c", "spotify:track:d"}},
+		{"keeping duplicates", nil, false, false, []string{"spotify:track:a", "spotify:track:b", "spotify:track:b", "spotify:track:c", "spotify:track:d"}},
+		{"skipping the target's tracks", []string{"spotify:track:a"}, true, false, []string{"spotify:track:b", "spotify:track:c", "spotify:track:d"}},
+		{"by added date", nil, true, true, []string{"spotify:track:b", "spotify:track:d", "spotify:track:a", "spotify:track:c"}},
+	} {
+		got := mergePlaylistTracks(sources, tt.existing, tt.dedupe, tt.byAdded, nil)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMergePlaylistTracks_Shuffle(t *testing.T) {
+	var first, second []playlistExportTrack
+	for i := 0; i < 4; i++ {
+		first = append(first, playlistExportTrack{URI: fmt.Sprintf("spotify:track:a%d", i), Artists: []string{"A"}, Album: "One"})
+		second = append(second, playlistExportTrack{URI: fmt.Sprintf("spotify:track:b%d", i), Artists: []string{"B"}, Album: "Two"})
+	}
+	sources := [][]playlistExportTrack{first, second}
+
+	engine := ordering.NewEngine(ordering.Constraints{ArtistSpacing: 1}, 42)
+	got := mergePlaylistTracks(sources, nil, true, false, engine)
+	if len(got) != 8 {
+		t.Fatalf("Expected all 8 tracks, got %v", got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i][len("spotify:track:")] == got[i-1][len("spotify:track:")] {
+			t.Errorf("Expected artists to alternate, got %v", got)
+			break
+		}
+	}
+
+	again := mergePlaylistTracks(sources, nil, true, false, ordering.NewEngine(ordering.Constraints{ArtistSpacing: 1}, 42))
+	if !slices.Equal(got, again) {
+		t.Errorf("Expected the same seed to give the same order, got %v and %v", got, again)
+	}
+}
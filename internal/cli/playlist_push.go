@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+// pushMatch is what copying a playlist to a media server did with one
+// Spotify track
+type pushMatch struct {
+	Track  string `json:"track" yaml:"track"`
+	SongID string `json:"song_id,omitempty" yaml:"song_id,omitempty"`
+	Song   string `json:"song,omitempty" yaml:"song,omitempty"`
+}
+
+// pushResult is the outcome of copying a playlist to a media server
+type pushResult struct {
+	Playlist   string      `json:"playlist" yaml:"playlist"`
+	PlaylistID string      `json:"playlist_id,omitempty" yaml:"playlist_id,omitempty"`
+	Created    bool        `json:"created" yaml:"created"`
+	DryRun     bool        `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+	Matched    int         `json:"matched" yaml:"matched"`
+	Missing    int         `json:"missing" yaml:"missing"`
+	Tracks     []pushMatch `json:"tracks" yaml:"tracks"`
+}
+
+// songIDs returns the IDs of the matched songs, in playlist order
+func (r *pushResult) songIDs() []string {
+	ids := make([]string, 0, r.Matched)
+	for _, match := range r.Tracks {
+		if match.SongID != "" {
+			ids = append(ids, match.SongID)
+		}
+	}
+	return ids
+}
+
+// matchPlaylistTracks looks each track of a playlist up with find, which
+// returns the ID and a description of the song it matched, or an empty ID.
+// Local files are skipped, as no server could have them under a Spotify name.
+func matchPlaylistTracks(export *playlistExport, find func(trackmatch.Track) (string, string, error)) ([]pushMatch, error) {
+	var matches []pushMatch
+	for i, track := range export.Tracks {
+		if track.IsLocal {
+			continue
+		}
+		label := fmt.Sprintf("%s - %s", strings.Join(track.Artists, ", "), track.Name)
+		utils.PrintVerbose("Looking up %d/%d: %s", i+1, len(export.Tracks), label)
+
+		id, song, err := find(trackmatch.Track{
+			Title:    track.Name,
+			Artists:  track.Artists,
+			Album:    track.Album,
+			Duration: time.Duration(track.DurationMs) * time.Millisecond,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, pushMatch{Track: label, SongID: id, Song: song})
+	}
+	return matches, nil
+}
+
+// newPushResult counts the matches of a push to the playlist name
+func newPushResult(name string, matches []pushMatch, dryRun bool) *pushResult {
+	result := &pushResult{Playlist: name, DryRun: dryRun, Tracks: matches}
+	for _, match := range matches {
+		if match.SongID != "" {
+			result.Matched++
+		}
+	}
+	result.Missing = len(matches) - result.Matched
+	commandReport.Add("tracks matched", result.Matched)
+	commandReport.Add("tracks missing", result.Missing)
+	return result
+}
+
+// printPushResult reports a push to server, listing the tracks it could
+// not find and, in a dry run, the matches
+func printPushResult(server string, result *pushResult) error {
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(result)
+	}
+
+	for _, match := range result.Tracks {
+		if match.SongID == "" {
+			fmt.Printf("  - %s (not in %s)\n", match.Track, server)
+		} else if result.DryRun {
+			fmt.Printf("  + %s -> %s\n", match.Track, match.Song)
+		}
+	}
+	total := len(result.Tracks)
+	switch {
+	case result.DryRun:
+		fmt.Printf("Dry run: would save %d of %d track%s to %q in %s\n", result.Matched, total, pluralize(total), result.Playlist, server)
+	case result.Created:
+		utils.PrintSuccess("Created %q in %s with %d of %d track%s", result.Playlist, server, result.Matched, total, pluralize(total))
+	default:
+		utils.PrintSuccess("Updated %q in %s with %d of %d track%s", result.Playlist, server, result.Matched, total, pluralize(total))
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchWithinWhere  []string
+	searchWithinLimit  int
+	searchWithinFormat string
+)
+
+var playlistSearchCmd = &cobra.Command{
+	Use:   "search <playlist-id> [text]",
+	Short: "Search the tracks of a playlist",
+	Long: `Find tracks in a playlist whose name, artists or album contain every word
+of the text, ignoring case. The Spotify API can only search the whole
+catalog, so the playlist is read and searched locally.
+
+--where keeps tracks matching a condition written as in 'query' filter(),
+such as "tempo>=120 and tempo<=130" or "energy>0.7"; repeat it to add more.
+Conditions on audio features fetch them for the playlist's tracks.
+
+The tracks are cached by snapshot ID, so searching a playlist again while it
+has not changed costs a single request. --no-cache reads it afresh.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli playlist search 37i9dQZF1DXcBWIGoYBM5M "bowie"
+  spotify-cli playlist search 37i9dQZF1DXcBWIGoYBM5M "live" --where "duration>300"
+  spotify-cli playlist search 37i9dQZF1DXcBWIGoYBM5M --where "tempo>=120 and tempo<=130" --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text := ""
+		if len(args) > 1 {
+			text = args[1]
+		}
+		return runPlaylistSearch(args[0], text)
+	},
+}
+
+var librarySearchCmd = &cobra.Command{
+	Use:   "search [text]",
+	Short: "Search your saved tracks",
+	Long: `Find saved tracks whose name, artists or album contain every word of the
+text, ignoring case, optionally narrowed with --where conditions as in
+'playlist search'. Your library is read and searched locally.
+
+The tracks are cached until you save or remove one, so searching again
+costs a single request. --no-cache reads the library afresh.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli library search "radiohead"
+  spotify-cli library search --where "danceability>0.8" --where "popularity<30"
+  spotify-cli library search "remix" --limit 10`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		text := ""
+		if len(args) > 0 {
+			text = args[0]
+		}
+		return runLibrarySearch(text)
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistSearchCmd)
+	libraryCmd.AddCommand(librarySearchCmd)
+
+	for _, cmd := range []*cobra.Command{playlistSearchCmd, librarySearchCmd} {
+		cmd.Flags().StringArrayVarP(&searchWithinWhere, "where", "w", nil, "Keep tracks matching a condition, such as \"energy>0.7\" (repeatable)")
+		cmd.Flags().IntVarP(&searchWithinLimit, "limit", "l", 0, "Show at most this many matches (0 for all)")
+		cmd.Flags().StringVarP(&searchWithinFormat, "format", "f", "table", "Output format (table, json, yaml)")
+	}
+}
+
+func runPlaylistSearch(playlistRef, text string) error {
+	// Compile first so a mistyped condition costs no requests
+	search, err := query.CompileSearch(text, searchWithinWhere)
+	if err != nil {
+		return err
+	}
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	ctx := GetCommandContext()
+
+	snapshotID, err := playlistSnapshotID(ctx, spotifyClient, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to read playlist: %w", err)
+	}
+	items, err := cachedTracks("playlist:"+playlistID, snapshotID, func() ([]query.Item, error) {
+		pager := spotifyClient.Playlists.PlaylistTracksPager(playlistID, nil)
+		items, err := query.Collect(ctx, query.FromPager(pager, playlistTrackItem))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get playlist tracks: %w", err)
+		}
+		return items, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	backend := &queryBackend{spotifyClient: spotifyClient, validator: api.NewValidator()}
+	found, err := search.Run(ctx, backend, items)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	return outputSearchWithin("Playlist search", found, len(items))
+}
+
+func runLibrarySearch(text string) error {
+	search, err := query.CompileSearch(text, searchWithinWhere)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+	ctx := GetCommandContext()
+
+	version, _, err := savedTracksVersion(ctx, spotifyClient)
+	if err != nil {
+		return err
+	}
+	items, err := cachedTracks("library", version, func() ([]query.Item, error) {
+		pager := spotifyClient.Library.SavedTracksPager(nil)
+		items, err := query.Collect(ctx, query.FromPager(pager, savedTrackItem))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get saved tracks: %w", err)
+		}
+		return items, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	backend := &queryBackend{spotifyClient: spotifyClient, validator: api.NewValidator()}
+	found, err := search.Run(ctx, backend, items)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	return outputSearchWithin("Library search", found, len(items))
+}
+
+// cachedTracks returns the tracks kept for key if they are at version, and
+// otherwise reads them and keeps them. The cache only saves requests, so
+// failing to use it is not an error.
+func cachedTracks(key, version string, read func() ([]query.Item, error)) ([]query.Item, error) {
+	cache := trackCache()
+
+	var items []query.Item
+	ok, err := cache.Get(key, version, &items)
+	if err != nil {
+		utils.PrintVerbose("Not using cached tracks: %v", err)
+	}
+	if ok {
+		utils.PrintVerbose("Searching %d cached track%s", len(items), pluralize(len(items)))
+		return items, nil
+	}
+
+	items, err = read()
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Put(key, version, items); err != nil {
+		utils.PrintVerbose("Not caching tracks: %v", err)
+	}
+	return items, nil
+}
+
+func outputSearchWithin(title string, found []query.Item, total int) error {
+	matches := len(found)
+	if searchWithinLimit > 0 && len(found) > searchWithinLimit {
+		found = found[:searchWithinLimit]
+	}
+
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := searchWithinFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"items":   found,
+			"matches": matches,
+			"tracks":  total,
+		})
+	}
+
+	fmt.Printf("%s - %d of %d track%s match\n\n", title, matches, total, pluralize(total))
+	printQueryItems(found)
+	if len(found) < matches {
+		fmt.Printf("\n... and %d more (raise --limit to see them)\n", matches-len(found))
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/playlistsync"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	playlistSyncDryRun     bool
+	playlistSyncAppendOnly bool
+	playlistSyncAs         string
+	playlistSyncForce      bool
+)
+
+var playlistSyncCmd = &cobra.Command{
+	Use:   "sync <source> <target>",
+	Short: "Make one playlist match another",
+	Long: `Make the target playlist hold the tracks of the source playlist, in the
+same order: tracks missing from the target are added, tracks not in the
+source are removed, and tracks out of place are moved. Before tracks are
+removed the target's current version is recorded, so 'playlist rollback'
+can undo the sync.
+
+Tracks already in the right order are left alone, so they keep the date
+they were added; only the tracks that differ are changed. The snapshot IDs
+of both playlists are remembered after each sync, so syncing a pair that
+has not changed since costs two small requests. Use --force to compare the
+tracks anyway.
+
+With --append-only, nothing is removed or reordered: tracks of the source
+missing from the target are appended to it. Local files cannot be added or
+removed and are left as they are. Use --as to write the target with another
+profile's account (see 'playlist copy --help').`,
+	Example: `  spotify-cli playlist sync 37i9dQZF1DXcBWIGoYBM5M 1a2B3c4D5e6F7g8H9i0JkL --dry-run
+  spotify-cli playlist sync 37i9dQZF1DXcBWIGoYBM5M 1a2B3c4D5e6F7g8H9i0JkL
+  spotify-cli playlist sync 37i9dQZF1DXcBWIGoYBM5M 1a2B3c4D5e6F7g8H9i0JkL --append-only --as label`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlaylistSync(args[0], args[1])
+	},
+}
+
+func init() {
+	playlistCmd.AddCommand(playlistSyncCmd)
+	addQuietFlag(playlistSyncCmd)
+
+	playlistSyncCmd.Flags().BoolVar(&playlistSyncDryRun, "dry-run", false, "Show the changes without making them")
+	playlistSyncCmd.Flags().BoolVar(&playlistSyncAppendOnly, "append-only", false, "Only add missing tracks; never remove or reorder")
+	playlistSyncCmd.Flags().StringVar(&playlistSyncAs, "as", "", "Write the target with this profile's account (default is the current profile)")
+	playlistSyncCmd.Flags().BoolVar(&playlistSyncForce, "force", false, "Compare the tracks even if neither playlist has changed since the last sync")
+}
+
+func runPlaylistSync(sourceRef, targetRef string) error {
+	sourceID, err := normalizePlaylistID(sourceRef)
+	if err != nil {
+		return err
+	}
+	targetID, err := normalizePlaylistID(targetRef)
+	if err != nil {
+		return err
+	}
+	if sourceID == targetID {
+		return fmt.Errorf("source and target are the same playlist")
+	}
+
+	reader, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+	writer, err := newProfileUserClient(playlistSyncAs, "its playlists")
+	if err != nil {
+		return err
+	}
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	sourceSnapshot, err := playlistSnapshotID(ctx, reader, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to read source playlist: %w", err)
+	}
+	targetSnapshot, err := playlistSnapshotID(ctx, writer, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to read target playlist: %w", err)
+	}
+
+	last, err := playlistsync.Last(dir, sourceID, targetID)
+	if err != nil {
+		return err
+	}
+	if !playlistSyncForce && last.InSync(sourceSnapshot, targetSnapshot, playlistSyncAppendOnly) {
+		fmt.Printf("Already in sync: neither playlist has changed since %s\n", last.SyncedAt.Local().Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	source, err := fetchPlaylistExport(ctx, reader, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to read source playlist: %w", err)
+	}
+	var sourceURIs []string
+	for _, track := range source.Tracks {
+		if !track.IsLocal {
+			sourceURIs = append(sourceURIs, track.URI)
+		}
+	}
+
+	targetItems, targetTracks, err := fetchSyncTarget(ctx, writer, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to read target playlist: %w", err)
+	}
+
+	plan := playlistsync.Compute(sourceURIs, targetItems, playlistSyncAppendOnly)
+
+	if playlistSyncDryRun {
+		printSyncPlan(plan, source.Tracks, targetTracks)
+		return nil
+	}
+
+	if plan.Empty() {
+		fmt.Println("Already in sync")
+	} else {
+		backup := ""
+		if len(plan.Remove) > 0 {
+			if backup, err = backupPlaylist(ctx, writer, targetID); err != nil {
+				return err
+			}
+		}
+		targetSnapshot, err = applySyncPlan(ctx, writer, targetID, targetSnapshot, plan)
+		if err != nil {
+			return err
+		}
+		commandReport.Add("tracks added", plan.Added())
+		commandReport.Add("tracks removed", len(plan.Remove))
+		utils.PrintSuccess("Synced %q: %d added, %d removed", source.Name, plan.Added(), len(plan.Remove))
+		printSnapshotID(targetSnapshot)
+		printUndoHint(targetID, backup)
+	}
+
+	return playlistsync.Record(dir, playlistsync.Pair{
+		Source:         sourceID,
+		Target:         targetID,
+		SourceSnapshot: sourceSnapshot,
+		TargetSnapshot: targetSnapshot,
+		AppendOnly:     playlistSyncAppendOnly,
+		SyncedAt:       time.Now().UTC(),
+	})
+}
+
+// playlistSnapshotID reads just the snapshot ID of a playlist
+func playlistSnapshotID(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID string) (string, error) {
+	playlist, err := spotifyClient.Playlists.GetPlaylist(ctx, playlistID, &spotify.PlaylistOptions{Fields: "snapshot_id"})
+	if err != nil {
+		return "", err
+	}
+	return playlist.SnapshotID, nil
+}
+
+// fetchSyncTarget reads every entry of the target, by position. Entries that
+// are unavailable are kept as fixed so later positions stay right.
+func fetchSyncTarget(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID string) ([]playlistsync.Item, map[string]playlistExportTrack, error) {
+	entries, err := spotifyClient.Playlists.PlaylistTracksPager(playlistID, &spotify.PlaylistTracksOptions{
+		AdditionalTypes: []string{"track", "episode"},
+	}).All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]playlistsync.Item, len(entries))
+	for i, entry := range entries {
+		if entry.Track == nil || entry.Track.URI() == "" {
+			items[i] = playlistsync.Item{Local: true}
+			continue
+		}
+		items[i] = playlistsync.Item{URI: entry.Track.URI(), Local: entry.IsLocal}
+	}
+
+	tracks := make(map[string]playlistExportTrack)
+	for _, track := range playlistExportTracks(entries) {
+		tracks[track.URI] = track
+	}
+	return items, tracks, nil
+}
+
+// applySyncPlan makes the edits of a plan and returns the new snapshot ID.
+// Removals go from the last position back, so every batch's positions are
+// still those of the playlist as it was read.
+func applySyncPlan(ctx context.Context, spotifyClient *client.SpotifyClient, playlistID, snapshotID string, plan playlistsync.Plan) (string, error) {
+	removals := append([]playlistsync.Removal(nil), plan.Remove...)
+	sort.Slice(removals, func(i, j int) bool { return removals[i].Position > removals[j].Position })
+
+	for start := 0; start < len(removals); start += playlistsync.BatchSize {
+		end := min(start+playlistsync.BatchSize, len(removals))
+		request := &spotify.RemoveTracksRequest{SnapshotID: &snapshotID}
+		for _, removal := range removals[start:end] {
+			request.Tracks = append(request.Tracks, spotify.TrackToRemove{URI: removal.URI, Positions: []int{removal.Position}})
+		}
+		response, err := spotifyClient.Playlists.RemoveTracksFromPlaylist(ctx, playlistID, request)
+		if err != nil {
+			return "", fmt.Errorf("failed to remove tracks from playlist: %w", err)
+		}
+		snapshotID = response.SnapshotID
+	}
+
+	for _, insertion := range plan.Insert {
+		position := insertion.Position
+		response, err := spotifyClient.Playlists.AddTracksToPlaylist(ctx, playlistID, &spotify.AddTracksRequest{URIs: insertion.URIs, Position: &position})
+		if err != nil {
+			return "", fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		snapshotID = response.SnapshotID
+	}
+	return snapshotID, nil
+}
+
+func printSyncPlan(plan playlistsync.Plan, sourceTracks []playlistExportTrack, targetTracks map[string]playlistExportTrack) {
+	if plan.Empty() {
+		fmt.Println("Dry run: already in sync")
+		return
+	}
+
+	sourceByURI := make(map[string]playlistExportTrack)
+	for _, track := range sourceTracks {
+		sourceByURI[track.URI] = track
+	}
+
+	var added, removed []playlistExportTrack
+	for _, insertion := range plan.Insert {
+		for _, uri := range insertion.URIs {
+			added = append(added, sourceByURI[uri])
+		}
+	}
+	for _, removal := range plan.Remove {
+		track, ok := targetTracks[removal.URI]
+		if !ok {
+			track = playlistExportTrack{URI: removal.URI}
+		}
+		removed = append(removed, track)
+	}
+
+	requests := plan.Requests()
+	fmt.Printf("Dry run: would make %d request%s\n", requests, pluralize(requests))
+	printDiffSection("Would add", added)
+	printDiffSection("Would remove", removed)
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/plex"
+	"github.com/spf13/cobra"
+)
+
+var plexCmd = &cobra.Command{
+	Use:   "plex",
+	Short: "Plex Media Server integration commands",
+	Long: `Commands for copying Spotify playlists to a music library on a Plex Media
+Server.
+
+The server is set with plex.url and plex.section in config.yaml and
+plex.token in credentials.yaml, or with PLEX_URL, PLEX_SECTION and
+PLEX_TOKEN. The section is the title or key of the music library, and may be
+left out when the server has only one. --plex-url and --section override
+them for one command.`,
+}
+
+var plexTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test the Plex connection and list its music libraries",
+	Args:  cobra.NoArgs,
+	RunE:  runPlexTest,
+}
+
+var plexSyncPlaylistCmd = &cobra.Command{
+	Use:   "sync-playlist <spotify-playlist-id>",
+	Short: "Copy a Spotify playlist to a Plex music library",
+	Long: `Find the tracks of a Spotify playlist in a Plex music library and save
+them as a Plex audio playlist of the same name, or --name. Syncing again
+replaces the tracks of that playlist, so it follows the Spotify one; smart
+playlists of that name are left alone.
+
+Tracks are matched as 'navidrome push-playlist' matches them: by title and
+one of the artists, preferring the same album and length. Tracks not in the
+library are listed and left out. --dry-run lists the matches without saving
+anything.`,
+	Example: `  spotify-cli plex sync-playlist 37i9dQZF1DXcBWIGoYBM5M
+  spotify-cli plex sync-playlist 37i9dQZF1DXcBWIGoYBM5M --section Music --name "Daily Mix" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPlexSyncPlaylist(cmd, args[0])
+	},
+}
+
+func init() {
+	plexSyncPlaylistCmd.Flags().String("name", "", "Name of the Plex playlist (default: the Spotify playlist's name)")
+	plexSyncPlaylistCmd.Flags().Bool("dry-run", false, "Match the tracks but do not create or change the playlist")
+	plexSyncPlaylistCmd.Flags().String("section", "", "Title or key of the music library (overrides config)")
+
+	for _, cmd := range []*cobra.Command{plexTestCmd, plexSyncPlaylistCmd} {
+		cmd.Flags().String("plex-url", "", "Plex server URL (overrides config)")
+	}
+
+	plexCmd.AddCommand(plexTestCmd)
+	plexCmd.AddCommand(plexSyncPlaylistCmd)
+	rootCmd.AddCommand(plexCmd)
+}
+
+// createPlexClient returns a client for the configured server and the
+// music library to use on it, which is empty if none is configured
+func createPlexClient(cmd *cobra.Command) (*plex.Client, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if url, _ := cmd.Flags().GetString("plex-url"); url != "" {
+		cfg.Plex.URL = url
+	}
+	if cmd.Flags().Lookup("section") != nil {
+		if section, _ := cmd.Flags().GetString("section"); section != "" {
+			cfg.Plex.Section = section
+		}
+	}
+
+	if cfg.Plex.URL == "" || cfg.Plex.Token == "" {
+		return nil, "", apierrors.NewConfigError(fmt.Sprintf("Plex is not configured: set plex.url in config.yaml and plex.token in %s, or PLEX_URL and PLEX_TOKEN", config.CredentialsPath()))
+	}
+
+	return plex.NewClient(plex.Config{
+		BaseURL: cfg.Plex.URL,
+		Token:   cfg.Plex.Token,
+	}), cfg.Plex.Section, nil
+}
+
+func runPlexTest(cmd *cobra.Command, args []string) error {
+	server, _, err := createPlexClient(cmd)
+	if err != nil {
+		return err
+	}
+	sections, err := server.MusicSections()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Plex: %w", err)
+	}
+
+	utils.PrintSuccess("Connected to Plex")
+	if len(sections) == 0 {
+		utils.PrintWarning("The server has no music library")
+		return nil
+	}
+	fmt.Println("Music libraries:")
+	for _, section := range sections {
+		fmt.Printf("  %s (key %s)\n", section.Title, section.Key)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bambithedeer/spotify-api/internal/plex"
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+	"github.com/spf13/cobra"
+)
+
+func runPlexSyncPlaylist(cmd *cobra.Command, playlistRef string) error {
+	playlistID, err := normalizePlaylistID(playlistRef)
+	if err != nil {
+		return err
+	}
+	server, sectionRef, err := createPlexClient(cmd)
+	if err != nil {
+		return err
+	}
+	section, err := server.FindSection(sectionRef)
+	if err != nil {
+		return fmt.Errorf("failed to choose a Plex music library: %w", err)
+	}
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	export, err := fetchPlaylistExport(GetCommandContext(), spotifyClient, playlistID)
+	if err != nil {
+		return err
+	}
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		name = export.Name
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	matches, err := matchPlaylistTracks(export, func(track trackmatch.Track) (string, string, error) {
+		found, err := server.FindTrack(section.Key, track)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to search Plex: %w", err)
+		}
+		if found == nil {
+			return "", "", nil
+		}
+		return found.RatingKey, fmt.Sprintf("%s - %s (%s)", found.Artist(), found.Title, found.ParentTitle), nil
+	})
+	if err != nil {
+		return err
+	}
+	result := newPushResult(name, matches, dryRun)
+
+	if !dryRun {
+		if result.Matched == 0 {
+			return fmt.Errorf("none of the %d track%s are in the Plex library %q", len(matches), pluralize(len(matches)), section.Title)
+		}
+		if err := saveSyncedPlexPlaylist(server, result); err != nil {
+			return err
+		}
+	}
+	return printPushResult("Plex", result)
+}
+
+// saveSyncedPlexPlaylist replaces the tracks of the audio playlist of that
+// name, or creates it if there is none
+func saveSyncedPlexPlaylist(server *plex.Client, result *pushResult) error {
+	playlists, err := server.Playlists()
+	if err != nil {
+		return fmt.Errorf("failed to list Plex playlists: %w", err)
+	}
+	for _, playlist := range playlists {
+		if playlist.Title != result.Playlist || playlist.Smart {
+			continue
+		}
+		result.PlaylistID = playlist.RatingKey
+		if err := server.ReplacePlaylist(playlist.RatingKey, result.songIDs()); err != nil {
+			return fmt.Errorf("failed to update Plex playlist: %w", err)
+		}
+		return nil
+	}
+
+	created, err := server.CreatePlaylist(result.Playlist, result.songIDs())
+	if err != nil {
+		return fmt.Errorf("failed to create Plex playlist: %w", err)
+	}
+	result.PlaylistID, result.Created = created.RatingKey, true
+	return nil
+}
@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	"github.com/bambithedeer/spotify-api/internal/discord"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/mosaic"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	presenceClientID    string
+	presenceDetails     string
+	presenceState       string
+	presenceLargeText   string
+	presenceIdle        string
+	presenceIdleTimeout time.Duration
+	presenceInterval    time.Duration
+)
+
+var presenceCmd = &cobra.Command{
+	Use:   "presence",
+	Short: "Show what you are playing on your Discord profile",
+	Long: `Run in the background and publish the track or episode playing to Discord
+Rich Presence: its name, artists, album, artwork and time left, with a link
+to it on Spotify. Playback is checked every --interval, on any device, and
+Discord is told only when what it shows changes.
+
+The activity is sent to the Discord desktop app over its local IPC socket,
+so the app must be running on this computer; presence waits for it to start
+and reconnects when it restarts. It is shown as the Discord application
+--client-id, whose name profiles show as what you are listening to. Create
+one at https://discord.com/developers/applications and set discord.client_id
+in config.yaml or DISCORD_CLIENT_ID.
+
+--details, --state and --large-text are Go templates for the two lines of
+the activity and the artwork's tooltip, over .Name, .Artists, .Album, .URL,
+.Episode and .Paused. With --idle paused a paused track stays shown, marked
+paused, until --idle-timeout; --idle clear hides it as soon as playback
+pauses. Nothing is shown while nothing is playing.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli presence --client-id 1234567890
+  spotify-cli presence --details "{{.Name}} by {{.Artists}}" --state "on {{.Album}}"
+  spotify-cli presence --idle clear --interval 10s
+  spotify-cli daemon install presence -- presence`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPresence(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(presenceCmd)
+
+	presenceCmd.Flags().StringVar(&presenceClientID, "client-id", "", "Discord application ID to show the activity as (overrides config)")
+	presenceCmd.Flags().StringVar(&presenceDetails, "details", "", "Template for the first line (default \""+discord.DefaultTemplates.Details+"\")")
+	presenceCmd.Flags().StringVar(&presenceState, "state", "", "Template for the second line (default \""+discord.DefaultTemplates.State+"\")")
+	presenceCmd.Flags().StringVar(&presenceLargeText, "large-text", "", "Template for the artwork's tooltip (default \""+discord.DefaultTemplates.LargeText+"\")")
+	presenceCmd.Flags().StringVar(&presenceIdle, "idle", "", "What to show while paused: paused or clear (default paused)")
+	presenceCmd.Flags().DurationVar(&presenceIdleTimeout, "idle-timeout", 15*time.Minute, "Clear the activity after being paused this long (0 keeps it)")
+	presenceCmd.Flags().DurationVar(&presenceInterval, "interval", 5*time.Second, "How often to check playback")
+}
+
+// presenceSession keeps the Discord connection and what it was last sent
+type presenceSession struct {
+	clientID string
+	presence *discord.Presence
+	idle     string
+
+	conn *discord.Client
+	// last is what Discord shows; sent is false until something was sent
+	// over conn
+	last   *discord.Activity
+	sent   bool
+	warned bool
+
+	// pausedID and pausedSince tell how long the item has been paused
+	pausedID    string
+	pausedSince time.Time
+}
+
+func runPresence(cmd *cobra.Command) error {
+	cfg, err := config.LoadSettings()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	settings := cfg.Discord
+	for _, flag := range []struct {
+		name string
+		dest *string
+		val  string
+	}{
+		{"client-id", &settings.ClientID, presenceClientID},
+		{"details", &settings.Details, presenceDetails},
+		{"state", &settings.State, presenceState},
+		{"large-text", &settings.LargeText, presenceLargeText},
+		{"idle", &settings.Idle, presenceIdle},
+	} {
+		if cmd.Flags().Changed(flag.name) {
+			*flag.dest = flag.val
+		}
+	}
+
+	if settings.ClientID == "" {
+		return apierrors.NewConfigError("a Discord application ID is required: set --client-id, discord.client_id in config.yaml or DISCORD_CLIENT_ID")
+	}
+	if settings.Idle == "" {
+		settings.Idle = "paused"
+	}
+	if settings.Idle != "paused" && settings.Idle != "clear" {
+		return fmt.Errorf("invalid --idle %q. Valid values: paused, clear", settings.Idle)
+	}
+	if presenceInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	if presenceIdleTimeout < 0 {
+		return fmt.Errorf("--idle-timeout cannot be negative")
+	}
+
+	presence, err := discord.NewPresence(discord.Templates{
+		Details:   settings.Details,
+		State:     settings.State,
+		LargeText: settings.LargeText,
+	})
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playback state")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	session := &presenceSession{clientID: settings.ClientID, presence: presence, idle: settings.Idle}
+	defer session.close()
+
+	utils.PrintSuccess("Showing playback on Discord, press Ctrl+C to stop")
+
+	ticker := time.NewTicker(presenceInterval)
+	defer ticker.Stop()
+	for {
+		session.update(ctx, spotifyClient)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// update checks playback and tells Discord if what it shows changed
+func (s *presenceSession) update(ctx context.Context, spotifyClient *client.SpotifyClient) {
+	playing, err := spotifyClient.Player.GetCurrentlyPlaying(ctx, &spotify.CurrentlyPlayingOptions{AdditionalTypes: []string{"track", "episode"}})
+	if err != nil {
+		if ctx.Err() == nil {
+			utils.PrintVerbose("Failed to check playback: %v", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	activity, err := s.activity(playing, now)
+	if err != nil {
+		utils.PrintWarning("%v", err)
+		return
+	}
+	if s.sent && discord.Same(s.last, activity) {
+		return
+	}
+	if !s.connect() {
+		return
+	}
+
+	if err := s.conn.SetActivity(activity); err != nil {
+		var apiErr *discord.Error
+		if errors.As(err, &apiErr) {
+			// Discord turned the activity down; sending it again will not help
+			utils.PrintWarning("Discord rejected the activity: %v", err)
+			s.last, s.sent = activity, true
+			return
+		}
+		utils.PrintVerbose("Lost the connection to Discord: %v", err)
+		s.disconnect()
+		return
+	}
+	s.last, s.sent = activity, true
+	if activity == nil {
+		utils.PrintVerbose("Cleared the activity")
+	} else {
+		utils.PrintVerbose("Showing %s", activity.Details)
+	}
+}
+
+// activity is what to show for the player's state, or nil for nothing
+func (s *presenceSession) activity(state *models.CurrentlyPlaying, now time.Time) (*discord.Activity, error) {
+	if state == nil || state.Item == nil {
+		s.pausedID = ""
+		return nil, nil
+	}
+	item, err := decodePlayingItem(state.Item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the playing item: %w", err)
+	}
+	playing, id := presencePlaying(item)
+	if id == "" {
+		return nil, nil
+	}
+	playing.Progress = time.Duration(state.ProgressMs) * time.Millisecond
+	playing.Paused = !state.IsPlaying
+
+	if !playing.Paused {
+		s.pausedID = ""
+	} else {
+		if s.pausedID != id {
+			s.pausedID, s.pausedSince = id, now
+		}
+		if s.idle == "clear" || (presenceIdleTimeout > 0 && now.Sub(s.pausedSince) >= presenceIdleTimeout) {
+			return nil, nil
+		}
+	}
+	return s.presence.Activity(playing, now)
+}
+
+// presencePlaying describes a track or episode to the templates, and
+// returns its URI to tell items apart
+func presencePlaying(item *models.PlaylistItem) (discord.Playing, string) {
+	var playing discord.Playing
+	var uri string
+	switch {
+	case item.Track != nil:
+		t := item.Track
+		playing = discord.Playing{
+			Name:     t.Name,
+			Artists:  utils.FormatSimpleArtists(t.Artists),
+			URL:      t.ExternalURLs.Spotify,
+			Duration: time.Duration(t.DurationMs) * time.Millisecond,
+		}
+		if t.Album != nil {
+			playing.Album = t.Album.Name
+		}
+		uri = t.URI
+	case item.Episode != nil:
+		e := item.Episode
+		playing = discord.Playing{
+			Name:     e.Name,
+			URL:      e.ExternalURLs.Spotify,
+			Episode:  true,
+			Duration: time.Duration(e.DurationMs) * time.Millisecond,
+		}
+		if e.Show != nil {
+			playing.Artists, playing.Album = e.Show.Publisher, e.Show.Name
+		}
+		uri = e.URI
+	default:
+		return playing, ""
+	}
+
+	// Discord shows the large image at 300px at most
+	if art, ok := mosaic.PickImage(artworkOf(item), 300); ok {
+		playing.ImageURL = art.URL
+	}
+	return playing, uri
+}
+
+// connect makes sure there is a connection to Discord, warning the first
+// time it cannot be made and retrying quietly after
+func (s *presenceSession) connect() bool {
+	if s.conn != nil {
+		return true
+	}
+	conn, err := discord.Connect(s.clientID)
+	if err != nil {
+		if !s.warned {
+			utils.PrintWarning("Could not connect to Discord, retrying every %s: %v", presenceInterval, err)
+			s.warned = true
+		} else {
+			utils.PrintVerbose("Could not connect to Discord: %v", err)
+		}
+		return false
+	}
+	if s.warned {
+		utils.PrintSuccess("Connected to Discord")
+	}
+	s.conn, s.warned, s.sent = conn, false, false
+	return true
+}
+
+func (s *presenceSession) disconnect() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.sent = nil, false
+	}
+}
+
+// close clears the activity before leaving, so the profile does not keep
+// showing a track after presence stops
+func (s *presenceSession) close() {
+	if s.conn == nil {
+		return
+	}
+	if s.last != nil {
+		if err := s.conn.SetActivity(nil); err != nil {
+			utils.PrintVerbose("Failed to clear the activity: %v", err)
+		}
+	}
+	s.disconnect()
+}
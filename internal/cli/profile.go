@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileCopyCredentials bool
+	profileBaseClear       bool
+	profileBaseRefresh     bool
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage account profiles",
+	Long: `Manage named account profiles.
+
+Each profile has its own credentials and tokens, so you can switch between
+several Spotify accounts (for example a personal and a work account).
+Select a profile for a single command with --profile, or make it the default
+with 'profile use'. Some commands can also work with two accounts at once:
+'playlist copy --as <name>' reads with the current profile and writes with
+another.`,
+	Example: `  # Create a profile for a second account and log in to it
+  spotify-cli profile create work
+  spotify-cli --profile work auth login
+
+  # Make it the default for every command
+  spotify-cli profile use work
+
+  # List profiles
+  spotify-cli profile list
+
+  # Publish a copy of a playlist to the work account
+  spotify-cli playlist copy 37i9dQZF1DXcBWIGoYBM5M --as work`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List account profiles",
+	Long:    `List all account profiles and show which one is active.`,
+	Example: `  spotify-cli profile list`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileList()
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Set the active profile",
+	Long:  `Set the profile used by commands that don't pass --profile.`,
+	Args:  cobra.ExactArgs(1),
+	Example: `  spotify-cli profile use work
+  spotify-cli profile use default`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileUse(args[0])
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a new profile",
+	Long: `Create a new account profile.
+
+By default the API credentials of the current profile are copied into the new
+profile. Tokens are never copied; log in to the new profile with
+'spotify-cli --profile <name> auth login'.`,
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli profile create work
+  spotify-cli profile create family --copy-credentials=false`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runProfileCreate(args[0])
+	},
+}
+
+var profileBaseCmd = &cobra.Command{
+	Use:   "base [url]",
+	Short: "Share settings from a base config",
+	Long: `Load a read-only base config that is merged under this profile's own
+settings, so a team or household can keep shared settings in one place
+while everyone's tokens stay in their own config file.
+
+The base is either an https:// URL of a YAML file in the config file
+format, or git+<repository> to read spotify-cli.yaml from a git repository
+(add #path/to/file.yaml for another file). Any setting left at its default
+in the local config is taken from the base; tokens never are. The base is
+fetched again once an hour, and the last copy is used when it cannot be
+reached.
+
+With no arguments, shows the base config and the settings taken from it.`,
+	Args: cobra.MaximumNArgs(1),
+	Example: `  spotify-cli profile base https://example.com/household/spotify-cli.yaml
+  spotify-cli profile base git+https://github.com/example/team-config.git#spotify/cli.yaml
+  spotify-cli profile base --refresh
+  spotify-cli profile base --clear`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := ""
+		if len(args) == 1 {
+			source = args[0]
+		}
+		return runProfileBase(source)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileBaseCmd)
+
+	profileCreateCmd.Flags().BoolVar(&profileCopyCredentials, "copy-credentials", true, "Copy API credentials from the current profile")
+
+	profileBaseCmd.Flags().BoolVar(&profileBaseClear, "clear", false, "Stop using a base config")
+	profileBaseCmd.Flags().BoolVar(&profileBaseRefresh, "refresh", false, "Fetch the base config again now")
+}
+
+func runProfileList() error {
+	profiles, err := config.ListProfiles(configDir)
+	if err != nil {
+		return err
+	}
+
+	active := config.ActiveProfile(configDir)
+
+	type profileInfo struct {
+		Name          string `json:"name" yaml:"name"`
+		Active        bool   `json:"active" yaml:"active"`
+		Current       bool   `json:"current" yaml:"current"`
+		Credentials   bool   `json:"credentials" yaml:"credentials"`
+		Authenticated bool   `json:"authenticated" yaml:"authenticated"`
+		Path          string `json:"path" yaml:"path"`
+	}
+
+	infos := make([]profileInfo, 0, len(profiles))
+	for _, name := range profiles {
+		info := profileInfo{
+			Name:    name,
+			Active:  name == active,
+			Current: name == profileName,
+			Path:    config.ProfilePath(configDir, name),
+		}
+
+		if profileConfig, err := config.LoadProfile(configDir, name); err == nil {
+			info.Credentials = profileConfig.ClientID != "" && profileConfig.ClientSecret != ""
+			info.Authenticated = profileConfig.RefreshToken != ""
+		}
+
+		infos = append(infos, info)
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(infos)
+	}
+
+	fmt.Printf("%-3s %-20s %-12s %-14s %s\n", "", "PROFILE", "CREDENTIALS", "AUTHENTICATED", "CONFIG")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, info := range infos {
+		marker := ""
+		if info.Current {
+			marker = "*"
+		}
+
+		name := info.Name
+		if info.Active {
+			name += " (active)"
+		}
+
+		fmt.Printf("%-3s %-20s %-12s %-14s %s\n",
+			marker,
+			truncateString(name, 20),
+			map[bool]string{true: "Yes", false: "No"}[info.Credentials],
+			map[bool]string{true: "Yes", false: "No"}[info.Authenticated],
+			info.Path)
+	}
+
+	return nil
+}
+
+func runProfileUse(name string) error {
+	if err := config.SetActiveProfile(configDir, name); err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Active profile set to: %s", name)
+	return nil
+}
+
+func runProfileCreate(name string) error {
+	var base *config.Config
+	if profileCopyCredentials {
+		base = config.Get()
+	}
+
+	if err := config.CreateProfile(configDir, name, base); err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Created profile: %s", name)
+	fmt.Printf("Config file: %s\n", config.ProfilePath(configDir, name))
+	if base == nil || !config.HasCredentials() {
+		fmt.Printf("Next: spotify-cli --profile %s auth setup\n", name)
+	} else {
+		fmt.Printf("Next: spotify-cli --profile %s auth login\n", name)
+	}
+
+	return nil
+}
+
+func runProfileBase(source string) error {
+	cfg := config.Get()
+	switch {
+	case profileBaseClear:
+		if source != "" {
+			return fmt.Errorf("--clear takes no URL")
+		}
+		if cfg.BaseConfig == "" {
+			fmt.Println("No base config is set.")
+			return nil
+		}
+		previous := cfg.BaseConfig
+		if err := config.SetBase(""); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Stopped using base config %s", previous)
+		return nil
+
+	case source != "":
+		if err := config.SetBase(source); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Base config set to %s", source)
+		fmt.Println("Settings left at their defaults now come from it.")
+		return nil
+
+	case profileBaseRefresh:
+		if cfg.BaseConfig == "" {
+			return fmt.Errorf("no base config is set. Set one with 'spotify-cli profile base <url>'")
+		}
+		if _, err := config.LoadBase(filepath.Dir(config.GetConfigFile()), cfg.BaseConfig, true); err != nil {
+			return err
+		}
+		utils.PrintSuccess("Fetched base config %s", cfg.BaseConfig)
+		return nil
+	}
+
+	settings := config.BaseSettings()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"base_config": cfg.BaseConfig,
+			"settings":    settings,
+		})
+	}
+
+	if cfg.BaseConfig == "" {
+		fmt.Println("No base config is set.")
+		return nil
+	}
+	fmt.Printf("Base config: %s\n", cfg.BaseConfig)
+	if len(settings) == 0 {
+		fmt.Println("No settings are taken from it; the local config sets them all.")
+		return nil
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fmt.Printf("\n%-20s %s\n", "SETTING", "VALUE")
+	fmt.Println(strings.Repeat("-", 50))
+	for _, key := range keys {
+		value := fmt.Sprint(settings[key])
+		if key == "client_secret" {
+			value = "(hidden)"
+		}
+		fmt.Printf("%-20s %s\n", key, value)
+	}
+	return nil
+}
+
+// newProfileUserClient creates a user client for another profile, given with
+// --as, so one command can read from the current account and write to that
+// one. Naming the current profile returns its usual client.
+func newProfileUserClient(name, what string) (*client.SpotifyClient, error) {
+	if name == "" || name == profileName {
+		return newUserClient(what)
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+
+	profileConfig, err := config.LoadProfile(configDir, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w. Create it with 'spotify-cli profile create %s'", err, name)
+	}
+	if profileConfig.RefreshToken == "" {
+		return nil, fmt.Errorf("profile %q is not logged in. Run 'spotify-cli --profile %s auth login' to access %s", name, name, what)
+	}
+
+	spotifyClient, err := client.NewProfileClient(configDir, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Spotify client for profile %q: %w", name, err)
+	}
+	if !spotifyClient.IsAuthenticated() {
+		return nil, fmt.Errorf("profile %q needs to log in again. Run 'spotify-cli --profile %s auth login'", name, name)
+	}
+	return spotifyClient, nil
+}
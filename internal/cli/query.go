@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/query"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryDryRun bool
+	queryFormat string
+)
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "Run a pipeline over your music in a small query language",
+	Long: `Run a query that reads tracks from a source, transforms them and
+optionally saves or plays the result, in one command.
+
+A query is a source followed by stages joined with dots:
+
+Sources:
+  playlist("name or id")   Tracks in a playlist, found by ID, URI or name
+  saved()                  Tracks saved in your library (also liked())
+  album("id")              Tracks on an album
+  top(short|medium|long)   Your top tracks for a time range
+  search("text")           Tracks matching a search
+  tagged("tag")            Tracks with a local tag, see 'tag --help'
+
+Stages:
+  tracks()                 Optional, reads naturally after playlist() and album()
+  filter(cond, ...)        Keep tracks matching every condition
+  sort(field[, desc])      Sort by a field
+  limit(n)                 Keep the first n tracks
+  dedupe()                 Drop repeated tracks
+  save_to("name")          Replace the contents of a playlist, creating it if needed
+  append_to("name")        Add to the end of a playlist, creating it if needed
+  play()                   Play the tracks on the active device
+  queue()                  Add the tracks to the playback queue
+
+Conditions compare a field with a value using > >= < <= == != or, for text,
+~ (contains) and !~. Conditions can be joined with "," or "and". Fields:
+  popularity, duration (seconds), duration_ms, track_number, explicit,
+  name, artist, album, id, uri, added_at,
+  acousticness, danceability, energy, instrumentalness, key, liveness,
+  loudness, mode, speechiness, tempo, time_signature, valence
+
+Sources are read a page at a time, so a limit stops paging early. Audio
+features are only fetched when a query uses them, 100 tracks per request.
+
+Queries can be saved as scripts and run as commands, see 'script --help'.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli query 'playlist("Workout").tracks().filter(energy>0.7).save_to("High Energy")'
+  spotify-cli query 'saved().filter(artist~"bowie", popularity>=60).sort(popularity, desc).limit(20)'
+  spotify-cli query 'top(short).filter(tempo>=120 and danceability>0.6).append_to("Run")' --dry-run
+  spotify-cli query 'search("genre:ambient").limit(200).dedupe()' --format json
+  spotify-cli query 'saved().filter(added_at>="2024-01-01").sort(energy, desc).limit(30).play()'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runQuery(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().BoolVar(&queryDryRun, "dry-run", false, "Run the query but do not write to any playlist or start playback")
+	queryCmd.Flags().StringVarP(&queryFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+func runQuery(expression string) error {
+	// Compile first so typos are reported without touching the network
+	q, err := query.Compile(expression)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+
+	backend := &queryBackend{spotifyClient: spotifyClient, validator: api.NewValidator()}
+	result, err := q.Run(GetCommandContext(), backend, query.Options{DryRun: queryDryRun})
+	if err != nil {
+		if result != nil && result.Playback != nil && result.Playback.Action == "queue" && result.Playback.Tracks > 0 {
+			utils.PrintWarning("Queued %d of %d tracks before the error", result.Playback.Tracks, len(result.Items))
+		}
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	return outputQueryResult(result)
+}
+
+func outputQueryResult(result *query.Result) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := queryFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(result)
+	}
+
+	fmt.Printf("Query - %d track%s\n\n", len(result.Items), pluralize(len(result.Items)))
+	printQueryItems(result.Items)
+
+	if save := result.Save; save != nil {
+		fmt.Println()
+		switch {
+		case save.DryRun:
+			fmt.Printf("Dry run: would write %d track%s to playlist %q\n", save.Tracks, pluralize(save.Tracks), save.Playlist)
+		case save.Created:
+			utils.PrintSuccess("Created playlist %q with %d track%s", save.Playlist, save.Tracks, pluralize(save.Tracks))
+			fmt.Printf("Playlist ID: %s\n", save.PlaylistID)
+		case save.Replaced:
+			utils.PrintSuccess("Replaced the tracks in %q with %d track%s", save.Playlist, save.Tracks, pluralize(save.Tracks))
+			printUndoHint(save.PlaylistID, save.BackupSnapshotID)
+		default:
+			utils.PrintSuccess("Added %d track%s to %q", save.Tracks, pluralize(save.Tracks), save.Playlist)
+		}
+	}
+
+	if playback := result.Playback; playback != nil {
+		fmt.Println()
+		switch {
+		case playback.Tracks == 0:
+			utils.PrintWarning("No tracks to %s", playback.Action)
+		case playback.DryRun && playback.Action == "play":
+			fmt.Printf("Dry run: would play %d track%s\n", playback.Tracks, pluralize(playback.Tracks))
+		case playback.DryRun:
+			fmt.Printf("Dry run: would queue %d track%s\n", playback.Tracks, pluralize(playback.Tracks))
+		case playback.Action == "play":
+			utils.PrintSuccess("Playing %d track%s", playback.Tracks, pluralize(playback.Tracks))
+		default:
+			utils.PrintSuccess("Queued %d track%s", playback.Tracks, pluralize(playback.Tracks))
+		}
+	}
+
+	return nil
+}
+
+// printQueryItems lists tracks as a numbered table
+func printQueryItems(items []query.Item) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("%-4s %-40s %-25s %s\n", "#", "TRACK", "ARTIST", "ALBUM")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for i, item := range items {
+		album := ""
+		if item.Track.Album != nil {
+			album = item.Track.Album.Name
+		}
+		fmt.Printf("%-4d %-40s %-25s %s\n",
+			i+1,
+			truncateString(item.Track.Name, 38),
+			truncateString(utils.FormatSimpleArtists(item.Track.Artists), 23),
+			truncateString(album, 30))
+	}
+}
+
+// queryBackend runs queries against the Spotify services
+type queryBackend struct {
+	spotifyClient *client.SpotifyClient
+	validator     *api.Validator
+	user          *models.User
+	// description is given to playlists WritePlaylist creates
+	description string
+}
+
+func (b *queryBackend) PlaylistTracks(ctx context.Context, ref string) (query.Stream, error) {
+	playlist, err := b.findPlaylist(ctx, ref, false)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("no playlist named %q in your library", ref)
+	}
+
+	pager := b.spotifyClient.Playlists.PlaylistTracksPager(playlist.ID, nil)
+	return query.FromPager(pager, playlistTrackItem), nil
+}
+
+func playlistTrackItem(item models.PlaylistTrack) (query.Item, bool) {
+	// Local files and episodes have nothing to filter on or save
+	if item.IsLocal || !item.Track.IsTrack() || item.Track.URI() == "" {
+		return query.Item{}, false
+	}
+	return query.Item{Track: *item.Track.Track, AddedAt: item.AddedAt}, true
+}
+
+func (b *queryBackend) SavedTracks(ctx context.Context) (query.Stream, error) {
+	pager := b.spotifyClient.Library.SavedTracksPager(nil)
+	return query.FromPager(pager, savedTrackItem), nil
+}
+
+func savedTrackItem(saved models.SavedTrack) (query.Item, bool) {
+	return query.Item{Track: saved.Track, AddedAt: saved.AddedAt}, true
+}
+
+func (b *queryBackend) AlbumTracks(ctx context.Context, ref string) (query.Stream, error) {
+	ids, err := b.validator.NormalizeAndValidateIDs([]string{ref})
+	if err != nil {
+		return nil, err
+	}
+
+	pager := b.spotifyClient.Albums.AlbumTracksPager(ids[0], "")
+	return query.FromPager(pager, trackItem), nil
+}
+
+func (b *queryBackend) TopTracks(ctx context.Context, timeRange string) (query.Stream, error) {
+	pager := b.spotifyClient.Users.TopTracksPager(&spotify.TopItemsOptions{TimeRange: timeRange})
+	return query.FromPager(pager, trackItem), nil
+}
+
+func (b *queryBackend) SearchTracks(ctx context.Context, text string) (query.Stream, error) {
+	pager := b.spotifyClient.Search.SearchTracksPager(text, nil)
+	return query.FromPager(pager, trackItem), nil
+}
+
+func (b *queryBackend) TaggedTracks(ctx context.Context, tag string) (query.Stream, error) {
+	tracks, err := loadTaggedTracks(ctx, b.spotifyClient, tag, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]query.Item, 0, len(tracks))
+	for _, track := range tracks {
+		if item, ok := trackItem(track); ok {
+			items = append(items, item)
+		}
+	}
+	return query.FromItems(items), nil
+}
+
+func (b *queryBackend) AudioFeatures(ctx context.Context, ids []string) ([]models.AudioFeatures, error) {
+	return b.spotifyClient.Tracks.GetTracksAudioFeatures(ctx, ids)
+}
+
+func (b *queryBackend) WritePlaylist(ctx context.Context, name string, uris []string, replace bool) (*query.SaveResult, error) {
+	result := &query.SaveResult{Playlist: name, Replaced: replace, Tracks: len(uris)}
+
+	playlist, err := b.findPlaylist(ctx, name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if playlist == nil {
+		user, err := b.currentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		description := b.description
+		if description == "" {
+			description = "Built with spotify-cli query"
+		}
+		public := false
+		playlist, err = b.spotifyClient.Playlists.CreatePlaylist(ctx, user.ID, &spotify.CreatePlaylistRequest{
+			Name:        name,
+			Description: description,
+			Public:      &public,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create playlist: %w", err)
+		}
+		result.Created = true
+		replace = false
+	}
+	result.PlaylistID = playlist.ID
+
+	// Replacing takes the first 100 tracks; the rest are appended like any
+	// other batch because the API accepts at most 100 tracks per request
+	start := 0
+	if replace {
+		result.BackupSnapshotID, err = backupPlaylist(ctx, b.spotifyClient, playlist.ID)
+		if err != nil {
+			return nil, err
+		}
+		start = min(100, len(uris))
+		response, err := b.spotifyClient.Playlists.ReplacePlaylistTracks(ctx, playlist.ID, uris[:start])
+		if err != nil {
+			return nil, fmt.Errorf("failed to replace playlist tracks: %w", err)
+		}
+		result.SnapshotID = response.SnapshotID
+	}
+
+	for ; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		response, err := b.spotifyClient.Playlists.AddTracksToPlaylist(ctx, playlist.ID, &spotify.AddTracksRequest{URIs: uris[start:end]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add tracks to playlist: %w", err)
+		}
+		result.SnapshotID = response.SnapshotID
+	}
+
+	return result, nil
+}
+
+func (b *queryBackend) Play(ctx context.Context, uris []string) error {
+	if err := b.spotifyClient.Player.Play(ctx, &spotify.PlayOptions{URIs: uris}); err != nil {
+		return fmt.Errorf("failed to start playback: %w", err)
+	}
+	return nil
+}
+
+// Enqueue adds tracks one at a time, since the queue endpoint takes a single
+// item per request
+func (b *queryBackend) Enqueue(ctx context.Context, uris []string) (int, error) {
+	for i, uri := range uris {
+		if err := b.spotifyClient.Player.AddToQueue(ctx, uri, ""); err != nil {
+			return i, fmt.Errorf("failed to add %s to queue: %w", uri, err)
+		}
+	}
+	return len(uris), nil
+}
+
+// errPlaylistFound stops paging through playlists once a match is found
+var errPlaylistFound = errors.New("playlist found")
+
+// findPlaylist finds a playlist by ID, URI or case-insensitive name. Names
+// are looked up among the user's playlists, and with owned set only among
+// the ones they can edit. It returns nil if no playlist has the name.
+func (b *queryBackend) findPlaylist(ctx context.Context, ref string, owned bool) (*models.Playlist, error) {
+	if ids, err := b.validator.NormalizeAndValidateIDs([]string{ref}); err == nil {
+		return b.spotifyClient.Playlists.GetPlaylist(ctx, ids[0], nil)
+	}
+
+	user, err := b.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *models.Playlist
+	err = b.spotifyClient.Playlists.UserPlaylistsPager(nil).Each(ctx, func(playlist models.Playlist) error {
+		if !strings.EqualFold(playlist.Name, ref) || (owned && playlist.Owner.ID != user.ID) {
+			return nil
+		}
+		found = &playlist
+		return errPlaylistFound
+	})
+	if err != nil && err != errPlaylistFound {
+		return nil, fmt.Errorf("failed to list playlists: %w", err)
+	}
+
+	return found, nil
+}
+
+func (b *queryBackend) currentUser(ctx context.Context) (*models.User, error) {
+	if b.user != nil {
+		return b.user, nil
+	}
+
+	user, err := b.spotifyClient.Users.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	b.user = user
+	return user, nil
+}
+
+func trackItem(track models.Track) (query.Item, bool) {
+	return query.Item{Track: track}, !track.IsLocal && track.URI != ""
+}
@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+	"github.com/bambithedeer/spotify-api/internal/playcounts"
+	"github.com/bambithedeer/spotify-api/internal/queue"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queueSources       string
+	queueLength        int
+	queueArtistSpacing int
+	queueNoAlbumRepeat bool
+	queuePoolSize      int
+	queueSeed          int64
+	queueSaveName      string
+	queueDeviceID      string
+	queueDryRun        bool
+	queueFormat        string
+	queueMaxSkipRate   float64
+)
+
+// queueCmd represents the queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Build playback queues from your music",
+	Long: `Build playback queues by sampling tracks from several sources at once.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  # Build a 50 track queue weighted towards your saved tracks
+  spotify-cli queue build --sources saved:tracks=3,playlist:37i9dQZF1DXcBWIGoYBM5M=1,followed:artists=1 --length 50
+
+  # Save the result as a playlist instead of queueing it
+  spotify-cli queue build --sources saved:tracks,top:tracks --save "Weekend Mix"`,
+}
+
+var queueBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a weighted queue from multiple sources",
+	Long: `Sample tracks from multiple sources according to their weights and add them to the
+playback queue, or save them as a new playlist.
+
+Sources are given as a comma separated list of type[=weight]:
+  saved:tracks        Tracks saved in your library
+  top:tracks          Your top tracks
+  followed:artists    Top tracks of artists you follow
+  playlist:<id>       Tracks from a playlist
+  album:<id>          Tracks from an album
+  tag:<name>          Tracks, albums and playlists with a local tag
+
+Tracks are never repeated, the same artist is kept at least --artist-spacing
+tracks apart, and with --no-album-repeat two tracks from the same album are never
+played back to back, whenever the sources allow it.
+
+With --max-skip-rate, tracks you have skipped more often than that share of
+their plays are left out (see 'history skips'). Tracks played fewer than 3
+times are always kept.`,
+	Example: `  spotify-cli queue build --sources saved:tracks=3,playlist:37i9dQZF1DXcBWIGoYBM5M=1,followed:artists=1 --length 50
+  spotify-cli queue build --sources top:tracks,album:4aawyAB9vmqN3uQ7FjRGTy --artist-spacing 5 --dry-run
+  spotify-cli queue build --sources saved:tracks --save "Shuffled Likes" --seed 42
+  spotify-cli queue build --sources saved:tracks,tag:chill --max-skip-rate 0.5`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runQueueBuild()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueBuildCmd)
+
+	queueBuildCmd.Flags().StringVarP(&queueSources, "sources", "s", "", "Comma separated weighted sources (required)")
+	queueBuildCmd.Flags().IntVarP(&queueLength, "length", "n", 50, "Number of tracks to queue")
+	queueBuildCmd.Flags().IntVar(&queueArtistSpacing, "artist-spacing", 3, "Minimum number of tracks between two tracks by the same artist")
+	queueBuildCmd.Flags().BoolVar(&queueNoAlbumRepeat, "no-album-repeat", false, "Never queue two tracks from the same album back to back")
+	queueBuildCmd.Flags().IntVar(&queuePoolSize, "pool-size", 200, "Maximum number of tracks to load from each source")
+	queueBuildCmd.Flags().Int64Var(&queueSeed, "seed", 0, "Random seed for reproducible queues (default is time based)")
+	queueBuildCmd.Flags().StringVar(&queueSaveName, "save", "", "Save the result as a new playlist with this name instead of queueing")
+	queueBuildCmd.Flags().StringVarP(&queueDeviceID, "device", "d", "", "Target device ID")
+	queueBuildCmd.Flags().BoolVar(&queueDryRun, "dry-run", false, "Print the queue without queueing or saving it")
+	queueBuildCmd.Flags().StringVarP(&queueFormat, "format", "f", "table", "Output format (table, json, yaml)")
+	queueBuildCmd.Flags().Float64Var(&queueMaxSkipRate, "max-skip-rate", 0, "Leave out tracks skipped more than this share of the time (0-1, 0 keeps all)")
+	queueBuildCmd.MarkFlagRequired("sources")
+}
+
+func runQueueBuild() error {
+	sources, err := queue.ParseSources(queueSources)
+	if err != nil {
+		return fmt.Errorf("invalid sources: %w", err)
+	}
+
+	if queueLength < 1 {
+		return fmt.Errorf("length must be at least 1")
+	}
+
+	if queueArtistSpacing < 0 {
+		return fmt.Errorf("artist spacing cannot be negative")
+	}
+
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' for user account access")
+	}
+
+	cfg := config.Get()
+	if cfg.RefreshToken == "" {
+		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access your library")
+	}
+
+	var skipRates map[string]playcounts.Count
+	if queueMaxSkipRate > 0 {
+		skipRates = loadPlayCounts()
+	}
+
+	pools := make([]queue.Pool, 0, len(sources))
+	for _, source := range sources {
+		utils.PrintVerbose("Loading tracks from %s", source)
+
+		candidates, err := loadQueueSource(spotifyClient, source, queuePoolSize)
+		if err != nil {
+			return fmt.Errorf("failed to load source %s: %w", source, err)
+		}
+
+		if skipRates != nil {
+			candidates = withoutSkipped(candidates, skipRates, queueMaxSkipRate)
+		}
+
+		if len(candidates) == 0 {
+			utils.PrintWarning("Source %s has no tracks", source)
+			continue
+		}
+
+		pools = append(pools, queue.Pool{Source: source, Candidates: candidates})
+	}
+
+	seed := queueSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	result := queue.Build(pools, queue.Options{
+		Length: queueLength,
+		Constraints: ordering.Constraints{
+			ArtistSpacing:   queueArtistSpacing,
+			NoAdjacentAlbum: queueNoAlbumRepeat,
+		},
+		Seed: seed,
+	})
+
+	if len(result) == 0 {
+		return fmt.Errorf("no tracks found in the given sources")
+	}
+
+	if len(result) < queueLength {
+		utils.PrintWarning("Only %d unique tracks available, requested %d", len(result), queueLength)
+	}
+
+	if queueDryRun {
+		return outputQueueResult(result, seed)
+	}
+
+	if queueSaveName != "" {
+		return saveQueueAsPlaylist(spotifyClient, queueSaveName, result)
+	}
+
+	return addCandidatesToQueue(spotifyClient, result, queueDeviceID)
+}
+
+// addCandidatesToQueue queues tracks in order. A failure after the first
+// track stops queueing with a warning, since what was queued stays queued.
+func addCandidatesToQueue(spotifyClient *client.SpotifyClient, tracks []queue.Candidate, deviceID string) error {
+	added := 0
+	for _, candidate := range tracks {
+		if err := spotifyClient.Player.AddToQueue(GetCommandContext(), candidate.URI, deviceID); err != nil {
+			if added == 0 {
+				return fmt.Errorf("failed to add to queue: %w", err)
+			}
+			utils.PrintWarning("Stopped after queueing %d of %d tracks: %v", added, len(tracks), err)
+			return nil
+		}
+		added++
+	}
+
+	utils.PrintSuccess("Added %d track(s) to queue", added)
+	return nil
+}
+
+// errQueueSourceFull stops paging once a source has produced enough candidates
+var errQueueSourceFull = errors.New("queue source full")
+
+// loadQueueSource loads up to limit candidate tracks for a single source
+func loadQueueSource(spotifyClient *client.SpotifyClient, source queue.Source, limit int) ([]queue.Candidate, error) {
+	ctx := GetCommandContext()
+	var candidates []queue.Candidate
+
+	switch source.Kind {
+	case queue.SourceSavedTracks:
+		saved, err := spotifyClient.Library.SavedTracksPager(nil).WithMaxItems(limit).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range saved {
+			candidates = append(candidates, candidateFromTrack(&saved[i].Track, source))
+		}
+
+	case queue.SourceTopTracks:
+		top, err := spotifyClient.Users.TopTracksPager(nil).WithMaxItems(limit).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range top {
+			candidates = append(candidates, candidateFromTrack(&top[i], source))
+		}
+
+	case queue.SourceFollowedArtists:
+		err := spotifyClient.Users.FollowedArtistsPager(nil).Each(ctx, func(artist models.Artist) error {
+			if len(candidates) >= limit {
+				return errQueueSourceFull
+			}
+			topTracks, err := spotifyClient.Artists.GetArtistTopTracks(ctx, artist.ID, "US")
+			if err != nil {
+				utils.PrintVerbose("Skipping artist %s: %v", artist.Name, err)
+				return nil
+			}
+			for i := range topTracks {
+				candidates = append(candidates, candidateFromTrack(&topTracks[i], source))
+			}
+			return nil
+		})
+		if err != nil && err != errQueueSourceFull {
+			return nil, err
+		}
+
+	case queue.SourcePlaylist:
+		err := spotifyClient.Playlists.PlaylistTracksPager(source.ID, nil).Each(ctx, func(item models.PlaylistTrack) error {
+			if candidate, ok := candidateFromPlaylistTrack(item, source); ok {
+				candidates = append(candidates, candidate)
+			}
+			if len(candidates) >= limit {
+				return errQueueSourceFull
+			}
+			return nil
+		})
+		if err != nil && err != errQueueSourceFull {
+			return nil, err
+		}
+
+	case queue.SourceAlbum:
+		tracks, err := spotifyClient.Albums.AlbumTracksPager(source.ID, "").WithMaxItems(limit).All(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tracks {
+			candidate := candidateFromTrack(&tracks[i], source)
+			candidate.AlbumID = source.ID
+			candidates = append(candidates, candidate)
+		}
+
+	case queue.SourceTag:
+		tracks, err := loadTaggedTracks(ctx, spotifyClient, source.ID, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tracks {
+			if tracks[i].IsLocal || tracks[i].URI == "" {
+				continue
+			}
+			candidates = append(candidates, candidateFromTrack(&tracks[i], source))
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported source type: %s", source.Kind)
+	}
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	return candidates, nil
+}
+
+// candidateFromTrack converts a track into a queue candidate
+func candidateFromTrack(track *models.Track, source queue.Source) queue.Candidate {
+	candidate := queue.Candidate{
+		URI:    track.URI,
+		ID:     track.ID,
+		Name:   track.Name,
+		Source: source.String(),
+	}
+
+	if len(track.Artists) > 0 {
+		candidate.ArtistID = track.Artists[0].ID
+		candidate.ArtistName = track.Artists[0].Name
+	}
+
+	if track.Album != nil {
+		candidate.AlbumID = track.Album.ID
+	}
+
+	return candidate
+}
+
+// candidateFromPlaylistTrack converts a playlist item into a queue candidate,
+// skipping local files, episodes and unavailable tracks
+func candidateFromPlaylistTrack(item models.PlaylistTrack, source queue.Source) (queue.Candidate, bool) {
+	if item.IsLocal || !item.Track.IsTrack() || item.Track.URI() == "" {
+		return queue.Candidate{}, false
+	}
+
+	return candidateFromTrack(item.Track.Track, source), true
+}
+
+func saveQueueAsPlaylist(spotifyClient *client.SpotifyClient, name string, tracks []queue.Candidate) error {
+	uris := make([]string, len(tracks))
+	for i, track := range tracks {
+		uris[i] = track.URI
+	}
+
+	playlist, err := createPlaylistWithTracks(spotifyClient, name, "Built with spotify-cli queue build", uris)
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Saved %d track(s) to new playlist: %s", len(uris), playlist.Name)
+	fmt.Printf("Playlist ID: %s\n", playlist.ID)
+	return nil
+}
+
+func outputQueueResult(tracks []queue.Candidate, seed int64) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := queueFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"results": tracks,
+			"seed":    seed,
+		})
+	}
+
+	fmt.Printf("Queue - %d tracks (seed %d)\n\n", len(tracks), seed)
+	fmt.Printf("%-4s %-40s %-25s %s\n", "#", "TRACK", "ARTIST", "SOURCE")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for i, track := range tracks {
+		fmt.Printf("%-4d %-40s %-25s %s\n",
+			i+1,
+			truncateString(track.Name, 38),
+			truncateString(track.ArtistName, 23),
+			track.Source)
+	}
+
+	return nil
+}
+
+// withoutSkipped drops candidates skipped more than maxRate of the time,
+// judging only tracks played often enough for the rate to mean something
+func withoutSkipped(candidates []queue.Candidate, counts map[string]playcounts.Count, maxRate float64) []queue.Candidate {
+	kept := candidates[:0]
+	for _, candidate := range candidates {
+		count := counts[candidate.URI]
+		if count.Plays >= playcounts.MinRatePlays && count.SkipRate() > maxRate {
+			utils.PrintVerbose("Leaving out %s, skipped %d of %d plays", candidate.Name, count.Skips, count.Plays)
+			continue
+		}
+		kept = append(kept, candidate)
+	}
+	return kept
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+var quietCmd = &cobra.Command{
+	Use:   "quiet",
+	Short: "Keep automation quiet at night",
+	Long: `Quiet hours protect shared speakers from automation going off at the wrong
+time. They are set under quiet_hours in config.yaml:
+
+  quiet_hours:
+    windows:
+      - start: "22:00"
+        end: "07:00"
+      - days: [sat, sun]
+        start: "07:00"
+        end: "09:00"
+    max_volume: 20
+
+Windows take days and times as schedule slots do. During quiet hours:
+
+  - 'schedule' starts no slots except those marked alarm: true, and the
+    jukebox does not restart playback that has stopped
+  - 'schedule run', the jukebox and 'quiet enforce' lower the volume of
+    whatever plays to max_volume, if it is set
+  - the digest holds its email or webhook until quiet hours end, and
+    'history record --notify' holds track.saved and playlist.changed
+  - 'desktop' shows no track notifications, and 'player watch --notify'
+    posts no track.started events
+
+Anything you start yourself plays as usual, apart from the volume cap.`,
+}
+
+var quietStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the quiet hours and whether they are on",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hours, err := loadQuietHours()
+		if err != nil {
+			return err
+		}
+		if hours == nil {
+			fmt.Println("No quiet hours are configured")
+			return nil
+		}
+
+		for _, w := range hours.Windows {
+			fmt.Printf("  %s\n", w)
+		}
+		if hours.MaxVolume >= 0 {
+			fmt.Printf("Volume cap: %d%%\n", hours.MaxVolume)
+		}
+		now := time.Now()
+		if hours.Active(now) {
+			fmt.Printf("Quiet now, until %s\n", hours.Until(now).Format("Mon 15:04"))
+		} else {
+			fmt.Println("Not quiet now")
+		}
+		return nil
+	},
+}
+
+var quietEnforceCmd = &cobra.Command{
+	Use:   "enforce",
+	Short: "Keep the volume under the quiet hours cap",
+	Long: `Check playback every --interval and, during quiet hours, lower the volume of
+whatever is playing to the max_volume of quiet_hours.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli quiet enforce
+  spotify-cli daemon install quiet -- quiet enforce`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hours, err := loadQuietHours()
+		if err != nil {
+			return err
+		}
+		if hours == nil || hours.MaxVolume < 0 {
+			return apierrors.NewConfigError("no volume cap to enforce: set quiet_hours windows and max_volume in config.yaml")
+		}
+		spotifyClient, err := newUserClient("playback control")
+		if err != nil {
+			return err
+		}
+
+		return runDaemonLoop(cmd, "quiet", func(ctx context.Context, report *daemon.Report) error {
+			lowered, err := enforceQuietVolume(ctx, spotifyClient, hours, time.Now())
+			if lowered {
+				report.Add("volume lowered", 1)
+			}
+			return err
+		})
+	},
+}
+
+func init() {
+	addDaemonFlags(quietEnforceCmd, time.Minute)
+
+	quietCmd.AddCommand(quietStatusCmd, quietEnforceCmd)
+	rootCmd.AddCommand(quietCmd)
+}
+
+// loadQuietHours reads the quiet hours from the config file. It returns nil
+// when none are set.
+func loadQuietHours() (*quiet.Hours, error) {
+	cfg, err := config.LoadSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return quietHoursOf(cfg.Quiet)
+}
+
+func quietHoursOf(c config.QuietConfig) (*quiet.Hours, error) {
+	if len(c.Windows) == 0 {
+		return nil, nil
+	}
+	hours := &quiet.Hours{MaxVolume: -1}
+	for _, w := range c.Windows {
+		window, err := schedule.NewWindow(w.Days, w.Start, w.End)
+		if err != nil {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("quiet_hours: %v", err))
+		}
+		hours.Windows = append(hours.Windows, window)
+	}
+	if c.MaxVolume != nil {
+		if *c.MaxVolume < 0 || *c.MaxVolume > 100 {
+			return nil, apierrors.NewConfigError("quiet_hours: max_volume must be between 0 and 100")
+		}
+		hours.MaxVolume = *c.MaxVolume
+	}
+	return hours, nil
+}
+
+// enforceQuietVolume lowers the volume of active playback to the quiet
+// hours cap, and reports whether it had to
+func enforceQuietVolume(ctx context.Context, spotifyClient *client.SpotifyClient, hours *quiet.Hours, now time.Time) (bool, error) {
+	if !hours.Active(now) || hours.MaxVolume < 0 {
+		return false, nil
+	}
+	state, err := spotifyClient.Player.GetPlaybackState(ctx, "")
+	if err != nil {
+		return false, fmt.Errorf("failed to check playback: %w", err)
+	}
+	if state == nil || !state.IsPlaying || !state.Device.SupportsVolume {
+		return false, nil
+	}
+
+	volume, lower := hours.Cap(state.Device.VolumePercent, now)
+	if !lower {
+		return false, nil
+	}
+	if err := spotifyClient.Player.SetVolume(ctx, volume, state.Device.ID); err != nil {
+		return false, fmt.Errorf("failed to lower the volume: %w", err)
+	}
+	utils.PrintSuccess("Quiet hours: lowered %s from %d%% to %d%%", state.Device.Name, state.Device.VolumePercent, volume)
+	return true, nil
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+var quotaTop int
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show recent API usage and rate limiting",
+	Long: `Show how many requests recent commands sent to the Spotify API, how often
+Spotify answered with 429 Too Many Requests, whether a rate limit cooldown is
+still running and how many requests the response cache saved.
+
+Usage is recorded per profile for the last 7 days. If batch commands such as
+Lidarr imports are being rate limited, lower their --concurrency.`,
+	Example: `  spotify-cli quota
+  spotify-cli quota --top 10
+  spotify-cli quota --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runQuota()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+
+	quotaCmd.Flags().IntVar(&quotaTop, "top", 5, "Number of busiest commands to show")
+}
+
+// recordQuotaUsage saves the API usage of the command that just ran
+func recordQuotaUsage() {
+	if commandLine == "" {
+		return
+	}
+
+	stats, cacheStats := client.Usage()
+	sample := quota.Sample{
+		Command:          commandLine,
+		Requests:         stats.Requests,
+		RateLimited:      stats.RateLimited,
+		Retries:          stats.Retries,
+		Backoff:          stats.TotalBackoff,
+		CacheHits:        cacheStats.Hits,
+		CacheRevalidated: cacheStats.Revalidated,
+		CacheMisses:      cacheStats.Misses,
+		LastRateLimit:    stats.LastRateLimit,
+		RetryAfter:       stats.RetryAfter,
+	}
+	if sample.Empty() {
+		return
+	}
+
+	dir, err := openState()
+	if err == nil {
+		err = quota.Record(dir, sample)
+	}
+	if err != nil {
+		utils.PrintVerbose("Failed to record API usage: %v", err)
+	}
+}
+
+// quotaReport is the structured output of the quota command
+type quotaReport struct {
+	Windows       map[string]quota.Summary `json:"windows" yaml:"windows"`
+	BackoffUntil  *time.Time               `json:"backoff_until,omitempty" yaml:"backoff_until,omitempty"`
+	LastRateLimit *quota.Sample            `json:"last_rate_limit,omitempty" yaml:"last_rate_limit,omitempty"`
+	TopCommands   []quota.CommandUsage     `json:"top_commands" yaml:"top_commands"`
+}
+
+func runQuota() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	samples, err := quota.Load(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	windows := []struct {
+		key   string
+		label string
+		span  time.Duration
+	}{
+		{"1h", "Last hour", time.Hour},
+		{"24h", "Last 24 hours", 24 * time.Hour},
+		{"7d", "Last 7 days", quota.Retention},
+	}
+
+	report := quotaReport{
+		Windows:     make(map[string]quota.Summary),
+		TopCommands: quota.TopCommands(samples, now.Add(-24*time.Hour), quotaTop),
+	}
+	for _, w := range windows {
+		report.Windows[w.key] = quota.Summarize(samples, now.Add(-w.span))
+	}
+	if until := quota.BackoffUntil(samples, now); !until.IsZero() {
+		report.BackoffUntil = &until
+	}
+	if last, ok := quota.LastRateLimit(samples); ok {
+		report.LastRateLimit = &last
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(report)
+	}
+
+	if len(samples) == 0 {
+		fmt.Println("No API usage recorded yet.")
+		return nil
+	}
+
+	fmt.Printf("%-15s %-9s %-9s %-6s %-8s %-9s %s\n", "WINDOW", "COMMANDS", "REQUESTS", "429s", "RETRIES", "BACKOFF", "CACHE HITS")
+	fmt.Println(strings.Repeat("-", 75))
+	for _, w := range windows {
+		summary := report.Windows[w.key]
+		fmt.Printf("%-15s %-9d %-9d %-6d %-8d %-9s %s\n",
+			w.label,
+			summary.Commands,
+			summary.Requests,
+			summary.RateLimited,
+			summary.Retries,
+			summary.Backoff.Round(time.Second),
+			formatHitRate(summary))
+	}
+
+	fmt.Println()
+	if report.BackoffUntil != nil {
+		fmt.Printf("Backoff: rate limited, cooling down for another %s (until %s)\n",
+			report.BackoffUntil.Sub(now).Round(time.Second), report.BackoffUntil.Local().Format("15:04:05"))
+	} else {
+		fmt.Println("Backoff: none")
+	}
+	if report.LastRateLimit != nil {
+		fmt.Printf("Last 429: %s during '%s'\n", report.LastRateLimit.LastRateLimit.Local().Format("2006-01-02 15:04"), report.LastRateLimit.Command)
+	}
+
+	if len(report.TopCommands) > 0 {
+		fmt.Println()
+		fmt.Println("Busiest commands (last 24 hours):")
+		for _, usage := range report.TopCommands {
+			fmt.Printf("  %-50s %d request%s in %d run%s", truncateString(usage.Command, 50),
+				usage.Requests, pluralize(usage.Requests), usage.Runs, pluralize(usage.Runs))
+			if usage.RateLimited > 0 {
+				fmt.Printf(", %d rate limited", usage.RateLimited)
+			}
+			fmt.Println()
+		}
+	}
+
+	if report.Windows["1h"].RateLimited > 0 {
+		fmt.Println()
+		fmt.Println("Spotify rate limited requests in the last hour; lower --concurrency on batch commands to reduce retries.")
+	}
+
+	return nil
+}
+
+// formatHitRate shows the cache hit rate with the counts behind it
+func formatHitRate(summary quota.Summary) string {
+	rate := summary.CacheHitRate()
+	if rate < 0 {
+		return "-"
+	}
+	served := summary.CacheHits + summary.CacheRevalidated
+	return fmt.Sprintf("%.0f%% (%d/%d)", rate*100, served, served+summary.CacheMisses)
+}
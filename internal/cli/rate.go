@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/ratings"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rateMin      int
+	ratePlaylist string
+	rateDryRun   bool
+)
+
+// rateCmd represents the rate command
+var rateCmd = &cobra.Command{
+	Use:   "rate <uri> <stars>",
+	Short: "Rate tracks and albums from one to five stars",
+	Long: `Give a track or album a rating from 1 to 5 stars. Ratings are kept in the
+local state directory; Spotify has no ratings of its own.
+
+'rate sync' turns ratings into a playlist, such as one holding every track
+rated 4 stars or more, and keeps it up to date when left running or run
+from cron with --once.`,
+	Example: `  spotify-cli rate spotify:track:4iV5W9uYEdYUVa79Axb7Rh 4
+  spotify-cli rate list --min 4
+  spotify-cli rate clear spotify:track:4iV5W9uYEdYUVa79Axb7Rh
+  spotify-cli rate sync --min 4
+  spotify-cli rate sync --min 5 --playlist "Five Stars" --interval 30m`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRate(args[0], args[1])
+	},
+}
+
+var rateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rated tracks and albums, highest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRateList()
+	},
+}
+
+var rateClearCmd = &cobra.Command{
+	Use:   "clear <uri...>",
+	Short: "Remove ratings",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRateClear(args)
+	},
+}
+
+var rateSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Keep a playlist of your highest rated tracks",
+	Long: `Replace the contents of a playlist with every track rated --min stars or
+more, highest rated first. The playlist is created on the first run; by
+default it is named after the threshold, like "4+ Stars".
+
+Left running, the playlist is rewritten every --interval when ratings have
+changed. Album ratings are not included.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRateSync(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rateCmd)
+	rateCmd.AddCommand(rateListCmd)
+	rateCmd.AddCommand(rateClearCmd)
+	rateCmd.AddCommand(rateSyncCmd)
+
+	rateListCmd.Flags().IntVar(&rateMin, "min", ratings.MinStars, "Only list ratings of at least this many stars")
+
+	rateSyncCmd.Flags().IntVar(&rateMin, "min", 4, "Include tracks rated at least this many stars")
+	rateSyncCmd.Flags().StringVar(&ratePlaylist, "playlist", "", "Name of the playlist to maintain (default \"<min>+ Stars\")")
+	rateSyncCmd.Flags().BoolVar(&rateDryRun, "dry-run", false, "Show what would be written without touching the playlist")
+	addDaemonFlags(rateSyncCmd, time.Hour)
+}
+
+func runRate(uri, starsArg string) error {
+	stars, err := strconv.Atoi(starsArg)
+	if err != nil {
+		return fmt.Errorf("invalid rating %q: must be a number of stars from %d to %d", starsArg, ratings.MinStars, ratings.MaxStars)
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	rating, err := ratings.Set(dir, uri, stars, time.Now())
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Rated %s %s", rating.URI, ratings.Stars(rating.Stars))
+	return nil
+}
+
+func runRateList() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	list, _, err := ratings.AtLeast(dir, rateMin)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(list)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No ratings found.")
+		return nil
+	}
+
+	fmt.Printf("%-7s %-38s %s\n", "STARS", "URI", "RATED")
+	fmt.Println(strings.Repeat("-", 65))
+	for _, rating := range list {
+		fmt.Printf("%-7s %-38s %s\n", ratings.Stars(rating.Stars), rating.URI, rating.RatedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	counts := ratings.Distribution(list)
+	fmt.Println()
+	for stars := ratings.MaxStars; stars >= ratings.MinStars; stars-- {
+		if counts[stars] > 0 {
+			fmt.Printf("%s  %d\n", ratings.Stars(stars), counts[stars])
+		}
+	}
+	return nil
+}
+
+func runRateClear(uris []string) error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	cleared := 0
+	for _, uri := range uris {
+		found, err := ratings.Clear(dir, uri)
+		if err != nil {
+			return err
+		}
+		if !found {
+			utils.PrintWarning("%s was not rated", uri)
+			continue
+		}
+		cleared++
+	}
+
+	if cleared > 0 {
+		utils.PrintSuccess("Cleared %d rating%s", cleared, pluralize(cleared))
+	}
+	return nil
+}
+
+func runRateSync(cmd *cobra.Command) error {
+	if rateMin < ratings.MinStars || rateMin > ratings.MaxStars {
+		return fmt.Errorf("--min must be between %d and %d", ratings.MinStars, ratings.MaxStars)
+	}
+	name := ratePlaylist
+	if name == "" {
+		name = fmt.Sprintf("%d+ Stars", rateMin)
+		if rateMin == ratings.MaxStars {
+			name = fmt.Sprintf("%d Stars", rateMin)
+		}
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+	backend := &queryBackend{
+		spotifyClient: spotifyClient,
+		validator:     api.NewValidator(),
+		description:   fmt.Sprintf("Tracks rated %s or more, kept up to date by spotify-cli rate sync", ratings.Stars(rateMin)),
+	}
+
+	lastVersion := -1
+	return runDaemonLoop(cmd, "rate-sync", func(ctx context.Context, report *daemon.Report) error {
+		rated, version, err := ratings.AtLeast(dir, rateMin)
+		if err != nil {
+			return err
+		}
+		if version == lastVersion {
+			utils.PrintVerbose("Ratings unchanged, leaving %q alone", name)
+			return nil
+		}
+
+		var uris []string
+		for _, rating := range rated {
+			if rating.IsTrack() {
+				uris = append(uris, rating.URI)
+			}
+		}
+		report.Add("tracks", len(uris))
+
+		if rateDryRun {
+			fmt.Printf("Dry run: would write %d track%s to playlist %q\n", len(uris), pluralize(len(uris)), name)
+			lastVersion = version
+			return nil
+		}
+
+		result, err := backend.WritePlaylist(ctx, name, uris, true)
+		if err != nil {
+			return err
+		}
+		lastVersion = version
+
+		if result.Created {
+			utils.PrintSuccess("Created playlist %q with %d track%s", name, len(uris), pluralize(len(uris)))
+		} else {
+			utils.PrintSuccess("Updated playlist %q with %d track%s", name, len(uris), pluralize(len(uris)))
+		}
+		return nil
+	})
+}
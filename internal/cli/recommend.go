@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recommendSeedArtists    []string
+	recommendSeedTracks     []string
+	recommendSeedGenres     []string
+	recommendLimit          int
+	recommendMarket         string
+	recommendFormat         string
+	recommendPlay           bool
+	recommendCreatePlaylist string
+	recommendOrder          orderingFlags
+
+	// recommendTunables holds the --min-*, --max-* and --target-* flags,
+	// keyed by API parameter name
+	recommendTunables = make(map[string]*float64)
+)
+
+// recommendAttributes are the tunable track attributes, as flag names
+var recommendAttributes = []string{
+	"acousticness", "danceability", "duration-ms", "energy", "instrumentalness",
+	"key", "liveness", "loudness", "mode", "popularity", "speechiness", "tempo",
+	"time-signature", "valence",
+}
+
+// recommendCmd represents the recommend command
+var recommendCmd = &cobra.Command{
+	Use:   "recommend",
+	Short: "Get track recommendations from seed artists, tracks and genres",
+	Long: `Get track recommendations based on up to five seeds in total, from any mix
+of artists, tracks and genres.
+
+Tune the results with --min-<attribute>, --max-<attribute> and
+--target-<attribute> for any of these attributes:
+  ` + strings.Join(recommendAttributes, ", ") + `
+
+Attributes such as energy and danceability range from 0 to 1, tempo is in BPM
+and loudness in dB. Run 'genres' for the valid --seed-genre values.
+
+Recommendations are shuffled so the same artist is at least --artist-spacing
+tracks apart and, with --no-album-repeat, no two tracks from the same album
+play back to back, whenever the results allow it.
+
+Use --play to start playing the recommendations, or --create-playlist to save
+them to a new private playlist.`,
+	Example: `  spotify-cli recommend --seed-artist 4Z8W4fKeB5YxbusRsdQVPb --seed-genre rock
+  spotify-cli recommend --seed-track 7ouMYWpwJ422jRcDASZB7P --target-energy 0.8 --min-tempo 120
+  spotify-cli recommend --seed-genre ambient --max-energy 0.3 --limit 50 --create-playlist "Wind Down"
+  spotify-cli recommend --seed-artist spotify:artist:0OdUWJ0sBjDrqHygGUXeCF --play`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRecommend(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recommendCmd)
+
+	recommendCmd.Flags().StringSliceVar(&recommendSeedArtists, "seed-artist", nil, "Seed artist IDs or URIs (comma-separated or repeated)")
+	recommendCmd.Flags().StringSliceVar(&recommendSeedTracks, "seed-track", nil, "Seed track IDs or URIs (comma-separated or repeated)")
+	recommendCmd.Flags().StringSliceVar(&recommendSeedGenres, "seed-genre", nil, "Seed genres (comma-separated or repeated)")
+	recommendCmd.Flags().IntVarP(&recommendLimit, "limit", "l", 20, "Number of tracks to return (1-100)")
+	recommendCmd.Flags().StringVarP(&recommendMarket, "market", "m", "", "Market/country code (e.g., US, GB)")
+	recommendCmd.Flags().StringVarP(&recommendFormat, "format", "f", "table", "Output format (table, list, json, yaml)")
+	recommendCmd.Flags().BoolVar(&recommendPlay, "play", false, "Play the recommended tracks on the active device")
+	recommendCmd.Flags().StringVar(&recommendCreatePlaylist, "create-playlist", "", "Save the recommended tracks to a new playlist with this name")
+	addOrderingFlags(recommendCmd, &recommendOrder, 2)
+
+	for _, attribute := range recommendAttributes {
+		for _, bound := range []struct{ prefix, label string }{{"min", "Minimum"}, {"max", "Maximum"}, {"target", "Target"}} {
+			flag := bound.prefix + "-" + attribute
+			value := new(float64)
+			recommendTunables[strings.ReplaceAll(flag, "-", "_")] = value
+			recommendCmd.Flags().Float64Var(value, flag, 0, bound.label+" "+strings.ReplaceAll(attribute, "-", " "))
+		}
+	}
+}
+
+func runRecommend(cmd *cobra.Command) error {
+	validator := api.NewValidator()
+	engine, err := recommendOrder.engine()
+	if err != nil {
+		return err
+	}
+
+	options := &spotify.RecommendationOptions{
+		Limit:  recommendLimit,
+		Market: recommendMarket,
+	}
+
+	if len(recommendSeedArtists) > 0 {
+		if options.SeedArtists, err = validator.NormalizeAndValidateIDs(recommendSeedArtists); err != nil {
+			return fmt.Errorf("invalid --seed-artist: %w", err)
+		}
+	}
+	if len(recommendSeedTracks) > 0 {
+		if options.SeedTracks, err = validator.NormalizeAndValidateIDs(recommendSeedTracks); err != nil {
+			return fmt.Errorf("invalid --seed-track: %w", err)
+		}
+	}
+	for _, genre := range recommendSeedGenres {
+		options.SeedGenres = append(options.SeedGenres, strings.ToLower(strings.TrimSpace(genre)))
+	}
+
+	// Only send the attributes that were set, since 0 is a meaningful value
+	for param, value := range recommendTunables {
+		if cmd.Flags().Changed(strings.ReplaceAll(param, "_", "-")) {
+			if options.AudioFeatures == nil {
+				options.AudioFeatures = make(map[string]interface{})
+			}
+			options.AudioFeatures[param] = *value
+		}
+	}
+
+	var spotifyClient *client.SpotifyClient
+	if recommendPlay || recommendCreatePlaylist != "" {
+		spotifyClient, err = newUserClient("playback and playlists")
+	} else {
+		spotifyClient, err = newBrowseClient()
+	}
+	if err != nil {
+		return err
+	}
+
+	recommendations, err := spotifyClient.Tracks.GetRecommendations(GetCommandContext(), options)
+	if err != nil {
+		return fmt.Errorf("failed to get recommendations: %w", err)
+	}
+	recommendations.Tracks = ordering.Shuffle(engine, recommendations.Tracks, trackKeys)
+
+	if err := outputRecommendations(recommendations); err != nil {
+		return err
+	}
+	if len(recommendations.Tracks) == 0 {
+		return nil
+	}
+
+	uris := make([]string, len(recommendations.Tracks))
+	for i, track := range recommendations.Tracks {
+		uris[i] = track.URI
+	}
+
+	if recommendCreatePlaylist != "" {
+		playlist, err := createPlaylistWithTracks(spotifyClient, recommendCreatePlaylist, "Built with spotify-cli recommend", uris)
+		if err != nil {
+			return err
+		}
+		utils.PrintSuccess("Saved %d track(s) to new playlist: %s", len(uris), playlist.Name)
+		fmt.Printf("Playlist ID: %s\n", playlist.ID)
+	}
+
+	if recommendPlay {
+		if err := spotifyClient.Player.Play(GetCommandContext(), &spotify.PlayOptions{URIs: uris}); err != nil {
+			return playerCommandError(spotifyClient, "start playback", err)
+		}
+		utils.PrintSuccess("Playing %d recommended track%s", len(uris), pluralize(len(uris)))
+	}
+
+	return nil
+}
+
+func outputRecommendations(recommendations *models.Recommendations) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := recommendFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(recommendations)
+	}
+
+	if len(recommendations.Tracks) == 0 {
+		fmt.Println("No recommendations found. Try fewer or looser attribute limits.")
+		return nil
+	}
+
+	fmt.Printf("Recommendations - %d track%s\n\n", len(recommendations.Tracks), pluralize(len(recommendations.Tracks)))
+
+	if recommendFormat == "list" {
+		for i, track := range recommendations.Tracks {
+			fmt.Printf("%d. %s\n", i+1, track.Name)
+			fmt.Printf("   ID: %s\n", track.ID)
+			if len(track.Artists) > 0 {
+				fmt.Printf("   by %s\n", utils.FormatSimpleArtists(track.Artists))
+			}
+			if track.Album != nil && track.Album.Name != "" {
+				fmt.Printf("   from %s\n", track.Album.Name)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	fmt.Printf("%-22s %-40s %-25s %-25s %s\n", "ID", "TRACK", "ARTIST", "ALBUM", "DURATION")
+	fmt.Println(strings.Repeat("-", 130))
+
+	for _, track := range recommendations.Tracks {
+		album := ""
+		if track.Album != nil {
+			album = track.Album.Name
+		}
+
+		fmt.Printf("%-22s %-40s %-25s %-25s %s\n",
+			track.ID,
+			truncateString(track.Name, 38),
+			truncateString(utils.FormatSimpleArtists(track.Artists), 23),
+			truncateString(album, 23),
+			utils.FormatDuration(track.DurationMs))
+	}
+
+	return nil
+}
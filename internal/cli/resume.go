@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/checkpoint"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+)
+
+func addResumeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("resume", "", "Carry on with a run that stopped partway, from the resume token it printed")
+}
+
+// resumeToken returns --resume, or "" for a fresh run
+func resumeToken(cmd *cobra.Command) string {
+	token, _ := cmd.Flags().GetString("resume")
+	return token
+}
+
+// batchCheckpoint records which items of a batch have finished. A nil
+// batchCheckpoint, as for a dry run, records nothing.
+type batchCheckpoint struct {
+	dir     *state.Dir
+	command string
+	token   string
+
+	// mu keeps the first failure to record from being reported repeatedly
+	mu     sync.Mutex
+	failed bool
+}
+
+// startCheckpoint records a checkpoint for a fresh batch over items. Not
+// being able to record one only costs the chance to resume, so that is a
+// warning rather than an error.
+func startCheckpoint(cmd *cobra.Command, items []string) *batchCheckpoint {
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return nil
+	}
+
+	dir, err := openState()
+	if err != nil {
+		utils.PrintWarning("Progress will not be saved: %v", err)
+		return nil
+	}
+
+	command := cmd.CommandPath()
+	if earlier, err := checkpoint.Unfinished(dir, command); err == nil && len(earlier) > 0 {
+		left := len(earlier[0].Remaining())
+		utils.PrintWarning("A run of '%s' from %s stopped with %d item%s left; resume it with --resume %s",
+			command, formatTime(earlier[0].UpdatedAt), left, pluralize(left), earlier[0].Token)
+	}
+
+	c, err := checkpoint.Create(dir, command, items, time.Now())
+	if err != nil {
+		utils.PrintWarning("Progress will not be saved: %v", err)
+		return nil
+	}
+	utils.PrintVerbose("Saving progress under resume token %s", c.Token)
+	return &batchCheckpoint{dir: dir, command: command, token: c.Token}
+}
+
+// loadCheckpoint returns the checkpoint --resume names and the items it has
+// left. It must belong to the command being run.
+func loadCheckpoint(cmd *cobra.Command, token string) (*batchCheckpoint, []string, error) {
+	dir, err := openState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := checkpoint.Load(dir, token)
+	if errors.Is(err, checkpoint.ErrNotFound) {
+		return nil, nil, &ExitError{Code: daemon.ExitUsage, Err: err}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	command := cmd.CommandPath()
+	if c.Command != command {
+		err := fmt.Errorf("resume token %s belongs to '%s', not '%s'", token, c.Command, command)
+		return nil, nil, &ExitError{Code: daemon.ExitUsage, Err: err}
+	}
+
+	remaining := c.Remaining()
+	fmt.Printf("Resuming the run from %s: %d of %d item%s left\n",
+		formatTime(c.CreatedAt), len(remaining), len(c.Items), pluralize(len(c.Items)))
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return nil, remaining, nil
+	}
+	return &batchCheckpoint{dir: dir, command: command, token: token}, remaining, nil
+}
+
+// done records an item as finished. It is safe to call from several
+// goroutines.
+func (b *batchCheckpoint) done(item string) {
+	if b == nil {
+		return
+	}
+	if err := checkpoint.MarkDone(b.dir, b.token, time.Now(), item); err != nil {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if !b.failed {
+			utils.PrintWarning("Failed to save progress: %v", err)
+			b.failed = true
+		}
+	}
+}
+
+// finish drops the checkpoint of a batch that left nothing unfinished, and
+// otherwise says how to resume it
+func (b *batchCheckpoint) finish(unfinished int) {
+	if b == nil {
+		return
+	}
+	if unfinished == 0 {
+		if err := checkpoint.Remove(b.dir, b.token); err != nil {
+			utils.PrintVerbose("Failed to remove checkpoint %s: %v", b.token, err)
+		}
+		return
+	}
+	fmt.Printf("\n%d item%s did not finish. Retry just those with:\n  %s --resume %s\n",
+		unfinished, pluralize(unfinished), b.command, b.token)
+}
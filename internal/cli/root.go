@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/bambithedeer/spotify-api/internal/cli/config"
@@ -13,11 +15,18 @@ import (
 )
 
 var (
-	cfgFile     string
-	verbose     bool
-	output      string
-	configDir   string
-	cacheDir    string
+	cfgFile       string
+	verbose       bool
+	output        string
+	configDir     string
+	cacheDir      string
+	noCache       bool
+	profileName   string
+	absoluteTimes bool
+
+	// commandLine is the running command and its arguments, recorded with
+	// the changes and API usage it causes
+	commandLine string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -30,6 +39,16 @@ You can search for music, manage your library, control playback, and more.
 Before using this tool, you'll need to authenticate with Spotify using:
   spotify-cli auth login
 
+Exit codes:
+  0    success
+  1    unclassified failure
+  64   invalid flags, arguments or input
+  74   a local file could not be read or written
+  75   network or API failure (safe to retry later)
+  77   authentication failure or a missing scope
+  78   configuration error
+  130  interrupted
+
 Examples:
   spotify-cli search track "bohemian rhapsody"
   spotify-cli library tracks
@@ -38,14 +57,31 @@ Examples:
 	SilenceUsage:  true,
 	SilenceErrors: false,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return initConfig()
+		if err := initConfig(); err != nil {
+			return err
+		}
+		commandLine = cmd.CommandPath()
+		if len(args) > 0 {
+			commandLine += " " + strings.Join(args, " ")
+		}
+		startAuditLog(commandLine)
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() error {
-	return rootCmd.Execute()
+	registerScriptCommands(os.Args[1:])
+	markUsageErrors(rootCmd)
+
+	startedAt := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	// Recorded even when the command failed, since failures are often the
+	// rate limits worth knowing about
+	recordQuotaUsage()
+	writeRunSummary(cmd, startedAt, err)
+	return err
 }
 
 // GetCommandContext returns a context for command execution
@@ -60,6 +96,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "text", "output format (text, json, yaml)")
 	rootCmd.PersistentFlags().StringVar(&configDir, "config-dir", "", "config directory (default is $HOME/.spotify-cli)")
 	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "cache directory (default is $HOME/.spotify-cli/cache)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "bypass the API response cache")
+	rootCmd.PersistentFlags().BoolVar(&absoluteTimes, "absolute", false, "show dates and times as local timestamps instead of relative ones like \"3 days ago\"")
+	rootCmd.PersistentFlags().StringVar(&summaryJSON, "summary-json", "", "write a JSON summary of the run (status, exit code, counts, duration, errors) to this file ('-' for stdout)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "account profile to use (default is the active profile, or $SPOTIFY_CLI_PROFILE)")
 
 	// Add subcommands
 	rootCmd.AddCommand(newVersionCmd())
@@ -89,11 +129,31 @@ func initConfig() error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
+	// Resolve the account profile: flag > environment > active profile
+	if profileName == "" {
+		profileName = os.Getenv("SPOTIFY_CLI_PROFILE")
+	}
+	if profileName == "" {
+		profileName = config.ActiveProfile(configDir)
+		if !config.ProfileExists(configDir, profileName) {
+			utils.PrintWarning("Active profile %q no longer exists, using %q", profileName, config.DefaultProfile)
+			profileName = config.DefaultProfile
+		}
+	}
+	if err := config.ValidateProfileName(profileName); err != nil {
+		return err
+	}
+
 	// Initialize config
 	if cfgFile == "" {
-		cfgFile = filepath.Join(configDir, "config.yaml")
+		if !config.ProfileExists(configDir, profileName) {
+			return fmt.Errorf("profile %q does not exist. Create it with 'spotify-cli profile create %s'", profileName, profileName)
+		}
+		cfgFile = config.ProfilePath(configDir, profileName)
 	}
 
+	config.SetCacheDir(httpCacheDir(), noCache)
+
 	return config.Init(cfgFile, verbose, output)
 }
 
@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/config"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/quiet"
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleDryRun bool
+	scheduleForce  bool
+	scheduleSlot   string
+
+	// scheduleHeldBack is the slot occurrence quiet hours last held back,
+	// so a daemon says so once rather than on every check
+	scheduleHeldBack string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Play different playlists at different times of the week",
+	Long: `Rotate what plays by time of day and day of the week: a morning playlist on
+weekdays, a focus playlist in working hours, a mix on Friday evenings.
+
+Slots are listed under schedule in config.yaml. Each has a name, the days
+and the start and end time it covers, the context to play (a playlist,
+album, artist or show, as a URI or link), and optionally the device to play
+on and the shuffle and volume to set:
+
+  schedule:
+    - name: friday
+      days: [fri]
+      start: "17:00"
+      end: "23:00"
+      context: spotify:playlist:37i9dQZF1DXcBWIGoYBM5M
+      device: Living Room
+    - name: morning
+      days: [weekdays]
+      start: "07:00"
+      end: "09:30"
+      context: https://open.spotify.com/playlist/37i9dQZF1DX0XUsuxWHRQd
+      shuffle: true
+      volume: 40
+
+Days are names such as mon or friday, ranges such as mon-fri, weekdays or
+weekends; without days a slot covers every day. A slot ending before it
+starts runs past midnight. When slots overlap the one listed first wins.
+
+Each slot starts once, when it begins: 'schedule apply' starts the slot that
+covers the current time unless it has already been started, so it can run
+from cron every few minutes, and 'schedule run' keeps doing the same in the
+background. Whatever you play in the middle of a slot is left alone until
+the next one begins.
+
+During quiet hours (see 'spotify-cli quiet') slots are held back unless they
+are marked alarm: true; one that is still on when quiet hours end starts
+then.`,
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the schedule's slots and which one is active",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		slots, err := loadSchedule()
+		if err != nil {
+			return err
+		}
+		return outputSchedule(slots, time.Now())
+	},
+}
+
+var scheduleApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Start the slot that covers the current time",
+	Long: `Start playing the context of the slot that covers the current time, on its
+device, unless that slot has already been started since it began. --force
+starts it again, and --slot starts a slot by name whatever the time, even
+in quiet hours (see 'spotify-cli quiet').
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli schedule apply
+  spotify-cli schedule apply --slot friday
+  */5 * * * * spotify-cli schedule apply`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runScheduleApply(cmd, false)
+	},
+}
+
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Keep running and start each slot as it begins",
+	Long: `Check the schedule every --interval and start each slot as it begins, as
+'schedule apply' does. A slot whose device is offline when it begins is
+tried again on every check until it starts or the slot ends.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli schedule run
+  spotify-cli daemon install schedule -- schedule run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runScheduleApply(cmd, true)
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{scheduleApplyCmd, scheduleRunCmd} {
+		cmd.Flags().BoolVar(&scheduleDryRun, "dry-run", false, "Show what would be played without changing playback")
+	}
+	scheduleApplyCmd.Flags().BoolVar(&scheduleForce, "force", false, "Start the active slot even if it was already started")
+	scheduleApplyCmd.Flags().StringVar(&scheduleSlot, "slot", "", "Start this slot by name, whatever the time")
+	addDaemonFlags(scheduleRunCmd, time.Minute)
+
+	scheduleCmd.AddCommand(scheduleListCmd, scheduleApplyCmd, scheduleRunCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+// loadSchedule reads the slots from the config file
+func loadSchedule() ([]schedule.Slot, error) {
+	cfg, err := config.LoadSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Schedule) == 0 {
+		return nil, apierrors.NewConfigError("no schedule is configured: add slots under schedule in config.yaml, see 'spotify-cli schedule --help'")
+	}
+
+	slots := make([]schedule.Slot, 0, len(cfg.Schedule))
+	names := make(map[string]bool)
+	for i, s := range cfg.Schedule {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("slot %d", i+1)
+		}
+		if names[name] {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("schedule: two slots are named %q", name))
+		}
+		names[name] = true
+
+		window, err := schedule.NewWindow(s.Days, s.Start, s.End)
+		if err != nil {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("schedule %s: %v", name, err))
+		}
+		contextURI, err := scheduleContext(s.Context)
+		if err != nil {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("schedule %s: %v", name, err))
+		}
+		if s.Volume != nil && (*s.Volume < 0 || *s.Volume > 100) {
+			return nil, apierrors.NewConfigError(fmt.Sprintf("schedule %s: volume must be between 0 and 100", name))
+		}
+		slots = append(slots, schedule.Slot{
+			Name:    name,
+			Window:  window,
+			Context: contextURI,
+			Device:  s.Device,
+			Shuffle: s.Shuffle,
+			Volume:  s.Volume,
+			Alarm:   s.Alarm,
+		})
+	}
+	return slots, nil
+}
+
+// scheduleContext turns a slot's context into a URI that can be played as a
+// context
+func scheduleContext(ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("context is required")
+	}
+	uri, _, ok, err := parseSpotifyLink(ref)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		uri = ref
+	}
+	for _, kind := range []string{"playlist", "album", "artist", "show"} {
+		if strings.HasPrefix(uri, "spotify:"+kind+":") {
+			return uri, nil
+		}
+	}
+	return "", fmt.Errorf("context %q is not a playlist, album, artist or show", ref)
+}
+
+func runScheduleApply(cmd *cobra.Command, watch bool) error {
+	slots, err := loadSchedule()
+	if err != nil {
+		return err
+	}
+	if scheduleSlot != "" {
+		found := false
+		for _, slot := range slots {
+			found = found || slot.Name == scheduleSlot
+		}
+		if !found {
+			return fmt.Errorf("no slot is named %q", scheduleSlot)
+		}
+	}
+
+	hours, err := loadQuietHours()
+	if err != nil {
+		return err
+	}
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	// A dry run only reads the schedule
+	var spotifyClient *client.SpotifyClient
+	if !scheduleDryRun {
+		if spotifyClient, err = newUserClient("playback control"); err != nil {
+			return err
+		}
+	}
+
+	if !watch {
+		_, err := applySchedule(GetCommandContext(), spotifyClient, dir, slots, hours, time.Now())
+		return err
+	}
+	return runDaemonLoop(cmd, "schedule", func(ctx context.Context, report *daemon.Report) error {
+		now := time.Now()
+		started, err := applySchedule(ctx, spotifyClient, dir, slots, hours, now)
+		if started {
+			report.Add("slots started", 1)
+		}
+		if err != nil || scheduleDryRun {
+			return err
+		}
+
+		// An alarm may be loud; anything else is kept under the cap
+		if slot, _, ok := schedule.Active(slots, now); ok && slot.Alarm {
+			return nil
+		}
+		lowered, err := enforceQuietVolume(ctx, spotifyClient, hours, now)
+		if lowered {
+			report.Add("volume lowered", 1)
+		}
+		return err
+	})
+}
+
+// applySchedule starts the slot covering now when it is due, and reports
+// whether it did
+func applySchedule(ctx context.Context, spotifyClient *client.SpotifyClient, dir *state.Dir, slots []schedule.Slot, hours *quiet.Hours, now time.Time) (bool, error) {
+	var slot *schedule.Slot
+	var start time.Time
+	if scheduleSlot != "" {
+		for i := range slots {
+			if slots[i].Name == scheduleSlot {
+				slot = &slots[i]
+			}
+		}
+		// Starting the active slot by hand counts as starting it
+		var ok bool
+		if start, ok = slot.Window.Covering(now); !ok {
+			start = now
+		}
+	} else {
+		var ok bool
+		if slot, start, ok = schedule.Active(slots, now); !ok {
+			utils.PrintVerbose("No slot covers %s", now.Format("Mon 15:04"))
+			return false, nil
+		}
+	}
+
+	applied, err := schedule.Load(dir)
+	if err != nil {
+		return false, err
+	}
+	if scheduleSlot == "" && !scheduleForce && !applied.Due(slot, start) {
+		utils.PrintVerbose("%s was already started at %s", slot.Name, applied.AppliedAt.Format("Mon 15:04"))
+		return false, nil
+	}
+	if scheduleSlot == "" && !slot.Alarm && hours.Active(now) {
+		if held := slot.Name + "@" + start.String(); held != scheduleHeldBack {
+			utils.PrintWarning("Quiet hours until %s: not starting %s", hours.Until(now).Format("Mon 15:04"), slot.Name)
+			scheduleHeldBack = held
+		}
+		return false, nil
+	}
+
+	device := slot.Device
+	if device == "" {
+		device = "the active device"
+	}
+	if scheduleDryRun {
+		fmt.Printf("Dry run: %s would play %s on %s\n", slot.Name, slot.Context, device)
+		return false, nil
+	}
+
+	if err := startSlot(ctx, spotifyClient, slot); err != nil {
+		return false, fmt.Errorf("failed to start %s: %w", slot.Name, err)
+	}
+	if err := schedule.Record(dir, schedule.Applied{Slot: slot.Name, Start: start, AppliedAt: now}); err != nil {
+		return true, err
+	}
+	utils.PrintSuccess("%s: playing %s on %s", slot.Name, slot.Context, device)
+	return true, nil
+}
+
+// startSlot plays a slot's context on its device with its settings
+func startSlot(ctx context.Context, spotifyClient *client.SpotifyClient, slot *schedule.Slot) error {
+	player := spotifyClient.Player
+
+	deviceID := ""
+	if slot.Device != "" {
+		devices, err := playerDevices(spotifyClient)
+		if err != nil {
+			return fmt.Errorf("failed to get devices: %w", err)
+		}
+		device, err := resolveDevice(devices, slot.Device)
+		if err != nil {
+			return err
+		}
+		deviceID = device.ID
+	}
+
+	// Shuffle set before playing picks the first track at random too; it
+	// only works once the device is active, so otherwise it is set after
+	shuffled := slot.Shuffle == nil
+	if !shuffled {
+		shuffled = player.SetShuffle(ctx, *slot.Shuffle, deviceID) == nil
+	}
+	if err := player.Play(ctx, &spotify.PlayOptions{DeviceID: deviceID, ContextURI: slot.Context}); err != nil {
+		return err
+	}
+	if !shuffled {
+		if err := player.SetShuffle(ctx, *slot.Shuffle, deviceID); err != nil {
+			return fmt.Errorf("failed to set shuffle: %w", err)
+		}
+	}
+	if slot.Volume != nil {
+		if err := player.SetVolume(ctx, *slot.Volume, deviceID); err != nil {
+			return fmt.Errorf("failed to set volume: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+)
+
+// scheduleEntry is a slot as listed
+type scheduleEntry struct {
+	Name    string `json:"name" yaml:"name"`
+	Days    string `json:"days" yaml:"days"`
+	Start   string `json:"start" yaml:"start"`
+	End     string `json:"end" yaml:"end"`
+	Context string `json:"context" yaml:"context"`
+	Device  string `json:"device,omitempty" yaml:"device,omitempty"`
+	Active  bool   `json:"active" yaml:"active"`
+	// Next is when the slot next begins
+	Next time.Time `json:"next" yaml:"next"`
+}
+
+func outputSchedule(slots []schedule.Slot, now time.Time) error {
+	active, _, _ := schedule.Active(slots, now)
+	entries := make([]scheduleEntry, len(slots))
+	for i, slot := range slots {
+		entries[i] = scheduleEntry{
+			Name:    slot.Name,
+			Days:    slot.Window.Days.String(),
+			Start:   slot.Window.Start.String(),
+			End:     slot.Window.End.String(),
+			Context: slot.Context,
+			Device:  slot.Device,
+			Active:  active != nil && active.Name == slot.Name,
+			Next:    slot.Window.Next(now),
+		}
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(entries)
+	}
+
+	fmt.Printf("  %-15s %-22s %-11s %-40s %s\n", "SLOT", "DAYS", "TIME", "CONTEXT", "DEVICE")
+	fmt.Println(strings.Repeat("-", 110))
+	for _, entry := range entries {
+		marker := " "
+		if entry.Active {
+			marker = "*"
+		}
+		device := entry.Device
+		if device == "" {
+			device = "(active device)"
+		}
+		fmt.Printf("%s %-15s %-22s %-11s %-40s %s\n", marker,
+			truncateString(entry.Name, 13),
+			truncateString(entry.Days, 20),
+			entry.Start+"-"+entry.End,
+			entry.Context,
+			device)
+	}
+
+	fmt.Println()
+	if active != nil {
+		fmt.Printf("Active now: %s\n", active.Name)
+	}
+	if next, at, ok := schedule.Next(slots, now); ok {
+		fmt.Printf("Next: %s at %s\n", next.Name, at.Format("Mon 15:04"))
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/query"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// scriptCmd represents the script command
+var scriptCmd = &cobra.Command{
+	Use:   "script",
+	Short: "Run saved scripts and queries as commands",
+	Long: `Save scripts and queries and run them as commands.
+
+A script is a file ending in .star, written in Starlark, a small dialect of
+Python (https://github.com/bazelbuild/starlark/blob/master/spec.md), with
+while loops and statements at the top level allowed. Scripts run
+sandboxed: they cannot read files, reach the network other than through
+the modules below, load other scripts or recurse, and are stopped if they
+run too long. The first comment describes the script.
+
+  args                     The arguments the script was run with, as strings
+  dry_run                  True with --dry-run; writes and playback are skipped
+  query(expr)              Run a query, see 'query --help', returning its tracks
+  search.tracks(q, limit=20), .albums, .artists, .playlists
+  playlists.mine(limit=0)  Your playlists
+  playlists.get(ref)       A playlist by ID, URI or name, or None
+  playlists.tracks(ref, limit=0)
+  playlists.save(name, tracks, append=False)
+                           Replace or add to a playlist, creating it if needed
+  playlists.remove(ref, tracks)
+  library.tracks(limit=0), library.albums(limit=0)
+  library.save(tracks), library.remove(tracks), library.contains(tracks)
+  player.current()         What is playing, or None
+  player.devices()
+  player.play(tracks=None, context=None, device=None)
+  player.pause(), .next(), .previous(), each taking device=None
+  player.queue(tracks), player.volume(percent), player.shuffle(on)
+
+Tracks, albums, artists, playlists and devices are structs with fields
+such as id, uri, name, artist, album, duration_ms and popularity. Functions
+taking tracks accept a URI, an ID, a track or a list of them.
+
+A file ending in .query holds a single query in the same language as the
+query command, split over as many lines as you like. Lines starting with #
+are comments, and the first comment is shown as the script's description.
+$1 to $9 are replaced with the arguments the script is run with.
+
+Scripts are read from these directories, earlier ones taking precedence:
+  scripts in the profile's directory, for profiles other than default
+  $HOME/.spotify-cli/scripts (or scripts in --config-dir)
+  $XDG_CONFIG_HOME/spotify-cli/scripts (default ~/.config/spotify-cli/scripts)
+
+Each script is also available as a top-level command named after its file,
+unless a built-in command already has that name.
+
+Example script, saved as recent.star:
+
+  # Save the tracks liked this week to a playlist
+  week = [t for t in library.tracks(limit = 200) if t.added_at >= args[0]]
+  playlists.save("This week", week)
+  print("Saved %d tracks" % len(week))
+
+The same as a query, saved as workout.query:
+
+  # Queue the most energetic tracks of a playlist
+  playlist("$1")
+    .filter(energy>0.8, tempo>=120)
+    .sort(energy, desc)
+    .limit($2)
+    .queue()
+
+Run them with:
+
+  spotify-cli recent 2024-06-01
+  spotify-cli workout "Running" 20`,
+}
+
+// scriptListCmd represents the script list command
+var scriptListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available scripts",
+	Long:  `List the scripts found in the script directories.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runScriptList()
+	},
+}
+
+// scriptRunCmd represents the script run command
+var scriptRunCmd = &cobra.Command{
+	Use:   "run <name> [args...]",
+	Short: "Run a script",
+	Long: `Run a script by name, passing it the arguments that follow.
+
+Requires user authentication. Use 'auth login' to authenticate with user account first.`,
+	Example: `  spotify-cli script run workout "Running" 20
+  spotify-cli script run workout "Running" 20 --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, err := findScript(args[0])
+		if err != nil {
+			return err
+		}
+		return script.run(args[1:])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scriptCmd)
+	scriptCmd.AddCommand(scriptListCmd)
+	scriptCmd.AddCommand(scriptRunCmd)
+
+	scriptRunCmd.Flags().BoolVar(&queryDryRun, "dry-run", false, "Run the script but do not write to any playlist or start playback")
+	scriptRunCmd.Flags().StringVarP(&queryFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+// savedScript is a Starlark script, or a query script when query is set
+type savedScript struct {
+	Name        string
+	Path        string
+	Description string
+	Source      string
+
+	query *query.Script
+}
+
+func (s *savedScript) run(args []string) error {
+	if s.query != nil {
+		return runScript(s.query, args)
+	}
+	return runStarScript(s, args)
+}
+
+// scriptDirs returns the directories scripts are read from, in order of
+// precedence
+func scriptDirs() []string {
+	return scriptDirsFor(configDir, profileName)
+}
+
+// scriptDirsFor returns the script directories for a config directory and
+// profile, either of which may be empty for the default
+func scriptDirsFor(dir, profile string) []string {
+	var dirs []string
+
+	home, _ := os.UserHomeDir()
+	if dir == "" && home != "" {
+		dir = filepath.Join(home, ".spotify-cli")
+	}
+	if dir != "" {
+		if profile != "" && profile != config.DefaultProfile {
+			dirs = append(dirs, filepath.Join(config.ProfileDir(dir, profile), "scripts"))
+		}
+		dirs = append(dirs, filepath.Join(dir, "scripts"))
+	}
+
+	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfig == "" && home != "" {
+		xdgConfig = filepath.Join(home, ".config")
+	}
+	if xdgConfig != "" {
+		dirs = append(dirs, filepath.Join(xdgConfig, "spotify-cli", "scripts"))
+	}
+
+	return dirs
+}
+
+// loadScripts loads the Starlark and query scripts in dirs. As with
+// query.FindScripts, a script in an earlier directory hides one with the same
+// name in a later one, whichever kind either is.
+func loadScripts(dirs []string) ([]*savedScript, []error) {
+	byName := make(map[string]*savedScript)
+	var errs []error
+
+	for _, dir := range dirs {
+		var found []*savedScript
+
+		queries, queryErrs := query.FindScripts(dir)
+		errs = append(errs, queryErrs...)
+		for _, q := range queries {
+			found = append(found, &savedScript{Name: q.Name, Path: q.Path, Description: q.Description, Source: q.Source, query: q})
+		}
+
+		paths, _ := filepath.Glob(filepath.Join(dir, "*"+starScriptExt))
+		for _, path := range paths {
+			script, err := loadStarScript(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			found = append(found, script)
+		}
+
+		for _, script := range found {
+			if _, exists := byName[script.Name]; !exists {
+				byName[script.Name] = script
+			}
+		}
+	}
+
+	scripts := make([]*savedScript, 0, len(byName))
+	for _, script := range byName {
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].Name < scripts[j].Name
+	})
+
+	return scripts, errs
+}
+
+func findScript(name string) (*savedScript, error) {
+	scripts, _ := loadScripts(scriptDirs())
+	for _, script := range scripts {
+		if script.Name == name {
+			return script, nil
+		}
+	}
+	return nil, fmt.Errorf("no script named %q. Use 'script list' to see available scripts", name)
+}
+
+func runScript(script *query.Script, args []string) error {
+	expression, err := script.Expand(args)
+	if err != nil {
+		return err
+	}
+
+	utils.PrintVerbose("Running %s: %s", script.Path, expression)
+	return runQuery(expression)
+}
+
+func runScriptList() error {
+	scripts, errs := loadScripts(scriptDirs())
+	for _, err := range errs {
+		utils.PrintWarning("Skipping script %v", err)
+	}
+
+	if len(scripts) == 0 {
+		fmt.Println("No scripts found. Save scripts as .star or .query files in:")
+		for _, dir := range scriptDirs() {
+			fmt.Printf("  %s\n", dir)
+		}
+		return nil
+	}
+
+	fmt.Printf("%-20s %-6s %s\n", "NAME", "ARGS", "DESCRIPTION")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, script := range scripts {
+		scriptArgs := "any"
+		if script.query != nil {
+			scriptArgs = strconv.Itoa(script.query.Args)
+		}
+		fmt.Printf("%-20s %-6s %s\n", truncateString(script.Name, 18), scriptArgs, truncateString(script.Description, 52))
+	}
+
+	return nil
+}
+
+// registerScriptCommands adds a top-level command for each script when args
+// do not name a built-in command. Flags are not parsed yet, so --config-dir
+// and --profile are read from args here to find the same scripts 'script run'
+// would. Scripts named like a built-in command are only reachable with
+// 'script run'.
+func registerScriptCommands(args []string) {
+	if cmd, _, err := rootCmd.Find(args); err == nil && cmd != rootCmd {
+		return
+	}
+
+	dir, profile := scriptFlags(args)
+	scripts, _ := loadScripts(scriptDirsFor(dir, profile))
+
+	builtin := make(map[string]bool)
+	for _, cmd := range rootCmd.Commands() {
+		builtin[cmd.Name()] = true
+		for _, alias := range cmd.Aliases {
+			builtin[alias] = true
+		}
+	}
+	builtin["help"] = true
+	builtin["completion"] = true
+
+	for _, script := range scripts {
+		if builtin[script.Name] {
+			continue
+		}
+
+		script := script
+		short := script.Description
+		if short == "" {
+			short = "Run the " + script.Name + " script"
+		}
+
+		cmd := &cobra.Command{
+			Use:   script.Name + " [args...]",
+			Short: short + " (script)",
+			Long:  fmt.Sprintf("%s\n\nRuns the script %s:\n\n%s", short, script.Path, script.Source),
+			Args:  cobra.ArbitraryArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return script.run(args)
+			},
+		}
+		if script.query != nil {
+			cmd.Use = script.Name + strings.Repeat(" <arg>", script.query.Args)
+			cmd.Args = cobra.ExactArgs(script.query.Args)
+		}
+		cmd.Flags().BoolVar(&queryDryRun, "dry-run", false, "Run the script but do not write to any playlist or start playback")
+		cmd.Flags().StringVarP(&queryFormat, "format", "f", "table", "Output format (table, json, yaml)")
+
+		rootCmd.AddCommand(cmd)
+	}
+}
+
+// scriptFlags reads the config directory and profile from args the way
+// initConfig resolves them, without creating anything
+func scriptFlags(args []string) (dir, profile string) {
+	flags := pflag.NewFlagSet("scripts", pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Value.Type() == "bool" {
+			flags.BoolP(flag.Name, flag.Shorthand, false, "")
+		} else {
+			flags.StringP(flag.Name, flag.Shorthand, "", "")
+		}
+	})
+	_ = flags.Parse(args)
+
+	dir, _ = flags.GetString("config-dir")
+	if dir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".spotify-cli")
+		}
+	}
+
+	profile, _ = flags.GetString("profile")
+	if profile == "" {
+		profile = os.Getenv("SPOTIFY_CLI_PROFILE")
+	}
+	if profile == "" && dir != "" {
+		profile = config.ActiveProfile(dir)
+	}
+	if config.ValidateProfileName(profile) != nil || !config.ProfileExists(dir, profile) {
+		profile = config.DefaultProfile
+	}
+	return dir, profile
+}
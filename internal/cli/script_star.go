@@ -0,0 +1,824 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/query"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// starScriptExt is the file extension of Starlark scripts
+const starScriptExt = ".star"
+
+var starScriptName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// loadStarScript reads a Starlark script. Its description is the first line
+// of the comments it starts with.
+func loadStarScript(path string) (*savedScript, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, apierrors.WrapFileError(err, "failed to read script")
+	}
+	name := strings.TrimSuffix(filepath.Base(path), starScriptExt)
+	if !starScriptName.MatchString(name) {
+		return nil, apierrors.NewValidationError(fmt.Sprintf("invalid script name %q: use lowercase letters, digits, - and _", name))
+	}
+
+	script := &savedScript{Name: name, Path: path, Source: string(data)}
+	for _, line := range strings.Split(script.Source, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		if description := strings.TrimSpace(strings.TrimPrefix(trimmed, "#")); description != "" && !strings.HasPrefix(description, "!") {
+			script.Description = description
+			break
+		}
+	}
+	return script, nil
+}
+
+// starMaxSteps is how much work a script may do: far more than any script
+// over a music library needs, but a loop that never ends runs into it
+// within seconds
+const starMaxSteps = 10_000_000
+
+// starFileOptions is the dialect scripts are written in. Scripts are
+// programs rather than configuration, so while loops and control flow at
+// the top level are allowed; recursion is not.
+var starFileOptions = &syntax.FileOptions{
+	Set:             true,
+	While:           true,
+	TopLevelControl: true,
+	GlobalReassign:  true,
+}
+
+// starContextKey is the thread local holding the context builtins run with
+const starContextKey = "context"
+
+// runStarScript runs a Starlark script with the script modules and args
+// holding its arguments
+func runStarScript(script *savedScript, args []string) error {
+	host := &scriptHost{name: script.Name, dryRun: queryDryRun}
+
+	utils.PrintVerbose("Running %s", script.Path)
+	th, err := execStarScript(GetCommandContext(), script.Path, script.Source, host.predeclared(args), func(msg string) { fmt.Println(msg) })
+	if err != nil {
+		return fmt.Errorf("script %s failed: %w", script.Name, err)
+	}
+	utils.PrintVerbose("%s finished in %d steps", script.Name, th.ExecutionSteps())
+	return nil
+}
+
+// execStarScript runs src sandboxed: the thread has no load, so scripts
+// cannot import files, only predeclared reaches outside the script, and it
+// is cancelled after starMaxSteps steps or when ctx is done. Errors in the
+// script carry its traceback.
+func execStarScript(ctx context.Context, path, src string, predeclared starlark.StringDict, print func(string)) (*starlark.Thread, error) {
+	th := &starlark.Thread{
+		Name:  path,
+		Print: func(_ *starlark.Thread, msg string) { print(msg) },
+	}
+	th.SetLocal(starContextKey, ctx)
+	th.SetMaxExecutionSteps(starMaxSteps)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			th.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+
+	_, err := starlark.ExecFileOptions(starFileOptions, th, path, src, predeclared)
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return th, fmt.Errorf("%s", evalErr.Backtrace())
+	}
+	return th, err
+}
+
+// scriptHost gives Starlark scripts the Spotify services. It logs in on the
+// first call that needs to, so scripts that fail early never do.
+type scriptHost struct {
+	name    string
+	dryRun  bool
+	backend *queryBackend
+}
+
+func (h *scriptHost) client() (*queryBackend, error) {
+	if h.backend != nil {
+		return h.backend, nil
+	}
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return nil, err
+	}
+	h.backend = &queryBackend{
+		spotifyClient: spotifyClient,
+		validator:     api.NewValidator(),
+		description:   "Built with spotify-cli script " + h.name,
+	}
+	return h.backend, nil
+}
+
+// scriptFunc is a script builtin that needs the Spotify client
+type scriptFunc func(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error)
+
+func (h *scriptHost) builtin(name string, call scriptFunc) *starlark.Builtin {
+	return starlark.NewBuiltin(name, func(th *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		b, err := h.client()
+		if err != nil {
+			return nil, err
+		}
+		ctx, _ := th.Local(starContextKey).(context.Context)
+		return call(ctx, b, fn, args, kwargs)
+	})
+}
+
+func (h *scriptHost) module(name string, calls map[string]scriptFunc) *starlarkstruct.Module {
+	members := make(starlark.StringDict, len(calls))
+	for callName, call := range calls {
+		members[callName] = h.builtin(name+"."+callName, call)
+	}
+	return &starlarkstruct.Module{Name: name, Members: members}
+}
+
+// predeclared is what a script can use besides the builtins
+func (h *scriptHost) predeclared(args []string) starlark.StringDict {
+	argv := make([]starlark.Value, len(args))
+	for i, arg := range args {
+		argv[i] = starlark.String(arg)
+	}
+
+	return starlark.StringDict{
+		"args":    starlark.NewList(argv),
+		"dry_run": starlark.Bool(h.dryRun),
+		"query":   h.builtin("query", h.runQuery),
+		"search": h.module("search", map[string]scriptFunc{
+			"tracks":    h.searchTracks,
+			"albums":    h.searchAlbums,
+			"artists":   h.searchArtists,
+			"playlists": h.searchPlaylists,
+		}),
+		"playlists": h.module("playlists", map[string]scriptFunc{
+			"mine":   h.myPlaylists,
+			"get":    h.getPlaylist,
+			"tracks": h.playlistTracks,
+			"save":   h.savePlaylist,
+			"remove": h.removeFromPlaylist,
+		}),
+		"library": h.module("library", map[string]scriptFunc{
+			"tracks":   h.savedTracks,
+			"albums":   h.savedAlbums,
+			"save":     h.saveTracks,
+			"remove":   h.removeTracks,
+			"contains": h.containsTracks,
+		}),
+		"player": h.module("player", map[string]scriptFunc{
+			"current":  h.current,
+			"devices":  h.devices,
+			"play":     h.play,
+			"pause":    h.playerCommand("pause playback", (*spotify.PlayerService).Pause),
+			"next":     h.playerCommand("skip to next track", (*spotify.PlayerService).Next),
+			"previous": h.playerCommand("skip to previous track", (*spotify.PlayerService).Previous),
+			"queue":    h.queue,
+			"volume":   h.volume,
+			"shuffle":  h.shuffle,
+		}),
+	}
+}
+
+// pageAll reads a pager to the end, or to limit items when it is positive
+func pageAll[T any](ctx context.Context, pager *api.Pager[T], limit int, value func(T) starlark.Value) (starlark.Value, error) {
+	if limit > 0 {
+		pager = pager.WithMaxItems(limit)
+	}
+	items, err := pager.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]starlark.Value, len(items))
+	for i, item := range items {
+		values[i] = value(item)
+	}
+	return starlark.NewList(values), nil
+}
+
+func unpackSearch(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (string, int, error) {
+	text, limit := "", 20
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "query", &text, "limit?", &limit); err != nil {
+		return "", 0, err
+	}
+	return text, limit, nil
+}
+
+func (h *scriptHost) searchTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	text, limit, err := unpackSearch(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Search.SearchTracksPager(text, nil), limit, func(t models.Track) starlark.Value { return trackValue(t, "") })
+}
+
+func (h *scriptHost) searchAlbums(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	text, limit, err := unpackSearch(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Search.SearchAlbumsPager(text, nil), limit, albumValue)
+}
+
+func (h *scriptHost) searchArtists(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	text, limit, err := unpackSearch(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Search.SearchArtistsPager(text, nil), limit, artistValue)
+}
+
+func (h *scriptHost) searchPlaylists(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	text, limit, err := unpackSearch(fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Search.SearchPlaylistsPager(text, nil), limit, playlistValue)
+}
+
+func (h *scriptHost) myPlaylists(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	limit := 0
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "limit?", &limit); err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Playlists.UserPlaylistsPager(nil), limit, playlistValue)
+}
+
+func (h *scriptHost) getPlaylist(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var ref string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "playlist", &ref); err != nil {
+		return nil, err
+	}
+	playlist, err := b.findPlaylist(ctx, ref, false)
+	if err != nil || playlist == nil {
+		return starlark.None, err
+	}
+	return playlistValue(*playlist), nil
+}
+
+func (h *scriptHost) playlistTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var ref string
+	limit := 0
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "playlist", &ref, "limit?", &limit); err != nil {
+		return nil, err
+	}
+	stream, err := b.PlaylistTracks(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var values []starlark.Value
+	for stream.HasNext() && (limit <= 0 || len(values) < limit) {
+		items, err := stream.Next(ctx)
+		if err == api.ErrNoMorePages {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			values = append(values, trackValue(item.Track, item.AddedAt))
+		}
+	}
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+	return starlark.NewList(values), nil
+}
+
+// savePlaylist replaces a playlist's tracks, or appends to them, creating
+// the playlist if there is none by that name; the same as the query
+// language's save_to and append_to
+func (h *scriptHost) savePlaylist(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		name   string
+		tracks starlark.Value
+		append bool
+	)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name, "tracks", &tracks, "append?", &append); err != nil {
+		return nil, err
+	}
+	uris, err := trackURIs(tracks)
+	if err != nil {
+		return nil, err
+	}
+	if h.dryRun {
+		verb := "replace the tracks of"
+		if append {
+			verb = "add to"
+		}
+		fmt.Printf("Dry run: would %s %q with %d track%s\n", verb, name, len(uris), pluralize(len(uris)))
+		return starlark.None, nil
+	}
+	result, err := b.WritePlaylist(ctx, name, uris, !append)
+	if err != nil {
+		return nil, err
+	}
+	return starlarkstruct.FromStringDict(starlark.String("saved"), starlark.StringDict{
+		"id":      starlark.String(result.PlaylistID),
+		"name":    starlark.String(result.Playlist),
+		"tracks":  starlark.MakeInt(result.Tracks),
+		"created": starlark.Bool(result.Created),
+	}), nil
+}
+
+func (h *scriptHost) removeFromPlaylist(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		ref    string
+		tracks starlark.Value
+	)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "playlist", &ref, "tracks", &tracks); err != nil {
+		return nil, err
+	}
+	uris, err := trackURIs(tracks)
+	if err != nil {
+		return nil, err
+	}
+	playlist, err := b.findPlaylist(ctx, ref, true)
+	if err != nil {
+		return nil, err
+	}
+	if playlist == nil {
+		return nil, fmt.Errorf("no playlist named %q that you can edit", ref)
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would remove %d track%s from %q\n", len(uris), pluralize(len(uris)), playlist.Name)
+		return starlark.MakeInt(0), nil
+	}
+	// Kept before the first batch, as 'playlist remove' does, so the
+	// removal can be rolled back
+	backup, err := backupPlaylist(ctx, b.spotifyClient, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(uris); start += 100 {
+		end := min(start+100, len(uris))
+		request := &spotify.RemoveTracksRequest{}
+		for _, uri := range uris[start:end] {
+			request.Tracks = append(request.Tracks, spotify.TrackToRemove{URI: uri})
+		}
+		if _, err := b.spotifyClient.Playlists.RemoveTracksFromPlaylist(ctx, playlist.ID, request); err != nil {
+			printUndoHint(playlist.ID, backup)
+			return nil, fmt.Errorf("failed to remove tracks from playlist: %w", err)
+		}
+	}
+	printUndoHint(playlist.ID, backup)
+	return starlark.MakeInt(len(uris)), nil
+}
+
+func (h *scriptHost) savedTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	limit := 0
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "limit?", &limit); err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Library.SavedTracksPager(nil), limit, func(saved models.SavedTrack) starlark.Value {
+		return trackValue(saved.Track, saved.AddedAt)
+	})
+}
+
+func (h *scriptHost) savedAlbums(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	limit := 0
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "limit?", &limit); err != nil {
+		return nil, err
+	}
+	return pageAll(ctx, b.spotifyClient.Library.SavedAlbumsPager(nil), limit, func(saved models.SavedAlbum) starlark.Value {
+		return albumValue(saved.Album)
+	})
+}
+
+// libraryIDs reads the tracks argument of the library functions as IDs
+func libraryIDs(b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) ([]string, error) {
+	var tracks starlark.Value
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "tracks", &tracks); err != nil {
+		return nil, err
+	}
+	uris, err := trackURIs(tracks)
+	if err != nil || len(uris) == 0 {
+		return nil, err
+	}
+	return b.validator.NormalizeAndValidateIDs(uris)
+}
+
+// eachBatch calls fn with ids 50 at a time, the most the library endpoints
+// take
+func eachBatch(ids []string, fn func(batch []string) error) error {
+	for start := 0; start < len(ids); start += 50 {
+		if err := fn(ids[start:min(start+50, len(ids))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *scriptHost) saveTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ids, err := libraryIDs(b, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would save %d track%s to your library\n", len(ids), pluralize(len(ids)))
+		return starlark.MakeInt(0), nil
+	}
+	err = eachBatch(ids, func(batch []string) error { return b.spotifyClient.Library.SaveTracks(ctx, batch) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tracks: %w", err)
+	}
+	return starlark.MakeInt(len(ids)), nil
+}
+
+func (h *scriptHost) removeTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ids, err := libraryIDs(b, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would remove %d track%s from your library\n", len(ids), pluralize(len(ids)))
+		return starlark.MakeInt(0), nil
+	}
+	err = eachBatch(ids, func(batch []string) error { return b.spotifyClient.Library.RemoveTracks(ctx, batch) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove tracks: %w", err)
+	}
+	return starlark.MakeInt(len(ids)), nil
+}
+
+func (h *scriptHost) containsTracks(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	ids, err := libraryIDs(b, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	var saved []starlark.Value
+	err = eachBatch(ids, func(batch []string) error {
+		found, err := b.spotifyClient.Library.CheckSavedTracks(ctx, batch)
+		for _, ok := range found {
+			saved = append(saved, starlark.Bool(ok))
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check saved tracks: %w", err)
+	}
+	return starlark.NewList(saved), nil
+}
+
+func (h *scriptHost) current(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	state, err := b.spotifyClient.Player.GetPlaybackState(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playback state: %w", err)
+	}
+	if state == nil {
+		return starlark.None, nil
+	}
+
+	var item starlark.Value = starlark.None
+	if playing, err := decodePlayingItem(state.Item); err == nil {
+		if playing.IsTrack() {
+			item = trackValue(*playing.Track, "")
+		} else if playing.IsEpisode() {
+			item = starlarkstruct.FromStringDict(starlark.String("episode"), starlark.StringDict{
+				"id":          starlark.String(playing.ID()),
+				"uri":         starlark.String(playing.URI()),
+				"name":        starlark.String(playing.Name()),
+				"show":        starlark.String(playing.AlbumName()),
+				"duration_ms": starlark.MakeInt(playing.DurationMs()),
+			})
+		}
+	}
+	var playingContext starlark.Value = starlark.None
+	if state.Context != nil {
+		playingContext = starlark.String(state.Context.URI)
+	}
+	return starlarkstruct.FromStringDict(starlark.String("playback"), starlark.StringDict{
+		"playing":     starlark.Bool(state.IsPlaying),
+		"item":        item,
+		"context":     playingContext,
+		"progress_ms": starlark.MakeInt(state.ProgressMs),
+		"shuffle":     starlark.Bool(state.ShuffleState),
+		"repeat":      starlark.String(state.RepeatState),
+		"device":      deviceValue(state.Device),
+	}), nil
+}
+
+func (h *scriptHost) devices(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	devices, err := b.spotifyClient.Player.GetDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	values := make([]starlark.Value, len(devices.Devices))
+	for i, device := range devices.Devices {
+		values[i] = deviceValue(device)
+	}
+	return starlark.NewList(values), nil
+}
+
+// deviceID resolves a device given by name or ID, or the active one for ""
+func deviceID(ctx context.Context, spotifyClient *client.SpotifyClient, ref string) (string, error) {
+	if ref == "" {
+		return "", nil
+	}
+	devices, err := spotifyClient.Player.GetDevices(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get devices: %w", err)
+	}
+	device, err := resolveDevice(devices.Devices, ref)
+	if err != nil {
+		return "", err
+	}
+	return device.ID, nil
+}
+
+func (h *scriptHost) play(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		tracks                starlark.Value = starlark.None
+		contextURI, deviceRef string
+	)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "tracks?", &tracks, "context?", &contextURI, "device?", &deviceRef); err != nil {
+		return nil, err
+	}
+	options := &spotify.PlayOptions{ContextURI: contextURI}
+	if tracks != starlark.None {
+		uris, err := trackURIs(tracks)
+		if err != nil {
+			return nil, err
+		}
+		options.URIs = uris
+	}
+	if h.dryRun {
+		fmt.Println("Dry run: would start playback")
+		return starlark.None, nil
+	}
+	id, err := deviceID(ctx, b.spotifyClient, deviceRef)
+	if err != nil {
+		return nil, err
+	}
+	options.DeviceID = id
+	if err := b.spotifyClient.Player.Play(ctx, options); err != nil {
+		return nil, playerCommandError(b.spotifyClient, "start playback", err)
+	}
+	return starlark.None, nil
+}
+
+// playerCommand makes a player function that takes only a device
+func (h *scriptHost) playerCommand(action string, command func(*spotify.PlayerService, context.Context, string) error) scriptFunc {
+	return func(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var deviceRef string
+		if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "device?", &deviceRef); err != nil {
+			return nil, err
+		}
+		if h.dryRun {
+			fmt.Printf("Dry run: would %s\n", action)
+			return starlark.None, nil
+		}
+		id, err := deviceID(ctx, b.spotifyClient, deviceRef)
+		if err != nil {
+			return nil, err
+		}
+		if err := command(b.spotifyClient.Player, ctx, id); err != nil {
+			return nil, playerCommandError(b.spotifyClient, action, err)
+		}
+		return starlark.None, nil
+	}
+}
+
+func (h *scriptHost) queue(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var tracks starlark.Value
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "tracks", &tracks); err != nil {
+		return nil, err
+	}
+	uris, err := trackURIs(tracks)
+	if err != nil {
+		return nil, err
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would queue %d track%s\n", len(uris), pluralize(len(uris)))
+		return starlark.MakeInt(0), nil
+	}
+	queued, err := b.Enqueue(ctx, uris)
+	if err != nil {
+		if queued > 0 {
+			utils.PrintWarning("Queued %d of %d tracks before the error", queued, len(uris))
+		}
+		return nil, err
+	}
+	return starlark.MakeInt(queued), nil
+}
+
+func (h *scriptHost) volume(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		percent   int
+		deviceRef string
+	)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "percent", &percent, "device?", &deviceRef); err != nil {
+		return nil, err
+	}
+	if percent < 0 || percent > 100 {
+		return nil, fmt.Errorf("volume must be between 0 and 100, got %d", percent)
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would set the volume to %d%%\n", percent)
+		return starlark.None, nil
+	}
+	id, err := deviceID(ctx, b.spotifyClient, deviceRef)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.spotifyClient.Player.SetVolume(ctx, percent, id); err != nil {
+		return nil, playerCommandError(b.spotifyClient, "set volume", err)
+	}
+	return starlark.None, nil
+}
+
+func (h *scriptHost) shuffle(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var (
+		on        bool
+		deviceRef string
+	)
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "on", &on, "device?", &deviceRef); err != nil {
+		return nil, err
+	}
+	if h.dryRun {
+		fmt.Printf("Dry run: would turn shuffle %s\n", map[bool]string{true: "on", false: "off"}[on])
+		return starlark.None, nil
+	}
+	id, err := deviceID(ctx, b.spotifyClient, deviceRef)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.spotifyClient.Player.SetShuffle(ctx, on, id); err != nil {
+		return nil, playerCommandError(b.spotifyClient, "set shuffle", err)
+	}
+	return starlark.None, nil
+}
+
+// runQuery runs a query and returns the tracks it ends with. Its save_to,
+// append_to, play and queue stages honour --dry-run like the rest.
+func (h *scriptHost) runQuery(ctx context.Context, b *queryBackend, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var expression string
+	if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "expression", &expression); err != nil {
+		return nil, err
+	}
+	q, err := query.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Run(ctx, b, query.Options{DryRun: h.dryRun})
+	if err != nil {
+		return nil, err
+	}
+	values := make([]starlark.Value, len(result.Items))
+	for i, item := range result.Items {
+		values[i] = trackValue(item.Track, item.AddedAt)
+	}
+	return starlark.NewList(values), nil
+}
+
+// trackURIs reads tracks given to a script function: a URI, bare ID or
+// track, or a list of them
+func trackURIs(v starlark.Value) ([]string, error) {
+	elems := []starlark.Value{v}
+	if list, ok := v.(*starlark.List); ok {
+		elems = make([]starlark.Value, list.Len())
+		for i := range elems {
+			elems[i] = list.Index(i)
+		}
+	} else if tuple, ok := v.(starlark.Tuple); ok {
+		elems = tuple
+	}
+
+	uris := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		if s, ok := elem.(*starlarkstruct.Struct); ok {
+			if uri, err := s.Attr("uri"); err == nil {
+				elem = uri
+			}
+		}
+		uri, ok := starlark.AsString(elem)
+		if !ok {
+			return nil, fmt.Errorf("tracks must be URIs, IDs or tracks, got %s", elem.Type())
+		}
+		if !strings.Contains(uri, ":") {
+			uri = "spotify:track:" + uri
+		}
+		uris = append(uris, uri)
+	}
+	return uris, nil
+}
+
+func stringList(strs []string) *starlark.List {
+	values := make([]starlark.Value, len(strs))
+	for i, s := range strs {
+		values[i] = starlark.String(s)
+	}
+	return starlark.NewList(values)
+}
+
+func artistNames(artists []models.SimpleArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
+
+// trackValue is a track as scripts see it; addedAt is when it was saved or
+// added to a playlist, if known
+func trackValue(t models.Track, addedAt string) starlark.Value {
+	names := artistNames(t.Artists)
+	var album, albumID string
+	if t.Album != nil {
+		album, albumID = t.Album.Name, t.Album.ID
+	}
+	var added starlark.Value = starlark.None
+	if addedAt != "" {
+		added = starlark.String(addedAt)
+	}
+	return starlarkstruct.FromStringDict(starlark.String("track"), starlark.StringDict{
+		"id":          starlark.String(t.ID),
+		"uri":         starlark.String(t.URI),
+		"name":        starlark.String(t.Name),
+		"artists":     stringList(names),
+		"artist":      starlark.String(strings.Join(names, ", ")),
+		"album":       starlark.String(album),
+		"album_id":    starlark.String(albumID),
+		"duration_ms": starlark.MakeInt(t.DurationMs),
+		"popularity":  starlark.MakeInt(t.Popularity),
+		"explicit":    starlark.Bool(t.Explicit),
+		"added_at":    added,
+	})
+}
+
+func albumValue(a models.Album) starlark.Value {
+	names := artistNames(a.Artists)
+	return starlarkstruct.FromStringDict(starlark.String("album"), starlark.StringDict{
+		"id":           starlark.String(a.ID),
+		"uri":          starlark.String(a.URI),
+		"name":         starlark.String(a.Name),
+		"artists":      stringList(names),
+		"artist":       starlark.String(strings.Join(names, ", ")),
+		"album_type":   starlark.String(a.AlbumType),
+		"release_date": starlark.String(a.DateStr),
+		"total_tracks": starlark.MakeInt(a.TotalTracks),
+	})
+}
+
+func artistValue(a models.Artist) starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("artist"), starlark.StringDict{
+		"id":         starlark.String(a.ID),
+		"uri":        starlark.String(a.URI),
+		"name":       starlark.String(a.Name),
+		"genres":     stringList(a.Genres),
+		"popularity": starlark.MakeInt(a.Popularity),
+		"followers":  starlark.MakeInt(a.Followers.Total),
+	})
+}
+
+func playlistValue(p models.Playlist) starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("playlist"), starlark.StringDict{
+		"id":            starlark.String(p.ID),
+		"uri":           starlark.String(p.URI),
+		"name":          starlark.String(p.Name),
+		"description":   starlark.String(p.Description),
+		"owner":         starlark.String(p.Owner.ID),
+		"public":        starlark.Bool(p.Public),
+		"collaborative": starlark.Bool(p.Collaborative),
+		"tracks":        starlark.MakeInt(p.Tracks.Total),
+	})
+}
+
+func deviceValue(d models.Device) starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("device"), starlark.StringDict{
+		"id":     starlark.String(d.ID),
+		"name":   starlark.String(d.Name),
+		"type":   starlark.String(d.Type),
+		"active": starlark.Bool(d.IsActive),
+		"volume": starlark.MakeInt(d.VolumePercent),
+	})
+}
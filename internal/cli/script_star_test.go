@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"go.starlark.net/starlark"
+)
+
+func TestExecStarScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		output string
+		err    string
+	}{
+		{"print", `print("hello", args[0])`, "hello mix", ""},
+		{"top level loops", "total = 0\nfor i in range(3):\n    total += i\nwhile total < 10:\n    total *= 2\nprint(total)", "12", ""},
+		{"sets", `print(len(set([1, 1, 2])))`, "2", ""},
+		{"comprehension", `print([t for t in args if t != "mix"])`, "[]", ""},
+		{"runaway loop", "while True:\n    pass", "", "too many steps"},
+		{"recursion", "def f(n):\n    return f(n - 1)\nf(3)", "", "called recursively"},
+		{"load", `load("other.star", "x")`, "", "load not implemented"},
+		{"no files", `open("/etc/passwd")`, "", "undefined: open"},
+		{"error position", "x = 1\ny = x + \"a\"", "", "test.star:2:7"},
+		{"syntax error", "def f(:\n    pass", "", "test.star:1:8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output []string
+			predeclared := starlark.StringDict{"args": starlark.NewList([]starlark.Value{starlark.String("mix")})}
+			_, err := execStarScript(context.Background(), "test.star", tt.src, predeclared, func(msg string) {
+				output = append(output, msg)
+			})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("Expected an error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := strings.Join(output, "\n"); got != tt.output {
+				t.Errorf("Expected output %q, got %q", tt.output, got)
+			}
+		})
+	}
+}
+
+func TestExecStarScript_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := execStarScript(ctx, "test.star", "while True:\n    pass", nil, func(string) {})
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("Expected a cancelled script to stop, got %v", err)
+	}
+}
+
+func TestTrackURIs(t *testing.T) {
+	track := trackValue(models.Track{ID: "abc", URI: "spotify:track:abc"}, "")
+	tests := []struct {
+		value    starlark.Value
+		expected []string
+	}{
+		{starlark.String("4uLU6hMCjMI75M1A2tKUQC"), []string{"spotify:track:4uLU6hMCjMI75M1A2tKUQC"}},
+		{starlark.String("spotify:episode:xyz"), []string{"spotify:episode:xyz"}},
+		{track, []string{"spotify:track:abc"}},
+		{starlark.NewList([]starlark.Value{track, starlark.String("def")}), []string{"spotify:track:abc", "spotify:track:def"}},
+		{starlark.Tuple{starlark.String("a"), starlark.String("b")}, []string{"spotify:track:a", "spotify:track:b"}},
+	}
+	for _, tt := range tests {
+		got, err := trackURIs(tt.value)
+		if err != nil || strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("trackURIs(%s): expected %v, got %v, %v", tt.value, tt.expected, got, err)
+		}
+	}
+
+	if _, err := trackURIs(starlark.MakeInt(1)); err == nil {
+		t.Error("Expected an error for a number")
+	}
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+)
+
+func TestScriptFlags(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SPOTIFY_CLI_PROFILE", "")
+	if err := config.CreateProfile(dir, "work", &config.Config{}); err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+
+	tests := []struct {
+		args    []string
+		profile string
+	}{
+		{[]string{"--config-dir", dir, "mix", "--dry-run"}, config.DefaultProfile},
+		{[]string{"-v", "--config-dir=" + dir, "--profile", "work", "mix", "-f", "json"}, "work"},
+		{[]string{"mix", "--config-dir", dir, "--profile", "missing"}, config.DefaultProfile},
+	}
+	for _, tt := range tests {
+		gotDir, gotProfile := scriptFlags(tt.args)
+		if gotDir != dir || gotProfile != tt.profile {
+			t.Errorf("%v: expected %s and %s, got %s and %s", tt.args, dir, tt.profile, gotDir, gotProfile)
+		}
+	}
+
+	dirs := scriptDirsFor(dir, "work")
+	if len(dirs) < 2 || dirs[0] != filepath.Join(dir, "profiles", "work", "scripts") || dirs[1] != filepath.Join(dir, "scripts") {
+		t.Errorf("Expected the profile's scripts before the shared ones, got %v", dirs)
+	}
+}
+
+func TestLoadScripts(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(first, "mix.star"):    "#!/usr/bin/env spotify-cli\n# Make a mix\nprint(args)",
+		filepath.Join(first, "Bad.star"):    "print(1)",
+		filepath.Join(second, "mix.query"):  "saved().limit(5)",
+		filepath.Join(second, "top.query"):  "# Top five\ntop(short).limit($1)",
+		filepath.Join(second, "note.txt"):   "not a script",
+		filepath.Join(second, "empty.star"): "",
+	}
+	for path, src := range files {
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scripts, errs := loadScripts([]string{first, second})
+	if len(errs) != 1 {
+		t.Errorf("Expected an error for Bad.star only, got %v", errs)
+	}
+	if len(scripts) != 3 {
+		t.Fatalf("Expected empty, mix and top, got %d scripts", len(scripts))
+	}
+
+	mix, top := scripts[1], scripts[2]
+	if mix.Name != "mix" || mix.query != nil || mix.Description != "Make a mix" {
+		t.Errorf("Expected the Starlark mix to hide the query one, got %+v", mix)
+	}
+	if top.query == nil || top.query.Args != 1 || top.Description != "Top five" {
+		t.Errorf("Expected top to be a query script with one argument, got %+v", top)
+	}
+}
@@ -290,7 +290,7 @@ func outputTracksTable(tracks *models.Paging[models.Track], pagination *api.Pagi
 				fmt.Printf("   from %s\n", track.Album.Name)
 			}
 			if track.DurationMs > 0 {
-				duration := formatTrackDuration(track.DurationMs)
+				duration := utils.FormatDuration(track.DurationMs)
 				fmt.Printf("   ⏱ %s\n", duration)
 			}
 			fmt.Println()
@@ -317,7 +317,7 @@ func outputTracksTable(tracks *models.Paging[models.Track], pagination *api.Pagi
 
 			duration := ""
 			if track.DurationMs > 0 {
-				duration = formatTrackDuration(track.DurationMs)
+				duration = utils.FormatDuration(track.DurationMs)
 			}
 
 			fmt.Printf("%-22s %-40s %-25s %-25s %s\n",
@@ -564,13 +564,6 @@ func outputPlaylistsTable(playlists *models.Paging[models.Playlist], pagination
 
 // Helper functions
 
-func formatTrackDuration(durationMs int) string {
-	seconds := durationMs / 1000
-	minutes := seconds / 60
-	remainingSeconds := seconds % 60
-	return fmt.Sprintf("%d:%02d", minutes, remainingSeconds)
-}
-
 func formatNumber(n int) string {
 	if n >= 1000000 {
 		return fmt.Sprintf("%.1fM", float64(n)/1000000)
@@ -0,0 +1,331 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/apikey"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var serveListen string
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a local HTTP control server",
+	Long: `Run an HTTP server that lets other programs control playback through this
+CLI's Spotify login, for example home-automation hubs or stream decks.
+
+Every request needs an API key created with 'spotify-cli apikey create',
+passed as "Authorization: Bearer <key>" or an X-API-Key header. A key can only
+call the endpoints its operations allow.
+
+Endpoints (operation in brackets):
+  GET  /v1/status                 [status]
+  POST /v1/play                   [play]      {"context_uri": "...", "uris": [...]}
+  POST /v1/pause                  [pause]
+  POST /v1/next                   [next]
+  POST /v1/previous               [previous]
+  POST /v1/seek                   [seek]      {"position_ms": 30000, "clamp": false}
+  POST /v1/volume                 [volume]    {"volume_percent": 40}
+  POST /v1/shuffle                [shuffle]   {"state": true}
+  POST /v1/repeat                 [repeat]    {"state": "context"}
+  POST /v1/queue                  [queue:add] {"uri": "spotify:track:..."}
+  POST /v1/transfer               [transfer]  {"device_id": "...", "play": true}
+  GET  /v1/playlists              [playlist:read]
+  POST /v1/playlists/{id}/tracks  [playlist:modify] {"uris": [...]}
+
+The server listens on localhost by default. Only bind to other interfaces on
+networks you trust.`,
+	Example: `  spotify-cli apikey create home-assistant --role player
+  spotify-cli serve --listen 127.0.0.1:8790
+
+  curl -X POST -H "Authorization: Bearer $KEY" http://127.0.0.1:8790/v1/next`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServe()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8790", "Address to listen on")
+}
+
+// serveRoute is one control server endpoint
+type serveRoute struct {
+	method  string
+	path    string
+	op      apikey.Operation
+	handler func(ctx context.Context, r *http.Request) (interface{}, error)
+}
+
+func runServe() error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() || config.Get().RefreshToken == "" {
+		return fmt.Errorf("user authentication required. Run 'spotify-cli auth login' before starting the control server")
+	}
+
+	keys, err := openAPIKeys()
+	if err != nil {
+		return err
+	}
+
+	existing, err := keys.List()
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		utils.PrintWarning("No API keys exist yet; every request will be rejected. Create one with 'spotify-cli apikey create'")
+	}
+
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", serveListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveListen, err)
+	}
+
+	server := &http.Server{
+		Handler:           newServeHandler(keys, serveRoutes(spotifyClient)),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	utils.PrintSuccess("Control server listening on http://%s", listener.Addr())
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}
+
+	return nil
+}
+
+// newServeHandler routes requests to their handlers, checking the API key
+// against each route's operation first
+func newServeHandler(keys *apikey.Store, routes []serveRoute) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeServeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	mux.HandleFunc("/v1/", func(w http.ResponseWriter, r *http.Request) {
+		for _, route := range routes {
+			if !matchServePath(route.path, r.URL.Path) {
+				continue
+			}
+			if r.Method != route.method {
+				writeServeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+				return
+			}
+
+			keys.Require(route.op, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				result, err := route.handler(r.Context(), r)
+				if err != nil {
+					status := http.StatusBadGateway
+					var badRequest *serveBadRequest
+					if errors.As(err, &badRequest) {
+						status = http.StatusBadRequest
+					}
+					writeServeJSON(w, status, map[string]string{"error": err.Error()})
+					return
+				}
+				if result == nil {
+					result = map[string]bool{"ok": true}
+				}
+				writeServeJSON(w, http.StatusOK, result)
+			})).ServeHTTP(w, r)
+			return
+		}
+
+		writeServeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	})
+
+	return mux
+}
+
+func serveRoutes(spotifyClient *client.SpotifyClient) []serveRoute {
+	player := spotifyClient.Player
+
+	return []serveRoute{
+		{"GET", "/v1/status", apikey.OpStatus, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			return player.GetPlaybackState(ctx, "")
+		}},
+		{"POST", "/v1/play", apikey.OpPlay, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				DeviceID   string   `json:"device_id"`
+				ContextURI string   `json:"context_uri"`
+				URIs       []string `json:"uris"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			return nil, player.Play(ctx, &spotify.PlayOptions{DeviceID: body.DeviceID, ContextURI: body.ContextURI, URIs: body.URIs})
+		}},
+		{"POST", "/v1/pause", apikey.OpPause, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			return nil, player.Pause(ctx, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/next", apikey.OpNext, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			return nil, player.Next(ctx, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/previous", apikey.OpPrevious, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			return nil, player.Previous(ctx, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/seek", apikey.OpSeek, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				PositionMs int  `json:"position_ms"`
+				Clamp      bool `json:"clamp"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			positionMs, err := player.SeekInItem(ctx, body.PositionMs, &spotify.SeekOptions{
+				DeviceID: r.URL.Query().Get("device_id"),
+				Clamp:    body.Clamp,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]int{"position_ms": positionMs}, nil
+		}},
+		{"POST", "/v1/volume", apikey.OpVolume, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				VolumePercent *int `json:"volume_percent"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			if body.VolumePercent == nil {
+				return nil, &serveBadRequest{"volume_percent is required"}
+			}
+			return nil, player.SetVolume(ctx, *body.VolumePercent, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/shuffle", apikey.OpShuffle, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				State bool `json:"state"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			return nil, player.SetShuffle(ctx, body.State, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/repeat", apikey.OpRepeat, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				State string `json:"state"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			return nil, player.SetRepeat(ctx, body.State, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/queue", apikey.OpQueueAdd, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				URI string `json:"uri"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			return nil, player.AddToQueue(ctx, body.URI, r.URL.Query().Get("device_id"))
+		}},
+		{"POST", "/v1/transfer", apikey.OpTransfer, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				DeviceID string `json:"device_id"`
+				Play     *bool  `json:"play"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			if body.DeviceID == "" {
+				return nil, &serveBadRequest{"device_id is required"}
+			}
+			return nil, player.TransferPlayback(ctx, &spotify.TransferPlaybackRequest{DeviceIDs: []string{body.DeviceID}, Play: body.Play})
+		}},
+		{"GET", "/v1/playlists", apikey.OpPlaylistRead, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			return spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+		}},
+		{"POST", "/v1/playlists/*/tracks", apikey.OpPlaylistModify, func(ctx context.Context, r *http.Request) (interface{}, error) {
+			var body struct {
+				URIs []string `json:"uris"`
+			}
+			if err := decodeServeBody(r, &body); err != nil {
+				return nil, err
+			}
+			playlistID := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/playlists/"), "/")[0]
+			return spotifyClient.Playlists.AddTracksToPlaylist(ctx, playlistID, &spotify.AddTracksRequest{URIs: body.URIs})
+		}},
+	}
+}
+
+// serveBadRequest is returned by handlers for malformed requests
+type serveBadRequest struct {
+	message string
+}
+
+func (e *serveBadRequest) Error() string {
+	return e.message
+}
+
+// matchServePath matches a request path against a route pattern where "*"
+// stands for exactly one path segment
+func matchServePath(pattern, path string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i, part := range patternParts {
+		if part != "*" && part != pathParts[i] {
+			return false
+		}
+		if part == "*" && pathParts[i] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeServeBody(r *http.Request, v interface{}) error {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20)).Decode(v); err != nil {
+		return &serveBadRequest{fmt.Sprintf("invalid request body: %v", err)}
+	}
+	return nil
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,25 @@
+package cli
+
+import "testing"
+
+func TestMatchServePath(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/v1/next", "/v1/next", true},
+		{"/v1/next", "/v1/next/", true},
+		{"/v1/next", "/v1/nextx", false},
+		{"/v1/playlists/*/tracks", "/v1/playlists/abc/tracks", true},
+		{"/v1/playlists/*/tracks", "/v1/playlists//tracks", false},
+		{"/v1/playlists/*/tracks", "/v1/playlists/abc/tracks/extra", false},
+		{"/v1/playlists", "/v1/playlists/abc/tracks", false},
+	}
+
+	for _, tt := range tests {
+		if result := matchServePath(tt.pattern, tt.path); result != tt.expected {
+			t.Errorf("matchServePath(%q, %q) = %v, expected %v", tt.pattern, tt.path, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	showLimit  int
+	showOffset int
+	showMarket string
+	showFormat string
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Browse podcast shows",
+	Long: `Look up podcast shows and their episodes, and manage the shows saved in your
+library.
+
+Saving, removing and listing saved shows requires user authentication.`,
+	Example: `  # Show details and latest episodes
+  spotify-cli show get 38bS44xjbVVZ3No3ByF1dJ
+  spotify-cli show episodes 38bS44xjbVVZ3No3ByF1dJ --limit 10
+
+  # Manage saved shows
+  spotify-cli show saved
+  spotify-cli show save 38bS44xjbVVZ3No3ByF1dJ
+  spotify-cli show remove 38bS44xjbVVZ3No3ByF1dJ`,
+}
+
+var showGetCmd = &cobra.Command{
+	Use:   "get <show-id>",
+	Short: "Get show details",
+	Args:  cobra.ExactArgs(1),
+	Example: `  spotify-cli show get 38bS44xjbVVZ3No3ByF1dJ
+  spotify-cli show get 38bS44xjbVVZ3No3ByF1dJ --market GB`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowGet(args[0])
+	},
+}
+
+var showEpisodesCmd = &cobra.Command{
+	Use:   "episodes <show-id>",
+	Short: "List a show's episodes",
+	Long:  `List the episodes of a show, newest first.`,
+	Args:  cobra.ExactArgs(1),
+	Example: `  spotify-cli show episodes 38bS44xjbVVZ3No3ByF1dJ
+  spotify-cli show episodes 38bS44xjbVVZ3No3ByF1dJ --limit 50 --offset 50`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowEpisodes(args[0])
+	},
+}
+
+var showSavedCmd = &cobra.Command{
+	Use:     "saved",
+	Short:   "List saved shows",
+	Example: `  spotify-cli show saved --limit 50`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowSaved()
+	},
+}
+
+var showSaveCmd = &cobra.Command{
+	Use:     "save <show-id> [show-id...]",
+	Short:   "Save shows to your library",
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  spotify-cli show save 38bS44xjbVVZ3No3ByF1dJ`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowSave(args)
+	},
+}
+
+var showRemoveCmd = &cobra.Command{
+	Use:     "remove <show-id> [show-id...]",
+	Short:   "Remove shows from your library",
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  spotify-cli show remove 38bS44xjbVVZ3No3ByF1dJ`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowRemove(args)
+	},
+}
+
+var showCheckCmd = &cobra.Command{
+	Use:     "check <show-id> [show-id...]",
+	Short:   "Check if shows are saved",
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  spotify-cli show check 38bS44xjbVVZ3No3ByF1dJ 5CfCWKI5pZ28U0uOzXkDHe`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runShowCheck(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.AddCommand(showGetCmd)
+	showCmd.AddCommand(showEpisodesCmd)
+	showCmd.AddCommand(showSavedCmd)
+	showCmd.AddCommand(showSaveCmd)
+	showCmd.AddCommand(showRemoveCmd)
+	showCmd.AddCommand(showCheckCmd)
+
+	for _, cmd := range []*cobra.Command{showGetCmd, showEpisodesCmd, showSavedCmd} {
+		cmd.Flags().StringVarP(&showFormat, "format", "f", "table", "Output format (table, list, json, yaml)")
+	}
+	for _, cmd := range []*cobra.Command{showEpisodesCmd, showSavedCmd} {
+		cmd.Flags().IntVarP(&showLimit, "limit", "l", 20, "Number of results to return (1-50)")
+		cmd.Flags().IntVarP(&showOffset, "offset", "", 0, "Offset for pagination")
+	}
+	for _, cmd := range []*cobra.Command{showGetCmd, showEpisodesCmd} {
+		cmd.Flags().StringVarP(&showMarket, "market", "m", "", "Market/country code (e.g., US, GB)")
+	}
+}
+
+// showOutputFormat resolves the output format: flag > global config > default
+func showOutputFormat() string {
+	cfg := config.Get()
+	if showFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		return cfg.DefaultOutput
+	}
+	return showFormat
+}
+
+// newUserClient creates a client and checks it is authorised for user data
+func newUserClient(what string) (*client.SpotifyClient, error) {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return nil, fmt.Errorf("authentication required. Run 'spotify-cli auth login' for user account access")
+	}
+
+	if config.Get().RefreshToken == "" {
+		return nil, fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to access %s", what)
+	}
+
+	return spotifyClient, nil
+}
+
+func runShowGet(showID string) error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' or 'spotify-cli auth client-credentials' first")
+	}
+
+	show, err := spotifyClient.Shows.GetShow(GetCommandContext(), showID, showMarket)
+	if err != nil {
+		return fmt.Errorf("failed to get show: %w", err)
+	}
+
+	if format := showOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(show)
+	}
+
+	fmt.Printf("%s\n", show.Name)
+	fmt.Printf("   ID: %s\n", show.ID)
+	if show.Publisher != "" {
+		fmt.Printf("   by %s\n", show.Publisher)
+	}
+	fmt.Printf("   %d episode%s\n", show.TotalEpisodes, pluralize(show.TotalEpisodes))
+	if show.Explicit {
+		fmt.Println("   explicit")
+	}
+	if show.Description != "" {
+		fmt.Printf("\n%s\n", show.Description)
+	}
+
+	if show.Episodes != nil && len(show.Episodes.Items) > 0 {
+		fmt.Println()
+		fmt.Println("Latest episodes:")
+		printEpisodesTable(show.Episodes.Items)
+	}
+
+	return nil
+}
+
+func runShowEpisodes(showID string) error {
+	spotifyClient, err := client.NewSpotifyClient()
+	if err != nil {
+		return fmt.Errorf("failed to create Spotify client: %w", err)
+	}
+
+	if !spotifyClient.IsAuthenticated() {
+		return fmt.Errorf("authentication required. Run 'spotify-cli auth login' or 'spotify-cli auth client-credentials' first")
+	}
+
+	options := &api.PaginationOptions{Limit: showLimit, Offset: showOffset}
+	episodes, pagination, err := spotifyClient.Shows.GetShowEpisodes(GetCommandContext(), showID, options, showMarket)
+	if err != nil {
+		return fmt.Errorf("failed to get show episodes: %w", err)
+	}
+
+	if format := showOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"results":    episodes,
+			"pagination": pagination,
+		})
+	}
+
+	if len(episodes.Items) == 0 {
+		fmt.Println("No episodes found.")
+		return nil
+	}
+
+	fmt.Printf("Episodes - %d total", episodes.Total)
+	if pagination != nil {
+		fmt.Printf(" (showing %d-%d)", pagination.Offset+1, pagination.Offset+len(episodes.Items))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if showFormat == "list" {
+		for i, episode := range episodes.Items {
+			fmt.Printf("%d. %s\n", i+1, episode.Name)
+			fmt.Printf("   ID: %s\n", episode.ID)
+			fmt.Printf("   released %s, %s\n", episode.ReleaseDate, utils.FormatDuration(episode.DurationMs))
+			if progress := episodeProgress(episode); progress != "" {
+				fmt.Printf("   %s\n", progress)
+			}
+			fmt.Println()
+		}
+	} else {
+		printEpisodesTable(episodes.Items)
+	}
+
+	printNextOffset(pagination)
+	return nil
+}
+
+func runShowSaved() error {
+	spotifyClient, err := newUserClient("your saved shows")
+	if err != nil {
+		return err
+	}
+
+	options := &api.PaginationOptions{Limit: showLimit, Offset: showOffset}
+	shows, pagination, err := spotifyClient.Shows.GetSavedShows(GetCommandContext(), options)
+	if err != nil {
+		return fmt.Errorf("failed to get saved shows: %w", err)
+	}
+
+	if format := showOutputFormat(); format == "json" || format == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"results":    shows,
+			"pagination": pagination,
+		})
+	}
+
+	if len(shows.Items) == 0 {
+		fmt.Println("No saved shows found.")
+		return nil
+	}
+
+	fmt.Printf("Your Saved Shows - %d total", shows.Total)
+	if pagination != nil {
+		fmt.Printf(" (showing %d-%d)", pagination.Offset+1, pagination.Offset+len(shows.Items))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	if showFormat == "list" {
+		for i, saved := range shows.Items {
+			fmt.Printf("%d. %s\n", i+1, saved.Show.Name)
+			fmt.Printf("   ID: %s\n", saved.Show.ID)
+			if saved.Show.Publisher != "" {
+				fmt.Printf("   by %s\n", saved.Show.Publisher)
+			}
+			if saved.AddedAt != "" {
+				fmt.Printf("   📅 Added %s\n", formatTimestamp(saved.AddedAt))
+			}
+			fmt.Println()
+		}
+	} else {
+		fmt.Printf("%-22s %-35s %-25s %-8s %s\n", "ID", "SHOW", "PUBLISHER", "EPISODES", "ADDED")
+		fmt.Println(strings.Repeat("-", 110))
+
+		for _, saved := range shows.Items {
+			fmt.Printf("%-22s %-35s %-25s %-8d %s\n",
+				saved.Show.ID,
+				truncateString(saved.Show.Name, 33),
+				truncateString(saved.Show.Publisher, 23),
+				saved.Show.TotalEpisodes,
+				formatTimestamp(saved.AddedAt))
+		}
+	}
+
+	printNextOffset(pagination)
+	return nil
+}
+
+func runShowSave(ids []string) error {
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+
+	if err := spotifyClient.Shows.SaveShows(GetCommandContext(), ids); err != nil {
+		return fmt.Errorf("failed to save shows: %w", err)
+	}
+
+	utils.PrintSuccess("Successfully saved %d show%s to library", len(ids), pluralize(len(ids)))
+	return nil
+}
+
+func runShowRemove(ids []string) error {
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+
+	if err := spotifyClient.Shows.RemoveShows(GetCommandContext(), ids); err != nil {
+		return fmt.Errorf("failed to remove shows: %w", err)
+	}
+
+	utils.PrintSuccess("Successfully removed %d show%s from library", len(ids), pluralize(len(ids)))
+	return nil
+}
+
+func runShowCheck(ids []string) error {
+	spotifyClient, err := newUserClient("your library")
+	if err != nil {
+		return err
+	}
+
+	saved, err := spotifyClient.Shows.CheckSavedShows(GetCommandContext(), ids)
+	if err != nil {
+		return fmt.Errorf("failed to check saved shows: %w", err)
+	}
+
+	return outputLibraryCheckResults("show", ids, saved)
+}
+
+func printEpisodesTable(episodes []models.Episode) {
+	fmt.Printf("%-22s %-45s %-12s %-8s %s\n", "ID", "EPISODE", "RELEASED", "LENGTH", "PROGRESS")
+	fmt.Println(strings.Repeat("-", 110))
+
+	for _, episode := range episodes {
+		fmt.Printf("%-22s %-45s %-12s %-8s %s\n",
+			episode.ID,
+			truncateString(episode.Name, 43),
+			episode.ReleaseDate,
+			utils.FormatDuration(episode.DurationMs),
+			episodeProgress(episode))
+	}
+}
+
+// episodeProgress describes how far the user got through an episode
+func episodeProgress(episode models.Episode) string {
+	if episode.ResumePoint == nil {
+		return ""
+	}
+	if episode.ResumePoint.FullyPlayed {
+		return "played"
+	}
+	if episode.ResumePoint.ResumePositionMs > 0 {
+		return "at " + utils.FormatDuration(episode.ResumePoint.ResumePositionMs)
+	}
+	return ""
+}
+
+func printNextOffset(pagination *api.PaginationInfo) {
+	if pagination != nil && pagination.HasNext() {
+		fmt.Println()
+		if nextOffset := pagination.GetNextOffset(); nextOffset > 0 {
+			fmt.Printf("Use --offset %d for next page\n", nextOffset)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	stateShowStores     bool
+	stateBackupPath     string
+	stateExportNoSecret bool
+	stateExportExclude  []string
+	stateImportConfig   bool
+	stateImportState    bool
+	stateImportCache    bool
+	stateImportForce    bool
+	stateImportList     bool
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and back up local state",
+	Long: `Inspect and back up the local databases kept by spotify-cli, such as listening
+history and operation journals.
+
+State is stored per profile. Every store is locked while it is being written,
+so a daemon and an interactive command can run at the same time without
+corrupting each other's data.`,
+	Example: `  # Show where state is stored
+  spotify-cli state path
+
+  # Back up all state to the default backups directory
+  spotify-cli state backup
+
+  # Move everything to another machine
+  spotify-cli state export spotify.tar.gz
+  spotify-cli state import spotify.tar.gz`,
+}
+
+var statePathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show the state directory",
+	Long:  `Print the state directory of the current profile. With --stores, list the stores it contains.`,
+	Example: `  spotify-cli state path
+  spotify-cli state path --stores
+  cd "$(spotify-cli state path)"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStatePath()
+	},
+}
+
+var stateBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up local state to an archive",
+	Long: `Write a .tar.gz archive of every state store. Each store is locked while it is
+copied, so backups are consistent even while a daemon is running.
+
+By default the archive is written to the backups directory of the current
+profile.`,
+	Example: `  spotify-cli state backup
+  spotify-cli state backup --output ~/spotify-state.tar.gz`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateBackup()
+	},
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export <archive>",
+	Short: "Export config, state and cache to an archive",
+	Long: `Bundle the current profile's config, every state store (history, journals)
+and the response cache into one .tar.gz archive for moving to another machine.
+
+The config includes the client secret and tokens unless --no-secrets is given.
+Keep archives with secrets somewhere private.`,
+	Example: `  spotify-cli state export spotify.tar.gz
+  spotify-cli state export spotify.tar.gz --no-secrets
+  spotify-cli state export spotify.tar.gz --exclude cache`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateExport(args[0])
+	},
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "Restore config, state and cache from an archive",
+	Long: `Restore an archive written by 'state export' into the current profile.
+
+Everything in the archive is restored unless --config, --state or --cache is
+given, in which case only those sections are. Existing state stores are kept
+unless --force is given. If the archive was exported without secrets, the
+current client secret and tokens are kept.`,
+	Example: `  spotify-cli state import spotify.tar.gz
+  spotify-cli state import spotify.tar.gz --list
+  spotify-cli state import spotify.tar.gz --state --force
+  spotify-cli state import spotify.tar.gz --profile work --config`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStateImport(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(statePathCmd)
+	stateCmd.AddCommand(stateBackupCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	statePathCmd.Flags().BoolVar(&stateShowStores, "stores", false, "List the stores in the state directory")
+	stateBackupCmd.Flags().StringVarP(&stateBackupPath, "output", "O", "", "Archive path (default is <profile>/backups/state-<timestamp>.tar.gz)")
+
+	stateExportCmd.Flags().BoolVar(&stateExportNoSecret, "no-secrets", false, "Leave the client secret and tokens out of the config")
+	stateExportCmd.Flags().StringSliceVar(&stateExportExclude, "exclude", nil, "Sections to leave out (config, state, cache)")
+
+	stateImportCmd.Flags().BoolVar(&stateImportConfig, "config", false, "Restore the config")
+	stateImportCmd.Flags().BoolVar(&stateImportState, "state", false, "Restore state stores")
+	stateImportCmd.Flags().BoolVar(&stateImportCache, "cache", false, "Restore the response cache")
+	stateImportCmd.Flags().BoolVar(&stateImportForce, "force", false, "Overwrite state stores that already exist")
+	stateImportCmd.Flags().BoolVar(&stateImportList, "list", false, "Show what the archive contains without restoring it")
+}
+
+// openState opens the state directory of the current profile
+func openState() (*state.Dir, error) {
+	return state.Open(filepath.Join(config.ProfileDir(configDir, profileName), state.DirName))
+}
+
+func runStatePath() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	if !stateShowStores {
+		fmt.Println(dir.Root())
+		return nil
+	}
+
+	stores, err := dir.Stores()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"path":   dir.Root(),
+			"cache":  httpCacheDir(),
+			"stores": stores,
+		})
+	}
+
+	fmt.Printf("State: %s\n", dir.Root())
+	fmt.Printf("Cache: %s\n\n", httpCacheDir())
+
+	if len(stores) == 0 {
+		fmt.Println("No state stores yet.")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-10s %s\n", "STORE", "SIZE", "MODIFIED")
+	fmt.Println(strings.Repeat("-", 56))
+	for _, store := range stores {
+		fmt.Printf("%-24s %-10s %s\n",
+			truncateString(store.Name, 24),
+			formatBytes(store.Size),
+			store.Modified.Format("2006-01-02 15:04"))
+	}
+
+	return nil
+}
+
+func runStateBackup() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	path := stateBackupPath
+	if path == "" {
+		path = filepath.Join(config.ProfileDir(configDir, profileName), "backups", state.BackupFileName(time.Now()))
+	}
+
+	stores, err := dir.BackupTo(path)
+	if err != nil {
+		return err
+	}
+
+	utils.PrintSuccess("Backed up %d store%s to %s", len(stores), pluralize(len(stores)), path)
+	return nil
+}
+
+func runStateExport(path string) error {
+	excluded := map[string]bool{}
+	for _, section := range stateExportExclude {
+		switch section {
+		case state.SectionConfig, state.SectionState, state.SectionCache:
+			excluded[section] = true
+		default:
+			return fmt.Errorf("unknown section %q (expected config, state or cache)", section)
+		}
+	}
+	if excluded[state.SectionState] {
+		return fmt.Errorf("state cannot be excluded from an export; use 'state backup' for config-free archives")
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	opts := state.ExportOptions{
+		Profile:         profileName,
+		IncludesSecrets: !stateExportNoSecret,
+	}
+
+	if !excluded[state.SectionConfig] {
+		profileConfig, err := config.LoadProfile(configDir, profileName)
+		if err != nil {
+			return err
+		}
+		if stateExportNoSecret {
+			profileConfig = profileConfig.WithoutSecrets()
+		}
+		if opts.Config, err = yaml.Marshal(profileConfig); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+	} else {
+		opts.IncludesSecrets = false
+	}
+
+	if !excluded[state.SectionCache] {
+		opts.CacheDir = httpCacheDir()
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	manifest, err := dir.Export(file, opts)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"path":     path,
+			"manifest": manifest,
+		})
+	}
+
+	utils.PrintSuccess("Exported %s to %s", describeManifest(manifest), path)
+	if manifest.IncludesSecrets {
+		utils.PrintWarning("The archive contains your client secret and tokens; keep it private")
+	}
+	return nil
+}
+
+func runStateImport(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	cfg := config.Get()
+
+	if stateImportList {
+		manifest, err := state.ReadManifest(file)
+		if err != nil {
+			return err
+		}
+
+		if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+			return utils.Output(manifest)
+		}
+
+		fmt.Printf("Created:  %s\n", manifest.CreatedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("Profile:  %s\n", manifest.Profile)
+		fmt.Printf("Sections: %s\n", strings.Join(manifest.Sections, ", "))
+		fmt.Printf("Secrets:  %v\n", manifest.IncludesSecrets)
+		fmt.Printf("Stores:   %s\n", strings.Join(manifest.Stores, ", "))
+		fmt.Printf("Cache:    %d cached response%s\n", manifest.CacheEntries, pluralize(manifest.CacheEntries))
+		return nil
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	opts := state.ImportOptions{Overwrite: stateImportForce}
+	if stateImportConfig {
+		opts.Sections = append(opts.Sections, state.SectionConfig)
+	}
+	if stateImportState {
+		opts.Sections = append(opts.Sections, state.SectionState)
+	}
+	if stateImportCache {
+		opts.Sections = append(opts.Sections, state.SectionCache)
+	}
+	if len(opts.Sections) == 0 || stateImportCache {
+		opts.CacheDir = httpCacheDir()
+	}
+
+	result, err := dir.Import(file, opts)
+	if err != nil {
+		return err
+	}
+
+	if result.Config != nil {
+		if err := config.ImportProfile(configDir, profileName, result.Config); err != nil {
+			return err
+		}
+	}
+
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{
+			"profile":       profileName,
+			"config":        result.Config != nil,
+			"restored":      result.Restored,
+			"skipped":       result.Skipped,
+			"cache_entries": result.CacheEntries,
+		})
+	}
+
+	if result.Config != nil {
+		utils.PrintSuccess("Restored config into profile %q", profileName)
+	}
+	if len(result.Restored) > 0 {
+		utils.PrintSuccess("Restored %d store%s: %s", len(result.Restored), pluralize(len(result.Restored)), strings.Join(result.Restored, ", "))
+	}
+	if len(result.Skipped) > 0 {
+		utils.PrintWarning("Kept %d existing store%s (use --force to overwrite): %s", len(result.Skipped), pluralize(len(result.Skipped)), strings.Join(result.Skipped, ", "))
+	}
+	if result.CacheEntries > 0 {
+		utils.PrintSuccess("Restored %d cached response%s", result.CacheEntries, pluralize(result.CacheEntries))
+	}
+	if result.Config == nil && len(result.Restored) == 0 && len(result.Skipped) == 0 && result.CacheEntries == 0 {
+		fmt.Println("Nothing to restore.")
+	}
+
+	return nil
+}
+
+// describeManifest summarises an archive, e.g. "config, 3 stores and 120 cached responses"
+func describeManifest(m *state.Manifest) string {
+	parts := []string{}
+	if m.Has(state.SectionConfig) {
+		parts = append(parts, "config")
+	}
+	parts = append(parts, fmt.Sprintf("%d store%s", len(m.Stores), pluralize(len(m.Stores))))
+	if m.Has(state.SectionCache) {
+		parts = append(parts, fmt.Sprintf("%d cached response%s", m.CacheEntries, pluralize(m.CacheEntries)))
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
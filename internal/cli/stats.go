@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+	"github.com/bambithedeer/spotify-api/internal/playcounts"
+	"github.com/bambithedeer/spotify-api/internal/queue"
+	"github.com/bambithedeer/spotify-api/internal/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsTimeRange    string
+	statsTop          int
+	statsReportFormat string
+	statsNoLibrary    bool
+
+	statsMonths   int
+	statsMinPlays int
+	statsLimit    int
+	statsFormat   string
+	statsQueue    bool
+	statsDeviceID string
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report on your library and listening",
+	Long: `Report your top artists and tracks for --time-range, the genres of your
+top artists, the decades your saved tracks come from, the average audio
+features of your top tracks, and how many tracks you saved each year.
+
+Top items come from up to 50 artists and tracks Spotify ranks for you. The
+decades and growth need every saved track, which is one request per 50;
+--no-library skips them for a quick report.
+
+The subcommands report from the play counts kept by 'history record'. The
+further back recording goes, the more there is to report.`,
+	Example: `  spotify-cli stats
+  spotify-cli stats --time-range long_term --format markdown > stats.md
+  spotify-cli stats --no-library --format json
+  spotify-cli stats forgotten
+  spotify-cli stats forgotten --months 12 --queue`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStats()
+	},
+}
+
+var statsForgottenCmd = &cobra.Command{
+	Use:   "forgotten",
+	Short: "Find old favorites you have not played in a while",
+	Long: `List tracks you played often but not in the last --months months, the
+most played first.
+
+With --queue, up to --limit of them are shuffled into the playback queue
+for a rediscovery session, keeping the same artist a few tracks apart.`,
+	Example: `  spotify-cli stats forgotten
+  spotify-cli stats forgotten --months 12 --min-plays 10
+  spotify-cli stats forgotten --queue --limit 30`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStatsForgotten()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsForgottenCmd)
+
+	statsCmd.Flags().StringVar(&statsTimeRange, "time-range", "medium_term", "Time range of top items (short_term, medium_term, long_term)")
+	statsCmd.Flags().IntVar(&statsTop, "top", 10, "Number of top artists, tracks and genres to list")
+	statsCmd.Flags().StringVarP(&statsReportFormat, "format", "f", "table", "Output format (table, markdown, json, yaml)")
+	statsCmd.Flags().BoolVar(&statsNoLibrary, "no-library", false, "Skip the decades and growth, which read the whole library")
+
+	statsForgottenCmd.Flags().IntVar(&statsMonths, "months", 6, "Months without a play before a track counts as forgotten")
+	statsForgottenCmd.Flags().IntVar(&statsMinPlays, "min-plays", 5, "Plays a track needs to count as a favorite")
+	statsForgottenCmd.Flags().IntVarP(&statsLimit, "limit", "l", 25, "Number of tracks to list or queue (0 for all)")
+	statsForgottenCmd.Flags().StringVarP(&statsFormat, "format", "f", "table", "Output format (table, uris, json, yaml)")
+	statsForgottenCmd.Flags().BoolVar(&statsQueue, "queue", false, "Add the tracks to the playback queue, shuffled")
+	statsForgottenCmd.Flags().StringVarP(&statsDeviceID, "device", "d", "", "Target device ID for --queue")
+}
+
+// statsSample is how many top artists and tracks the distributions and
+// averages are computed from
+const statsSample = 50
+
+func runStats() error {
+	switch statsReportFormat {
+	case "table", "markdown", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, markdown, json, yaml", statsReportFormat)
+	}
+	if statsTop < 1 {
+		return fmt.Errorf("--top must be at least 1")
+	}
+
+	spotifyClient, err := newUserClient("your listening statistics")
+	if err != nil {
+		return err
+	}
+
+	ctx := GetCommandContext()
+	topArtists, err := spotifyClient.Users.GetAllTopArtists(ctx, statsTimeRange, statsSample)
+	if err != nil {
+		return fmt.Errorf("failed to get top artists: %w", err)
+	}
+	topTracks, err := spotifyClient.Users.GetAllTopTracks(ctx, statsTimeRange, statsSample)
+	if err != nil {
+		return fmt.Errorf("failed to get top tracks: %w", err)
+	}
+
+	report := &stats.Report{
+		GeneratedAt: time.Now(),
+		TimeRange:   statsTimeRange,
+		TopArtists:  topArtists.Items[:min(statsTop, len(topArtists.Items))],
+		TopTracks:   topTracks.Items[:min(statsTop, len(topTracks.Items))],
+		Genres:      stats.Genres(topArtists.Items, statsTop),
+	}
+
+	var ids []string
+	for _, track := range topTracks.Items {
+		ids = append(ids, track.ID)
+	}
+	if len(ids) > 0 {
+		// Audio features are not available to every app, and the rest of the
+		// report stands without them
+		features, err := spotifyClient.Tracks.GetAllTracksAudioFeatures(ctx, ids)
+		if err != nil {
+			utils.PrintWarning("Leaving out audio features: %v", err)
+		} else {
+			report.Features = stats.AverageFeatures(features)
+		}
+	}
+
+	if !statsNoLibrary {
+		utils.PrintVerbose("Reading saved tracks...")
+		saved, err := spotifyClient.Library.SavedTracksPager(nil).All(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get saved tracks: %w", err)
+		}
+		tracks := make([]models.Track, len(saved))
+		for i := range saved {
+			tracks[i] = saved[i].Track
+		}
+		report.LibrarySize = len(saved)
+		report.Decades = stats.Decades(tracks)
+		report.Growth = stats.LibraryGrowth(saved)
+	}
+
+	cfg := config.Get()
+	outputFormat := statsReportFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	switch outputFormat {
+	case "json", "yaml":
+		return utils.Output(report)
+	case "markdown":
+		fmt.Print(report.Markdown())
+		return nil
+	}
+
+	printStatsTable(report)
+	return nil
+}
+
+func printStatsTable(report *stats.Report) {
+	fmt.Printf("Listening statistics - top items from %s\n", stats.TimeRangeLabel(report.TimeRange))
+
+	if len(report.TopArtists) > 0 {
+		fmt.Printf("\n%-4s %-40s %s\n", "#", "TOP ARTIST", "GENRES")
+		fmt.Println(strings.Repeat("-", 75))
+		for i, artist := range report.TopArtists {
+			fmt.Printf("%-4d %-40s %s\n", i+1, truncateString(artist.Name, 38), truncateString(strings.Join(artist.Genres, ", "), 30))
+		}
+	}
+
+	if len(report.TopTracks) > 0 {
+		fmt.Printf("\n%-4s %-40s %s\n", "#", "TOP TRACK", "ARTIST")
+		fmt.Println(strings.Repeat("-", 75))
+		for i, track := range report.TopTracks {
+			artist := ""
+			if len(track.Artists) > 0 {
+				artist = track.Artists[0].Name
+			}
+			fmt.Printf("%-4d %-40s %s\n", i+1, truncateString(track.Name, 38), truncateString(artist, 30))
+		}
+	}
+
+	printStatsShares("GENRE", report.Genres)
+	printStatsShares("DECADE", report.Decades)
+
+	if report.Features != nil {
+		fmt.Printf("\n%-20s %s\n", "AUDIO FEATURE", "AVERAGE")
+		fmt.Println(strings.Repeat("-", 30))
+		for _, row := range report.Features.Rows() {
+			fmt.Printf("%-20s %s\n", row[0], row[1])
+		}
+	}
+
+	if len(report.Growth) > 0 {
+		fmt.Printf("\n%-6s %-8s %s\n", "YEAR", "ADDED", "TOTAL")
+		fmt.Println(strings.Repeat("-", 24))
+		for _, g := range report.Growth {
+			fmt.Printf("%-6d %-8d %d\n", g.Year, g.Added, g.Total)
+		}
+		fmt.Printf("\n%d saved track%s\n", report.LibrarySize, pluralize(report.LibrarySize))
+	}
+}
+
+func printStatsShares(column string, shares []stats.Share) {
+	if len(shares) == 0 {
+		return
+	}
+	fmt.Printf("\n%-30s %-6s %s\n", column, "COUNT", "SHARE")
+	fmt.Println(strings.Repeat("-", 45))
+	for _, share := range shares {
+		fmt.Printf("%-30s %-6d %5.1f%%\n", truncateString(share.Name, 28), share.Count, share.Percent)
+	}
+}
+
+func runStatsForgotten() error {
+	switch statsFormat {
+	case "table", "uris", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, uris, json, yaml", statsFormat)
+	}
+	if statsMonths < 1 {
+		return fmt.Errorf("--months must be at least 1")
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	counts, err := playcounts.All(dir)
+	if err != nil {
+		return err
+	}
+
+	forgotten := playcounts.Forgotten(counts, time.Now().AddDate(0, -statsMonths, 0), statsMinPlays)
+	if statsLimit > 0 && len(forgotten) > statsLimit {
+		forgotten = forgotten[:statsLimit]
+	}
+
+	if statsQueue {
+		return queueForgotten(forgotten)
+	}
+
+	if statsFormat == "uris" {
+		for _, count := range forgotten {
+			fmt.Println(count.URI)
+		}
+		return nil
+	}
+
+	cfg := config.Get()
+	outputFormat := statsFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(forgotten)
+	}
+
+	if len(forgotten) == 0 {
+		fmt.Printf("No tracks played %d or more times have gone unplayed for %d months.\n", statsMinPlays, statsMonths)
+		return nil
+	}
+
+	uris := make([]string, len(forgotten))
+	for i, count := range forgotten {
+		uris[i] = count.URI
+	}
+	names, err := playedTrackNames(uris)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-6s %-50s %s\n", "PLAYS", "TRACK", "LAST PLAYED")
+	fmt.Println(strings.Repeat("-", 75))
+	for _, count := range forgotten {
+		name := names[count.URI]
+		if name == "" {
+			name = count.URI
+		}
+		fmt.Printf("%-6d %-50s %s\n", count.Plays, truncateString(name, 48), formatTime(count.LastPlayed))
+	}
+	return nil
+}
+
+// queueForgotten shuffles forgotten favorites into the playback queue
+func queueForgotten(forgotten []playcounts.Count) error {
+	if len(forgotten) == 0 {
+		return fmt.Errorf("no forgotten favorites to queue")
+	}
+
+	spotifyClient, err := newUserClient("your playback queue")
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(forgotten))
+	for i, count := range forgotten {
+		ids[i] = count.URI[strings.LastIndex(count.URI, ":")+1:]
+	}
+	tracks, err := getTracksByID(spotifyClient, ids)
+	if err != nil {
+		return err
+	}
+
+	source := queue.Source{Kind: "forgotten", Weight: 1}
+	pool := queue.Pool{Source: source}
+	for i := range tracks {
+		pool.Candidates = append(pool.Candidates, candidateFromTrack(&tracks[i], source))
+	}
+
+	result := queue.Build([]queue.Pool{pool}, queue.Options{
+		Length:      len(pool.Candidates),
+		Constraints: ordering.Constraints{ArtistSpacing: 3},
+		Seed:        time.Now().UnixNano(),
+	})
+	return addCandidatesToQueue(spotifyClient, result, statsDeviceID)
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// summaryJSON is where --summary-json writes the run summary
+	summaryJSON string
+
+	// commandReport collects the counters a command reports for the run
+	// summary, such as the number of items it added
+	commandReport = daemon.NewReport()
+
+	// daemonSummary is the summary runDaemonLoop produced, which describes
+	// a daemon's runs better than one for the command as a whole
+	daemonSummary *daemon.Summary
+)
+
+// writeRunSummary writes the --summary-json summary of cmd, which started at
+// startedAt and returned err. Nothing is written without the flag.
+func writeRunSummary(cmd *cobra.Command, startedAt time.Time, err error) {
+	if summaryJSON == "" {
+		return
+	}
+
+	summary := daemonSummary
+	if summary == nil {
+		name := rootCmd.Name()
+		if cmd != nil {
+			name = cmd.CommandPath()
+		}
+		summary = daemon.NewCommandSummary(name, startedAt, commandReport, err)
+		summary.ExitCode = ExitCode(err)
+	}
+	if stats, _ := client.Usage(); stats.Requests > 0 {
+		summary.Counters["api requests"] += stats.Requests
+	}
+
+	if writeErr := summary.WriteJSON(summaryJSON); writeErr != nil {
+		utils.PrintWarning("Failed to write run summary: %v", writeErr)
+	}
+}
@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/daemon"
+	"github.com/bambithedeer/spotify-api/internal/m3usync"
+	"github.com/bambithedeer/spotify-api/internal/playlistsync"
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncM3UDir    string
+	syncM3UDryRun bool
+	syncM3UForce  bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Keep Spotify playlists in step with playlists kept elsewhere",
+	Long:  `Commands that treat playlists kept outside Spotify as the source of truth and keep Spotify playlists matching them.`,
+}
+
+var syncM3UCmd = &cobra.Command{
+	Use:   "m3u",
+	Short: "Keep Spotify playlists matching a folder of M3U and text files",
+	Long: `Watch a folder of playlist files and keep a Spotify playlist matching each
+one, for playlists kept as files, in git or next to a music collection.
+
+Files ending in .m3u, .m3u8 or .txt are read. Each line is a track: a
+Spotify link or URI, a path to an audio file described by the #EXTINF line
+before it or by its file name, or in a text file "Artist - Title" or a
+line of 'playlist export --format tracks'. Tracks without a link are
+searched for on Spotify and matched by title and artist, as 'navidrome
+push-playlist' matches them; lines no track is found for are listed and
+left out.
+
+The first sync of a file creates a private playlist named by its
+#PLAYLIST line or file name. Later syncs edit that playlist to match the
+file, keeping the tracks already in place, and back it up first when
+tracks are removed. A file is only synced again once its contents change,
+or with --force; deleting a file leaves its playlist alone.
+
+The folder is checked every --interval until interrupted; --once checks it
+a single time and reports through the exit code, for cron.
+
+Requires user authentication with 'spotify-cli auth login'.`,
+	Example: `  spotify-cli sync m3u --dir ./playlists
+  spotify-cli sync m3u --dir ./playlists --once --dry-run
+  spotify-cli playlist export 37i9dQZF1DXcBWIGoYBM5M --format tracks --canonical --out playlists/mix.txt`,
+	Args: cobra.NoArgs,
+	RunE: runSyncM3U,
+}
+
+func init() {
+	syncM3UCmd.Flags().StringVar(&syncM3UDir, "dir", "", "Folder of playlist files to sync (required)")
+	syncM3UCmd.Flags().BoolVar(&syncM3UDryRun, "dry-run", false, "Show what each file would change without changing anything")
+	syncM3UCmd.Flags().BoolVar(&syncM3UForce, "force", false, "Sync every file, even those unchanged since the last sync")
+	syncM3UCmd.MarkFlagRequired("dir")
+	addDaemonFlags(syncM3UCmd, time.Minute)
+
+	syncCmd.AddCommand(syncM3UCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// m3uSyncPass collects what one pass over the folder did
+type m3uSyncPass struct {
+	spotifyClient *client.SpotifyClient
+	state         *m3usync.State
+	now           time.Time
+
+	files    map[string]m3usync.Synced
+	resolved map[string]string
+
+	synced, unchanged, failed, unresolved int
+}
+
+func runSyncM3U(cmd *cobra.Command, args []string) error {
+	root, err := filepath.Abs(syncM3UDir)
+	if err != nil {
+		return fmt.Errorf("invalid --dir: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil {
+		return fmt.Errorf("failed to read playlist folder: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a folder", root)
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+	spotifyClient, err := newUserClient("your playlists")
+	if err != nil {
+		return err
+	}
+
+	return runDaemonLoop(cmd, "sync-m3u", func(ctx context.Context, report *daemon.Report) error {
+		synced, err := m3usync.Load(dir)
+		if err != nil {
+			return err
+		}
+		paths, err := listPlaylistFiles(root)
+		if err != nil {
+			return err
+		}
+
+		pass := &m3uSyncPass{
+			spotifyClient: spotifyClient,
+			state:         synced,
+			now:           time.Now(),
+			files:         make(map[string]m3usync.Synced),
+			resolved:      make(map[string]string),
+		}
+		for _, path := range paths {
+			if err := pass.syncFile(ctx, path); err != nil {
+				pass.failed++
+				utils.PrintWarning("%s: %v", filepath.Base(path), err)
+			}
+		}
+
+		report.Add("files synced", pass.synced)
+		report.Add("files unchanged", pass.unchanged)
+		report.Add("files failed", pass.failed)
+		report.Add("tracks unresolved", pass.unresolved)
+		if pass.synced > 0 || pass.failed > 0 {
+			utils.PrintSuccess("Playlist files: %d synced, %d unchanged, %d failed", pass.synced, pass.unchanged, pass.failed)
+		}
+
+		if !syncM3UDryRun {
+			if err := m3usync.Record(dir, pass.files, pass.resolved); err != nil {
+				return err
+			}
+		}
+		if pass.failed > 0 {
+			return fmt.Errorf("%d of %d playlist file%s failed to sync", pass.failed, len(paths), pluralize(len(paths)))
+		}
+		return nil
+	})
+}
+
+// listPlaylistFiles returns the playlist files directly in root, by name
+func listPlaylistFiles(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playlist folder: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && m3usync.IsPlaylistFile(entry.Name()) {
+			paths = append(paths, filepath.Join(root, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (p *m3uSyncPass) syncFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	playlist, err := m3usync.Parse(f, path)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if !syncM3UForce && !p.state.Changed(path, playlist.Hash) {
+		p.unchanged++
+		return nil
+	}
+
+	name := filepath.Base(path)
+	uris, tracks, missing, err := p.resolve(ctx, playlist)
+	if err != nil {
+		return err
+	}
+	for _, entry := range missing {
+		utils.PrintWarning("%s:%d: no Spotify track found for %s", name, entry.Line, entry)
+	}
+	p.unresolved += len(missing)
+
+	synced := m3usync.Synced{
+		PlaylistID: p.state.Files[path].PlaylistID,
+		Hash:       playlist.Hash,
+		SyncedAt:   p.now,
+	}
+	for _, entry := range missing {
+		synced.Unresolved = append(synced.Unresolved, entry.String())
+	}
+
+	if synced.PlaylistID == "" {
+		if syncM3UDryRun {
+			fmt.Printf("Dry run: %s would create %q with %d track%s\n", name, playlist.Name, len(uris), pluralize(len(uris)))
+			p.synced++
+			return nil
+		}
+		created, err := createPlaylistWithTracks(p.spotifyClient, playlist.Name, "Synced from "+name+" by spotify-cli", uris)
+		if err != nil {
+			return err
+		}
+		synced.PlaylistID = created.ID
+		utils.PrintSuccess("%s: created %q with %d track%s (%s)", name, playlist.Name, len(uris), pluralize(len(uris)), created.ID)
+	} else if err := p.update(ctx, name, synced.PlaylistID, uris, tracks); err != nil {
+		return err
+	}
+
+	p.files[path] = synced
+	p.synced++
+	return nil
+}
+
+// update edits an existing playlist to hold uris, in order
+func (p *m3uSyncPass) update(ctx context.Context, name, playlistID string, uris []string, tracks []playlistExportTrack) error {
+	snapshotID, err := playlistSnapshotID(ctx, p.spotifyClient, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to read playlist %s: %w", playlistID, err)
+	}
+	target, targetTracks, err := fetchSyncTarget(ctx, p.spotifyClient, playlistID)
+	if err != nil {
+		return fmt.Errorf("failed to read playlist %s: %w", playlistID, err)
+	}
+	plan := playlistsync.Compute(uris, target, false)
+
+	if syncM3UDryRun {
+		fmt.Printf("%s -> %s\n", name, playlistID)
+		printSyncPlan(plan, tracks, targetTracks)
+		return nil
+	}
+	if plan.Empty() {
+		utils.PrintVerbose("%s: already in sync", name)
+		return nil
+	}
+
+	var backup string
+	if len(plan.Remove) > 0 {
+		if backup, err = backupPlaylist(ctx, p.spotifyClient, playlistID); err != nil {
+			return err
+		}
+	}
+	if _, err := applySyncPlan(ctx, p.spotifyClient, playlistID, snapshotID, plan); err != nil {
+		return err
+	}
+	utils.PrintSuccess("%s: updated %s, %d added, %d removed", name, playlistID, plan.Added(), len(plan.Remove))
+	printUndoHint(playlistID, backup)
+	return nil
+}
+
+// resolve finds the Spotify track of every entry of a playlist. It returns
+// the URIs in file order, the tracks they are for dry runs to describe, and
+// the entries no track was found for.
+func (p *m3uSyncPass) resolve(ctx context.Context, playlist *m3usync.Playlist) ([]string, []playlistExportTrack, []m3usync.Entry, error) {
+	var uris []string
+	var tracks []playlistExportTrack
+	var missing []m3usync.Entry
+	for _, entry := range playlist.Entries {
+		uri := entry.URI
+		if uri == "" {
+			uri = p.resolved[entry.Key()]
+		}
+		if uri == "" {
+			uri = p.state.Resolved[entry.Key()]
+		}
+		if uri == "" {
+			found, err := p.search(ctx, entry)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if found == "" {
+				missing = append(missing, entry)
+				continue
+			}
+			uri = found
+			p.resolved[entry.Key()] = uri
+		}
+
+		uris = append(uris, uri)
+		track := playlistExportTrack{Name: entry.Title, Album: entry.Album, URI: uri}
+		if entry.Artist != "" {
+			track.Artists = []string{entry.Artist}
+		}
+		tracks = append(tracks, track)
+	}
+	return uris, tracks, missing, nil
+}
+
+// search looks an entry up on Spotify and returns the URI of the track it
+// is, or "" if none is. A field search by title and artist comes first, and
+// a plain one after it for titles the field search misses.
+func (p *m3uSyncPass) search(ctx context.Context, entry m3usync.Entry) (string, error) {
+	track := trackmatch.Track{Title: entry.Title, Album: entry.Album, Duration: entry.Duration}
+	title := strings.ReplaceAll(trackmatch.BaseTitle(entry.Title), `"`, "")
+	queries := []string{title}
+	if entry.Artist != "" {
+		track.Artists = []string{entry.Artist}
+		artist := strings.ReplaceAll(entry.Artist, `"`, "")
+		queries = []string{fmt.Sprintf("track:%q artist:%q", title, artist), artist + " " + title}
+	}
+
+	for _, query := range queries {
+		utils.PrintVerbose("Searching for %s", query)
+		results, _, err := p.spotifyClient.Search.SearchTracks(ctx, query, &api.PaginationOptions{Limit: 10})
+		if err != nil {
+			return "", fmt.Errorf("failed to search for %s: %w", entry, err)
+		}
+
+		songs := make([]trackmatch.Song, len(results.Items))
+		for i, result := range results.Items {
+			songs[i] = trackmatch.Song{
+				Title:    result.Name,
+				Artist:   utils.FormatSimpleArtists(result.Artists),
+				Duration: time.Duration(result.DurationMs) * time.Millisecond,
+			}
+			if result.Album != nil {
+				songs[i].Album = result.Album.Name
+			}
+		}
+		if i := trackmatch.Best(track, songs); i >= 0 {
+			return results.Items[i].URI, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/tags"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagFindFormat string
+	tagFindKind   string
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Organize tracks, albums and playlists with local tags",
+	Long: `Label tracks, albums and playlists with your own tags, such as "chill" or
+"dinner". Tags are lowercase words kept in the local state directory, so
+'state export' includes them, and they are never sent to Spotify.
+
+Tags can feed other commands:
+  queue build --sources tag:chill       Sample from everything tagged chill
+  query 'tagged("chill")...'            Use tagged tracks as a query source
+
+Tagged albums and playlists contribute all of their tracks.`,
+	Example: `  spotify-cli tag add spotify:track:4iV5W9uYEdYUVa79Axb7Rh chill night
+  spotify-cli tag add spotify:album:4aawyAB9vmqN3uQ7FjRGTy chill
+  spotify-cli tag find chill --format uris
+  spotify-cli query 'tagged("chill").filter(energy<0.4).save_to("Wind Down")'
+  spotify-cli tag remove spotify:track:4iV5W9uYEdYUVa79Axb7Rh night`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <uri> <tag...>",
+	Short: "Tag an item",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTagAdd(args[0], args[1:])
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <uri> <tag...>",
+	Short: "Remove tags from an item",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTagRemove(args[0], args[1:])
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list [uri]",
+	Short: "List tags in use, or the tags on one item",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return runTagListItem(args[0])
+		}
+		return runTagList()
+	},
+}
+
+var tagFindCmd = &cobra.Command{
+	Use:   "find <tag...>",
+	Short: "Find the items carrying every given tag",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTagFind(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagFindCmd)
+
+	tagFindCmd.Flags().StringVarP(&tagFindFormat, "format", "f", "table", "Output format (table, uris, json, yaml)")
+	tagFindCmd.Flags().StringVar(&tagFindKind, "kind", "", "Only find items of this kind (track, album, playlist)")
+}
+
+func runTagAdd(uri string, tagNames []string) error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	added, err := tags.Add(dir, uri, tagNames, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(added) == 0 {
+		fmt.Printf("%s already has those tags\n", uri)
+		return nil
+	}
+	utils.PrintSuccess("Tagged %s with %s", uri, strings.Join(added, ", "))
+	return nil
+}
+
+func runTagRemove(uri string, tagNames []string) error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	removed, err := tags.Remove(dir, uri, tagNames)
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Printf("%s had none of those tags\n", uri)
+		return nil
+	}
+	utils.PrintSuccess("Removed %s from %s", strings.Join(removed, ", "), uri)
+	return nil
+}
+
+func runTagList() error {
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	counts, err := tags.Counts(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(counts)
+	}
+
+	if len(counts) == 0 {
+		fmt.Println("No tags yet. Add one with 'spotify-cli tag add <uri> <tag>'.")
+		return nil
+	}
+
+	fmt.Printf("%-30s %s\n", "TAG", "ITEMS")
+	fmt.Println(strings.Repeat("-", 40))
+	for _, count := range counts {
+		fmt.Printf("%-30s %d\n", count.Tag, count.Items)
+	}
+	return nil
+}
+
+func runTagListItem(uri string) error {
+	if err := tags.ValidateURI(uri); err != nil {
+		return err
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	itemTags, err := tags.Of(dir, uri)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get()
+	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+		return utils.Output(map[string]interface{}{"uri": uri, "tags": itemTags})
+	}
+
+	if len(itemTags) == 0 {
+		fmt.Printf("%s has no tags\n", uri)
+		return nil
+	}
+	fmt.Println(strings.Join(itemTags, " "))
+	return nil
+}
+
+func runTagFind(tagNames []string) error {
+	switch tagFindFormat {
+	case "table", "uris", "json", "yaml":
+	default:
+		return fmt.Errorf("invalid format %q. Valid formats: table, uris, json, yaml", tagFindFormat)
+	}
+	if tagFindKind != "" && !slices.Contains(tags.Kinds, tagFindKind) {
+		return fmt.Errorf("invalid --kind %q. Valid kinds: %s", tagFindKind, strings.Join(tags.Kinds, ", "))
+	}
+
+	dir, err := openState()
+	if err != nil {
+		return err
+	}
+
+	uris, err := tags.Find(dir, tagNames, tagFindKind)
+	if err != nil {
+		return err
+	}
+
+	if tagFindFormat == "uris" {
+		for _, uri := range uris {
+			fmt.Println(uri)
+		}
+		return nil
+	}
+
+	type taggedItem struct {
+		URI  string   `json:"uri" yaml:"uri"`
+		Kind string   `json:"kind" yaml:"kind"`
+		Tags []string `json:"tags" yaml:"tags"`
+	}
+	items := make([]taggedItem, 0, len(uris))
+	for _, uri := range uris {
+		itemTags, err := tags.Of(dir, uri)
+		if err != nil {
+			return err
+		}
+		items = append(items, taggedItem{URI: uri, Kind: tags.Kind(uri), Tags: itemTags})
+	}
+
+	cfg := config.Get()
+	outputFormat := tagFindFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+	if outputFormat == "json" || outputFormat == "yaml" {
+		return utils.Output(items)
+	}
+
+	if len(items) == 0 {
+		fmt.Printf("Nothing is tagged %s\n", strings.Join(tagNames, " and "))
+		return nil
+	}
+
+	fmt.Printf("%-9s %-38s %s\n", "KIND", "URI", "TAGS")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, item := range items {
+		fmt.Printf("%-9s %-38s %s\n", item.Kind, item.URI, strings.Join(item.Tags, " "))
+	}
+	return nil
+}
+
+// loadTaggedTracks returns up to limit tracks carrying a tag, in the order
+// they were tagged. Tagged albums and playlists contribute all their tracks;
+// album tracks get an album with just the ID and URI, since the album tracks
+// endpoint leaves it out. A limit of zero loads everything.
+func loadTaggedTracks(ctx context.Context, spotifyClient *client.SpotifyClient, tag string, limit int) ([]models.Track, error) {
+	dir, err := openState()
+	if err != nil {
+		return nil, err
+	}
+	uris, err := tags.Find(dir, []string{tag}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []models.Track
+	full := func() bool { return limit > 0 && len(tracks) >= limit }
+
+	// Consecutive tagged tracks are fetched together, 50 at a time
+	var pendingIDs []string
+	flush := func() error {
+		if len(pendingIDs) == 0 {
+			return nil
+		}
+		batch, err := getTracksByID(spotifyClient, pendingIDs)
+		if err != nil {
+			return err
+		}
+		tracks = append(tracks, batch...)
+		pendingIDs = nil
+		return nil
+	}
+
+	for _, uri := range uris {
+		if full() {
+			break
+		}
+		id := uri[strings.LastIndex(uri, ":")+1:]
+
+		switch tags.Kind(uri) {
+		case "track":
+			pendingIDs = append(pendingIDs, id)
+			continue
+
+		case "album":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			pager := spotifyClient.Albums.AlbumTracksPager(id, "")
+			if limit > 0 {
+				pager = pager.WithMaxItems(limit - len(tracks))
+			}
+			albumTracks, err := pager.All(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tracks of %s: %w", uri, err)
+			}
+			for _, track := range albumTracks {
+				if track.Album == nil {
+					track.Album = &models.SimpleAlbum{ID: id, URI: uri}
+				}
+				tracks = append(tracks, track)
+			}
+
+		case "playlist":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			items, err := spotifyClient.Playlists.PlaylistTracksPager(id, nil).All(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tracks of %s: %w", uri, err)
+			}
+			for _, item := range items {
+				if item.IsLocal || !item.Track.IsTrack() || item.Track.URI() == "" {
+					continue
+				}
+				tracks = append(tracks, *item.Track.Track)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(tracks) > limit {
+		tracks = tracks[:limit]
+	}
+	return tracks, nil
+}
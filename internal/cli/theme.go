@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/mosaic"
+	"github.com/bambithedeer/spotify-api/internal/palette"
+)
+
+var playerTheme string
+
+// playerThemeFor picks the colors for now-playing output. With the auto
+// theme, truecolor terminals get colors taken from the album or show art,
+// and other terminals the static theme. Output that is not going to a
+// terminal, or with color turned off in the config, is never colored.
+func playerThemeFor(rawItem interface{}) (palette.Theme, error) {
+	switch playerTheme {
+	case "auto", "static", "none":
+	default:
+		return palette.Theme{}, fmt.Errorf("invalid theme '%s'. Must be 'auto', 'static' or 'none'", playerTheme)
+	}
+
+	if playerTheme == "none" || !config.Get().ColorOutput || !stdoutIsTerminal() {
+		return palette.Theme{}, nil
+	}
+	if playerTheme == "static" || !trueColorTerminal() {
+		return palette.Static, nil
+	}
+
+	item, err := decodePlayingItem(rawItem)
+	if err != nil {
+		return palette.Static, nil
+	}
+
+	// The smallest artwork is plenty to find the main colors in
+	art, ok := mosaic.PickImage(artworkOf(item), 64)
+	if !ok {
+		return palette.Static, nil
+	}
+	img, err := fetchImage(art.URL)
+	if err != nil {
+		utils.PrintVerbose("Failed to download artwork for the theme: %v", err)
+		return palette.Static, nil
+	}
+
+	if theme, ok := palette.FromImage(img); ok {
+		return theme, nil
+	}
+	return palette.Static, nil
+}
+
+// artworkOf returns the album art of a track or the cover of an episode
+func artworkOf(item *models.PlaylistItem) []models.Image {
+	switch {
+	case item.Track != nil && item.Track.Album != nil:
+		return item.Track.Album.Images
+	case item.Episode != nil && len(item.Episode.Images) > 0:
+		return item.Episode.Images
+	case item.Episode != nil && item.Episode.Show != nil:
+		return item.Episode.Show.Images
+	}
+	return nil
+}
+
+// trueColorTerminal reports whether the terminal advertises 24-bit color
+func trueColorTerminal() bool {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	return colorTerm == "truecolor" || colorTerm == "24bit"
+}
+
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressBar draws how far through a track playback is
+func progressBar(progressMs, durationMs, width int) string {
+	filled := 0
+	if durationMs > 0 {
+		filled = min(width, max(0, progressMs*width/durationMs))
+	}
+	return strings.Repeat("━", filled) + strings.Repeat("─", width-filled)
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/spf13/cobra"
+)
+
+var trackFormat string
+
+// trackCmd represents the track command
+var trackCmd = &cobra.Command{
+	Use:   "track",
+	Short: "Inspect tracks",
+	Long: `Inspect tracks in the Spotify catalog.
+
+Requires authentication with either user account or client credentials.`,
+	Example: `  spotify-cli track features 4iV5W9uYEdYUVa79Axb7Rh 7ouMYWpwJ422jRcDASZB7P
+  spotify-cli track analysis 4iV5W9uYEdYUVa79Axb7Rh --format json
+  spotify-cli track tempo 4iV5W9uYEdYUVa79Axb7Rh --transpose -2`,
+}
+
+var trackFeaturesCmd = &cobra.Command{
+	Use:   "features [track-id...]",
+	Short: "Show audio features of tracks",
+	Long: `Show the audio features Spotify computed for one or more tracks:
+danceability, energy and valence from 0 to 1, tempo in BPM, and key.
+
+Tracks can be given as IDs, URIs or open.spotify.com links, any number at a
+time; features are requested 100 tracks at a time.`,
+	Args: cobra.MinimumNArgs(1),
+	Example: `  spotify-cli track features 4iV5W9uYEdYUVa79Axb7Rh
+  spotify-cli track features spotify:track:4iV5W9uYEdYUVa79Axb7Rh 7ouMYWpwJ422jRcDASZB7P --format json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTrackFeatures(args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trackCmd)
+	trackCmd.AddCommand(trackFeaturesCmd)
+
+	trackFeaturesCmd.Flags().StringVarP(&trackFormat, "format", "f", "table", "Output format (table, json, yaml)")
+}
+
+// trackFeatures pairs a track with its audio features
+type trackFeatures struct {
+	Track    models.Track          `json:"track" yaml:"track"`
+	Features *models.AudioFeatures `json:"features" yaml:"features"`
+}
+
+func runTrackFeatures(refs []string) error {
+	ids, err := api.NewValidator().NormalizeAndValidateIDs(refs)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	tracks, err := getTracksByID(spotifyClient, ids)
+	if err != nil {
+		return err
+	}
+
+	results, err := withAudioFeatures(spotifyClient, tracks)
+	if err != nil {
+		return err
+	}
+
+	return outputTrackFeatures(results, false)
+}
+
+// getTracksByID looks up tracks 50 at a time, the most the API accepts.
+// Tracks that no longer exist are warned about and left out.
+func getTracksByID(spotifyClient *client.SpotifyClient, ids []string) ([]models.Track, error) {
+	var tracks []models.Track
+	for start := 0; start < len(ids); start += 50 {
+		end := min(start+50, len(ids))
+		batch, err := spotifyClient.Tracks.GetTracks(GetCommandContext(), ids[start:end], "")
+		var partial *spotify.PartialError
+		if errors.As(err, &partial) {
+			utils.PrintWarning(fmt.Sprintf("Skipping %s", partial))
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get tracks: %w", err)
+		}
+		tracks = append(tracks, batch...)
+	}
+	return tracks, nil
+}
+
+// withAudioFeatures looks up the audio features of tracks. Local files and
+// tracks Spotify has not analysed get nil features.
+func withAudioFeatures(spotifyClient *client.SpotifyClient, tracks []models.Track) ([]trackFeatures, error) {
+	results := make([]trackFeatures, len(tracks))
+	var ids []string
+	for i, track := range tracks {
+		results[i].Track = track
+		if track.ID != "" {
+			ids = append(ids, track.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	features, err := spotifyClient.Tracks.GetAllTracksAudioFeatures(GetCommandContext(), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audio features: %w", err)
+	}
+
+	byID := make(map[string]*models.AudioFeatures, len(features))
+	for i := range features {
+		if features[i].ID != "" {
+			byID[features[i].ID] = &features[i]
+		}
+	}
+	for i := range results {
+		results[i].Features = byID[results[i].Track.ID]
+	}
+
+	return results, nil
+}
+
+// featureSummary holds the range of a feature across tracks
+type featureSummary struct {
+	Min float64 `json:"min" yaml:"min"`
+	Avg float64 `json:"avg" yaml:"avg"`
+	Max float64 `json:"max" yaml:"max"`
+}
+
+// summarizeFeatures returns the min, average and max of the columns shown in
+// the features table, over the tracks that have features
+func summarizeFeatures(results []trackFeatures) map[string]featureSummary {
+	columns := map[string]func(*models.AudioFeatures) float64{
+		"danceability": func(f *models.AudioFeatures) float64 { return f.Danceability },
+		"energy":       func(f *models.AudioFeatures) float64 { return f.Energy },
+		"valence":      func(f *models.AudioFeatures) float64 { return f.Valence },
+		"tempo":        func(f *models.AudioFeatures) float64 { return f.Tempo },
+	}
+
+	summary := make(map[string]featureSummary, len(columns))
+	for name, get := range columns {
+		var s featureSummary
+		n := 0
+		for _, result := range results {
+			if result.Features == nil {
+				continue
+			}
+			v := get(result.Features)
+			if n == 0 || v < s.Min {
+				s.Min = v
+			}
+			if n == 0 || v > s.Max {
+				s.Max = v
+			}
+			s.Avg += v
+			n++
+		}
+		if n == 0 {
+			return nil
+		}
+		s.Avg /= float64(n)
+		summary[name] = s
+	}
+	return summary
+}
+
+func outputTrackFeatures(results []trackFeatures, withSummary bool) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := trackFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	var summary map[string]featureSummary
+	if withSummary {
+		summary = summarizeFeatures(results)
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		data := map[string]interface{}{
+			"tracks": results,
+		}
+		if summary != nil {
+			data["summary"] = summary
+		}
+		return utils.Output(data)
+	}
+
+	fmt.Printf("%-35s %-22s %6s %6s %7s %7s  %s\n", "TRACK", "ARTIST", "DANCE", "ENERGY", "VALENCE", "TEMPO", "KEY")
+	fmt.Println(strings.Repeat("-", 100))
+
+	missing := 0
+	for _, result := range results {
+		name := truncateString(result.Track.Name, 33)
+		artists := truncateString(utils.FormatSimpleArtists(result.Track.Artists), 20)
+		f := result.Features
+		if f == nil {
+			missing++
+			fmt.Printf("%-35s %-22s %6s %6s %7s %7s  %s\n", name, artists, "-", "-", "-", "-", "-")
+			continue
+		}
+		fmt.Printf("%-35s %-22s %6.2f %6.2f %7.2f %7.1f  %s\n",
+			name, artists, f.Danceability, f.Energy, f.Valence, f.Tempo, f.KeyName())
+	}
+
+	if summary != nil {
+		fmt.Println(strings.Repeat("-", 100))
+		for _, row := range []struct {
+			label string
+			value func(featureSummary) float64
+		}{
+			{"MIN", func(s featureSummary) float64 { return s.Min }},
+			{"AVG", func(s featureSummary) float64 { return s.Avg }},
+			{"MAX", func(s featureSummary) float64 { return s.Max }},
+		} {
+			fmt.Printf("%-35s %-22s %6.2f %6.2f %7.2f %7.1f\n", row.label, "",
+				row.value(summary["danceability"]), row.value(summary["energy"]),
+				row.value(summary["valence"]), row.value(summary["tempo"]))
+		}
+	}
+
+	if missing > 0 {
+		fmt.Printf("\n%d track%s without audio features\n", missing, pluralize(missing))
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var trackAnalysisOnly []string
+
+// analysisParts are the top-level parts of an audio analysis, in API order
+var analysisParts = []string{"meta", "track", "bars", "beats", "sections", "segments", "tatums"}
+
+var trackAnalysisCmd = &cobra.Command{
+	Use:   "analysis [track-id]",
+	Short: "Show or export the audio analysis of a track",
+	Long: `Show the detailed audio analysis Spotify computed for a track: its overall
+tempo, key and loudness, and the timing of every bar, beat, section, segment
+and tatum.
+
+The table view summarizes the track and lists its sections. Use --format json
+or yaml to dump the full analysis, and --only to keep just the parts you need:
+  ` + strings.Join(analysisParts, ", "),
+	Args: cobra.ExactArgs(1),
+	Example: `  spotify-cli track analysis 4iV5W9uYEdYUVa79Axb7Rh
+  spotify-cli track analysis 4iV5W9uYEdYUVa79Axb7Rh --format json > analysis.json
+  spotify-cli track analysis spotify:track:4iV5W9uYEdYUVa79Axb7Rh --only beats,bars --format json
+  spotify-cli track analysis 4iV5W9uYEdYUVa79Axb7Rh --only segments`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTrackAnalysis(args[0])
+	},
+}
+
+func init() {
+	trackCmd.AddCommand(trackAnalysisCmd)
+
+	trackAnalysisCmd.Flags().StringVarP(&trackFormat, "format", "f", "table", "Output format (table, json, yaml)")
+	trackAnalysisCmd.Flags().StringSliceVar(&trackAnalysisOnly, "only", nil, "Only output these parts (comma-separated: "+strings.Join(analysisParts, ", ")+")")
+}
+
+func runTrackAnalysis(ref string) error {
+	ids, err := api.NewValidator().NormalizeAndValidateIDs([]string{ref})
+	if err != nil {
+		return err
+	}
+	trackID := ids[0]
+
+	only, err := parseAnalysisParts(trackAnalysisOnly)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	analysis, err := spotifyClient.Tracks.GetTrackAudioAnalysis(GetCommandContext(), trackID)
+	if err != nil {
+		return fmt.Errorf("failed to get audio analysis: %w", err)
+	}
+
+	return outputTrackAnalysis(analysis, only)
+}
+
+// parseAnalysisParts validates --only, returning the parts in API order
+// without duplicates, or nil for the whole analysis
+func parseAnalysisParts(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		known := false
+		for _, part := range analysisParts {
+			if name == part {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown analysis part %q (expected %s)", name, strings.Join(analysisParts, ", "))
+		}
+		wanted[name] = true
+	}
+
+	var parts []string
+	for _, part := range analysisParts {
+		if wanted[part] {
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
+
+// analysisPart returns one top-level part of an analysis by name
+func analysisPart(analysis *models.AudioAnalysis, name string) interface{} {
+	switch name {
+	case "meta":
+		return analysis.Meta
+	case "track":
+		return analysis.Track
+	case "bars":
+		return analysis.Bars
+	case "beats":
+		return analysis.Beats
+	case "sections":
+		return analysis.Sections
+	case "segments":
+		return analysis.Segments
+	default:
+		return analysis.Tatums
+	}
+}
+
+func outputTrackAnalysis(analysis *models.AudioAnalysis, only []string) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := trackFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	if outputFormat == "json" || outputFormat == "yaml" {
+		var data interface{} = analysis
+		if only != nil {
+			parts := make(map[string]interface{}, len(only))
+			for _, name := range only {
+				parts[name] = analysisPart(analysis, name)
+			}
+			data = parts
+		}
+
+		// The analysis is meant to be piped into other tools, so honor
+		// --format even when the configured default output is text
+		if outputFormat == "yaml" {
+			return utils.OutputYAML(data)
+		}
+		return utils.OutputJSON(data)
+	}
+
+	if only == nil {
+		only = []string{"track", "sections"}
+	}
+
+	for i, name := range only {
+		if i > 0 {
+			fmt.Println()
+		}
+		switch name {
+		case "meta":
+			printAnalysisMeta(analysis.Meta)
+		case "track":
+			printAnalysisSummary(analysis)
+		case "sections":
+			printAnalysisSections(analysis.Sections)
+		case "segments":
+			printAnalysisSegments(analysis.Segments)
+		default:
+			printAnalysisIntervals(name, analysisPart(analysis, name).([]models.AudioAnalysisSegment))
+		}
+	}
+
+	return nil
+}
+
+func printAnalysisMeta(meta models.AudioAnalysisMeta) {
+	fmt.Println("Analysis:")
+	fmt.Printf("  Analyzer:  %s (%s)\n", meta.AnalyzerVersion, meta.Platform)
+	fmt.Printf("  Status:    %s\n", meta.DetailedStatus)
+	fmt.Printf("  Took:      %.2fs\n", meta.AnalysisTime)
+}
+
+func printAnalysisSummary(analysis *models.AudioAnalysis) {
+	track := analysis.Track
+
+	fmt.Println("Track:")
+	fmt.Printf("  Duration:        %s\n", utils.FormatDuration(int(track.Duration*1000)))
+	fmt.Printf("  Tempo:           %.1f BPM (confidence %.2f)\n", track.Tempo, track.TempoConfidence)
+	if key := models.KeyName(track.Key, track.Mode); key != "" {
+		fmt.Printf("  Key:             %s (confidence %.2f)\n", key, track.KeyConfidence)
+	}
+	fmt.Printf("  Time signature:  %d/4 (confidence %.2f)\n", track.TimeSignature, track.TimeSignatureConfidence)
+	fmt.Printf("  Loudness:        %.1f dB\n", track.Loudness)
+	fmt.Printf("  Fade in ends:    %.2fs\n", track.EndOfFadeIn)
+	fmt.Printf("  Fade out starts: %.2fs\n", track.StartOfFadeOut)
+	fmt.Printf("  Contains:        %d bars, %d beats, %d sections, %d segments, %d tatums\n",
+		len(analysis.Bars), len(analysis.Beats), len(analysis.Sections), len(analysis.Segments), len(analysis.Tatums))
+}
+
+func printAnalysisSections(sections []models.AudioAnalysisSection) {
+	fmt.Printf("Sections - %d\n\n", len(sections))
+	fmt.Printf("%9s %9s %8s %-10s %6s %9s\n", "START", "DURATION", "TEMPO", "KEY", "TIME", "LOUDNESS")
+	fmt.Println(strings.Repeat("-", 56))
+
+	for _, section := range sections {
+		key := models.KeyName(section.Key, section.Mode)
+		if key == "" {
+			key = "-"
+		}
+		fmt.Printf("%8.2fs %8.2fs %8.1f %-10s %4d/4 %6.1f dB\n",
+			section.Start, section.Duration, section.Tempo, key, section.TimeSignature, section.Loudness)
+	}
+}
+
+func printAnalysisSegments(segments []models.AudioAnalysisSegment) {
+	fmt.Printf("Segments - %d\n\n", len(segments))
+	fmt.Printf("%9s %9s %10s %10s\n", "START", "DURATION", "CONFIDENCE", "LOUDNESS")
+	fmt.Println(strings.Repeat("-", 44))
+
+	for _, segment := range segments {
+		fmt.Printf("%8.3fs %8.3fs %10.2f %7.1f dB\n",
+			segment.Start, segment.Duration, segment.Confidence, segment.LoudnessMax)
+	}
+}
+
+// printAnalysisIntervals lists bars, beats or tatums, which only carry timing
+func printAnalysisIntervals(name string, intervals []models.AudioAnalysisSegment) {
+	fmt.Printf("%s - %d\n\n", strings.ToUpper(name[:1])+name[1:], len(intervals))
+	fmt.Printf("%9s %9s %10s\n", "START", "DURATION", "CONFIDENCE")
+	fmt.Println(strings.Repeat("-", 30))
+
+	for _, interval := range intervals {
+		fmt.Printf("%8.3fs %8.3fs %10.2f\n", interval.Start, interval.Duration, interval.Confidence)
+	}
+}
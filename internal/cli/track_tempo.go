@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trackTempoTranspose int
+	trackTempoSpeed     float64
+)
+
+var trackTempoCmd = &cobra.Command{
+	Use:   "tempo [track-id...]",
+	Short: "Show tempo, key and time signature for practicing",
+	Long: `Show the tempo, key and time signature of one or more tracks, with how
+confident Spotify's analysis is in each, for setting a metronome or tuning
+up before playing along.
+
+Keys are shown in standard notation and on the Camelot wheel. Use --transpose
+to also show the key a number of semitones up or down, for a capo or a
+singer's range, and --speed to show the tempo slowed down or sped up for
+practice. Use --format csv to export a setlist to a spreadsheet.
+
+Each track's analysis is a separate request, so long lists take a while.`,
+	Args: cobra.MinimumNArgs(1),
+	Example: `  spotify-cli track tempo 4iV5W9uYEdYUVa79Axb7Rh
+  spotify-cli track tempo 4iV5W9uYEdYUVa79Axb7Rh 7ouMYWpwJ422jRcDASZB7P --transpose -2
+  spotify-cli track tempo spotify:track:4iV5W9uYEdYUVa79Axb7Rh --speed 75
+  spotify-cli track tempo 4iV5W9uYEdYUVa79Axb7Rh 7ouMYWpwJ422jRcDASZB7P --format csv > setlist.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTrackTempo(args)
+	},
+}
+
+func init() {
+	trackCmd.AddCommand(trackTempoCmd)
+
+	trackTempoCmd.Flags().StringVarP(&trackFormat, "format", "f", "table", "Output format (table, csv, json, yaml)")
+	trackTempoCmd.Flags().IntVarP(&trackTempoTranspose, "transpose", "t", 0, "Also show the key shifted by this many semitones (-11 to 11)")
+	trackTempoCmd.Flags().Float64Var(&trackTempoSpeed, "speed", 100, "Also show the tempo at this percentage of full speed")
+}
+
+// trackTempo is the tempo metadata of a track, flattened for CSV export
+type trackTempo struct {
+	ID                      string  `json:"id" yaml:"id"`
+	Name                    string  `json:"name" yaml:"name"`
+	Artists                 string  `json:"artists" yaml:"artists"`
+	BPM                     float64 `json:"bpm" yaml:"bpm"`
+	TempoConfidence         float64 `json:"tempo_confidence" yaml:"tempo_confidence"`
+	Key                     string  `json:"key" yaml:"key"`
+	Camelot                 string  `json:"camelot" yaml:"camelot"`
+	KeyConfidence           float64 `json:"key_confidence" yaml:"key_confidence"`
+	TimeSignature           string  `json:"time_signature" yaml:"time_signature"`
+	TimeSignatureConfidence float64 `json:"time_signature_confidence" yaml:"time_signature_confidence"`
+	PracticeBPM             float64 `json:"practice_bpm,omitempty" yaml:"practice_bpm,omitempty"`
+	TransposedKey           string  `json:"transposed_key,omitempty" yaml:"transposed_key,omitempty"`
+	TransposedCamelot       string  `json:"transposed_camelot,omitempty" yaml:"transposed_camelot,omitempty"`
+}
+
+func runTrackTempo(refs []string) error {
+	if trackTempoTranspose < -11 || trackTempoTranspose > 11 {
+		return fmt.Errorf("transpose must be between -11 and 11 semitones")
+	}
+	if trackTempoSpeed <= 0 {
+		return fmt.Errorf("speed must be a positive percentage")
+	}
+
+	ids, err := api.NewValidator().NormalizeAndValidateIDs(refs)
+	if err != nil {
+		return err
+	}
+
+	spotifyClient, err := newBrowseClient()
+	if err != nil {
+		return err
+	}
+
+	tracks, err := getTracksByID(spotifyClient, ids)
+	if err != nil {
+		return err
+	}
+
+	var rows []trackTempo
+	for _, track := range tracks {
+		utils.PrintVerbose("Analyzing %s", track.Name)
+
+		analysis, err := spotifyClient.Tracks.GetTrackAudioAnalysis(GetCommandContext(), track.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get audio analysis for %s: %w", track.Name, err)
+		}
+		rows = append(rows, newTrackTempo(track, analysis.Track))
+	}
+
+	return outputTrackTempo(rows)
+}
+
+func newTrackTempo(track models.Track, analysis models.AudioAnalysisTrack) trackTempo {
+	row := trackTempo{
+		ID:                      track.ID,
+		Name:                    track.Name,
+		Artists:                 utils.FormatSimpleArtists(track.Artists),
+		BPM:                     analysis.Tempo,
+		TempoConfidence:         analysis.TempoConfidence,
+		Key:                     models.KeyName(analysis.Key, analysis.Mode),
+		Camelot:                 models.CamelotKey(analysis.Key, analysis.Mode),
+		KeyConfidence:           analysis.KeyConfidence,
+		TimeSignature:           fmt.Sprintf("%d/4", analysis.TimeSignature),
+		TimeSignatureConfidence: analysis.TimeSignatureConfidence,
+	}
+
+	if trackTempoSpeed != 100 {
+		row.PracticeBPM = analysis.Tempo * trackTempoSpeed / 100
+	}
+	if trackTempoTranspose != 0 {
+		key := models.TransposeKey(analysis.Key, trackTempoTranspose)
+		row.TransposedKey = models.KeyName(key, analysis.Mode)
+		row.TransposedCamelot = models.CamelotKey(key, analysis.Mode)
+	}
+
+	return row
+}
+
+func outputTrackTempo(rows []trackTempo) error {
+	cfg := config.Get()
+
+	// Check output format priority: flag > global config > default
+	outputFormat := trackFormat
+	if outputFormat == "table" && (cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml") {
+		outputFormat = cfg.DefaultOutput
+	}
+
+	switch outputFormat {
+	case "json", "yaml":
+		return utils.Output(rows)
+	case "csv":
+		return writeTrackTempoCSV(rows)
+	}
+
+	// Columns for --speed and --transpose only when they change something
+	header := fmt.Sprintf("%-35s %-22s %7s %5s", "TRACK", "ARTIST", "BPM", "CONF")
+	if trackTempoSpeed != 100 {
+		header += fmt.Sprintf(" %8s", fmt.Sprintf("@%g%%", trackTempoSpeed))
+	}
+	header += fmt.Sprintf("  %-9s %-4s %5s", "KEY", "CAM", "CONF")
+	if trackTempoTranspose != 0 {
+		header += fmt.Sprintf("  %-14s", fmt.Sprintf("TRANSPOSE %+d", trackTempoTranspose))
+	}
+	header += fmt.Sprintf("  %-4s %s", "TIME", "CONF")
+
+	fmt.Println(header)
+	fmt.Println(strings.Repeat("-", len(header)))
+
+	for _, row := range rows {
+		key, camelot := row.Key, row.Camelot
+		if key == "" {
+			key, camelot = "-", "-"
+		}
+
+		line := fmt.Sprintf("%-35s %-22s %7.1f %5.2f",
+			truncateString(row.Name, 33), truncateString(row.Artists, 20), row.BPM, row.TempoConfidence)
+		if trackTempoSpeed != 100 {
+			line += fmt.Sprintf(" %8.1f", row.PracticeBPM)
+		}
+		line += fmt.Sprintf("  %-9s %-4s %5.2f", key, camelot, row.KeyConfidence)
+		if trackTempoTranspose != 0 {
+			transposed := "-"
+			if row.TransposedKey != "" {
+				transposed = row.TransposedKey + " " + row.TransposedCamelot
+			}
+			line += fmt.Sprintf("  %-14s", transposed)
+		}
+		line += fmt.Sprintf("  %-4s %4.2f", row.TimeSignature, row.TimeSignatureConfidence)
+
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+func writeTrackTempoCSV(rows []trackTempo) error {
+	w := csv.NewWriter(os.Stdout)
+
+	header := []string{"id", "name", "artists", "bpm", "tempo_confidence", "key", "camelot", "key_confidence", "time_signature", "time_signature_confidence"}
+	if trackTempoSpeed != 100 {
+		header = append(header, "practice_bpm")
+	}
+	if trackTempoTranspose != 0 {
+		header = append(header, "transposed_key", "transposed_camelot")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	formatFloat := func(v float64, precision int) string {
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	}
+	for _, row := range rows {
+		record := []string{
+			row.ID, row.Name, row.Artists,
+			formatFloat(row.BPM, 3), formatFloat(row.TempoConfidence, 3),
+			row.Key, row.Camelot, formatFloat(row.KeyConfidence, 3),
+			row.TimeSignature, formatFloat(row.TimeSignatureConfidence, 3),
+		}
+		if trackTempoSpeed != 100 {
+			record = append(record, formatFloat(row.PracticeBPM, 3))
+		}
+		if trackTempoTranspose != 0 {
+			record = append(record, row.TransposedKey, row.TransposedCamelot)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
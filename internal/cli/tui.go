@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/cli/client"
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/spotify"
+	"github.com/bambithedeer/spotify-api/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiInterval time.Duration
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen interactive player",
+	Long: `Open a full-screen terminal interface showing what is playing, with panes
+for the queue, your playlists and search below it.
+
+Keys:
+  space      Play or pause
+  n / →      Next track
+  p / ←      Previous track
+  + / -      Volume up or down
+  tab, 1-3   Switch pane
+  ↑↓ / j k   Move the selection
+  enter      Play the selected playlist or search result
+  /          Search for tracks (enter to search, esc to cancel)
+  a          Add the selected search result to the queue
+  r          Reload playlists and playback
+  q          Quit
+
+Requires user authentication and an active Spotify device. Supported on
+Linux and macOS terminals.`,
+	Example: `  spotify-cli tui
+  spotify-cli tui --interval 5s`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTUI()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().DurationVar(&tuiInterval, "interval", 2*time.Second, "How often to refresh the playback state")
+}
+
+func runTUI() error {
+	if !tui.Supported {
+		return tui.ErrUnsupported
+	}
+	if tuiInterval < time.Second {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+
+	spotifyClient, err := newUserClient("playback control")
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(GetCommandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app := tui.NewApp(tuiBackend{spotifyClient: spotifyClient})
+	return tui.Run(ctx, app, os.Stdin, os.Stdout, tuiInterval)
+}
+
+// tuiBackend adapts the Spotify client to the terminal interface, always
+// acting on the active device
+type tuiBackend struct {
+	spotifyClient *client.SpotifyClient
+}
+
+func (b tuiBackend) State(ctx context.Context) (*models.PlaybackState, error) {
+	return b.spotifyClient.Player.GetPlaybackState(ctx, "")
+}
+
+func (b tuiBackend) Queue(ctx context.Context) ([]models.PlaylistItem, error) {
+	queue, err := b.spotifyClient.Player.GetQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return queue.Queue, nil
+}
+
+func (b tuiBackend) Playlists(ctx context.Context) ([]models.Playlist, error) {
+	return b.spotifyClient.Playlists.UserPlaylistsPager(nil).All(ctx)
+}
+
+func (b tuiBackend) Search(ctx context.Context, query string) ([]models.Track, error) {
+	results, _, err := b.spotifyClient.Search.SearchTracks(ctx, query, &api.PaginationOptions{Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+	return results.Items, nil
+}
+
+func (b tuiBackend) Resume(ctx context.Context) error {
+	return b.spotifyClient.Player.Play(ctx, nil)
+}
+
+func (b tuiBackend) Pause(ctx context.Context) error {
+	return b.spotifyClient.Player.Pause(ctx, "")
+}
+
+func (b tuiBackend) Next(ctx context.Context) error {
+	return b.spotifyClient.Player.Next(ctx, "")
+}
+
+func (b tuiBackend) Previous(ctx context.Context) error {
+	return b.spotifyClient.Player.Previous(ctx, "")
+}
+
+func (b tuiBackend) SetVolume(ctx context.Context, percent int) error {
+	return b.spotifyClient.Player.SetVolume(ctx, percent, "")
+}
+
+func (b tuiBackend) PlayContext(ctx context.Context, uri string) error {
+	return b.spotifyClient.Player.Play(ctx, &spotify.PlayOptions{ContextURI: uri})
+}
+
+func (b tuiBackend) PlayTracks(ctx context.Context, uris []string) error {
+	return b.spotifyClient.Player.Play(ctx, &spotify.PlayOptions{URIs: uris})
+}
+
+func (b tuiBackend) Enqueue(ctx context.Context, uri string) error {
+	return b.spotifyClient.Player.AddToQueue(ctx, uri, "")
+}
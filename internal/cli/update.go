@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bambithedeer/spotify-api/internal/cli/config"
+	"github.com/bambithedeer/spotify-api/internal/cli/utils"
+	"github.com/bambithedeer/spotify-api/internal/selfupdate"
+	"github.com/bambithedeer/spotify-api/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateCheck         bool
+	updateVersion       string
+	updateSkipSignature bool
+	verifySkipSignature bool
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update spotify-cli to the latest release",
+	Long: `Download the latest release for this platform and replace the running
+binary with it.
+
+spotify-cli keeps your OAuth tokens, so a tampered binary could hand them
+to someone else. Every release publishes SHA-256 checksums of its binaries,
+signed with cosign by the release workflow of this repository. The
+signature is checked with 'cosign verify-blob' before the checksums are
+trusted, and the download is checked against them before it replaces
+anything. Install cosign to update; --skip-signature checks the checksums
+alone, which only guards against a corrupted download.`,
+	Example: `  spotify-cli update --check
+  spotify-cli update
+  spotify-cli update --version v1.4.0`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUpdate()
+	},
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the installed binary against the published checksums",
+	Long: `Check that the running spotify-cli binary is exactly the one published
+for its version, by comparing its SHA-256 checksum with the signed
+checksums of that release. See 'update --help' for how signatures are
+checked.`,
+	Example: `  spotify-cli verify
+  spotify-cli verify --skip-signature`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runVerify()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(verifyCmd)
+
+	updateCmd.Flags().BoolVar(&updateCheck, "check", false, "Only report whether an update is available")
+	updateCmd.Flags().StringVar(&updateVersion, "version", "", "Install this release instead of the latest, such as v1.4.0")
+	updateCmd.Flags().BoolVar(&updateSkipSignature, "skip-signature", false, "Do not verify the signature of the checksums (not recommended)")
+
+	verifyCmd.Flags().BoolVar(&verifySkipSignature, "skip-signature", false, "Do not verify the signature of the checksums")
+}
+
+func runUpdate() error {
+	ctx := GetCommandContext()
+	current := version.Get().Version
+
+	var release *selfupdate.Release
+	var err error
+	if updateVersion != "" {
+		release, err = selfupdate.ByTag(ctx, updateVersion)
+	} else {
+		release, err = selfupdate.Latest(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find release: %w", err)
+	}
+
+	if updateCheck {
+		cfg := config.Get()
+		if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
+			return utils.Output(map[string]interface{}{
+				"current":   current,
+				"latest":    release.Tag,
+				"available": release.Tag != current,
+				"url":       release.URL,
+			})
+		}
+		if release.Tag == current {
+			fmt.Printf("spotify-cli %s is up to date\n", current)
+		} else {
+			fmt.Printf("spotify-cli %s is available (installed: %s)\n%s\n", release.Tag, current, release.URL)
+		}
+		return nil
+	}
+
+	if release.Tag == current && updateVersion == "" {
+		fmt.Printf("spotify-cli %s is up to date\n", current)
+		return nil
+	}
+
+	name := selfupdate.CurrentAssetName()
+	asset, ok := release.Asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for this platform (%s)", release.Tag, name)
+	}
+
+	if updateSkipSignature {
+		utils.PrintWarning("Skipping signature verification; checking checksums only")
+	}
+	sums, err := selfupdate.Checksums(ctx, release, updateSkipSignature)
+	if err != nil {
+		return updateSignatureError(err)
+	}
+
+	utils.PrintVerbose("Downloading %s", asset.URL)
+	data, err := selfupdate.Download(ctx, asset.URL)
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.VerifyChecksum(sums, name, selfupdate.SHA256(data)); err != nil {
+		return err
+	}
+
+	exe, err := executablePath()
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Replace(exe, data); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+
+	utils.PrintSuccess("Updated spotify-cli %s to %s", current, release.Tag)
+	return nil
+}
+
+func runVerify() error {
+	current := version.Get().Version
+	if current == "dev" {
+		return fmt.Errorf("this is a development build; only release builds have published checksums")
+	}
+
+	exe, err := executablePath()
+	if err != nil {
+		return err
+	}
+	sum, err := selfupdate.FileSHA256(exe)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", exe, err)
+	}
+
+	ctx := GetCommandContext()
+	release, err := selfupdate.ByTag(ctx, current)
+	if err != nil {
+		return fmt.Errorf("failed to find release %s: %w", current, err)
+	}
+	if verifySkipSignature {
+		utils.PrintWarning("Skipping signature verification; checking checksums only")
+	}
+	sums, err := selfupdate.Checksums(ctx, release, verifySkipSignature)
+	if err != nil {
+		return updateSignatureError(err)
+	}
+
+	name := selfupdate.CurrentAssetName()
+	if err := selfupdate.VerifyChecksum(sums, name, sum); err != nil {
+		return fmt.Errorf("%s does not match release %s: %w", exe, current, err)
+	}
+
+	utils.PrintSuccess("%s matches the published %s of %s", exe, name, current)
+	if !verifySkipSignature {
+		fmt.Println("Checksums signed by the release workflow of " + selfupdate.Repository)
+	}
+	fmt.Printf("SHA-256: %s\n", sum)
+	return nil
+}
+
+// executablePath is the running binary, with symlinks resolved so an update
+// replaces the binary rather than the link to it
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the running binary: %w", err)
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+func updateSignatureError(err error) error {
+	if errors.Is(err, selfupdate.ErrNoCosign) {
+		return fmt.Errorf("%w\nInstall cosign to verify release signatures, or pass --skip-signature to check checksums only", err)
+	}
+	return err
+}
@@ -48,7 +48,10 @@ Client credentials authentication does not provide access to user data.`,
   spotify-cli user follow <artist-id> [artist-id...]
 
   # Check if following artists
-  spotify-cli user following <artist-id> [artist-id...]`,
+  spotify-cli user following <artist-id> [artist-id...]
+
+  # Follow other listeners
+  spotify-cli user follow-user <user-id> [user-id...]`,
 }
 
 var userProfileCmd = &cobra.Command{
@@ -97,7 +100,7 @@ You can provide multiple artist IDs to follow multiple artists at once (up to 50
 	Example: `  spotify-cli user follow 4Z8W4fKeB5YxbusRsdQVPb
   spotify-cli user follow artist1 artist2 artist3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUserFollow(args)
+		return runUserFollow(spotify.FollowTypeArtist, args)
 	},
 }
 
@@ -111,7 +114,7 @@ You can provide multiple artist IDs to unfollow multiple artists at once (up to
 	Example: `  spotify-cli user unfollow 4Z8W4fKeB5YxbusRsdQVPb
   spotify-cli user unfollow artist1 artist2 artist3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUserUnfollow(args)
+		return runUserUnfollow(spotify.FollowTypeArtist, args)
 	},
 }
 
@@ -125,7 +128,47 @@ You can check multiple artist IDs at once (up to 50).`,
 	Example: `  spotify-cli user following 4Z8W4fKeB5YxbusRsdQVPb
   spotify-cli user following artist1 artist2 artist3`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runUserFollowing(args)
+		return runUserFollowing(spotify.FollowTypeArtist, args)
+	},
+}
+
+var userFollowUserCmd = &cobra.Command{
+	Use:   "follow-user [user-id...]",
+	Short: "Follow users",
+	Long: `Follow one or more Spotify users.
+
+Users are given by user ID, spotify:user URI or profile URL, up to 50 at once.`,
+	Args: cobra.MinimumNArgs(1),
+	Example: `  spotify-cli user follow-user jmperezperez
+  spotify-cli user follow-user https://open.spotify.com/user/smedjan alice bob`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUserFollow(spotify.FollowTypeUser, args)
+	},
+}
+
+var userUnfollowUserCmd = &cobra.Command{
+	Use:   "unfollow-user [user-id...]",
+	Short: "Unfollow users",
+	Long: `Unfollow one or more Spotify users.
+
+Users are given by user ID, spotify:user URI or profile URL, up to 50 at once.`,
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  spotify-cli user unfollow-user jmperezperez alice`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUserUnfollow(spotify.FollowTypeUser, args)
+	},
+}
+
+var userFollowingUserCmd = &cobra.Command{
+	Use:   "following-user [user-id...]",
+	Short: "Check if following users",
+	Long: `Check if you are following one or more Spotify users.
+
+You can check multiple user IDs at once (up to 50).`,
+	Args:    cobra.MinimumNArgs(1),
+	Example: `  spotify-cli user following-user jmperezperez alice bob`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runUserFollowing(spotify.FollowTypeUser, args)
 	},
 }
 
@@ -155,6 +198,9 @@ func init() {
 	userCmd.AddCommand(userFollowCmd)
 	userCmd.AddCommand(userUnfollowCmd)
 	userCmd.AddCommand(userFollowingCmd)
+	userCmd.AddCommand(userFollowUserCmd)
+	userCmd.AddCommand(userUnfollowUserCmd)
+	userCmd.AddCommand(userFollowingUserCmd)
 	userCmd.AddCommand(userPlaylistsCmd)
 
 	// Add flags to list commands
@@ -261,7 +307,7 @@ func runUserTop(topType string) error {
 	}
 }
 
-func runUserFollow(artistIDs []string) error {
+func runUserFollow(followType string, ids []string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
 		return fmt.Errorf("failed to create Spotify client: %w", err)
@@ -274,23 +320,23 @@ func runUserFollow(artistIDs []string) error {
 	// Check if we're using client credentials (which don't have user scope access)
 	cfg := config.Get()
 	if cfg.RefreshToken == "" {
-		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to follow artists")
+		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to follow %ss", followType)
 	}
 
-	if len(artistIDs) > 50 {
-		return fmt.Errorf("cannot follow more than 50 artists at once")
+	if len(ids) > 50 {
+		return fmt.Errorf("cannot follow more than 50 %ss at once", followType)
 	}
 
-	err = spotifyClient.Users.FollowArtists(GetCommandContext(), artistIDs)
+	err = spotifyClient.Users.Follow(GetCommandContext(), followType, ids)
 	if err != nil {
-		return fmt.Errorf("failed to follow artists: %w", err)
+		return fmt.Errorf("failed to follow %ss: %w", followType, err)
 	}
 
-	utils.PrintSuccess(fmt.Sprintf("Successfully followed %d artist(s)", len(artistIDs)))
+	utils.PrintSuccess(fmt.Sprintf("Successfully followed %d %s(s)", len(ids), followType))
 	return nil
 }
 
-func runUserUnfollow(artistIDs []string) error {
+func runUserUnfollow(followType string, ids []string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
 		return fmt.Errorf("failed to create Spotify client: %w", err)
@@ -303,23 +349,23 @@ func runUserUnfollow(artistIDs []string) error {
 	// Check if we're using client credentials (which don't have user scope access)
 	cfg := config.Get()
 	if cfg.RefreshToken == "" {
-		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to unfollow artists")
+		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to unfollow %ss", followType)
 	}
 
-	if len(artistIDs) > 50 {
-		return fmt.Errorf("cannot unfollow more than 50 artists at once")
+	if len(ids) > 50 {
+		return fmt.Errorf("cannot unfollow more than 50 %ss at once", followType)
 	}
 
-	err = spotifyClient.Users.UnfollowArtists(GetCommandContext(), artistIDs)
+	err = spotifyClient.Users.Unfollow(GetCommandContext(), followType, ids)
 	if err != nil {
-		return fmt.Errorf("failed to unfollow artists: %w", err)
+		return fmt.Errorf("failed to unfollow %ss: %w", followType, err)
 	}
 
-	utils.PrintSuccess(fmt.Sprintf("Successfully unfollowed %d artist(s)", len(artistIDs)))
+	utils.PrintSuccess(fmt.Sprintf("Successfully unfollowed %d %s(s)", len(ids), followType))
 	return nil
 }
 
-func runUserFollowing(artistIDs []string) error {
+func runUserFollowing(followType string, ids []string) error {
 	spotifyClient, err := client.NewSpotifyClient()
 	if err != nil {
 		return fmt.Errorf("failed to create Spotify client: %w", err)
@@ -335,16 +381,16 @@ func runUserFollowing(artistIDs []string) error {
 		return fmt.Errorf("user authentication required. Client credentials only provide access to public data. Run 'spotify-cli auth login' to check following status")
 	}
 
-	if len(artistIDs) > 50 {
-		return fmt.Errorf("cannot check more than 50 artists at once")
+	if len(ids) > 50 {
+		return fmt.Errorf("cannot check more than 50 %ss at once", followType)
 	}
 
-	following, err := spotifyClient.Users.CheckFollowingArtists(GetCommandContext(), artistIDs)
+	following, err := spotifyClient.Users.CheckFollowing(GetCommandContext(), followType, ids)
 	if err != nil {
-		return fmt.Errorf("failed to check following artists: %w", err)
+		return fmt.Errorf("failed to check following %ss: %w", followType, err)
 	}
 
-	return outputFollowingResults(artistIDs, following)
+	return outputFollowingResults(followType, ids, following)
 }
 
 func runUserOwnPlaylists() error {
@@ -602,16 +648,16 @@ func outputTopArtists(artists *models.Paging[models.Artist], pagination *api.Pag
 	return nil
 }
 
-func outputFollowingResults(artistIDs []string, following []bool) error {
+func outputFollowingResults(followType string, ids []string, following []bool) error {
 	cfg := config.Get()
 
 	// For structured output
 	if cfg.DefaultOutput == "json" || cfg.DefaultOutput == "yaml" {
-		results := make([]map[string]interface{}, len(artistIDs))
-		for i, id := range artistIDs {
+		results := make([]map[string]interface{}, len(ids))
+		for i, id := range ids {
 			results[i] = map[string]interface{}{
-				"artist_id": id,
-				"following": following[i],
+				followType + "_id": id,
+				"following":        following[i],
 			}
 		}
 		return utils.Output(map[string]interface{}{
@@ -620,10 +666,10 @@ func outputFollowingResults(artistIDs []string, following []bool) error {
 	}
 
 	// Text output
-	fmt.Printf("Following Check Results - %d artist%s\n", len(artistIDs), pluralize(len(artistIDs)))
+	fmt.Printf("Following Check Results - %d %s%s\n", len(ids), followType, pluralize(len(ids)))
 	fmt.Println(strings.Repeat("-", 60))
 
-	for i, id := range artistIDs {
+	for i, id := range ids {
 		status := "❌ Not following"
 		if following[i] {
 			status = "✅ Following"
@@ -638,8 +684,8 @@ func outputFollowingResults(artistIDs []string, following []bool) error {
 			followingCount++
 		}
 	}
-	fmt.Printf("\nSummary: Following %d/%d artist%s\n",
-		followingCount, len(artistIDs), pluralize(len(artistIDs)))
+	fmt.Printf("\nSummary: Following %d/%d %s%s\n",
+		followingCount, len(ids), followType, pluralize(len(ids)))
 
 	return nil
 }
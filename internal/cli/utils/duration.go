@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/duration"
+)
+
+// FormatDuration formats a duration in milliseconds as M:SS, or as H:MM:SS
+// from an hour up; see duration.Format
+func FormatDuration(ms int) string {
+	return duration.Format(ms)
+}
+
+// ParseDuration reads a duration typed on the command line; see
+// duration.Parse
+func ParseDuration(s string) (time.Duration, error) {
+	return duration.Parse(s)
+}
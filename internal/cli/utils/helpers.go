@@ -9,12 +9,79 @@ import (
 	"github.com/bambithedeer/spotify-api/internal/models"
 )
 
-// FormatDuration formats a duration in milliseconds to MM:SS format
-func FormatDuration(ms int) string {
-	duration := time.Duration(ms) * time.Millisecond
-	minutes := int(duration.Minutes())
-	seconds := int(duration.Seconds()) % 60
-	return fmt.Sprintf("%d:%02d", minutes, seconds)
+// AbsoluteTimeLayout is how FormatTime writes absolute times
+const AbsoluteTimeLayout = "2006-01-02 15:04"
+
+// FormatTime formats a time in now's time zone, as how long ago it was
+// ("3 days ago") or, with absolute set, as a date and time
+func FormatTime(t, now time.Time, absolute bool) string {
+	t = t.In(now.Location())
+	if absolute {
+		return t.Format(AbsoluteTimeLayout)
+	}
+	return FormatRelativeTime(t, now)
+}
+
+// FormatTimestamp is FormatTime for the RFC 3339 times the API returns, such
+// as added_at and played_at. A value that does not parse is returned as is.
+func FormatTimestamp(value string, now time.Time, absolute bool) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return FormatTime(t, now, absolute)
+}
+
+// FormatRelativeTime describes how far t is from now in the largest unit
+// that fits. Days are counted by calendar date in now's time zone, so
+// yesterday evening is "yesterday" the next morning rather than "13 hours
+// ago".
+func FormatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	y1, m1, d1 := t.In(now.Location()).Date()
+	y2, m2, d2 := now.Date()
+	days := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	if days < 0 {
+		days = -days
+	}
+
+	var span string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		span = countUnit(int(d/time.Minute), "minute")
+	case days == 0 || d < 12*time.Hour:
+		span = countUnit(int(d/time.Hour), "hour")
+	case days == 1 && future:
+		return "tomorrow"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		span = countUnit(days, "day")
+	case days < 60:
+		span = countUnit(days/7, "week")
+	case days < 365:
+		span = countUnit(days/30, "month")
+	default:
+		span = countUnit(days/365, "year")
+	}
+	if future {
+		return "in " + span
+	}
+	return span + " ago"
+}
+
+func countUnit(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
 }
 
 // FormatArtists formats a list of artists as a comma-separated string
@@ -2,32 +2,11 @@ package utils
 
 import (
 	"testing"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/models"
 )
 
-func TestFormatDuration(t *testing.T) {
-	tests := []struct {
-		ms       int
-		expected string
-	}{
-		{0, "0:00"},
-		{1000, "0:01"},
-		{60000, "1:00"},
-		{90000, "1:30"},
-		{3661000, "61:01"},
-		{180000, "3:00"},
-		{240000, "4:00"},
-	}
-
-	for _, test := range tests {
-		result := FormatDuration(test.ms)
-		if result != test.expected {
-			t.Errorf("FormatDuration(%d) = %s, expected %s", test.ms, result, test.expected)
-		}
-	}
-}
-
 func TestFormatArtists(t *testing.T) {
 	artists := []models.Artist{
 		{Name: "Queen"},
@@ -208,3 +187,45 @@ func containsStringHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{now.Add(-20 * time.Second), "just now"},
+		{now.Add(-time.Minute), "1 minute ago"},
+		{now.Add(-45 * time.Minute), "45 minutes ago"},
+		{now.Add(-8 * time.Hour), "8 hours ago"},
+		// 11pm the night before is yesterday, though under a day ago
+		{time.Date(2024, 3, 14, 20, 0, 0, 0, time.UTC), "yesterday"},
+		{now.AddDate(0, 0, -3), "3 days ago"},
+		{now.AddDate(0, 0, -21), "3 weeks ago"},
+		{now.AddDate(0, -4, 0), "4 months ago"},
+		{now.AddDate(-2, 0, 0), "2 years ago"},
+		{now.Add(3 * time.Hour), "in 3 hours"},
+		{now.AddDate(0, 0, 1), "tomorrow"},
+	}
+
+	for _, test := range tests {
+		if result := FormatRelativeTime(test.t, now); result != test.expected {
+			t.Errorf("FormatRelativeTime(%v) = %q, expected %q", test.t, result, test.expected)
+		}
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, zone)
+
+	if result := FormatTimestamp("2024-03-12T07:00:00Z", now, false); result != "3 days ago" {
+		t.Errorf("Expected a relative time, got %q", result)
+	}
+	if result := FormatTimestamp("2024-03-14T23:30:00Z", now, true); result != "2024-03-15 01:30" {
+		t.Errorf("Expected the time in the local zone, got %q", result)
+	}
+	if result := FormatTimestamp("2024-03", now, false); result != "2024-03" {
+		t.Errorf("Expected an unparseable value to be kept, got %q", result)
+	}
+}
@@ -0,0 +1,355 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheablePrefixes lists the catalog endpoints whose responses are cached.
+// User data under /me, /users and /playlists changes through this client and
+// is never cached. Shows and episodes include the user's resume points, so a
+// disk cache must not be shared between accounts.
+var cacheablePrefixes = []string{
+	"/search",
+	"/albums",
+	"/artists",
+	"/tracks",
+	"/audio-features",
+	"/audio-analysis",
+	"/browse",
+	"/recommendations",
+	"/markets",
+	"/shows",
+	"/episodes",
+}
+
+// Cache stores HTTP responses keyed by request URL
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// CacheEntry is a stored response along with its validators
+type CacheEntry struct {
+	Key          string      `json:"key"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	Expires      time.Time   `json:"expires"`
+}
+
+// Fresh reports whether the entry can be served without revalidation
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// Revalidatable reports whether a conditional request can be sent for the entry
+func (e *CacheEntry) Revalidatable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// Response builds an HTTP response from the entry
+func (e *CacheEntry) Response(source string) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(CacheStatusHeader, source)
+
+	return &http.Response{
+		StatusCode:    e.StatusCode,
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+	}
+}
+
+// CacheStatusHeader is set on responses served from the cache
+const CacheStatusHeader = "X-Spotify-Cli-Cache"
+
+// Cache status values reported in CacheStatusHeader
+const (
+	CacheHit         = "hit"
+	CacheRevalidated = "revalidated"
+)
+
+// cacheControl holds the Cache-Control directives the client honours
+type cacheControl struct {
+	noStore bool
+	noCache bool
+	maxAge  time.Duration
+	hasAge  bool
+}
+
+func parseCacheControl(value string) cacheControl {
+	var cc cacheControl
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store":
+			cc.noStore = true
+		case directive == "no-cache":
+			cc.noCache = true
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && seconds >= 0 {
+				cc.maxAge = time.Duration(seconds) * time.Second
+				cc.hasAge = true
+			}
+		}
+	}
+	return cc
+}
+
+// expiresAt returns when a response stored at now stops being fresh. Responses
+// without freshness information stay fresh for fallbackTTL.
+func (cc cacheControl) expiresAt(now time.Time, fallbackTTL time.Duration) time.Time {
+	switch {
+	case cc.noCache:
+		return now
+	case cc.hasAge:
+		return now.Add(cc.maxAge)
+	default:
+		return now.Add(fallbackTTL)
+	}
+}
+
+// isCacheable reports whether a request may be served from the cache
+func isCacheable(method, endpoint string) bool {
+	if method != http.MethodGet {
+		return false
+	}
+
+	for _, prefix := range cacheablePrefixes {
+		if endpoint == prefix || strings.HasPrefix(endpoint, prefix+"/") || strings.HasPrefix(endpoint, prefix+"?") {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryCache is an in-process LRU cache
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+}
+
+// NewMemoryCache creates an LRU cache holding at most maxEntries responses
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 500
+	}
+
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns the entry for key
+func (mc *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	element, ok := mc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	mc.order.MoveToFront(element)
+	return element.Value.(*CacheEntry), true
+}
+
+// Set stores entry under key, evicting the least recently used entry when full
+func (mc *MemoryCache) Set(key string, entry *CacheEntry) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		element.Value = entry
+		mc.order.MoveToFront(element)
+		return
+	}
+
+	mc.entries[key] = mc.order.PushFront(entry)
+
+	for mc.order.Len() > mc.maxEntries {
+		oldest := mc.order.Back()
+		mc.order.Remove(oldest)
+		delete(mc.entries, oldest.Value.(*CacheEntry).Key)
+	}
+}
+
+// Delete removes the entry for key
+func (mc *MemoryCache) Delete(key string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if element, ok := mc.entries[key]; ok {
+		mc.order.Remove(element)
+		delete(mc.entries, key)
+	}
+}
+
+// Len returns the number of cached entries
+func (mc *MemoryCache) Len() int {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	return mc.order.Len()
+}
+
+// DiskCache stores responses as JSON files in a directory so they survive
+// between CLI invocations
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a cache in dir, creating the directory if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Dir returns the cache directory
+func (dc *DiskCache) Dir() string {
+	return dc.dir
+}
+
+// Get returns the entry for key. Unreadable entries are treated as misses.
+func (dc *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(dc.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Set stores entry under key. Write failures are ignored; the cache is only
+// an optimisation.
+func (dc *DiskCache) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(dc.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmp.Name(), dc.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes the entry for key
+func (dc *DiskCache) Delete(key string) {
+	os.Remove(dc.path(key))
+}
+
+// Clear removes every cached entry and returns how many were removed
+func (dc *DiskCache) Clear() (int, error) {
+	files, err := filepath.Glob(filepath.Join(dc.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if err := os.Remove(file); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Stats returns the number of entries and their combined size in bytes
+func (dc *DiskCache) Stats() (entries int, size int64, err error) {
+	files, err := filepath.Glob(filepath.Join(dc.dir, "*.json"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			entries++
+			size += info.Size()
+		}
+	}
+	return entries, size, nil
+}
+
+func (dc *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// LayeredCache checks a fast cache before a slower, persistent one
+type LayeredCache struct {
+	front Cache
+	back  Cache
+}
+
+// NewLayeredCache combines front (e.g. memory) and back (e.g. disk) caches
+func NewLayeredCache(front, back Cache) *LayeredCache {
+	return &LayeredCache{front: front, back: back}
+}
+
+// Get returns the entry from the front cache, falling back to the back cache
+func (lc *LayeredCache) Get(key string) (*CacheEntry, bool) {
+	if entry, ok := lc.front.Get(key); ok {
+		return entry, true
+	}
+
+	entry, ok := lc.back.Get(key)
+	if ok {
+		lc.front.Set(key, entry)
+	}
+	return entry, ok
+}
+
+// Set stores the entry in both caches
+func (lc *LayeredCache) Set(key string, entry *CacheEntry) {
+	lc.front.Set(key, entry)
+	lc.back.Set(key, entry)
+}
+
+// Delete removes the entry from both caches
+func (lc *LayeredCache) Delete(key string) {
+	lc.front.Delete(key)
+	lc.back.Delete(key)
+}
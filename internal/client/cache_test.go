@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Eviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", &CacheEntry{Key: "a"})
+	cache.Set("b", &CacheEntry{Key: "b"})
+	cache.Get("a") // a is now most recently used
+	cache.Set("c", &CacheEntry{Key: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Expected recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create disk cache: %v", err)
+	}
+
+	entry := &CacheEntry{Key: "/albums/1", StatusCode: 200, Body: []byte(`{"id":"1"}`), ETag: `"v1"`}
+	cache.Set(entry.Key, entry)
+
+	loaded, ok := cache.Get("/albums/1")
+	if !ok {
+		t.Fatal("Expected entry to be read back from disk")
+	}
+	if string(loaded.Body) != `{"id":"1"}` || loaded.ETag != `"v1"` {
+		t.Errorf("Unexpected entry: %+v", loaded)
+	}
+
+	if entries, _, _ := cache.Stats(); entries != 1 {
+		t.Errorf("Expected 1 entry, got %d", entries)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil || removed != 1 {
+		t.Errorf("Expected 1 entry to be cleared, got %d (err=%v)", removed, err)
+	}
+	if _, ok := cache.Get("/albums/1"); ok {
+		t.Error("Expected entry to be gone after Clear")
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	now := time.Now()
+
+	cc := parseCacheControl("public, max-age=300")
+	if !cc.expiresAt(now, time.Hour).Equal(now.Add(5 * time.Minute)) {
+		t.Error("Expected max-age to set expiry")
+	}
+
+	if cc := parseCacheControl("no-cache"); !cc.expiresAt(now, time.Hour).Equal(now) {
+		t.Error("Expected no-cache to require revalidation")
+	}
+
+	if cc := parseCacheControl("private, no-store"); !cc.noStore {
+		t.Error("Expected no-store to be parsed")
+	}
+
+	if cc := parseCacheControl(""); !cc.expiresAt(now, time.Hour).Equal(now.Add(time.Hour)) {
+		t.Error("Expected fallback TTL without Cache-Control")
+	}
+}
+
+func TestIsCacheable(t *testing.T) {
+	tests := []struct {
+		method   string
+		endpoint string
+		expected bool
+	}{
+		{"GET", "/search?q=radiohead&type=artist", true},
+		{"GET", "/albums/123", true},
+		{"GET", "/artists/123/top-tracks?market=US", true},
+		{"GET", "/me/tracks", false},
+		{"GET", "/playlists/123", false},
+		{"GET", "/albumsx", false},
+		{"PUT", "/albums/123", false},
+	}
+
+	for _, tt := range tests {
+		if result := isCacheable(tt.method, tt.endpoint); result != tt.expected {
+			t.Errorf("isCacheable(%s, %s) = %v, expected %v", tt.method, tt.endpoint, result, tt.expected)
+		}
+	}
+}
+
+func TestMakeRequest_CacheRevalidation(t *testing.T) {
+	requests := 0
+	conditional := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.Header().Set("Cache-Control", "max-age=0")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "max-age=0")
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithCache(NewMemoryCache(10), time.Hour))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(context.Background(), "/albums/1")
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i+1, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || string(body) != `{"id":"1"}` {
+			t.Errorf("Request %d: expected cached body with status 200, got %d %q", i+1, resp.StatusCode, body)
+		}
+		if i == 1 && resp.Header.Get(CacheStatusHeader) != CacheRevalidated {
+			t.Errorf("Expected second response to be revalidated, got %q", resp.Header.Get(CacheStatusHeader))
+		}
+	}
+
+	if requests != 2 || conditional != 1 {
+		t.Errorf("Expected 2 requests with 1 conditional, got %d and %d", requests, conditional)
+	}
+
+	stats := client.CacheStats()
+	if stats.Misses != 1 || stats.Revalidated != 1 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestMakeRequest_CacheHit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Write([]byte(`{"tracks":[]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithCache(NewMemoryCache(10), time.Hour))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(context.Background(), "/artists/1/top-tracks?market=US")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	// User data must always go to the API
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(context.Background(), "/me/tracks")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if requests != 3 {
+		t.Errorf("Expected 1 catalog request and 2 library requests, got %d", requests)
+	}
+	if stats := client.CacheStats(); stats.Hits != 2 {
+		t.Errorf("Expected 2 cache hits, got %+v", stats)
+	}
+}
@@ -1,14 +1,18 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/auth"
 	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
 	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
@@ -25,11 +29,124 @@ type Client struct {
 	baseURL     string
 	rateLimiter *ratelimit.RateLimiter
 	retryConfig *ratelimit.RetryConfig
+	onRetry     func(RetryEvent)
+	onWrite     func(WriteEvent)
+	cache       Cache
+	cacheTTL    time.Duration
+	tokenStore  auth.TokenStore
+
+	statsMu    sync.Mutex
+	stats      RateLimitStats
+	cacheStats CacheStats
+}
+
+// Option customizes a Client at construction time
+type Option func(*Client)
+
+// WithRetryConfig sets the retry policy used for failed and rate-limited requests
+func WithRetryConfig(config *ratelimit.RetryConfig) Option {
+	return func(c *Client) {
+		c.retryConfig = config
+	}
+}
+
+// WithRateLimiter sets the client-side rate limiter
+func WithRateLimiter(rl *ratelimit.RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = rl
+	}
+}
+
+// WithRetryObserver registers a callback invoked before every retry, e.g. to
+// tell the user the client is backing off
+func WithRetryObserver(fn func(RetryEvent)) Option {
+	return func(c *Client) {
+		c.onRetry = fn
+	}
+}
+
+// WithWriteObserver registers a callback invoked after every request that
+// can change data, i.e. any method other than GET
+func WithWriteObserver(fn func(WriteEvent)) Option {
+	return func(c *Client) {
+		c.onWrite = fn
+	}
+}
+
+// WithCache enables response caching for catalog endpoints. Responses that
+// carry no Cache-Control freshness information stay fresh for fallbackTTL.
+func WithCache(cache Cache, fallbackTTL time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = fallbackTTL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API and token requests. On
+// js/wasm the default client already sends requests with the browser's fetch
+// API; this is for custom transports, proxies and tests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+		c.authClient.SetHTTPClient(httpClient)
+	}
+}
+
+// WithTokenStore loads the client's token from store and saves new tokens to
+// it after authenticating and refreshing
+func WithTokenStore(store auth.TokenStore) Option {
+	return func(c *Client) {
+		c.tokenStore = store
+	}
+}
+
+// RetryEvent describes a request that is about to be retried
+type RetryEvent struct {
+	Method   string
+	Endpoint string
+	// Attempt is the number of the upcoming retry, starting at 1
+	Attempt    int
+	MaxRetries int
+	Delay      time.Duration
+	// StatusCode is zero when the previous attempt failed with a network error
+	StatusCode  int
+	RateLimited bool
+	Err         error
+}
+
+// WriteEvent describes a completed write request. The bodies are copies; the
+// caller still receives the full response.
+type WriteEvent struct {
+	Method       string
+	Endpoint     string
+	RequestBody  []byte
+	StatusCode   int // zero when no response was received
+	ResponseBody []byte
+	Err          error
+}
+
+// RateLimitStats records how often the client was throttled
+type RateLimitStats struct {
+	Requests       int           // requests sent to the API, including retries
+	RateLimited    int           // 429 responses received
+	Retries        int           // retries performed for any reason
+	TotalBackoff   time.Duration // total time spent waiting before retries
+	LastRateLimit  time.Time
+	RetryAfter     time.Time // when the current rate limit cooldown ends
+	AvailableBurst int       // requests that can be sent before the limiter blocks
+	MaxBurst       int
+}
+
+// CacheStats counts how requests were served by the response cache
+type CacheStats struct {
+	Hits        int // served from the cache without a request
+	Revalidated int // served from the cache after a 304 Not Modified
+	Misses      int // fetched from the API
 }
 
 // NewClient creates a new Spotify API client
-func NewClient(clientID, clientSecret, redirectURI string) *Client {
-	return &Client{
+func NewClient(clientID, clientSecret, redirectURI string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
@@ -38,6 +155,20 @@ func NewClient(clientID, clientSecret, redirectURI string) *Client {
 		rateLimiter: ratelimit.NewRateLimiter(),
 		retryConfig: ratelimit.DefaultRetryConfig(),
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// A store that cannot be read leaves the client unauthenticated, which
+	// is reported on the first request
+	if c.tokenStore != nil {
+		if token, err := c.tokenStore.Load(); err == nil {
+			c.token = token
+		}
+	}
+
+	return c
 }
 
 // AuthenticateClientCredentials authenticates using client credentials flow
@@ -49,14 +180,30 @@ func (c *Client) AuthenticateClientCredentials() error {
 	}
 
 	c.token = token
-	return nil
+	return c.saveToken()
 }
 
-// SetToken sets the access token (for when user has already authenticated)
+// SetToken sets the access token (for when user has already authenticated).
+// It is not saved to the token store; use SaveToken for that.
 func (c *Client) SetToken(token *auth.Token) {
 	c.token = token
 }
 
+// SaveToken saves the current token to the token store, if there is one
+func (c *Client) SaveToken() error {
+	return c.saveToken()
+}
+
+func (c *Client) saveToken() error {
+	if c.tokenStore == nil || c.token == nil {
+		return nil
+	}
+	if err := c.tokenStore.Save(c.token); err != nil {
+		return errors.WrapAuthError(err, "failed to save token")
+	}
+	return nil
+}
+
 // GetToken returns the current token
 func (c *Client) GetToken() *auth.Token {
 	return c.token
@@ -82,36 +229,76 @@ func (c *Client) RefreshTokenIfNeeded() error {
 	}
 
 	c.token = newToken
-	return nil
+	return c.saveToken()
 }
 
 // Get performs a GET request to the Spotify API
 func (c *Client) Get(ctx context.Context, endpoint string) (*http.Response, error) {
-	return c.makeRequest(ctx, "GET", endpoint, nil)
+	return c.makeRequest(ctx, "GET", endpoint, nil, nil)
 }
 
 // Post performs a POST request to the Spotify API
 func (c *Client) Post(ctx context.Context, endpoint string, body io.Reader) (*http.Response, error) {
-	return c.makeRequest(ctx, "POST", endpoint, body)
+	return c.makeRequest(ctx, "POST", endpoint, body, nil)
 }
 
 // Put performs a PUT request to the Spotify API
 func (c *Client) Put(ctx context.Context, endpoint string, body io.Reader) (*http.Response, error) {
-	return c.makeRequest(ctx, "PUT", endpoint, body)
+	return c.makeRequest(ctx, "PUT", endpoint, body, nil)
 }
 
 // Delete performs a DELETE request to the Spotify API
 func (c *Client) Delete(ctx context.Context, endpoint string) (*http.Response, error) {
-	return c.makeRequest(ctx, "DELETE", endpoint, nil)
+	return c.makeRequest(ctx, "DELETE", endpoint, nil, nil)
 }
 
 // DeleteWithBody performs a DELETE request with body to the Spotify API
 func (c *Client) DeleteWithBody(ctx context.Context, endpoint string, body io.Reader) (*http.Response, error) {
-	return c.makeRequest(ctx, "DELETE", endpoint, body)
+	return c.makeRequest(ctx, "DELETE", endpoint, body, nil)
+}
+
+// PutWithContentType performs a PUT request whose body is not JSON, such as
+// an image upload
+func (c *Client) PutWithContentType(ctx context.Context, endpoint string, body io.Reader, contentType string) (*http.Response, error) {
+	return c.makeRequest(ctx, "PUT", endpoint, body, http.Header{"Content-Type": []string{contentType}})
 }
 
-// makeRequest is the internal method that handles all HTTP requests with rate limiting and retries
-func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+// makeRequest is the internal method that handles all HTTP requests, reporting
+// writes to the write observer. header overrides the default request headers.
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
+	if c.onWrite == nil || method == http.MethodGet {
+		return c.doRequest(ctx, method, endpoint, body, header)
+	}
+
+	event := WriteEvent{Method: method, Endpoint: endpoint}
+	if body != nil {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, errors.WrapValidationError(err, "failed to read request body")
+		}
+		event.RequestBody = data
+		body = bytes.NewReader(data)
+	}
+
+	resp, err := c.doRequest(ctx, method, endpoint, body, header)
+	event.Err = err
+	if resp != nil {
+		data, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return nil, errors.WrapNetworkError(readErr, "failed to read response")
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		event.StatusCode = resp.StatusCode
+		event.ResponseBody = data
+	}
+
+	c.onWrite(event)
+	return resp, err
+}
+
+// doRequest sends a request with rate limiting, retries and caching
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
 	// Ensure we have a valid token
 	if err := c.RefreshTokenIfNeeded(); err != nil {
 		return nil, err
@@ -121,6 +308,43 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 		return nil, errors.NewAuthError("not authenticated")
 	}
 
+	// Serve fresh catalog responses from the cache, and revalidate stale ones
+	var cacheKey string
+	var cached *CacheEntry
+	// Copied so the conditional headers below stay out of the caller's header
+	requestHeader := header.Clone()
+	if c.cache != nil && isCacheable(method, endpoint) {
+		cacheKey = c.baseURL + endpoint
+		if entry, ok := c.cache.Get(cacheKey); ok {
+			if entry.Fresh(time.Now()) {
+				c.recordCache(CacheHit)
+				return entry.Response(CacheHit), nil
+			}
+			if entry.Revalidatable() {
+				cached = entry
+				if requestHeader == nil {
+					requestHeader = make(http.Header)
+				}
+				if entry.ETag != "" {
+					requestHeader.Set("If-None-Match", entry.ETag)
+				}
+				if entry.LastModified != "" {
+					requestHeader.Set("If-Modified-Since", entry.LastModified)
+				}
+			}
+		}
+	}
+
+	// Buffer the body so every attempt sends the full payload
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = io.ReadAll(body)
+		if err != nil {
+			return nil, errors.WrapValidationError(err, "failed to read request body")
+		}
+	}
+
 	// Implement retry logic with exponential backoff
 	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
 		// Wait for rate limiter
@@ -128,75 +352,154 @@ func (c *Client) makeRequest(ctx context.Context, method, endpoint string, body
 			return nil, errors.WrapNetworkError(err, "rate limiter wait failed")
 		}
 
-		// Create a new request for each attempt (body might need to be read multiple times)
 		var requestBody io.Reader
-		if body != nil {
-			// For retry attempts, we need a fresh body reader
-			// This is a limitation - callers should pass seekable readers for retries
-			requestBody = body
+		if bodyBytes != nil {
+			requestBody = bytes.NewReader(bodyBytes)
 		}
 
-		resp, err := c.executeRequest(ctx, method, endpoint, requestBody)
+		c.recordRequest()
+		resp, err := c.executeRequest(ctx, method, endpoint, requestBody, requestHeader)
 
 		// If request succeeded or context was cancelled, return immediately
 		if err != nil {
 			if ctx.Err() != nil {
 				return nil, ctx.Err()
 			}
-			// Network error - should retry
-			if attempt < c.retryConfig.MaxRetries {
-				delay := c.retryConfig.GetRetryDelay(attempt, nil)
-				select {
-				case <-time.After(delay):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
+			// Network errors should be retried, auth errors should not
+			if errors.IsNetworkError(err) && attempt < c.retryConfig.MaxRetries {
+				delay := c.retryConfig.NextDelay(attempt, nil)
+				c.notifyRetry(RetryEvent{Method: method, Endpoint: endpoint, Attempt: attempt + 1, MaxRetries: c.retryConfig.MaxRetries, Delay: delay, Err: err})
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, err
 				}
+				continue
 			}
 			return nil, err
 		}
 
 		// Handle rate limiting
 		if resp.StatusCode == http.StatusTooManyRequests {
-			if err := c.rateLimiter.HandleRateLimitResponse(resp); err != nil {
-				// If this is the last attempt, return the error
-				if !c.retryConfig.ShouldRetry(resp, attempt) {
-					resp.Body.Close()
-					return nil, err
-				}
-				// Otherwise, wait and retry
-				resp.Body.Close()
-				delay := c.retryConfig.GetRetryDelay(attempt, resp)
-				select {
-				case <-time.After(delay):
-					continue
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				}
+			c.recordRateLimit()
+			rateLimitErr := c.rateLimiter.HandleRateLimitResponse(resp)
+			resp.Body.Close()
+
+			if c.retryConfig.ExceedsMaxRetryAfter(resp) {
+				retryAfter, _ := c.retryConfig.RetryAfter(resp)
+				return nil, errors.NewStatusError(http.StatusTooManyRequests, fmt.Sprintf("rate limited: Spotify asked to retry in %s, which exceeds the %s limit", retryAfter.Round(time.Second), c.retryConfig.MaxRetryAfter))
+			}
+			if !c.retryConfig.ShouldRetry(resp, attempt) {
+				return nil, rateLimitErr
+			}
+
+			// The limiter holds further requests until its cooldown ends, so
+			// report whichever wait is longer
+			delay := c.retryConfig.NextDelay(attempt, resp)
+			if _, _, cooldown := c.rateLimiter.GetStatus(); time.Until(cooldown) > delay {
+				delay = time.Until(cooldown)
+			}
+			c.notifyRetry(RetryEvent{Method: method, Endpoint: endpoint, Attempt: attempt + 1, MaxRetries: c.retryConfig.MaxRetries, Delay: delay, StatusCode: resp.StatusCode, RateLimited: true})
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
 		// Check if we should retry based on status code
 		if c.retryConfig.ShouldRetry(resp, attempt) {
 			resp.Body.Close()
-			delay := c.retryConfig.GetRetryDelay(attempt, resp)
-			select {
-			case <-time.After(delay):
-				continue
-			case <-ctx.Done():
-				return nil, ctx.Err()
+			delay := c.retryConfig.NextDelay(attempt, resp)
+			c.notifyRetry(RetryEvent{Method: method, Endpoint: endpoint, Attempt: attempt + 1, MaxRetries: c.retryConfig.MaxRetries, Delay: delay, StatusCode: resp.StatusCode})
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
 		// Request succeeded, return response
+		if cacheKey != "" {
+			return c.cacheResponse(cacheKey, cached, resp)
+		}
 		return resp, nil
 	}
 
 	return nil, errors.NewAPIError("max retries exceeded")
 }
 
+// cacheResponse stores a successful response, or turns a 304 Not Modified
+// into the cached response it validated
+func (c *Client) cacheResponse(key string, cached *CacheEntry, resp *http.Response) (*http.Response, error) {
+	now := time.Now()
+	cc := parseCacheControl(resp.Header.Get("Cache-Control"))
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		resp.Body.Close()
+		cached.Expires = cc.expiresAt(now, c.cacheTTL)
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			cached.ETag = etag
+		}
+		c.cache.Set(key, cached)
+		c.recordCache(CacheRevalidated)
+		return cached.Response(CacheRevalidated), nil
+	}
+
+	c.recordCache("")
+	if resp.StatusCode != http.StatusOK || cc.noStore {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.WrapNetworkError(err, "failed to read response body")
+	}
+
+	entry := &CacheEntry{
+		Key:          key,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     now,
+		Expires:      cc.expiresAt(now, c.cacheTTL),
+	}
+	if entry.Fresh(now) || entry.Revalidatable() {
+		c.cache.Set(key, entry)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (c *Client) recordCache(status string) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	switch status {
+	case CacheHit:
+		c.cacheStats.Hits++
+	case CacheRevalidated:
+		c.cacheStats.Revalidated++
+	default:
+		c.cacheStats.Misses++
+	}
+}
+
+// sleepContext waits for delay or until ctx is cancelled
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // executeRequest performs a single HTTP request without retry logic
-func (c *Client) executeRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+func (c *Client) executeRequest(ctx context.Context, method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
 	// Build the full URL
 	url := c.baseURL + endpoint
 
@@ -209,6 +512,9 @@ func (c *Client) executeRequest(ctx context.Context, method, endpoint string, bo
 	// Add authentication header
 	req.Header.Set("Authorization", fmt.Sprintf("%s %s", c.token.TokenType, c.token.AccessToken))
 	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
 
 	// Make the request
 	resp, err := c.httpClient.Do(req)
@@ -222,13 +528,28 @@ func (c *Client) executeRequest(ctx context.Context, method, endpoint string, bo
 		resp.Body.Close()
 		return nil, errors.NewAuthError("unauthorized - token may be invalid")
 	case http.StatusForbidden:
-		resp.Body.Close()
-		return nil, errors.NewAuthError("forbidden - insufficient permissions")
+		return nil, forbiddenError(resp)
 	}
 
 	return resp, nil
 }
 
+// forbiddenError describes a 403. Spotify sends one both for a missing scope
+// and for a player command the device refuses, such as volume on a Cast
+// device, and says which in the body.
+func forbiddenError(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	message := "insufficient permissions"
+	var errorResp models.ErrorResponse
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(body, &errorResp) == nil && errorResp.Error.Message != "" {
+		message = errorResp.Error.Message
+	}
+
+	return fmt.Errorf("%w: %w - %s", errors.ErrAuth, errors.ErrForbidden, message)
+}
+
 // GetAuthorizationURL returns the authorization URL for user authentication
 func (c *Client) GetAuthorizationURL(scopes []string, state string) string {
 	return c.authClient.GetAuthorizationURL(scopes, state)
@@ -255,6 +576,64 @@ func (c *Client) SetRetryConfig(config *ratelimit.RetryConfig) {
 	c.retryConfig = config
 }
 
+// SetCache enables or, when cache is nil, disables response caching
+func (c *Client) SetCache(cache Cache, fallbackTTL time.Duration) {
+	c.cache = cache
+	c.cacheTTL = fallbackTTL
+}
+
+// CacheStats returns how many requests were served by the response cache
+func (c *Client) CacheStats() CacheStats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	return c.cacheStats
+}
+
+// SetRetryObserver sets the callback invoked before every retry
+func (c *Client) SetRetryObserver(fn func(RetryEvent)) {
+	c.onRetry = fn
+}
+
+// SetWriteObserver sets the callback invoked after every write request
+func (c *Client) SetWriteObserver(fn func(WriteEvent)) {
+	c.onWrite = fn
+}
+
+// RateLimitStats returns retry and throttling counters along with the current
+// rate limiter state
+func (c *Client) RateLimitStats() RateLimitStats {
+	c.statsMu.Lock()
+	stats := c.stats
+	c.statsMu.Unlock()
+
+	stats.AvailableBurst, stats.MaxBurst, stats.RetryAfter = c.rateLimiter.GetStatus()
+	return stats
+}
+
+func (c *Client) recordRequest() {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	c.stats.Requests++
+}
+
+func (c *Client) recordRateLimit() {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	c.stats.RateLimited++
+	c.stats.LastRateLimit = time.Now()
+}
+
+func (c *Client) notifyRetry(event RetryEvent) {
+	c.statsMu.Lock()
+	c.stats.Retries++
+	c.stats.TotalBackoff += event.Delay
+	c.statsMu.Unlock()
+
+	if c.onRetry != nil {
+		c.onRetry(event)
+	}
+}
+
 // GetRateLimiterStatus returns the current rate limiter status
 func (c *Client) GetRateLimiterStatus() (availableTokens int, maxTokens int, retryAfter time.Time) {
 	return c.rateLimiter.GetStatus()
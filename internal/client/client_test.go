@@ -2,12 +2,16 @@ package client
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/auth"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
 func TestNewClient(t *testing.T) {
@@ -127,4 +131,247 @@ func TestGetAuthorizationURL(t *testing.T) {
 	if url == "" {
 		t.Error("Expected authorization URL to be returned")
 	}
-}
\ No newline at end of file
+}
+func newTestClient(serverURL string, opts ...Option) *Client {
+	client := NewClient("test_id", "test_secret", "http://localhost:8080/callback", opts...)
+	client.baseURL = serverURL
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	return client
+}
+
+func fastRetryConfig() *ratelimit.RetryConfig {
+	config := ratelimit.DefaultRetryConfig()
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 10 * time.Millisecond
+	config.MaxRetryAfter = 2 * time.Second
+	return config
+}
+
+func TestMakeRequest_RateLimitRetry(t *testing.T) {
+	requests := 0
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var events []RetryEvent
+	client := newTestClient(server.URL,
+		WithRetryConfig(fastRetryConfig()),
+		WithRetryObserver(func(event RetryEvent) { events = append(events, event) }),
+	)
+
+	resp, err := client.Post(context.Background(), "/test", strings.NewReader(`{"uris":["a"]}`))
+	if err != nil {
+		t.Fatalf("Expected request to succeed after retry, got %v", err)
+	}
+	resp.Body.Close()
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	for i, body := range bodies {
+		if body != `{"uris":["a"]}` {
+			t.Errorf("Expected request %d to resend the full body, got %q", i+1, body)
+		}
+	}
+
+	if len(events) != 1 || !events[0].RateLimited || events[0].Attempt != 1 || events[0].StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected one rate-limited retry event, got %+v", events)
+	}
+
+	stats := client.RateLimitStats()
+	if stats.Requests != 2 || stats.RateLimited != 1 || stats.Retries != 1 {
+		t.Errorf("Expected stats to record the rate limit, got %+v", stats)
+	}
+}
+
+func TestMakeRequest_RetryAfterTooLong(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithRetryConfig(fastRetryConfig()))
+
+	_, err := client.Get(context.Background(), "/test")
+	if err == nil {
+		t.Fatal("Expected error when Retry-After exceeds the configured limit")
+	}
+	if !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("Expected rate limit error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no retries, got %d requests", requests)
+	}
+}
+
+func TestMakeRequest_MaxRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := fastRetryConfig()
+	config.MaxRetries = 2
+	client := newTestClient(server.URL, WithRetryConfig(config))
+
+	resp, err := client.Get(context.Background(), "/test")
+	if err != nil {
+		t.Fatalf("Expected final response to be returned, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", resp.StatusCode)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests (1 + 2 retries), got %d", requests)
+	}
+}
+
+func TestMakeRequest_NoRetryOnUnauthorized(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithRetryConfig(fastRetryConfig()))
+
+	if _, err := client.Get(context.Background(), "/test"); err == nil {
+		t.Fatal("Expected error for unauthorized response")
+	}
+	if requests != 1 {
+		t.Errorf("Expected unauthorized responses not to be retried, got %d requests", requests)
+	}
+}
+
+func TestMakeRequest_Forbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"status": 403, "message": "Player command failed: Restriction violated"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, WithRetryConfig(fastRetryConfig()))
+
+	_, err := client.Put(context.Background(), "/me/player/volume", nil)
+	if !errors.IsForbidden(err) || !errors.IsAuthError(err) {
+		t.Fatalf("Expected a forbidden auth error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Restriction violated") {
+		t.Errorf("Expected the API's reason in the error, got %q", err.Error())
+	}
+}
+
+func TestMakeRequest_WriteObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPost && string(body) != `{"uris":["spotify:track:a"]}` {
+			t.Errorf("Expected request body to reach the server, got %q", body)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"snapshot_id":"snap"}`))
+	}))
+	defer server.Close()
+
+	var events []WriteEvent
+	client := newTestClient(server.URL, WithWriteObserver(func(event WriteEvent) {
+		events = append(events, event)
+	}))
+
+	resp, err := client.Post(context.Background(), "/playlists/p1/tracks", strings.NewReader(`{"uris":["spotify:track:a"]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"snapshot_id":"snap"}` {
+		t.Errorf("Expected caller to receive the full response, got %q", body)
+	}
+
+	if _, err := client.Get(context.Background(), "/playlists/p1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("Expected only the write to be observed, got %d events", len(events))
+	}
+	event := events[0]
+	if event.Method != "POST" || event.Endpoint != "/playlists/p1/tracks" || event.StatusCode != http.StatusCreated {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if string(event.RequestBody) != `{"uris":["spotify:track:a"]}` || string(event.ResponseBody) != `{"snapshot_id":"snap"}` {
+		t.Errorf("Unexpected event bodies %q, %q", event.RequestBody, event.ResponseBody)
+	}
+}
+
+// roundTripFunc lets a test stand in for the network
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTokenStore_RefreshThroughCustomTransport(t *testing.T) {
+	var hosts []string
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		hosts = append(hosts, r.URL.Host)
+		body := `{}`
+		if r.URL.Host == "accounts.spotify.com" {
+			body = `{"access_token":"fresh","token_type":"Bearer","expires_in":3600}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})
+
+	store := auth.NewMemoryTokenStore(&auth.Token{
+		AccessToken:  "stale",
+		RefreshToken: "refresh",
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+	client := NewClient("test_id", "test_secret", "", WithTokenStore(store), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if token := client.GetToken(); token == nil || token.AccessToken != "stale" {
+		t.Fatalf("Expected the stored token to be loaded, got %+v", token)
+	}
+
+	resp, err := client.Get(context.Background(), "/me")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(hosts) != 2 || hosts[0] != "accounts.spotify.com" || hosts[1] != "api.spotify.com" {
+		t.Errorf("Expected a refresh and then the API request through the transport, got %v", hosts)
+	}
+
+	saved, _ := store.Load()
+	if saved.AccessToken != "fresh" || saved.RefreshToken != "refresh" {
+		t.Errorf("Expected the refreshed token to be saved with the old refresh token, got %+v", saved)
+	}
+}
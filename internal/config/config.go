@@ -14,20 +14,26 @@ import (
 
 type Config struct {
 	Spotify SpotifyConfig `yaml:"spotify"`
-	Lidarr  LidarrConfig  `yaml:"lidarr"`
-	Logging LoggingConfig `yaml:"logging"`
+	Lidarr    LidarrConfig    `yaml:"lidarr"`
+	Navidrome NavidromeConfig `yaml:"navidrome"`
+	Plex      PlexConfig      `yaml:"plex"`
+	Discord   DiscordConfig   `yaml:"discord"`
+	Schedule  []ScheduleSlot  `yaml:"schedule"`
+	Quiet     QuietConfig     `yaml:"quiet_hours"`
+	Webhooks  []WebhookConfig `yaml:"webhooks"`
+	Logging   LoggingConfig   `yaml:"logging"`
 }
 
 type SpotifyConfig struct {
 	ClientID     string `yaml:"client_id"`
-	ClientSecret string `yaml:"client_secret"`
+	ClientSecret string `yaml:"client_secret,omitempty"`
 	RedirectURI  string `yaml:"redirect_uri"`
 	Scopes       []string `yaml:"scopes"`
 }
 
 type LidarrConfig struct {
 	URL               string `yaml:"url"`
-	APIKey            string `yaml:"api_key"`
+	APIKey            string `yaml:"api_key,omitempty"`
 	RootFolderPath    string `yaml:"root_folder_path"`
 	QualityProfileID  int    `yaml:"quality_profile_id"`
 	MetadataProfileID int    `yaml:"metadata_profile_id"`
@@ -35,6 +41,90 @@ type LidarrConfig struct {
 	SearchForMissing  bool   `yaml:"search_for_missing"`
 }
 
+// NavidromeConfig is a Navidrome server, or any other that speaks the
+// Subsonic API
+type NavidromeConfig struct {
+	URL      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// PlexConfig is a Plex Media Server and the music library on it
+type PlexConfig struct {
+	URL   string `yaml:"url"`
+	Token string `yaml:"token,omitempty"`
+	// Section is the title or key of the music library to match tracks in.
+	// It may be left out when the server has a single music library.
+	Section string `yaml:"section"`
+}
+
+// DiscordConfig is how presence shows playback on Discord
+type DiscordConfig struct {
+	// ClientID is the Discord application the activity is shown as, whose
+	// name is what profiles say the user is listening to
+	ClientID string `yaml:"client_id"`
+	// Details, State and LargeText are templates for the lines of the
+	// activity; empty ones keep the defaults
+	Details   string `yaml:"details"`
+	State     string `yaml:"state"`
+	LargeText string `yaml:"large_text"`
+	// Idle is what to show while paused: "paused" or "clear"
+	Idle string `yaml:"idle"`
+}
+
+// ScheduleSlot is what to play at some times of the week, for schedule
+type ScheduleSlot struct {
+	Name string `yaml:"name"`
+	// Days are names such as "mon", ranges such as "mon-fri", "weekdays"
+	// or "weekends"; none is every day
+	Days []string `yaml:"days"`
+	// Start and End are times of day such as "07:30"
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Context is the playlist, album, artist or show to play, as a URI or
+	// link
+	Context string `yaml:"context"`
+	// Device is the name or ID of the device to play on; empty is the
+	// active device
+	Device  string `yaml:"device"`
+	Shuffle *bool  `yaml:"shuffle"`
+	Volume  *int   `yaml:"volume"`
+	// Alarm slots start during quiet hours too
+	Alarm bool `yaml:"alarm"`
+}
+
+// QuietConfig is when automation must keep the house quiet
+type QuietConfig struct {
+	Windows []QuietWindow `yaml:"windows"`
+	// MaxVolume caps the volume of playback during quiet hours; unset
+	// leaves the volume alone
+	MaxVolume *int `yaml:"max_volume"`
+}
+
+// QuietWindow is a span of quiet hours, given as a ScheduleSlot's days and
+// times are
+type QuietWindow struct {
+	Days  []string `yaml:"days"`
+	Start string   `yaml:"start"`
+	End   string   `yaml:"end"`
+}
+
+// WebhookConfig is a URL playback and library events are posted to. The
+// URL and headers of a named webhook are kept in the credentials file, and
+// may name environment variables, as $NAME or ${NAME}.
+type WebhookConfig struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+	// Events are the event types to post, such as "track.started"; none is
+	// every type
+	Events []string `yaml:"events"`
+	// Template is a Go template over the event that makes the request body;
+	// without one the event is posted as JSON
+	Template    string            `yaml:"template"`
+	ContentType string            `yaml:"content_type"`
+	Headers     map[string]string `yaml:"headers,omitempty"`
+}
+
 type LoggingConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
@@ -78,27 +168,50 @@ func DefaultConfig() *Config {
 
 // Load loads configuration from file and environment variables
 func Load() (*Config, error) {
+	config, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	if err := validate(config); err != nil {
+		return nil, errors.WrapConfigError(err, "config validation failed")
+	}
+
+	return config, nil
+}
+
+// LoadSettings loads configuration as Load does without requiring Spotify
+// credentials, for commands that get those from the CLI config and only
+// read settings such as quiet hours here
+func LoadSettings() (*Config, error) {
 	config := DefaultConfig()
 
 	// Load .env file if it exists (this will set environment variables)
 	loadDotEnv()
 
 	// Try to load from config file
-	if err := loadFromFile(config); err != nil {
+	path, err := loadFromFile(config)
+	if err != nil {
 		// Config file is optional, so we only return error if it exists but is invalid
 		if !os.IsNotExist(err) {
 			return nil, errors.WrapConfigError(err, "failed to load config file")
 		}
 	}
+	if secrets := secretsOf(config); len(secrets) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s holds secrets; move them by hand to %s, which only you may read, as %s\n", path, CredentialsPath(), strings.Join(secrets, ", "))
+	}
+
+	// Secrets come from their own file, which only the owner may read
+	credentials, err := LoadCredentials(CredentialsPath())
+	if err != nil {
+		return nil, err
+	}
+	credentials.apply(config)
 
 	// Override with environment variables
 	loadFromEnv(config)
 
-	// Validate required fields
-	if err := validate(config); err != nil {
-		return nil, errors.WrapConfigError(err, "config validation failed")
-	}
-
 	return config, nil
 }
 
@@ -118,8 +231,9 @@ func loadDotEnv() {
 	}
 }
 
-// loadFromFile attempts to load configuration from various possible locations
-func loadFromFile(config *Config) error {
+// loadFromFile attempts to load configuration from various possible locations,
+// returning the path it was loaded from
+func loadFromFile(config *Config) (string, error) {
 	possiblePaths := []string{
 		"spotify-cli.yaml",
 		"spotify-cli.yml",
@@ -131,11 +245,11 @@ func loadFromFile(config *Config) error {
 
 	for _, path := range possiblePaths {
 		if _, err := os.Stat(path); err == nil {
-			return loadConfigFile(path, config)
+			return path, loadConfigFile(path, config)
 		}
 	}
 
-	return os.ErrNotExist
+	return "", os.ErrNotExist
 }
 
 // loadConfigFile loads configuration from a specific file
@@ -197,6 +311,33 @@ func loadFromEnv(config *Config) {
 		config.Lidarr.SearchForMissing = strings.ToLower(val) == "true"
 	}
 
+	// Navidrome configuration
+	if val := os.Getenv("NAVIDROME_URL"); val != "" {
+		config.Navidrome.URL = val
+	}
+	if val := os.Getenv("NAVIDROME_USERNAME"); val != "" {
+		config.Navidrome.Username = val
+	}
+	if val := os.Getenv("NAVIDROME_PASSWORD"); val != "" {
+		config.Navidrome.Password = val
+	}
+
+	// Plex configuration
+	if val := os.Getenv("PLEX_URL"); val != "" {
+		config.Plex.URL = val
+	}
+	if val := os.Getenv("PLEX_TOKEN"); val != "" {
+		config.Plex.Token = val
+	}
+	if val := os.Getenv("PLEX_SECTION"); val != "" {
+		config.Plex.Section = val
+	}
+
+	// Discord configuration
+	if val := os.Getenv("DISCORD_CLIENT_ID"); val != "" {
+		config.Discord.ClientID = val
+	}
+
 	// Logging configuration
 	if val := os.Getenv("LOG_LEVEL"); val != "" {
 		config.Logging.Level = val
@@ -239,9 +380,14 @@ func validate(config *Config) error {
 	return nil
 }
 
-// Save saves the current configuration to a file
+// Save saves the current configuration to a file, and its secrets to the
+// credentials file
 func (c *Config) Save(path string) error {
-	data, err := yaml.Marshal(c)
+	if err := credentialsOf(c).Save(CredentialsPath()); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(withoutSecrets(c))
 	if err != nil {
 		return errors.WrapFileError(err, "failed to marshal config")
 	}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// CredentialsFile is the name of the file holding integration secrets
+const CredentialsFile = "credentials.yaml"
+
+// CredentialsPerm is the only permission the credentials file is written
+// with, and the most open one it is read with without a warning
+const CredentialsPerm = 0600
+
+// Credentials are the secrets of the integrations. They are kept apart from
+// the settings in config.yaml, which people copy between machines and share
+// with each other, and new integrations keep their secrets here too.
+type Credentials struct {
+	Spotify   SpotifyCredentials   `yaml:"spotify,omitempty"`
+	Lidarr    LidarrCredentials    `yaml:"lidarr,omitempty"`
+	Navidrome NavidromeCredentials `yaml:"navidrome,omitempty"`
+	Plex      PlexCredentials      `yaml:"plex,omitempty"`
+	// Webhooks are keyed by the name of the webhook in config.yaml
+	Webhooks map[string]WebhookCredentials `yaml:"webhooks,omitempty"`
+}
+
+// SpotifyCredentials are the secrets of the Spotify app
+type SpotifyCredentials struct {
+	ClientSecret string `yaml:"client_secret,omitempty"`
+}
+
+// LidarrCredentials are the secrets of a Lidarr server
+type LidarrCredentials struct {
+	APIKey string `yaml:"api_key,omitempty"`
+}
+
+// NavidromeCredentials are the secrets of a Navidrome server
+type NavidromeCredentials struct {
+	Password string `yaml:"password,omitempty"`
+}
+
+// PlexCredentials are the secrets of a Plex Media Server
+type PlexCredentials struct {
+	Token string `yaml:"token,omitempty"`
+}
+
+// WebhookCredentials are the URL and headers of a webhook, which often
+// carry its token
+type WebhookCredentials struct {
+	URL     string            `yaml:"url,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// CredentialsPath returns where credentials are kept: $SPOTIFY_CLI_CREDENTIALS
+// if set, otherwise credentials.yaml in ~/.config/spotify-cli
+func CredentialsPath() string {
+	if path := os.Getenv("SPOTIFY_CLI_CREDENTIALS"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "spotify-cli", CredentialsFile)
+}
+
+// CheckPermissions returns an error if a secrets file can be read or
+// written by anyone but its owner. Windows has no such permission bits, so
+// nothing is checked there.
+func CheckPermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return errors.NewConfigError(fmt.Sprintf("permissions %04o on %s are too open; run 'chmod 600 %s'", perm, path, path))
+	}
+	return nil
+}
+
+// LoadCredentials reads the credentials file at path. A missing file gives
+// empty credentials. A file others can read is still used, with a warning.
+func LoadCredentials(path string) (*Credentials, error) {
+	credentials := &Credentials{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return credentials, nil
+	}
+	if err != nil {
+		return nil, errors.WrapFileError(err, "failed to read credentials file")
+	}
+
+	if err := CheckPermissions(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if err := yaml.Unmarshal(data, credentials); err != nil {
+		return nil, errors.WrapConfigError(err, "failed to parse credentials file")
+	}
+	return credentials, nil
+}
+
+// Save writes the credentials to path with CredentialsPerm, tightening the
+// permissions of a file that already exists
+func (c *Credentials) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.WrapFileError(err, "failed to marshal credentials")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WrapFileError(err, "failed to create credentials directory")
+	}
+	// Written to a new file and renamed over the old one, so a failed write
+	// cannot lose every secret at once, and the mode of an existing file is
+	// replaced too
+	if err := state.WriteFileAtomic(path, data, CredentialsPerm); err != nil {
+		return errors.WrapFileError(err, "failed to write credentials file")
+	}
+	return nil
+}
+
+// apply sets the secrets present in the credentials on config
+func (c *Credentials) apply(config *Config) {
+	if c.Spotify.ClientSecret != "" {
+		config.Spotify.ClientSecret = c.Spotify.ClientSecret
+	}
+	if c.Lidarr.APIKey != "" {
+		config.Lidarr.APIKey = c.Lidarr.APIKey
+	}
+	if c.Navidrome.Password != "" {
+		config.Navidrome.Password = c.Navidrome.Password
+	}
+	if c.Plex.Token != "" {
+		config.Plex.Token = c.Plex.Token
+	}
+
+	webhooks := make([]WebhookConfig, len(config.Webhooks))
+	for i, w := range config.Webhooks {
+		if secrets, ok := c.Webhooks[w.Name]; ok && w.Name != "" {
+			if secrets.URL != "" {
+				w.URL = secrets.URL
+			}
+			headers := make(map[string]string, len(w.Headers)+len(secrets.Headers))
+			for name, value := range w.Headers {
+				headers[name] = value
+			}
+			for name, value := range secrets.Headers {
+				headers[name] = value
+			}
+			w.Headers = headers
+		}
+		webhooks[i] = w
+	}
+	config.Webhooks = webhooks
+}
+
+// credentialsOf returns the secrets of config
+func credentialsOf(config *Config) *Credentials {
+	return &Credentials{
+		Spotify:   SpotifyCredentials{ClientSecret: config.Spotify.ClientSecret},
+		Lidarr:    LidarrCredentials{APIKey: config.Lidarr.APIKey},
+		Navidrome: NavidromeCredentials{Password: config.Navidrome.Password},
+		Plex:      PlexCredentials{Token: config.Plex.Token},
+		Webhooks:  webhookCredentialsOf(config),
+	}
+}
+
+// webhookCredentialsOf returns the URLs and headers of the named webhooks
+// of config. Those of a webhook without a name cannot be matched up again,
+// so they stay in the settings.
+func webhookCredentialsOf(config *Config) map[string]WebhookCredentials {
+	var webhooks map[string]WebhookCredentials
+	for _, w := range config.Webhooks {
+		if w.Name == "" || (w.URL == "" && len(w.Headers) == 0) {
+			continue
+		}
+		if webhooks == nil {
+			webhooks = make(map[string]WebhookCredentials)
+		}
+		webhooks[w.Name] = WebhookCredentials{URL: w.URL, Headers: w.Headers}
+	}
+	return webhooks
+}
+
+// secretsOf returns the secrets config holds, named by where they go in the
+// credentials file
+func secretsOf(config *Config) []string {
+	var keys []string
+	for key, value := range map[string]string{
+		"spotify.client_secret": config.Spotify.ClientSecret,
+		"lidarr.api_key":        config.Lidarr.APIKey,
+		"navidrome.password":    config.Navidrome.Password,
+		"plex.token":            config.Plex.Token,
+	} {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	for name, webhook := range webhookCredentialsOf(config) {
+		if webhook.URL != "" {
+			keys = append(keys, "webhooks."+name+".url")
+		}
+		if len(webhook.Headers) > 0 {
+			keys = append(keys, "webhooks."+name+".headers")
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withoutSecrets returns a copy of config without any secret, for writing
+// to the settings file
+func withoutSecrets(config *Config) *Config {
+	settings := *config
+	settings.Spotify.ClientSecret = ""
+	settings.Lidarr.APIKey = ""
+	settings.Navidrome.Password = ""
+	settings.Plex.Token = ""
+	settings.Webhooks = make([]WebhookConfig, len(config.Webhooks))
+	for i, w := range config.Webhooks {
+		if w.Name != "" {
+			w.URL, w.Headers = "", nil
+		}
+		settings.Webhooks[i] = w
+	}
+	return &settings
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCredentials_SaveSplitsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	credentialsPath := filepath.Join(dir, CredentialsFile)
+	t.Setenv("SPOTIFY_CLI_CREDENTIALS", credentialsPath)
+
+	config := DefaultConfig()
+	config.Spotify.ClientID = "id"
+	config.Spotify.ClientSecret = "spotify-secret"
+	config.Lidarr.APIKey = "lidarr-key"
+	config.Navidrome.Password = "navidrome-password"
+	config.Plex.Token = "plex-token"
+
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	settings, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(settings), "spotify-secret") || strings.Contains(string(settings), "lidarr-key") || strings.Contains(string(settings), "navidrome-password") || strings.Contains(string(settings), "plex-token") {
+		t.Errorf("Expected no secrets in the settings file:\n%s", settings)
+	}
+
+	credentials, err := LoadCredentials(credentialsPath)
+	if err != nil {
+		t.Fatalf("LoadCredentials() error = %v", err)
+	}
+	if credentials.Spotify.ClientSecret != "spotify-secret" || credentials.Lidarr.APIKey != "lidarr-key" || credentials.Navidrome.Password != "navidrome-password" || credentials.Plex.Token != "plex-token" {
+		t.Errorf("Unexpected credentials: %+v", credentials)
+	}
+	if err := CheckPermissions(credentialsPath); err != nil {
+		t.Errorf("Expected credentials to be written 0600, got %v", err)
+	}
+}
+
+func TestCredentials_Permissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not checked on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), CredentialsFile)
+	if err := os.WriteFile(path, []byte("lidarr:\n  api_key: key\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckPermissions(path); err == nil {
+		t.Error("Expected 0644 credentials to be reported as too open")
+	}
+
+	// Open permissions warn but do not stop the file being used
+	credentials, err := LoadCredentials(path)
+	if err != nil || credentials.Lidarr.APIKey != "key" {
+		t.Errorf("Expected credentials despite open permissions, got %+v, %v", credentials, err)
+	}
+
+	// Saving tightens an existing file
+	if err := credentials.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := CheckPermissions(path); err != nil {
+		t.Errorf("Expected Save to tighten permissions, got %v", err)
+	}
+}
+
+func TestCredentials_Missing(t *testing.T) {
+	credentials, err := LoadCredentials(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Expected no error for a missing credentials file, got %v", err)
+	}
+	if credentials.Lidarr.APIKey != "" {
+		t.Errorf("Expected empty credentials, got %+v", credentials)
+	}
+}
+
+func TestCredentials_Webhooks(t *testing.T) {
+	dir := t.TempDir()
+	credentialsPath := filepath.Join(dir, CredentialsFile)
+	t.Setenv("SPOTIFY_CLI_CREDENTIALS", credentialsPath)
+
+	config := DefaultConfig()
+	config.Webhooks = []WebhookConfig{
+		{Name: "ntfy", URL: "https://ntfy.sh/secret-topic", Events: []string{"track.started"}, Headers: map[string]string{"Authorization": "Bearer ntfy-token"}},
+		{URL: "https://example.com/unnamed"},
+	}
+
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	settings, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(settings), "secret-topic") || strings.Contains(string(settings), "ntfy-token") {
+		t.Errorf("Expected no webhook secrets in the settings file:\n%s", settings)
+	}
+	if !strings.Contains(string(settings), "https://example.com/unnamed") {
+		t.Errorf("Expected the URL of an unnamed webhook to stay in the settings file:\n%s", settings)
+	}
+
+	credentials, err := LoadCredentials(credentialsPath)
+	if err != nil {
+		t.Fatalf("LoadCredentials() error = %v", err)
+	}
+	if len(credentials.Webhooks) != 1 || credentials.Webhooks["ntfy"].URL != "https://ntfy.sh/secret-topic" {
+		t.Fatalf("Unexpected webhook credentials: %+v", credentials.Webhooks)
+	}
+
+	loaded := DefaultConfig()
+	loaded.Webhooks = []WebhookConfig{{Name: "ntfy", Events: []string{"track.started"}, Headers: map[string]string{"Title": "Now playing"}}}
+	credentials.apply(loaded)
+	hook := loaded.Webhooks[0]
+	if hook.URL != "https://ntfy.sh/secret-topic" || hook.Headers["Authorization"] != "Bearer ntfy-token" || hook.Headers["Title"] != "Now playing" {
+		t.Errorf("Expected the webhook's URL and headers from the credentials, got %+v", hook)
+	}
+}
+
+func TestCredentials_SecretsNamed(t *testing.T) {
+	config := DefaultConfig()
+	if secrets := secretsOf(config); len(secrets) != 0 {
+		t.Errorf("Expected no secrets in the default config, got %v", secrets)
+	}
+
+	config.Plex.Token = "plex-token"
+	config.Webhooks = []WebhookConfig{
+		{Name: "ntfy", URL: "https://ntfy.sh/topic", Headers: map[string]string{"Authorization": "Bearer token"}},
+		{URL: "https://example.com/unnamed"},
+	}
+	expected := "plex.token, webhooks.ntfy.headers, webhooks.ntfy.url"
+	if got := strings.Join(secretsOf(config), ", "); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
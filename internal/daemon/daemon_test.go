@@ -0,0 +1,416 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+func TestHealth_Endpoints(t *testing.T) {
+	health := NewHealth("test", time.Minute)
+	server := httptest.NewServer(health.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("Failed to get /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected /healthz status 200, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("Failed to get /readyz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz status 503 before first run, got %d", resp.StatusCode)
+	}
+
+	health.RecordRun(nil)
+
+	resp, err = http.Get(server.URL + "/readyz")
+	if err != nil {
+		t.Fatalf("Failed to get /readyz: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected /readyz status 200 after successful run, got %d", resp.StatusCode)
+	}
+
+	var status HealthStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status.Name != "test" || status.Runs != 1 || !status.Ready {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
+
+func TestHealth_FailedRunAndStaleness(t *testing.T) {
+	health := NewHealth("test", time.Minute)
+	now := time.Now()
+	health.now = func() time.Time { return now }
+
+	health.RecordRun(nil)
+	health.RecordRun(errors.New("boom"))
+
+	if health.Ready() {
+		t.Error("Expected daemon not to be ready after a failed run")
+	}
+	if status := health.Status(); status.LastError != "boom" || status.Status != "not_ready" {
+		t.Errorf("Unexpected status after failure: %+v", status)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if health.Alive() {
+		t.Error("Expected daemon to be stale after missing heartbeats")
+	}
+	if status := health.Status(); status.Status != "stale" {
+		t.Errorf("Expected stale status, got %s", status.Status)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	sent, err := Notify(NotifyReady)
+	if err != nil || sent {
+		t.Errorf("Expected no notification without NOTIFY_SOCKET, got sent=%v err=%v", sent, err)
+	}
+
+	socketPath := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unix datagram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socketPath)
+	sent, err = Notify(NotifyReady)
+	if err != nil || !sent {
+		t.Fatalf("Expected notification to be sent, got sent=%v err=%v", sent, err)
+	}
+
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Failed to read notification: %v", err)
+	}
+	if string(buf[:n]) != NotifyReady {
+		t.Errorf("Expected %q, got %q", NotifyReady, string(buf[:n]))
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	if interval := WatchdogInterval(); interval != 0 {
+		t.Errorf("Expected no watchdog, got %v", interval)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", "")
+	if interval := WatchdogInterval(); interval != 30*time.Second {
+		t.Errorf("Expected 30s watchdog, got %v", interval)
+	}
+
+	t.Setenv("WATCHDOG_PID", "1")
+	if os.Getpid() != 1 {
+		if interval := WatchdogInterval(); interval != 0 {
+			t.Errorf("Expected watchdog for another PID to be ignored, got %v", interval)
+		}
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit := SystemdUnit(ServiceSpec{
+		Name:        "history",
+		Executable:  "/usr/local/bin/spotify-cli",
+		Args:        []string{"history", "record", "--health-addr", "127.0.0.1:8089"},
+		Environment: map[string]string{"SPOTIFY_CLI_PROFILE": "work"},
+		WatchdogSec: 120,
+	})
+
+	expected := []string{
+		"Type=notify",
+		"ExecStart=/usr/local/bin/spotify-cli history record --health-addr 127.0.0.1:8089",
+		"Environment=SPOTIFY_CLI_PROFILE=work",
+		"WatchdogSec=120",
+		"WantedBy=default.target",
+	}
+	for _, line := range expected {
+		if !strings.Contains(unit, line+"\n") {
+			t.Errorf("Expected unit to contain %q, got:\n%s", line, unit)
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":      "plain",
+		"with space": `"with space"`,
+		`say "hi"`:   `"say \"hi\""`,
+		"$HOME":      `"$$HOME"`,
+		"":           `""`,
+		"50%":        `"50%%"`,
+	}
+
+	for input, expected := range tests {
+		if result := systemdQuote(input); result != expected {
+			t.Errorf("systemdQuote(%q) = %q, expected %q", input, result, expected)
+		}
+	}
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := LaunchdPlist(ServiceSpec{
+		Name:       "history",
+		Executable: "/usr/local/bin/spotify-cli",
+		Args:       []string{"history", "record", "--name", "A&B"},
+		LogDir:     "/tmp/logs",
+	})
+
+	expected := []string{
+		"<string>com.spotify-cli.history</string>",
+		"<string>/usr/local/bin/spotify-cli</string>",
+		"<string>A&amp;B</string>",
+		"<string>/tmp/logs/history.log</string>",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(plist, fragment) {
+			t.Errorf("Expected plist to contain %q, got:\n%s", fragment, plist)
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	home := t.TempDir()
+	spec := ServiceSpec{Name: "history", Executable: "/bin/spotify-cli", Args: []string{"history", "record"}}
+
+	path, err := Install(ManagerSystemd, home, spec, false)
+	if err != nil {
+		t.Fatalf("Failed to install service: %v", err)
+	}
+
+	if expected := filepath.Join(home, ".config", "systemd", "user", "spotify-cli-history.service"); path != expected {
+		t.Errorf("Expected service at %s, got %s", expected, path)
+	}
+
+	if _, err := Install(ManagerSystemd, home, spec, false); err == nil {
+		t.Error("Expected error when the service file already exists")
+	}
+
+	if _, err := Install(ManagerSystemd, home, spec, true); err != nil {
+		t.Errorf("Expected overwrite to succeed, got %v", err)
+	}
+
+	if _, err := Render("upstart", spec); err == nil {
+		t.Error("Expected error for unknown service manager")
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	var errs []error
+	done := make(chan error, 1)
+
+	var summary *Summary
+	go func() {
+		var err error
+		summary, err = Run(ctx, Options{
+			Name:     "test",
+			Interval: 10 * time.Millisecond,
+			OnError:  func(err error) { errs = append(errs, err) },
+		}, func(ctx context.Context, report *Report) error {
+			runs++
+			report.Add("items", 2)
+			if runs == 2 {
+				return errors.New("transient")
+			}
+			if runs == 3 {
+				cancel()
+			}
+			return nil
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected Run to return nil on cancellation, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+
+	if runs != 3 {
+		t.Errorf("Expected 3 runs, got %d", runs)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 reported error, got %d", len(errs))
+	}
+	if summary.Mode != ModeWatch || summary.Runs != 3 || summary.Failures != 1 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if summary.Status != "recovered" || summary.Counters["items"] != 6 {
+		t.Errorf("Expected recovered status with 6 items, got %+v", summary)
+	}
+}
+
+func TestRun_Once(t *testing.T) {
+	runs := 0
+	summary, err := Run(context.Background(), Options{Name: "test", Once: true}, func(ctx context.Context, report *Report) error {
+		runs++
+		report.Add("imported", 3)
+		report.Add("skipped", 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected successful run, got %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("Expected exactly 1 run, got %d", runs)
+	}
+	if summary.Mode != ModeOnce || summary.Status != "success" || summary.ExitCode != ExitOK {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if names := summary.CounterNames(); len(names) != 2 || names[0] != "imported" {
+		t.Errorf("Expected sorted counter names, got %v", names)
+	}
+
+	taskErr := apierrors.NewAuthError("token expired")
+	summary, err = Run(context.Background(), Options{Name: "test", Once: true}, func(ctx context.Context, report *Report) error {
+		return taskErr
+	})
+	if err != taskErr {
+		t.Fatalf("Expected task error to be returned, got %v", err)
+	}
+	if summary.Status != "failed" || summary.ExitCode != ExitNoPerm || summary.LastError == "" {
+		t.Errorf("Unexpected summary after failure: %+v", summary)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOK},
+		{errors.New("boom"), ExitFailure},
+		{apierrors.NewValidationError("bad input"), ExitUsage},
+		{apierrors.NewAuthError("expired"), ExitNoPerm},
+		{apierrors.NewConfigError("missing client id"), ExitConfig},
+		{apierrors.NewNetworkError("timeout"), ExitTempFail},
+		{fmt.Errorf("failed to fetch: %w", apierrors.NewStatusError(500, "HTTP 500: Server error")), ExitTempFail},
+		{apierrors.NewStatusError(429, "rate limited"), ExitTempFail},
+		{apierrors.NewStatusError(404, "HTTP 404: Not found"), ExitFailure},
+		{apierrors.NewStatusError(400, "HTTP 400: Invalid limit"), ExitUsage},
+		{apierrors.NewAPIError("failed to decode response"), ExitFailure},
+		{apierrors.NewFileError("permission denied"), ExitIOErr},
+		{apierrors.WrapNetworkError(context.Canceled, "request cancelled"), ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		if code := ExitCode(tt.err); code != tt.expected {
+			t.Errorf("ExitCode(%v) = %d, expected %d", tt.err, code, tt.expected)
+		}
+	}
+}
+
+func TestSummary_WriteJSON(t *testing.T) {
+	summary := newSummary("test", ModeOnce)
+	summary.record(NewReport(), nil)
+	summary.finish()
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := summary.WriteJSON(path); err != nil {
+		t.Fatalf("Failed to write summary: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read summary: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+	if decoded["status"] != "success" || decoded["exit_code"] != float64(0) {
+		t.Errorf("Unexpected summary JSON: %s", data)
+	}
+}
+
+func TestNewCommandSummary(t *testing.T) {
+	startedAt := time.Now().Add(-time.Second)
+	report := NewReport()
+	report.Add("tracks added", 3)
+
+	summary := NewCommandSummary("spotify-cli playlist merge", startedAt, report, apierrors.NewAuthError("expired"))
+	if summary.Mode != ModeCommand || summary.Runs != 1 || summary.Status != "failed" || summary.ExitCode != ExitNoPerm {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if summary.Counters["tracks added"] != 3 || summary.DurationMs < 1000 {
+		t.Errorf("Expected the counters and the whole duration, got %+v", summary)
+	}
+}
+
+func TestRun_HealthServer(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addrCh := make(chan string, 1)
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := Run(ctx, Options{
+			Name:          "test",
+			Interval:      time.Hour,
+			HealthAddr:    "127.0.0.1:0",
+			OnHealthServe: func(addr string) { addrCh <- addr },
+		}, func(ctx context.Context, report *Report) error { return nil })
+		done <- err
+	}()
+
+	addr := <-addrCh
+
+	// Wait for the first run to mark the daemon ready
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get("http://" + addr + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Daemon never became ready")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Expected Run to return nil, got %v", err)
+	}
+}
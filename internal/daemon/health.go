@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health tracks the liveness and readiness of a long-running process and
+// serves them over HTTP for supervisors and load balancers
+type Health struct {
+	name      string
+	staleness time.Duration
+	now       func() time.Time
+
+	mu        sync.RWMutex
+	ready     bool
+	started   time.Time
+	lastBeat  time.Time
+	lastRun   time.Time
+	lastError string
+	runs      int
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// HealthStatus is the JSON body returned by the health endpoints
+type HealthStatus struct {
+	Status    string    `json:"status"`
+	Name      string    `json:"name"`
+	Ready     bool      `json:"ready"`
+	Started   time.Time `json:"started"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	Runs      int       `json:"runs"`
+}
+
+// NewHealth creates a health tracker. The process is reported unhealthy when
+// no heartbeat has been recorded for longer than staleness; a zero staleness
+// disables the check.
+func NewHealth(name string, staleness time.Duration) *Health {
+	now := time.Now()
+	return &Health{
+		name:      name,
+		staleness: staleness,
+		now:       time.Now,
+		started:   now,
+		lastBeat:  now,
+	}
+}
+
+// Beat records that the process is alive
+func (h *Health) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastBeat = h.now()
+}
+
+// RecordRun records the outcome of a unit of work. A successful run marks the
+// process ready; a failed run marks it not ready until the next success.
+func (h *Health) RecordRun(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.runs++
+	h.lastRun = h.now()
+	h.lastBeat = h.lastRun
+
+	if err != nil {
+		h.lastError = err.Error()
+		h.ready = false
+		return
+	}
+
+	h.lastError = ""
+	h.ready = true
+}
+
+// SetReady overrides the readiness state
+func (h *Health) SetReady(ready bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ready = ready
+}
+
+// Alive reports whether a heartbeat was recorded within the staleness window
+func (h *Health) Alive() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.staleness <= 0 || h.now().Sub(h.lastBeat) <= h.staleness
+}
+
+// Ready reports whether the last unit of work succeeded
+func (h *Health) Ready() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.ready
+}
+
+// Status returns a snapshot of the current health state
+func (h *Health) Status() HealthStatus {
+	alive := h.Alive()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	status := "ok"
+	if !alive {
+		status = "stale"
+	} else if !h.ready {
+		status = "not_ready"
+	}
+
+	return HealthStatus{
+		Status:    status,
+		Name:      h.name,
+		Ready:     h.ready,
+		Started:   h.started,
+		LastRun:   h.lastRun,
+		LastError: h.lastError,
+		Runs:      h.runs,
+	}
+}
+
+// Handler returns an HTTP handler serving /healthz and /readyz
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		h.writeStatus(w, h.Alive())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		h.writeStatus(w, h.Alive() && h.Ready())
+	})
+	return mux
+}
+
+func (h *Health) writeStatus(w http.ResponseWriter, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if ok {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(h.Status())
+}
+
+// Serve starts the health endpoints on addr in the background and returns the
+// address actually bound, which differs from addr when port 0 is used
+func (h *Health) Serve(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	h.listener = listener
+	h.server = &http.Server{
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go h.server.Serve(listener)
+
+	return listener.Addr().String(), nil
+}
+
+// Shutdown stops the health endpoints if they were started
+func (h *Health) Shutdown(ctx context.Context) error {
+	if h.server == nil {
+		return nil
+	}
+	return h.server.Shutdown(ctx)
+}
@@ -0,0 +1,211 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Service managers supported by Install
+const (
+	ManagerSystemd = "systemd"
+	ManagerLaunchd = "launchd"
+)
+
+var serviceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// ServiceSpec describes a daemon to register with the service manager
+type ServiceSpec struct {
+	Name        string
+	Description string
+	Executable  string
+	Args        []string
+	Environment map[string]string
+	// WatchdogSec enables the systemd watchdog when greater than zero
+	WatchdogSec int
+	// LogDir is where launchd writes stdout and stderr
+	LogDir string
+}
+
+// ManagerForOS returns the service manager used on the given GOOS
+func ManagerForOS(goos string) (string, error) {
+	switch goos {
+	case "linux":
+		return ManagerSystemd, nil
+	case "darwin":
+		return ManagerLaunchd, nil
+	default:
+		return "", fmt.Errorf("service installation is not supported on %s", goos)
+	}
+}
+
+// ServiceName returns the unit or label name for a daemon
+func ServiceName(manager, name string) string {
+	if manager == ManagerLaunchd {
+		return "com.spotify-cli." + name
+	}
+	return "spotify-cli-" + name
+}
+
+// ServicePath returns where the service file for a daemon is installed
+func ServicePath(manager, homeDir, name string) string {
+	if manager == ManagerLaunchd {
+		return filepath.Join(homeDir, "Library", "LaunchAgents", ServiceName(manager, name)+".plist")
+	}
+	return filepath.Join(homeDir, ".config", "systemd", "user", ServiceName(manager, name)+".service")
+}
+
+// Render returns the service file contents for the given manager
+func Render(manager string, spec ServiceSpec) (string, error) {
+	if !serviceNamePattern.MatchString(spec.Name) {
+		return "", fmt.Errorf("invalid service name %q: use letters, numbers, '-' and '_'", spec.Name)
+	}
+	if spec.Executable == "" {
+		return "", fmt.Errorf("service executable cannot be empty")
+	}
+
+	switch manager {
+	case ManagerSystemd:
+		return SystemdUnit(spec), nil
+	case ManagerLaunchd:
+		return LaunchdPlist(spec), nil
+	default:
+		return "", fmt.Errorf("unknown service manager: %s", manager)
+	}
+}
+
+// SystemdUnit renders a systemd user unit. The unit uses Type=notify, so the
+// daemon must send READY=1 once it has started.
+func SystemdUnit(spec ServiceSpec) string {
+	var b strings.Builder
+
+	description := spec.Description
+	if description == "" {
+		description = fmt.Sprintf("spotify-cli %s daemon", spec.Name)
+	}
+
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", description)
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("Wants=network-online.target\n\n")
+
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("NotifyAccess=main\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdCommandLine(spec.Executable, spec.Args))
+	for _, key := range sortedKeys(spec.Environment) {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(key+"="+spec.Environment[key]))
+	}
+	if spec.WatchdogSec > 0 {
+		fmt.Fprintf(&b, "WatchdogSec=%d\n", spec.WatchdogSec)
+	}
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=30\n\n")
+
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+
+	return b.String()
+}
+
+// LaunchdPlist renders a launchd agent property list
+func LaunchdPlist(spec ServiceSpec) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+
+	fmt.Fprintf(&b, "  <key>Label</key>\n  <string>%s</string>\n", xmlEscape(ServiceName(ManagerLaunchd, spec.Name)))
+
+	b.WriteString("  <key>ProgramArguments</key>\n  <array>\n")
+	fmt.Fprintf(&b, "    <string>%s</string>\n", xmlEscape(spec.Executable))
+	for _, arg := range spec.Args {
+		fmt.Fprintf(&b, "    <string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("  </array>\n")
+
+	if len(spec.Environment) > 0 {
+		b.WriteString("  <key>EnvironmentVariables</key>\n  <dict>\n")
+		for _, key := range sortedKeys(spec.Environment) {
+			fmt.Fprintf(&b, "    <key>%s</key>\n    <string>%s</string>\n", xmlEscape(key), xmlEscape(spec.Environment[key]))
+		}
+		b.WriteString("  </dict>\n")
+	}
+
+	b.WriteString("  <key>RunAtLoad</key>\n  <true/>\n")
+	b.WriteString("  <key>KeepAlive</key>\n  <dict>\n    <key>SuccessfulExit</key>\n    <false/>\n  </dict>\n")
+	b.WriteString("  <key>ThrottleInterval</key>\n  <integer>30</integer>\n")
+
+	if spec.LogDir != "" {
+		logBase := filepath.Join(spec.LogDir, spec.Name)
+		fmt.Fprintf(&b, "  <key>StandardOutPath</key>\n  <string>%s.log</string>\n", xmlEscape(logBase))
+		fmt.Fprintf(&b, "  <key>StandardErrorPath</key>\n  <string>%s.err.log</string>\n", xmlEscape(logBase))
+	}
+
+	b.WriteString("</dict>\n</plist>\n")
+
+	return b.String()
+}
+
+// Install writes the service file for spec and returns its path. Existing
+// files are only replaced when overwrite is true.
+func Install(manager, homeDir string, spec ServiceSpec, overwrite bool) (string, error) {
+	contents, err := Render(manager, spec)
+	if err != nil {
+		return "", err
+	}
+
+	path := ServicePath(manager, homeDir, spec.Name)
+	if !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("service file already exists: %s (use --force to overwrite)", path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create service directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		return "", fmt.Errorf("failed to write service file: %w", err)
+	}
+
+	return path, nil
+}
+
+// systemdCommandLine quotes the executable and arguments for ExecStart
+func systemdCommandLine(executable string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, systemdQuote(executable))
+	for _, arg := range args {
+		parts = append(parts, systemdQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func systemdQuote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\$%;") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func xmlEscape(value string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+	return replacer.Replace(value)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sd_notify states understood by systemd
+const (
+	NotifyReady     = "READY=1"
+	NotifyStopping  = "STOPPING=1"
+	NotifyReloading = "RELOADING=1"
+	NotifyWatchdog  = "WATCHDOG=1"
+)
+
+// Notify sends a state notification to the service manager using the
+// sd_notify protocol. It returns false without an error when the process is
+// not running under a service manager that requested notifications.
+func Notify(state string) (bool, error) {
+	socketPath := os.Getenv("NOTIFY_SOCKET")
+	if socketPath == "" {
+		return false, nil
+	}
+
+	// A leading '@' denotes a Linux abstract socket
+	if socketPath[0] == '@' {
+		socketPath = "\x00" + socketPath[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// NotifyStatus sends a free-form status line shown by 'systemctl status'
+func NotifyStatus(status string) (bool, error) {
+	return Notify("STATUS=" + status)
+}
+
+// WatchdogInterval returns how often the service manager expects a watchdog
+// ping, or zero if the watchdog is not enabled for this process. Pings should
+// be sent at half the returned interval.
+func WatchdogInterval() time.Duration {
+	usec := os.Getenv("WATCHDOG_USEC")
+	if usec == "" {
+		return 0
+	}
+
+	// WATCHDOG_PID, when set, names the process the watchdog applies to
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	value, err := strconv.ParseInt(usec, 10, 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+
+	return time.Duration(value) * time.Microsecond
+}
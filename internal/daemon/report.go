@@ -0,0 +1,202 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// Exit codes of spotify-cli, for daemons in single-run mode and every other
+// command alike. They follow the BSD sysexits convention so cron wrappers
+// and pipelines can tell transient failures (worth retrying on the next
+// schedule) from ones that need a human.
+const (
+	ExitOK          = 0   // the run completed successfully
+	ExitFailure     = 1   // the run failed for an unclassified reason
+	ExitUsage       = 64  // invalid flags or input
+	ExitIOErr       = 74  // reading or writing a local file failed
+	ExitTempFail    = 75  // network or API failure; retrying later may succeed
+	ExitNoPerm      = 77  // authentication failed or the token lacks a scope
+	ExitConfig      = 78  // missing or invalid configuration
+	ExitInterrupted = 130 // cancelled by Ctrl-C or SIGTERM
+)
+
+// ExitCode maps an error returned by a daemon task or a command to an exit
+// code. Only network errors, rate limits and server errors are worth
+// retrying; an API answering 404 for a missing ID will answer it again.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case stderrors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.IsValidationError(err):
+		return ExitUsage
+	case errors.IsAuthError(err):
+		return ExitNoPerm
+	case errors.IsConfigError(err):
+		return ExitConfig
+	case errors.IsTemporary(err):
+		return ExitTempFail
+	case errors.StatusCode(err) == http.StatusBadRequest:
+		return ExitUsage
+	case errors.IsFileError(err):
+		return ExitIOErr
+	default:
+		return ExitFailure
+	}
+}
+
+// Report collects counters for a single run of a task, such as the number of
+// items imported or skipped
+type Report struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+// NewReport creates an empty report
+func NewReport() *Report {
+	return &Report{counters: make(map[string]int)}
+}
+
+// Add increments a named counter
+func (r *Report) Add(name string, n int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters[name] += n
+}
+
+// Counters returns a copy of the counters
+func (r *Report) Counters() map[string]int {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counters := make(map[string]int, len(r.counters))
+	for name, value := range r.counters {
+		counters[name] = value
+	}
+	return counters
+}
+
+// Summary is the machine-readable outcome of a daemon invocation, or of a
+// single command
+type Summary struct {
+	Name       string         `json:"name"`
+	Mode       string         `json:"mode"`
+	Status     string         `json:"status"`
+	ExitCode   int            `json:"exit_code"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	DurationMs int64          `json:"duration_ms"`
+	Runs       int            `json:"runs"`
+	Failures   int            `json:"failures"`
+	LastError  string         `json:"last_error,omitempty"`
+	Counters   map[string]int `json:"counters,omitempty"`
+	Errors     []string       `json:"errors,omitempty"`
+}
+
+// Run modes reported in Summary.Mode
+const (
+	ModeOnce    = "once"
+	ModeWatch   = "watch"
+	ModeCommand = "command"
+)
+
+// maxSummaryErrors bounds how many error messages a summary keeps
+const maxSummaryErrors = 20
+
+func newSummary(name, mode string) *Summary {
+	return &Summary{
+		Name:      name,
+		Mode:      mode,
+		StartedAt: time.Now(),
+		Counters:  make(map[string]int),
+	}
+}
+
+// NewCommandSummary describes a command that started at startedAt and
+// finished now with err, as a single run
+func NewCommandSummary(name string, startedAt time.Time, report *Report, err error) *Summary {
+	s := newSummary(name, ModeCommand)
+	s.StartedAt = startedAt
+	s.record(report, err)
+	s.finish()
+	return s
+}
+
+// record folds the outcome of one run into the summary
+func (s *Summary) record(report *Report, err error) {
+	s.Runs++
+	for name, value := range report.Counters() {
+		s.Counters[name] += value
+	}
+
+	if err != nil {
+		s.Failures++
+		s.LastError = err.Error()
+		s.ExitCode = ExitCode(err)
+		if len(s.Errors) < maxSummaryErrors {
+			s.Errors = append(s.Errors, err.Error())
+		}
+		return
+	}
+
+	s.LastError = ""
+	s.ExitCode = ExitOK
+}
+
+// finish stamps the end time and overall status
+func (s *Summary) finish() {
+	s.FinishedAt = time.Now()
+	s.DurationMs = s.FinishedAt.Sub(s.StartedAt).Milliseconds()
+
+	switch {
+	case s.Failures == 0:
+		s.Status = "success"
+	case s.LastError == "":
+		s.Status = "recovered"
+	default:
+		s.Status = "failed"
+	}
+}
+
+// CounterNames returns the counter names in sorted order
+func (s *Summary) CounterNames() []string {
+	names := make([]string, 0, len(s.Counters))
+	for name := range s.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WriteJSON writes the summary as JSON to path, or to stdout when path is "-"
+func (s *Summary) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"time"
+)
+
+// Task is one unit of work performed by a daemon on every tick. Counters added
+// to report are accumulated into the run summary.
+type Task func(ctx context.Context, report *Report) error
+
+// Options configures a daemon loop
+type Options struct {
+	// Name identifies the daemon in health output and service names
+	Name string
+
+	// Interval is the time between the end of one run and the start of the next
+	Interval time.Duration
+
+	// HealthAddr, when set, serves /healthz and /readyz on this address
+	HealthAddr string
+
+	// OnError is called with every error returned by the task
+	OnError func(err error)
+
+	// OnHealthServe is called with the bound address once the health server starts
+	OnHealthServe func(addr string)
+
+	// Once runs the task a single time and returns its error instead of looping,
+	// for scheduling with cron or a systemd timer
+	Once bool
+}
+
+// Run executes task every opts.Interval until ctx is cancelled. Task errors
+// are reported through OnError and mark the daemon not ready, but do not stop
+// the loop. When running under systemd, READY=1 is sent after the first run
+// and watchdog pings are sent while the loop is healthy.
+//
+// With opts.Once the task runs exactly once and its error is returned. In
+// both modes the returned summary describes every run that took place.
+func Run(ctx context.Context, opts Options, task Task) (*Summary, error) {
+	if opts.Once {
+		return runOnce(ctx, opts, task)
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+
+	// Consider the daemon stale if it misses a few runs in a row
+	health := NewHealth(opts.Name, 3*opts.Interval+time.Minute)
+
+	if opts.HealthAddr != "" {
+		addr, err := health.Serve(opts.HealthAddr)
+		if err != nil {
+			return nil, err
+		}
+		if opts.OnHealthServe != nil {
+			opts.OnHealthServe(addr)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			health.Shutdown(shutdownCtx)
+		}()
+	}
+
+	if watchdog := WatchdogInterval(); watchdog > 0 {
+		go runWatchdog(ctx, health, watchdog/2)
+	}
+
+	defer Notify(NotifyStopping)
+
+	summary := newSummary(opts.Name, ModeWatch)
+	first := true
+	for {
+		report := NewReport()
+		err := task(ctx, report)
+		health.RecordRun(err)
+		summary.record(report, err)
+		if err != nil {
+			if opts.OnError != nil && ctx.Err() == nil {
+				opts.OnError(err)
+			}
+			NotifyStatus("last run failed: " + err.Error())
+		} else {
+			NotifyStatus("last run succeeded at " + time.Now().Format(time.RFC3339))
+		}
+
+		if first {
+			Notify(NotifyReady)
+			first = false
+		}
+
+		select {
+		case <-ctx.Done():
+			summary.finish()
+			return summary, nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// runOnce performs a single run. Health endpoints are not served and no
+// service manager notifications are sent, since the process exits right after.
+func runOnce(ctx context.Context, opts Options, task Task) (*Summary, error) {
+	summary := newSummary(opts.Name, ModeOnce)
+
+	report := NewReport()
+	err := task(ctx, report)
+	summary.record(report, err)
+	summary.finish()
+
+	if err != nil && opts.OnError != nil {
+		opts.OnError(err)
+	}
+
+	return summary, err
+}
+
+// runWatchdog pings the systemd watchdog while the daemon stays alive
+func runWatchdog(ctx context.Context, health *Health, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if health.Alive() {
+				Notify(NotifyWatchdog)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package desktop integrates playback with the operating system: media keys
+// control the Player API and track changes raise native notifications.
+// Windows is supported; other platforms report ErrUnsupported until their
+// backends (MPRIS on Linux) are added.
+package desktop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// ErrUnsupported is returned on platforms without a desktop backend
+var ErrUnsupported = errors.New("desktop integration is not supported on this platform")
+
+// Key is a media key
+type Key int
+
+const (
+	KeyPlayPause Key = iota
+	KeyNext
+	KeyPrevious
+	KeyStop
+)
+
+func (k Key) String() string {
+	switch k {
+	case KeyPlayPause:
+		return "play/pause"
+	case KeyNext:
+		return "next"
+	case KeyPrevious:
+		return "previous"
+	case KeyStop:
+		return "stop"
+	default:
+		return "unknown"
+	}
+}
+
+// Notification is a native notification about a track change
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// TrackNotification describes the item now playing
+func TrackNotification(item *models.PlaylistItem) Notification {
+	n := Notification{Title: item.Name()}
+	if artists := item.ArtistNames(); len(artists) > 0 {
+		n.Body = strings.Join(artists, ", ")
+	}
+	if album := item.AlbumName(); album != "" {
+		if n.Body != "" {
+			n.Body += " — "
+		}
+		n.Body += album
+	}
+	return n
+}
+
+// NowPlaying returns the item currently playing, or nil when nothing is
+type NowPlaying func(ctx context.Context) (*models.PlaylistItem, error)
+
+// WatchTracks polls now at interval and calls changed whenever a different
+// item starts playing, including the one playing when it starts. Polling
+// errors are passed to onError and do not stop the watch; it returns when
+// ctx is done.
+func WatchTracks(ctx context.Context, now NowPlaying, interval time.Duration, changed func(*models.PlaylistItem), onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastURI := ""
+	for {
+		item, err := now(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if onError != nil {
+				onError(err)
+			}
+		case item == nil:
+			lastURI = ""
+		case item.URI() != lastURI:
+			lastURI = item.URI()
+			changed(item)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
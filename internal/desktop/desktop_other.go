@@ -0,0 +1,18 @@
+//go:build !windows
+
+package desktop
+
+import "context"
+
+// Supported reports whether this platform has a desktop backend
+const Supported = false
+
+// ListenMediaKeys is not available on this platform
+func ListenMediaKeys(ctx context.Context, handle func(Key)) error {
+	return ErrUnsupported
+}
+
+// Notify is not available on this platform
+func Notify(ctx context.Context, n Notification) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,71 @@
+package desktop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func track(t *testing.T, id string) *models.PlaylistItem {
+	t.Helper()
+
+	var item models.PlaylistItem
+	data := `{"type": "track", "id": "` + id + `", "uri": "spotify:track:` + id + `", "name": "Song ` + id + `",
+		"artists": [{"name": "A"}, {"name": "B"}], "album": {"name": "Album"}}`
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatalf("Failed to build track: %v", err)
+	}
+	return &item
+}
+
+func TestTrackNotification(t *testing.T) {
+	n := TrackNotification(track(t, "1"))
+	if n.Title != "Song 1" || n.Body != "A, B — Album" {
+		t.Errorf("Unexpected notification %+v", n)
+	}
+}
+
+func TestWatchTracks(t *testing.T) {
+	// Polls see track 1 twice, an error, track 2, nothing, then track 2 again
+	polls := []*models.PlaylistItem{track(t, "1"), track(t, "1"), nil, track(t, "2"), nil, track(t, "2")}
+	pollErr := errors.New("offline")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	now := func(ctx context.Context) (*models.PlaylistItem, error) {
+		calls++
+		switch {
+		case calls == 3:
+			return nil, pollErr
+		case calls > len(polls):
+			cancel()
+			return nil, ctx.Err()
+		}
+		return polls[calls-1], nil
+	}
+
+	var changes []string
+	var errs []error
+	err := WatchTracks(ctx, now, time.Millisecond, func(item *models.PlaylistItem) {
+		changes = append(changes, item.ID())
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the watch to end with the context, got %v", err)
+	}
+	// Track 2 is reported again after playback stopped in between
+	if len(changes) != 3 || changes[0] != "1" || changes[1] != "2" || changes[2] != "2" {
+		t.Errorf("Unexpected changes %v", changes)
+	}
+	if len(errs) != 1 || errs[0] != pollErr {
+		t.Errorf("Expected one polling error, got %v", errs)
+	}
+}
@@ -0,0 +1,158 @@
+//go:build windows
+
+package desktop
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// Supported reports whether this platform has a desktop backend
+const Supported = true
+
+var (
+	user32   = syscall.NewLazyDLL("user32.dll")
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+
+	procRegisterHotKey     = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey   = user32.NewProc("UnregisterHotKey")
+	procGetMessageW        = user32.NewProc("GetMessageW")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+	procGetCurrentThreadID = kernel32.NewProc("GetCurrentThreadId")
+)
+
+const (
+	wmQuit      = 0x0012
+	wmHotkey    = 0x0312
+	modNoRepeat = 0x4000
+)
+
+// Virtual key codes of the media keys
+var mediaKeys = map[Key]uintptr{
+	KeyNext:      0xB0, // VK_MEDIA_NEXT_TRACK
+	KeyPrevious:  0xB1, // VK_MEDIA_PREV_TRACK
+	KeyStop:      0xB2, // VK_MEDIA_STOP
+	KeyPlayPause: 0xB3, // VK_MEDIA_PLAY_PAUSE
+}
+
+// msg mirrors the Win32 MSG structure
+type msg struct {
+	hwnd     uintptr
+	message  uint32
+	wParam   uintptr
+	lParam   uintptr
+	time     uint32
+	pt       [2]int32
+	lPrivate uint32
+}
+
+// ListenMediaKeys registers the media keys as global hotkeys and calls handle
+// for each press until ctx is done. handle runs on the message loop thread,
+// so presses are handled one at a time in order.
+//
+// Hotkeys take the keys from other applications, including the Spotify
+// desktop app, while they are registered. Registration fails if another
+// application already holds them.
+func ListenMediaKeys(ctx context.Context, handle func(Key)) error {
+	threadID := make(chan uintptr, 1)
+	done := make(chan error, 1)
+
+	go func() {
+		// Hotkey messages go to the thread that registered them
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		var registered []uintptr
+		defer func() {
+			for _, id := range registered {
+				procUnregisterHotKey.Call(0, id)
+			}
+		}()
+
+		for key, vk := range mediaKeys {
+			id := uintptr(key) + 1
+			if ok, _, err := procRegisterHotKey.Call(0, id, modNoRepeat, vk); ok == 0 {
+				done <- fmt.Errorf("failed to register the %s media key: %w", key, err)
+				return
+			}
+			registered = append(registered, id)
+		}
+
+		tid, _, _ := procGetCurrentThreadID.Call()
+		threadID <- tid
+
+		var m msg
+		for {
+			// GetMessage returns 0 for WM_QUIT and -1 on error
+			r, _, err := procGetMessageW.Call(uintptr(unsafe.Pointer(&m)), 0, 0, 0)
+			switch int32(r) {
+			case 0:
+				done <- nil
+				return
+			case -1:
+				done <- fmt.Errorf("media key message loop failed: %w", err)
+				return
+			}
+			if m.message == wmHotkey && m.wParam > 0 {
+				handle(Key(m.wParam - 1))
+			}
+		}
+	}()
+
+	var tid uintptr
+	select {
+	case err := <-done:
+		return err
+	case tid = <-threadID:
+	}
+
+	select {
+	case <-ctx.Done():
+		procPostThreadMessageW.Call(tid, wmQuit, 0, 0)
+		<-done
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// toastAppID is the application user model ID toasts are shown under.
+// Windows only shows toasts for registered applications, so they appear as
+// coming from PowerShell rather than registering spotify-cli.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript shows the toast XML in $env:SPOTIFY_CLI_TOAST through the
+// WinRT notification API
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml($env:SPOTIFY_CLI_TOAST)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:SPOTIFY_CLI_TOAST_APP).Show($toast)
+`
+
+// Notify shows a toast notification
+func Notify(ctx context.Context, n Notification) error {
+	var title, body bytes.Buffer
+	xml.EscapeText(&title, []byte(n.Title))
+	xml.EscapeText(&body, []byte(n.Body))
+
+	toast := fmt.Sprintf(`<toast><visual><binding template="ToastGeneric"><text>%s</text><text>%s</text></binding></visual><audio silent="true"/></toast>`, title.String(), body.String())
+
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(os.Environ(), "SPOTIFY_CLI_TOAST="+toast, "SPOTIFY_CLI_TOAST_APP="+toastAppID)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to show notification: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return nil
+}
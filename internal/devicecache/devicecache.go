@@ -0,0 +1,87 @@
+// Package devicecache remembers what each Spotify Connect device can do, as
+// last reported by the devices endpoint, so player commands can warn about
+// an unsupported operation before sending it instead of after a 403.
+package devicecache
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding device capabilities
+const StoreName = "devices"
+
+// MaxAge is how long capabilities are trusted. A device's volume support
+// rarely changes, but restrictions come and go with things like private
+// sessions and Spotify Connect groups.
+const MaxAge = time.Hour
+
+// Retention is how long devices that have not been seen are kept
+const Retention = 30 * 24 * time.Hour
+
+// Capabilities is what a device reported the last time it was listed
+type Capabilities struct {
+	ID             string    `json:"id" yaml:"id"`
+	Name           string    `json:"name" yaml:"name"`
+	Type           string    `json:"type" yaml:"type"`
+	SupportsVolume bool      `json:"supports_volume" yaml:"supports_volume"`
+	IsRestricted   bool      `json:"is_restricted" yaml:"is_restricted"`
+	SeenAt         time.Time `json:"seen_at" yaml:"seen_at"`
+}
+
+// Fresh reports whether the capabilities are recent enough to act on
+func (c Capabilities) Fresh(now time.Time) bool {
+	return now.Sub(c.SeenAt) < MaxAge
+}
+
+type store struct {
+	Devices map[string]Capabilities `json:"devices"`
+}
+
+// Record saves the capabilities of listed devices. Devices without an ID,
+// which cannot be controlled anyway, are skipped.
+func Record(dir *state.Dir, devices []models.Device, now time.Time) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		if data.Devices == nil {
+			data.Devices = make(map[string]Capabilities)
+		}
+
+		for _, device := range devices {
+			if device.ID == "" {
+				continue
+			}
+			data.Devices[device.ID] = Capabilities{
+				ID:             device.ID,
+				Name:           device.Name,
+				Type:           device.Type,
+				SupportsVolume: device.SupportsVolume,
+				IsRestricted:   device.IsRestricted,
+				SeenAt:         now,
+			}
+		}
+
+		for id, caps := range data.Devices {
+			if now.Sub(caps.SeenAt) > Retention {
+				delete(data.Devices, id)
+			}
+		}
+		return nil
+	})
+}
+
+// Lookup returns the capabilities of a device if they are fresh
+func Lookup(dir *state.Dir, deviceID string, now time.Time) (Capabilities, bool, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return Capabilities{}, false, err
+	}
+
+	caps, ok := data.Devices[deviceID]
+	if !ok || !caps.Fresh(now) {
+		return Capabilities{}, false, nil
+	}
+	return caps, true, nil
+}
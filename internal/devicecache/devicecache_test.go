@@ -0,0 +1,55 @@
+package devicecache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRecordAndLookup(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	now := time.Now().UTC()
+	old := now.Add(-Retention - time.Hour)
+
+	if err := Record(dir, []models.Device{{ID: "gone", Name: "Old Phone"}}, old); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	devices := []models.Device{
+		{ID: "nest", Name: "Nest Mini", Type: "CastAudio", SupportsVolume: false},
+		{ID: "desk", Name: "Desktop", Type: "Computer", SupportsVolume: true},
+		{ID: "", Name: "Car", IsRestricted: true},
+	}
+	if err := Record(dir, devices, now); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+
+	caps, ok, err := Lookup(dir, "nest", now.Add(time.Minute))
+	if err != nil || !ok {
+		t.Fatalf("Expected nest to be cached, got %v, %v", ok, err)
+	}
+	if caps.SupportsVolume || caps.Type != "CastAudio" {
+		t.Errorf("Unexpected capabilities %+v", caps)
+	}
+
+	if _, ok, _ := Lookup(dir, "desk", now.Add(MaxAge+time.Minute)); ok {
+		t.Error("Expected stale capabilities to be ignored")
+	}
+
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		t.Fatalf("Failed to read store: %v", err)
+	}
+	if _, ok := data.Devices["gone"]; ok {
+		t.Error("Expected a device unseen past the retention period to be dropped")
+	}
+	if len(data.Devices) != 2 {
+		t.Errorf("Expected 2 devices, got %+v", data.Devices)
+	}
+}
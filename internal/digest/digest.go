@@ -0,0 +1,132 @@
+// Package digest builds a periodic summary of listening activity: tracks
+// saved, the most played tracks and new releases from followed artists,
+// rendered as Markdown or HTML for email or chat.
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// Save is a track saved to the library during the period
+type Save struct {
+	Track   models.Track `json:"track"`
+	AddedAt time.Time    `json:"added_at"`
+}
+
+// Play is a track played during the period and how often
+type Play struct {
+	Track      models.Track `json:"track"`
+	Count      int          `json:"count"`
+	LastPlayed time.Time    `json:"last_played"`
+}
+
+// Release is an album or single released during the period
+type Release struct {
+	Album    models.SimpleAlbum `json:"album"`
+	Released time.Time          `json:"released"`
+}
+
+// Digest is the summary of one period
+type Digest struct {
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	NewSaves    []Save    `json:"new_saves"`
+	TopPlays    []Play    `json:"top_plays"`
+	NewReleases []Release `json:"new_releases"`
+}
+
+// Empty reports whether nothing happened during the period
+func (d *Digest) Empty() bool {
+	return len(d.NewSaves) == 0 && len(d.TopPlays) == 0 && len(d.NewReleases) == 0
+}
+
+// SavedSince keeps the saved tracks added at or after since, newest first
+func SavedSince(saved []models.SavedTrack, since time.Time) []Save {
+	var saves []Save
+	for _, s := range saved {
+		added, err := time.Parse(time.RFC3339, s.AddedAt)
+		if err != nil || added.Before(since) {
+			continue
+		}
+		saves = append(saves, Save{Track: s.Track, AddedAt: added})
+	}
+
+	sort.SliceStable(saves, func(i, j int) bool {
+		return saves[i].AddedAt.After(saves[j].AddedAt)
+	})
+	return saves
+}
+
+// TopPlays counts the plays at or after since and returns the n most played
+// tracks. Ties go to the track played most recently.
+func TopPlays(history []models.PlayHistory, since time.Time, n int) []Play {
+	byID := make(map[string]*Play)
+	var plays []*Play
+	for _, h := range history {
+		playedAt, err := time.Parse(time.RFC3339, h.PlayedAt)
+		if err != nil || playedAt.Before(since) || h.Track.ID == "" {
+			continue
+		}
+
+		play := byID[h.Track.ID]
+		if play == nil {
+			play = &Play{Track: h.Track}
+			byID[h.Track.ID] = play
+			plays = append(plays, play)
+		}
+		play.Count++
+		if playedAt.After(play.LastPlayed) {
+			play.LastPlayed = playedAt
+		}
+	}
+
+	sort.SliceStable(plays, func(i, j int) bool {
+		if plays[i].Count != plays[j].Count {
+			return plays[i].Count > plays[j].Count
+		}
+		return plays[i].LastPlayed.After(plays[j].LastPlayed)
+	})
+
+	top := make([]Play, 0, min(n, len(plays)))
+	for _, play := range plays {
+		if len(top) == n {
+			break
+		}
+		top = append(top, *play)
+	}
+	return top
+}
+
+// ReleasedSince keeps the albums released at or after since, newest first.
+// An album appearing under several followed artists is listed once. Albums
+// dated only by month or year are skipped, since there is no telling
+// whether they came out during the period.
+func ReleasedSince(albums []models.SimpleAlbum, since time.Time) []Release {
+	// Release dates have no time of day, so compare whole days
+	day := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+
+	seen := make(map[string]bool)
+	var releases []Release
+	for _, album := range albums {
+		if seen[album.ID] || album.Precision != models.DatePrecisionDay {
+			continue
+		}
+		released, ok := album.Released()
+		if !ok {
+			continue
+		}
+		if released.Before(day) {
+			continue
+		}
+		seen[album.ID] = true
+		releases = append(releases, Release{Album: album, Released: released})
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Released.After(releases[j].Released)
+	})
+	return releases
+}
@@ -0,0 +1,145 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+var since = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func track(id, name string) models.Track {
+	return models.Track{ID: id, Name: name, Artists: []models.SimpleArtist{{Name: "Artist " + id}}}
+}
+
+func TestSavedSince(t *testing.T) {
+	saves := SavedSince([]models.SavedTrack{
+		{AddedAt: "2024-03-05T10:00:00Z", Track: track("a", "Older")},
+		{AddedAt: "2024-03-07T10:00:00Z", Track: track("b", "Newer")},
+		{AddedAt: "2024-02-01T10:00:00Z", Track: track("c", "Before")},
+	}, since)
+
+	if len(saves) != 2 || saves[0].Track.ID != "b" || saves[1].Track.ID != "a" {
+		t.Errorf("Expected the two saves in the period newest first, got %+v", saves)
+	}
+}
+
+func TestTopPlays(t *testing.T) {
+	history := []models.PlayHistory{
+		{Track: track("a", "Once"), PlayedAt: "2024-03-08T10:00:00Z"},
+		{Track: track("b", "Twice"), PlayedAt: "2024-03-06T10:00:00Z"},
+		{Track: track("b", "Twice"), PlayedAt: "2024-03-05T10:00:00Z"},
+		{Track: track("c", "Earlier once"), PlayedAt: "2024-03-05T09:00:00Z"},
+		{Track: track("d", "Before"), PlayedAt: "2024-03-01T10:00:00Z"},
+	}
+
+	plays := TopPlays(history, since, 2)
+	if len(plays) != 2 {
+		t.Fatalf("Expected 2 plays, got %d", len(plays))
+	}
+	if plays[0].Track.ID != "b" || plays[0].Count != 2 {
+		t.Errorf("Expected the track played twice first, got %s (%d)", plays[0].Track.ID, plays[0].Count)
+	}
+	if plays[1].Track.ID != "a" {
+		t.Errorf("Expected ties to go to the most recent play, got %s", plays[1].Track.ID)
+	}
+}
+
+func TestReleasedSince(t *testing.T) {
+	album := func(id, date string, precision models.DatePrecision) models.SimpleAlbum {
+		return models.SimpleAlbum{ID: id, Name: "Album " + id,
+			ReleaseDatePrecision: models.ReleaseDatePrecision{DateStr: date, Precision: precision}}
+	}
+
+	releases := ReleasedSince([]models.SimpleAlbum{
+		album("a", "2024-03-04", models.DatePrecisionDay),
+		album("b", "2024-03-08", models.DatePrecisionDay),
+		album("a", "2024-03-04", models.DatePrecisionDay),
+		album("c", "2024-03", models.DatePrecisionMonth),
+		album("d", "2024-02-28", models.DatePrecisionDay),
+	}, since)
+
+	if len(releases) != 2 || releases[0].Album.ID != "b" || releases[1].Album.ID != "a" {
+		t.Errorf("Expected releases b and a, got %+v", releases)
+	}
+}
+
+func TestRender(t *testing.T) {
+	d := &Digest{
+		Since:    since,
+		Until:    since.AddDate(0, 0, 7),
+		TopPlays: []Play{{Track: track("a", "Fish & Chips"), Count: 3}},
+	}
+
+	md := d.Markdown()
+	if !strings.Contains(md, "1. **Fish & Chips** — Artist a (3 plays)") {
+		t.Errorf("Unexpected markdown:\n%s", md)
+	}
+	if strings.Contains(md, "Newly saved") {
+		t.Error("Expected empty sections to be left out")
+	}
+
+	html, err := d.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "Fish &amp; Chips") {
+		t.Errorf("Expected escaped track names in HTML:\n%s", html)
+	}
+
+	if !strings.Contains((&Digest{Since: since, Until: since}).Markdown(), "Nothing new") {
+		t.Error("Expected a note for an empty digest")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	msg, err := Message("me@example.com", []string{"you@example.com"}, "Digest — week 10", "plain\nbody", "<p>html</p>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := string(msg)
+	for _, want := range []string{
+		"Subject: =?utf-8?q?",
+		"Content-Type: multipart/alternative;",
+		"Content-Type: text/plain; charset=utf-8",
+		"plain\r\nbody",
+		"Content-Type: text/html; charset=utf-8",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected message to contain %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	d := &Digest{Since: since, Until: since, NewSaves: []Save{{Track: track("a", "Song")}}}
+	if err := PostWebhook(context.Background(), server.URL, d); err != nil {
+		t.Fatalf("PostWebhook failed: %v", err)
+	}
+	if !strings.Contains(payload.Text, "Song") || payload.Digest == nil || len(payload.Digest.NewSaves) != 1 {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	if err := PostWebhook(context.Background(), failing.URL, d); err == nil {
+		t.Error("Expected an error for a failing webhook")
+	}
+}
@@ -0,0 +1,117 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// Title names the digest after its period, like "Your music digest: Jan 1 – Jan 7"
+func (d *Digest) Title() string {
+	return fmt.Sprintf("Your music digest: %s – %s", d.Since.Format("Jan 2"), d.Until.Format("Jan 2"))
+}
+
+func artistNames(artists []models.SimpleArtist) string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// Markdown renders the digest for chat and plain-text email
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", d.Title())
+
+	if d.Empty() {
+		b.WriteString("\nNothing new since the last digest.\n")
+		return b.String()
+	}
+
+	if len(d.TopPlays) > 0 {
+		b.WriteString("\n## Most played\n\n")
+		for i, play := range d.TopPlays {
+			fmt.Fprintf(&b, "%d. **%s** — %s (%s)\n", i+1, play.Track.Name, artistNames(play.Track.Artists), plural(play.Count, "play"))
+		}
+	}
+
+	if len(d.NewSaves) > 0 {
+		fmt.Fprintf(&b, "\n## Newly saved (%d)\n\n", len(d.NewSaves))
+		for _, save := range d.NewSaves {
+			fmt.Fprintf(&b, "- **%s** — %s\n", save.Track.Name, artistNames(save.Track.Artists))
+		}
+	}
+
+	if len(d.NewReleases) > 0 {
+		b.WriteString("\n## New releases from artists you follow\n\n")
+		for _, release := range d.NewReleases {
+			fmt.Fprintf(&b, "- **%s** — %s (%s, %s)", release.Album.Name, artistNames(release.Album.Artists),
+				release.Album.AlbumType, release.Released.Format("Jan 2"))
+			if url := release.Album.ExternalURLs.Spotify; url != "" {
+				fmt.Fprintf(&b, " [listen](%s)", url)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"artists": artistNames,
+	"plural":  plural,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; max-width: 600px; margin: auto; color: #191414;">
+<h1 style="color: #1DB954;">{{.Title}}</h1>
+{{- if .Empty}}
+<p>Nothing new since the last digest.</p>
+{{- end}}
+{{- with .TopPlays}}
+<h2>Most played</h2>
+<ol>
+{{- range .}}
+<li><strong>{{.Track.Name}}</strong> — {{artists .Track.Artists}} ({{plural .Count "play"}})</li>
+{{- end}}
+</ol>
+{{- end}}
+{{- with .NewSaves}}
+<h2>Newly saved ({{len .}})</h2>
+<ul>
+{{- range .}}
+<li><strong>{{.Track.Name}}</strong> — {{artists .Track.Artists}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .NewReleases}}
+<h2>New releases from artists you follow</h2>
+<ul>
+{{- range .}}
+<li>{{if .Album.ExternalURLs.Spotify}}<a href="{{.Album.ExternalURLs.Spotify}}"><strong>{{.Album.Name}}</strong></a>{{else}}<strong>{{.Album.Name}}</strong>{{end}} — {{artists .Album.Artists}} ({{.Album.AlbumType}}, {{.Released.Format "Jan 2"}})</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML renders the digest as an email-friendly page with inline styles
+func (d *Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
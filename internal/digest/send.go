@@ -0,0 +1,132 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// SMTPConfig is where and how to send digest emails
+type SMTPConfig struct {
+	// Addr is the server as host:port. The connection is upgraded with
+	// STARTTLS when the server offers it; implicit TLS on port 465 is not
+	// supported.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Message builds a multipart email with the Markdown as the plain text part
+// and the HTML as the rich part
+func Message(from string, to []string, subject, text, html string) ([]byte, error) {
+	boundary, err := randomBoundary()
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", text},
+		{"text/html", html},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(part.body, "\r\n", "\n"), "\n", "\r\n"))
+		b.WriteString("\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+
+	return b.Bytes(), nil
+}
+
+func randomBoundary() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate MIME boundary: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// SendMail emails the digest as Markdown and HTML alternatives
+func SendMail(cfg SMTPConfig, d *Digest) error {
+	if cfg.Addr == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return errors.NewConfigError("SMTP server, sender and at least one recipient are required")
+	}
+
+	html, err := d.HTML()
+	if err != nil {
+		return err
+	}
+	msg, err := Message(cfg.From, cfg.To, d.Title(), d.Markdown(), html)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		host, _, err := net.SplitHostPort(cfg.Addr)
+		if err != nil {
+			return errors.WrapConfigError(err, fmt.Sprintf("invalid SMTP address %q", cfg.Addr))
+		}
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+
+	if err := smtp.SendMail(cfg.Addr, auth, cfg.From, cfg.To, msg); err != nil {
+		return errors.WrapNetworkError(err, "failed to send digest email")
+	}
+	return nil
+}
+
+// WebhookPayload is the JSON posted to a webhook. Text holds the Markdown,
+// which Slack, Mattermost and similar incoming webhooks display as is.
+type WebhookPayload struct {
+	Text   string  `json:"text"`
+	Digest *Digest `json:"digest"`
+}
+
+// PostWebhook posts the digest to url as a WebhookPayload
+func PostWebhook(ctx context.Context, url string, d *Digest) error {
+	body, err := json.Marshal(WebhookPayload{Text: d.Markdown(), Digest: d})
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WrapConfigError(err, "invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WrapNetworkError(err, "failed to post digest")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.NewNetworkError(fmt.Sprintf("webhook returned %s", resp.Status))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package discord
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// dial connects to the first of discord-ipc-0 to discord-ipc-9 that is
+// listening, in the runtime and temporary folders and in those of the
+// Flatpak and Snap packages of Discord under them
+func dial() (io.ReadWriteCloser, error) {
+	for _, dir := range socketDirs() {
+		for i := 0; i < 10; i++ {
+			conn, err := net.Dial("unix", filepath.Join(dir, fmt.Sprintf("discord-ipc-%d", i)))
+			if err == nil {
+				return conn, nil
+			}
+		}
+	}
+	return nil, ErrNotRunning
+}
+
+func socketDirs() []string {
+	var bases []string
+	for _, name := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		if dir := os.Getenv(name); dir != "" {
+			bases = append(bases, dir)
+		}
+	}
+	bases = append(bases, "/tmp")
+
+	var dirs []string
+	for _, base := range bases {
+		dirs = append(dirs, base, filepath.Join(base, "app", "com.discordapp.Discord"), filepath.Join(base, "snap.discord"))
+	}
+	return dirs
+}
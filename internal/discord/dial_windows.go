@@ -0,0 +1,21 @@
+//go:build windows
+
+package discord
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// dial opens the first of the named pipes discord-ipc-0 to discord-ipc-9
+// that exists
+func dial() (io.ReadWriteCloser, error) {
+	for i := 0; i < 10; i++ {
+		pipe, err := os.OpenFile(fmt.Sprintf(`\\.\pipe\discord-ipc-%d`, i), os.O_RDWR, 0)
+		if err == nil {
+			return pipe, nil
+		}
+	}
+	return nil, ErrNotRunning
+}
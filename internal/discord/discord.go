@@ -0,0 +1,273 @@
+// Package discord publishes Rich Presence to the Discord desktop app over the
+// local IPC socket it listens on, as Discord's own SDKs do. Nothing goes
+// through Discord's servers other than what the app itself sends on.
+package discord
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNotRunning is returned by Connect when no Discord app is listening
+var ErrNotRunning = errors.New("Discord is not running, or its IPC socket could not be found")
+
+// Opcodes of the frames on the socket
+const (
+	opHandshake = 0
+	opFrame     = 1
+	opClose     = 2
+	opPing      = 3
+	opPong      = 4
+)
+
+// replyTimeout is how long the app has to answer a command
+const replyTimeout = 10 * time.Second
+
+// maxFrame bounds the payloads read, which are small JSON objects
+const maxFrame = 64 * 1024
+
+// ActivityListening shows the activity as "Listening to ..."
+const ActivityListening = 2
+
+// Activity is what the user's profile shows they are doing
+type Activity struct {
+	Type       int         `json:"type"`
+	Details    string      `json:"details,omitempty"`
+	State      string      `json:"state,omitempty"`
+	Timestamps *Timestamps `json:"timestamps,omitempty"`
+	Assets     *Assets     `json:"assets,omitempty"`
+	Buttons    []Button    `json:"buttons,omitempty"`
+}
+
+// Timestamps draw elapsed and remaining time, in Unix milliseconds
+type Timestamps struct {
+	Start int64 `json:"start,omitempty"`
+	End   int64 `json:"end,omitempty"`
+}
+
+// Assets are the images of an activity. Images may be https URLs.
+type Assets struct {
+	LargeImage string `json:"large_image,omitempty"`
+	LargeText  string `json:"large_text,omitempty"`
+	SmallImage string `json:"small_image,omitempty"`
+	SmallText  string `json:"small_text,omitempty"`
+}
+
+// Button is a link shown under the activity to other users
+type Button struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+// timestampSlack is how far timestamps may drift before an activity counts
+// as changed; polling makes the start of a track wander by a few hundred
+// milliseconds
+const timestampSlack = 2000
+
+// Same reports whether two activities look the same, so an unchanged one is
+// not sent again. Discord allows only a few updates a minute.
+func Same(a, b *Activity) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if (a.Timestamps == nil) != (b.Timestamps == nil) {
+		return false
+	}
+	if a.Timestamps != nil {
+		if abs(a.Timestamps.Start-b.Timestamps.Start) > timestampSlack || abs(a.Timestamps.End-b.Timestamps.End) > timestampSlack {
+			return false
+		}
+	}
+	ca, cb := *a, *b
+	ca.Timestamps, cb.Timestamps = nil, nil
+	ja, _ := json.Marshal(ca)
+	jb, _ := json.Marshal(cb)
+	return string(ja) == string(jb)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Error is a failure the app reported
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("discord error %d: %s", e.Code, e.Message)
+}
+
+// Client is a connection to the Discord app
+type Client struct {
+	mu   sync.Mutex
+	conn io.ReadWriteCloser
+}
+
+// message is a command sent, or a reply or event received
+type message struct {
+	Cmd   string          `json:"cmd,omitempty"`
+	Evt   string          `json:"evt,omitempty"`
+	Nonce string          `json:"nonce,omitempty"`
+	Args  interface{}     `json:"args,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// Connect finds the app's socket and introduces clientID, the ID of the
+// Discord application the activity is shown as
+func Connect(clientID string) (*Client, error) {
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn}
+
+	if err := c.write(opHandshake, map[string]interface{}{"v": 1, "client_id": clientID}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reply, err := c.reply("")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if reply.Evt != "READY" {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected handshake reply %q", reply.Evt)
+	}
+	return c, nil
+}
+
+// SetActivity shows an activity, or clears it when activity is nil
+func (c *Client) SetActivity(activity *Activity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	err = c.write(opFrame, message{
+		Cmd:   "SET_ACTIVITY",
+		Nonce: nonce,
+		Args: map[string]interface{}{
+			"pid":      os.Getpid(),
+			"activity": activity,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.reply(nonce)
+	return err
+}
+
+// Close ends the connection, which also clears the activity
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// reply reads frames until the one answering nonce, or the first one when
+// nonce is empty, answering pings on the way
+func (c *Client) reply(nonce string) (*message, error) {
+	if d, ok := c.conn.(interface{ SetReadDeadline(time.Time) error }); ok {
+		d.SetReadDeadline(time.Now().Add(replyTimeout))
+		defer d.SetReadDeadline(time.Time{})
+	}
+
+	for {
+		op, payload, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeRaw(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opClose:
+			var closed Error
+			if err := json.Unmarshal(payload, &closed); err != nil || closed.Message == "" {
+				return nil, fmt.Errorf("Discord closed the connection")
+			}
+			return nil, &closed
+		case opFrame:
+		default:
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return nil, fmt.Errorf("failed to decode reply: %w", err)
+		}
+		if nonce != "" && msg.Nonce != nonce {
+			continue
+		}
+		if msg.Evt == "ERROR" {
+			var apiErr Error
+			if err := json.Unmarshal(msg.Data, &apiErr); err != nil {
+				return nil, fmt.Errorf("failed to decode error: %w", err)
+			}
+			return nil, &apiErr
+		}
+		return &msg, nil
+	}
+}
+
+// write sends a frame: its opcode and length, little endian, then the JSON
+func (c *Client) write(op uint32, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeRaw(op, payload)
+}
+
+func (c *Client) writeRaw(op uint32, payload []byte) error {
+	frame := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(frame[0:4], op)
+	binary.LittleEndian.PutUint32(frame[4:8], uint32(len(payload)))
+	copy(frame[8:], payload)
+	if _, err := c.conn.Write(frame); err != nil {
+		return fmt.Errorf("failed to write to Discord: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) read() (uint32, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return 0, nil, fmt.Errorf("failed to read from Discord: %w", err)
+	}
+	op := binary.LittleEndian.Uint32(header[0:4])
+	length := binary.LittleEndian.Uint32(header[4:8])
+	if length > maxFrame {
+		return 0, nil, fmt.Errorf("reply of %d bytes is too large", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		return 0, nil, fmt.Errorf("failed to read from Discord: %w", err)
+	}
+	return op, payload, nil
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,249 @@
+//go:build !windows
+
+package discord
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDiscord listens where Discord would and answers with handle, which
+// gets each frame received and returns the frames to reply with
+func fakeDiscord(t *testing.T, handle func(op uint32, msg map[string]interface{}) [][]byte) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	listener, err := net.Listen("unix", filepath.Join(dir, "discord-ipc-0"))
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var header [8]byte
+			if _, err := io.ReadFull(conn, header[:]); err != nil {
+				return
+			}
+			payload := make([]byte, binary.LittleEndian.Uint32(header[4:8]))
+			if _, err := io.ReadFull(conn, payload); err != nil {
+				return
+			}
+			var msg map[string]interface{}
+			json.Unmarshal(payload, &msg)
+			for _, reply := range handle(binary.LittleEndian.Uint32(header[0:4]), msg) {
+				conn.Write(reply)
+			}
+		}
+	}()
+}
+
+func frame(op uint32, v interface{}) []byte {
+	payload, _ := json.Marshal(v)
+	b := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(b[0:4], op)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(len(payload)))
+	copy(b[8:], payload)
+	return b
+}
+
+func TestSetActivity(t *testing.T) {
+	var received map[string]interface{}
+	fakeDiscord(t, func(op uint32, msg map[string]interface{}) [][]byte {
+		switch op {
+		case opHandshake:
+			if msg["client_id"] != "123" {
+				t.Errorf("Expected client ID 123, got %v", msg["client_id"])
+			}
+			return [][]byte{frame(opFrame, map[string]interface{}{"cmd": "DISPATCH", "evt": "READY"})}
+		default:
+			received = msg
+			// A ping and another command's reply come before the answer
+			return [][]byte{
+				frame(opPing, map[string]interface{}{}),
+				frame(opFrame, map[string]interface{}{"cmd": "SET_ACTIVITY", "nonce": "other"}),
+				frame(opFrame, map[string]interface{}{"cmd": "SET_ACTIVITY", "nonce": msg["nonce"]}),
+			}
+		}
+	})
+
+	c, err := Connect("123")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.SetActivity(&Activity{Type: ActivityListening, Details: "Song"}); err != nil {
+		t.Fatalf("SetActivity failed: %v", err)
+	}
+	if received["cmd"] != "SET_ACTIVITY" {
+		t.Fatalf("Expected SET_ACTIVITY, got %v", received)
+	}
+	activity := received["args"].(map[string]interface{})["activity"].(map[string]interface{})
+	if activity["details"] != "Song" || activity["type"] != float64(ActivityListening) {
+		t.Errorf("Unexpected activity %v", activity)
+	}
+
+	if err := c.SetActivity(nil); err != nil {
+		t.Fatalf("Clearing failed: %v", err)
+	}
+	if args := received["args"].(map[string]interface{}); args["activity"] != nil {
+		t.Errorf("Expected a null activity, got %v", args["activity"])
+	}
+}
+
+func TestSetActivityError(t *testing.T) {
+	fakeDiscord(t, func(op uint32, msg map[string]interface{}) [][]byte {
+		if op == opHandshake {
+			return [][]byte{frame(opFrame, map[string]interface{}{"evt": "READY"})}
+		}
+		return [][]byte{frame(opFrame, map[string]interface{}{
+			"evt":   "ERROR",
+			"nonce": msg["nonce"],
+			"data":  map[string]interface{}{"code": 4000, "message": "child \"activity\" fails"},
+		})}
+	})
+
+	c, err := Connect("123")
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	err = c.SetActivity(&Activity{Details: "x"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 4000 {
+		t.Fatalf("Expected error 4000, got %v", err)
+	}
+}
+
+func TestConnectRejected(t *testing.T) {
+	fakeDiscord(t, func(op uint32, msg map[string]interface{}) [][]byte {
+		return [][]byte{frame(opClose, map[string]interface{}{"code": 4000, "message": "Invalid Client ID"})}
+	})
+
+	_, err := Connect("bad")
+	if err == nil || !strings.Contains(err.Error(), "Invalid Client ID") {
+		t.Fatalf("Expected the close reason, got %v", err)
+	}
+}
+
+func TestConnectNotRunning(t *testing.T) {
+	empty := t.TempDir()
+	for _, name := range []string{"XDG_RUNTIME_DIR", "TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(name, empty)
+	}
+	conn, err := dial()
+	if err == nil {
+		// A real Discord is listening in /tmp
+		conn.Close()
+		t.Skip("Discord is running")
+	}
+	if !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestSame(t *testing.T) {
+	a := &Activity{Details: "Song", Timestamps: &Timestamps{Start: 10000, End: 200000}}
+	drifted := &Activity{Details: "Song", Timestamps: &Timestamps{Start: 11500, End: 201500}}
+	seeked := &Activity{Details: "Song", Timestamps: &Timestamps{Start: 40000, End: 230000}}
+	paused := &Activity{Details: "Song"}
+	other := &Activity{Details: "Other", Timestamps: &Timestamps{Start: 10000, End: 200000}}
+
+	if !Same(a, drifted) {
+		t.Error("Expected a small drift to count as the same")
+	}
+	for _, b := range []*Activity{seeked, paused, other, nil} {
+		if Same(a, b) {
+			t.Errorf("Expected %+v to differ", b)
+		}
+	}
+	if !Same(nil, nil) {
+		t.Error("Expected two cleared activities to be the same")
+	}
+}
+
+func TestPresenceActivity(t *testing.T) {
+	p, err := NewPresence(Templates{})
+	if err != nil {
+		t.Fatalf("NewPresence failed: %v", err)
+	}
+	now := time.UnixMilli(1_000_000)
+	playing := Playing{
+		Name:     "Song",
+		Artists:  "A, B",
+		Album:    "Album",
+		URL:      "https://open.spotify.com/track/1",
+		ImageURL: "https://i.scdn.co/image/1",
+		Progress: 30 * time.Second,
+		Duration: 3 * time.Minute,
+	}
+
+	activity, err := p.Activity(playing, now)
+	if err != nil {
+		t.Fatalf("Activity failed: %v", err)
+	}
+	if activity.Details != "Song" || activity.State != "by A, B" {
+		t.Errorf("Unexpected text %q / %q", activity.Details, activity.State)
+	}
+	if activity.Assets == nil || activity.Assets.LargeImage != playing.ImageURL || activity.Assets.LargeText != "Album" {
+		t.Errorf("Unexpected assets %+v", activity.Assets)
+	}
+	if activity.Timestamps == nil || activity.Timestamps.Start != 970_000 || activity.Timestamps.End != 1_150_000 {
+		t.Errorf("Unexpected timestamps %+v", activity.Timestamps)
+	}
+	if len(activity.Buttons) != 1 || activity.Buttons[0].URL != playing.URL {
+		t.Errorf("Unexpected buttons %+v", activity.Buttons)
+	}
+
+	playing.Paused = true
+	activity, err = p.Activity(playing, now)
+	if err != nil {
+		t.Fatalf("Activity failed: %v", err)
+	}
+	if activity.State != "by A, B (paused)" || activity.Timestamps != nil {
+		t.Errorf("Expected a paused activity without timestamps, got %+v", activity)
+	}
+}
+
+func TestPresenceTemplates(t *testing.T) {
+	p, err := NewPresence(Templates{Details: "{{.Artists}} – {{.Name}}", State: "{{.Album}}", LargeText: "{{if .Episode}}podcast{{end}}"})
+	if err != nil {
+		t.Fatalf("NewPresence failed: %v", err)
+	}
+	activity, err := p.Activity(Playing{Name: strings.Repeat("x", 200), Artists: "A", Album: "B"}, time.Now())
+	if err != nil {
+		t.Fatalf("Activity failed: %v", err)
+	}
+	if n := len([]rune(activity.Details)); n != maxText {
+		t.Errorf("Expected details cut to %d characters, got %d", maxText, n)
+	}
+	if activity.State != "B " {
+		t.Errorf("Expected short text padded, got %q", activity.State)
+	}
+	if activity.Assets != nil {
+		t.Errorf("Expected no assets, got %+v", activity.Assets)
+	}
+
+	if _, err := NewPresence(Templates{Details: "{{.Name"}); err == nil {
+		t.Error("Expected an invalid template to fail")
+	}
+	p, _ = NewPresence(Templates{State: "{{.Nope}}"})
+	if _, err := p.Activity(Playing{}, time.Now()); err == nil {
+		t.Error("Expected an unknown field to fail")
+	}
+}
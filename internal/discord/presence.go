@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Playing is what the player is doing, as templates see it
+type Playing struct {
+	Name string
+	// Artists are joined with commas. For episodes they are the show's
+	// publisher and Album is the show.
+	Artists string
+	Album   string
+	// URL is the item's open.spotify.com link and ImageURL its artwork
+	URL      string
+	ImageURL string
+	Episode  bool
+	Paused   bool
+	Progress time.Duration
+	Duration time.Duration
+}
+
+// Templates format the text of an activity. Each is a text/template over
+// Playing, such as "{{.Name}}" or "by {{.Artists}}".
+type Templates struct {
+	Details   string
+	State     string
+	LargeText string
+}
+
+// DefaultTemplates are used for templates left empty
+var DefaultTemplates = Templates{
+	Details:   "{{.Name}}",
+	State:     "by {{.Artists}}{{if .Paused}} (paused){{end}}",
+	LargeText: "{{.Album}}",
+}
+
+// Text fields must be 2 to 128 characters long
+const (
+	minText = 2
+	maxText = 128
+)
+
+// Presence turns playback into activities
+type Presence struct {
+	details   *template.Template
+	state     *template.Template
+	largeText *template.Template
+}
+
+// NewPresence parses templates
+func NewPresence(templates Templates) (*Presence, error) {
+	p := &Presence{}
+	for _, t := range []struct {
+		name string
+		text string
+		def  string
+		dest **template.Template
+	}{
+		{"details", templates.Details, DefaultTemplates.Details, &p.details},
+		{"state", templates.State, DefaultTemplates.State, &p.state},
+		{"large text", templates.LargeText, DefaultTemplates.LargeText, &p.largeText},
+	} {
+		text := t.text
+		if text == "" {
+			text = t.def
+		}
+		parsed, err := template.New(t.name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", t.name, err)
+		}
+		*t.dest = parsed
+	}
+	return p, nil
+}
+
+// Activity renders what is playing at now. Paused items have no
+// timestamps, as their time is not running.
+func (p *Presence) Activity(playing Playing, now time.Time) (*Activity, error) {
+	activity := &Activity{Type: ActivityListening}
+	var err error
+	if activity.Details, err = render(p.details, playing); err != nil {
+		return nil, err
+	}
+	if activity.State, err = render(p.state, playing); err != nil {
+		return nil, err
+	}
+
+	assets := &Assets{LargeImage: playing.ImageURL}
+	if assets.LargeText, err = render(p.largeText, playing); err != nil {
+		return nil, err
+	}
+	if *assets != (Assets{}) {
+		activity.Assets = assets
+	}
+
+	if !playing.Paused && playing.Duration > 0 {
+		start := now.Add(-playing.Progress)
+		activity.Timestamps = &Timestamps{
+			Start: start.UnixMilli(),
+			End:   start.Add(playing.Duration).UnixMilli(),
+		}
+	}
+	if playing.URL != "" {
+		label := "Listen on Spotify"
+		if playing.Episode {
+			label = "Listen to the episode"
+		}
+		activity.Buttons = []Button{{Label: label, URL: playing.URL}}
+	}
+	return activity, nil
+}
+
+// render runs a template and fits its output in a text field. Text too
+// short for Discord is padded, and empty text leaves the field out.
+func render(t *template.Template, playing Playing) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, playing); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", t.Name(), err)
+	}
+	text := strings.Join(strings.Fields(b.String()), " ")
+	if text == "" {
+		return "", nil
+	}
+	if runes := []rune(text); len(runes) > maxText {
+		text = string(runes[:maxText-1]) + "…"
+	}
+	for len([]rune(text)) < minText {
+		text += " "
+	}
+	return text, nil
+}
@@ -0,0 +1,73 @@
+// Package duration formats track lengths and reads durations typed by
+// users. It imports only the standard library, so the core client and
+// the CLI can share it.
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format formats a duration in milliseconds as M:SS, or as H:MM:SS from an
+// hour up, the forms Parse reads back
+func Format(ms int) string {
+	seconds := ms / 1000
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// Parse reads a duration typed on the command line: a number of
+// seconds ("150"), a clock position ("2:30" or "1:02:03"), or a Go duration
+// ("1h30m", "90m"). Negative durations are rejected.
+func Parse(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	if seconds, err := strconv.Atoi(s); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("duration cannot be negative: %s", s)
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	if strings.Contains(s, ":") {
+		return parseClock(s)
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: use seconds, MM:SS, H:MM:SS or a duration like 1h30m", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration cannot be negative: %s", s)
+	}
+	return d, nil
+}
+
+// parseClock reads MM:SS or H:MM:SS. The first field may be any size, so
+// "90:00" is an hour and a half; the ones after it must be below 60.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time %q: use MM:SS or H:MM:SS", s)
+	}
+
+	var total int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || strings.HasPrefix(part, "+") {
+			return 0, fmt.Errorf("invalid time %q: use MM:SS or H:MM:SS", s)
+		}
+		if i > 0 && (n >= 60 || len(part) != 2) {
+			return 0, fmt.Errorf("invalid time %q: minutes and seconds after the first field are two digits below 60", s)
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total) * time.Second, nil
+}
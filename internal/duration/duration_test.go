@@ -0,0 +1,83 @@
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		ms       int
+		expected string
+	}{
+		{0, "0:00"},
+		{1000, "0:01"},
+		{60000, "1:00"},
+		{90000, "1:30"},
+		{3599999, "59:59"},
+		{3600000, "1:00:00"},
+		{3661000, "1:01:01"},
+		{180000, "3:00"},
+		{240000, "4:00"},
+		{10 * 3600000, "10:00:00"},
+	}
+
+	for _, test := range tests {
+		result := Format(test.ms)
+		if result != test.expected {
+			t.Errorf("Format(%d) = %s, expected %s", test.ms, result, test.expected)
+		}
+	}
+}
+
+func TestFormat_ParsesBack(t *testing.T) {
+	for _, ms := range []int{0, 59000, 754000, 3600000, 3661000, 5025000} {
+		d, err := Parse(Format(ms))
+		if err != nil || d != time.Duration(ms)*time.Millisecond {
+			t.Errorf("Parse(Format(%d)) = %v, %v", ms, d, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"150", 150 * time.Second, false},
+		{"0", 0, false},
+		{"2:30", 2*time.Minute + 30*time.Second, false},
+		{"90:00", 90 * time.Minute, false},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"90m", 90 * time.Minute, false},
+		{"45s", 45 * time.Second, false},
+		{" 2:30 ", 2*time.Minute + 30*time.Second, false},
+		{"", 0, true},
+		{"-5", 0, true},
+		{"-1m", 0, true},
+		{"2:75", 0, true},
+		{"2:5", 0, true},
+		{"1:2:3:4", 0, true},
+		{"a:30", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, test := range tests {
+		result, err := Parse(test.input)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, expected an error", test.input, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", test.input, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("Parse(%q) = %v, expected %v", test.input, result, test.expected)
+		}
+	}
+}
@@ -13,11 +13,16 @@ var (
 	ErrNetwork    = errors.New("network error")
 	ErrValidation = errors.New("validation error")
 	ErrFile       = errors.New("file error")
+
+	// ErrForbidden marks a 403 response. It comes with ErrAuth, but unlike a
+	// missing scope it can also mean the device refused a player command.
+	ErrForbidden = errors.New("forbidden")
 )
 
-// Wrap wraps an error with additional context and type
+// Wrap wraps an error with additional context and type. The wrapped error
+// stays in the chain, so errors.Is still finds its own type.
 func Wrap(err error, errorType error, message string) error {
-	return fmt.Errorf("%w: %s: %v", errorType, message, err)
+	return fmt.Errorf("%w: %s: %w", errorType, message, err)
 }
 
 // New creates a new error with type and message
@@ -98,4 +103,41 @@ func IsValidationError(err error) bool {
 
 func IsFileError(err error) bool {
 	return errors.Is(err, ErrFile)
-}
\ No newline at end of file
+}
+
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// StatusError is an error response from an API, keeping its HTTP status so
+// callers can tell a failure worth retrying from one that will not change
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// NewStatusError creates an API error for a response with the given status
+func NewStatusError(statusCode int, message string) error {
+	return fmt.Errorf("%w: %w", ErrAPI, &StatusError{StatusCode: statusCode, Message: message})
+}
+
+// StatusCode returns the HTTP status of an API error response, or 0 if err
+// did not come from one
+func StatusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// IsTemporary reports whether retrying later may succeed: a network error,
+// a rate limit or a server error
+func IsTemporary(err error) bool {
+	status := StatusCode(err)
+	return IsNetworkError(err) || status == 429 || status >= 500
+}
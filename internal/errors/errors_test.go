@@ -44,6 +44,12 @@ func TestWrappedErrors(t *testing.T) {
 	if !strings.Contains(wrappedErr.Error(), baseErr.Error()) {
 		t.Error("Expected wrapped error to contain base error message")
 	}
+
+	// The wrapped error's own type survives wrapping
+	apiErr := WrapAPIError(NewNetworkError("timeout"), "failed to get track")
+	if !IsAPIError(apiErr) || !IsNetworkError(apiErr) {
+		t.Errorf("Expected both API and network types, got %q", apiErr.Error())
+	}
 }
 
 func TestErrorMessages(t *testing.T) {
@@ -53,4 +59,31 @@ func TestErrorMessages(t *testing.T) {
 	if err.Error() != expected {
 		t.Errorf("Expected %q, got %q", expected, err.Error())
 	}
-}
\ No newline at end of file
+}
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		err       error
+		status    int
+		temporary bool
+	}{
+		{NewStatusError(404, "HTTP 404: Not found"), 404, false},
+		{WrapAPIError(NewStatusError(503, "HTTP 503: Unavailable"), "failed to get playlist"), 503, true},
+		{NewStatusError(429, "rate limited"), 429, true},
+		{NewNetworkError("timeout"), 0, true},
+		{NewAPIError("failed to decode response"), 0, false},
+	}
+
+	for _, tt := range tests {
+		if status := StatusCode(tt.err); status != tt.status {
+			t.Errorf("StatusCode(%v) = %d, expected %d", tt.err, status, tt.status)
+		}
+		if temporary := IsTemporary(tt.err); temporary != tt.temporary {
+			t.Errorf("IsTemporary(%v) = %v, expected %v", tt.err, temporary, tt.temporary)
+		}
+	}
+
+	err := NewStatusError(404, "HTTP 404: Not found")
+	if !IsAPIError(err) || err.Error() != "API error: HTTP 404: Not found" {
+		t.Errorf("Expected an API error keeping its message, got %q", err.Error())
+	}
+}
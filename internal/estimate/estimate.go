@@ -0,0 +1,161 @@
+// Package estimate predicts how many API requests a large operation will
+// make and roughly how long they will take under each service's rate limit,
+// so commands can show the cost up front and refuse to start jobs that are
+// bigger than the user allowed.
+package estimate
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
+)
+
+// ErrTooManyRequests is returned by Check when an estimate exceeds the limit
+var ErrTooManyRequests = errors.New("estimated requests exceed the limit")
+
+// Rate describes how quickly a service accepts requests
+type Rate struct {
+	Service string
+	// Burst is how many requests may be sent before Interval applies; zero
+	// with a zero Interval means the service is not rate limited
+	Burst    int
+	Interval time.Duration
+	// Latency is the typical time one request takes
+	Latency time.Duration
+}
+
+// Spotify is the rate the client's limiter allows for the Spotify Web API
+var Spotify = Rate{
+	Service:  "Spotify",
+	Burst:    ratelimit.DefaultBurst,
+	Interval: ratelimit.DefaultRefillInterval,
+	Latency:  250 * time.Millisecond,
+}
+
+// Duration estimates how long n requests take with the given number of
+// requests in flight: the slower of sending them back to back and waiting
+// for the rate limit to allow them
+func (r Rate) Duration(n, concurrency int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sending := time.Duration((n+concurrency-1)/concurrency) * r.Latency
+
+	var limited time.Duration
+	if r.Interval > 0 && n > r.Burst {
+		limited = time.Duration(n-r.Burst) * r.Interval
+	}
+
+	if limited > sending {
+		return limited
+	}
+	return sending
+}
+
+// Step is one part of an operation
+type Step struct {
+	Description string `json:"description" yaml:"description"`
+	Service     string `json:"service" yaml:"service"`
+	Requests    int    `json:"requests" yaml:"requests"`
+	// Approximate is set when the number of items was guessed rather than known
+	Approximate bool          `json:"approximate,omitempty" yaml:"approximate,omitempty"`
+	Duration    time.Duration `json:"duration" yaml:"duration"`
+}
+
+// Estimate is the predicted cost of an operation, built step by step
+type Estimate struct {
+	Concurrency int    `json:"concurrency" yaml:"concurrency"`
+	Steps       []Step `json:"steps" yaml:"steps"`
+}
+
+// New starts an estimate for an operation running concurrency requests at once
+func New(concurrency int) *Estimate {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Estimate{Concurrency: concurrency}
+}
+
+// Calls adds a step making one request per item
+func (e *Estimate) Calls(rate Rate, description string, n int) *Step {
+	return e.add(rate, description, n)
+}
+
+// Pages adds a step reading items from a paginated endpoint
+func (e *Estimate) Pages(rate Rate, description string, items, pageSize int) *Step {
+	return e.add(rate, description, batches(items, pageSize))
+}
+
+// Chunks adds a step sending items in batches of at most chunkSize, such as
+// adding tracks to a playlist 100 at a time
+func (e *Estimate) Chunks(rate Rate, description string, items, chunkSize int) *Step {
+	return e.add(rate, description, batches(items, chunkSize))
+}
+
+func (e *Estimate) add(rate Rate, description string, requests int) *Step {
+	if requests < 0 {
+		requests = 0
+	}
+
+	e.Steps = append(e.Steps, Step{
+		Description: description,
+		Service:     rate.Service,
+		Requests:    requests,
+		Duration:    rate.Duration(requests, e.Concurrency),
+	})
+	return &e.Steps[len(e.Steps)-1]
+}
+
+// Requests returns the total number of requests
+func (e *Estimate) Requests() int {
+	total := 0
+	for _, step := range e.Steps {
+		total += step.Requests
+	}
+	return total
+}
+
+// Duration returns the estimated running time. Steps are assumed to run one
+// after another.
+func (e *Estimate) Duration() time.Duration {
+	var total time.Duration
+	for _, step := range e.Steps {
+		total += step.Duration
+	}
+	return total
+}
+
+// Approximate reports whether any step was estimated from a guessed count
+func (e *Estimate) Approximate() bool {
+	for _, step := range e.Steps {
+		if step.Approximate {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns ErrTooManyRequests if the estimate exceeds max requests. A
+// max of zero or less means no limit.
+func (e *Estimate) Check(max int) error {
+	if max <= 0 || e.Requests() <= max {
+		return nil
+	}
+	return fmt.Errorf("%w: about %d requests needed, limit is %d", ErrTooManyRequests, e.Requests(), max)
+}
+
+func batches(items, size int) int {
+	if items <= 0 {
+		return 0
+	}
+	if size < 1 {
+		size = 1
+	}
+	return (items + size - 1) / size
+}
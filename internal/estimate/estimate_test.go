@@ -0,0 +1,77 @@
+package estimate
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRate_Duration(t *testing.T) {
+	limited := Rate{Service: "MusicBrainz", Burst: 1, Interval: time.Second, Latency: 100 * time.Millisecond}
+	unlimited := Rate{Service: "Lidarr", Latency: 300 * time.Millisecond}
+
+	tests := []struct {
+		name        string
+		rate        Rate
+		n           int
+		concurrency int
+		want        time.Duration
+	}{
+		{"no requests", limited, 0, 3, 0},
+		{"within burst", limited, 1, 1, 100 * time.Millisecond},
+		{"rate limited", limited, 11, 5, 10 * time.Second},
+		{"unlimited sequential", unlimited, 4, 1, 1200 * time.Millisecond},
+		{"unlimited concurrent", unlimited, 4, 2, 600 * time.Millisecond},
+		{"concurrency clamped", unlimited, 2, 0, 600 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rate.Duration(tt.n, tt.concurrency); got != tt.want {
+				t.Errorf("Duration(%d, %d) = %v, want %v", tt.n, tt.concurrency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimate_Steps(t *testing.T) {
+	rate := Rate{Service: "Spotify", Latency: time.Second}
+	e := New(1)
+
+	if pages := e.Pages(rate, "fetch tracks", 120, 50); pages.Requests != 3 {
+		t.Errorf("Expected 3 pages for 120 items, got %d", pages.Requests)
+	}
+	if chunks := e.Chunks(rate, "add tracks", 100, 100); chunks.Requests != 1 {
+		t.Errorf("Expected 1 chunk for 100 items, got %d", chunks.Requests)
+	}
+	e.Calls(rate, "look up artists", 6).Approximate = true
+
+	if e.Requests() != 10 {
+		t.Errorf("Expected 10 requests, got %d", e.Requests())
+	}
+	if e.Duration() != 10*time.Second {
+		t.Errorf("Expected 10s, got %v", e.Duration())
+	}
+	if !e.Approximate() || e.Steps[0].Approximate {
+		t.Errorf("Expected only the last step to be approximate: %+v", e.Steps)
+	}
+
+	if e.Pages(rate, "empty", 0, 50).Requests != 0 {
+		t.Error("Expected no requests for no items")
+	}
+}
+
+func TestEstimate_Check(t *testing.T) {
+	e := New(2)
+	e.Calls(Rate{Service: "Lidarr"}, "add artists", 20)
+
+	if err := e.Check(0); err != nil {
+		t.Errorf("Expected no limit for zero, got %v", err)
+	}
+	if err := e.Check(20); err != nil {
+		t.Errorf("Expected estimate at the limit to pass, got %v", err)
+	}
+	if err := e.Check(19); !errors.Is(err, ErrTooManyRequests) {
+		t.Errorf("Expected ErrTooManyRequests, got %v", err)
+	}
+}
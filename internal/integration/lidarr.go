@@ -1,13 +1,16 @@
 package integration
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 
 	"github.com/bambithedeer/spotify-api/internal/lidarr"
 	"github.com/bambithedeer/spotify-api/internal/logger"
 	"github.com/bambithedeer/spotify-api/internal/musicbrainz"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
 // LidarrIntegration handles the integration between Spotify artists, MusicBrainz, and Lidarr
@@ -16,6 +19,17 @@ type LidarrIntegration struct {
 	musicbrainzClient *musicbrainz.Client
 	config           *LidarrConfig
 	logger           *logger.Logger
+
+	// library is fetched once per integration, for batches to skip what
+	// Lidarr already has and for dry runs to check matches against
+	libraryOnce sync.Once
+	library     *Library
+	libraryErr  error
+
+	// onArtist and onAlbum are told about each item of a batch as it
+	// finishes
+	onArtist func(ArtistResult)
+	onAlbum  func(AlbumResult)
 }
 
 // LidarrConfig holds configuration for Lidarr integration
@@ -25,6 +39,9 @@ type LidarrConfig struct {
 	MetadataProfileID int
 	Monitor           bool
 	SearchForMissing  bool
+	// DryRun looks items up in MusicBrainz and checks them against the
+	// library, but adds nothing to Lidarr
+	DryRun bool
 }
 
 // ArtistResult represents the result of adding an artist to Lidarr
@@ -35,6 +52,9 @@ type ArtistResult struct {
 	Success      bool
 	Error        error
 	LidarrArtist *lidarr.Artist
+	// AlreadyExists is set when Lidarr has the artist already, either found
+	// in its library or turned down as a duplicate
+	AlreadyExists bool
 }
 
 // BatchResult represents the results of a batch artist addition
@@ -43,6 +63,14 @@ type BatchResult struct {
 	Successes int
 	Failures  int
 	Results   []ArtistResult
+	// Existing counts the artists Lidarr already has, which are neither
+	// Successes nor Failures
+	Existing int
+	// DryRun is set when nothing was added: Successes are the artists that
+	// would be
+	DryRun bool
+	// Concurrency records how the number of parallel lookups adapted
+	Concurrency ratelimit.AdaptiveStats
 }
 
 // NewLidarrIntegration creates a new Lidarr integration instance
@@ -55,6 +83,18 @@ func NewLidarrIntegration(lidarrClient *lidarr.Client, musicbrainzClient *musicb
 	}
 }
 
+// OnArtistResult registers fn to be called with the result of every artist
+// of a batch as soon as it is known, so progress outlives a batch that dies
+// partway. fn is called from several goroutines at once.
+func (li *LidarrIntegration) OnArtistResult(fn func(ArtistResult)) {
+	li.onArtist = fn
+}
+
+// OnAlbumResult is OnArtistResult for album batches
+func (li *LidarrIntegration) OnAlbumResult(fn func(AlbumResult)) {
+	li.onAlbum = fn
+}
+
 // AddArtist adds a single artist to Lidarr
 func (li *LidarrIntegration) AddArtist(artistName string) (*ArtistResult, error) {
 	result := &ArtistResult{
@@ -82,6 +122,21 @@ func (li *LidarrIntegration) AddArtist(artistName string) (*ArtistResult, error)
 		"score":        mbArtist.Score,
 	})
 
+	library, err := li.cachedLibrary()
+	if err != nil && li.config.DryRun {
+		result.Error = err
+		return result, err
+	}
+	if library.HasArtistMBID(mbArtist.ID) {
+		result.Error = fmt.Errorf("artist already exists: %s", mbArtist.Name)
+		result.AlreadyExists = true
+		return result, result.Error
+	}
+	if li.config.DryRun {
+		result.Success = true
+		return result, nil
+	}
+
 	// Step 2: Add artist to Lidarr using MBID
 	li.logger.InfoWithFields("Adding artist to Lidarr", logger.Fields{"mbid": mbArtist.ID})
 	lidarrArtist, err := li.lidarrClient.AddArtistByMBID(
@@ -98,6 +153,7 @@ func (li *LidarrIntegration) AddArtist(artistName string) (*ArtistResult, error)
 		if strings.Contains(strings.ToLower(err.Error()), "already") {
 			li.logger.WarnWithFields("Artist already exists in Lidarr", logger.Fields{"artist": mbArtist.Name})
 			result.Error = fmt.Errorf("artist already exists: %s", mbArtist.Name)
+			result.AlreadyExists = true
 			return result, result.Error
 		}
 
@@ -116,45 +172,110 @@ func (li *LidarrIntegration) AddArtist(artistName string) (*ArtistResult, error)
 	return result, nil
 }
 
-// AddArtistsBatch adds multiple artists to Lidarr with concurrent processing
+// AddArtistsBatch adds multiple artists to Lidarr with concurrent processing.
+// Artists whose name Lidarr already has are skipped without a MusicBrainz
+// lookup. maxConcurrency is a cap: the number of artists processed at once
+// is halved whenever MusicBrainz or Lidarr push back and grows again while
+// they keep up.
 func (li *LidarrIntegration) AddArtistsBatch(artistNames []string, maxConcurrency int) *BatchResult {
-	if maxConcurrency <= 0 {
-		maxConcurrency = 3 // Default to 3 concurrent requests to be respectful to APIs
+	result := &BatchResult{Total: len(artistNames), DryRun: li.config.DryRun}
+
+	library := li.batchLibrary()
+	var lookups []string
+	for _, name := range artistNames {
+		if library.HasArtist(name) {
+			existing := ArtistResult{
+				ArtistName:    name,
+				SpotifyName:   name,
+				Error:         fmt.Errorf("artist already exists: %s", name),
+				AlreadyExists: true,
+			}
+			li.reportArtist(existing)
+			result.Results = append(result.Results, existing)
+			continue
+		}
+		lookups = append(lookups, name)
+	}
+
+	looked, concurrency := runBatch(lookups, maxConcurrency, func(name string) (*ArtistResult, error) {
+		artistResult, err := li.AddArtist(name)
+		li.reportArtist(*artistResult)
+		return artistResult, err
+	})
+	result.Results, result.Concurrency = append(result.Results, looked...), concurrency
+	for _, artistResult := range result.Results {
+		switch {
+		case artistResult.Success:
+			result.Successes++
+		case artistResult.AlreadyExists:
+			result.Existing++
+		default:
+			result.Failures++
+		}
+	}
+
+	li.logger.InfoWithFields("Batch operation completed", logger.Fields{
+		"total":             result.Total,
+		"successes":         result.Successes,
+		"existing":          result.Existing,
+		"looked_up":         len(lookups),
+		"failures":          result.Failures,
+		"final_concurrency": result.Concurrency.Limit,
+	})
+
+	return result
+}
+
+func (li *LidarrIntegration) reportArtist(result ArtistResult) {
+	if li.onArtist != nil {
+		li.onArtist(result)
+	}
+}
+
+func (li *LidarrIntegration) reportAlbum(result AlbumResult) {
+	if li.onAlbum != nil {
+		li.onAlbum(result)
 	}
+}
 
-	result := &BatchResult{
-		Total:   len(artistNames),
-		Results: make([]ArtistResult, 0, len(artistNames)),
+// runBatch calls add for each job from a pool of workers and returns the
+// results in the order they finished. The limiter lets at most
+// maxConcurrency run at once, and fewer while add keeps failing from
+// overload; the error is otherwise left in the result.
+func runBatch[J, R any](jobs []J, maxConcurrency int, add func(J) (*R, error)) ([]R, ratelimit.AdaptiveStats) {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 3 // Default to 3 concurrent requests to be respectful to APIs
 	}
 
 	// Create worker pool
-	jobs := make(chan string, len(artistNames))
-	results := make(chan ArtistResult, len(artistNames))
+	queue := make(chan J, len(jobs))
+	results := make(chan R, len(jobs))
 
-	// Start workers
+	// Start workers; the limiter decides how many of them run at once
+	limiter := ratelimit.NewAdaptiveLimiter(maxConcurrency)
 	var wg sync.WaitGroup
 	for i := 0; i < maxConcurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for artistName := range jobs {
-				artistResult, err := li.AddArtist(artistName)
-				if err != nil {
-					// Error is already stored in artistResult
-					results <- *artistResult
-				} else {
-					results <- *artistResult
-				}
+			for job := range queue {
+				var result *R
+				limiter.Do(context.Background(), func() error {
+					var err error
+					result, err = add(job)
+					return err
+				})
+				results <- *result
 			}
 		}()
 	}
 
 	// Send jobs
 	go func() {
-		for _, artistName := range artistNames {
-			jobs <- artistName
+		for _, job := range jobs {
+			queue <- job
 		}
-		close(jobs)
+		close(queue)
 	}()
 
 	// Close results channel after all workers complete
@@ -163,25 +284,276 @@ func (li *LidarrIntegration) AddArtistsBatch(artistNames []string, maxConcurrenc
 		close(results)
 	}()
 
-	// Collect results
-	for artistResult := range results {
-		result.Results = append(result.Results, artistResult)
-		if artistResult.Success {
+	collected := make([]R, 0, len(jobs))
+	for result := range results {
+		collected = append(collected, result)
+	}
+	return collected, limiter.Stats()
+}
+
+// AlbumRequest names an album to add to Lidarr, as Spotify has it
+type AlbumRequest struct {
+	Artist string
+	Title  string
+}
+
+func (r AlbumRequest) String() string {
+	return fmt.Sprintf("%s - %s", r.Artist, r.Title)
+}
+
+// ParseAlbumRequest reads an album written as "Artist - Album". The first
+// " - " separates them, since album titles use it more often than artist
+// names do.
+func ParseAlbumRequest(line string) (AlbumRequest, error) {
+	artist, title, ok := strings.Cut(line, " - ")
+	artist, title = strings.TrimSpace(artist), strings.TrimSpace(title)
+	if !ok || artist == "" || title == "" {
+		return AlbumRequest{}, fmt.Errorf("expected \"Artist - Album\", got %q", line)
+	}
+	return AlbumRequest{Artist: artist, Title: title}, nil
+}
+
+// AlbumResult represents the result of adding an album to Lidarr
+type AlbumResult struct {
+	Request     AlbumRequest
+	AlbumTitle  string
+	ArtistName  string
+	MBID        string
+	Success     bool
+	Error       error
+	LidarrAlbum *lidarr.Album
+	// AlreadyExists is set when Lidarr has the album already, as for artists
+	AlreadyExists bool
+}
+
+// AlbumBatchResult represents the results of a batch album addition
+type AlbumBatchResult struct {
+	Total       int
+	Successes   int
+	Failures    int
+	Results     []AlbumResult
+	Concurrency ratelimit.AdaptiveStats
+	// DryRun and Existing are as in BatchResult
+	DryRun   bool
+	Existing int
+}
+
+// editionSuffix matches the edition Spotify often appends to an album
+// title, which MusicBrainz keeps off the release group
+var editionSuffix = regexp.MustCompile(`(?i)\s*(\(|\[|- )[^)\]]*(remaster|deluxe|edition|expanded|anniversary|version|bonus)[^)\]]*[)\]]?\s*$`)
+
+// albumSearchTitle strips edition suffixes such as "(Deluxe Edition)" or
+// "- 2011 Remaster" from a Spotify album title
+func albumSearchTitle(title string) string {
+	if stripped := editionSuffix.ReplaceAllString(title, ""); stripped != "" {
+		return stripped
+	}
+	return title
+}
+
+// AddAlbum adds a single album to Lidarr, with its artist if Lidarr does
+// not have them yet. The album is monitored when the config says to.
+func (li *LidarrIntegration) AddAlbum(request AlbumRequest) (*AlbumResult, error) {
+	result := &AlbumResult{Request: request}
+
+	title := albumSearchTitle(request.Title)
+	li.logger.InfoWithFields("Looking up album in MusicBrainz", logger.Fields{
+		"artist": request.Artist,
+		"album":  title,
+	})
+	group, err := li.musicbrainzClient.GetBestReleaseGroup(request.Artist, title)
+	if err != nil {
+		result.Error = fmt.Errorf("MusicBrainz lookup failed: %w", err)
+		return result, result.Error
+	}
+
+	result.AlbumTitle = group.Title
+	result.MBID = group.ID
+	if len(group.ArtistCredit) > 0 {
+		result.ArtistName = group.ArtistCredit[0].Name
+	}
+
+	library, err := li.cachedLibrary()
+	if err != nil && li.config.DryRun {
+		result.Error = err
+		return result, err
+	}
+	if library.HasAlbumMBID(group.ID) {
+		result.Error = fmt.Errorf("album already exists: %s", group.Title)
+		result.AlreadyExists = true
+		return result, result.Error
+	}
+	if li.config.DryRun {
+		result.Success = true
+		return result, nil
+	}
+
+	li.logger.InfoWithFields("Adding album to Lidarr", logger.Fields{"mbid": group.ID})
+	lidarrAlbum, err := li.lidarrClient.AddAlbumByMBID(
+		group.ID,
+		li.config.RootFolderPath,
+		li.config.QualityProfileID,
+		li.config.MetadataProfileID,
+		li.config.Monitor,
+		li.config.SearchForMissing,
+	)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "already") {
+			result.Error = fmt.Errorf("album already exists: %s", group.Title)
+			result.AlreadyExists = true
+			return result, result.Error
+		}
+		result.Error = fmt.Errorf("Lidarr add failed: %w", err)
+		return result, result.Error
+	}
+
+	result.LidarrAlbum = lidarrAlbum
+	result.Success = true
+
+	li.logger.InfoWithFields("Successfully added album to Lidarr", logger.Fields{
+		"album":     group.Title,
+		"lidarr_id": lidarrAlbum.ID,
+	})
+
+	return result, nil
+}
+
+// AddAlbumsBatch adds multiple albums to Lidarr, skipping the ones it
+// already has and adapting concurrency as in AddArtistsBatch
+func (li *LidarrIntegration) AddAlbumsBatch(albums []AlbumRequest, maxConcurrency int) *AlbumBatchResult {
+	result := &AlbumBatchResult{Total: len(albums), DryRun: li.config.DryRun}
+
+	library := li.batchLibrary()
+	var lookups []AlbumRequest
+	for _, request := range albums {
+		if library.HasAlbum(request) {
+			existing := AlbumResult{
+				Request:       request,
+				AlbumTitle:    request.Title,
+				ArtistName:    request.Artist,
+				Error:         fmt.Errorf("album already exists: %s", request),
+				AlreadyExists: true,
+			}
+			li.reportAlbum(existing)
+			result.Results = append(result.Results, existing)
+			continue
+		}
+		lookups = append(lookups, request)
+	}
+
+	looked, concurrency := runBatch(lookups, maxConcurrency, func(request AlbumRequest) (*AlbumResult, error) {
+		albumResult, err := li.AddAlbum(request)
+		li.reportAlbum(*albumResult)
+		return albumResult, err
+	})
+	result.Results, result.Concurrency = append(result.Results, looked...), concurrency
+	for _, albumResult := range result.Results {
+		switch {
+		case albumResult.Success:
 			result.Successes++
-		} else {
+		case albumResult.AlreadyExists:
+			result.Existing++
+		default:
 			result.Failures++
 		}
 	}
 
-	li.logger.InfoWithFields("Batch operation completed", logger.Fields{
-		"total":     result.Total,
-		"successes": result.Successes,
-		"failures":  result.Failures,
+	li.logger.InfoWithFields("Album batch completed", logger.Fields{
+		"total":             result.Total,
+		"successes":         result.Successes,
+		"existing":          result.Existing,
+		"looked_up":         len(lookups),
+		"failures":          result.Failures,
+		"final_concurrency": result.Concurrency.Limit,
 	})
 
 	return result
 }
 
+// Library is what Lidarr already has, indexed by name so that items from
+// Spotify can be checked against it without a MusicBrainz lookup
+type Library struct {
+	artists map[string]bool
+	albums  map[string]bool
+	// mbids holds the MusicBrainz IDs of the artists and release groups
+	mbids map[string]bool
+}
+
+// libraryKey reduces names to lowercase words, since Spotify and
+// MusicBrainz differ in case and spacing more often than in spelling
+func libraryKey(names ...string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.Join(names, " \x00 "))), " ")
+}
+
+// Library fetches the artists and albums in Lidarr
+func (li *LidarrIntegration) Library() (*Library, error) {
+	artists, err := li.lidarrClient.GetArtists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Lidarr artists: %w", err)
+	}
+	albums, err := li.lidarrClient.GetAlbums()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Lidarr albums: %w", err)
+	}
+
+	library := &Library{artists: make(map[string]bool), albums: make(map[string]bool), mbids: make(map[string]bool)}
+	names := make(map[int]string, len(artists))
+	for _, artist := range artists {
+		names[artist.ID] = artist.ArtistName
+		library.artists[libraryKey(artist.ArtistName)] = true
+		library.mbids[artist.ForeignArtistID] = true
+	}
+	for _, album := range albums {
+		artistName := names[album.ArtistID]
+		if album.Artist != nil && album.Artist.ArtistName != "" {
+			artistName = album.Artist.ArtistName
+		}
+		library.albums[libraryKey(artistName, albumSearchTitle(album.Title))] = true
+		library.mbids[album.ForeignAlbumID] = true
+	}
+	return library, nil
+}
+
+// cachedLibrary returns the library, fetching it on first use
+func (li *LidarrIntegration) cachedLibrary() (*Library, error) {
+	li.libraryOnce.Do(func() {
+		li.library, li.libraryErr = li.Library()
+	})
+	return li.library, li.libraryErr
+}
+
+// batchLibrary is the library a batch filters against. When it cannot be
+// fetched the batch goes ahead unfiltered, and Lidarr turns duplicates down
+// as it did before. A nil Library has nothing in it.
+func (li *LidarrIntegration) batchLibrary() *Library {
+	library, err := li.cachedLibrary()
+	if err != nil {
+		li.logger.WarnWithFields("Could not fetch the Lidarr library; looking up every item", logger.Fields{"error": err.Error()})
+	}
+	return library
+}
+
+// HasArtist reports whether Lidarr has an artist of this name
+func (l *Library) HasArtist(name string) bool {
+	return l != nil && l.artists[libraryKey(name)]
+}
+
+// HasArtistMBID reports whether Lidarr has the artist with this MusicBrainz ID
+func (l *Library) HasArtistMBID(mbid string) bool {
+	return l != nil && mbid != "" && l.mbids[mbid]
+}
+
+// HasAlbumMBID reports whether Lidarr has the release group with this
+// MusicBrainz ID
+func (l *Library) HasAlbumMBID(mbid string) bool {
+	return l != nil && mbid != "" && l.mbids[mbid]
+}
+
+// HasAlbum reports whether Lidarr has the album, ignoring edition suffixes
+func (l *Library) HasAlbum(request AlbumRequest) bool {
+	return l != nil && l.albums[libraryKey(request.Artist, albumSearchTitle(request.Title))]
+}
+
 // ValidateConfig validates the Lidarr configuration
 func (li *LidarrIntegration) ValidateConfig() error {
 	// Test Lidarr connection
@@ -1,6 +1,8 @@
 package integration
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/bambithedeer/spotify-api/internal/lidarr"
@@ -224,4 +226,101 @@ func TestBatchResult(t *testing.T) {
 	if len(batchResult.Results) != 3 {
 		t.Errorf("expected 3 results, got %d", len(batchResult.Results))
 	}
-}
\ No newline at end of file
+}
+func TestParseAlbumRequest(t *testing.T) {
+	request, err := ParseAlbumRequest("Pink Floyd - Wish You Were Here - 2011 Remaster")
+	if err != nil {
+		t.Fatalf("ParseAlbumRequest() error = %v", err)
+	}
+	if request.Artist != "Pink Floyd" || request.Title != "Wish You Were Here - 2011 Remaster" {
+		t.Errorf("expected the first dash to split artist and album, got %+v", request)
+	}
+
+	for _, line := range []string{"Pink Floyd", " - Animals", "Pink Floyd - "} {
+		if _, err := ParseAlbumRequest(line); err == nil {
+			t.Errorf("expected an error for %q", line)
+		}
+	}
+}
+
+func TestAlbumSearchTitle(t *testing.T) {
+	tests := map[string]string{
+		"Abbey Road (Remastered 2009)":       "Abbey Road",
+		"Wish You Were Here - 2011 Remaster": "Wish You Were Here",
+		"Rumours [Super Deluxe]":             "Rumours",
+		"OK Computer":                        "OK Computer",
+		"(What's the Story) Morning Glory?":  "(What's the Story) Morning Glory?",
+	}
+	for title, want := range tests {
+		if got := albumSearchTitle(title); got != want {
+			t.Errorf("albumSearchTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/artist":
+			w.Write([]byte(`[{"id": 1, "artistName": "Pink Floyd", "foreignArtistId": "83d91898-7763-47d7-b03b-b92132375c47"}]`))
+		case "/api/v1/album":
+			w.Write([]byte(`[{"id": 5, "title": "Animals", "artistId": 1}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	log := logger.NewLogger(&logger.Config{Level: "error", Format: "text", Output: "stdout"})
+	li := NewLidarrIntegration(lidarr.NewClient(lidarr.Config{BaseURL: server.URL, APIKey: "test-key"}), nil, &LidarrConfig{}, log)
+
+	library, err := li.Library()
+	if err != nil {
+		t.Fatalf("Library() error = %v", err)
+	}
+	if !library.HasArtist("pink  floyd") || library.HasArtist("Radiohead") {
+		t.Error("expected artists to match by name, ignoring case and spacing")
+	}
+	if !library.HasAlbum(AlbumRequest{Artist: "Pink Floyd", Title: "Animals (Deluxe Edition)"}) {
+		t.Error("expected an album to match with its edition suffix dropped")
+	}
+	if library.HasAlbum(AlbumRequest{Artist: "Radiohead", Title: "Animals"}) {
+		t.Error("expected an album by another artist not to match")
+	}
+	if !library.HasArtistMBID("83d91898-7763-47d7-b03b-b92132375c47") || library.HasAlbumMBID("") {
+		t.Error("expected artists to match by MusicBrainz ID, and an empty ID never to")
+	}
+}
+
+func TestBatchSkipsExisting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/artist":
+			w.Write([]byte(`[{"id": 1, "artistName": "Pink Floyd", "foreignArtistId": "83d91898-7763-47d7-b03b-b92132375c47"}]`))
+		case "/api/v1/album":
+			w.Write([]byte(`[{"id": 5, "title": "Animals", "artistId": 1}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// Without a MusicBrainz client, any lookup would panic
+	log := logger.NewLogger(&logger.Config{Level: "error", Format: "text", Output: "stdout"})
+	li := NewLidarrIntegration(lidarr.NewClient(lidarr.Config{BaseURL: server.URL, APIKey: "test-key"}), nil, &LidarrConfig{}, log)
+
+	artists := li.AddArtistsBatch([]string{"Pink Floyd", "pink floyd"}, 2)
+	if artists.Total != 2 || artists.Existing != 2 || artists.Failures != 0 || artists.Successes != 0 {
+		t.Errorf("expected both artists to be skipped as existing, got %+v", artists)
+	}
+	for _, r := range artists.Results {
+		if !r.AlreadyExists {
+			t.Errorf("expected %q to be marked as existing", r.SpotifyName)
+		}
+	}
+
+	albums := li.AddAlbumsBatch([]AlbumRequest{{Artist: "Pink Floyd", Title: "Animals (Deluxe Edition)"}}, 2)
+	if albums.Existing != 1 || albums.Failures != 0 {
+		t.Errorf("expected the album to be skipped as existing, got %+v", albums)
+	}
+}
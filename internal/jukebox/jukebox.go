@@ -0,0 +1,224 @@
+// Package jukebox keeps a Spotify Connect device playing a fixed context,
+// for always-on players such as a Raspberry Pi running librespot. A watch
+// loop restarts the context whenever playback stops, and simple text
+// commands, for example from GPIO buttons, control the bound device.
+package jukebox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// ErrDeviceOffline is returned when the bound device is not in the user's
+// device list, usually because its Connect receiver is not running
+var ErrDeviceOffline = errors.New("jukebox device is offline")
+
+// VolumeStep is how far "volume up" and "volume down" move the volume
+const VolumeStep = 10
+
+// Player is the playback API the jukebox drives
+type Player interface {
+	Devices(ctx context.Context) ([]models.Device, error)
+	State(ctx context.Context) (*models.PlaybackState, error)
+	// Play starts contextURI on the device, or resumes when it is empty
+	Play(ctx context.Context, deviceID, contextURI string) error
+	Pause(ctx context.Context, deviceID string) error
+	Next(ctx context.Context, deviceID string) error
+	Previous(ctx context.Context, deviceID string) error
+	SetVolume(ctx context.Context, deviceID string, percent int) error
+}
+
+// Config is what the jukebox plays and where
+type Config struct {
+	// Device is the Connect device name, matched case-insensitively
+	Device string
+	// Context is the album, playlist or artist URI to keep playing
+	Context string
+}
+
+// Action describes what a watch pass did
+type Action string
+
+const (
+	ActionNone      Action = "none"
+	ActionRestarted Action = "restarted"
+	ActionHeld      Action = "held"
+)
+
+// Jukebox binds a Player to one device. It is safe for concurrent use by the
+// watch loop and command handlers.
+type Jukebox struct {
+	player Player
+	config Config
+
+	mu sync.Mutex
+	// held is set by an explicit pause so the watch loop leaves playback
+	// stopped until the next play
+	held bool
+}
+
+// New creates a jukebox
+func New(player Player, config Config) *Jukebox {
+	return &Jukebox{player: player, config: config}
+}
+
+// Device finds the bound device
+func (j *Jukebox) Device(ctx context.Context) (*models.Device, error) {
+	devices, err := j.player.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range devices {
+		if strings.EqualFold(devices[i].Name, j.config.Device) && devices[i].ID != "" {
+			return &devices[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no device named %q", ErrDeviceOffline, j.config.Device)
+}
+
+// Check is one pass of the watch loop: when nothing is playing it starts the
+// configured context on the bound device. Playback someone has moved to
+// another device is left alone, as is playback paused with the pause command.
+func (j *Jukebox) Check(ctx context.Context) (Action, error) {
+	j.mu.Lock()
+	held := j.held
+	j.mu.Unlock()
+	if held {
+		return ActionHeld, nil
+	}
+
+	state, err := j.player.State(ctx)
+	if err != nil {
+		return ActionNone, err
+	}
+	if state != nil && state.IsPlaying {
+		return ActionNone, nil
+	}
+
+	device, err := j.Device(ctx)
+	if err != nil {
+		return ActionNone, err
+	}
+
+	// Resume where it stopped if the device still has the context loaded,
+	// otherwise start it from the top
+	contextURI := j.config.Context
+	if state != nil && state.Device.ID == device.ID && state.Context != nil && state.Context.URI == j.config.Context {
+		contextURI = ""
+	}
+	if err := j.player.Play(ctx, device.ID, contextURI); err != nil {
+		return ActionNone, err
+	}
+	return ActionRestarted, nil
+}
+
+// Commands lists the commands Handle accepts, for help output
+var Commands = []string{
+	"play", "pause", "toggle", "next", "previous", "restart",
+	"volume up", "volume down", "volume <0-100>", "status",
+}
+
+// Handle runs a text command against the bound device and returns a short
+// reply. Commands are a word, optionally followed by an argument.
+func (j *Jukebox) Handle(ctx context.Context, command string) (string, error) {
+	fields := strings.Fields(strings.ToLower(command))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+
+	if fields[0] == "status" {
+		return j.status(ctx)
+	}
+
+	device, err := j.Device(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	switch fields[0] {
+	case "play":
+		j.setHeld(false)
+		return "playing", j.player.Play(ctx, device.ID, "")
+	case "pause":
+		j.setHeld(true)
+		return "paused", j.player.Pause(ctx, device.ID)
+	case "toggle":
+		state, err := j.player.State(ctx)
+		if err != nil {
+			return "", err
+		}
+		if state != nil && state.IsPlaying && state.Device.ID == device.ID {
+			j.setHeld(true)
+			return "paused", j.player.Pause(ctx, device.ID)
+		}
+		j.setHeld(false)
+		return "playing", j.player.Play(ctx, device.ID, "")
+	case "next":
+		return "skipped", j.player.Next(ctx, device.ID)
+	case "previous", "prev":
+		return "went back", j.player.Previous(ctx, device.ID)
+	case "restart":
+		j.setHeld(false)
+		return "restarted", j.player.Play(ctx, device.ID, j.config.Context)
+	case "volume":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("usage: volume up|down|<0-100>")
+		}
+		percent, err := volumeTarget(device.VolumePercent, fields[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("volume %d", percent), j.player.SetVolume(ctx, device.ID, percent)
+	}
+
+	return "", fmt.Errorf("unknown command %q", fields[0])
+}
+
+func (j *Jukebox) setHeld(held bool) {
+	j.mu.Lock()
+	j.held = held
+	j.mu.Unlock()
+}
+
+func (j *Jukebox) status(ctx context.Context) (string, error) {
+	state, err := j.player.State(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	j.mu.Lock()
+	held := j.held
+	j.mu.Unlock()
+
+	switch {
+	case state == nil || !state.IsPlaying:
+		if held {
+			return "paused", nil
+		}
+		return "stopped", nil
+	case !strings.EqualFold(state.Device.Name, j.config.Device):
+		return "playing on " + state.Device.Name, nil
+	}
+	return fmt.Sprintf("playing, volume %d", state.Device.VolumePercent), nil
+}
+
+func volumeTarget(current int, arg string) (int, error) {
+	switch arg {
+	case "up":
+		return min(current+VolumeStep, 100), nil
+	case "down":
+		return max(current-VolumeStep, 0), nil
+	}
+
+	percent, err := strconv.Atoi(arg)
+	if err != nil || percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("volume must be up, down or 0-100, got %q", arg)
+	}
+	return percent, nil
+}
@@ -0,0 +1,149 @@
+package jukebox
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+type fakePlayer struct {
+	devices []models.Device
+	state   *models.PlaybackState
+	calls   []string
+}
+
+func (p *fakePlayer) Devices(ctx context.Context) ([]models.Device, error) {
+	return p.devices, nil
+}
+
+func (p *fakePlayer) State(ctx context.Context) (*models.PlaybackState, error) {
+	return p.state, nil
+}
+
+func (p *fakePlayer) Play(ctx context.Context, deviceID, contextURI string) error {
+	p.calls = append(p.calls, "play "+deviceID+" "+contextURI)
+	return nil
+}
+
+func (p *fakePlayer) Pause(ctx context.Context, deviceID string) error {
+	p.calls = append(p.calls, "pause "+deviceID)
+	return nil
+}
+
+func (p *fakePlayer) Next(ctx context.Context, deviceID string) error {
+	p.calls = append(p.calls, "next "+deviceID)
+	return nil
+}
+
+func (p *fakePlayer) Previous(ctx context.Context, deviceID string) error {
+	p.calls = append(p.calls, "previous "+deviceID)
+	return nil
+}
+
+func (p *fakePlayer) SetVolume(ctx context.Context, deviceID string, percent int) error {
+	p.calls = append(p.calls, "volume "+deviceID)
+	return nil
+}
+
+const radio = "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"
+
+func newFake() *fakePlayer {
+	return &fakePlayer{devices: []models.Device{
+		{ID: "phone", Name: "Phone"},
+		{ID: "pi", Name: "Kitchen Pi", VolumePercent: 95},
+	}}
+}
+
+func TestJukebox_Check(t *testing.T) {
+	ctx := context.Background()
+	player := newFake()
+	j := New(player, Config{Device: "kitchen pi", Context: radio})
+
+	// Nothing playing starts the context from the top
+	if action, err := j.Check(ctx); err != nil || action != ActionRestarted {
+		t.Fatalf("Expected a restart, got %v, %v", action, err)
+	}
+
+	// Stopped partway through the context on the device resumes instead
+	player.state = &models.PlaybackState{Device: models.Device{ID: "pi"}, Context: &models.Context{URI: radio}}
+	j.Check(ctx)
+
+	// Playing anywhere, even on another device, is left alone
+	player.state = &models.PlaybackState{Device: models.Device{ID: "phone"}, IsPlaying: true}
+	if action, _ := j.Check(ctx); action != ActionNone {
+		t.Errorf("Expected playback elsewhere to be left alone, got %v", action)
+	}
+
+	expected := []string{"play pi " + radio, "play pi "}
+	if len(player.calls) != len(expected) || player.calls[0] != expected[0] || player.calls[1] != expected[1] {
+		t.Errorf("Unexpected calls %q", player.calls)
+	}
+
+	player.devices = player.devices[:1]
+	player.state = nil
+	if _, err := j.Check(ctx); !errors.Is(err, ErrDeviceOffline) {
+		t.Errorf("Expected ErrDeviceOffline, got %v", err)
+	}
+}
+
+func TestJukebox_Handle(t *testing.T) {
+	ctx := context.Background()
+	player := newFake()
+	j := New(player, Config{Device: "Kitchen Pi", Context: radio})
+
+	// An explicit pause holds playback until the next play
+	if _, err := j.Handle(ctx, "pause"); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if action, _ := j.Check(ctx); action != ActionHeld {
+		t.Errorf("Expected the watch loop to hold after pause, got %v", action)
+	}
+	if reply, _ := j.Handle(ctx, "status"); reply != "paused" {
+		t.Errorf("Expected status paused, got %q", reply)
+	}
+	if reply, err := j.Handle(ctx, "TOGGLE"); err != nil || reply != "playing" {
+		t.Errorf("Expected toggle to play, got %q, %v", reply, err)
+	}
+
+	if reply, err := j.Handle(ctx, "volume up"); err != nil || reply != "volume 100" {
+		t.Errorf("Expected volume capped at 100, got %q, %v", reply, err)
+	}
+	if reply, err := j.Handle(ctx, "volume 40"); err != nil || reply != "volume 40" {
+		t.Errorf("Expected volume 40, got %q, %v", reply, err)
+	}
+
+	for _, bad := range []string{"", "volume", "volume loud", "volume 101", "dance"} {
+		if _, err := j.Handle(ctx, bad); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jukebox.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	player := newFake()
+	j := New(player, Config{Device: "Kitchen Pi", Context: radio})
+	go j.Serve(ctx, listener)
+
+	if reply, err := Send(path, "next"); err != nil || reply != "skipped" {
+		t.Errorf("Expected skipped, got %q, %v", reply, err)
+	}
+	if _, err := Send(path, "dance"); err == nil || err.Error() != `unknown command "dance"` {
+		t.Errorf("Expected the command error to come back, got %v", err)
+	}
+	if len(player.calls) != 1 || player.calls[0] != "next pi" {
+		t.Errorf("Unexpected calls %q", player.calls)
+	}
+}
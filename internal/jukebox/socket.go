@@ -0,0 +1,95 @@
+package jukebox
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// The control socket speaks one command per line. Every command gets a
+// single reply line, "ok <message>" or "error <message>", so a GPIO button
+// script can drive it with nothing more than
+//
+//	echo next | nc -U /path/to/jukebox.sock
+
+// commandTimeout bounds how long one command may take, so a stuck API call
+// cannot wedge a button
+const commandTimeout = 15 * time.Second
+
+// Serve answers commands on listener until ctx is cancelled
+func (j *Jukebox) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go j.serveConn(ctx, conn)
+	}
+}
+
+func (j *Jukebox) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		commandCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+		reply, err := j.Handle(commandCtx, line)
+		cancel()
+
+		if err != nil {
+			reply = "error " + oneLine(err.Error())
+		} else {
+			reply = "ok " + reply
+		}
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// Send runs a command through the control socket at path and returns the
+// reply message
+func Send(path, command string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("jukebox is not running: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(commandTimeout + 5*time.Second))
+	if _, err := fmt.Fprintln(conn, oneLine(command)); err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("no reply from jukebox: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	if message, ok := strings.CutPrefix(reply, "error "); ok {
+		return "", errors.New(message)
+	}
+	return strings.TrimPrefix(reply, "ok "), nil
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,153 @@
+// Package karaoke finds instrumental and karaoke versions of a track among
+// search results, by title, duration and how little singing they contain.
+package karaoke
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// DefaultMaxSpeechiness is the speechiness above which a candidate is assumed
+// to still have vocals. Sung music usually scores below 0.1, instrumentals
+// well under it.
+const DefaultMaxSpeechiness = 0.1
+
+// markers are the title fragments labels use for vocal-free releases
+var markers = []string{
+	"karaoke", "instrumental", "backing track", "minus one", "off vocal",
+	"no vocals", "without vocals", "in the style of", "originally performed by",
+}
+
+var (
+	// decoration matches bracketed qualifiers like "(feat. X)" or "[Live]"
+	decoration = regexp.MustCompile(`\s*[\(\[][^\)\]]*[\)\]]`)
+	// suffix matches dash qualifiers like " - Remastered 2011" or " - Karaoke Version"
+	suffix = regexp.MustCompile(`\s+-\s+.*$`)
+	// punctuation is removed before comparing titles
+	punctuation = regexp.MustCompile(`[^\p{L}\p{N}\s]+`)
+)
+
+// Candidate is a possible vocal-free version of the original track
+type Candidate struct {
+	Track    models.Track          `json:"track"`
+	Features *models.AudioFeatures `json:"features,omitempty"`
+	Score    float64               `json:"score"`
+}
+
+// CleanTitle strips qualifiers such as "(feat. X)" and " - Remastered" from
+// a track name and normalizes case and punctuation, so versions of a song
+// compare equal
+func CleanTitle(name string) string {
+	name = decoration.ReplaceAllString(name, "")
+	name = suffix.ReplaceAllString(name, "")
+	name = punctuation.ReplaceAllString(strings.ToLower(name), "")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// IsKaraokeTitle reports whether a track or album name labels a karaoke or
+// instrumental release
+func IsKaraokeTitle(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range markers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Queries returns the searches to run for vocal-free versions of a track,
+// most specific first. Karaoke labels rarely credit the original artist, so
+// most queries use the title alone.
+func Queries(original models.Track) []string {
+	title := CleanTitle(original.Name)
+	if title == "" {
+		return nil
+	}
+
+	queries := []string{
+		title + " karaoke",
+		title + " instrumental",
+		`track:"` + title + `" karaoke version`,
+	}
+	if len(original.Artists) > 0 {
+		artist := original.Artists[0].Name
+		queries = append([]string{title + " " + artist + " instrumental"}, queries...)
+		queries = append(queries, title+" in the style of "+artist)
+	}
+	return queries
+}
+
+// Match scores how likely track is a vocal-free version of original, from
+// 0 to 1. It returns false for the original itself, for tracks whose title
+// is a different song, and for tracks not labelled as karaoke or
+// instrumental.
+func Match(original, track models.Track) (float64, bool) {
+	if track.ID == original.ID || (track.URI != "" && track.URI == original.URI) {
+		return 0, false
+	}
+
+	album := ""
+	if track.Album != nil {
+		album = track.Album.Name
+	}
+	if !IsKaraokeTitle(track.Name) && !IsKaraokeTitle(album) {
+		return 0, false
+	}
+
+	want := CleanTitle(original.Name)
+	got := CleanTitle(track.Name)
+	if want == "" || !strings.Contains(got, want) {
+		return 0, false
+	}
+
+	// An exact title is worth more than one with extra words, such as a
+	// medley or a different song that shares the name
+	score := 0.5
+	if got == want {
+		score += 0.2
+	}
+
+	// Karaoke versions are usually re-recorded close to the original
+	// length; anything over a minute off gets no credit
+	if original.DurationMs > 0 && track.DurationMs > 0 {
+		diff := track.DurationMs - original.DurationMs
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < 60000 {
+			score += 0.3 * (1 - float64(diff)/60000)
+		}
+	}
+
+	return score, true
+}
+
+// Rank keeps the candidates with at most maxSpeechiness and orders them best
+// first. Each candidate's score is raised by its instrumentalness, so a
+// track Spotify hears as having no vocals beats one that is only labelled
+// that way. Candidates without features are kept below the rest, since they
+// could not be checked.
+func Rank(candidates []Candidate, maxSpeechiness float64) []Candidate {
+	var ranked []Candidate
+	for _, candidate := range candidates {
+		if f := candidate.Features; f != nil {
+			if f.Speechiness > maxSpeechiness {
+				continue
+			}
+			candidate.Score += f.Instrumentalness * 0.5
+		}
+		ranked = append(ranked, candidate)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if (ranked[i].Features == nil) != (ranked[j].Features == nil) {
+			return ranked[i].Features != nil
+		}
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
@@ -0,0 +1,71 @@
+package karaoke
+
+import (
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"Yesterday - Remastered 2009":                      "yesterday",
+		"Don't Stop Me Now (Karaoke Version)":              "dont stop me now",
+		"Umbrella [In the Style of Rihanna] (feat. JAY-Z)": "umbrella",
+		"  Hey   Jude ": "hey jude",
+	}
+
+	for input, want := range tests {
+		if got := CleanTitle(input); got != want {
+			t.Errorf("CleanTitle(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	original := models.Track{ID: "orig", Name: "Yesterday - Remastered 2009", DurationMs: 125000}
+
+	tests := []struct {
+		name  string
+		track models.Track
+		match bool
+	}{
+		{"karaoke version", models.Track{ID: "a", Name: "Yesterday (Karaoke Version)", DurationMs: 126000}, true},
+		{"labelled by album", models.Track{ID: "b", Name: "Yesterday", Album: &models.SimpleAlbum{Name: "Beatles Karaoke Hits"}}, true},
+		{"original", models.Track{ID: "orig", Name: "Yesterday (Instrumental)"}, false},
+		{"no marker", models.Track{ID: "c", Name: "Yesterday - Live"}, false},
+		{"other song", models.Track{ID: "d", Name: "Let It Be (Karaoke Version)"}, false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Match(original, tt.track); ok != tt.match {
+			t.Errorf("%s: expected match %v", tt.name, tt.match)
+		}
+	}
+
+	close, _ := Match(original, models.Track{ID: "e", Name: "Yesterday (Karaoke)", DurationMs: 125000})
+	far, _ := Match(original, models.Track{ID: "f", Name: "Yesterday (Karaoke)", DurationMs: 240000})
+	if close <= far {
+		t.Errorf("Expected a matching duration to score higher, got %.2f and %.2f", close, far)
+	}
+}
+
+func TestRank(t *testing.T) {
+	candidates := []Candidate{
+		{Track: models.Track{ID: "unchecked"}, Score: 1},
+		{Track: models.Track{ID: "vocal"}, Score: 1, Features: &models.AudioFeatures{Speechiness: 0.3}},
+		{Track: models.Track{ID: "labelled"}, Score: 0.8, Features: &models.AudioFeatures{Speechiness: 0.04, Instrumentalness: 0.1}},
+		{Track: models.Track{ID: "instrumental"}, Score: 0.7, Features: &models.AudioFeatures{Speechiness: 0.03, Instrumentalness: 0.9}},
+	}
+
+	ranked := Rank(candidates, DefaultMaxSpeechiness)
+	if len(ranked) != 3 {
+		t.Fatalf("Expected the vocal track to be filtered out, got %d candidates", len(ranked))
+	}
+
+	order := []string{"instrumental", "labelled", "unchecked"}
+	for i, id := range order {
+		if ranked[i].Track.ID != id {
+			t.Errorf("Expected %s at position %d, got %s", id, i, ranked[i].Track.ID)
+		}
+	}
+}
@@ -60,6 +60,28 @@ type AddOptions struct {
 	SearchForMissingAlbums bool   `json:"searchForMissingAlbums"`
 }
 
+// Album represents a Lidarr album. Lidarr adds the album's artist along
+// with it when the artist is not in the library yet.
+type Album struct {
+	ID             int              `json:"id,omitempty"`
+	Title          string           `json:"title"`
+	ForeignAlbumID string           `json:"foreignAlbumId"`
+	ArtistID       int              `json:"artistId,omitempty"`
+	AlbumType      string           `json:"albumType"`
+	ReleaseDate    string           `json:"releaseDate,omitempty"`
+	Monitored      bool             `json:"monitored"`
+	Artist         *Artist          `json:"artist,omitempty"`
+	Images         []Image          `json:"images"`
+	Links          []Link           `json:"links"`
+	Genres         []string         `json:"genres"`
+	AddOptions     *AlbumAddOptions `json:"addOptions,omitempty"`
+}
+
+// AlbumAddOptions represents options for adding an album
+type AlbumAddOptions struct {
+	SearchForNewAlbum bool `json:"searchForNewAlbum"`
+}
+
 // Link represents an external link
 type Link struct {
 	URL  string `json:"url"`
@@ -204,6 +226,88 @@ func (c *Client) AddArtistByMBID(mbid, rootFolderPath string, qualityProfileID,
 	return c.AddArtist(artist)
 }
 
+// SearchAlbum searches for an album by MusicBrainz release group ID
+func (c *Client) SearchAlbum(mbid string) ([]Album, error) {
+	searchTerm := fmt.Sprintf("lidarr:%s", mbid)
+	endpoint := fmt.Sprintf("/album/lookup?term=%s", url.QueryEscape(searchTerm))
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var albums []Album
+	if err := json.NewDecoder(resp.Body).Decode(&albums); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return albums, nil
+}
+
+// AddAlbum adds an album to Lidarr
+func (c *Client) AddAlbum(album Album) (*Album, error) {
+	resp, err := c.makeRequest("POST", "/album", album)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		// Lidarr explains a rejected album, such as one already added, in the body
+		var failures []struct {
+			ErrorMessage string `json:"errorMessage"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&failures) == nil && len(failures) > 0 && failures[0].ErrorMessage != "" {
+			return nil, fmt.Errorf("failed to add album, status: %d: %s", resp.StatusCode, failures[0].ErrorMessage)
+		}
+		return nil, fmt.Errorf("failed to add album, status: %d", resp.StatusCode)
+	}
+
+	var addedAlbum Album
+	if err := json.NewDecoder(resp.Body).Decode(&addedAlbum); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &addedAlbum, nil
+}
+
+// AddAlbumByMBID adds an album to Lidarr using its MusicBrainz release group
+// ID. An artist added with it monitors no other albums, so only this one is
+// wanted.
+func (c *Client) AddAlbumByMBID(mbid, rootFolderPath string, qualityProfileID, metadataProfileID int, monitor bool, searchForMissing bool) (*Album, error) {
+	albums, err := c.SearchAlbum(mbid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search for album: %w", err)
+	}
+
+	if len(albums) == 0 {
+		return nil, fmt.Errorf("album not found with MBID: %s", mbid)
+	}
+
+	album := albums[0]
+	if album.Artist == nil {
+		return nil, fmt.Errorf("album %s has no artist", mbid)
+	}
+	album.Monitored = monitor
+	album.AddOptions = &AlbumAddOptions{SearchForNewAlbum: monitor && searchForMissing}
+
+	artist := *album.Artist
+	artist.RootFolderPath = rootFolderPath
+	artist.QualityProfileID = qualityProfileID
+	artist.MetadataProfileID = metadataProfileID
+	artist.Monitored = monitor
+	artist.MonitorNewItems = "none"
+	artist.AddOptions = &AddOptions{Monitor: "none"}
+	album.Artist = &artist
+
+	return c.AddAlbum(album)
+}
+
 // GetRootFolders gets available root folders
 func (c *Client) GetRootFolders() ([]RootFolder, error) {
 	resp, err := c.makeRequest("GET", "/rootfolder", nil)
@@ -264,6 +368,46 @@ func (c *Client) GetMetadataProfiles() ([]MetadataProfile, error) {
 	return profiles, nil
 }
 
+// GetArtists gets every artist in the library
+func (c *Client) GetArtists() ([]Artist, error) {
+	resp, err := c.makeRequest("GET", "/artist", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var artists []Artist
+	if err := json.NewDecoder(resp.Body).Decode(&artists); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return artists, nil
+}
+
+// GetAlbums gets every album in the library, monitored or not
+func (c *Client) GetAlbums() ([]Album, error) {
+	resp, err := c.makeRequest("GET", "/album", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var albums []Album
+	if err := json.NewDecoder(resp.Body).Decode(&albums); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return albums, nil
+}
+
 // TestConnection tests the connection to Lidarr
 func (c *Client) TestConnection() error {
 	resp, err := c.makeRequest("GET", "/system/status", nil)
@@ -1,6 +1,10 @@
 package lidarr
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -171,4 +175,57 @@ func TestGetMetadataProfilesIntegration(t *testing.T) {
 
 	// This test requires a running Lidarr instance
 	t.Skip("integration test requires running Lidarr instance")
-}
\ No newline at end of file
+}
+func TestAddAlbumByMBID(t *testing.T) {
+	var added Album
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/album/lookup":
+			if term := r.URL.Query().Get("term"); term != "lidarr:rg-1" {
+				t.Errorf("unexpected lookup term %q", term)
+			}
+			w.Write([]byte(`[{"title": "Animals", "foreignAlbumId": "rg-1", "artist": {"artistName": "Pink Floyd", "foreignArtistId": "a-1"}}]`))
+		case r.Method == "POST" && r.URL.Path == "/api/v1/album":
+			if err := json.NewDecoder(r.Body).Decode(&added); err != nil {
+				t.Errorf("failed to decode album: %v", err)
+			}
+			added.ID = 7
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(added)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-api-key"})
+	album, err := client.AddAlbumByMBID("rg-1", "/music", 1, 2, true, true)
+	if err != nil {
+		t.Fatalf("AddAlbumByMBID() error = %v", err)
+	}
+	if album.ID != 7 || !added.Monitored || added.AddOptions == nil || !added.AddOptions.SearchForNewAlbum {
+		t.Errorf("expected a monitored album searched for on add, got %+v", added)
+	}
+	artist := added.Artist
+	if artist == nil || artist.RootFolderPath != "/music" || artist.QualityProfileID != 1 || artist.MetadataProfileID != 2 {
+		t.Fatalf("expected the artist to carry the profiles, got %+v", artist)
+	}
+	// Adding one album must not monitor the rest of the discography
+	if artist.MonitorNewItems != "none" || artist.AddOptions == nil || artist.AddOptions.Monitor != "none" {
+		t.Errorf("expected the artist to monitor nothing else, got %+v", artist)
+	}
+}
+
+func TestAddAlbumRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`[{"propertyName": "ForeignAlbumId", "errorMessage": "This album has already been added."}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(Config{BaseURL: server.URL, APIKey: "test-api-key"})
+	_, err := client.AddAlbum(Album{ForeignAlbumID: "rg-1"})
+	if err == nil || !strings.Contains(err.Error(), "already been added") {
+		t.Errorf("expected Lidarr's reason in the error, got %v", err)
+	}
+}
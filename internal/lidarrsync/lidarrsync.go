@@ -0,0 +1,88 @@
+// Package lidarrsync remembers which followed artists and saved albums have
+// already been sent to Lidarr, so a sync run from cron or as a daemon only
+// looks up what is new since the last one.
+package lidarrsync
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the items already synced
+const StoreName = "lidarrsync"
+
+// Item is a Spotify artist or album that a sync has dealt with
+type Item struct {
+	Name string `json:"name"`
+	MBID string `json:"mbid,omitempty"`
+	// Error is why the last attempt to add the item failed. Failed items
+	// are tried again once the retry delay has passed.
+	Error    string    `json:"error,omitempty"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// Failed reports whether the item could not be added
+func (i Item) Failed() bool {
+	return i.Error != ""
+}
+
+// State is everything synced so far, keyed by Spotify ID
+type State struct {
+	Artists  map[string]Item `json:"artists"`
+	Albums   map[string]Item `json:"albums"`
+	LastSync time.Time       `json:"last_sync"`
+}
+
+// Load reads the sync state. A sync that has never run has empty maps.
+func Load(dir *state.Dir) (*State, error) {
+	var s State
+	if err := dir.Read(StoreName, &s); err != nil {
+		return nil, err
+	}
+	s.init()
+	return &s, nil
+}
+
+func (s *State) init() {
+	if s.Artists == nil {
+		s.Artists = make(map[string]Item)
+	}
+	if s.Albums == nil {
+		s.Albums = make(map[string]Item)
+	}
+}
+
+// due reports whether an item needs syncing: it has not been seen, or it
+// failed at least retryAfter before now
+func due(items map[string]Item, id string, now time.Time, retryAfter time.Duration) bool {
+	item, ok := items[id]
+	return !ok || (item.Failed() && now.Sub(item.SyncedAt) >= retryAfter)
+}
+
+// ArtistDue reports whether the artist with this Spotify ID needs syncing
+func (s *State) ArtistDue(id string, now time.Time, retryAfter time.Duration) bool {
+	return due(s.Artists, id, now, retryAfter)
+}
+
+// AlbumDue reports whether the album with this Spotify ID needs syncing
+func (s *State) AlbumDue(id string, now time.Time, retryAfter time.Duration) bool {
+	return due(s.Albums, id, now, retryAfter)
+}
+
+// Record merges the outcome of a sync into the store and stamps the time
+// it finished. Items recorded by another run in the meantime are kept.
+func Record(dir *state.Dir, artists, albums map[string]Item, syncedAt time.Time) error {
+	var data State
+	return dir.Update(StoreName, &data, func() error {
+		data.init()
+		for id, item := range artists {
+			data.Artists[id] = item
+		}
+		for id, item := range albums {
+			data.Albums[id] = item
+		}
+		data.LastSync = syncedAt
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package lidarrsync
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRecordAndDue(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !s.ArtistDue("a", start, time.Hour) {
+		t.Error("Expected an unseen artist to be due")
+	}
+
+	err = Record(dir, map[string]Item{
+		"a": {Name: "Artist", MBID: "mbid-a", SyncedAt: start},
+		"b": {Name: "Missing", Error: "no match", SyncedAt: start},
+	}, map[string]Item{
+		"x": {Name: "Album", SyncedAt: start},
+	}, start)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	s, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !s.LastSync.Equal(start) {
+		t.Errorf("LastSync = %v, want %v", s.LastSync, start)
+	}
+	later := start.Add(30 * time.Minute)
+	if s.ArtistDue("a", later, time.Hour) || s.AlbumDue("x", later, time.Hour) {
+		t.Error("Expected synced items not to be due")
+	}
+	if s.ArtistDue("b", later, time.Hour) {
+		t.Error("Expected a failed artist to wait for the retry delay")
+	}
+	if !s.ArtistDue("b", start.Add(time.Hour), time.Hour) {
+		t.Error("Expected a failed artist to be due after the retry delay")
+	}
+
+	// A later run only adds to what is there
+	if err := Record(dir, map[string]Item{"b": {Name: "Missing", MBID: "mbid-b", SyncedAt: later}}, nil, later); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	s, _ = Load(dir)
+	if len(s.Artists) != 2 || s.Artists["b"].Failed() || len(s.Albums) != 1 {
+		t.Errorf("Expected the retry to replace the failure and keep the rest, got %+v", s)
+	}
+}
@@ -0,0 +1,131 @@
+// Package listens keeps a log of every play, one JSON line each, as the
+// recently played list is polled. Spotify forgets all but the last 50
+// plays; the log keeps them all, in the order they ended, for exporting or
+// for reports that need more than the totals in playcounts.
+package listens
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the play log, one play per line
+const StoreName = "listens.jsonl"
+
+// Listen is one play of a track. PlayedAt is when it stopped playing.
+type Listen struct {
+	PlayedAt   time.Time `json:"played_at" yaml:"played_at"`
+	URI        string    `json:"uri" yaml:"uri"`
+	Name       string    `json:"name" yaml:"name"`
+	Artists    []string  `json:"artists,omitempty" yaml:"artists,omitempty"`
+	Album      string    `json:"album,omitempty" yaml:"album,omitempty"`
+	DurationMs int       `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+	// Context is the URI of the playlist, album or artist played from
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+}
+
+// Append logs the plays that ended after the newest one logged so far,
+// oldest first, and returns how many were new. Polls overlap, so most of
+// what is passed in has usually been logged already.
+func Append(dir *state.Dir, plays []Listen) (int, error) {
+	plays = slices.Clone(plays)
+	sort.SliceStable(plays, func(i, j int) bool { return plays[i].PlayedAt.Before(plays[j].PlayedAt) })
+
+	appended := 0
+	err := dir.WithLock(StoreName, func() error {
+		logged, err := readUnlocked(dir)
+		if err != nil {
+			return err
+		}
+		var newest time.Time
+		if len(logged) > 0 {
+			newest = logged[len(logged)-1].PlayedAt
+		}
+
+		var buf bytes.Buffer
+		for _, play := range plays {
+			if play.URI == "" || !play.PlayedAt.After(newest) {
+				continue
+			}
+			play.PlayedAt = play.PlayedAt.UTC()
+			line, err := json.Marshal(play)
+			if err != nil {
+				return fmt.Errorf("failed to encode play: %w", err)
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+			newest = play.PlayedAt
+			appended++
+		}
+		if appended == 0 {
+			return nil
+		}
+
+		file, err := os.OpenFile(dir.Path(StoreName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open play log: %w", err)
+		}
+		if _, err := file.Write(buf.Bytes()); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write play log: %w", err)
+		}
+		return file.Close()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return appended, nil
+}
+
+// Since returns the plays logged that ended at or after since, oldest
+// first. A zero since returns the whole log.
+func Since(dir *state.Dir, since time.Time) ([]Listen, error) {
+	var plays []Listen
+	err := dir.WithLock(StoreName, func() error {
+		logged, err := readUnlocked(dir)
+		if err != nil {
+			return err
+		}
+		start := sort.Search(len(logged), func(i int) bool { return !logged[i].PlayedAt.Before(since) })
+		plays = logged[start:]
+		return nil
+	})
+	return plays, err
+}
+
+func readUnlocked(dir *state.Dir) ([]Listen, error) {
+	data, err := os.ReadFile(dir.Path(StoreName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read play log: %w", err)
+	}
+
+	var plays []Listen
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var play Listen
+		if err := json.Unmarshal(text, &play); err != nil {
+			// A torn final line from a crash should not hide the rest
+			continue
+		}
+		plays = append(plays, play)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read play log: %w", err)
+	}
+	return plays, nil
+}
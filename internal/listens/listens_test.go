@@ -0,0 +1,82 @@
+package listens
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestAppendAndSince(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	// Polls return the newest play first
+	n, err := Append(dir, []Listen{
+		{URI: "spotify:track:b", PlayedAt: at(4)},
+		{URI: "spotify:track:a", PlayedAt: at(0)},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Append() = %d, want 2", n)
+	}
+
+	// The next poll overlaps the last one
+	n, err = Append(dir, []Listen{
+		{URI: "spotify:track:c", PlayedAt: at(8)},
+		{URI: "spotify:track:b", PlayedAt: at(4)},
+		{URI: "spotify:track:a", PlayedAt: at(0)},
+		{PlayedAt: at(9)},
+	})
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if n != 1 {
+		t.Errorf("Append() of an overlapping poll = %d, want 1", n)
+	}
+
+	all, err := Since(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(all) != 3 || all[0].URI != "spotify:track:a" || all[2].URI != "spotify:track:c" {
+		t.Fatalf("Since(zero) = %+v, want a, b, c", all)
+	}
+
+	recent, err := Since(dir, at(4))
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(recent) != 2 || recent[0].URI != "spotify:track:b" {
+		t.Errorf("Since(4m) = %+v, want b, c", recent)
+	}
+}
+
+func TestSinceSkipsTornLine(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	if _, err := Append(dir, []Listen{{URI: "spotify:track:a", PlayedAt: time.Now()}}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	file, _ := os.OpenFile(dir.Path(StoreName), os.O_WRONLY|os.O_APPEND, 0600)
+	file.WriteString(`{"played_at": "2024-`)
+	file.Close()
+
+	all, err := Since(dir, time.Time{})
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("Expected the torn line to be skipped, got %+v", all)
+	}
+}
@@ -0,0 +1,75 @@
+// Package m3usync reads playlists kept as M3U or plain text files and
+// remembers which Spotify playlist each file is synced to, so a watch only
+// touches the playlists whose files have changed.
+package m3usync
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the files synced so far
+const StoreName = "m3usync"
+
+// Synced is a playlist file and the Spotify playlist that follows it
+type Synced struct {
+	PlaylistID string `json:"playlist_id"`
+	// Hash is of the file's contents when it was last synced
+	Hash     string    `json:"hash"`
+	SyncedAt time.Time `json:"synced_at"`
+	// Unresolved lists the entries no Spotify track was found for
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// State is what every sync so far has done. Files are keyed by their
+// absolute path, so two folders can hold files of the same name.
+type State struct {
+	Files map[string]Synced `json:"files"`
+	// Resolved maps the key of an entry without a Spotify URI to the URI of
+	// the track it was found as, so an edit to a file only looks up the
+	// lines that are new
+	Resolved map[string]string `json:"resolved"`
+}
+
+// Load reads the sync state. A sync that has never run has empty maps.
+func Load(dir *state.Dir) (*State, error) {
+	var s State
+	if err := dir.Read(StoreName, &s); err != nil {
+		return nil, err
+	}
+	s.init()
+	return &s, nil
+}
+
+func (s *State) init() {
+	if s.Files == nil {
+		s.Files = make(map[string]Synced)
+	}
+	if s.Resolved == nil {
+		s.Resolved = make(map[string]string)
+	}
+}
+
+// Changed reports whether the file at path needs syncing: it has not been
+// synced, or its contents have changed since
+func (s *State) Changed(path, hash string) bool {
+	synced, ok := s.Files[path]
+	return !ok || synced.PlaylistID == "" || synced.Hash != hash
+}
+
+// Record merges the outcome of a sync into the store. Files and entries
+// recorded by another run in the meantime are kept.
+func Record(dir *state.Dir, files map[string]Synced, resolved map[string]string) error {
+	var data State
+	return dir.Update(StoreName, &data, func() error {
+		data.init()
+		for path, synced := range files {
+			data.Files[path] = synced
+		}
+		for key, uri := range resolved {
+			data.Resolved[key] = uri
+		}
+		return nil
+	})
+}
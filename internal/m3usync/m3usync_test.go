@@ -0,0 +1,125 @@
+package m3usync
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestParseM3U(t *testing.T) {
+	src := "\ufeff#EXTM3U\n" +
+		"#PLAYLIST:Road Trip\n" +
+		"#EXTINF:354,Queen - Bohemian Rhapsody\n" +
+		"#EXTALB:A Night at the Opera\n" +
+		"https://open.spotify.com/track/4u7EnebtmKWzUH433cf5Qv?si=abc\n" +
+		"#EXTINF:-1 tvg-id=\"x\",Radiohead - Karma Police\n" +
+		"/music/Radiohead/OK Computer/06 Karma Police.flac\n" +
+		"\n" +
+		`C:\Music\Johnny Cash\03 - Johnny Cash - Hurt.mp3` + "\n" +
+		"spotify:track:3n3Ppam7vgaVa1iaRUc9Lp\n"
+
+	playlist, err := Parse(strings.NewReader(src), "/lists/trip.m3u8")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if playlist.Name != "Road Trip" || playlist.Hash == "" {
+		t.Errorf("Unexpected playlist %q with hash %q", playlist.Name, playlist.Hash)
+	}
+
+	want := []Entry{
+		{URI: "spotify:track:4u7EnebtmKWzUH433cf5Qv", Artist: "Queen", Title: "Bohemian Rhapsody", Album: "A Night at the Opera", Duration: 354 * time.Second, Line: 5},
+		{Artist: "Radiohead", Title: "Karma Police", Line: 7},
+		{Artist: "Johnny Cash", Title: "Hurt", Line: 9},
+		{URI: "spotify:track:3n3Ppam7vgaVa1iaRUc9Lp", Line: 10},
+	}
+	if len(playlist.Entries) != len(want) {
+		t.Fatalf("Parse() = %+v, want %d entries", playlist.Entries, len(want))
+	}
+	for i, entry := range playlist.Entries {
+		if entry != want[i] {
+			t.Errorf("Entry %d = %+v, want %+v", i, entry, want[i])
+		}
+	}
+}
+
+func TestParseText(t *testing.T) {
+	src := "# Songs for Sunday\nNick Drake - Pink Moon\nHoppípolla\n\nhttps://open.spotify.com/intl-de/track/4u7EnebtmKWzUH433cf5Qv\n"
+	playlist, err := Parse(strings.NewReader(src), "sunday.txt")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if playlist.Name != "sunday" || len(playlist.Entries) != 3 {
+		t.Fatalf("Parse() = %+v", playlist)
+	}
+	if e := playlist.Entries[0]; e.Artist != "Nick Drake" || e.Title != "Pink Moon" {
+		t.Errorf("Unexpected first entry %+v", e)
+	}
+	if e := playlist.Entries[1]; e.Artist != "" || e.Title != "Hoppípolla" || e.String() != "Hoppípolla" {
+		t.Errorf("Expected a line without a dash to be a title, got %+v", e)
+	}
+	if e := playlist.Entries[2]; e.URI != "spotify:track:4u7EnebtmKWzUH433cf5Qv" {
+		t.Errorf("Expected a localized link to give a URI, got %+v", e)
+	}
+
+	again, _ := Parse(strings.NewReader(src+"Sigur Rós - Glósóli\n"), "sunday.txt")
+	if again.Hash == playlist.Hash {
+		t.Error("Expected an edit to change the hash")
+	}
+}
+
+func TestParseTrackList(t *testing.T) {
+	src := "#PLAYLIST:Mix\n# Exported at 2024-05-01T10:00:00Z\n" +
+		"spotify:track:4u7EnebtmKWzUH433cf5Qv\tQueen - Bohemian Rhapsody\n" +
+		"spotify:local:::Home+Demo:120\tHome Demo\n" +
+		"https://open.spotify.com/track/3n3Ppam7vgaVa1iaRUc9Lp typed by hand\n"
+	playlist, err := Parse(strings.NewReader(src), "mix.txt")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if playlist.Name != "Mix" || len(playlist.Entries) != 2 {
+		t.Fatalf("Parse() = %+v, want two entries without the local file", playlist)
+	}
+	if e := playlist.Entries[0]; e.URI != "spotify:track:4u7EnebtmKWzUH433cf5Qv" || e.String() != "Queen - Bohemian Rhapsody" {
+		t.Errorf("Expected the label to describe the track, got %+v", e)
+	}
+	if e := playlist.Entries[1]; e.URI != "spotify:track:3n3Ppam7vgaVa1iaRUc9Lp" || e.Line != 5 {
+		t.Errorf("Unexpected last entry %+v", e)
+	}
+}
+
+func TestRecordAndChanged(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !s.Changed("/lists/a.m3u", "h1") {
+		t.Error("Expected an unseen file to need syncing")
+	}
+
+	now := time.Now()
+	if err := Record(dir, map[string]Synced{"/lists/a.m3u": {PlaylistID: "p1", Hash: "h1", SyncedAt: now}}, map[string]string{"k": "spotify:track:x"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := Record(dir, map[string]Synced{"/lists/b.m3u": {PlaylistID: "p2", Hash: "h2", SyncedAt: now}}, nil); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	s, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if s.Changed("/lists/a.m3u", "h1") || !s.Changed("/lists/a.m3u", "h3") {
+		t.Error("Expected only a new hash to need syncing")
+	}
+	if len(s.Files) != 2 || s.Resolved["k"] != "spotify:track:x" {
+		t.Errorf("Expected later records to keep earlier ones, got %+v", s)
+	}
+}
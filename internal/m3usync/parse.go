@@ -0,0 +1,191 @@
+package m3usync
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/tracklist"
+)
+
+// Extensions are those of the files a folder sync reads
+var Extensions = []string{".m3u", ".m3u8", ".txt"}
+
+// IsPlaylistFile reports whether name has one of the Extensions
+func IsPlaylistFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is one track of a playlist file
+type Entry struct {
+	// URI is set when the file names the Spotify track itself, as the
+	// open.spotify.com links of 'playlist export --format m3u8' do
+	URI    string
+	Artist string
+	Title  string
+	Album  string
+	// Duration is zero when the file does not give it
+	Duration time.Duration
+	// Line is where the entry is in the file, counting from 1
+	Line int
+}
+
+// Key identifies an entry that has to be looked up, whatever its file
+func (e Entry) Key() string {
+	return strings.ToLower(e.Artist + "\x00" + e.Title + "\x00" + e.Album)
+}
+
+// String describes the entry for messages
+func (e Entry) String() string {
+	switch {
+	case e.URI != "" && e.Title == "":
+		return e.URI
+	case e.Artist == "":
+		return e.Title
+	}
+	return e.Artist + " - " + e.Title
+}
+
+// Playlist is a parsed playlist file
+type Playlist struct {
+	// Name is from a #PLAYLIST line, or else the file name without its
+	// extension
+	Name    string
+	Entries []Entry
+	// Hash is of the file's contents
+	Hash string
+}
+
+var (
+	// trackURL matches a track link or URI, such as
+	// https://open.spotify.com/intl-de/track/ID?si=... or spotify:track:ID
+	trackURL = regexp.MustCompile(`^(?:https?://open\.spotify\.com/(?:intl-[a-z-]+/)?track/|spotify:track:)([0-9A-Za-z]{22})(?:[?#].*)?$`)
+	// trackNumber matches a leading track number in a file name, such as
+	// "01 - " or "3. "
+	trackNumber = regexp.MustCompile(`^\d{1,3}(?:\s*[-.]\s*|\s+)`)
+)
+
+// Parse reads a playlist file. Each line that is not blank or a comment is
+// a track: a Spotify link or URI, a path to an audio file, or in a text
+// file "Artist - Title" or a line of a track list (see package tracklist).
+// A path is described by the #EXTINF line before it or, without one, by its
+// file name. Local files of a track list are skipped, as only Spotify has
+// them. name is the file's name, which gives the playlist's name unless the
+// file sets one.
+func Parse(r io.Reader, name string) (*Playlist, error) {
+	hash := sha256.New()
+	scanner := bufio.NewScanner(io.TeeReader(r, hash))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	playlist := &Playlist{Name: strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))}
+	text := strings.EqualFold(filepath.Ext(name), ".txt")
+
+	// Set by #EXTINF and #EXTALB for the path that follows them
+	var info Entry
+	for line := 1; scanner.Scan(); line++ {
+		value := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			value = strings.TrimPrefix(value, "\ufeff")
+		}
+
+		switch {
+		case value == "":
+			continue
+		case strings.HasPrefix(value, tracklist.NameHeader):
+			if title := strings.TrimSpace(strings.TrimPrefix(value, tracklist.NameHeader)); title != "" {
+				playlist.Name = title
+			}
+			continue
+		case strings.HasPrefix(value, "#EXTINF:"):
+			info = parseExtInf(strings.TrimPrefix(value, "#EXTINF:"))
+			continue
+		case strings.HasPrefix(value, "#EXTALB:"):
+			info.Album = strings.TrimSpace(strings.TrimPrefix(value, "#EXTALB:"))
+			continue
+		case strings.HasPrefix(value, "#"):
+			continue
+		}
+
+		entry := info
+		info = Entry{}
+		entry.Line = line
+		ref, label := value, ""
+		if text {
+			ref, label = tracklist.Split(value)
+		}
+		if strings.HasPrefix(ref, "spotify:local:") {
+			continue
+		}
+		if match := trackURL.FindStringSubmatch(ref); match != nil {
+			entry.URI = "spotify:track:" + match[1]
+			if entry.Title == "" && label != "" {
+				entry.Artist, entry.Title = splitArtistTitle(label)
+			}
+		} else if entry.Title == "" {
+			if !text {
+				value = fileTitle(value)
+			}
+			entry.Artist, entry.Title = splitArtistTitle(value)
+			if entry.Title == "" {
+				continue
+			}
+		}
+		playlist.Entries = append(playlist.Entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	playlist.Hash = hex.EncodeToString(hash.Sum(nil))
+	return playlist, nil
+}
+
+// parseExtInf reads "seconds,Artist - Title". Attributes such as
+// tvg-id="..." that some players write between the two are skipped.
+func parseExtInf(value string) Entry {
+	var entry Entry
+	seconds, title, ok := strings.Cut(value, ",")
+	if !ok {
+		return entry
+	}
+	if fields := strings.Fields(seconds); len(fields) > 0 {
+		if n, err := strconv.Atoi(fields[0]); err == nil && n > 0 {
+			entry.Duration = time.Duration(n) * time.Second
+		}
+	}
+	entry.Artist, entry.Title = splitArtistTitle(title)
+	return entry
+}
+
+// fileTitle turns the path of an audio file into "Artist - Title", as well
+// as its name allows. Paths may use either kind of slash.
+func fileTitle(p string) string {
+	base := path.Base(strings.ReplaceAll(p, `\`, "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	base = trackNumber.ReplaceAllString(base, "")
+	return strings.ReplaceAll(base, "_", " ")
+}
+
+// splitArtistTitle splits "Artist - Title" at its first dash. A value
+// without one is all title.
+func splitArtistTitle(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if artist, title, ok := strings.Cut(value, " - "); ok {
+		return strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+	return "", value
+}
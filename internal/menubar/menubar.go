@@ -0,0 +1,104 @@
+// Package menubar renders menus in the plugin format read by xbar and
+// SwiftBar, which show them in the macOS menu bar. The app runs the plugin
+// every few seconds and shows what it prints: the first line is the menu bar
+// title, the lines after a --- separator are the menu.
+package menubar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Item is a menu entry
+type Item struct {
+	Title string
+	// Command is run in the background when the item is clicked, after which
+	// the menu is refreshed. Items without one are labels.
+	Command []string
+	// Color is a name or #rrggbb value for the title
+	Color string
+	// Children form a submenu
+	Children []Item
+	// Separator draws a line instead of an entry
+	Separator bool
+}
+
+// Menu is a menu bar title and its drop-down menu
+type Menu struct {
+	Title string
+	Items []Item
+}
+
+// Separator is a line between groups of items
+var Separator = Item{Separator: true}
+
+// Render writes the menu in plugin format
+func (m Menu) Render(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, sanitize(m.Title)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "---"); err != nil {
+		return err
+	}
+	return renderItems(w, m.Items, 0)
+}
+
+func renderItems(w io.Writer, items []Item, depth int) error {
+	prefix := strings.Repeat("--", depth)
+
+	for _, item := range items {
+		var line string
+		if item.Separator {
+			line = prefix + "---"
+		} else {
+			line = prefix + sanitize(item.Title) + attributes(item)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		if err := renderItems(w, item.Children, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func attributes(item Item) string {
+	var attrs []string
+	if item.Color != "" {
+		attrs = append(attrs, "color="+item.Color)
+	}
+	if len(item.Command) > 0 {
+		attrs = append(attrs, "shell="+quote(item.Command[0]))
+		for i, arg := range item.Command[1:] {
+			attrs = append(attrs, fmt.Sprintf("param%d=%s", i+1, quote(arg)))
+		}
+		attrs = append(attrs, "terminal=false", "refresh=true")
+	}
+
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " | " + strings.Join(attrs, " ")
+}
+
+// sanitize keeps titles from being read as plugin syntax: | starts the
+// attributes and leading dashes nest an item in a submenu
+func sanitize(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	title = strings.ReplaceAll(title, "|", "¦")
+	if strings.HasPrefix(title, "-") {
+		title = "–" + strings.TrimLeft(title, "-")
+	}
+	return title
+}
+
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
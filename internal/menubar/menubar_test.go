@@ -0,0 +1,44 @@
+package menubar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMenu_Render(t *testing.T) {
+	menu := Menu{
+		Title: "▶ Song | Live — Artist",
+		Items: []Item{
+			{Title: "--Intro--", Color: "gray"},
+			Separator,
+			{Title: "Pause", Command: []string{"/usr/local/bin/spotify-cli", "player", "pause"}},
+			{Title: "Playlists", Children: []Item{
+				{Title: "Road Trip", Command: []string{"/Applications/My Tools/spotify-cli", "player", "play", "--context", "spotify:playlist:abc"}},
+				Separator,
+				{Title: `Say "hi"`},
+			}},
+		},
+	}
+
+	var out strings.Builder
+	if err := menu.Render(&out); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"▶ Song ¦ Live — Artist",
+		"---",
+		"–Intro-- | color=gray",
+		"---",
+		"Pause | shell=/usr/local/bin/spotify-cli param1=player param2=pause terminal=false refresh=true",
+		"Playlists",
+		`--Road Trip | shell="/Applications/My Tools/spotify-cli" param1=player param2=play param3=--context param4=spotify:playlist:abc terminal=false refresh=true`,
+		"-----",
+		`--Say "hi"`,
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
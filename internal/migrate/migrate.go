@@ -0,0 +1,155 @@
+// Package migrate upgrades versioned documents, such as the CLI config file
+// and local state stores, from the format written by an older release to the
+// current one.
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// VersionKey is the document field holding the schema version. Documents
+// without it are treated as version 0.
+const VersionKey = "schema_version"
+
+// ErrTooNew is returned for documents written by a newer release than this one
+var ErrTooNew = errors.New("schema version is newer than this version of spotify-cli supports")
+
+// Migration upgrades a document from Version-1 to Version
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(doc map[string]interface{}) error
+}
+
+// Set is the ordered list of migrations for one kind of document
+type Set struct {
+	Name       string
+	Migrations []Migration
+}
+
+// NewSet creates a migration set, checking that versions start at 1 and
+// increase by one
+func NewSet(name string, migrations ...Migration) *Set {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			panic(fmt.Sprintf("migrate: %s migration %d has version %d, expected %d", name, i, m.Version, i+1))
+		}
+	}
+
+	return &Set{Name: name, Migrations: migrations}
+}
+
+// Latest returns the schema version produced by the last migration
+func (s *Set) Latest() int {
+	return len(s.Migrations)
+}
+
+// Result describes what Migrate did to a document
+type Result struct {
+	From    int
+	To      int
+	Applied []string
+}
+
+// Changed reports whether any migration was applied
+func (r Result) Changed() bool {
+	return r.From != r.To
+}
+
+// Version returns the schema version of a document
+func Version(doc map[string]interface{}) (int, error) {
+	value, ok := doc[VersionKey]
+	if !ok || value == nil {
+		return 0, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("invalid %s %v", VersionKey, v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("invalid %s %v", VersionKey, value)
+	}
+}
+
+// Check returns ErrTooNew if doc was written by a newer release
+func (s *Set) Check(doc map[string]interface{}) (int, error) {
+	version, err := Version(doc)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", s.Name, err)
+	}
+
+	if version > s.Latest() {
+		return version, fmt.Errorf("%s %w (file is version %d, supported up to %d); upgrade spotify-cli",
+			s.Name, ErrTooNew, version, s.Latest())
+	}
+
+	return version, nil
+}
+
+// Migrate applies every pending migration to doc in place and stamps it with
+// the latest version
+func (s *Set) Migrate(doc map[string]interface{}) (Result, error) {
+	version, err := s.Check(doc)
+	if err != nil {
+		return Result{From: version, To: version}, err
+	}
+
+	result := Result{From: version, To: version}
+	for _, m := range s.Migrations[version:] {
+		if err := m.Apply(doc); err != nil {
+			return result, fmt.Errorf("%s migration to version %d (%s) failed: %w", s.Name, m.Version, m.Description, err)
+		}
+		result.To = m.Version
+		result.Applied = append(result.Applied, m.Description)
+	}
+
+	doc[VersionKey] = result.To
+	return result, nil
+}
+
+// BackupFile copies path into dir before it is migrated. The backup name
+// records the version it was taken from, e.g. config.yaml.v0.20240101-120000.bak.
+func BackupFile(path, dir string, version int) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for backup: %w", path, err)
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s.v%d.%s.bak", filepath.Base(path), version, time.Now().Format("20060102-150405"))
+	backupPath := filepath.Join(dir, name)
+
+	dst, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(backupPath)
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(backupPath)
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return backupPath, nil
+}
@@ -0,0 +1,103 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testSet() *Set {
+	return NewSet("test",
+		Migration{Version: 1, Description: "rename output", Apply: func(doc map[string]interface{}) error {
+			if v, ok := doc["output"]; ok {
+				doc["default_output"] = v
+				delete(doc, "output")
+			}
+			return nil
+		}},
+		Migration{Version: 2, Description: "add retries", Apply: func(doc map[string]interface{}) error {
+			doc["max_retries"] = 3
+			return nil
+		}},
+	)
+}
+
+func TestSet_Migrate(t *testing.T) {
+	doc := map[string]interface{}{"output": "json"}
+
+	result, err := testSet().Migrate(doc)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	if result.From != 0 || result.To != 2 || len(result.Applied) != 2 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if doc["default_output"] != "json" || doc["max_retries"] != 3 || doc[VersionKey] != 2 {
+		t.Errorf("Unexpected migrated document: %v", doc)
+	}
+
+	// Migrating again is a no-op
+	result, err = testSet().Migrate(doc)
+	if err != nil || result.Changed() {
+		t.Errorf("Expected current document to be unchanged, got %+v (err=%v)", result, err)
+	}
+}
+
+func TestSet_MigrateFromJSONVersion(t *testing.T) {
+	doc := map[string]interface{}{VersionKey: float64(1)}
+
+	result, err := testSet().Migrate(doc)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if result.From != 1 || len(result.Applied) != 1 || result.Applied[0] != "add retries" {
+		t.Errorf("Expected only the second migration to apply, got %+v", result)
+	}
+}
+
+func TestSet_MigrateTooNew(t *testing.T) {
+	doc := map[string]interface{}{VersionKey: 5}
+
+	_, err := testSet().Migrate(doc)
+	if !errors.Is(err, ErrTooNew) {
+		t.Fatalf("Expected ErrTooNew, got %v", err)
+	}
+	if _, ok := doc["max_retries"]; ok {
+		t.Error("Expected newer document to be left untouched")
+	}
+}
+
+func TestNewSet_InvalidVersions(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewSet to panic on a version gap")
+		}
+	}()
+
+	NewSet("broken", Migration{Version: 1}, Migration{Version: 3})
+}
+
+func TestBackupFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("client_id: abc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := BackupFile(path, filepath.Join(dir, "backups"), 0)
+	if err != nil {
+		t.Fatalf("BackupFile failed: %v", err)
+	}
+
+	if !strings.HasPrefix(filepath.Base(backup), "config.yaml.v0.") {
+		t.Errorf("Unexpected backup name %s", backup)
+	}
+
+	data, err := os.ReadFile(backup)
+	if err != nil || string(data) != "client_id: abc\n" {
+		t.Errorf("Expected backup to match original, got %q (err=%v)", data, err)
+	}
+}
@@ -10,7 +10,7 @@ type Album struct {
 	ID                   string               `json:"id"`
 	Images               []Image              `json:"images"`
 	Name                 string               `json:"name"`
-	ReleaseDatePrecision ReleaseDatePrecision `json:",inline"`
+	ReleaseDatePrecision                      `yaml:",inline"`
 	Restrictions         *Restrictions        `json:"restrictions,omitempty"`
 	Type                 string               `json:"type"`
 	URI                  string               `json:"uri"`
@@ -34,7 +34,7 @@ type SimpleAlbum struct {
 	ID                   string               `json:"id"`
 	Images               []Image              `json:"images"`
 	Name                 string               `json:"name"`
-	ReleaseDatePrecision ReleaseDatePrecision `json:",inline"`
+	ReleaseDatePrecision                      `yaml:",inline"`
 	Restrictions         *Restrictions        `json:"restrictions,omitempty"`
 	Type                 string               `json:"type"`
 	URI                  string               `json:"uri"`
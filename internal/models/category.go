@@ -0,0 +1,9 @@
+package models
+
+// Category represents a browse category, such as "Mood" or "Hip-Hop"
+type Category struct {
+	Href  string  `json:"href"`
+	Icons []Image `json:"icons"`
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+}
@@ -87,7 +87,26 @@ const (
 
 // ReleaseDatePrecision represents a release date with precision
 type ReleaseDatePrecision struct {
-	Date      time.Time     `json:"-"`
-	DateStr   string        `json:"release_date"`
-	Precision DatePrecision `json:"release_date_precision"`
+	Date      time.Time     `json:"-" yaml:"-"`
+	DateStr   string        `json:"release_date" yaml:"release_date"`
+	Precision DatePrecision `json:"release_date_precision" yaml:"release_date_precision"`
+}
+
+// Released parses the release date, taking the first day of the month or
+// year when the precision is coarser than a day. It returns false when
+// there is no date or it cannot be parsed.
+func (r ReleaseDatePrecision) Released() (time.Time, bool) {
+	layout := "2006-01-02"
+	switch {
+	case r.Precision == DatePrecisionYear || len(r.DateStr) == 4:
+		layout = "2006"
+	case r.Precision == DatePrecisionMonth || len(r.DateStr) == 7:
+		layout = "2006-01"
+	}
+
+	date, err := time.Parse(layout, r.DateStr)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
 }
\ No newline at end of file
@@ -65,6 +65,11 @@ func TestTrackUnmarshal(t *testing.T) {
 	if track.Album.Name != "Test Album" {
 		t.Errorf("Expected album name 'Test Album', got %s", track.Album.Name)
 	}
+
+	released, ok := track.Album.Released()
+	if !ok || released.Format("2006-01-02") != "2023-01-01" {
+		t.Errorf("Expected release date 2023-01-01, got %v (%q)", released, track.Album.DateStr)
+	}
 }
 
 func TestArtistUnmarshal(t *testing.T) {
@@ -265,6 +270,38 @@ func TestAudioFeaturesUnmarshal(t *testing.T) {
 	if features.TimeSignature != 4 {
 		t.Errorf("Expected time signature 4, got %d", features.TimeSignature)
 	}
+
+	if features.KeyName() != "F minor" {
+		t.Errorf("Expected key 'F minor', got %s", features.KeyName())
+	}
+
+	if (AudioFeatures{Key: -1}).KeyName() != "" {
+		t.Error("Expected no key name when no key was detected")
+	}
+}
+
+func TestCamelotKey(t *testing.T) {
+	tests := []struct {
+		key, mode int
+		expected  string
+	}{
+		{0, 1, "8B"},
+		{9, 0, "8A"},
+		{4, 1, "12B"},
+		{11, 1, "1B"},
+		{5, 0, "4A"},
+		{-1, 1, ""},
+	}
+
+	for _, tt := range tests {
+		if got := CamelotKey(tt.key, tt.mode); got != tt.expected {
+			t.Errorf("CamelotKey(%d, %d) = %q, expected %q", tt.key, tt.mode, got, tt.expected)
+		}
+	}
+
+	if TransposeKey(10, 3) != 1 || TransposeKey(1, -3) != 10 || TransposeKey(-1, 2) != -1 {
+		t.Error("Expected keys to wrap around the octave and undetected keys to stay undetected")
+	}
 }
 
 func TestDatePrecisionHandling(t *testing.T) {
@@ -303,4 +340,48 @@ func TestSearchTypesConstants(t *testing.T) {
 			t.Errorf("Expected search type %s, got %s", expected, string(searchType))
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestPlaylistTrackUnmarshal(t *testing.T) {
+	itemsJSON := `[
+		{"added_at": "2023-01-01T00:00:00Z", "is_local": false, "track": {"type": "track", "id": "track1", "name": "Song", "uri": "spotify:track:track1", "duration_ms": 1000, "artists": [{"name": "Artist"}], "album": {"name": "Album"}}},
+		{"added_at": "2023-01-02T00:00:00Z", "is_local": false, "track": {"type": "episode", "id": "ep1", "name": "Episode", "uri": "spotify:episode:ep1", "duration_ms": 2000, "show": {"name": "Show", "publisher": "Publisher"}}},
+		{"added_at": "2023-01-03T00:00:00Z", "is_local": false, "track": null}
+	]`
+
+	var items []PlaylistTrack
+	if err := json.Unmarshal([]byte(itemsJSON), &items); err != nil {
+		t.Fatalf("Failed to unmarshal playlist tracks: %v", err)
+	}
+
+	track := items[0].Track
+	if !track.IsTrack() || track.IsEpisode() {
+		t.Fatalf("Expected first item to be a track, got %+v", track)
+	}
+	if track.URI() != "spotify:track:track1" || track.AlbumName() != "Album" || track.ArtistNames()[0] != "Artist" {
+		t.Errorf("Unexpected track accessors: %s %s %v", track.URI(), track.AlbumName(), track.ArtistNames())
+	}
+
+	episode := items[1].Track
+	if !episode.IsEpisode() || episode.Episode.ID != "ep1" {
+		t.Fatalf("Expected second item to be an episode, got %+v", episode)
+	}
+	if episode.Name() != "Episode" || episode.DurationMs() != 2000 || episode.AlbumName() != "Show" || episode.ArtistNames()[0] != "Publisher" {
+		t.Errorf("Unexpected episode accessors: %s %d %s %v", episode.Name(), episode.DurationMs(), episode.AlbumName(), episode.ArtistNames())
+	}
+
+	if items[2].Track != nil || items[2].Track.URI() != "" {
+		t.Error("Expected unavailable item to decode as nil")
+	}
+
+	// The wrapper must not leak into encoded output
+	data, err := json.Marshal(items[1])
+	if err != nil {
+		t.Fatalf("Failed to marshal playlist track: %v", err)
+	}
+	var decoded map[string]interface{}
+	json.Unmarshal(data, &decoded)
+	if inner, ok := decoded["track"].(map[string]interface{}); !ok || inner["type"] != "episode" {
+		t.Errorf("Expected episode object under track, got %s", data)
+	}
+}
@@ -67,10 +67,11 @@ type PlayHistory struct {
 	Context   Context `json:"context"`
 }
 
-// Queue represents the user's queue
+// Queue represents the user's queue: what is playing now and the tracks or
+// episodes that will play after it
 type Queue struct {
-	CurrentlyPlaying interface{}   `json:"currently_playing"` // Can be Track or Episode
-	Queue            []interface{} `json:"queue"`              // Can be Track or Episode
+	CurrentlyPlaying *PlaylistItem  `json:"currently_playing"` // nil when nothing is playing
+	Queue            []PlaylistItem `json:"queue"`
 }
 
 // DevicesResponse represents the response from the devices endpoint
@@ -0,0 +1,66 @@
+package models
+
+// Show represents a Spotify podcast show
+type Show struct {
+	AvailableMarkets   []string         `json:"available_markets"`
+	Copyrights         []Copyright      `json:"copyrights"`
+	Description        string           `json:"description"`
+	HTMLDescription    string           `json:"html_description"`
+	Explicit           bool             `json:"explicit"`
+	ExternalURLs       ExternalURLs     `json:"external_urls"`
+	Href               string           `json:"href"`
+	ID                 string           `json:"id"`
+	Images             []Image          `json:"images"`
+	IsExternallyHosted bool             `json:"is_externally_hosted"`
+	Languages          []string         `json:"languages"`
+	MediaType          string           `json:"media_type"`
+	Name               string           `json:"name"`
+	Publisher          string           `json:"publisher"`
+	Type               string           `json:"type"`
+	URI                string           `json:"uri"`
+	TotalEpisodes      int              `json:"total_episodes"`
+	Episodes           *Paging[Episode] `json:"episodes,omitempty"`
+}
+
+// Episode represents a podcast episode
+type Episode struct {
+	AudioPreviewURL      string        `json:"audio_preview_url"`
+	Description          string        `json:"description"`
+	HTMLDescription      string        `json:"html_description"`
+	DurationMs           int           `json:"duration_ms"`
+	Explicit             bool          `json:"explicit"`
+	ExternalURLs         ExternalURLs  `json:"external_urls"`
+	Href                 string        `json:"href"`
+	ID                   string        `json:"id"`
+	Images               []Image       `json:"images"`
+	IsExternallyHosted   bool          `json:"is_externally_hosted"`
+	IsPlayable           bool          `json:"is_playable"`
+	Language             string        `json:"language"`
+	Languages            []string      `json:"languages"`
+	Name                 string        `json:"name"`
+	ReleaseDate          string        `json:"release_date"`
+	ReleaseDatePrecision DatePrecision `json:"release_date_precision"`
+	ResumePoint          *ResumePoint  `json:"resume_point"`
+	Type                 string        `json:"type"`
+	URI                  string        `json:"uri"`
+	Restrictions         *Restrictions `json:"restrictions,omitempty"`
+	Show                 *Show         `json:"show,omitempty"`
+}
+
+// ResumePoint represents resume point for episodes
+type ResumePoint struct {
+	FullyPlayed      bool `json:"fully_played"`
+	ResumePositionMs int  `json:"resume_position_ms"`
+}
+
+// SavedShow represents a show saved in user's library
+type SavedShow struct {
+	AddedAt string `json:"added_at"`
+	Show    Show   `json:"show"`
+}
+
+// SavedEpisode represents an episode saved in user's library
+type SavedEpisode struct {
+	AddedAt string  `json:"added_at"`
+	Episode Episode `json:"episode"`
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 // Track represents a Spotify track
 type Track struct {
 	Album            *SimpleAlbum   `json:"album,omitempty"`
@@ -62,10 +67,136 @@ type SavedTrack struct {
 
 // PlaylistTrack represents a track in a playlist
 type PlaylistTrack struct {
-	AddedAt string      `json:"added_at"`
-	AddedBy *User       `json:"added_by"`
-	IsLocal bool        `json:"is_local"`
-	Track   interface{} `json:"track"` // Can be Track or Episode
+	AddedAt string        `json:"added_at"`
+	AddedBy *User         `json:"added_by"`
+	IsLocal bool          `json:"is_local"`
+	Track   *PlaylistItem `json:"track"` // nil for unavailable items
+}
+
+// PlaylistItem is the content of a playlist entry: either a track or a
+// podcast episode. Exactly one of Track and Episode is set.
+type PlaylistItem struct {
+	Track   *Track
+	Episode *Episode
+}
+
+// UnmarshalJSON decodes a track or episode object based on its type field
+func (p *PlaylistItem) UnmarshalJSON(data []byte) error {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	*p = PlaylistItem{}
+	if header.Type == "episode" {
+		p.Episode = &Episode{}
+		return json.Unmarshal(data, p.Episode)
+	}
+
+	p.Track = &Track{}
+	return json.Unmarshal(data, p.Track)
+}
+
+// MarshalJSON encodes the track or episode as Spotify returns it
+func (p PlaylistItem) MarshalJSON() ([]byte, error) {
+	switch {
+	case p.Episode != nil:
+		return json.Marshal(p.Episode)
+	case p.Track != nil:
+		return json.Marshal(p.Track)
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// MarshalYAML encodes the track or episode rather than the wrapper
+func (p PlaylistItem) MarshalYAML() (interface{}, error) {
+	if p.Episode != nil {
+		return p.Episode, nil
+	}
+	return p.Track, nil
+}
+
+// IsTrack reports whether the item is a track
+func (p *PlaylistItem) IsTrack() bool {
+	return p != nil && p.Track != nil
+}
+
+// IsEpisode reports whether the item is a podcast episode
+func (p *PlaylistItem) IsEpisode() bool {
+	return p != nil && p.Episode != nil
+}
+
+// ID returns the Spotify ID of the track or episode
+func (p *PlaylistItem) ID() string {
+	switch {
+	case p.IsTrack():
+		return p.Track.ID
+	case p.IsEpisode():
+		return p.Episode.ID
+	}
+	return ""
+}
+
+// URI returns the Spotify URI of the track or episode
+func (p *PlaylistItem) URI() string {
+	switch {
+	case p.IsTrack():
+		return p.Track.URI
+	case p.IsEpisode():
+		return p.Episode.URI
+	}
+	return ""
+}
+
+// Name returns the track or episode name
+func (p *PlaylistItem) Name() string {
+	switch {
+	case p.IsTrack():
+		return p.Track.Name
+	case p.IsEpisode():
+		return p.Episode.Name
+	}
+	return ""
+}
+
+// DurationMs returns the duration of the track or episode
+func (p *PlaylistItem) DurationMs() int {
+	switch {
+	case p.IsTrack():
+		return p.Track.DurationMs
+	case p.IsEpisode():
+		return p.Episode.DurationMs
+	}
+	return 0
+}
+
+// ArtistNames returns the track's artists, or the publisher of an episode's show
+func (p *PlaylistItem) ArtistNames() []string {
+	switch {
+	case p.IsTrack():
+		names := make([]string, len(p.Track.Artists))
+		for i, artist := range p.Track.Artists {
+			names[i] = artist.Name
+		}
+		return names
+	case p.IsEpisode() && p.Episode.Show != nil && p.Episode.Show.Publisher != "":
+		return []string{p.Episode.Show.Publisher}
+	}
+	return nil
+}
+
+// AlbumName returns the track's album, or the show an episode belongs to
+func (p *PlaylistItem) AlbumName() string {
+	switch {
+	case p.IsTrack() && p.Track.Album != nil:
+		return p.Track.Album.Name
+	case p.IsEpisode() && p.Episode.Show != nil:
+		return p.Episode.Show.Name
+	}
+	return ""
 }
 
 // AudioFeatures represents audio features for a track
@@ -90,6 +221,56 @@ type AudioFeatures struct {
 	Valence          float64 `json:"valence"`
 }
 
+// pitchClasses names the keys in pitch class notation, from C = 0
+var pitchClasses = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// KeyName spells a pitch class and mode as reported by the API, like
+// "F# minor", or returns "" for the -1 of an undetected key
+func KeyName(key, mode int) string {
+	if key < 0 || key >= len(pitchClasses) {
+		return ""
+	}
+	if mode == 0 {
+		return pitchClasses[key] + " minor"
+	}
+	return pitchClasses[key] + " major"
+}
+
+// CamelotKey returns the Camelot wheel position DJs use for harmonic mixing,
+// like "8B" for C major or "8A" for A minor, or "" for an undetected key.
+// Neighbouring numbers and the same number in the other mode mix cleanly.
+func CamelotKey(key, mode int) string {
+	if key < 0 || key >= len(pitchClasses) {
+		return ""
+	}
+	// Minor keys share a number with their relative major, three semitones up
+	letter := "B"
+	if mode == 0 {
+		key = (key + 3) % 12
+		letter = "A"
+	}
+	number := (7*key + 8) % 12
+	if number == 0 {
+		number = 12
+	}
+	return strconv.Itoa(number) + letter
+}
+
+// TransposeKey shifts a pitch class by semitones, which may be negative. An
+// undetected key stays undetected.
+func TransposeKey(key, semitones int) int {
+	if key < 0 || key >= len(pitchClasses) {
+		return key
+	}
+	return ((key+semitones)%12 + 12) % 12
+}
+
+// KeyName returns the track's key and mode, like "F# minor", or "" when
+// Spotify detected no key
+func (f AudioFeatures) KeyName() string {
+	return KeyName(f.Key, f.Mode)
+}
+
 // AudioAnalysis represents detailed audio analysis
 type AudioAnalysis struct {
 	Meta     AudioAnalysisMeta      `json:"meta"`
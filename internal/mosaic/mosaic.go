@@ -0,0 +1,154 @@
+// Package mosaic builds playlist cover images from album artwork: a 2x2 grid
+// of the albums a playlist draws on most, like the covers Spotify generates
+// from the first tracks but weighted by what the playlist is actually made of.
+package mosaic
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"sort"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// Tiles is the number of covers in a mosaic
+const Tiles = 4
+
+// DefaultSize is the width and height of generated covers, the largest size
+// Spotify serves playlist covers at
+const DefaultSize = 640
+
+// TopAlbums returns up to n albums ordered by how many of the playlist's
+// tracks come from them, most first. Ties keep playlist order. Episodes,
+// unavailable items and albums without artwork are skipped.
+func TopAlbums(items []models.PlaylistTrack, n int) []models.SimpleAlbum {
+	counts := make(map[string]int)
+	var albums []models.SimpleAlbum
+
+	for _, item := range items {
+		if item.Track == nil || item.Track.Track == nil {
+			continue
+		}
+		album := item.Track.Track.Album
+		if album == nil || album.ID == "" || len(album.Images) == 0 {
+			continue
+		}
+		if counts[album.ID] == 0 {
+			albums = append(albums, *album)
+		}
+		counts[album.ID]++
+	}
+
+	sort.SliceStable(albums, func(i, j int) bool {
+		return counts[albums[i].ID] > counts[albums[j].ID]
+	})
+
+	if len(albums) > n {
+		albums = albums[:n]
+	}
+	return albums
+}
+
+// PickImage returns the smallest image at least size pixels wide, or the
+// largest one when none is big enough, so tiles are scaled down rather than up
+func PickImage(images []models.Image, size int) (models.Image, bool) {
+	if len(images) == 0 {
+		return models.Image{}, false
+	}
+
+	best := images[0]
+	for _, img := range images[1:] {
+		switch {
+		case best.Width < size && img.Width > best.Width:
+			best = img
+		case img.Width >= size && img.Width < best.Width:
+			best = img
+		}
+	}
+	return best, true
+}
+
+// Compose draws covers into a size x size image. Four covers make a 2x2
+// grid in order; with fewer there is no grid to fill, so the first cover
+// takes the whole image.
+func Compose(covers []image.Image, size int) (image.Image, error) {
+	if len(covers) == 0 {
+		return nil, fmt.Errorf("no covers to compose")
+	}
+	if size < 2 {
+		return nil, fmt.Errorf("invalid mosaic size %d", size)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	if len(covers) < Tiles {
+		scaleInto(dst, dst.Bounds(), covers[0])
+		return dst, nil
+	}
+
+	half := size / 2
+	for i, cover := range covers[:Tiles] {
+		x, y := (i%2)*half, (i/2)*half
+		// The right and bottom tiles absorb the odd pixel of an odd size
+		rect := image.Rect(x, y, x+half, y+half)
+		if i%2 == 1 {
+			rect.Max.X = size
+		}
+		if i/2 == 1 {
+			rect.Max.Y = size
+		}
+		scaleInto(dst, rect, cover)
+	}
+	return dst, nil
+}
+
+// scaleInto draws src into rect of dst, cropping it to a centred square
+// first. Each destination pixel averages the source pixels it covers, which
+// keeps downscaled artwork from aliasing.
+func scaleInto(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	if side == 0 {
+		return
+	}
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	w, h := rect.Dx(), rect.Dy()
+	for dy := 0; dy < h; dy++ {
+		y0, y1 := dy*side/h, max((dy+1)*side/h, dy*side/h+1)
+		for dx := 0; dx < w; dx++ {
+			x0, x1 := dx*side/w, max((dx+1)*side/w, dx*side/w+1)
+
+			var r, g, b, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, _ := src.At(origin.X+sx, origin.Y+sy).RGBA()
+					r, g, b, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), n+1
+				}
+			}
+			dst.SetRGBA(rect.Min.X+dx, rect.Min.Y+dy, color.RGBA{
+				R: uint8((r / n) >> 8),
+				G: uint8((g / n) >> 8),
+				B: uint8((b / n) >> 8),
+				A: 0xFF,
+			})
+		}
+	}
+}
+
+// EncodeJPEG encodes img at the highest quality that fits in maxBytes
+func EncodeJPEG(img image.Image, maxBytes int) ([]byte, error) {
+	var buf bytes.Buffer
+	for quality := 90; quality >= 30; quality -= 10 {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+		if buf.Len() <= maxBytes {
+			return buf.Bytes(), nil
+		}
+	}
+	return nil, fmt.Errorf("cover is %d KB even at low quality, the limit is %d KB", buf.Len()/1024, maxBytes/1024)
+}
@@ -0,0 +1,129 @@
+package mosaic
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func playlistTrack(albumID string) models.PlaylistTrack {
+	return models.PlaylistTrack{Track: &models.PlaylistItem{Track: &models.Track{
+		Album: &models.SimpleAlbum{ID: albumID, Images: []models.Image{{URL: "https://i.scdn.co/" + albumID, Width: 640}}},
+	}}}
+}
+
+func TestTopAlbums(t *testing.T) {
+	items := []models.PlaylistTrack{
+		playlistTrack("a"),
+		playlistTrack("b"),
+		playlistTrack("b"),
+		{Track: nil},
+		{Track: &models.PlaylistItem{Episode: &models.Episode{ID: "e"}}},
+		playlistTrack("c"),
+		playlistTrack("b"),
+		playlistTrack("c"),
+		{Track: &models.PlaylistItem{Track: &models.Track{Album: &models.SimpleAlbum{ID: "no-art"}}}},
+		playlistTrack("d"),
+		playlistTrack("e"),
+	}
+
+	albums := TopAlbums(items, 4)
+	var ids []string
+	for _, album := range albums {
+		ids = append(ids, album.ID)
+	}
+	if got := strings.Join(ids, " "); got != "b c a d" {
+		t.Errorf("Expected b c a d, got %s", got)
+	}
+}
+
+func TestPickImage(t *testing.T) {
+	images := []models.Image{{URL: "640", Width: 640}, {URL: "300", Width: 300}, {URL: "64", Width: 64}}
+
+	if img, _ := PickImage(images, 320); img.URL != "640" {
+		t.Errorf("Expected the 640 image for 320 tiles, got %s", img.URL)
+	}
+	if img, _ := PickImage(images, 200); img.URL != "300" {
+		t.Errorf("Expected the 300 image for 200 tiles, got %s", img.URL)
+	}
+	if img, _ := PickImage(images, 1000); img.URL != "640" {
+		t.Errorf("Expected the largest image when none is big enough, got %s", img.URL)
+	}
+	if _, ok := PickImage(nil, 320); ok {
+		t.Error("Expected no image from an empty list")
+	}
+}
+
+func solid(c color.RGBA, w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompose(t *testing.T) {
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	green := color.RGBA{G: 0xFF, A: 0xFF}
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+
+	covers := []image.Image{solid(red, 64, 64), solid(green, 300, 300), solid(blue, 640, 480), solid(white, 10, 10)}
+	img, err := Compose(covers, 101)
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+	if img.Bounds().Dx() != 101 || img.Bounds().Dy() != 101 {
+		t.Fatalf("Expected a 101x101 image, got %v", img.Bounds())
+	}
+
+	corners := map[image.Point]color.RGBA{
+		{0, 0}:     red,
+		{100, 0}:   green,
+		{0, 100}:   blue,
+		{100, 100}: white,
+	}
+	for pt, want := range corners {
+		if got := color.RGBAModel.Convert(img.At(pt.X, pt.Y)).(color.RGBA); got != want {
+			t.Errorf("Pixel %v: expected %v, got %v", pt, want, got)
+		}
+	}
+
+	single, err := Compose(covers[:2], 50)
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+	if got := color.RGBAModel.Convert(single.At(49, 49)).(color.RGBA); got != red {
+		t.Errorf("Expected the first cover to fill the image, got %v", got)
+	}
+
+	if _, err := Compose(nil, 640); err == nil {
+		t.Error("Expected error for no covers")
+	}
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	img, err := Compose([]image.Image{solid(color.RGBA{R: 0x80, A: 0xFF}, 8, 8)}, DefaultSize)
+	if err != nil {
+		t.Fatalf("Compose failed: %v", err)
+	}
+
+	data, err := EncodeJPEG(img, 192*1024)
+	if err != nil {
+		t.Fatalf("EncodeJPEG failed: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("Expected a valid JPEG: %v", err)
+	}
+
+	if _, err := EncodeJPEG(img, 10); err == nil {
+		t.Error("Expected error when the image cannot fit")
+	}
+}
@@ -0,0 +1,132 @@
+// Package multiroom keeps several rooms playing the same thing for setups
+// without speakers that group natively. Spotify plays on one device per
+// account at a time, so each room is a device on a different account: one
+// leads, and the others are started and kept where it is.
+package multiroom
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Member is a room: a device on the account of a profile
+type Member struct {
+	// Profile is the account the device plays on, or "" for the current one
+	Profile string
+	// Device is the device's name or ID
+	Device string
+}
+
+func (m Member) String() string {
+	if m.Profile == "" {
+		return m.Device
+	}
+	return m.Profile + ":" + m.Device
+}
+
+// ParseMembers reads a list of rooms such as "Kitchen,work:Office", the first
+// of which leads. A device given without a profile plays on the current one.
+func ParseMembers(spec string) ([]Member, error) {
+	var members []Member
+	accounts := make(map[string]Member)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var m Member
+		if profile, device, ok := strings.Cut(part, ":"); ok {
+			m = Member{Profile: strings.TrimSpace(profile), Device: strings.TrimSpace(device)}
+		} else {
+			m = Member{Device: part}
+		}
+		if m.Device == "" {
+			return nil, fmt.Errorf("invalid device %q: use a name, or profile:name for a device on another account", part)
+		}
+
+		// A second device on an account would take playback from the first
+		if other, ok := accounts[m.Profile]; ok {
+			account := "the current profile"
+			if m.Profile != "" {
+				account = fmt.Sprintf("profile %q", m.Profile)
+			}
+			return nil, fmt.Errorf("%s and %s are both on %s: an account plays on one device at a time, so each room needs its own profile", other, m, account)
+		}
+		accounts[m.Profile] = m
+		members = append(members, m)
+	}
+	if len(members) < 2 {
+		return nil, fmt.Errorf("a group needs at least two devices")
+	}
+	return members, nil
+}
+
+// Sample is the playback of one room as it was read
+type Sample struct {
+	// Item is the URI of the track or episode, or "" when nothing is loaded
+	Item     string
+	Context  string
+	Playing  bool
+	Progress time.Duration
+	Duration time.Duration
+	// At is when the progress was read; the middle of the request is the
+	// best guess there is
+	At time.Time
+}
+
+// Position is how far into its item the room is at t, supposing it played
+// on since it was read
+func (s Sample) Position(t time.Time) time.Duration {
+	if !s.Playing {
+		return s.Progress
+	}
+	p := s.Progress + t.Sub(s.At)
+	if s.Duration > 0 && p > s.Duration {
+		p = s.Duration
+	}
+	return max(p, 0)
+}
+
+// Drift is how far follower is ahead of leader at t, or behind when
+// negative
+func Drift(leader, follower Sample, t time.Time) time.Duration {
+	return follower.Position(t) - leader.Position(t)
+}
+
+// Action is what a follower needs to be back in step
+type Action int
+
+const (
+	// Keep leaves the follower alone
+	Keep Action = iota
+	// Join plays the leader's item on the follower from the leader's position
+	Join
+	// Seek moves the follower to the leader's position
+	Seek
+	// Pause stops the follower because the leader stopped
+	Pause
+)
+
+func (a Action) String() string {
+	return [...]string{"keep", "join", "seek", "pause"}[a]
+}
+
+// Plan decides what follower needs to match leader at now. Drift within
+// tolerance is left alone, since every seek is heard as a skip.
+func Plan(leader, follower Sample, now time.Time, tolerance time.Duration) Action {
+	switch {
+	case leader.Item == "" || !leader.Playing:
+		if follower.Playing {
+			return Pause
+		}
+		return Keep
+	case !follower.Playing || follower.Item != leader.Item:
+		return Join
+	}
+	drift := Drift(leader, follower, now)
+	if drift > tolerance || drift < -tolerance {
+		return Seek
+	}
+	return Keep
+}
@@ -0,0 +1,77 @@
+package multiroom
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMembers(t *testing.T) {
+	members, err := ParseMembers("Kitchen, work:Office ,family:Living Room")
+	if err != nil {
+		t.Fatalf("ParseMembers failed: %v", err)
+	}
+	want := []Member{{Device: "Kitchen"}, {Profile: "work", Device: "Office"}, {Profile: "family", Device: "Living Room"}}
+	if len(members) != len(want) {
+		t.Fatalf("Expected %d members, got %+v", len(want), members)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("Member %d: expected %+v, got %+v", i, want[i], members[i])
+		}
+	}
+
+	for spec, message := range map[string]string{
+		"Kitchen":               "at least two",
+		"Kitchen,Office":        "one device at a time",
+		"work:Kitchen,work:Den": `profile "work"`,
+		"Kitchen,work:":         "invalid device",
+	} {
+		if _, err := ParseMembers(spec); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("ParseMembers(%q): expected an error about %q, got %v", spec, message, err)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	at := time.Date(2024, 6, 7, 18, 0, 0, 0, time.UTC)
+	s := Sample{Item: "spotify:track:a", Playing: true, Progress: 10 * time.Second, Duration: time.Minute, At: at}
+	if got := s.Position(at.Add(5 * time.Second)); got != 15*time.Second {
+		t.Errorf("Expected 15s, got %s", got)
+	}
+	if got := s.Position(at.Add(time.Hour)); got != time.Minute {
+		t.Errorf("Expected the position to stop at the end of the track, got %s", got)
+	}
+	s.Playing = false
+	if got := s.Position(at.Add(5 * time.Second)); got != 10*time.Second {
+		t.Errorf("Expected a paused position to stay put, got %s", got)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	at := time.Date(2024, 6, 7, 18, 0, 0, 0, time.UTC)
+	leader := Sample{Item: "spotify:track:a", Playing: true, Progress: 30 * time.Second, Duration: 3 * time.Minute, At: at}
+	now := at.Add(time.Second)
+	tolerance := time.Second
+
+	tests := []struct {
+		name     string
+		leader   Sample
+		follower Sample
+		want     Action
+	}{
+		{"in step", leader, Sample{Item: leader.Item, Playing: true, Progress: 30500 * time.Millisecond, At: at}, Keep},
+		{"behind", leader, Sample{Item: leader.Item, Playing: true, Progress: 28 * time.Second, At: at}, Seek},
+		{"ahead", leader, Sample{Item: leader.Item, Playing: true, Progress: 33 * time.Second, At: at}, Seek},
+		{"other track", leader, Sample{Item: "spotify:track:b", Playing: true, Progress: 30 * time.Second, At: at}, Join},
+		{"stopped", leader, Sample{Item: leader.Item, Progress: 30 * time.Second, At: at}, Join},
+		{"leader paused", Sample{Item: leader.Item, Progress: 30 * time.Second, At: at}, Sample{Item: leader.Item, Playing: true, At: at}, Pause},
+		{"both paused", Sample{Item: leader.Item, At: at}, Sample{Item: "spotify:track:b", At: at}, Keep},
+		{"leader idle", Sample{}, Sample{}, Keep},
+	}
+	for _, tt := range tests {
+		if got := Plan(tt.leader, tt.follower, now, tolerance); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package musicbrainz
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// CacheTTL is how long a lookup is reused. MBIDs do not change, but new
+// artists and better matches are added to MusicBrainz all the time.
+const CacheTTL = 90 * 24 * time.Hour
+
+// Cache keeps the best matches found for artist and release group searches
+// in a JSON file, so a repeated import does not search for them again. Only
+// matches are kept; a search that found nothing is tried again next time.
+type Cache struct {
+	path string
+
+	mu      sync.Mutex
+	loaded  bool
+	entries cacheFile
+}
+
+type cacheFile struct {
+	Artists       map[string]cachedArtist       `json:"artists"`
+	ReleaseGroups map[string]cachedReleaseGroup `json:"release_groups"`
+}
+
+type cachedArtist struct {
+	Artist    Artist    `json:"artist"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+type cachedReleaseGroup struct {
+	ReleaseGroup ReleaseGroup `json:"release_group"`
+	FetchedAt    time.Time    `json:"fetched_at"`
+}
+
+// NewCache returns the cache kept in the file at path. The file is read on
+// first use and created on the first lookup worth keeping.
+func NewCache(path string) *Cache {
+	return &Cache{path: path}
+}
+
+// Path returns the file the cache is kept in
+func (c *Cache) Path() string {
+	return c.path
+}
+
+// cacheKey reduces search terms to lowercase words, so "The  Beatles" and
+// "the beatles" share an entry
+func cacheKey(terms ...string) string {
+	for i, term := range terms {
+		terms[i] = strings.Join(strings.Fields(strings.ToLower(term)), " ")
+	}
+	return strings.Join(terms, "\x00")
+}
+
+func (c *Cache) load() error {
+	if c.loaded {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read MusicBrainz cache: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &c.entries); err != nil {
+			// A damaged cache is only a cache; start over
+			c.entries = cacheFile{}
+		}
+	}
+	if c.entries.Artists == nil {
+		c.entries.Artists = make(map[string]cachedArtist)
+	}
+	if c.entries.ReleaseGroups == nil {
+		c.entries.ReleaseGroups = make(map[string]cachedReleaseGroup)
+	}
+	c.loaded = true
+	return nil
+}
+
+func (c *Cache) save() error {
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode MusicBrainz cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return state.WriteFileAtomic(c.path, data, 0644)
+}
+
+func (c *Cache) artist(name string) (*Artist, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.load() != nil {
+		return nil, false
+	}
+
+	entry, ok := c.entries.Artists[cacheKey(name)]
+	if !ok || time.Since(entry.FetchedAt) > CacheTTL {
+		return nil, false
+	}
+	artist := entry.Artist
+	return &artist, true
+}
+
+func (c *Cache) putArtist(name string, artist *Artist) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	c.entries.Artists[cacheKey(name)] = cachedArtist{Artist: *artist, FetchedAt: time.Now().UTC()}
+	return c.save()
+}
+
+func (c *Cache) releaseGroup(artistName, title string) (*ReleaseGroup, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.load() != nil {
+		return nil, false
+	}
+
+	entry, ok := c.entries.ReleaseGroups[cacheKey(artistName, title)]
+	if !ok || time.Since(entry.FetchedAt) > CacheTTL {
+		return nil, false
+	}
+	group := entry.ReleaseGroup
+	return &group, true
+}
+
+func (c *Cache) putReleaseGroup(artistName, title string, group *ReleaseGroup) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	c.entries.ReleaseGroups[cacheKey(artistName, title)] = cachedReleaseGroup{ReleaseGroup: *group, FetchedAt: time.Now().UTC()}
+	return c.save()
+}
+
+// Len returns the number of lookups in the cache
+func (c *Cache) Len() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.load(); err != nil {
+		return 0, err
+	}
+	return len(c.entries.Artists) + len(c.entries.ReleaseGroups), nil
+}
+
+// Clear removes every lookup and returns how many there were
+func (c *Cache) Clear() (int, error) {
+	n, err := c.Len()
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to remove MusicBrainz cache: %w", err)
+	}
+	c.entries = cacheFile{Artists: make(map[string]cachedArtist), ReleaseGroups: make(map[string]cachedReleaseGroup)}
+	return n, nil
+}
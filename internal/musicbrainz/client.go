@@ -7,6 +7,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
 const (
@@ -20,6 +22,9 @@ type Client struct {
 	httpClient  *http.Client
 	rateLimiter *time.Ticker
 	userAgent   string
+	baseURL     string
+	retry       *ratelimit.RetryConfig
+	cache       *Cache
 }
 
 // Artist represents a MusicBrainz artist
@@ -48,6 +53,31 @@ type SearchResponse struct {
 	Artists []Artist `json:"artists"`
 }
 
+// ReleaseGroup represents a MusicBrainz release group: an album, EP or
+// single, covering every edition and reissue of it
+type ReleaseGroup struct {
+	ID               string         `json:"id"`
+	Title            string         `json:"title"`
+	PrimaryType      string         `json:"primary-type"`
+	FirstReleaseDate string         `json:"first-release-date"`
+	ArtistCredit     []ArtistCredit `json:"artist-credit"`
+	Score            int            `json:"score"`
+}
+
+// ArtistCredit represents one artist credited on a release group
+type ArtistCredit struct {
+	Name   string `json:"name"`
+	Artist Artist `json:"artist"`
+}
+
+// ReleaseGroupSearchResponse represents the response from a release group search
+type ReleaseGroupSearchResponse struct {
+	Created       string         `json:"created"`
+	Count         int            `json:"count"`
+	Offset        int            `json:"offset"`
+	ReleaseGroups []ReleaseGroup `json:"release-groups"`
+}
+
 // NewClient creates a new MusicBrainz API client
 func NewClient() *Client {
 	return &Client{
@@ -56,53 +86,109 @@ func NewClient() *Client {
 		},
 		rateLimiter: time.NewTicker(RateLimit),
 		userAgent:   UserAgent,
+		baseURL:     BaseURL,
+		retry:       ratelimit.DefaultRetryConfig(),
 	}
 }
 
+// SetBaseURL points the client at another MusicBrainz server, such as a
+// local mirror
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetRetryConfig changes how failed searches are retried. MusicBrainz
+// answers 503 when it is busy or a client goes over the rate limit.
+func (c *Client) SetRetryConfig(retry *ratelimit.RetryConfig) {
+	c.retry = retry
+}
+
+// SetCache makes the client reuse the matches kept in cache; nil turns
+// caching off
+func (c *Client) SetCache(cache *Cache) {
+	c.cache = cache
+}
+
 // SearchArtist searches for artists by name
 func (c *Client) SearchArtist(artistName string) (*SearchResponse, error) {
-	// Wait for rate limiter
-	<-c.rateLimiter.C
-
 	// Build search query
 	query := fmt.Sprintf("artist:%s", url.QueryEscape(strings.ToLower(artistName)))
 
-	// Construct URL
-	searchURL := fmt.Sprintf("%s/artist/?query=%s&fmt=json&limit=10", BaseURL, query)
-
-	// Create request
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var searchResp SearchResponse
+	if err := c.search("artist", query, &searchResp); err != nil {
+		return nil, err
 	}
+	return &searchResp, nil
+}
 
-	// Set headers
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept", "application/json")
+// SearchReleaseGroup searches for release groups by title and artist name
+func (c *Client) SearchReleaseGroup(artistName, title string) (*ReleaseGroupSearchResponse, error) {
+	query := url.QueryEscape(fmt.Sprintf(`releasegroup:"%s" AND artist:"%s"`, quoteEscaper.Replace(title), quoteEscaper.Replace(artistName)))
 
-	// Make request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+	var searchResp ReleaseGroupSearchResponse
+	if err := c.search("release-group", query, &searchResp); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &searchResp, nil
+}
+
+// quoteEscaper escapes a value for a quoted term of a search query
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// search runs an already escaped query against an entity's search endpoint.
+// Network errors and busy responses are retried per the retry config, each
+// attempt waiting its turn under the rate limit.
+func (c *Client) search(entity, query string, out interface{}) error {
+	searchURL := fmt.Sprintf("%s/%s/?query=%s&fmt=json&limit=10", c.baseURL, entity, query)
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	for attempt := 0; ; attempt++ {
+		// Wait for rate limiter
+		<-c.rateLimiter.C
+
+		resp, err := c.get(searchURL)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			defer resp.Body.Close()
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+			return nil
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if c.retry == nil || !c.retry.ShouldRetry(resp, attempt) || c.retry.ExceedsMaxRetryAfter(resp) {
+			if err != nil {
+				return fmt.Errorf("failed to make request: %w", err)
+			}
+			return fmt.Errorf("API request failed with status %d", resp.StatusCode)
+		}
+		time.Sleep(c.retry.NextDelay(attempt, resp))
 	}
+}
 
-	// Parse response
-	var searchResp SearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// get sends a GET with the headers MusicBrainz asks clients to send. A
+// failed request returns a nil response.
+func (c *Client) get(url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
 
-	return &searchResp, nil
+	return c.httpClient.Do(req)
 }
 
-// GetBestMatch returns the best matching artist from search results
+// GetBestMatch returns the best matching artist from search results, or
+// from the cache when it has the name
 func (c *Client) GetBestMatch(artistName string) (*Artist, error) {
+	if c.cache != nil {
+		if artist, ok := c.cache.artist(artistName); ok {
+			return artist, nil
+		}
+	}
+
 	searchResp, err := c.SearchArtist(artistName)
 	if err != nil {
 		return nil, err
@@ -114,9 +200,56 @@ func (c *Client) GetBestMatch(artistName string) (*Artist, error) {
 
 	// Return the first result (highest score)
 	bestMatch := &searchResp.Artists[0]
+	if c.cache != nil {
+		// The match was found either way, so a cache that cannot be
+		// written is not an error
+		c.cache.putArtist(artistName, bestMatch)
+	}
 	return bestMatch, nil
 }
 
+// GetBestReleaseGroup returns the best matching release group from search
+// results. Albums are preferred over singles and EPs of the same name.
+func (c *Client) GetBestReleaseGroup(artistName, title string) (*ReleaseGroup, error) {
+	if c.cache != nil {
+		if group, ok := c.cache.releaseGroup(artistName, title); ok {
+			return group, nil
+		}
+	}
+
+	group, err := c.bestReleaseGroup(artistName, title)
+	if err != nil {
+		return nil, err
+	}
+	if c.cache != nil {
+		c.cache.putReleaseGroup(artistName, title, group)
+	}
+	return group, nil
+}
+
+func (c *Client) bestReleaseGroup(artistName, title string) (*ReleaseGroup, error) {
+	searchResp, err := c.SearchReleaseGroup(artistName, title)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(searchResp.ReleaseGroups) == 0 {
+		return nil, fmt.Errorf("no release groups found for '%s' by '%s'", title, artistName)
+	}
+
+	best := &searchResp.ReleaseGroups[0]
+	for i := range searchResp.ReleaseGroups {
+		group := &searchResp.ReleaseGroups[i]
+		if group.Score < best.Score {
+			break
+		}
+		if group.PrimaryType == "Album" {
+			return group, nil
+		}
+	}
+	return best, nil
+}
+
 // GetArtistMBID returns the MusicBrainz ID for an artist
 func (c *Client) GetArtistMBID(artistName string) (string, error) {
 	artist, err := c.GetBestMatch(artistName)
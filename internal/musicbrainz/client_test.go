@@ -1,8 +1,14 @@
 package musicbrainz
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
 func TestNewClient(t *testing.T) {
@@ -146,4 +152,52 @@ func TestRateLimit(t *testing.T) {
 	if elapsed < RateLimit {
 		t.Errorf("rate limiting not working properly: elapsed %v, expected at least %v", elapsed, RateLimit)
 	}
-}
\ No newline at end of file
+}
+
+func TestGetBestMatchRetriesAndCaches(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// MusicBrainz answers 503 when a client goes over the rate limit
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"count": 1, "artists": [{"id": "a74b1b7f-71a5-4011-9441-d0b5e4122711", "name": "Radiohead", "score": 100}]}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "musicbrainz.json")
+	client := NewClient()
+	defer client.Close()
+	client.SetBaseURL(server.URL)
+	client.SetRetryConfig(&ratelimit.RetryConfig{
+		MaxRetries:      2,
+		BaseDelay:       time.Millisecond,
+		MaxDelay:        time.Millisecond,
+		BackoffFactor:   1,
+		RetryableErrors: map[int]bool{http.StatusServiceUnavailable: true},
+	})
+	client.SetCache(NewCache(path))
+
+	artist, err := client.GetBestMatch("Radiohead")
+	if err != nil {
+		t.Fatalf("GetBestMatch failed: %v", err)
+	}
+	if artist.ID != "a74b1b7f-71a5-4011-9441-d0b5e4122711" || atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("expected a match after one retry, got %+v after %d requests", artist, atomic.LoadInt32(&requests))
+	}
+
+	// A new client with the same cache file does not search again
+	cached := NewClient()
+	defer cached.Close()
+	cached.SetBaseURL(server.URL)
+	cached.SetCache(NewCache(path))
+	artist, err = cached.GetBestMatch("  radiohead")
+	if err != nil || artist.Name != "Radiohead" || atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("expected the cached match, got %+v, %v after %d requests", artist, err, atomic.LoadInt32(&requests))
+	}
+
+	if n, err := cached.cache.Clear(); err != nil || n != 1 {
+		t.Errorf("Clear() = %d, %v, want 1 entry", n, err)
+	}
+}
@@ -0,0 +1,175 @@
+// Package notes keeps free-text notes attached to Spotify items, such as
+// "great bassline" on a track, or to a single play of one. Notes live only
+// in the local state directory, so they travel with 'state export'.
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding notes
+const StoreName = "notes"
+
+// Kinds of item a note can be attached to
+var Kinds = []string{"track", "album", "artist", "playlist", "episode", "show"}
+
+var uriPattern = regexp.MustCompile(`^spotify:([a-z]+):[0-9A-Za-z]{22}$`)
+
+// Note is a note on an item, or on one play of it when PlayedAt is set
+type Note struct {
+	ID        int        `json:"id" yaml:"id"`
+	URI       string     `json:"uri" yaml:"uri"`
+	Text      string     `json:"text" yaml:"text"`
+	PlayedAt  *time.Time `json:"played_at,omitempty" yaml:"played_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" yaml:"created_at"`
+}
+
+// Kind returns the item type from the note's URI, such as "track"
+func (n Note) Kind() string {
+	return Kind(n.URI)
+}
+
+// Kind returns the item type of a Spotify URI, or "" if it is not one
+func Kind(uri string) string {
+	matches := uriPattern.FindStringSubmatch(uri)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// ValidateURI checks that uri is a Spotify URI of a kind notes can be
+// attached to
+func ValidateURI(uri string) error {
+	kind := Kind(uri)
+	if kind == "" {
+		return errors.NewValidationError(fmt.Sprintf("invalid Spotify URI: %s", uri))
+	}
+	for _, k := range Kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return errors.NewValidationError(fmt.Sprintf("notes cannot be attached to %s URIs (supported: %s)", kind, strings.Join(Kinds, ", ")))
+}
+
+type store struct {
+	NextID int    `json:"next_id"`
+	Notes  []Note `json:"notes"`
+}
+
+// Add stores a new note and returns it with its ID assigned
+func Add(dir *state.Dir, uri, text string, playedAt *time.Time, now time.Time) (*Note, error) {
+	if err := ValidateURI(uri); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.NewValidationError("note text cannot be empty")
+	}
+
+	note := Note{URI: uri, Text: text, PlayedAt: playedAt, CreatedAt: now.UTC()}
+	var data store
+	err := dir.Update(StoreName, &data, func() error {
+		if data.NextID == 0 {
+			data.NextID = 1
+		}
+		note.ID = data.NextID
+		data.NextID++
+		data.Notes = append(data.Notes, note)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// All returns every note, oldest first
+func All(dir *state.Dir) ([]Note, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	return data.Notes, nil
+}
+
+// ForURI returns the notes on an item, oldest first
+func ForURI(dir *state.Dir, uri string) ([]Note, error) {
+	all, err := All(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []Note
+	for _, note := range all {
+		if note.URI == uri {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+// Remove deletes the note with the given ID
+func Remove(dir *state.Dir, id int) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		for i, note := range data.Notes {
+			if note.ID == id {
+				data.Notes = append(data.Notes[:i], data.Notes[i+1:]...)
+				return nil
+			}
+		}
+		return errors.NewValidationError(fmt.Sprintf("no note with ID %d", id))
+	})
+}
+
+// Index groups notes by URI, for annotating listings of many items
+type Index map[string][]Note
+
+// Load reads every note into an Index
+func Load(dir *state.Dir) (Index, error) {
+	all, err := All(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(Index)
+	for _, note := range all {
+		index[note.URI] = append(index[note.URI], note)
+	}
+	return index, nil
+}
+
+// For returns the notes on an item, oldest first
+func (idx Index) For(uri string) []Note {
+	return idx[uri]
+}
+
+// Subset keeps the notes on the given URIs, dropping the rest
+func (idx Index) Subset(uris []string) Index {
+	subset := make(Index)
+	for _, uri := range uris {
+		if notes, ok := idx[uri]; ok {
+			subset[uri] = notes
+		}
+	}
+	return subset
+}
+
+// URIs returns the noted URIs, sorted
+func (idx Index) URIs() []string {
+	uris := make([]string, 0, len(idx))
+	for uri := range idx {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
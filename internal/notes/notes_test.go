@@ -0,0 +1,91 @@
+package notes
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+const (
+	trackURI = "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"
+	albumURI = "spotify:album:4aawyAB9vmqN3uQ7FjRGTy"
+)
+
+func openDir(t *testing.T) *state.Dir {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	return dir
+}
+
+func TestAddAndRemove(t *testing.T) {
+	dir := openDir(t)
+	now := time.Now()
+	playedAt := now.Add(-time.Hour).UTC()
+
+	first, err := Add(dir, trackURI, "  great bassline ", nil, now)
+	if err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+	if first.ID != 1 || first.Text != "great bassline" || first.Kind() != "track" {
+		t.Errorf("Unexpected note %+v", first)
+	}
+
+	if _, err := Add(dir, albumURI, "front to back", nil, now); err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+	if _, err := Add(dir, trackURI, "heard it live", &playedAt, now); err != nil {
+		t.Fatalf("Failed to add note: %v", err)
+	}
+
+	onTrack, err := ForURI(dir, trackURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(onTrack) != 2 || onTrack[1].PlayedAt == nil || !onTrack[1].PlayedAt.Equal(playedAt) {
+		t.Errorf("Expected two notes on the track, the second on a play, got %+v", onTrack)
+	}
+
+	if err := Remove(dir, first.ID); err != nil {
+		t.Fatalf("Failed to remove note: %v", err)
+	}
+	if err := Remove(dir, first.ID); err == nil {
+		t.Error("Expected an error removing a note twice")
+	}
+
+	// IDs are not reused after a removal
+	next, err := Add(dir, trackURI, "again", nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID != 4 {
+		t.Errorf("Expected ID 4, got %d", next.ID)
+	}
+
+	index, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.For(trackURI)) != 2 || len(index.For(albumURI)) != 1 {
+		t.Errorf("Unexpected index %+v", index)
+	}
+	if subset := index.Subset([]string{albumURI, "spotify:track:0000000000000000000000"}); len(subset) != 1 {
+		t.Errorf("Expected a subset with only the album, got %+v", subset)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	dir := openDir(t)
+
+	for _, uri := range []string{"4iV5W9uYEdYUVa79Axb7Rh", "spotify:user:4iV5W9uYEdYUVa79Axb7Rh", "spotify:track:short"} {
+		if _, err := Add(dir, uri, "text", nil, time.Now()); err == nil {
+			t.Errorf("Expected %q to be rejected", uri)
+		}
+	}
+	if _, err := Add(dir, trackURI, "   ", nil, time.Now()); err == nil {
+		t.Error("Expected empty text to be rejected")
+	}
+}
@@ -0,0 +1,309 @@
+// Package notifier posts playback and library events to webhooks, such as
+// those of Home Assistant, Slack or ntfy. Each hook picks the events it
+// wants and may shape its request body with a template; failed deliveries
+// are retried with backoff.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Event types
+const (
+	TrackStarted    = "track.started"
+	TrackSaved      = "track.saved"
+	PlaylistChanged = "playlist.changed"
+)
+
+// Types lists the event types, for help output and validation
+var Types = []string{TrackStarted, TrackSaved, PlaylistChanged}
+
+// Track is the track an event is about
+type Track struct {
+	URI     string   `json:"uri"`
+	Name    string   `json:"name"`
+	Artists []string `json:"artists,omitempty"`
+	Album   string   `json:"album,omitempty"`
+	URL     string   `json:"url,omitempty"`
+}
+
+// Playlist is the playlist an event is about
+type Playlist struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SnapshotID string `json:"snapshot_id"`
+	Tracks     int    `json:"tracks"`
+	URL        string `json:"url,omitempty"`
+}
+
+// Event is what is posted. Text describes it in a sentence, which Slack and
+// similar incoming webhooks show as the message.
+type Event struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Text     string    `json:"text"`
+	Track    *Track    `json:"track,omitempty"`
+	Playlist *Playlist `json:"playlist,omitempty"`
+	// Device is where a track started
+	Device string `json:"device,omitempty"`
+}
+
+// NewTrackEvent builds a track.started or track.saved event
+func NewTrackEvent(eventType string, track Track, at time.Time) Event {
+	verb := "Saved"
+	if eventType == TrackStarted {
+		verb = "Now playing"
+	}
+	text := fmt.Sprintf("%s: %s", verb, track.Name)
+	if len(track.Artists) > 0 {
+		text += " by " + strings.Join(track.Artists, ", ")
+	}
+	return Event{Type: eventType, Time: at, Text: text, Track: &track}
+}
+
+// NewPlaylistEvent builds a playlist.changed event
+func NewPlaylistEvent(playlist Playlist, at time.Time) Event {
+	return Event{
+		Type:     PlaylistChanged,
+		Time:     at,
+		Text:     fmt.Sprintf("Playlist %s changed, %d tracks", playlist.Name, playlist.Tracks),
+		Playlist: &playlist,
+	}
+}
+
+// Hook is a URL events are posted to
+type Hook struct {
+	// Name tells hooks apart in messages; the URL's host is used without it
+	Name string
+	URL  string
+	// Events are the event types posted; none is every type
+	Events []string
+	// Template is a text/template over the Event that makes the body. Without
+	// one the event is posted as JSON.
+	Template    string
+	ContentType string
+	Headers     map[string]string
+}
+
+// Defaults for retrying failed deliveries
+const (
+	DefaultRetries = 3
+	DefaultBackoff = 2 * time.Second
+	// maxRetryAfter bounds how long a Retry-After header may hold a hook up
+	maxRetryAfter = time.Minute
+)
+
+// Notifier posts events to hooks
+type Notifier struct {
+	hooks  []hook
+	client *http.Client
+
+	// Retries is how many times a failed delivery is tried again; Backoff is
+	// the wait before the first retry, and doubles before each after it
+	Retries int
+	Backoff time.Duration
+}
+
+type hook struct {
+	Hook
+	template *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	// json writes a value as JSON, for templates building JSON bodies
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// New checks hooks and parses their templates
+func New(hooks []Hook) (*Notifier, error) {
+	n := &Notifier{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}
+	for _, h := range hooks {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("webhook %s: invalid URL, it must be http or https", h.label())
+		}
+		if h.Name == "" {
+			h.Name = u.Host
+		}
+		for _, event := range h.Events {
+			if !validType(event) {
+				return nil, fmt.Errorf("webhook %s: unknown event %q. Events: %s", h.Name, event, strings.Join(Types, ", "))
+			}
+		}
+
+		parsed := hook{Hook: h}
+		if h.Template != "" {
+			if parsed.template, err = template.New(h.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(h.Template); err != nil {
+				return nil, fmt.Errorf("webhook %s: invalid template: %w", h.Name, err)
+			}
+		}
+		n.hooks = append(n.hooks, parsed)
+	}
+	return n, nil
+}
+
+func validType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (h Hook) label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return fmt.Sprintf("%q", h.URL)
+}
+
+// wants reports whether the hook takes events of eventType
+func (h hook) wants(eventType string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Send posts event to every hook that wants it. Hooks that fail after
+// their retries are named in the error; the others are delivered anyway.
+func (n *Notifier) Send(ctx context.Context, event Event) error {
+	var errs []error
+	for _, h := range n.hooks {
+		if !h.wants(event.Type) {
+			continue
+		}
+		if err := n.deliver(ctx, h, event); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) deliver(ctx context.Context, h hook, event Event) error {
+	body, contentType, err := h.body(event)
+	if err != nil {
+		return err
+	}
+
+	wait := n.Backoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := n.post(ctx, h, body, contentType)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= n.Retries {
+			return err
+		}
+
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// body renders the request body for event
+func (h hook) body(event Event) ([]byte, string, error) {
+	contentType := h.ContentType
+	if h.template == nil {
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		body, err := json.Marshal(event)
+		return body, contentType, err
+	}
+
+	var b bytes.Buffer
+	if err := h.template.Execute(&b, event); err != nil {
+		return nil, "", fmt.Errorf("failed to render template: %w", err)
+	}
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+		if json.Valid(b.Bytes()) {
+			contentType = "application/json"
+		}
+	}
+	return b.Bytes(), contentType, nil
+}
+
+// permanentError is a rejection that retrying will not change
+type permanentError struct {
+	status string
+}
+
+func (e *permanentError) Error() string {
+	return "rejected with " + e.status
+}
+
+// post makes one delivery attempt. It returns how long the hook asked to
+// be left alone for, if it did.
+func (n *Notifier) post(ctx context.Context, h hook, body []byte, contentType string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{status: err.Error()}
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", "spotify-cli")
+	for name, value := range h.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode >= 500:
+		return retryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("returned %s", resp.Status)
+	default:
+		return 0, &permanentError{status: resp.Status}
+	}
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return min(time.Duration(seconds)*time.Second, maxRetryAfter)
+}
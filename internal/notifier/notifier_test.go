@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var at = time.Date(2024, 6, 7, 18, 30, 0, 0, time.UTC)
+
+func song() Track {
+	return Track{URI: "spotify:track:a", Name: "Song", Artists: []string{"A", "B"}, Album: "Album"}
+}
+
+func newNotifier(t *testing.T, hooks ...Hook) *Notifier {
+	t.Helper()
+	n, err := New(hooks)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	n.Backoff = time.Millisecond
+	return n
+}
+
+func TestNew(t *testing.T) {
+	for _, hooks := range [][]Hook{
+		{{URL: "ftp://example.com"}},
+		{{URL: "example.com/hook"}},
+		{{URL: "https://example.com", Events: []string{"track.skipped"}}},
+		{{URL: "https://example.com", Template: "{{.Track.Name"}},
+	} {
+		if _, err := New(hooks); err == nil {
+			t.Errorf("Expected an error for %+v", hooks[0])
+		}
+	}
+}
+
+func TestSendJSON(t *testing.T) {
+	var got Event
+	var contentType, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, token = r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, Hook{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}})
+	if err := n.Send(context.Background(), NewTrackEvent(TrackStarted, song(), at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got.Type != TrackStarted || got.Track == nil || got.Track.Name != "Song" || !got.Time.Equal(at) {
+		t.Errorf("Unexpected event %+v", got)
+	}
+	if got.Text != "Now playing: Song by A, B" {
+		t.Errorf("Expected the text to describe the track, got %q", got.Text)
+	}
+	if contentType != "application/json" || token != "Bearer secret" {
+		t.Errorf("Unexpected headers %q, %q", contentType, token)
+	}
+}
+
+func TestSendTemplate(t *testing.T) {
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, contentType = string(b), r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, Hook{URL: server.URL, Template: `{{.Track.Name}} - {{join .Track.Artists " & "}}`})
+	if err := n.Send(context.Background(), NewTrackEvent(TrackSaved, song(), at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if body != "Song - A & B" || !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Unexpected body %q of type %q", body, contentType)
+	}
+
+	n = newNotifier(t, Hook{URL: server.URL, Template: `{"state": {{json .Text}}}`})
+	if err := n.Send(context.Background(), NewTrackEvent(TrackSaved, song(), at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if body != `{"state": "Saved: Song by A, B"}` || contentType != "application/json" {
+		t.Errorf("Unexpected body %q of type %q", body, contentType)
+	}
+
+	// A playlist event has no track for the template to read
+	if err := n.Send(context.Background(), NewPlaylistEvent(Playlist{ID: "p", Name: "Mix"}, at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	n = newNotifier(t, Hook{URL: server.URL, Template: `{{.Track.Name}}`})
+	if err := n.Send(context.Background(), NewPlaylistEvent(Playlist{ID: "p", Name: "Mix"}, at)); err == nil {
+		t.Error("Expected an error rendering a missing track")
+	}
+}
+
+func TestSendEvents(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, Hook{URL: server.URL, Events: []string{PlaylistChanged}})
+	if err := n.Send(context.Background(), NewTrackEvent(TrackStarted, song(), at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Error("Expected a hook to skip events it did not ask for")
+	}
+	if err := n.Send(context.Background(), NewPlaylistEvent(Playlist{ID: "p", Name: "Mix"}, at)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected one call, got %d", calls.Load())
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	n := newNotifier(t, Hook{URL: server.URL})
+	if err := n.Send(context.Background(), NewTrackEvent(TrackStarted, song(), at)); err != nil {
+		t.Fatalf("Expected a retry to deliver the event, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+	n.Retries = 1
+	if err := n.Send(context.Background(), NewTrackEvent(TrackStarted, song(), at)); err == nil {
+		t.Error("Expected an error once the retries ran out")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestSendRejected(t *testing.T) {
+	var calls atomic.Int32
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer rejecting.Close()
+	var delivered bool
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered = true
+	}))
+	defer working.Close()
+
+	n := newNotifier(t, Hook{Name: "gone", URL: rejecting.URL}, Hook{URL: working.URL})
+	err := n.Send(context.Background(), NewTrackEvent(TrackStarted, song(), at))
+	if err == nil || !strings.Contains(err.Error(), "webhook gone") {
+		t.Errorf("Expected an error naming the hook, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a rejection not to be retried, got %d attempts", calls.Load())
+	}
+	if !delivered {
+		t.Error("Expected the other hook to be delivered to")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{"": 0, "soon": 0, "-1": 0, "5": 5 * time.Second, "3600": maxRetryAfter}
+	for value, want := range tests {
+		if got := retryAfter(value); got != want {
+			t.Errorf("retryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestPlaylistChanges(t *testing.T) {
+	var seen Seen
+	if changed := seen.PlaylistChanges([]Playlist{{ID: "a", SnapshotID: "1"}, {ID: "b", SnapshotID: "1"}}); len(changed) != 0 {
+		t.Errorf("Expected the first check to report nothing, got %+v", changed)
+	}
+
+	changed := seen.PlaylistChanges([]Playlist{{ID: "a", SnapshotID: "2"}, {ID: "b", SnapshotID: "1"}, {ID: "c", SnapshotID: "1"}})
+	if len(changed) != 2 || changed[0].ID != "a" || changed[1].ID != "c" {
+		t.Errorf("Expected a changed and c new, got %+v", changed)
+	}
+	if changed := seen.PlaylistChanges([]Playlist{{ID: "a", SnapshotID: "2"}}); len(changed) != 0 {
+		t.Errorf("Expected no changes, got %+v", changed)
+	}
+	if _, ok := seen.Playlists["b"]; ok {
+		t.Error("Expected a deleted playlist to be forgotten")
+	}
+}
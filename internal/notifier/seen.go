@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding what the library looked like when
+// it was last checked for events
+const StoreName = "notifier"
+
+// Seen is what the library looked like when it was last checked, so only
+// what changed since is posted
+type Seen struct {
+	// SavedAt is when the newest saved track seen was saved
+	SavedAt time.Time `json:"saved_at"`
+	// Playlists maps the ID of each playlist to its snapshot ID. It is nil
+	// before the first check.
+	Playlists map[string]string `json:"playlists"`
+}
+
+// Load reads what was last seen, which is empty before the first check
+func Load(dir *state.Dir) (*Seen, error) {
+	var seen Seen
+	if err := dir.Read(StoreName, &seen); err != nil {
+		return nil, err
+	}
+	return &seen, nil
+}
+
+// Record stores what was just seen
+func Record(dir *state.Dir, seen *Seen) error {
+	var data Seen
+	return dir.Update(StoreName, &data, func() error {
+		data = *seen
+		return nil
+	})
+}
+
+// PlaylistChanges returns the playlists in current that are new or have a
+// new snapshot, and remembers current as seen. The first check only takes
+// note of the playlists, so starting up does not announce every one.
+func (s *Seen) PlaylistChanges(current []Playlist) []Playlist {
+	first := s.Playlists == nil
+	var changed []Playlist
+	snapshots := make(map[string]string, len(current))
+	for _, p := range current {
+		if snapshot, ok := s.Playlists[p.ID]; !first && (!ok || snapshot != p.SnapshotID) {
+			changed = append(changed, p)
+		}
+		snapshots[p.ID] = p.SnapshotID
+	}
+	s.Playlists = snapshots
+	return changed
+}
@@ -0,0 +1,251 @@
+package ordering
+
+import (
+	"math/rand"
+)
+
+// Keys identifies the attributes of an item that constraints are evaluated
+// against. Empty keys never conflict with anything.
+type Keys struct {
+	Artist string `json:"artist,omitempty"`
+	Album  string `json:"album,omitempty"`
+}
+
+// Constraints describes the rules a generated ordering should follow
+type Constraints struct {
+	// ArtistSpacing is the minimum number of other items between two items
+	// by the same artist. Zero disables the constraint.
+	ArtistSpacing int `json:"artist_spacing"`
+
+	// NoAdjacentAlbum prevents two items from the same album back to back
+	NoAdjacentAlbum bool `json:"no_adjacent_album"`
+}
+
+// Violation describes a position in an ordering that breaks a constraint
+type Violation struct {
+	Position int    `json:"position"`
+	Rule     string `json:"rule"`
+	Key      string `json:"key"`
+}
+
+// Violation rules
+const (
+	RuleArtistSpacing = "artist_spacing"
+	RuleAdjacentAlbum = "adjacent_album"
+)
+
+// Engine produces orderings that satisfy a set of constraints where possible.
+// Orderings are best-effort: when the input makes a constraint impossible to
+// satisfy (for example every item is by the same artist) the engine still
+// places every item, choosing the least-bad option at each step.
+type Engine struct {
+	constraints Constraints
+	rng         *rand.Rand
+}
+
+// NewEngine creates an ordering engine. The same seed and input always produce
+// the same ordering.
+func NewEngine(constraints Constraints, seed int64) *Engine {
+	if constraints.ArtistSpacing < 0 {
+		constraints.ArtistSpacing = 0
+	}
+
+	return &Engine{
+		constraints: constraints,
+		rng:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Constraints returns the constraints the engine enforces
+func (e *Engine) Constraints() Constraints {
+	return e.constraints
+}
+
+// Rand returns the engine's seeded random source so callers that make other
+// random choices stay reproducible under the same seed
+func (e *Engine) Rand() *rand.Rand {
+	return e.rng
+}
+
+// Penalty scores how badly appending next to placed would break the
+// constraints. Zero means the item is allowed; higher values are worse.
+func (e *Engine) Penalty(placed []Keys, next Keys) int {
+	penalty := 0
+
+	if e.constraints.ArtistSpacing > 0 && next.Artist != "" {
+		distance := artistDistance(placed, next.Artist, e.constraints.ArtistSpacing)
+		if distance <= e.constraints.ArtistSpacing {
+			// Closer repeats are worse; weight artist conflicts above album ones
+			penalty += (e.constraints.ArtistSpacing - distance + 1) * 2
+		}
+	}
+
+	if e.constraints.NoAdjacentAlbum && next.Album != "" && len(placed) > 0 {
+		if placed[len(placed)-1].Album == next.Album {
+			penalty++
+		}
+	}
+
+	return penalty
+}
+
+// Allowed reports whether next can be appended to placed without breaking
+// any constraint
+func (e *Engine) Allowed(placed []Keys, next Keys) bool {
+	return e.Penalty(placed, next) == 0
+}
+
+// Pick returns the index of the first candidate that can be appended to placed
+// without breaking a constraint, or the candidate with the lowest penalty if
+// none can. It returns -1 only when there are no candidates.
+func (e *Engine) Pick(placed []Keys, candidates []Keys) int {
+	best := -1
+	bestPenalty := 0
+
+	for i, candidate := range candidates {
+		penalty := e.Penalty(placed, candidate)
+		if penalty == 0 {
+			return i
+		}
+
+		if best < 0 || penalty < bestPenalty {
+			best = i
+			bestPenalty = penalty
+		}
+	}
+
+	return best
+}
+
+// Order returns a random permutation of indices into items that satisfies the
+// constraints where possible.
+func (e *Engine) Order(items []Keys) []int {
+	remaining := e.rng.Perm(len(items))
+	order := make([]int, 0, len(items))
+	placed := make([]Keys, 0, len(items))
+
+	artistCounts := make(map[string]int)
+	for _, item := range items {
+		if item.Artist != "" {
+			artistCounts[item.Artist]++
+		}
+	}
+
+	for len(remaining) > 0 {
+		pos := e.next(items, remaining, placed, artistCounts)
+
+		idx := remaining[pos]
+		remaining = append(remaining[:pos], remaining[pos+1:]...)
+
+		order = append(order, idx)
+		placed = append(placed, items[idx])
+		if items[idx].Artist != "" {
+			artistCounts[items[idx].Artist]--
+		}
+	}
+
+	return order
+}
+
+// next picks the position in remaining of the item to place next
+func (e *Engine) next(items []Keys, remaining []int, placed []Keys, artistCounts map[string]int) int {
+	// When the most frequent artist needs nearly all remaining slots to stay
+	// spaced out, random picks would paint the ordering into a corner. In that
+	// case always place the allowed item whose artist has the most tracks left.
+	tight := false
+	if spacing := e.constraints.ArtistSpacing; spacing > 0 {
+		maxCount := 0
+		for _, idx := range remaining {
+			if count := artistCounts[items[idx].Artist]; items[idx].Artist != "" && count > maxCount {
+				maxCount = count
+			}
+		}
+		tight = maxCount > 1 && (maxCount-1)*(spacing+1) >= len(remaining)-spacing-1
+	}
+
+	chosen := -1
+	chosenCount := 0
+	best := -1
+	bestPenalty := 0
+
+	for pos, idx := range remaining {
+		item := items[idx]
+		penalty := e.Penalty(placed, item)
+
+		if penalty > 0 {
+			if best < 0 || penalty < bestPenalty {
+				best = pos
+				bestPenalty = penalty
+			}
+			continue
+		}
+
+		if !tight {
+			// remaining is already in random order
+			return pos
+		}
+
+		if count := artistCounts[item.Artist]; chosen < 0 || count > chosenCount {
+			chosen = pos
+			chosenCount = count
+		}
+	}
+
+	if chosen >= 0 {
+		return chosen
+	}
+
+	return best
+}
+
+// Shuffle returns a new slice with the items of input reordered according to
+// the engine's constraints. keys extracts the constraint keys from an item.
+func Shuffle[T any](e *Engine, input []T, keys func(T) Keys) []T {
+	itemKeys := make([]Keys, len(input))
+	for i, item := range input {
+		itemKeys[i] = keys(item)
+	}
+
+	order := e.Order(itemKeys)
+	result := make([]T, len(order))
+	for i, idx := range order {
+		result[i] = input[idx]
+	}
+
+	return result
+}
+
+// Violations lists every position in sequence that breaks a constraint
+func (e *Engine) Violations(sequence []Keys) []Violation {
+	var violations []Violation
+
+	for i := range sequence {
+		placed := sequence[:i]
+		item := sequence[i]
+
+		if e.constraints.ArtistSpacing > 0 && item.Artist != "" {
+			if artistDistance(placed, item.Artist, e.constraints.ArtistSpacing) <= e.constraints.ArtistSpacing {
+				violations = append(violations, Violation{Position: i, Rule: RuleArtistSpacing, Key: item.Artist})
+			}
+		}
+
+		if e.constraints.NoAdjacentAlbum && item.Album != "" && i > 0 && sequence[i-1].Album == item.Album {
+			violations = append(violations, Violation{Position: i, Rule: RuleAdjacentAlbum, Key: item.Album})
+		}
+	}
+
+	return violations
+}
+
+// artistDistance returns how many positions back artist last appeared, looking
+// at most window positions back. Artists outside the window are reported as
+// window+1.
+func artistDistance(placed []Keys, artist string, window int) int {
+	for d := 1; d <= window && d <= len(placed); d++ {
+		if placed[len(placed)-d].Artist == artist {
+			return d
+		}
+	}
+
+	return window + 1
+}
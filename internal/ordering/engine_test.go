@@ -0,0 +1,187 @@
+package ordering
+
+import (
+	"fmt"
+	"testing"
+)
+
+func makeItems(artists map[string]int) []Keys {
+	var items []Keys
+	for artist, count := range artists {
+		for i := 0; i < count; i++ {
+			items = append(items, Keys{Artist: artist, Album: fmt.Sprintf("%s-album-%d", artist, i%2)})
+		}
+	}
+	return items
+}
+
+func ordered(items []Keys, order []int) []Keys {
+	result := make([]Keys, len(order))
+	for i, idx := range order {
+		result[i] = items[idx]
+	}
+	return result
+}
+
+func assertPermutation(t *testing.T, order []int, n int) {
+	t.Helper()
+
+	if len(order) != n {
+		t.Fatalf("Expected %d items in ordering, got %d", n, len(order))
+	}
+
+	seen := make(map[int]bool)
+	for _, idx := range order {
+		if idx < 0 || idx >= n || seen[idx] {
+			t.Fatalf("Ordering is not a permutation: %v", order)
+		}
+		seen[idx] = true
+	}
+}
+
+func TestEngine_EmptyAndSingle(t *testing.T) {
+	engine := NewEngine(Constraints{ArtistSpacing: 3, NoAdjacentAlbum: true}, 1)
+
+	if order := engine.Order(nil); len(order) != 0 {
+		t.Errorf("Expected empty ordering, got %v", order)
+	}
+
+	order := engine.Order([]Keys{{Artist: "a"}})
+	assertPermutation(t, order, 1)
+}
+
+func TestEngine_ArtistSpacing(t *testing.T) {
+	items := makeItems(map[string]int{"a": 5, "b": 5, "c": 5, "d": 5, "e": 5})
+	engine := NewEngine(Constraints{ArtistSpacing: 3}, 42)
+
+	order := engine.Order(items)
+	assertPermutation(t, order, len(items))
+
+	if violations := engine.Violations(ordered(items, order)); len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
+
+func TestEngine_TightlyFeasible(t *testing.T) {
+	// Artist "a" needs every other item to separate its tracks:
+	// a x x a x x a x x a
+	items := makeItems(map[string]int{"a": 4, "b": 2, "c": 2, "d": 2})
+	for seed := int64(0); seed < 200; seed++ {
+		engine := NewEngine(Constraints{ArtistSpacing: 2}, seed)
+
+		order := engine.Order(items)
+		assertPermutation(t, order, len(items))
+
+		if violations := engine.Violations(ordered(items, order)); len(violations) != 0 {
+			t.Fatalf("Seed %d: expected no violations, got %v", seed, violations)
+		}
+	}
+}
+
+func TestEngine_SingleArtist(t *testing.T) {
+	items := makeItems(map[string]int{"a": 10})
+	engine := NewEngine(Constraints{ArtistSpacing: 5, NoAdjacentAlbum: true}, 3)
+
+	order := engine.Order(items)
+	assertPermutation(t, order, len(items))
+}
+
+func TestEngine_DominantArtist(t *testing.T) {
+	items := makeItems(map[string]int{"a": 20, "b": 1, "c": 1})
+	engine := NewEngine(Constraints{ArtistSpacing: 1}, 5)
+
+	order := engine.Order(items)
+	assertPermutation(t, order, len(items))
+
+	// The two other tracks should each be used to break up a run of "a"
+	result := ordered(items, order)
+	if violations := engine.Violations(result); len(violations) > 17 {
+		t.Errorf("Expected at most 17 violations, got %d", len(violations))
+	}
+}
+
+func TestEngine_AdjacentAlbum(t *testing.T) {
+	var items []Keys
+	for i := 0; i < 6; i++ {
+		items = append(items, Keys{Album: "x"})
+		items = append(items, Keys{Album: "y"})
+	}
+
+	engine := NewEngine(Constraints{NoAdjacentAlbum: true}, 11)
+	order := engine.Order(items)
+	assertPermutation(t, order, len(items))
+
+	if violations := engine.Violations(ordered(items, order)); len(violations) != 0 {
+		t.Errorf("Expected alternating albums, got violations %v", violations)
+	}
+}
+
+func TestEngine_EmptyKeysNeverConflict(t *testing.T) {
+	items := make([]Keys, 10)
+	engine := NewEngine(Constraints{ArtistSpacing: 3, NoAdjacentAlbum: true}, 1)
+
+	order := engine.Order(items)
+	assertPermutation(t, order, len(items))
+
+	if violations := engine.Violations(ordered(items, order)); len(violations) != 0 {
+		t.Errorf("Expected no violations for empty keys, got %v", violations)
+	}
+}
+
+func TestEngine_Deterministic(t *testing.T) {
+	items := makeItems(map[string]int{"a": 3, "b": 3, "c": 3})
+
+	first := NewEngine(Constraints{ArtistSpacing: 1}, 99).Order(items)
+	second := NewEngine(Constraints{ArtistSpacing: 1}, 99).Order(items)
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("Expected identical orderings for identical seeds, differed at position %d", i)
+		}
+	}
+}
+
+func TestEngine_Pick(t *testing.T) {
+	engine := NewEngine(Constraints{ArtistSpacing: 2}, 1)
+	placed := []Keys{{Artist: "a"}, {Artist: "b"}}
+
+	if idx := engine.Pick(placed, []Keys{{Artist: "b"}, {Artist: "a"}, {Artist: "c"}}); idx != 2 {
+		t.Errorf("Expected candidate 2 to be picked, got %d", idx)
+	}
+
+	// With no allowed candidate the one whose artist is furthest back wins
+	if idx := engine.Pick(placed, []Keys{{Artist: "b"}, {Artist: "a"}}); idx != 1 {
+		t.Errorf("Expected candidate 1 to be picked, got %d", idx)
+	}
+
+	if idx := engine.Pick(placed, nil); idx != -1 {
+		t.Errorf("Expected -1 for no candidates, got %d", idx)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	type track struct {
+		uri    string
+		artist string
+	}
+
+	var input []track
+	for i := 0; i < 12; i++ {
+		input = append(input, track{uri: fmt.Sprintf("spotify:track:%d", i), artist: fmt.Sprintf("artist-%d", i%4)})
+	}
+
+	engine := NewEngine(Constraints{ArtistSpacing: 3}, 8)
+	result := Shuffle(engine, input, func(tr track) Keys { return Keys{Artist: tr.artist} })
+
+	if len(result) != len(input) {
+		t.Fatalf("Expected %d tracks, got %d", len(input), len(result))
+	}
+
+	for i := range result {
+		for j := i + 1; j <= i+3 && j < len(result); j++ {
+			if result[i].artist == result[j].artist {
+				t.Fatalf("Artist %s repeated at positions %d and %d", result[i].artist, i, j)
+			}
+		}
+	}
+}
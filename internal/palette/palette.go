@@ -0,0 +1,190 @@
+// Package palette picks the dominant colors of album artwork and turns them
+// into a theme for terminal output, so the now-playing display takes on the
+// colors of what is playing.
+package palette
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Role is the part of the display a color is used for
+type Role int
+
+const (
+	// Title is the track name
+	Title Role = iota
+	// Detail is secondary text such as the artist and album
+	Detail
+	// Bar is the progress bar
+	Bar
+)
+
+// Theme maps roles to SGR parameters, the part of an ANSI escape sequence
+// between "\033[" and "m". The zero Theme paints nothing.
+type Theme struct {
+	codes map[Role]string
+}
+
+// Static is the fallback theme, using the basic ANSI colors every color
+// terminal supports
+var Static = Theme{codes: map[Role]string{
+	Title:  "1;32",
+	Detail: "36",
+	Bar:    "32",
+}}
+
+// Paint wraps s in the theme's color for role
+func (t Theme) Paint(role Role, s string) string {
+	code, ok := t.codes[role]
+	if !ok || s == "" {
+		return s
+	}
+	return "\033[" + code + "m" + s + "\033[0m"
+}
+
+// FromImage builds a 24-bit color theme from artwork: the most vivid of its
+// dominant colors for the title and bar, the next distinct one for details.
+// Colors are lightened where needed to stay readable on a dark background.
+// It returns false when the artwork has no usable color, such as a black
+// and white photo, so the caller can fall back to Static.
+func FromImage(img image.Image) (Theme, bool) {
+	colors := Dominant(img, 6)
+
+	var vivid []color.RGBA
+	for _, c := range colors {
+		if saturation(c) >= 0.25 {
+			vivid = append(vivid, c)
+		}
+	}
+	if len(vivid) == 0 {
+		return Theme{}, false
+	}
+
+	sort.SliceStable(vivid, func(i, j int) bool {
+		return saturation(vivid[i]) > saturation(vivid[j])
+	})
+	accent := readable(vivid[0])
+
+	detail := accent
+	for _, c := range colors {
+		if distance(c, vivid[0]) > 80 {
+			detail = readable(c)
+			break
+		}
+	}
+
+	return Theme{codes: map[Role]string{
+		Title:  "1;" + trueColor(accent),
+		Detail: trueColor(detail),
+		Bar:    trueColor(accent),
+	}}, true
+}
+
+// Dominant returns up to n colors covering the most of img, most common
+// first. Pixels are grouped by their top four bits per channel and each
+// group contributes its average color, so similar shades count together.
+// Large images are sampled on a grid.
+func Dominant(img image.Image, n int) []color.RGBA {
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/64)
+
+	type bucket struct {
+		r, g, b, count int
+	}
+	buckets := make(map[int]*bucket)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if c.A < 0x80 {
+				continue
+			}
+			key := int(c.R>>4)<<8 | int(c.G>>4)<<4 | int(c.B>>4)
+			b := buckets[key]
+			if b == nil {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.r += int(c.R)
+			b.g += int(c.G)
+			b.b += int(c.B)
+			b.count++
+		}
+	}
+
+	sorted := make([]*bucket, 0, len(buckets))
+	for _, b := range buckets {
+		sorted = append(sorted, b)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].r+sorted[i].g+sorted[i].b > sorted[j].r+sorted[j].g+sorted[j].b
+	})
+
+	var colors []color.RGBA
+	for _, b := range sorted {
+		if len(colors) == n {
+			break
+		}
+		c := color.RGBA{R: uint8(b.r / b.count), G: uint8(b.g / b.count), B: uint8(b.b / b.count), A: 0xFF}
+
+		// Neighbouring buckets along a gradient would fill the palette
+		// with shades of one color
+		similar := false
+		for _, existing := range colors {
+			if distance(c, existing) < 40 {
+				similar = true
+				break
+			}
+		}
+		if !similar {
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
+
+// saturation is the HSV saturation of c, from 0 for greys to 1
+func saturation(c color.RGBA) float64 {
+	hi := max(c.R, c.G, c.B)
+	lo := min(c.R, c.G, c.B)
+	if hi == 0 {
+		return 0
+	}
+	return float64(hi-lo) / float64(hi)
+}
+
+// luminance approximates perceived brightness from 0 to 255
+func luminance(c color.RGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
+
+// readable mixes c with white until it stands out on a dark background
+func readable(c color.RGBA) color.RGBA {
+	for i := 0; i < 10 && luminance(c) < 110; i++ {
+		c.R += (0xFF - c.R) / 5
+		c.G += (0xFF - c.G) / 5
+		c.B += (0xFF - c.B) / 5
+	}
+	return c
+}
+
+// distance is the Manhattan distance between two colors
+func distance(a, b color.RGBA) int {
+	abs := func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+	return abs(int(a.R)-int(b.R)) + abs(int(a.G)-int(b.G)) + abs(int(a.B)-int(b.B))
+}
+
+func trueColor(c color.RGBA) string {
+	return fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B)
+}
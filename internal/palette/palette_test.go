@@ -0,0 +1,89 @@
+package palette
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// striped returns an image whose rows are split between colors in
+// proportion to their weights
+func striped(colors []color.RGBA, weights []int) image.Image {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 10, total))
+	y := 0
+	for i, c := range colors {
+		for end := y + weights[i]; y < end; y++ {
+			for x := 0; x < 10; x++ {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+	return img
+}
+
+func TestDominant(t *testing.T) {
+	navy := color.RGBA{R: 0x10, G: 0x20, B: 0x60, A: 0xFF}
+	orange := color.RGBA{R: 0xF0, G: 0x80, B: 0x10, A: 0xFF}
+	nearOrange := color.RGBA{R: 0xEE, G: 0x7E, B: 0x0E, A: 0xFF}
+
+	colors := Dominant(striped([]color.RGBA{navy, orange, nearOrange}, []int{50, 30, 20}), 5)
+	if len(colors) != 2 {
+		t.Fatalf("Expected near-identical shades to be skipped, got %v", colors)
+	}
+	if colors[0] != navy {
+		t.Errorf("Expected navy first, got %v", colors[0])
+	}
+	if distance(colors[1], orange) > 10 {
+		t.Errorf("Expected orange second, got %v", colors[1])
+	}
+}
+
+func TestFromImage(t *testing.T) {
+	black := color.RGBA{A: 0xFF}
+	red := color.RGBA{R: 0xC0, G: 0x10, B: 0x10, A: 0xFF}
+	teal := color.RGBA{R: 0x10, G: 0x80, B: 0x80, A: 0xFF}
+
+	theme, ok := FromImage(striped([]color.RGBA{black, teal, red}, []int{60, 30, 10}))
+	if !ok {
+		t.Fatal("Expected a theme from colorful artwork")
+	}
+
+	title := theme.Paint(Title, "Song")
+	if !strings.HasPrefix(title, "\033[1;38;2;") || !strings.HasSuffix(title, "Song\033[0m") {
+		t.Errorf("Expected a bold 24-bit title, got %q", title)
+	}
+	if theme.Paint(Detail, "Artist") == theme.Paint(Title, "Artist") {
+		t.Error("Expected details in a different style from the title")
+	}
+
+	if _, ok := FromImage(striped([]color.RGBA{black, {R: 0x80, G: 0x80, B: 0x80, A: 0xFF}}, []int{50, 50})); ok {
+		t.Error("Expected no theme from greyscale artwork")
+	}
+}
+
+func TestReadable(t *testing.T) {
+	dark := color.RGBA{R: 0x30, B: 0x10, A: 0xFF}
+	if luminance(readable(dark)) < 110 {
+		t.Errorf("Expected dark colors to be lightened, got %v", readable(dark))
+	}
+
+	light := color.RGBA{R: 0xF0, G: 0xD0, B: 0x20, A: 0xFF}
+	if readable(light) != light {
+		t.Error("Expected light colors to be left alone")
+	}
+}
+
+func TestPaint(t *testing.T) {
+	if got := (Theme{}).Paint(Title, "Song"); got != "Song" {
+		t.Errorf("Expected the zero theme to leave text alone, got %q", got)
+	}
+	if got := Static.Paint(Detail, "Artist"); got != "\033[36mArtist\033[0m" {
+		t.Errorf("Unexpected static detail %q", got)
+	}
+}
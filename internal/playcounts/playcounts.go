@@ -0,0 +1,233 @@
+// Package playcounts keeps how often and how recently each track was played,
+// and how often it was skipped, counted from the recently played history as
+// it is polled over time. Spotify only remembers the last 50 plays, so
+// counts start from the first time the history is recorded.
+package playcounts
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding play counts
+const StoreName = "playcounts"
+
+// SkipFraction is how much of a track must play for it not to count as
+// skipped
+const SkipFraction = 0.5
+
+// MinRatePlays is the fewest plays a skip rate is worth reporting for
+const MinRatePlays = 3
+
+// Play is one play of a track. PlayedAt is when the track stopped playing,
+// which is what the recently played list reports.
+type Play struct {
+	URI        string
+	PlayedAt   time.Time
+	DurationMs int
+	Artists    []string
+}
+
+// Count is the plays of one track
+type Count struct {
+	URI        string    `json:"uri" yaml:"uri"`
+	Artists    []string  `json:"artists,omitempty" yaml:"artists,omitempty"`
+	Plays      int       `json:"plays" yaml:"plays"`
+	Skips      int       `json:"skips" yaml:"skips"`
+	LastPlayed time.Time `json:"last_played" yaml:"last_played"`
+}
+
+// SkipRate is the share of plays that were skips
+func (c Count) SkipRate() float64 {
+	if c.Plays == 0 {
+		return 0
+	}
+	return float64(c.Skips) / float64(c.Plays)
+}
+
+// Rate is the skip rate of a track or artist
+type Rate struct {
+	Key   string  `json:"key" yaml:"key"`
+	Plays int     `json:"plays" yaml:"plays"`
+	Skips int     `json:"skips" yaml:"skips"`
+	Rate  float64 `json:"rate" yaml:"rate"`
+}
+
+type store struct {
+	// Cursor is the time of the newest play counted. Plays at or before it
+	// have been seen, which keeps overlapping polls from counting twice.
+	Cursor time.Time        `json:"cursor"`
+	Counts map[string]Count `json:"counts"`
+}
+
+// Record counts the plays newer than any recorded before and returns how
+// many were counted.
+//
+// A play is a skip when the previous play ended so shortly before it that
+// less than SkipFraction of the track can have played. Pauses only make a
+// play look longer, so they never turn one into a skip. Spotify leaves plays
+// under 30 seconds out of the list, so the quickest skips go unseen.
+func Record(dir *state.Dir, plays []Play) (int, error) {
+	plays = slices.Clone(plays)
+	sort.SliceStable(plays, func(i, j int) bool { return plays[i].PlayedAt.Before(plays[j].PlayedAt) })
+
+	counted := 0
+	var data store
+	err := dir.Update(StoreName, &data, func() error {
+		if data.Counts == nil {
+			data.Counts = make(map[string]Count)
+		}
+
+		for _, play := range plays {
+			if play.URI == "" || !play.PlayedAt.After(data.Cursor) {
+				continue
+			}
+			count := data.Counts[play.URI]
+			count.URI = play.URI
+			count.Plays++
+			if isSkip(data.Cursor, play) {
+				count.Skips++
+			}
+			if len(play.Artists) > 0 {
+				count.Artists = play.Artists
+			}
+			count.LastPlayed = play.PlayedAt.UTC()
+			data.Counts[play.URI] = count
+			data.Cursor = play.PlayedAt.UTC()
+			counted++
+		}
+		return nil
+	})
+	return counted, err
+}
+
+// isSkip reports whether play was cut short, given when the play before it
+// ended
+func isSkip(previous time.Time, play Play) bool {
+	if previous.IsZero() || play.DurationMs <= 0 {
+		return false
+	}
+	listened := play.PlayedAt.Sub(previous)
+	return listened < time.Duration(float64(play.DurationMs)*SkipFraction)*time.Millisecond
+}
+
+// Cursor returns the time of the newest play counted, or the zero time
+// when nothing has been recorded yet
+func Cursor(dir *state.Dir) (time.Time, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return time.Time{}, err
+	}
+	return data.Cursor, nil
+}
+
+// All returns the play counts by track URI
+func All(dir *state.Dir) (map[string]Count, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	if data.Counts == nil {
+		data.Counts = make(map[string]Count)
+	}
+	return data.Counts, nil
+}
+
+// Top returns the most played tracks first, the most recently played first
+// among equals. A limit of zero returns every track.
+func Top(counts map[string]Count, limit int) []Count {
+	top := make([]Count, 0, len(counts))
+	for _, count := range counts {
+		top = append(top, count)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Plays != top[j].Plays {
+			return top[i].Plays > top[j].Plays
+		}
+		if !top[i].LastPlayed.Equal(top[j].LastPlayed) {
+			return top[i].LastPlayed.After(top[j].LastPlayed)
+		}
+		return top[i].URI < top[j].URI
+	})
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// Forgotten returns the tracks played at least minPlays times but not since
+// before, most played first and the longest forgotten first among equals
+func Forgotten(counts map[string]Count, before time.Time, minPlays int) []Count {
+	var forgotten []Count
+	for _, count := range counts {
+		if count.Plays >= minPlays && count.LastPlayed.Before(before) {
+			forgotten = append(forgotten, count)
+		}
+	}
+	sort.Slice(forgotten, func(i, j int) bool {
+		if forgotten[i].Plays != forgotten[j].Plays {
+			return forgotten[i].Plays > forgotten[j].Plays
+		}
+		if !forgotten[i].LastPlayed.Equal(forgotten[j].LastPlayed) {
+			return forgotten[i].LastPlayed.Before(forgotten[j].LastPlayed)
+		}
+		return forgotten[i].URI < forgotten[j].URI
+	})
+	return forgotten
+}
+
+// TrackRates returns the skip rates of tracks played at least minPlays
+// times, most skipped first
+func TrackRates(counts map[string]Count, minPlays int) []Rate {
+	var rates []Rate
+	for uri, count := range counts {
+		if count.Plays >= minPlays {
+			rates = append(rates, Rate{Key: uri, Plays: count.Plays, Skips: count.Skips, Rate: count.SkipRate()})
+		}
+	}
+	sortRates(rates)
+	return rates
+}
+
+// ArtistRates returns the skip rates of artists, over every play of their
+// tracks, for artists played at least minPlays times. A track counts toward
+// each of its artists.
+func ArtistRates(counts map[string]Count, minPlays int) []Rate {
+	byArtist := make(map[string]*Rate)
+	for _, count := range counts {
+		for _, artist := range count.Artists {
+			rate, ok := byArtist[artist]
+			if !ok {
+				rate = &Rate{Key: artist}
+				byArtist[artist] = rate
+			}
+			rate.Plays += count.Plays
+			rate.Skips += count.Skips
+		}
+	}
+
+	var rates []Rate
+	for _, rate := range byArtist {
+		if rate.Plays >= minPlays {
+			rate.Rate = float64(rate.Skips) / float64(rate.Plays)
+			rates = append(rates, *rate)
+		}
+	}
+	sortRates(rates)
+	return rates
+}
+
+func sortRates(rates []Rate) {
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Rate != rates[j].Rate {
+			return rates[i].Rate > rates[j].Rate
+		}
+		if rates[i].Plays != rates[j].Plays {
+			return rates[i].Plays > rates[j].Plays
+		}
+		return rates[i].Key < rates[j].Key
+	})
+}
@@ -0,0 +1,115 @@
+package playcounts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRecord(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	const (
+		a = "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"
+		b = "spotify:track:0VjIjW4GlUZAMYd2vXMi3b"
+	)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	counted, err := Record(dir, []Play{{URI: a, PlayedAt: at(8)}, {URI: b, PlayedAt: at(4)}, {URI: a, PlayedAt: at(0)}})
+	if err != nil || counted != 3 {
+		t.Fatalf("Expected 3 plays counted, got %d (%v)", counted, err)
+	}
+
+	// The next poll overlaps the first
+	counted, err = Record(dir, []Play{{URI: b, PlayedAt: at(12)}, {URI: a, PlayedAt: at(8)}, {URI: b, PlayedAt: at(4)}})
+	if err != nil || counted != 1 {
+		t.Fatalf("Expected only the new play to be counted, got %d (%v)", counted, err)
+	}
+
+	counts, err := All(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts[a].Plays != 2 || !counts[a].LastPlayed.Equal(at(8)) {
+		t.Errorf("Unexpected count for a: %+v", counts[a])
+	}
+	if counts[b].Plays != 2 || !counts[b].LastPlayed.Equal(at(12)) {
+		t.Errorf("Unexpected count for b: %+v", counts[b])
+	}
+	if cursor, _ := Cursor(dir); !cursor.Equal(at(12)) {
+		t.Errorf("Expected the cursor at the newest play, got %v", cursor)
+	}
+
+	top := Top(counts, 1)
+	if len(top) != 1 || top[0].URI != b {
+		t.Errorf("Expected the most recently played of equals first, got %+v", top)
+	}
+}
+
+func TestSkips(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	const minutes4 = 4 * 60 * 1000
+
+	plays := []Play{
+		{URI: "spotify:track:first", PlayedAt: at(0), DurationMs: minutes4, Artists: []string{"A"}},
+		// Played in full
+		{URI: "spotify:track:full", PlayedAt: at(240), DurationMs: minutes4, Artists: []string{"A"}},
+		// Stopped after 40 seconds
+		{URI: "spotify:track:skipped", PlayedAt: at(280), DurationMs: minutes4, Artists: []string{"B"}},
+		// Paused along the way, which never makes a skip
+		{URI: "spotify:track:paused", PlayedAt: at(2000), DurationMs: minutes4, Artists: []string{"B"}},
+	}
+	if _, err := Record(dir, plays); err != nil {
+		t.Fatal(err)
+	}
+	// The next poll starts where the last one ended
+	if _, err := Record(dir, []Play{{URI: "spotify:track:skipped", PlayedAt: at(2060), DurationMs: minutes4, Artists: []string{"B"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := All(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for uri, skips := range map[string]int{"spotify:track:first": 0, "spotify:track:full": 0, "spotify:track:skipped": 2, "spotify:track:paused": 0} {
+		if counts[uri].Skips != skips {
+			t.Errorf("Expected %d skips of %s, got %+v", skips, uri, counts[uri])
+		}
+	}
+
+	tracks := TrackRates(counts, 2)
+	if len(tracks) != 1 || tracks[0].Key != "spotify:track:skipped" || tracks[0].Rate != 1 {
+		t.Errorf("Unexpected track rates %+v", tracks)
+	}
+	artists := ArtistRates(counts, 1)
+	if len(artists) != 2 || artists[0].Key != "B" || artists[0].Plays != 3 || artists[0].Skips != 2 {
+		t.Errorf("Unexpected artist rates %+v", artists)
+	}
+}
+
+func TestForgotten(t *testing.T) {
+	now := time.Now()
+	counts := map[string]Count{
+		"spotify:track:recent":   {URI: "spotify:track:recent", Plays: 40, LastPlayed: now.AddDate(0, 0, -3)},
+		"spotify:track:favorite": {URI: "spotify:track:favorite", Plays: 30, LastPlayed: now.AddDate(-1, 0, 0)},
+		"spotify:track:older":    {URI: "spotify:track:older", Plays: 30, LastPlayed: now.AddDate(-2, 0, 0)},
+		"spotify:track:fewplays": {URI: "spotify:track:fewplays", Plays: 2, LastPlayed: now.AddDate(-1, 0, 0)},
+	}
+
+	forgotten := Forgotten(counts, now.AddDate(0, -6, 0), 5)
+	if len(forgotten) != 2 || forgotten[0].URI != "spotify:track:older" || forgotten[1].URI != "spotify:track:favorite" {
+		t.Errorf("Unexpected forgotten tracks %+v", forgotten)
+	}
+}
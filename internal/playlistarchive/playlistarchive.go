@@ -0,0 +1,90 @@
+// Package playlistarchive names dated copies of playlists that Spotify
+// rewrites on a schedule, such as Discover Weekly, and remembers which
+// versions have been copied so a scheduled run never archives one twice.
+package playlistarchive
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding archived versions
+const StoreName = "playlist_archive"
+
+// DefaultTemplate names an archive after its playlist and the day it was made
+const DefaultTemplate = "{{name}} {{date}}"
+
+// Render fills in a name template. Templates may use {{name}}, the name of
+// the playlist archived, and {{date}} (2024-06-03), {{week}} (2024-W23),
+// {{month}} (2024-06) and {{year}} of the time t.
+func Render(text, name string, t time.Time) (string, error) {
+	year, week := t.ISOWeek()
+	funcs := template.FuncMap{
+		"name":  func() string { return name },
+		"date":  func() string { return t.Format("2006-01-02") },
+		"week":  func() string { return fmt.Sprintf("%d-W%02d", year, week) },
+		"month": func() string { return t.Format("2006-01") },
+		"year":  func() string { return t.Format("2006") },
+	}
+
+	tmpl, err := template.New("name").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid name template %q: %w", text, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		return "", fmt.Errorf("invalid name template %q: %w", text, err)
+	}
+
+	rendered := strings.TrimSpace(out.String())
+	if rendered == "" {
+		return "", fmt.Errorf("name template %q gives an empty name", text)
+	}
+	return rendered, nil
+}
+
+// Entry is one archived version of a playlist
+type Entry struct {
+	SnapshotID string    `json:"snapshot_id"`
+	PlaylistID string    `json:"playlist_id"`
+	Name       string    `json:"name"`
+	Tracks     int       `json:"tracks"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+type store struct {
+	// Archives are the archived versions of each source playlist, oldest first
+	Archives map[string][]Entry `json:"archives"`
+}
+
+// Find returns the archive of a version of source, by its snapshot ID, or
+// one with the given name, whichever was made
+func Find(dir *state.Dir, source, snapshotID, name string) (*Entry, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	entries := data.Archives[source]
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].SnapshotID == snapshotID || entries[i].Name == name {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Record saves an archived version of source
+func Record(dir *state.Dir, source string, entry Entry) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		if data.Archives == nil {
+			data.Archives = make(map[string][]Entry)
+		}
+		data.Archives[source] = append(data.Archives[source], entry)
+		return nil
+	})
+}
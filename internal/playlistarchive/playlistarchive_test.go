@@ -0,0 +1,68 @@
+package playlistarchive
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRender(t *testing.T) {
+	monday := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{DefaultTemplate, "Discover Weekly 2024-06-03"},
+		{"Discover {{date}}", "Discover 2024-06-03"},
+		{"{{name}} {{week}}", "Discover Weekly 2024-W23"},
+		{"Radar {{month}}", "Radar 2024-06"},
+		{"{{year}} archive", "2024 archive"},
+	}
+	for _, tt := range tests {
+		got, err := Render(tt.template, "Discover Weekly", monday)
+		if err != nil {
+			t.Errorf("Render(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"{{date", "{{unknown}}", "  "} {
+		if _, err := Render(bad, "x", monday); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestFindAndRecord(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	if entry, err := Find(dir, "dw", "s1", "Discover 2024-06-03"); err != nil || entry != nil {
+		t.Fatalf("Expected nothing archived yet, got %v, %v", entry, err)
+	}
+
+	if err := Record(dir, "dw", Entry{SnapshotID: "s1", PlaylistID: "copy1", Name: "Discover 2024-06-03", ArchivedAt: time.Now()}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if entry, _ := Find(dir, "dw", "s1", "Discover 2024-06-04"); entry == nil || entry.PlaylistID != "copy1" {
+		t.Errorf("Expected the same version to be found by snapshot ID, got %v", entry)
+	}
+	if entry, _ := Find(dir, "dw", "s2", "Discover 2024-06-03"); entry == nil {
+		t.Error("Expected an archive with the same name to be found")
+	}
+	if entry, _ := Find(dir, "dw", "s2", "Discover 2024-06-10"); entry != nil {
+		t.Errorf("Expected a new version to need archiving, got %v", entry)
+	}
+	if entry, _ := Find(dir, "rr", "s1", "Discover 2024-06-03"); entry != nil {
+		t.Errorf("Expected archives to be kept per source, got %v", entry)
+	}
+}
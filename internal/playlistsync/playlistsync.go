@@ -0,0 +1,256 @@
+// Package playlistsync works out the smallest set of playlist edits that
+// make one playlist match another, and remembers the snapshot IDs of the
+// last sync so a pair that has not changed since is not fetched again.
+package playlistsync
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the last sync of each pair
+const StoreName = "playlistsync"
+
+// BatchSize is the most tracks the API adds or removes in one request
+const BatchSize = 100
+
+// Item is a track in the target playlist
+type Item struct {
+	URI string
+	// Local files cannot be added or removed through the API, so they are
+	// left where they are
+	Local bool
+}
+
+// Removal is a track to remove, at its position before any edit
+type Removal struct {
+	URI      string
+	Position int
+}
+
+// Insertion is a run of tracks to insert at a position, applied in order
+// after every removal
+type Insertion struct {
+	Position int
+	URIs     []string
+}
+
+// Plan is the edits that make the target match the source
+type Plan struct {
+	Remove []Removal
+	Insert []Insertion
+}
+
+// Empty reports whether the target already matches
+func (p Plan) Empty() bool {
+	return len(p.Remove) == 0 && len(p.Insert) == 0
+}
+
+// Added is the number of tracks the plan adds
+func (p Plan) Added() int {
+	n := 0
+	for _, insertion := range p.Insert {
+		n += len(insertion.URIs)
+	}
+	return n
+}
+
+// Requests is the number of API requests the plan takes
+func (p Plan) Requests() int {
+	return (len(p.Remove)+BatchSize-1)/BatchSize + len(p.Insert)
+}
+
+// Compute plans the edits that make target hold the tracks of source in
+// the same order. Tracks already in target in the right relative order are
+// kept, so their added dates survive; the rest are removed and the missing
+// ones inserted where they belong. With appendOnly, nothing is removed or
+// moved and tracks missing from target are appended in source order.
+func Compute(source []string, target []Item, appendOnly bool) Plan {
+	if appendOnly {
+		return computeAppend(source, target)
+	}
+
+	// Pair each target track with an occurrence of it in source, so a track
+	// listed twice in source may be listed twice in target
+	occurrences := make(map[string][]int)
+	for i, uri := range source {
+		occurrences[uri] = append(occurrences[uri], i)
+	}
+	mapped := make([]int, len(target))
+	for j, item := range target {
+		mapped[j] = -1
+		if item.Local {
+			continue
+		}
+		if queue := occurrences[item.URI]; len(queue) > 0 {
+			mapped[j] = queue[0]
+			occurrences[item.URI] = queue[1:]
+		}
+	}
+
+	keep := increasingRun(mapped)
+
+	var plan Plan
+	// current is the target after the removals, as source indices, with -1
+	// for local files
+	var current []int
+	kept := make(map[int]bool)
+	for j, item := range target {
+		switch {
+		case item.Local:
+			current = append(current, -1)
+		case keep[j]:
+			current = append(current, mapped[j])
+			kept[mapped[j]] = true
+		default:
+			plan.Remove = append(plan.Remove, Removal{URI: item.URI, Position: j})
+		}
+	}
+
+	for start := 0; start < len(source); {
+		if kept[start] {
+			start++
+			continue
+		}
+		end := start
+		for end < len(source) && !kept[end] {
+			end++
+		}
+
+		// The run goes right after the kept track before it in source
+		position := 0
+		for p, index := range current {
+			if index >= 0 && index < start {
+				position = p + 1
+			}
+		}
+		run := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			run = append(run, i)
+		}
+		current = append(current[:position], append(run, current[position:]...)...)
+
+		for chunk := start; chunk < end; chunk += BatchSize {
+			chunkEnd := min(chunk+BatchSize, end)
+			plan.Insert = append(plan.Insert, Insertion{
+				Position: position + chunk - start,
+				URIs:     append([]string(nil), source[chunk:chunkEnd]...),
+			})
+		}
+		start = end
+	}
+	return plan
+}
+
+func computeAppend(source []string, target []Item) Plan {
+	present := make(map[string]int)
+	for _, item := range target {
+		if !item.Local {
+			present[item.URI]++
+		}
+	}
+
+	var missing []string
+	for _, uri := range source {
+		if present[uri] > 0 {
+			present[uri]--
+			continue
+		}
+		missing = append(missing, uri)
+	}
+
+	var plan Plan
+	for start := 0; start < len(missing); start += BatchSize {
+		end := min(start+BatchSize, len(missing))
+		plan.Insert = append(plan.Insert, Insertion{Position: len(target) + start, URIs: missing[start:end]})
+	}
+	return plan
+}
+
+// increasingRun returns the positions of the longest strictly increasing
+// subsequence of the non-negative values, which are the tracks already in
+// source order
+func increasingRun(values []int) map[int]bool {
+	// tails[k] is the position ending the best run of length k+1
+	var tails []int
+	previous := make([]int, len(values))
+	for j, value := range values {
+		if value < 0 {
+			continue
+		}
+		k := sort.Search(len(tails), func(k int) bool { return values[tails[k]] >= value })
+		previous[j] = -1
+		if k > 0 {
+			previous[j] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, j)
+		} else {
+			tails[k] = j
+		}
+	}
+
+	keep := make(map[int]bool)
+	if len(tails) == 0 {
+		return keep
+	}
+	for j := tails[len(tails)-1]; j >= 0; j = previous[j] {
+		keep[j] = true
+	}
+	return keep
+}
+
+// Pair is the last sync of a source playlist into a target
+type Pair struct {
+	Source         string    `json:"source"`
+	Target         string    `json:"target"`
+	SourceSnapshot string    `json:"source_snapshot"`
+	TargetSnapshot string    `json:"target_snapshot"`
+	AppendOnly     bool      `json:"append_only"`
+	SyncedAt       time.Time `json:"synced_at"`
+}
+
+// InSync reports whether the pair is unchanged since this sync, so another
+// sync in the given mode would do nothing. A full sync also satisfies an
+// append-only one.
+func (p *Pair) InSync(sourceSnapshot, targetSnapshot string, appendOnly bool) bool {
+	return p != nil && p.SourceSnapshot != "" &&
+		p.SourceSnapshot == sourceSnapshot && p.TargetSnapshot == targetSnapshot &&
+		(!p.AppendOnly || appendOnly)
+}
+
+type store struct {
+	Pairs map[string]Pair `json:"pairs"`
+}
+
+func pairKey(source, target string) string {
+	return source + ">" + target
+}
+
+// Last returns the last sync of source into target, or nil if there has
+// been none
+func Last(dir *state.Dir, source, target string) (*Pair, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	pair, ok := data.Pairs[pairKey(source, target)]
+	if !ok {
+		return nil, nil
+	}
+	return &pair, nil
+}
+
+// Record saves a sync of a pair
+func Record(dir *state.Dir, pair Pair) error {
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		if data.Pairs == nil {
+			data.Pairs = make(map[string]Pair)
+		}
+		data.Pairs[pairKey(pair.Source, pair.Target)] = pair
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package playlistsync
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// apply runs a plan the way the API would: removals against the original
+// positions, then insertions in order
+func apply(target []Item, plan Plan) []Item {
+	removed := make(map[int]bool)
+	for _, removal := range plan.Remove {
+		if target[removal.Position].URI != removal.URI {
+			panic(fmt.Sprintf("removal of %s at %d finds %s", removal.URI, removal.Position, target[removal.Position].URI))
+		}
+		removed[removal.Position] = true
+	}
+	var result []Item
+	for j, item := range target {
+		if !removed[j] {
+			result = append(result, item)
+		}
+	}
+	for _, insertion := range plan.Insert {
+		items := make([]Item, len(insertion.URIs))
+		for i, uri := range insertion.URIs {
+			items[i] = Item{URI: uri}
+		}
+		result = append(result[:insertion.Position], append(items, result[insertion.Position:]...)...)
+	}
+	return result
+}
+
+func items(uris ...string) []Item {
+	result := make([]Item, len(uris))
+	for i, uri := range uris {
+		result[i] = Item{URI: uri}
+	}
+	return result
+}
+
+func uris(items []Item) []string {
+	result := []string{}
+	for _, item := range items {
+		if !item.Local {
+			result = append(result, item.URI)
+		}
+	}
+	return result
+}
+
+func TestCompute(t *testing.T) {
+	long := make([]string, 250)
+	for i := range long {
+		long[i] = fmt.Sprintf("t%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		source  []string
+		target  []Item
+		removes int
+		adds    int
+	}{
+		{"in sync", []string{"a", "b", "c"}, items("a", "b", "c"), 0, 0},
+		{"empty target", []string{"a", "b"}, nil, 0, 2},
+		{"empty source", nil, items("a", "b"), 2, 0},
+		{"add and remove", []string{"a", "b", "c", "d"}, items("x", "b", "d", "y"), 2, 2},
+		{"reordered", []string{"a", "b", "c", "d"}, items("d", "a", "b", "c"), 1, 1},
+		{"duplicates", []string{"a", "b", "a"}, items("a", "a", "b"), 1, 1},
+		{"long", long, items("t10", "t200"), 0, 248},
+		{"local files stay", []string{"a", "b"}, []Item{{URI: "a"}, {URI: "spotify:local:x", Local: true}, {URI: "c"}}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := Compute(tt.source, tt.target, false)
+			if len(plan.Remove) != tt.removes || plan.Added() != tt.adds {
+				t.Errorf("Expected %d removals and %d additions, got %d and %d", tt.removes, tt.adds, len(plan.Remove), plan.Added())
+			}
+			for _, insertion := range plan.Insert {
+				if len(insertion.URIs) > BatchSize {
+					t.Errorf("Insertion of %d tracks exceeds the batch size", len(insertion.URIs))
+				}
+			}
+			want := tt.source
+			if want == nil {
+				want = []string{}
+			}
+			if got := uris(apply(tt.target, plan)); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v after sync, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestComputeAppendOnly(t *testing.T) {
+	target := items("x", "b", "a")
+	plan := Compute([]string{"a", "b", "c", "d"}, target, true)
+	if len(plan.Remove) != 0 {
+		t.Errorf("Expected no removals in append-only mode, got %v", plan.Remove)
+	}
+	if got, want := uris(apply(target, plan)), []string{"x", "b", "a", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	if pair, err := Last(dir, "src", "dst"); err != nil || pair != nil {
+		t.Fatalf("Expected no sync yet, got %v, %v", pair, err)
+	}
+
+	if err := Record(dir, Pair{Source: "src", Target: "dst", SourceSnapshot: "s1", TargetSnapshot: "t1", AppendOnly: true, SyncedAt: time.Now()}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	pair, err := Last(dir, "src", "dst")
+	if err != nil || pair == nil {
+		t.Fatalf("Expected a recorded sync, got %v, %v", pair, err)
+	}
+	if !pair.InSync("s1", "t1", true) {
+		t.Error("Expected unchanged pair to be in sync for an append-only sync")
+	}
+	if pair.InSync("s1", "t1", false) {
+		t.Error("Expected an append-only sync not to satisfy a full sync")
+	}
+	if pair.InSync("s2", "t1", true) {
+		t.Error("Expected a changed source to need a sync")
+	}
+}
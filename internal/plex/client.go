@@ -0,0 +1,305 @@
+// Package plex is a client for the parts of the Plex Media Server API that
+// deal with music libraries and audio playlists.
+package plex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientName identifies spotify-cli to the server
+const ClientName = "spotify-cli"
+
+// itemBatch is how many tracks are added to a playlist per request. Items
+// are passed in the query string, which servers and proxies cap at a few
+// kilobytes.
+const itemBatch = 200
+
+// Client represents a Plex Media Server API client
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	machineID string
+}
+
+// Config holds Plex client configuration
+type Config struct {
+	BaseURL string
+	Token   string
+	Timeout time.Duration
+}
+
+// Section is a library on the server
+type Section struct {
+	Key   string `json:"key"`
+	Title string `json:"title"`
+	// Type is "artist" for music libraries
+	Type string `json:"type"`
+}
+
+// Track is a track in a music library
+type Track struct {
+	RatingKey string `json:"ratingKey"`
+	Title     string `json:"title"`
+	// GrandparentTitle is the album artist and ParentTitle the album.
+	// OriginalTitle is the track's own artist when it differs from the
+	// album's, as on compilations.
+	GrandparentTitle string `json:"grandparentTitle"`
+	ParentTitle      string `json:"parentTitle"`
+	OriginalTitle    string `json:"originalTitle"`
+	// Duration is in milliseconds
+	Duration int `json:"duration"`
+}
+
+// Artist returns the track's own artist
+func (t Track) Artist() string {
+	if t.OriginalTitle != "" {
+		return t.OriginalTitle
+	}
+	return t.GrandparentTitle
+}
+
+// Playlist is a playlist on the server
+type Playlist struct {
+	RatingKey    string `json:"ratingKey"`
+	Title        string `json:"title"`
+	PlaylistType string `json:"playlistType"`
+	Smart        bool   `json:"smart"`
+	LeafCount    int    `json:"leafCount"`
+}
+
+// mediaContainer is the body of every reply, wrapped in "MediaContainer"
+type mediaContainer struct {
+	MachineIdentifier string          `json:"machineIdentifier"`
+	Directory         []Section       `json:"Directory"`
+	Metadata          json.RawMessage `json:"Metadata"`
+}
+
+// NewClient creates a new Plex API client
+func NewClient(config Config) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &Client{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		token:   config.Token,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// do makes a request to the server and decodes the media container it
+// replies with. Some calls reply with no body at all, which gives an empty
+// container.
+func (c *Client) do(method, path string, params url.Values) (*mediaContainer, error) {
+	endpoint := c.baseURL + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Plex-Token", c.token)
+	req.Header.Set("X-Plex-Product", ClientName)
+	req.Header.Set("X-Plex-Client-Identifier", ClientName)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, fmt.Errorf("the server rejected the token (status %d)", resp.StatusCode)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		MediaContainer mediaContainer `json:"MediaContainer"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &body.MediaContainer, nil
+}
+
+// metadata decodes the items of a container
+func metadata[T any](container *mediaContainer) ([]T, error) {
+	if len(container.Metadata) == 0 {
+		return nil, nil
+	}
+	var items []T
+	if err := json.Unmarshal(container.Metadata, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return items, nil
+}
+
+// MachineIdentifier returns the server's ID, which names it in the URIs of
+// playlist items. It is fetched once.
+func (c *Client) MachineIdentifier() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.machineID != "" {
+		return c.machineID, nil
+	}
+
+	container, err := c.do(http.MethodGet, "/identity", nil)
+	if err != nil {
+		return "", err
+	}
+	if container.MachineIdentifier == "" {
+		return "", fmt.Errorf("server did not report its machine identifier")
+	}
+	c.machineID = container.MachineIdentifier
+	return c.machineID, nil
+}
+
+// MusicSections returns the music libraries on the server
+func (c *Client) MusicSections() ([]Section, error) {
+	container, err := c.do(http.MethodGet, "/library/sections", nil)
+	if err != nil {
+		return nil, err
+	}
+	var sections []Section
+	for _, section := range container.Directory {
+		if section.Type == "artist" {
+			sections = append(sections, section)
+		}
+	}
+	return sections, nil
+}
+
+// FindSection returns the music library whose title or key is ref. An
+// empty ref picks the only music library, and is an error when there are
+// several.
+func (c *Client) FindSection(ref string) (*Section, error) {
+	sections, err := c.MusicSections()
+	if err != nil {
+		return nil, err
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("the server has no music library")
+	}
+
+	if ref == "" {
+		if len(sections) > 1 {
+			return nil, fmt.Errorf("the server has %d music libraries (%s); choose one", len(sections), sectionTitles(sections))
+		}
+		return &sections[0], nil
+	}
+	for i, section := range sections {
+		if section.Key == ref || strings.EqualFold(section.Title, ref) {
+			return &sections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no music library %q; the server has %s", ref, sectionTitles(sections))
+}
+
+func sectionTitles(sections []Section) string {
+	titles := make([]string, len(sections))
+	for i, section := range sections {
+		titles[i] = fmt.Sprintf("%q", section.Title)
+	}
+	return strings.Join(titles, ", ")
+}
+
+// SearchTracks returns the tracks in a music library whose title contains
+// title, ignoring case
+func (c *Client) SearchTracks(sectionKey, title string) ([]Track, error) {
+	container, err := c.do(http.MethodGet, "/library/sections/"+url.PathEscape(sectionKey)+"/all", url.Values{
+		// 10 is the type of tracks
+		"type":  {"10"},
+		"title": {title},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadata[Track](container)
+}
+
+// Playlists returns the audio playlists on the server
+func (c *Client) Playlists() ([]Playlist, error) {
+	container, err := c.do(http.MethodGet, "/playlists", url.Values{"playlistType": {"audio"}})
+	if err != nil {
+		return nil, err
+	}
+	return metadata[Playlist](container)
+}
+
+// CreatePlaylist creates an audio playlist holding the tracks, in order
+func (c *Client) CreatePlaylist(title string, ratingKeys []string) (*Playlist, error) {
+	first := ratingKeys[:min(len(ratingKeys), itemBatch)]
+	uri, err := c.itemsURI(first)
+	if err != nil {
+		return nil, err
+	}
+	container, err := c.do(http.MethodPost, "/playlists", url.Values{
+		"type":  {"audio"},
+		"title": {title},
+		"smart": {"0"},
+		"uri":   {uri},
+	})
+	if err != nil {
+		return nil, err
+	}
+	playlists, err := metadata[Playlist](container)
+	if err != nil {
+		return nil, err
+	}
+	if len(playlists) == 0 {
+		return nil, fmt.Errorf("server did not return the playlist it created")
+	}
+
+	playlist := &playlists[0]
+	if err := c.addItems(playlist.RatingKey, ratingKeys[len(first):]); err != nil {
+		return nil, err
+	}
+	return playlist, nil
+}
+
+// ReplacePlaylist sets the tracks of an existing playlist, in order
+func (c *Client) ReplacePlaylist(id string, ratingKeys []string) error {
+	if _, err := c.do(http.MethodDelete, "/playlists/"+url.PathEscape(id)+"/items", nil); err != nil {
+		return err
+	}
+	return c.addItems(id, ratingKeys)
+}
+
+// addItems appends tracks to a playlist, a batch at a time
+func (c *Client) addItems(id string, ratingKeys []string) error {
+	for start := 0; start < len(ratingKeys); start += itemBatch {
+		uri, err := c.itemsURI(ratingKeys[start:min(start+itemBatch, len(ratingKeys))])
+		if err != nil {
+			return err
+		}
+		if _, err := c.do(http.MethodPut, "/playlists/"+url.PathEscape(id)+"/items", url.Values{"uri": {uri}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemsURI is how playlist calls refer to tracks of this server
+func (c *Client) itemsURI(ratingKeys []string) (string, error) {
+	machineID, err := c.MachineIdentifier()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("server://%s/com.plexapp.plugins.library/library/metadata/%s", machineID, strings.Join(ratingKeys, ",")), nil
+}
@@ -0,0 +1,131 @@
+package plex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Plex-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("Accept") != "application/json" {
+			t.Errorf("Expected a request for JSON, got Accept %q", r.Header.Get("Accept"))
+		}
+		if r.URL.Path == "/identity" {
+			w.Write([]byte(`{"MediaContainer":{"machineIdentifier":"machine"}}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(Config{BaseURL: server.URL + "/", Token: "token"})
+}
+
+func TestFindSection(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"MediaContainer":{"Directory":[
+			{"key":"1","title":"Movies","type":"movie"},
+			{"key":"3","title":"Music","type":"artist"},
+			{"key":"4","title":"Audiobooks","type":"artist"}
+		]}}`))
+	})
+
+	section, err := client.FindSection("music")
+	if err != nil || section.Key != "3" {
+		t.Errorf("FindSection(music) = %+v, %v, want section 3", section, err)
+	}
+	if section, err := client.FindSection("4"); err != nil || section.Title != "Audiobooks" {
+		t.Errorf("FindSection(4) = %+v, %v, want Audiobooks", section, err)
+	}
+	if _, err := client.FindSection(""); err == nil || !strings.Contains(err.Error(), `"Audiobooks"`) {
+		t.Errorf("Expected a choice between several music libraries to be an error, got %v", err)
+	}
+	if _, err := client.FindSection("Movies"); err == nil {
+		t.Error("Expected a library that is not for music to be an error")
+	}
+
+	client.token = "wrong"
+	if _, err := client.MusicSections(); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("Expected a rejected token error, got %v", err)
+	}
+}
+
+func TestFindTrack(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/library/sections/3/all" || r.URL.Query().Get("type") != "10" {
+			t.Errorf("Unexpected search %s", r.URL)
+		}
+		if title := r.URL.Query().Get("title"); title != "Bohemian Rhapsody" {
+			t.Errorf("Expected a search by the title without additions, got %q", title)
+		}
+		w.Write([]byte(`{"MediaContainer":{"Metadata":[
+			{"ratingKey":"10","title":"Bohemian Rhapsody","grandparentTitle":"Various Artists","parentTitle":"Rock Hits","originalTitle":"Queen","duration":354000},
+			{"ratingKey":"11","title":"Bohemian Rhapsody","grandparentTitle":"Queen","parentTitle":"A Night at the Opera","duration":355000}
+		]}}`))
+	})
+
+	track := trackmatch.Track{Title: "Bohemian Rhapsody - Remastered 2011", Artists: []string{"Queen"}, Album: "A Night At The Opera (2011 Remaster)"}
+	found, err := client.FindTrack("3", track)
+	if err != nil {
+		t.Fatalf("FindTrack() error = %v", err)
+	}
+	if found == nil || found.RatingKey != "11" {
+		t.Errorf("FindTrack() = %+v, want the track on the same album", found)
+	}
+
+	compilation := BestMatch(trackmatch.Track{Title: "Bohemian Rhapsody", Artists: []string{"Queen"}, Album: "Rock Hits"}, []Track{
+		{RatingKey: "10", Title: "Bohemian Rhapsody", GrandparentTitle: "Various Artists", OriginalTitle: "Queen", ParentTitle: "Rock Hits"},
+	})
+	if compilation == nil {
+		t.Error("Expected a compilation track to match by its own artist")
+	}
+}
+
+func TestSavePlaylist(t *testing.T) {
+	var requests []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("uri"))
+		if r.Method == http.MethodPost {
+			if r.URL.Query().Get("title") != "Mix" || r.URL.Query().Get("type") != "audio" {
+				t.Errorf("Unexpected playlist %v", r.URL.Query())
+			}
+			w.Write([]byte(`{"MediaContainer":{"Metadata":[{"ratingKey":"99","title":"Mix","playlistType":"audio"}]}}`))
+		}
+	})
+
+	keys := make([]string, itemBatch+1)
+	for i := range keys {
+		keys[i] = "1"
+	}
+	keys[itemBatch] = "2"
+	playlist, err := client.CreatePlaylist("Mix", keys)
+	if err != nil {
+		t.Fatalf("CreatePlaylist() error = %v", err)
+	}
+	if playlist.RatingKey != "99" {
+		t.Errorf("CreatePlaylist() = %+v, want playlist 99", playlist)
+	}
+	if len(requests) != 2 || requests[1] != "PUT /playlists/99/items server://machine/com.plexapp.plugins.library/library/metadata/2" {
+		t.Errorf("Expected a create and a request for the rest of the tracks, got %q", requests)
+	}
+
+	requests = nil
+	if err := client.ReplacePlaylist("99", []string{"5", "6"}); err != nil {
+		t.Fatalf("ReplacePlaylist() error = %v", err)
+	}
+	want := []string{
+		"DELETE /playlists/99/items ",
+		"PUT /playlists/99/items server://machine/com.plexapp.plugins.library/library/metadata/5,6",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ReplacePlaylist() made %q, want %q", requests, want)
+	}
+}
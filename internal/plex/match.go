@@ -0,0 +1,40 @@
+package plex
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+// FindTrack searches a music library for track and returns the best match,
+// or nil if no track matches. The library is searched by title, without
+// additions such as "- Remastered" that the tags may not have.
+func (c *Client) FindTrack(sectionKey string, track trackmatch.Track) (*Track, error) {
+	title := trackmatch.BaseTitle(track.Title)
+	if title == "" {
+		return nil, nil
+	}
+	tracks, err := c.SearchTracks(sectionKey, title)
+	if err != nil {
+		return nil, err
+	}
+	return BestMatch(track, tracks), nil
+}
+
+// BestMatch picks the Plex track that is track, as trackmatch.Best does, or
+// nil
+func BestMatch(track trackmatch.Track, tracks []Track) *Track {
+	candidates := make([]trackmatch.Song, len(tracks))
+	for i, t := range tracks {
+		candidates[i] = trackmatch.Song{
+			Title:    t.Title,
+			Artist:   t.Artist(),
+			Album:    t.ParentTitle,
+			Duration: time.Duration(t.Duration) * time.Millisecond,
+		}
+	}
+	if i := trackmatch.Best(track, candidates); i >= 0 {
+		return &tracks[i]
+	}
+	return nil
+}
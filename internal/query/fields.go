@@ -0,0 +1,165 @@
+package query
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+type fieldType int
+
+const (
+	numberField fieldType = iota
+	stringField
+	boolField
+)
+
+// field is a track attribute that can be filtered and sorted on
+type field struct {
+	typ fieldType
+	// features is set for audio features, which cost an extra request per
+	// 100 tracks and are only fetched when a query uses them
+	features bool
+	number   func(Item) (float64, bool)
+	text     func(Item) string
+	boolean  func(Item) bool
+}
+
+func trackNumber(get func(models.Track) float64) field {
+	return field{typ: numberField, number: func(item Item) (float64, bool) {
+		return get(item.Track), true
+	}}
+}
+
+func featureNumber(get func(*models.AudioFeatures) float64) field {
+	return field{typ: numberField, features: true, number: func(item Item) (float64, bool) {
+		if item.Features == nil {
+			return 0, false
+		}
+		return get(item.Features), true
+	}}
+}
+
+func trackText(get func(Item) string) field {
+	return field{typ: stringField, text: get}
+}
+
+var fields = map[string]field{
+	"popularity":   trackNumber(func(t models.Track) float64 { return float64(t.Popularity) }),
+	"duration":     trackNumber(func(t models.Track) float64 { return math.Round(float64(t.DurationMs) / 1000) }),
+	"duration_ms":  trackNumber(func(t models.Track) float64 { return float64(t.DurationMs) }),
+	"track_number": trackNumber(func(t models.Track) float64 { return float64(t.TrackNumber) }),
+
+	"acousticness":     featureNumber(func(f *models.AudioFeatures) float64 { return f.Acousticness }),
+	"danceability":     featureNumber(func(f *models.AudioFeatures) float64 { return f.Danceability }),
+	"energy":           featureNumber(func(f *models.AudioFeatures) float64 { return f.Energy }),
+	"instrumentalness": featureNumber(func(f *models.AudioFeatures) float64 { return f.Instrumentalness }),
+	"key":              featureNumber(func(f *models.AudioFeatures) float64 { return float64(f.Key) }),
+	"liveness":         featureNumber(func(f *models.AudioFeatures) float64 { return f.Liveness }),
+	"loudness":         featureNumber(func(f *models.AudioFeatures) float64 { return f.Loudness }),
+	"mode":             featureNumber(func(f *models.AudioFeatures) float64 { return float64(f.Mode) }),
+	"speechiness":      featureNumber(func(f *models.AudioFeatures) float64 { return f.Speechiness }),
+	"tempo":            featureNumber(func(f *models.AudioFeatures) float64 { return f.Tempo }),
+	"time_signature":   featureNumber(func(f *models.AudioFeatures) float64 { return float64(f.TimeSignature) }),
+	"valence":          featureNumber(func(f *models.AudioFeatures) float64 { return f.Valence }),
+
+	"name":     trackText(func(item Item) string { return item.Track.Name }),
+	"artist":   trackText(func(item Item) string { return artistNames(item.Track) }),
+	"album":    trackText(func(item Item) string { return albumName(item.Track) }),
+	"id":       trackText(func(item Item) string { return item.Track.ID }),
+	"uri":      trackText(func(item Item) string { return item.Track.URI }),
+	"added_at": trackText(func(item Item) string { return item.AddedAt }),
+
+	"explicit": {typ: boolField, boolean: func(item Item) bool { return item.Track.Explicit }},
+}
+
+// fieldNames returns the known field names, sorted, for error messages
+func fieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func artistNames(track models.Track) string {
+	names := make([]string, len(track.Artists))
+	for i, artist := range track.Artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func albumName(track models.Track) string {
+	if track.Album == nil {
+		return ""
+	}
+	return track.Album.Name
+}
+
+// match reports whether an item satisfies a condition. Conditions on audio
+// features never match tracks Spotify has no features for.
+func match(item Item, cond *Condition, f field) bool {
+	switch f.typ {
+	case numberField:
+		n, ok := f.number(item)
+		if !ok {
+			return false
+		}
+		return compareNumbers(n, cond.Op, cond.Value.Num)
+
+	case boolField:
+		b := f.boolean(item)
+		if cond.Op == "!=" {
+			return b != cond.Value.Bool
+		}
+		return b == cond.Value.Bool
+
+	default:
+		return compareText(f.text(item), cond.Op, cond.Value.Str)
+	}
+}
+
+func compareNumbers(a float64, op string, b float64) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
+
+// compareText compares case-insensitively; "~" tests whether a contains b
+func compareText(a, op, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	switch op {
+	case "~":
+		return strings.Contains(a, b)
+	case "!~":
+		return !strings.Contains(a, b)
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
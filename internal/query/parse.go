@@ -0,0 +1,368 @@
+// Package query implements a small pipeline language for scripting across
+// resources, such as
+//
+//	playlist("Workout").tracks().filter(energy>0.7).save_to("High Energy")
+//
+// A query starts with a source of tracks, followed by stages that transform
+// the stream and optionally a terminal stage that writes the result. Sources
+// are read lazily, one page at a time, so a limit stops paging early.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// ValueKind identifies the type of a literal
+type ValueKind int
+
+const (
+	KindString ValueKind = iota
+	KindNumber
+	KindBool
+	// KindIdent is a bare word such as a field name
+	KindIdent
+)
+
+// Value is a literal argument
+type Value struct {
+	Kind ValueKind
+	Str  string
+	Num  float64
+	Bool bool
+}
+
+// String returns the value as it would be written in a query
+func (v Value) String() string {
+	switch v.Kind {
+	case KindString:
+		return strconv.Quote(v.Str)
+	case KindNumber:
+		return strconv.FormatFloat(v.Num, 'f', -1, 64)
+	case KindBool:
+		return strconv.FormatBool(v.Bool)
+	default:
+		return v.Str
+	}
+}
+
+// Condition compares a track field with a value, as in energy>0.7
+type Condition struct {
+	Field string
+	Op    string
+	Value Value
+}
+
+// Arg is a call argument: either a value or a condition
+type Arg struct {
+	Value *Value
+	Cond  *Condition
+}
+
+// Call is one element of a pipeline, such as filter(energy>0.7)
+type Call struct {
+	Name string
+	Args []Arg
+	// Pos is the byte offset of the call in the query
+	Pos int
+}
+
+// Pipeline is a parsed query: a source followed by stages
+type Pipeline struct {
+	Source Call
+	Stages []Call
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// comparison operators, longest first so ">=" is not read as ">"
+var operators = []string{">=", "<=", "==", "!=", "!~", ">", "<", "=", "~"}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(' || c == ')' || c == ',' || c == '.':
+			tokens = append(tokens, token{tokPunct, string(c), i})
+			i++
+
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && rune(src[end]) != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, syntaxError(i, "unterminated string")
+			}
+
+			text := strings.ReplaceAll(src[i+1:end], `\'`, "'")
+			if c == '"' {
+				unquoted, err := strconv.Unquote(`"` + text + `"`)
+				if err != nil {
+					return nil, syntaxError(i, "invalid string")
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, token{tokString, text, i})
+			i = end + 1
+
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(src) && (unicode.IsDigit(rune(src[i+1])) || src[i+1] == '.')):
+			end := i + 1
+			for end < len(src) && (unicode.IsDigit(rune(src[end])) || src[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{tokNumber, src[i:end], i})
+			i = end
+
+		case unicode.IsLetter(c) || c == '_':
+			end := i + 1
+			for end < len(src) && (unicode.IsLetter(rune(src[end])) || unicode.IsDigit(rune(src[end])) || src[end] == '_') {
+				end++
+			}
+			tokens = append(tokens, token{tokIdent, src[i:end], i})
+			i = end
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, syntaxError(i, fmt.Sprintf("unexpected character %q", c))
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a query into a pipeline without checking what the calls mean;
+// use Compile to also validate the calls and their arguments
+func Parse(src string) (*Pipeline, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.NewValidationError("query cannot be empty")
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	source, err := p.call()
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := &Pipeline{Source: source}
+	for p.peek().kind != tokEOF {
+		if err := p.expect(tokPunct, "."); err != nil {
+			return nil, err
+		}
+		stage, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		pipeline.Stages = append(pipeline.Stages, stage)
+	}
+
+	return pipeline, nil
+}
+
+// ParseConditions parses conditions as written in filter(), such as
+// "tempo>=120 and energy>0.7", joined with "," or "and"
+func ParseConditions(src string) ([]Condition, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.NewValidationError("condition cannot be empty")
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	var conds []Condition
+	for {
+		arg, err := p.arg()
+		if err != nil {
+			return nil, err
+		}
+		if arg.Cond == nil {
+			return nil, syntaxError(p.tokens[p.pos-1].pos, "expected a condition, such as energy>0.7")
+		}
+		conds = append(conds, *arg.Cond)
+
+		next := p.advance()
+		switch {
+		case next.kind == tokEOF:
+			return conds, nil
+		case next.kind == tokPunct && next.text == ",":
+		case next.kind == tokIdent && next.text == "and":
+		default:
+			return nil, syntaxError(next.pos, fmt.Sprintf("expected \",\" or \"and\", found %s", describe(next)))
+		}
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	t := p.advance()
+	if t.kind != kind || t.text != text {
+		return syntaxError(t.pos, fmt.Sprintf("expected %q, found %s", text, describe(t)))
+	}
+	return nil
+}
+
+func (p *parser) call() (Call, error) {
+	t := p.advance()
+	if t.kind != tokIdent {
+		return Call{}, syntaxError(t.pos, fmt.Sprintf("expected a function name, found %s", describe(t)))
+	}
+
+	call := Call{Name: t.text, Pos: t.pos}
+	if err := p.expect(tokPunct, "("); err != nil {
+		return Call{}, err
+	}
+
+	if next := p.peek(); next.kind == tokPunct && next.text == ")" {
+		p.advance()
+		return call, nil
+	}
+
+	for {
+		arg, err := p.arg()
+		if err != nil {
+			return Call{}, err
+		}
+		call.Args = append(call.Args, arg)
+
+		// Arguments are separated by commas; "and" reads better between
+		// filter conditions and means the same thing
+		next := p.advance()
+		switch {
+		case next.kind == tokPunct && next.text == ")":
+			return call, nil
+		case next.kind == tokPunct && next.text == ",":
+		case next.kind == tokIdent && next.text == "and":
+		default:
+			return Call{}, syntaxError(next.pos, fmt.Sprintf("expected \",\" or \")\", found %s", describe(next)))
+		}
+	}
+}
+
+func (p *parser) arg() (Arg, error) {
+	value, err := p.value()
+	if err != nil {
+		return Arg{}, err
+	}
+
+	if p.peek().kind != tokOp {
+		return Arg{Value: &value}, nil
+	}
+
+	op := p.advance()
+	if value.Kind != KindIdent {
+		return Arg{}, syntaxError(op.pos, "the left side of a comparison must be a field name")
+	}
+
+	right, err := p.value()
+	if err != nil {
+		return Arg{}, err
+	}
+
+	operator := op.text
+	if operator == "=" {
+		operator = "=="
+	}
+
+	return Arg{Cond: &Condition{Field: value.Str, Op: operator, Value: right}}, nil
+}
+
+func (p *parser) value() (Value, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokString:
+		return Value{Kind: KindString, Str: t.text}, nil
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return Value{}, syntaxError(t.pos, fmt.Sprintf("invalid number %q", t.text))
+		}
+		return Value{Kind: KindNumber, Num: n}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return Value{Kind: KindBool, Bool: t.text == "true"}, nil
+		}
+		return Value{Kind: KindIdent, Str: t.text}, nil
+	default:
+		return Value{}, syntaxError(t.pos, fmt.Sprintf("expected a value, found %s", describe(t)))
+	}
+}
+
+func describe(t token) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return fmt.Sprintf("string %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+func syntaxError(pos int, message string) error {
+	return errors.NewValidationError(fmt.Sprintf("query syntax error at position %d: %s", pos+1, message))
+}
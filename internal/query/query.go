@@ -0,0 +1,706 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// audioFeaturesBatch is the most tracks GET /audio-features accepts at once
+const audioFeaturesBatch = 100
+
+// Item is a track flowing through a query
+type Item struct {
+	Track   models.Track `json:"track" yaml:"track"`
+	AddedAt string       `json:"added_at,omitempty" yaml:"added_at,omitempty"`
+	// Features is filled in when the query uses audio features
+	Features *models.AudioFeatures `json:"audio_features,omitempty" yaml:"audio_features,omitempty"`
+
+	hydrated bool
+}
+
+// Stream is a lazily read sequence of items, usually a page at a time
+type Stream interface {
+	HasNext() bool
+	Next(ctx context.Context) ([]Item, error)
+}
+
+type pagerStream[T any] struct {
+	pager   *api.Pager[T]
+	convert func(T) (Item, bool)
+}
+
+// FromPager turns a pager into a stream, dropping items convert rejects such
+// as local files and podcast episodes
+func FromPager[T any](pager *api.Pager[T], convert func(T) (Item, bool)) Stream {
+	return &pagerStream[T]{pager: pager, convert: convert}
+}
+
+func (s *pagerStream[T]) HasNext() bool {
+	return s.pager.HasNext()
+}
+
+func (s *pagerStream[T]) Next(ctx context.Context) ([]Item, error) {
+	page, err := s.pager.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(page))
+	for _, raw := range page {
+		if item, ok := s.convert(raw); ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+type itemsStream struct {
+	items []Item
+	done  bool
+}
+
+// FromItems turns items that are already loaded into a single page stream
+func FromItems(items []Item) Stream {
+	return &itemsStream{items: items}
+}
+
+func (s *itemsStream) HasNext() bool {
+	return !s.done
+}
+
+func (s *itemsStream) Next(ctx context.Context) ([]Item, error) {
+	s.done = true
+	return s.items, nil
+}
+
+// Collect reads a stream to the end
+func Collect(ctx context.Context, stream Stream) ([]Item, error) {
+	var all []Item
+	for stream.HasNext() {
+		items, err := stream.Next(ctx)
+		if err == api.ErrNoMorePages {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// FeatureSource looks up audio features, for up to 100 track IDs at a time
+type FeatureSource interface {
+	AudioFeatures(ctx context.Context, ids []string) ([]models.AudioFeatures, error)
+}
+
+// Backend is what a query reads from and writes to, implemented on top of
+// the Spotify services by the CLI
+type Backend interface {
+	// PlaylistTracks streams a playlist given by ID, URI or name
+	PlaylistTracks(ctx context.Context, ref string) (Stream, error)
+	SavedTracks(ctx context.Context) (Stream, error)
+	AlbumTracks(ctx context.Context, ref string) (Stream, error)
+	TopTracks(ctx context.Context, timeRange string) (Stream, error)
+	SearchTracks(ctx context.Context, query string) (Stream, error)
+	// TaggedTracks streams the tracks carrying a local tag, including those
+	// on tagged albums and playlists
+	TaggedTracks(ctx context.Context, tag string) (Stream, error)
+	// AudioFeatures returns features for up to 100 track IDs; tracks without
+	// features may be missing from the result
+	AudioFeatures(ctx context.Context, ids []string) ([]models.AudioFeatures, error)
+	// WritePlaylist puts the tracks in the named playlist, creating it if the
+	// user has none by that name. With replace set the existing tracks are
+	// replaced, otherwise the tracks are appended.
+	WritePlaylist(ctx context.Context, name string, uris []string, replace bool) (*SaveResult, error)
+	// Play starts playing the tracks on the active device
+	Play(ctx context.Context, uris []string) error
+	// Enqueue adds the tracks to the playback queue one by one and returns
+	// how many were added before any error
+	Enqueue(ctx context.Context, uris []string) (int, error)
+}
+
+// SaveResult describes what a save_to or append_to stage wrote
+type SaveResult struct {
+	Playlist   string `json:"playlist" yaml:"playlist"`
+	PlaylistID string `json:"playlist_id,omitempty" yaml:"playlist_id,omitempty"`
+	SnapshotID string `json:"snapshot_id,omitempty" yaml:"snapshot_id,omitempty"`
+	Created    bool   `json:"created" yaml:"created"`
+	Replaced   bool   `json:"replaced" yaml:"replaced"`
+	Tracks     int    `json:"tracks" yaml:"tracks"`
+	DryRun     bool   `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+	// BackupSnapshotID is the version recorded before the tracks were
+	// replaced, for 'playlist rollback'
+	BackupSnapshotID string `json:"backup_snapshot_id,omitempty" yaml:"backup_snapshot_id,omitempty"`
+}
+
+// PlaybackResult describes what a play or queue stage did
+type PlaybackResult struct {
+	// Action is "play" or "queue"
+	Action string `json:"action" yaml:"action"`
+	Tracks int    `json:"tracks" yaml:"tracks"`
+	DryRun bool   `json:"dry_run,omitempty" yaml:"dry_run,omitempty"`
+}
+
+// Result is the outcome of running a query
+type Result struct {
+	Items    []Item          `json:"items" yaml:"items"`
+	Save     *SaveResult     `json:"save,omitempty" yaml:"save,omitempty"`
+	Playback *PlaybackResult `json:"playback,omitempty" yaml:"playback,omitempty"`
+}
+
+// Options control how a query runs
+type Options struct {
+	// DryRun runs every read but skips the final write
+	DryRun bool
+}
+
+// Query is a compiled pipeline, checked and ready to run
+type Query struct {
+	source func(ctx context.Context, b Backend) (Stream, error)
+	stages []func(in iterator, b Backend) iterator
+	// terminal is the final save_to, append_to, play or queue stage, if any
+	terminal *Call
+}
+
+// errDone ends an iterator
+var errDone = io.EOF
+
+type iterator interface {
+	next(ctx context.Context) ([]Item, error)
+}
+
+// Compile parses and checks a query, so mistakes are reported before any
+// request is made
+func Compile(src string) (*Query, error) {
+	pipeline, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	if q.source, err = compileSource(pipeline.Source); err != nil {
+		return nil, err
+	}
+
+	for i, call := range pipeline.Stages {
+		switch call.Name {
+		case "save_to", "append_to", "play", "queue":
+			if i != len(pipeline.Stages)-1 {
+				return nil, queryError(call, "must be the last stage")
+			}
+			if call.Name == "play" || call.Name == "queue" {
+				err = noArgs(call)
+			} else {
+				_, err = stringArg(call)
+			}
+			if err != nil {
+				return nil, err
+			}
+			q.terminal = &pipeline.Stages[i]
+
+		default:
+			stage, err := compileStage(call)
+			if err != nil {
+				return nil, err
+			}
+			if stage != nil {
+				q.stages = append(q.stages, stage)
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// Run evaluates the query, reading sources lazily through the backend
+func (q *Query) Run(ctx context.Context, b Backend, opts Options) (*Result, error) {
+	stream, err := q.source(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var it iterator = &streamIterator{stream: stream}
+	for _, stage := range q.stages {
+		it = stage(it, b)
+	}
+
+	result := &Result{}
+	for {
+		items, err := it.next(ctx)
+		if err == errDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, items...)
+	}
+
+	if q.terminal == nil {
+		return result, nil
+	}
+
+	uris := make([]string, 0, len(result.Items))
+	for _, item := range result.Items {
+		uris = append(uris, item.Track.URI)
+	}
+
+	switch q.terminal.Name {
+	case "play", "queue":
+		result.Playback = &PlaybackResult{Action: q.terminal.Name, Tracks: len(uris), DryRun: opts.DryRun}
+		if opts.DryRun || len(uris) == 0 {
+			return result, nil
+		}
+		if q.terminal.Name == "play" {
+			return result, b.Play(ctx, uris)
+		}
+		result.Playback.Tracks, err = b.Enqueue(ctx, uris)
+		return result, err
+
+	default:
+		playlist, replace := q.terminal.Args[0].Value.Str, q.terminal.Name == "save_to"
+		if opts.DryRun {
+			result.Save = &SaveResult{Playlist: playlist, Replaced: replace, Tracks: len(uris), DryRun: true}
+			return result, nil
+		}
+
+		result.Save, err = b.WritePlaylist(ctx, playlist, uris, replace)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+func compileSource(call Call) (func(ctx context.Context, b Backend) (Stream, error), error) {
+	switch call.Name {
+	case "playlist":
+		ref, err := stringArg(call)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.PlaylistTracks(ctx, ref) }, nil
+
+	case "saved", "liked":
+		if err := noArgs(call); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.SavedTracks(ctx) }, nil
+
+	case "album":
+		ref, err := stringArg(call)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.AlbumTracks(ctx, ref) }, nil
+
+	case "top":
+		timeRange := "medium_term"
+		if len(call.Args) > 1 {
+			return nil, queryError(call, "takes at most one argument, the time range")
+		}
+		if len(call.Args) == 1 {
+			word, ok := wordArg(call.Args[0])
+			if !ok {
+				return nil, queryError(call, "time range must be short, medium or long")
+			}
+			switch strings.TrimSuffix(word, "_term") {
+			case "short", "medium", "long":
+				timeRange = strings.TrimSuffix(word, "_term") + "_term"
+			default:
+				return nil, queryError(call, fmt.Sprintf("unknown time range %q: must be short, medium or long", word))
+			}
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.TopTracks(ctx, timeRange) }, nil
+
+	case "search":
+		text, err := stringArg(call)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.SearchTracks(ctx, text) }, nil
+
+	case "tagged":
+		tag, err := stringArg(call)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, b Backend) (Stream, error) { return b.TaggedTracks(ctx, tag) }, nil
+
+	default:
+		return nil, queryError(call, "unknown source: must be playlist, saved, album, top, search or tagged")
+	}
+}
+
+func compileStage(call Call) (func(in iterator, b Backend) iterator, error) {
+	switch call.Name {
+	case "tracks":
+		// Reads naturally after playlist() and album(); every source already
+		// yields tracks
+		return nil, noArgs(call)
+
+	case "filter":
+		if len(call.Args) == 0 {
+			return nil, queryError(call, "needs at least one condition, such as energy>0.7")
+		}
+		conds := make([]compiledCondition, 0, len(call.Args))
+		needsFeatures := false
+		for _, arg := range call.Args {
+			cond, err := compileCondition(call, arg)
+			if err != nil {
+				return nil, err
+			}
+			needsFeatures = needsFeatures || cond.field.features
+			conds = append(conds, cond)
+		}
+		return func(in iterator, b Backend) iterator {
+			return &filterIterator{in: in, backend: b, conds: conds, features: needsFeatures}
+		}, nil
+
+	case "sort":
+		if len(call.Args) == 0 || len(call.Args) > 2 {
+			return nil, queryError(call, "takes a field and an optional direction, such as sort(tempo, desc)")
+		}
+		name, ok := wordArg(call.Args[0])
+		if !ok {
+			return nil, queryError(call, "first argument must be a field name")
+		}
+		f, ok := fields[name]
+		if !ok {
+			return nil, unknownField(call, name)
+		}
+		desc := false
+		if len(call.Args) == 2 {
+			direction, _ := wordArg(call.Args[1])
+			switch direction {
+			case "asc":
+			case "desc":
+				desc = true
+			default:
+				return nil, queryError(call, "direction must be asc or desc")
+			}
+		}
+		return func(in iterator, b Backend) iterator {
+			return &sortIterator{in: in, backend: b, field: f, desc: desc}
+		}, nil
+
+	case "limit":
+		if len(call.Args) != 1 || call.Args[0].Value == nil || call.Args[0].Value.Kind != KindNumber {
+			return nil, queryError(call, "takes one number")
+		}
+		n := call.Args[0].Value.Num
+		if n < 1 || n != math.Trunc(n) {
+			return nil, queryError(call, "must be a positive whole number")
+		}
+		return func(in iterator, b Backend) iterator {
+			return &limitIterator{in: in, remaining: int(n)}
+		}, nil
+
+	case "dedupe":
+		if err := noArgs(call); err != nil {
+			return nil, err
+		}
+		return func(in iterator, b Backend) iterator {
+			return &dedupeIterator{in: in, seen: make(map[string]bool)}
+		}, nil
+
+	default:
+		return nil, queryError(call, "unknown stage: must be tracks, filter, sort, limit, dedupe, save_to, append_to, play or queue")
+	}
+}
+
+type compiledCondition struct {
+	cond  *Condition
+	field field
+}
+
+func compileCondition(call Call, arg Arg) (compiledCondition, error) {
+	if arg.Cond == nil {
+		return compiledCondition{}, queryError(call, "arguments must be conditions, such as energy>0.7")
+	}
+
+	f, problem := checkCondition(arg.Cond)
+	if problem != "" {
+		return compiledCondition{}, queryError(call, problem)
+	}
+	return compiledCondition{cond: arg.Cond, field: f}, nil
+}
+
+// checkCondition looks up the field of a condition and checks the value
+// suits it, returning what is wrong if not
+func checkCondition(cond *Condition) (field, string) {
+	f, ok := fields[cond.Field]
+	if !ok {
+		return field{}, unknownFieldMessage(cond.Field)
+	}
+
+	switch f.typ {
+	case numberField:
+		if cond.Value.Kind != KindNumber {
+			return field{}, fmt.Sprintf("%s must be compared with a number", cond.Field)
+		}
+		if cond.Op == "~" || cond.Op == "!~" {
+			return field{}, fmt.Sprintf("%s cannot be used with %s", cond.Op, cond.Field)
+		}
+	case boolField:
+		if cond.Value.Kind != KindBool || (cond.Op != "==" && cond.Op != "!=") {
+			return field{}, fmt.Sprintf("%s can only be compared with == or != to true or false", cond.Field)
+		}
+	default:
+		if cond.Value.Kind != KindString {
+			return field{}, fmt.Sprintf("%s must be compared with a quoted string", cond.Field)
+		}
+	}
+	return f, ""
+}
+
+type streamIterator struct {
+	stream Stream
+}
+
+func (it *streamIterator) next(ctx context.Context) ([]Item, error) {
+	if !it.stream.HasNext() {
+		return nil, errDone
+	}
+
+	items, err := it.stream.Next(ctx)
+	if err == api.ErrNoMorePages {
+		return nil, errDone
+	}
+	return items, err
+}
+
+type filterIterator struct {
+	in       iterator
+	backend  Backend
+	conds    []compiledCondition
+	features bool
+}
+
+func (it *filterIterator) next(ctx context.Context) ([]Item, error) {
+	items, err := it.in.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if it.features {
+		if err := hydrate(ctx, it.backend, items); err != nil {
+			return nil, err
+		}
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		if it.matches(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+func (it *filterIterator) matches(item Item) bool {
+	for _, c := range it.conds {
+		if !match(item, c.cond, c.field) {
+			return false
+		}
+	}
+	return true
+}
+
+type limitIterator struct {
+	in        iterator
+	remaining int
+}
+
+func (it *limitIterator) next(ctx context.Context) ([]Item, error) {
+	// Stop before asking for another page once the limit is reached
+	if it.remaining <= 0 {
+		return nil, errDone
+	}
+
+	items, err := it.in.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) > it.remaining {
+		items = items[:it.remaining]
+	}
+	it.remaining -= len(items)
+	return items, nil
+}
+
+type dedupeIterator struct {
+	in   iterator
+	seen map[string]bool
+}
+
+func (it *dedupeIterator) next(ctx context.Context) ([]Item, error) {
+	items, err := it.in.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := items[:0]
+	for _, item := range items {
+		key := item.Track.URI
+		if key == "" {
+			key = item.Track.ID
+		}
+		if !it.seen[key] {
+			it.seen[key] = true
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// sortIterator has to read everything before it can return anything
+type sortIterator struct {
+	in      iterator
+	backend Backend
+	field   field
+	desc    bool
+	done    bool
+}
+
+func (it *sortIterator) next(ctx context.Context) ([]Item, error) {
+	if it.done {
+		return nil, errDone
+	}
+	it.done = true
+
+	var all []Item
+	for {
+		items, err := it.in.next(ctx)
+		if err == errDone {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+
+	if it.field.features {
+		if err := hydrate(ctx, it.backend, all); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return it.less(all[i], all[j])
+	})
+	return all, nil
+}
+
+// less orders two items by the sort field; tracks without a value for it go
+// last whichever the direction
+func (it *sortIterator) less(a, b Item) bool {
+	switch it.field.typ {
+	case numberField:
+		x, okX := it.field.number(a)
+		y, okY := it.field.number(b)
+		if okX != okY {
+			return okX
+		}
+		if it.desc {
+			return x > y
+		}
+		return x < y
+
+	case boolField:
+		x, y := it.field.boolean(a), it.field.boolean(b)
+		if it.desc {
+			return x && !y
+		}
+		return !x && y
+
+	default:
+		x, y := strings.ToLower(it.field.text(a)), strings.ToLower(it.field.text(b))
+		if it.desc {
+			return x > y
+		}
+		return x < y
+	}
+}
+
+// hydrate fetches audio features for the items that do not have them yet,
+// in batches of at most 100 tracks
+func hydrate(ctx context.Context, b FeatureSource, items []Item) error {
+	var pending []int
+	for i := range items {
+		if !items[i].hydrated && items[i].Track.ID != "" {
+			pending = append(pending, i)
+		}
+	}
+
+	for start := 0; start < len(pending); start += audioFeaturesBatch {
+		end := min(start+audioFeaturesBatch, len(pending))
+		batch := pending[start:end]
+
+		ids := make([]string, len(batch))
+		for i, idx := range batch {
+			ids[i] = items[idx].Track.ID
+		}
+
+		features, err := b.AudioFeatures(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[string]*models.AudioFeatures, len(features))
+		for i := range features {
+			byID[features[i].ID] = &features[i]
+		}
+		for _, idx := range batch {
+			items[idx].Features = byID[items[idx].Track.ID]
+			items[idx].hydrated = true
+		}
+	}
+
+	return nil
+}
+
+func stringArg(call Call) (string, error) {
+	if len(call.Args) != 1 || call.Args[0].Value == nil || call.Args[0].Value.Kind != KindString || call.Args[0].Value.Str == "" {
+		return "", queryError(call, "takes one quoted string")
+	}
+	return call.Args[0].Value.Str, nil
+}
+
+// wordArg accepts a bare word or a string, as in sort(tempo) or sort("tempo")
+func wordArg(arg Arg) (string, bool) {
+	if arg.Value == nil || (arg.Value.Kind != KindIdent && arg.Value.Kind != KindString) {
+		return "", false
+	}
+	return arg.Value.Str, true
+}
+
+func noArgs(call Call) error {
+	if len(call.Args) > 0 {
+		return queryError(call, "takes no arguments")
+	}
+	return nil
+}
+
+func unknownField(call Call, name string) error {
+	return queryError(call, unknownFieldMessage(name))
+}
+
+func unknownFieldMessage(name string) string {
+	return fmt.Sprintf("unknown field %q. Fields: %s", name, strings.Join(fieldNames(), ", "))
+}
+
+func queryError(call Call, message string) error {
+	return errors.NewValidationError(fmt.Sprintf("query error at position %d: %s() %s", call.Pos+1, call.Name, message))
+}
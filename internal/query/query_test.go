@@ -0,0 +1,307 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// fakeBackend serves a numbered playlist of tracks whose energy rises with
+// the track number
+type fakeBackend struct {
+	tracks        []models.Track
+	pageSize      int
+	pages         int
+	featureCalls  int
+	written       []string
+	writtenName   string
+	writtenAppend bool
+	played        []string
+	queued        []string
+}
+
+func newFakeBackend(n int) *fakeBackend {
+	b := &fakeBackend{pageSize: 10}
+	for i := 0; i < n; i++ {
+		b.tracks = append(b.tracks, models.Track{
+			ID:         fmt.Sprintf("track%02d", i),
+			URI:        fmt.Sprintf("spotify:track:track%02d", i),
+			Name:       fmt.Sprintf("Song %d", i),
+			Popularity: i,
+			Artists:    []models.SimpleArtist{{Name: fmt.Sprintf("Artist %d", i%3)}},
+		})
+	}
+	return b
+}
+
+func (b *fakeBackend) stream() Stream {
+	pager := api.NewPager(b.pageSize, 0, func(ctx context.Context, offset, limit int) (*models.Paging[models.Track], error) {
+		b.pages++
+		end := min(offset+limit, len(b.tracks))
+		page := &models.Paging[models.Track]{Items: b.tracks[offset:end], Total: len(b.tracks)}
+		if end < len(b.tracks) {
+			page.Next = "next"
+		}
+		return page, nil
+	})
+	return FromPager(pager, func(track models.Track) (Item, bool) {
+		return Item{Track: track}, true
+	})
+}
+
+func (b *fakeBackend) PlaylistTracks(ctx context.Context, ref string) (Stream, error) {
+	if ref != "X" {
+		return nil, fmt.Errorf("no playlist %q", ref)
+	}
+	return b.stream(), nil
+}
+
+func (b *fakeBackend) SavedTracks(ctx context.Context) (Stream, error) { return b.stream(), nil }
+
+func (b *fakeBackend) AlbumTracks(ctx context.Context, ref string) (Stream, error) {
+	return b.stream(), nil
+}
+
+func (b *fakeBackend) TopTracks(ctx context.Context, timeRange string) (Stream, error) {
+	return b.stream(), nil
+}
+
+func (b *fakeBackend) SearchTracks(ctx context.Context, query string) (Stream, error) {
+	return b.stream(), nil
+}
+
+func (b *fakeBackend) TaggedTracks(ctx context.Context, tag string) (Stream, error) {
+	items := make([]Item, 0, len(b.tracks))
+	for _, track := range b.tracks {
+		items = append(items, Item{Track: track})
+	}
+	return FromItems(items), nil
+}
+
+func (b *fakeBackend) AudioFeatures(ctx context.Context, ids []string) ([]models.AudioFeatures, error) {
+	b.featureCalls++
+	var features []models.AudioFeatures
+	for _, id := range ids {
+		var n int
+		fmt.Sscanf(id, "track%d", &n)
+		features = append(features, models.AudioFeatures{ID: id, Energy: float64(n) / 100, Tempo: float64(200 - n)})
+	}
+	return features, nil
+}
+
+func (b *fakeBackend) WritePlaylist(ctx context.Context, name string, uris []string, replace bool) (*SaveResult, error) {
+	b.writtenName = name
+	b.written = uris
+	b.writtenAppend = !replace
+	return &SaveResult{Playlist: name, PlaylistID: "new", Created: true, Replaced: replace, Tracks: len(uris)}, nil
+}
+
+func (b *fakeBackend) Play(ctx context.Context, uris []string) error {
+	b.played = uris
+	return nil
+}
+
+func (b *fakeBackend) Enqueue(ctx context.Context, uris []string) (int, error) {
+	b.queued = uris
+	return len(uris), nil
+}
+
+func run(t *testing.T, src string, backend *fakeBackend, opts Options) *Result {
+	t.Helper()
+
+	q, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile(%q) failed: %v", src, err)
+	}
+
+	result, err := q.Run(context.Background(), backend, opts)
+	if err != nil {
+		t.Fatalf("Run(%q) failed: %v", src, err)
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	pipeline, err := Parse(`playlist("X").tracks().filter(energy>0.7, artist ~ 'o\'neil' and explicit=false).limit(5)`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if pipeline.Source.Name != "playlist" || pipeline.Source.Args[0].Value.Str != "X" {
+		t.Errorf("Unexpected source: %+v", pipeline.Source)
+	}
+	if len(pipeline.Stages) != 3 {
+		t.Fatalf("Expected 3 stages, got %d", len(pipeline.Stages))
+	}
+
+	filter := pipeline.Stages[1]
+	if len(filter.Args) != 3 {
+		t.Fatalf("Expected 3 filter conditions, got %d", len(filter.Args))
+	}
+	if c := filter.Args[0].Cond; c.Field != "energy" || c.Op != ">" || c.Value.Num != 0.7 {
+		t.Errorf("Unexpected first condition: %+v", c)
+	}
+	if c := filter.Args[1].Cond; c.Op != "~" || c.Value.Str != "o'neil" {
+		t.Errorf("Unexpected second condition: %+v", c)
+	}
+	if c := filter.Args[2].Cond; c.Op != "==" || c.Value.Kind != KindBool || c.Value.Bool {
+		t.Errorf("Expected = to be read as ==, got %+v", c)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{``, "empty"},
+		{`playlist("X"`, "expected"},
+		{`playlist("X).limit(1)`, "unterminated"},
+		{`nowhere()`, "unknown source"},
+		{`playlist()`, "quoted string"},
+		{`saved().shuffle()`, "unknown stage"},
+		{`saved().filter(bpm>120)`, "unknown field"},
+		{`saved().filter(energy>"high")`, "number"},
+		{`saved().filter(name>3)`, "quoted string"},
+		{`saved().filter(energy)`, "conditions"},
+		{`saved().limit(0)`, "positive"},
+		{`saved().sort(tempo, sideways)`, "asc or desc"},
+		{`saved().save_to("Y").limit(3)`, "last stage"},
+		{`saved().play().limit(3)`, "last stage"},
+		{`saved().queue("Y")`, "no arguments"},
+		{`top(yearly)`, "time range"},
+		{`saved() filter()`, "expected"},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(tt.query)
+		if err == nil {
+			t.Errorf("Compile(%q) succeeded, expected an error containing %q", tt.query, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) error = %v, expected it to contain %q", tt.query, err, tt.want)
+		}
+	}
+}
+
+func TestRun_FilterAndSave(t *testing.T) {
+	backend := newFakeBackend(25)
+
+	result := run(t, `playlist("X").tracks().filter(energy>0.2).save_to("Y")`, backend, Options{})
+
+	if len(result.Items) != 4 {
+		t.Fatalf("Expected tracks 21-24, got %d items", len(result.Items))
+	}
+	if result.Items[0].Features == nil || result.Items[0].Track.ID != "track21" {
+		t.Errorf("Expected hydrated track21 first, got %+v", result.Items[0])
+	}
+
+	if backend.writtenName != "Y" || len(backend.written) != 4 || backend.writtenAppend {
+		t.Errorf("Expected 4 tracks to replace playlist Y, got %q %v append=%v", backend.writtenName, backend.written, backend.writtenAppend)
+	}
+	if result.Save == nil || !result.Save.Created || result.Save.Tracks != 4 {
+		t.Errorf("Unexpected save result: %+v", result.Save)
+	}
+
+	// One features request per page of 10
+	if backend.featureCalls != 3 {
+		t.Errorf("Expected 3 audio features requests, got %d", backend.featureCalls)
+	}
+}
+
+func TestRun_LimitStopsPaging(t *testing.T) {
+	backend := newFakeBackend(100)
+
+	result := run(t, `saved().limit(15)`, backend, Options{})
+
+	if len(result.Items) != 15 {
+		t.Errorf("Expected 15 items, got %d", len(result.Items))
+	}
+	if backend.pages != 2 {
+		t.Errorf("Expected only 2 pages to be fetched, got %d", backend.pages)
+	}
+}
+
+func TestRun_Tagged(t *testing.T) {
+	backend := newFakeBackend(12)
+
+	result := run(t, `tagged("chill").filter(popularity>=8).queue()`, backend, Options{})
+
+	if len(result.Items) != 4 || len(backend.queued) != 4 {
+		t.Errorf("Expected tracks 8-11 to be queued, got %d items and %d queued", len(result.Items), len(backend.queued))
+	}
+}
+
+func TestRun_NoFeaturesUnlessNeeded(t *testing.T) {
+	backend := newFakeBackend(20)
+
+	result := run(t, `saved().filter(artist~"artist 1", popularity>=10)`, backend, Options{})
+
+	if len(result.Items) != 4 {
+		t.Errorf("Expected tracks 10, 13, 16 and 19, got %d items", len(result.Items))
+	}
+	if backend.featureCalls != 0 {
+		t.Errorf("Expected no audio features requests, got %d", backend.featureCalls)
+	}
+}
+
+func TestRun_SortAndDedupe(t *testing.T) {
+	backend := newFakeBackend(5)
+	backend.tracks = append(backend.tracks, backend.tracks[0])
+
+	result := run(t, `saved().dedupe().sort(tempo).limit(2)`, backend, Options{})
+
+	if len(result.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(result.Items))
+	}
+	// Tempo falls as the track number rises
+	if result.Items[0].Track.ID != "track04" || result.Items[1].Track.ID != "track03" {
+		t.Errorf("Unexpected order: %s, %s", result.Items[0].Track.ID, result.Items[1].Track.ID)
+	}
+
+	result = run(t, `saved().dedupe().sort(name, desc)`, backend, Options{})
+	if len(result.Items) != 5 || result.Items[0].Track.Name != "Song 4" {
+		t.Errorf("Expected 5 deduplicated tracks starting with Song 4, got %d starting with %s", len(result.Items), result.Items[0].Track.Name)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	backend := newFakeBackend(5)
+
+	result := run(t, `saved().append_to("Y")`, backend, Options{DryRun: true})
+
+	if backend.written != nil {
+		t.Error("Expected nothing to be written in a dry run")
+	}
+	if result.Save == nil || !result.Save.DryRun || result.Save.Replaced || result.Save.Tracks != 5 {
+		t.Errorf("Unexpected dry run save result: %+v", result.Save)
+	}
+}
+
+func TestRun_PlayAndQueue(t *testing.T) {
+	backend := newFakeBackend(30)
+
+	result := run(t, `saved().filter(popularity>=25).play()`, backend, Options{})
+	if len(backend.played) != 5 || backend.played[0] != "spotify:track:track25" {
+		t.Errorf("Expected tracks 25-29 to be played, got %v", backend.played)
+	}
+	if result.Playback == nil || result.Playback.Action != "play" || result.Playback.Tracks != 5 {
+		t.Errorf("Unexpected playback result: %+v", result.Playback)
+	}
+
+	result = run(t, `top(short).limit(3).queue()`, backend, Options{})
+	if len(backend.queued) != 3 || result.Playback.Action != "queue" || result.Playback.Tracks != 3 {
+		t.Errorf("Expected 3 tracks to be queued, got %v and %+v", backend.queued, result.Playback)
+	}
+
+	backend.queued = nil
+	result = run(t, `saved().queue()`, backend, Options{DryRun: true})
+	if backend.queued != nil || !result.Playback.DryRun || result.Playback.Tracks != 30 {
+		t.Errorf("Expected a dry run to queue nothing, got %v and %+v", backend.queued, result.Playback)
+	}
+}
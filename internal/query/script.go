@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// ScriptExt is the file extension of query scripts
+const ScriptExt = ".query"
+
+// Script is a saved query, run as a command named after its file. Lines
+// starting with # are comments; the first one describes the script. $1 to $9
+// are replaced with the script's arguments, so
+//
+//	# Copy the most energetic tracks of a playlist
+//	playlist("$1").filter(energy>0.8).limit($2).save_to("$1 (energy)")
+//
+// saved as energy.query runs as "energy Workout 25".
+type Script struct {
+	Name        string
+	Path        string
+	Description string
+	Source      string
+	// Args is the number of arguments the script uses
+	Args int
+}
+
+var scriptNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var placeholderPattern = regexp.MustCompile(`\$([1-9])`)
+
+// LoadScript reads a script file
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapFileError(err, "failed to read script")
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), ScriptExt)
+	if !scriptNamePattern.MatchString(name) {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid script name %q: use lowercase letters, digits, - and _", name))
+	}
+
+	script := &Script{Name: name, Path: path}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			if script.Description == "" {
+				script.Description = strings.TrimSpace(strings.TrimPrefix(trimmed, "#"))
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	script.Source = strings.TrimSpace(strings.Join(lines, "\n"))
+	if script.Source == "" {
+		return nil, errors.NewValidationError(fmt.Sprintf("script %s has no query", name))
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(script.Source, -1) {
+		n, _ := strconv.Atoi(match[1])
+		if n > script.Args {
+			script.Args = n
+		}
+	}
+
+	return script, nil
+}
+
+// FindScripts loads the scripts in the given directories. Missing
+// directories are skipped, and a script in an earlier directory hides one
+// with the same name in a later one. Scripts that fail to load are left out
+// and their errors returned alongside the rest.
+func FindScripts(dirs ...string) ([]*Script, []error) {
+	byName := make(map[string]*Script)
+	var errs []error
+
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*"+ScriptExt))
+
+		for _, path := range paths {
+			script, err := LoadScript(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			if _, exists := byName[script.Name]; !exists {
+				byName[script.Name] = script
+			}
+		}
+	}
+
+	scripts := make([]*Script, 0, len(byName))
+	for _, script := range byName {
+		scripts = append(scripts, script)
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		return scripts[i].Name < scripts[j].Name
+	})
+
+	return scripts, errs
+}
+
+// Expand substitutes the arguments into the script. Quotes and backslashes
+// in arguments are escaped so an argument inside a string literal stays in
+// it.
+func (s *Script) Expand(args []string) (string, error) {
+	if len(args) < s.Args {
+		return "", errors.NewValidationError(fmt.Sprintf("script %s needs %d argument%s, got %d", s.Name, s.Args, plural(s.Args), len(args)))
+	}
+	if len(args) > s.Args {
+		return "", errors.NewValidationError(fmt.Sprintf("script %s takes %d argument%s, got %d", s.Name, s.Args, plural(s.Args), len(args)))
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `'`, `\'`)
+	return placeholderPattern.ReplaceAllStringFunc(s.Source, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholder[1:])
+		return escaper.Replace(args[n-1])
+	}), nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
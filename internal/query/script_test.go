@@ -0,0 +1,99 @@
+package query
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeScript(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+	return path
+}
+
+func TestLoadScript(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScript(t, dir, "energy.query", `# Copy the most energetic tracks of a playlist
+# Usage: energy <playlist> <count>
+playlist("$1")
+  .filter(energy>0.8)
+  .limit($2)
+  .save_to("$1 (energy)")
+`)
+
+	script, err := LoadScript(path)
+	if err != nil {
+		t.Fatalf("LoadScript failed: %v", err)
+	}
+
+	if script.Name != "energy" {
+		t.Errorf("Expected name energy, got %q", script.Name)
+	}
+	if script.Description != "Copy the most energetic tracks of a playlist" {
+		t.Errorf("Unexpected description %q", script.Description)
+	}
+	if script.Args != 2 {
+		t.Errorf("Expected 2 arguments, got %d", script.Args)
+	}
+	if strings.Contains(script.Source, "#") {
+		t.Errorf("Expected comments to be stripped, got %q", script.Source)
+	}
+
+	src, err := script.Expand([]string{`Mum's "Mix"`, "10"})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+
+	q, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Expanded script does not compile: %v\n%s", err, src)
+	}
+	if q.terminal.Args[0].Value.Str != `Mum's "Mix" (energy)` {
+		t.Errorf("Unexpected playlist name %q", q.terminal.Args[0].Value.Str)
+	}
+
+	if _, err := script.Expand([]string{"Workout"}); err == nil || !strings.Contains(err.Error(), "needs 2 arguments") {
+		t.Errorf("Expected a missing argument error, got %v", err)
+	}
+	if _, err := script.Expand([]string{"a", "b", "c"}); err == nil || !strings.Contains(err.Error(), "takes 2 arguments") {
+		t.Errorf("Expected an extra argument error, got %v", err)
+	}
+}
+
+func TestLoadScript_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := LoadScript(writeScript(t, dir, "empty.query", "# nothing here\n")); err == nil {
+		t.Error("Expected an error for a script without a query")
+	}
+	if _, err := LoadScript(writeScript(t, dir, "Bad Name.query", "saved()")); err == nil {
+		t.Error("Expected an error for an invalid script name")
+	}
+}
+
+func TestFindScripts(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeScript(t, first, "liked.query", "# first\nsaved()")
+	writeScript(t, second, "liked.query", "# second\nsaved()")
+	writeScript(t, second, "recent.query", "top(short)")
+	writeScript(t, second, "notes.txt", "not a script")
+	writeScript(t, second, "broken.query", "")
+
+	scripts, errs := FindScripts(first, second, filepath.Join(first, "missing"))
+
+	if len(scripts) != 2 || scripts[0].Name != "liked" || scripts[1].Name != "recent" {
+		t.Fatalf("Expected liked and recent, got %v", scripts)
+	}
+	if scripts[0].Description != "first" {
+		t.Errorf("Expected the first directory to win, got %q", scripts[0].Description)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "broken.query") {
+		t.Errorf("Expected one error for broken.query, got %v", errs)
+	}
+}
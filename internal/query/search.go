@@ -0,0 +1,83 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// Search finds tracks among items already read. The Spotify API can only
+// search the whole catalog, so this is how a playlist or the library is
+// searched.
+type Search struct {
+	words    []string
+	conds    []compiledCondition
+	features bool
+}
+
+// CompileSearch checks a search. A track matches when every word of text
+// appears in its name, artists or album, ignoring case, and every condition,
+// written as in filter(), holds.
+func CompileSearch(text string, conditions []string) (*Search, error) {
+	s := &Search{words: strings.Fields(strings.ToLower(text))}
+
+	for _, src := range conditions {
+		parsed, err := ParseConditions(src)
+		if err != nil {
+			return nil, err
+		}
+		for i := range parsed {
+			f, problem := checkCondition(&parsed[i])
+			if problem != "" {
+				return nil, errors.NewValidationError(fmt.Sprintf("invalid condition %q: %s", src, problem))
+			}
+			s.features = s.features || f.features
+			s.conds = append(s.conds, compiledCondition{cond: &parsed[i], field: f})
+		}
+	}
+
+	if len(s.words) == 0 && len(s.conds) == 0 {
+		return nil, errors.NewValidationError("nothing to search for: give some text or a condition")
+	}
+	return s, nil
+}
+
+// Features reports whether the search needs audio features
+func (s *Search) Features() bool {
+	return s.features
+}
+
+// Run returns the items that match, in their order, fetching audio features
+// first if a condition needs them
+func (s *Search) Run(ctx context.Context, source FeatureSource, items []Item) ([]Item, error) {
+	if s.features {
+		if err := hydrate(ctx, source, items); err != nil {
+			return nil, err
+		}
+	}
+
+	var found []Item
+	for _, item := range items {
+		if s.matches(item) {
+			found = append(found, item)
+		}
+	}
+	return found, nil
+}
+
+func (s *Search) matches(item Item) bool {
+	text := strings.ToLower(strings.Join([]string{item.Track.Name, artistNames(item.Track), albumName(item.Track)}, "\n"))
+	for _, word := range s.words {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	for _, c := range s.conds {
+		if !match(item, c.cond, c.field) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	b := newFakeBackend(30)
+	items, err := Collect(ctx, b.stream())
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(items) != 30 || b.pages != 3 {
+		t.Fatalf("Collect() read %d items in %d pages, want 30 in 3", len(items), b.pages)
+	}
+
+	tests := []struct {
+		name       string
+		text       string
+		conditions []string
+		want       int
+		features   bool
+	}{
+		{"text in name", "song 13", nil, 1, false},
+		{"case is ignored", "SONG 13", nil, 1, false},
+		{"words in any field", "13 artist 1", nil, 1, false},
+		{"every word must appear", "13 artist 2", nil, 0, false},
+		{"condition only", "", []string{"popularity>=25"}, 5, false},
+		{"feature range", "", []string{"energy>=0.1 and energy<0.2"}, 10, true},
+		{"text and condition", "artist 2", []string{"tempo>185", "popularity<10"}, 3, true},
+		{"no match", "nothing", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := CompileSearch(tt.text, tt.conditions)
+			if err != nil {
+				t.Fatalf("CompileSearch() error = %v", err)
+			}
+			if s.Features() != tt.features {
+				t.Errorf("Features() = %v, want %v", s.Features(), tt.features)
+			}
+			found, err := s.Run(ctx, b, append([]Item(nil), items...))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if len(found) != tt.want {
+				t.Errorf("Run() found %d tracks, want %d", len(found), tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileSearchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		conditions []string
+	}{
+		{"nothing to search for", "  ", nil},
+		{"unknown field", "", []string{"loudest>1"}},
+		{"wrong value type", "", []string{"energy>\"high\""}},
+		{"not a condition", "", []string{"energy"}},
+		{"bad separator", "", []string{"energy>0.5 or tempo>100"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompileSearch(tt.text, tt.conditions); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
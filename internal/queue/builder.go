@@ -0,0 +1,219 @@
+package queue
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+)
+
+// Source kinds supported by the queue builder
+const (
+	SourceSavedTracks     = "saved:tracks"
+	SourceTopTracks       = "top:tracks"
+	SourceFollowedArtists = "followed:artists"
+	SourcePlaylist        = "playlist"
+	SourceAlbum           = "album"
+	SourceTag             = "tag"
+)
+
+// Source describes a weighted pool of candidate tracks
+type Source struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Weight int    `json:"weight"`
+}
+
+// String returns the source in the same form it was specified on the command line
+func (s Source) String() string {
+	if s.ID != "" {
+		return fmt.Sprintf("%s:%s", s.Kind, s.ID)
+	}
+	return s.Kind
+}
+
+// Candidate is a track that may be picked for the queue
+type Candidate struct {
+	URI        string `json:"uri"`
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ArtistID   string `json:"artist_id"`
+	ArtistName string `json:"artist_name"`
+	AlbumID    string `json:"album_id,omitempty"`
+	Source     string `json:"source"`
+}
+
+// Keys returns the ordering keys used to space candidates apart
+func (c Candidate) Keys() ordering.Keys {
+	return ordering.Keys{Artist: c.ArtistID, Album: c.AlbumID}
+}
+
+// Pool is the set of candidates loaded for a single source
+type Pool struct {
+	Source     Source
+	Candidates []Candidate
+}
+
+// ParseSources parses a comma separated source specification such as
+// "saved:tracks=3,playlist:37i9dQZF1DXcBWIGoYBM5M=1,followed:artists=1".
+// The weight suffix is optional and defaults to 1.
+func ParseSources(spec string) ([]Source, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.NewValidationError("at least one source is required")
+	}
+
+	var sources []Source
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		source, err := parseSource(part)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	if len(sources) == 0 {
+		return nil, errors.NewValidationError("at least one source is required")
+	}
+
+	return sources, nil
+}
+
+func parseSource(part string) (Source, error) {
+	source := Source{Weight: 1}
+
+	if idx := strings.LastIndex(part, "="); idx >= 0 {
+		weight, err := strconv.Atoi(strings.TrimSpace(part[idx+1:]))
+		if err != nil || weight < 1 {
+			return Source{}, errors.NewValidationError(fmt.Sprintf("invalid weight in source %q: must be a positive integer", part))
+		}
+		source.Weight = weight
+		part = strings.TrimSpace(part[:idx])
+	}
+
+	switch part {
+	case SourceSavedTracks, SourceTopTracks, SourceFollowedArtists:
+		source.Kind = part
+		return source, nil
+	}
+
+	kind, id, found := strings.Cut(part, ":")
+	if !found || id == "" {
+		return Source{}, errors.NewValidationError(fmt.Sprintf("invalid source %q", part))
+	}
+
+	switch kind {
+	case SourcePlaylist, SourceAlbum, SourceTag:
+		source.Kind = kind
+		source.ID = id
+		return source, nil
+	default:
+		return Source{}, errors.NewValidationError(fmt.Sprintf("unknown source type %q. Must be one of: saved:tracks, top:tracks, followed:artists, playlist:<id>, album:<id>, tag:<name>", kind))
+	}
+}
+
+// Options controls how the queue is assembled
+type Options struct {
+	Length      int
+	Constraints ordering.Constraints
+	Seed        int64
+}
+
+// Build samples up to opts.Length tracks from the given pools. Each pick first
+// chooses a pool in proportion to its weight, then a random unused track from
+// that pool that satisfies the ordering constraints. Tracks never repeat; if
+// the chosen pool cannot satisfy the constraints its least-conflicting
+// candidate is used instead so that small libraries still produce a full queue.
+func Build(pools []Pool, opts Options) []Candidate {
+	if opts.Length <= 0 {
+		return nil
+	}
+
+	engine := ordering.NewEngine(opts.Constraints, opts.Seed)
+	rng := engine.Rand()
+
+	// Shuffle copies of each pool so picking from the front is random
+	remaining := make([][]Candidate, len(pools))
+	for i, pool := range pools {
+		candidates := make([]Candidate, len(pool.Candidates))
+		copy(candidates, pool.Candidates)
+		rng.Shuffle(len(candidates), func(a, b int) {
+			candidates[a], candidates[b] = candidates[b], candidates[a]
+		})
+		remaining[i] = candidates
+	}
+
+	used := make(map[string]bool)
+	var result []Candidate
+	var placed []ordering.Keys
+
+	for len(result) < opts.Length {
+		poolIdx := pickPool(pools, remaining, rng)
+		if poolIdx < 0 {
+			break
+		}
+
+		// Drop candidates already queued from another pool
+		unused := remaining[poolIdx][:0]
+		for _, candidate := range remaining[poolIdx] {
+			if !used[candidate.URI] {
+				unused = append(unused, candidate)
+			}
+		}
+		remaining[poolIdx] = unused
+
+		if len(unused) == 0 {
+			continue
+		}
+
+		keys := make([]ordering.Keys, len(unused))
+		for i, candidate := range unused {
+			keys[i] = candidate.Keys()
+		}
+
+		candidateIdx := engine.Pick(placed, keys)
+		candidate := unused[candidateIdx]
+		remaining[poolIdx] = append(unused[:candidateIdx], unused[candidateIdx+1:]...)
+
+		used[candidate.URI] = true
+		result = append(result, candidate)
+		placed = append(placed, keys[candidateIdx])
+	}
+
+	return result
+}
+
+// pickPool chooses a non-empty pool according to the pool weights
+func pickPool(pools []Pool, remaining [][]Candidate, rng *rand.Rand) int {
+	total := 0
+	for i, pool := range pools {
+		if len(remaining[i]) > 0 {
+			total += pool.Source.Weight
+		}
+	}
+
+	if total == 0 {
+		return -1
+	}
+
+	n := rng.Intn(total)
+	for i, pool := range pools {
+		if len(remaining[i]) == 0 {
+			continue
+		}
+		if n < pool.Source.Weight {
+			return i
+		}
+		n -= pool.Source.Weight
+	}
+
+	return -1
+}
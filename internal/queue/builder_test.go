@@ -0,0 +1,160 @@
+package queue
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/ordering"
+)
+
+func TestParseSources(t *testing.T) {
+	sources, err := ParseSources("saved:tracks=3,playlist:37i9dQZF1DXcBWIGoYBM5M=1,followed:artists")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sources) != 3 {
+		t.Fatalf("Expected 3 sources, got %d", len(sources))
+	}
+
+	if sources[0].Kind != SourceSavedTracks || sources[0].Weight != 3 {
+		t.Errorf("Expected saved:tracks with weight 3, got %s with weight %d", sources[0].Kind, sources[0].Weight)
+	}
+
+	if sources[1].Kind != SourcePlaylist || sources[1].ID != "37i9dQZF1DXcBWIGoYBM5M" {
+		t.Errorf("Expected playlist source with ID, got %s (%s)", sources[1].Kind, sources[1].ID)
+	}
+
+	if sources[2].Weight != 1 {
+		t.Errorf("Expected default weight 1, got %d", sources[2].Weight)
+	}
+
+	if sources[1].String() != "playlist:37i9dQZF1DXcBWIGoYBM5M" {
+		t.Errorf("Expected source string 'playlist:37i9dQZF1DXcBWIGoYBM5M', got %s", sources[1].String())
+	}
+
+	sources, err = ParseSources("tag:chill=2")
+	if err != nil || sources[0].Kind != SourceTag || sources[0].ID != "chill" || sources[0].Weight != 2 {
+		t.Errorf("Expected tag source chill with weight 2, got %+v (%v)", sources, err)
+	}
+}
+
+func TestParseSources_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"saved:tracks=0",
+		"saved:tracks=abc",
+		"playlist:",
+		"podcast:123",
+		"nonsense",
+	}
+
+	for _, spec := range tests {
+		if _, err := ParseSources(spec); err == nil {
+			t.Errorf("Expected error for source spec %q", spec)
+		}
+	}
+}
+
+func makePool(kind string, weight int, artists int, perArtist int) Pool {
+	pool := Pool{Source: Source{Kind: kind, Weight: weight}}
+	for a := 0; a < artists; a++ {
+		for n := 0; n < perArtist; n++ {
+			pool.Candidates = append(pool.Candidates, Candidate{
+				URI:      fmt.Sprintf("spotify:track:%s-%d-%d", kind, a, n),
+				ArtistID: fmt.Sprintf("%s-artist-%d", kind, a),
+				Source:   kind,
+			})
+		}
+	}
+	return pool
+}
+
+func TestBuild_NoRepeats(t *testing.T) {
+	pool := makePool("a", 1, 5, 4)
+	// Duplicate the same tracks into a second pool
+	dup := pool
+	dup.Source = Source{Kind: "b", Weight: 1}
+
+	result := Build([]Pool{pool, dup}, Options{Length: 50, Seed: 1})
+
+	if len(result) != 20 {
+		t.Fatalf("Expected 20 unique tracks, got %d", len(result))
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range result {
+		if seen[c.URI] {
+			t.Fatalf("Track %s was queued twice", c.URI)
+		}
+		seen[c.URI] = true
+	}
+}
+
+func TestBuild_ArtistSpacing(t *testing.T) {
+	pool := makePool("a", 1, 10, 5)
+
+	result := Build([]Pool{pool}, Options{Length: 30, Constraints: ordering.Constraints{ArtistSpacing: 3}, Seed: 42})
+
+	if len(result) != 30 {
+		t.Fatalf("Expected 30 tracks, got %d", len(result))
+	}
+
+	for i := range result {
+		for j := i + 1; j <= i+3 && j < len(result); j++ {
+			if result[i].ArtistID == result[j].ArtistID {
+				t.Fatalf("Artist %s repeated at positions %d and %d", result[i].ArtistID, i, j)
+			}
+		}
+	}
+}
+
+func TestBuild_SpacingRelaxedForSingleArtist(t *testing.T) {
+	pool := makePool("a", 1, 1, 5)
+
+	result := Build([]Pool{pool}, Options{Length: 5, Constraints: ordering.Constraints{ArtistSpacing: 3}, Seed: 7})
+
+	if len(result) != 5 {
+		t.Fatalf("Expected spacing to be relaxed and 5 tracks returned, got %d", len(result))
+	}
+}
+
+func TestBuild_Weights(t *testing.T) {
+	heavy := makePool("heavy", 3, 50, 10)
+	light := makePool("light", 1, 50, 10)
+
+	result := Build([]Pool{heavy, light}, Options{Length: 400, Seed: 3})
+
+	counts := make(map[string]int)
+	for _, c := range result {
+		counts[c.Source]++
+	}
+
+	ratio := float64(counts["heavy"]) / float64(counts["light"])
+	if ratio < 2.0 || ratio > 4.5 {
+		t.Errorf("Expected roughly 3:1 ratio between sources, got %d:%d", counts["heavy"], counts["light"])
+	}
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	pools := []Pool{makePool("a", 2, 5, 5), makePool("b", 1, 5, 5)}
+
+	first := Build(pools, Options{Length: 20, Constraints: ordering.Constraints{ArtistSpacing: 2}, Seed: 99})
+	second := Build(pools, Options{Length: 20, Constraints: ordering.Constraints{ArtistSpacing: 2}, Seed: 99})
+
+	for i := range first {
+		if first[i].URI != second[i].URI {
+			t.Fatalf("Expected identical output for identical seeds, differed at position %d", i)
+		}
+	}
+}
+
+func TestBuild_EmptyPools(t *testing.T) {
+	if result := Build(nil, Options{Length: 10}); len(result) != 0 {
+		t.Errorf("Expected empty result, got %d tracks", len(result))
+	}
+
+	if result := Build([]Pool{makePool("a", 1, 1, 1)}, Options{Length: 0}); result != nil {
+		t.Errorf("Expected nil result for zero length, got %d tracks", len(result))
+	}
+}
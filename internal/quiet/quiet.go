@@ -0,0 +1,79 @@
+// Package quiet keeps automation from disturbing a household at night:
+// during quiet hours scheduled and automatic playback is held back, the
+// volume of what plays is kept under a cap, and notifications wait until
+// the quiet hours are over.
+package quiet
+
+import (
+	"context"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+)
+
+// Hours are the quiet hours. A nil *Hours is never quiet.
+type Hours struct {
+	Windows []schedule.Window
+	// MaxVolume is the highest volume allowed during quiet hours, or -1 to
+	// leave the volume alone
+	MaxVolume int
+}
+
+// Active reports whether t is in quiet hours
+func (h *Hours) Active(t time.Time) bool {
+	if h == nil {
+		return false
+	}
+	for _, w := range h.Windows {
+		if _, ok := w.Covering(t); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Until returns when the quiet hours t is in end, following windows that
+// run into each other, or t itself when it is not in quiet hours
+func (h *Hours) Until(t time.Time) time.Time {
+	end := t
+	// Each round moves end past one more window; a window covering the
+	// whole week would never end
+	for round := 0; h != nil && round <= 7*len(h.Windows); round++ {
+		moved := false
+		for _, w := range h.Windows {
+			if start, ok := w.Covering(end); ok {
+				end = start.Add(w.Duration())
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return end
+}
+
+// Cap returns the volume allowed for volume at t, and whether it is lower
+func (h *Hours) Cap(volume int, t time.Time) (int, bool) {
+	if h == nil || h.MaxVolume < 0 || volume <= h.MaxVolume || !h.Active(t) {
+		return volume, false
+	}
+	return h.MaxVolume, true
+}
+
+// Wait blocks until t is out of quiet hours, or ctx is done
+func (h *Hours) Wait(ctx context.Context) error {
+	for {
+		now := time.Now()
+		if !h.Active(now) {
+			return nil
+		}
+		timer := time.NewTimer(h.Until(now).Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
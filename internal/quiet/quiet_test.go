@@ -0,0 +1,79 @@
+package quiet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/schedule"
+)
+
+func window(t *testing.T, days []string, start, end string) schedule.Window {
+	t.Helper()
+	w, err := schedule.NewWindow(days, start, end)
+	if err != nil {
+		t.Fatalf("NewWindow failed: %v", err)
+	}
+	return w
+}
+
+// at is a time on the day of June 2025 given
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, 6, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestActiveAndUntil(t *testing.T) {
+	h := &Hours{
+		Windows: []schedule.Window{
+			window(t, nil, "22:00", "07:00"),
+			// Sundays (8 June) stay quiet longer
+			window(t, []string{"sun"}, "06:30", "09:00"),
+		},
+		MaxVolume: -1,
+	}
+
+	if !h.Active(at(2, 23, 0)) || !h.Active(at(3, 6, 59)) {
+		t.Error("Expected the night to be quiet")
+	}
+	if h.Active(at(3, 7, 0)) || h.Active(at(3, 12, 0)) {
+		t.Error("Expected the day not to be quiet")
+	}
+	if until := h.Until(at(2, 23, 0)); !until.Equal(at(3, 7, 0)) {
+		t.Errorf("Expected quiet until 07:00, got %v", until)
+	}
+	if until := h.Until(at(8, 2, 0)); !until.Equal(at(8, 9, 0)) {
+		t.Errorf("Expected the overlapping Sunday window to extend quiet hours, got %v", until)
+	}
+	if until := h.Until(at(3, 12, 0)); !until.Equal(at(3, 12, 0)) {
+		t.Errorf("Expected no quiet hours at noon, got %v", until)
+	}
+
+	var none *Hours
+	if none.Active(at(2, 23, 0)) {
+		t.Error("Expected nil hours never to be quiet")
+	}
+}
+
+func TestCap(t *testing.T) {
+	h := &Hours{Windows: []schedule.Window{window(t, nil, "22:00", "07:00")}, MaxVolume: 20}
+	if v, lowered := h.Cap(60, at(2, 23, 0)); v != 20 || !lowered {
+		t.Errorf("Expected 60 capped to 20, got %d %v", v, lowered)
+	}
+	if v, lowered := h.Cap(10, at(2, 23, 0)); v != 10 || lowered {
+		t.Errorf("Expected 10 left alone, got %d %v", v, lowered)
+	}
+	if v, lowered := h.Cap(60, at(2, 12, 0)); v != 60 || lowered {
+		t.Errorf("Expected no cap by day, got %d %v", v, lowered)
+	}
+	h.MaxVolume = -1
+	if _, lowered := h.Cap(60, at(2, 23, 0)); lowered {
+		t.Error("Expected no cap without a maximum")
+	}
+}
+
+func TestWaitOutsideQuietHours(t *testing.T) {
+	var h *Hours
+	if err := h.Wait(context.Background()); err != nil {
+		t.Errorf("Expected Wait to return at once, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+// Package quota keeps a rolling record of how many requests each command
+// sent to the Spotify API, how often it was rate limited and how well the
+// response cache served it. The figures outlive the process that produced
+// them, so 'quota' can show the load built up by a series of commands.
+package quota
+
+import (
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding usage samples
+const StoreName = "quota"
+
+// Retention is how long samples are kept
+const Retention = 7 * 24 * time.Hour
+
+// MaxSamples caps the number of samples kept, dropping the oldest first
+const MaxSamples = 5000
+
+// Sample is the API usage of one command
+type Sample struct {
+	Time             time.Time     `json:"time" yaml:"time"`
+	Command          string        `json:"command" yaml:"command"`
+	Requests         int           `json:"requests" yaml:"requests"`
+	RateLimited      int           `json:"rate_limited" yaml:"rate_limited"`
+	Retries          int           `json:"retries" yaml:"retries"`
+	Backoff          time.Duration `json:"backoff" yaml:"backoff"`
+	CacheHits        int           `json:"cache_hits" yaml:"cache_hits"`
+	CacheRevalidated int           `json:"cache_revalidated" yaml:"cache_revalidated"`
+	CacheMisses      int           `json:"cache_misses" yaml:"cache_misses"`
+	LastRateLimit    time.Time     `json:"last_rate_limit,omitempty" yaml:"last_rate_limit,omitempty"`
+	RetryAfter       time.Time     `json:"retry_after,omitempty" yaml:"retry_after,omitempty"`
+}
+
+// Empty reports whether the command made no API calls at all
+func (s Sample) Empty() bool {
+	return s.Requests == 0 && s.CacheHits == 0 && s.CacheRevalidated == 0
+}
+
+type store struct {
+	Samples []Sample `json:"samples"`
+}
+
+// Record adds a sample, dropping samples older than Retention
+func Record(dir *state.Dir, sample Sample) error {
+	if sample.Time.IsZero() {
+		sample.Time = time.Now().UTC()
+	}
+
+	var data store
+	return dir.Update(StoreName, &data, func() error {
+		cutoff := sample.Time.Add(-Retention)
+		kept := data.Samples[:0]
+		for _, s := range data.Samples {
+			if s.Time.After(cutoff) {
+				kept = append(kept, s)
+			}
+		}
+
+		kept = append(kept, sample)
+		if len(kept) > MaxSamples {
+			kept = kept[len(kept)-MaxSamples:]
+		}
+		data.Samples = kept
+		return nil
+	})
+}
+
+// Load returns every kept sample, oldest first
+func Load(dir *state.Dir) ([]Sample, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	return data.Samples, nil
+}
+
+// Summary totals the samples in a time window
+type Summary struct {
+	Since            time.Time     `json:"since" yaml:"since"`
+	Commands         int           `json:"commands" yaml:"commands"`
+	Requests         int           `json:"requests" yaml:"requests"`
+	RateLimited      int           `json:"rate_limited" yaml:"rate_limited"`
+	Retries          int           `json:"retries" yaml:"retries"`
+	Backoff          time.Duration `json:"backoff" yaml:"backoff"`
+	CacheHits        int           `json:"cache_hits" yaml:"cache_hits"`
+	CacheRevalidated int           `json:"cache_revalidated" yaml:"cache_revalidated"`
+	CacheMisses      int           `json:"cache_misses" yaml:"cache_misses"`
+}
+
+// CacheHitRate returns the share of cacheable requests answered from the
+// cache, counting revalidated responses as hits, or -1 if there were none
+func (s Summary) CacheHitRate() float64 {
+	served := s.CacheHits + s.CacheRevalidated
+	total := served + s.CacheMisses
+	if total == 0 {
+		return -1
+	}
+	return float64(served) / float64(total)
+}
+
+// Summarize totals the samples taken at or after since
+func Summarize(samples []Sample, since time.Time) Summary {
+	summary := Summary{Since: since}
+	for _, s := range samples {
+		if s.Time.Before(since) {
+			continue
+		}
+		summary.Commands++
+		summary.Requests += s.Requests
+		summary.RateLimited += s.RateLimited
+		summary.Retries += s.Retries
+		summary.Backoff += s.Backoff
+		summary.CacheHits += s.CacheHits
+		summary.CacheRevalidated += s.CacheRevalidated
+		summary.CacheMisses += s.CacheMisses
+	}
+	return summary
+}
+
+// CommandUsage is the load put on the API by one command
+type CommandUsage struct {
+	Command     string `json:"command" yaml:"command"`
+	Runs        int    `json:"runs" yaml:"runs"`
+	Requests    int    `json:"requests" yaml:"requests"`
+	RateLimited int    `json:"rate_limited" yaml:"rate_limited"`
+}
+
+// TopCommands returns the commands that sent the most requests since the
+// given time, busiest first
+func TopCommands(samples []Sample, since time.Time, n int) []CommandUsage {
+	byCommand := make(map[string]*CommandUsage)
+	for _, s := range samples {
+		if s.Time.Before(since) {
+			continue
+		}
+		usage, ok := byCommand[s.Command]
+		if !ok {
+			usage = &CommandUsage{Command: s.Command}
+			byCommand[s.Command] = usage
+		}
+		usage.Runs++
+		usage.Requests += s.Requests
+		usage.RateLimited += s.RateLimited
+	}
+
+	result := make([]CommandUsage, 0, len(byCommand))
+	for _, usage := range byCommand {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Command < result[j].Command
+	})
+
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// LastRateLimit returns the most recent sample that was rate limited
+func LastRateLimit(samples []Sample) (Sample, bool) {
+	var last Sample
+	found := false
+	for _, s := range samples {
+		if s.RateLimited > 0 && (!found || s.LastRateLimit.After(last.LastRateLimit)) {
+			last = s
+			found = true
+		}
+	}
+	return last, found
+}
+
+// BackoffUntil returns when the latest rate limit cooldown ends, or the zero
+// time if no cooldown is still running at now
+func BackoffUntil(samples []Sample, now time.Time) time.Time {
+	var until time.Time
+	for _, s := range samples {
+		if s.RetryAfter.After(now) && s.RetryAfter.After(until) {
+			until = s.RetryAfter
+		}
+	}
+	return until
+}
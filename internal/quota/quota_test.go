@@ -0,0 +1,79 @@
+package quota
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRecord_DropsOldSamples(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	now := time.Now().UTC()
+	samples := []Sample{
+		{Time: now.Add(-8 * 24 * time.Hour), Command: "old", Requests: 100},
+		{Time: now.Add(-2 * time.Hour), Command: "lidarr import-saved", Requests: 40, RateLimited: 2},
+		{Time: now, Command: "library tracks", Requests: 3},
+	}
+	for _, s := range samples {
+		if err := Record(dir, s); err != nil {
+			t.Fatalf("Failed to record: %v", err)
+		}
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Command != "lidarr import-saved" {
+		t.Errorf("Expected the 8-day-old sample to be dropped, got %+v", loaded)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	samples := []Sample{
+		{Time: now.Add(-3 * time.Hour), Command: "lidarr import-saved", Requests: 40, RateLimited: 2, Retries: 2, Backoff: 4 * time.Second,
+			CacheMisses: 30, LastRateLimit: now.Add(-3 * time.Hour)},
+		{Time: now.Add(-10 * time.Minute), Command: "library tracks", Requests: 2, CacheHits: 5, CacheRevalidated: 1, CacheMisses: 2},
+		{Time: now.Add(-5 * time.Minute), Command: "library tracks", Requests: 1, RetryAfter: now.Add(30 * time.Second)},
+	}
+
+	hour := Summarize(samples, now.Add(-time.Hour))
+	if hour.Commands != 2 || hour.Requests != 3 || hour.RateLimited != 0 {
+		t.Errorf("Unexpected hourly summary %+v", hour)
+	}
+	if rate := hour.CacheHitRate(); rate != 0.75 {
+		t.Errorf("Expected 75%% hit rate, got %v", rate)
+	}
+
+	day := Summarize(samples, now.Add(-24*time.Hour))
+	if day.Requests != 43 || day.RateLimited != 2 || day.Backoff != 4*time.Second {
+		t.Errorf("Unexpected daily summary %+v", day)
+	}
+
+	if rate := Summarize(nil, now).CacheHitRate(); rate != -1 {
+		t.Errorf("Expected -1 hit rate with no requests, got %v", rate)
+	}
+
+	top := TopCommands(samples, now.Add(-24*time.Hour), 1)
+	if len(top) != 1 || top[0].Command != "lidarr import-saved" || top[0].Requests != 40 {
+		t.Errorf("Unexpected top commands %+v", top)
+	}
+
+	if last, ok := LastRateLimit(samples); !ok || last.Command != "lidarr import-saved" {
+		t.Errorf("Unexpected last rate limit %+v", last)
+	}
+
+	if until := BackoffUntil(samples, now); !until.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("Expected cooldown to end in 30s, got %v", until)
+	}
+	if until := BackoffUntil(samples, now.Add(time.Minute)); !until.IsZero() {
+		t.Errorf("Expected no cooldown after it ended, got %v", until)
+	}
+}
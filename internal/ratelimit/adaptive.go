@@ -0,0 +1,258 @@
+package ratelimit
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// Adaptive concurrency defaults
+const (
+	// DefaultLatencyFactor is how many times slower than the fastest call seen
+	// a call may be before it counts as a sign of overload
+	DefaultLatencyFactor = 4.0
+	// DefaultMinSlowLatency keeps fast services from treating ordinary jitter
+	// as overload
+	DefaultMinSlowLatency = 2 * time.Second
+)
+
+// AdaptiveLimiter bounds the number of calls in flight and adjusts the bound
+// with AIMD: every call that succeeds promptly grows it by about one per
+// round of calls, and a rate limit, server error or unusually slow call
+// halves it. The bound never exceeds the maximum it was created with.
+type AdaptiveLimiter struct {
+	mu       sync.Mutex
+	changed  chan struct{}
+	limit    float64
+	max      int
+	inFlight int
+
+	latencyFactor  float64
+	minSlowLatency time.Duration
+	fastest        time.Duration
+
+	// lastDecrease stops one burst of failures, all from calls started
+	// under the old limit, from halving the limit again and again
+	lastDecrease time.Time
+
+	stats AdaptiveStats
+}
+
+// AdaptiveStats describes how an AdaptiveLimiter has behaved
+type AdaptiveStats struct {
+	Limit     int // current concurrency limit
+	Max       int // the cap the limit cannot exceed
+	Lowest    int // lowest limit reached
+	Decreases int // times the limit was cut
+	Overloads int // calls that signalled overload
+}
+
+// NewAdaptiveLimiter creates a limiter that starts at, and never exceeds,
+// maxConcurrency calls in flight
+func NewAdaptiveLimiter(maxConcurrency int) *AdaptiveLimiter {
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	return &AdaptiveLimiter{
+		changed:        make(chan struct{}),
+		limit:          float64(maxConcurrency),
+		max:            maxConcurrency,
+		latencyFactor:  DefaultLatencyFactor,
+		minSlowLatency: DefaultMinSlowLatency,
+		stats:          AdaptiveStats{Max: maxConcurrency, Lowest: maxConcurrency},
+	}
+}
+
+// SetLatencyThreshold changes when a successful call is slow enough to count
+// as overload: slower than factor times the fastest call and at least min.
+// A factor of zero turns the latency signal off.
+func (a *AdaptiveLimiter) SetLatencyThreshold(factor float64, min time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.latencyFactor = factor
+	a.minSlowLatency = min
+}
+
+// Permit is one call admitted by an AdaptiveLimiter
+type Permit struct {
+	limiter *AdaptiveLimiter
+	started time.Time
+	once    sync.Once
+}
+
+// Acquire blocks until another call may start or ctx is cancelled
+func (a *AdaptiveLimiter) Acquire(ctx context.Context) (*Permit, error) {
+	for {
+		a.mu.Lock()
+		if a.inFlight < int(a.limit) {
+			a.inFlight++
+			a.mu.Unlock()
+			return &Permit{limiter: a, started: time.Now()}, nil
+		}
+		changed := a.changed
+		a.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Release ends the call, reporting how it went. err is the call's error, if
+// any; errors that show the service is overloaded shrink the limit.
+func (p *Permit) Release(err error) {
+	p.once.Do(func() {
+		p.limiter.release(p.started, time.Since(p.started), err)
+	})
+}
+
+// Do runs fn once a call may start, and reports its outcome
+func (a *AdaptiveLimiter) Do(ctx context.Context, fn func() error) error {
+	permit, err := a.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	permit.Release(err)
+	return err
+}
+
+func (a *AdaptiveLimiter) release(started time.Time, latency time.Duration, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.inFlight--
+
+	overloaded := IsOverloadError(err)
+	if err == nil {
+		if a.fastest == 0 || latency < a.fastest {
+			a.fastest = latency
+		}
+		if a.latencyFactor > 0 && latency >= a.minSlowLatency &&
+			float64(latency) > a.latencyFactor*float64(a.fastest) {
+			overloaded = true
+		}
+	}
+
+	switch {
+	case overloaded:
+		a.stats.Overloads++
+		if started.After(a.lastDecrease) {
+			a.limit /= 2
+			if a.limit < 1 {
+				a.limit = 1
+			}
+			a.lastDecrease = time.Now()
+			a.stats.Decreases++
+			if int(a.limit) < a.stats.Lowest {
+				a.stats.Lowest = int(a.limit)
+			}
+		}
+	case err == nil:
+		a.limit += 1 / a.limit
+		if a.limit > float64(a.max) {
+			a.limit = float64(a.max)
+		}
+	}
+
+	// Wake every waiter so they can re-check against the new limit
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// ForEach calls fn for every index below n, starting each call once the
+// limiter admits it. The first error cancels the context given to the calls
+// still running and is returned once they finish.
+func (a *AdaptiveLimiter) ForEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		permit, err := a.Acquire(ctx)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := fn(ctx, i)
+			permit.Release(err)
+			if err != nil {
+				fail(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// Limit returns the current concurrency limit
+func (a *AdaptiveLimiter) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return int(a.limit)
+}
+
+// Stats returns the limiter's current state and history
+func (a *AdaptiveLimiter) Stats() AdaptiveStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.stats
+	stats.Limit = int(a.limit)
+	return stats
+}
+
+// statusPattern finds the HTTP status in errors such as
+// "API request failed with status 503" or "failed to add artist, status: 429"
+var statusPattern = regexp.MustCompile(`status:? (\d{3})\b`)
+
+// IsOverloadError reports whether err shows the remote service is shedding
+// load: a 429 or 5xx response, or a rate limit reported by the Spotify client
+func IsOverloadError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if status := errors.StatusCode(err); status == 429 || status >= 500 {
+		return true
+	}
+
+	message := err.Error()
+	if strings.Contains(strings.ToLower(message), "rate limit") {
+		return true
+	}
+
+	for _, match := range statusPattern.FindAllStringSubmatch(message, -1) {
+		status, _ := strconv.Atoi(match[1])
+		if status == 429 || status >= 500 {
+			return true
+		}
+	}
+
+	return false
+}
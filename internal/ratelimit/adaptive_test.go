@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	limiter := NewAdaptiveLimiter(8)
+	limiter.SetLatencyThreshold(0, 0)
+	ctx := context.Background()
+
+	if limiter.Limit() != 8 {
+		t.Fatalf("Expected to start at the cap, got %d", limiter.Limit())
+	}
+
+	overload := errors.New("API request failed with status 503")
+	limiter.Do(ctx, func() error { return overload })
+	if limiter.Limit() != 4 {
+		t.Errorf("Expected limit to halve to 4, got %d", limiter.Limit())
+	}
+
+	// Failures from calls started before the cut do not cut again
+	permits := make([]*Permit, 3)
+	for i := range permits {
+		permits[i], _ = limiter.Acquire(ctx)
+	}
+	time.Sleep(time.Millisecond)
+	limiter.Do(ctx, func() error { return overload })
+	if limiter.Limit() != 2 {
+		t.Fatalf("Expected limit 2, got %d", limiter.Limit())
+	}
+	for _, p := range permits {
+		p.Release(overload)
+	}
+	if limiter.Limit() != 2 {
+		t.Errorf("Expected calls started before the cut not to cut again, got %d", limiter.Limit())
+	}
+
+	// Successes grow the limit back, but never past the cap
+	for i := 0; i < 200; i++ {
+		limiter.Do(ctx, func() error { return nil })
+	}
+	stats := limiter.Stats()
+	if stats.Limit != 8 || stats.Decreases != 2 || stats.Lowest != 2 || stats.Overloads != 5 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Errors that are not about load leave the limit alone
+	limiter.Do(ctx, func() error { return errors.New("artist already exists") })
+	if limiter.Limit() != 8 {
+		t.Errorf("Expected other errors not to change the limit, got %d", limiter.Limit())
+	}
+}
+
+func TestAdaptiveLimiter_BoundsInFlight(t *testing.T) {
+	limiter := NewAdaptiveLimiter(3)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Do(ctx, func() error {
+				mu.Lock()
+				inFlight++
+				if inFlight > peak {
+					peak = inFlight
+				}
+				mu.Unlock()
+
+				time.Sleep(2 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if peak > 3 {
+		t.Errorf("Expected at most 3 calls in flight, got %d", peak)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	held := make([]*Permit, 3)
+	for i := range held {
+		held[i], _ = limiter.Acquire(ctx)
+	}
+	if _, err := limiter.Acquire(cancelled); err == nil {
+		t.Error("Expected Acquire to give up when the context is cancelled")
+	}
+}
+
+func TestAdaptiveLimiter_SlowCalls(t *testing.T) {
+	limiter := NewAdaptiveLimiter(4)
+	limiter.SetLatencyThreshold(4, 10*time.Millisecond)
+	ctx := context.Background()
+
+	limiter.Do(ctx, func() error { return nil })
+	limiter.Do(ctx, func() error {
+		time.Sleep(30 * time.Millisecond)
+		return nil
+	})
+
+	if limiter.Limit() != 2 {
+		t.Errorf("Expected a slow call to halve the limit, got %d", limiter.Limit())
+	}
+}
+
+func TestAdaptiveLimiter_ForEach(t *testing.T) {
+	limiter := NewAdaptiveLimiter(4)
+	ctx := context.Background()
+
+	done := make([]bool, 10)
+	err := limiter.ForEach(ctx, len(done), func(ctx context.Context, i int) error {
+		if i%3 == 0 {
+			return apierrors.NewStatusError(429, "rate limited")
+		}
+		done[i] = true
+		return nil
+	})
+	if err == nil || apierrors.StatusCode(err) != 429 {
+		t.Errorf("Expected the first error back, got %v", err)
+	}
+	if limiter.Limit() >= 4 {
+		t.Errorf("Expected rate limits to shrink the limit, got %d", limiter.Limit())
+	}
+
+	limiter = NewAdaptiveLimiter(4)
+	var mu sync.Mutex
+	calls := 0
+	err = limiter.ForEach(ctx, 25, func(ctx context.Context, i int) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil
+	})
+	if err != nil || calls != 25 {
+		t.Errorf("Expected 25 calls and no error, got %d and %v", calls, err)
+	}
+}
+
+func TestIsOverloadError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("API request failed with status 503"), true},
+		{errors.New("failed to add artist, status: 429"), true},
+		{errors.New("rate limited until 12:00"), true},
+		{errors.New("API request failed with status 404"), false},
+		{errors.New("artist already exists: Radiohead"), false},
+		{apierrors.NewStatusError(502, "HTTP 502: Bad gateway"), true},
+		{apierrors.NewStatusError(404, "HTTP 404: Not found"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsOverloadError(tt.err); got != tt.want {
+			t.Errorf("IsOverloadError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
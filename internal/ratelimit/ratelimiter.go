@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"sync"
@@ -12,6 +13,12 @@ import (
 	"github.com/bambithedeer/spotify-api/internal/errors"
 )
 
+// Spotify API rate limiter defaults
+const (
+	DefaultBurst          = 100                    // Spotify allows ~100 requests per minute in bursts
+	DefaultRefillInterval = 600 * time.Millisecond // 1 token every 600ms (100 per minute)
+)
+
 // RateLimiter manages rate limiting for Spotify API requests
 type RateLimiter struct {
 	mu                sync.RWMutex
@@ -28,9 +35,9 @@ type RateLimiter struct {
 // NewRateLimiter creates a new rate limiter with Spotify API defaults
 func NewRateLimiter() *RateLimiter {
 	return &RateLimiter{
-		tokens:         100,           // Start with full bucket
-		maxTokens:      100,           // Spotify allows ~100 requests per minute in bursts
-		refillRate:     600 * time.Millisecond, // Refill 1 token every 600ms (100 per minute)
+		tokens:         DefaultBurst, // Start with full bucket
+		maxTokens:      DefaultBurst,
+		refillRate:     DefaultRefillInterval,
 		lastRefill:     time.Now(),
 		maxRetries:     3,
 		baseRetryDelay: 1 * time.Second,
@@ -126,8 +133,8 @@ func (rl *RateLimiter) HandleRateLimitResponse(resp *http.Response) error {
 	// Parse Retry-After header
 	retryAfterStr := resp.Header.Get("Retry-After")
 	if retryAfterStr != "" {
-		if seconds, err := strconv.Atoi(retryAfterStr); err == nil {
-			rl.retryAfter = time.Now().Add(time.Duration(seconds) * time.Second)
+		if delay, ok := ParseRetryAfter(retryAfterStr, time.Now()); ok {
+			rl.retryAfter = time.Now().Add(delay)
 		}
 	} else {
 		// Default backoff if no Retry-After header
@@ -137,7 +144,7 @@ func (rl *RateLimiter) HandleRateLimitResponse(resp *http.Response) error {
 	// Reset token bucket
 	rl.tokens = 0
 
-	return errors.NewAPIError(fmt.Sprintf("rate limited until %v", rl.retryAfter))
+	return errors.NewStatusError(http.StatusTooManyRequests, fmt.Sprintf("rate limited until %v", rl.retryAfter))
 }
 
 // refillTokens adds tokens based on elapsed time (must be called with lock held)
@@ -167,13 +174,46 @@ func (rl *RateLimiter) GetStatus() (availableTokens int, maxTokens int, retryAft
 	return rl.tokens, rl.maxTokens, rl.retryAfter
 }
 
+// ParseRetryAfter parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
 // RetryConfig holds configuration for retry behavior
 type RetryConfig struct {
-	MaxRetries     int
-	BaseDelay      time.Duration
-	MaxDelay       time.Duration
-	BackoffFactor  float64
+	MaxRetries      int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	BackoffFactor   float64
 	RetryableErrors map[int]bool // HTTP status codes that should trigger retries
+
+	// Jitter randomizes backoff delays by up to this fraction (0-1) so that
+	// concurrent clients do not retry in lockstep
+	Jitter float64
+
+	// MaxRetryAfter is the longest Retry-After the client will wait out.
+	// Longer waits fail immediately instead of blocking the command.
+	MaxRetryAfter time.Duration
 }
 
 // DefaultRetryConfig returns default retry configuration for Spotify API
@@ -183,6 +223,8 @@ func DefaultRetryConfig() *RetryConfig {
 		BaseDelay:     1 * time.Second,
 		MaxDelay:      30 * time.Second,
 		BackoffFactor: 2.0,
+		Jitter:        0.2,
+		MaxRetryAfter: 2 * time.Minute,
 		RetryableErrors: map[int]bool{
 			http.StatusTooManyRequests:     true, // 429
 			http.StatusInternalServerError: true, // 500
@@ -209,13 +251,8 @@ func (rc *RetryConfig) ShouldRetry(resp *http.Response, attempt int) bool {
 // GetRetryDelay calculates the delay before next retry attempt
 func (rc *RetryConfig) GetRetryDelay(attempt int, resp *http.Response) time.Duration {
 	// Check for Retry-After header first
-	if resp != nil && resp.Header.Get("Retry-After") != "" {
-		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
-			delay := time.Duration(seconds) * time.Second
-			if delay <= rc.MaxDelay {
-				return delay
-			}
-		}
+	if delay, ok := rc.RetryAfter(resp); ok && delay <= rc.maxRetryAfter() {
+		return delay
 	}
 
 	// Calculate exponential backoff
@@ -227,6 +264,49 @@ func (rc *RetryConfig) GetRetryDelay(attempt int, resp *http.Response) time.Dura
 	return delay
 }
 
+// RetryAfter returns the delay requested by the response's Retry-After header
+func (rc *RetryConfig) RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	return ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+}
+
+// ExceedsMaxRetryAfter reports whether the server asked us to wait longer
+// than the configuration allows
+func (rc *RetryConfig) ExceedsMaxRetryAfter(resp *http.Response) bool {
+	delay, ok := rc.RetryAfter(resp)
+	return ok && delay > rc.maxRetryAfter()
+}
+
+// NextDelay returns the delay before the next attempt with jitter applied.
+// A Retry-After from the server is always waited out in full; jitter only
+// ever adds to it.
+func (rc *RetryConfig) NextDelay(attempt int, resp *http.Response) time.Duration {
+	delay := rc.GetRetryDelay(attempt, resp)
+	if rc.Jitter <= 0 {
+		return delay
+	}
+
+	jitter := rc.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+
+	if _, ok := rc.RetryAfter(resp); ok {
+		return delay + time.Duration(rand.Float64()*jitter*float64(rc.BaseDelay))
+	}
+
+	return delay - time.Duration(rand.Float64()*jitter*float64(delay))
+}
+
+func (rc *RetryConfig) maxRetryAfter() time.Duration {
+	if rc.MaxRetryAfter > 0 {
+		return rc.MaxRetryAfter
+	}
+	return rc.MaxDelay
+}
+
 // Helper function for min (Go 1.21+ has this built-in)
 func min(a, b int) int {
 	if a < b {
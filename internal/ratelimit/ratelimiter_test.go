@@ -293,4 +293,58 @@ func TestRefillTokens(t *testing.T) {
 	if available < 4 { // Allow some tolerance
 		t.Errorf("Expected at least 4 tokens after refill, got %d", available)
 	}
-}
\ No newline at end of file
+}
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if delay, ok := ParseRetryAfter("7", now); !ok || delay != 7*time.Second {
+		t.Errorf("Expected 7s from seconds value, got %v (ok=%v)", delay, ok)
+	}
+
+	date := now.Add(90 * time.Second).Format(http.TimeFormat)
+	if delay, ok := ParseRetryAfter(date, now); !ok || delay != 90*time.Second {
+		t.Errorf("Expected 90s from HTTP date, got %v (ok=%v)", delay, ok)
+	}
+
+	for _, value := range []string{"", "soon", "-5"} {
+		if _, ok := ParseRetryAfter(value, now); ok {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestNextDelayJitter(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.Jitter = 0.5
+
+	for i := 0; i < 50; i++ {
+		delay := config.NextDelay(2, nil)
+		if delay < 2*time.Second || delay > 4*time.Second {
+			t.Fatalf("Expected jittered delay between 2s and 4s, got %v", delay)
+		}
+	}
+
+	resp := &http.Response{StatusCode: 429, Header: make(http.Header)}
+	resp.Header.Set("Retry-After", "3")
+	for i := 0; i < 50; i++ {
+		delay := config.NextDelay(0, resp)
+		if delay < 3*time.Second || delay > 3*time.Second+config.BaseDelay {
+			t.Fatalf("Expected Retry-After to be honoured with additive jitter, got %v", delay)
+		}
+	}
+}
+
+func TestExceedsMaxRetryAfter(t *testing.T) {
+	config := DefaultRetryConfig()
+	resp := &http.Response{StatusCode: 429, Header: make(http.Header)}
+
+	resp.Header.Set("Retry-After", "60")
+	if config.ExceedsMaxRetryAfter(resp) {
+		t.Error("Expected 60s to be within the default limit")
+	}
+
+	resp.Header.Set("Retry-After", "3600")
+	if !config.ExceedsMaxRetryAfter(resp) {
+		t.Error("Expected one hour to exceed the default limit")
+	}
+}
@@ -0,0 +1,152 @@
+// Package ratings keeps local one to five star ratings of tracks and albums,
+// which Spotify itself has no notion of.
+package ratings
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding ratings
+const StoreName = "ratings"
+
+// MinStars and MaxStars bound a rating
+const (
+	MinStars = 1
+	MaxStars = 5
+)
+
+var uriPattern = regexp.MustCompile(`^spotify:(track|album):[0-9A-Za-z]{22}$`)
+
+// Rating is the stars given to a track or album
+type Rating struct {
+	URI     string    `json:"uri" yaml:"uri"`
+	Stars   int       `json:"stars" yaml:"stars"`
+	RatedAt time.Time `json:"rated_at" yaml:"rated_at"`
+}
+
+// IsTrack reports whether the rating is of a track
+func (r Rating) IsTrack() bool {
+	m := uriPattern.FindStringSubmatch(r.URI)
+	return m != nil && m[1] == "track"
+}
+
+// Stars renders a rating as filled and empty stars, like "★★★☆☆"
+func Stars(n int) string {
+	s := ""
+	for i := MinStars; i <= MaxStars; i++ {
+		if i <= n {
+			s += "★"
+		} else {
+			s += "☆"
+		}
+	}
+	return s
+}
+
+type store struct {
+	// Version goes up on every change, so a long-running sync can tell
+	// whether there is anything new to write
+	Version int               `json:"version"`
+	Ratings map[string]Rating `json:"ratings"`
+}
+
+// ValidateURI checks that uri is a track or album URI
+func ValidateURI(uri string) error {
+	if !uriPattern.MatchString(uri) {
+		return errors.NewValidationError(fmt.Sprintf("only tracks and albums can be rated, got %q", uri))
+	}
+	return nil
+}
+
+// Set rates an item, replacing any earlier rating
+func Set(dir *state.Dir, uri string, stars int, now time.Time) (*Rating, error) {
+	if err := ValidateURI(uri); err != nil {
+		return nil, err
+	}
+	if stars < MinStars || stars > MaxStars {
+		return nil, errors.NewValidationError(fmt.Sprintf("rating must be between %d and %d stars", MinStars, MaxStars))
+	}
+
+	rating := Rating{URI: uri, Stars: stars, RatedAt: now.UTC()}
+	var data store
+	err := dir.Update(StoreName, &data, func() error {
+		if data.Ratings == nil {
+			data.Ratings = make(map[string]Rating)
+		}
+		data.Ratings[uri] = rating
+		data.Version++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// Clear removes the rating of an item and reports whether it had one
+func Clear(dir *state.Dir, uri string) (bool, error) {
+	found := false
+	var data store
+	err := dir.Update(StoreName, &data, func() error {
+		if _, found = data.Ratings[uri]; found {
+			delete(data.Ratings, uri)
+			data.Version++
+		}
+		return nil
+	})
+	return found, err
+}
+
+// Get returns the rating of an item, if it has one
+func Get(dir *state.Dir, uri string) (Rating, bool, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return Rating{}, false, err
+	}
+	rating, ok := data.Ratings[uri]
+	return rating, ok, nil
+}
+
+// AtLeast returns the ratings of min stars or more, highest first and the
+// most recently rated first among equals. It also returns the store version.
+func AtLeast(dir *state.Dir, min int) ([]Rating, int, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, 0, err
+	}
+
+	var ratings []Rating
+	for _, rating := range data.Ratings {
+		if rating.Stars >= min {
+			ratings = append(ratings, rating)
+		}
+	}
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Stars != ratings[j].Stars {
+			return ratings[i].Stars > ratings[j].Stars
+		}
+		if !ratings[i].RatedAt.Equal(ratings[j].RatedAt) {
+			return ratings[i].RatedAt.After(ratings[j].RatedAt)
+		}
+		return ratings[i].URI < ratings[j].URI
+	})
+	return ratings, data.Version, nil
+}
+
+// Distribution counts the ratings given at each number of stars, indexed
+// by stars
+func Distribution(ratings []Rating) [MaxStars + 1]int {
+	var counts [MaxStars + 1]int
+	for _, rating := range ratings {
+		if rating.Stars >= MinStars && rating.Stars <= MaxStars {
+			counts[rating.Stars]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,85 @@
+package ratings
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRatings(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	now := time.Now()
+
+	const (
+		good  = "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"
+		great = "spotify:track:0VjIjW4GlUZAMYd2vXMi3b"
+		album = "spotify:album:4aawyAB9vmqN3uQ7FjRGTy"
+	)
+
+	for _, tt := range []struct {
+		uri   string
+		stars int
+		at    time.Time
+	}{
+		{good, 2, now},
+		{good, 4, now.Add(time.Minute)},
+		{great, 5, now},
+		{album, 4, now.Add(2 * time.Minute)},
+	} {
+		if _, err := Set(dir, tt.uri, tt.stars, tt.at); err != nil {
+			t.Fatalf("Failed to rate %s: %v", tt.uri, err)
+		}
+	}
+
+	rating, ok, err := Get(dir, good)
+	if err != nil || !ok || rating.Stars != 4 {
+		t.Errorf("Expected the second rating to replace the first, got %+v (%v)", rating, err)
+	}
+
+	top, version, err := AtLeast(dir, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 3 || top[0].URI != great || top[1].URI != album || top[2].URI != good {
+		t.Errorf("Unexpected order %+v", top)
+	}
+	if top[1].IsTrack() || !top[0].IsTrack() {
+		t.Error("Expected IsTrack to tell tracks from albums")
+	}
+
+	if found, err := Clear(dir, good); err != nil || !found {
+		t.Errorf("Expected to clear the rating, got %v, %v", found, err)
+	}
+	if found, _ := Clear(dir, good); found {
+		t.Error("Expected a second clear to find nothing")
+	}
+	if _, newVersion, _ := AtLeast(dir, 1); newVersion != version+1 {
+		t.Errorf("Expected the version to go up once, from %d to %d", version, newVersion)
+	}
+
+	if counts := Distribution(top); counts[5] != 1 || counts[4] != 2 {
+		t.Errorf("Unexpected distribution %v", counts)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Set(dir, "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", 6, time.Now()); err == nil {
+		t.Error("Expected 6 stars to be rejected")
+	}
+	if _, err := Set(dir, "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M", 3, time.Now()); err == nil {
+		t.Error("Expected playlists to be rejected")
+	}
+	if Stars(3) != "★★★☆☆" {
+		t.Errorf("Unexpected stars %q", Stars(3))
+	}
+}
@@ -0,0 +1,278 @@
+// Package schedule picks what should play from rules that cover times of
+// day on days of the week, such as a morning playlist on weekdays and a mix
+// on Friday evenings, and remembers which slot was last started so each one
+// is applied once rather than every time the schedule is checked.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding the slot last applied
+const StoreName = "schedule"
+
+// Clock is a time of day in minutes since midnight
+type Clock int
+
+// Day is the length of a day in Clock minutes
+const Day Clock = 24 * 60
+
+// ParseClock reads a time of day such as "7:30" or "19:00". "24:00" is the
+// end of the day.
+func ParseClock(s string) (Clock, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(s), ":")
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if !ok || errH != nil || errM != nil || len(minutes) != 2 || h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("invalid time %q: use HH:MM, such as 07:30", s)
+	}
+	return Clock(h*60 + m), nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", c/60, c%60)
+}
+
+// Days is a set of days of the week. The empty set is every day.
+type Days uint8
+
+var dayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseDays reads days given as names ("mon", "Friday"), ranges ("mon-fri",
+// "fri-sun"), "weekdays", "weekends" or "daily". No days is every day.
+func ParseDays(specs []string) (Days, error) {
+	var days Days
+	for _, spec := range specs {
+		for _, part := range strings.Split(spec, ",") {
+			part = strings.ToLower(strings.TrimSpace(part))
+			switch part {
+			case "":
+				continue
+			case "daily", "every day", "all":
+				days |= 0x7f
+				continue
+			case "weekdays":
+				days |= dayRange(time.Monday, time.Friday)
+				continue
+			case "weekends":
+				days |= dayRange(time.Saturday, time.Sunday)
+				continue
+			}
+
+			from, to, isRange := strings.Cut(part, "-")
+			first, err := parseDay(from)
+			if err != nil {
+				return 0, err
+			}
+			last := first
+			if isRange {
+				if last, err = parseDay(to); err != nil {
+					return 0, err
+				}
+			}
+			days |= dayRange(first, last)
+		}
+	}
+	if days == 0x7f {
+		return 0, nil
+	}
+	return days, nil
+}
+
+func parseDay(s string) (time.Weekday, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 3 {
+		for i, name := range dayNames {
+			if strings.HasPrefix(s, name) && strings.HasPrefix(strings.ToLower(time.Weekday(i).String()), s) {
+				return time.Weekday(i), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q: use names such as mon or friday, ranges such as mon-fri, weekdays or weekends", s)
+}
+
+// dayRange is first to last, wrapping past Saturday
+func dayRange(first, last time.Weekday) Days {
+	var days Days
+	for d := first; ; d = (d + 1) % 7 {
+		days |= 1 << d
+		if d == last {
+			return days
+		}
+	}
+}
+
+// Has reports whether d includes day
+func (d Days) Has(day time.Weekday) bool {
+	return d == 0 || d&(1<<day) != 0
+}
+
+func (d Days) String() string {
+	switch d {
+	case 0:
+		return "daily"
+	case dayRange(time.Monday, time.Friday):
+		return "weekdays"
+	case dayRange(time.Saturday, time.Sunday):
+		return "weekends"
+	}
+	// Monday first, as schedules are read
+	var names []string
+	for i := 1; i <= 7; i++ {
+		if day := i % 7; d&(1<<day) != 0 {
+			names = append(names, dayNames[day])
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// Window is a span of time on some days. A window that ends at or before it
+// starts runs past midnight, and its days are the ones it starts on; one
+// that starts and ends at midnight lasts the whole day.
+type Window struct {
+	Days  Days
+	Start Clock
+	End   Clock
+}
+
+// NewWindow reads a window from its days and its start and end times
+func NewWindow(days []string, start, end string) (Window, error) {
+	var w Window
+	var err error
+	if w.Days, err = ParseDays(days); err != nil {
+		return w, err
+	}
+	if w.Start, err = ParseClock(start); err != nil {
+		return w, err
+	}
+	if w.Start == Day {
+		return w, fmt.Errorf("invalid start %q: a window cannot start at the end of the day", start)
+	}
+	if w.End, err = ParseClock(end); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%s %s-%s", w.Days, w.Start, w.End)
+}
+
+// Duration is how long the window lasts
+func (w Window) Duration() time.Duration {
+	span := w.End - w.Start
+	if span <= 0 {
+		span += Day
+	}
+	return time.Duration(span) * time.Minute
+}
+
+// startOn is when the window starts on the day of t
+func (w Window) startOn(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, int(w.Start/60), int(w.Start%60), 0, 0, t.Location())
+}
+
+// Covering returns the start of the occurrence of the window that t is in
+func (w Window) Covering(t time.Time) (time.Time, bool) {
+	// An occurrence that covers t started today or, past midnight, yesterday
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		if !w.Days.Has(day.Weekday()) {
+			continue
+		}
+		start := w.startOn(day)
+		if !t.Before(start) && t.Before(start.Add(w.Duration())) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Next returns the first start of the window after t
+func (w Window) Next(t time.Time) time.Time {
+	for i := 0; i <= 7; i++ {
+		day := t.AddDate(0, 0, i)
+		if start := w.startOn(day); w.Days.Has(day.Weekday()) && start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// Slot is what to play during a window
+type Slot struct {
+	Name   string
+	Window Window
+	// Context is the URI of the playlist, album, artist or show to play
+	Context string
+	// Device is the name or ID of the device to play on, or "" for the
+	// active one
+	Device  string
+	Shuffle *bool
+	Volume  *int
+	// Alarm slots start during quiet hours too
+	Alarm bool
+}
+
+// Active returns the slot covering t and when its occurrence started. When
+// slots overlap the one listed first wins, so specific slots such as a
+// Friday evening mix go before the everyday ones they cut into.
+func Active(slots []Slot, t time.Time) (*Slot, time.Time, bool) {
+	for i := range slots {
+		if start, ok := slots[i].Window.Covering(t); ok {
+			return &slots[i], start, true
+		}
+	}
+	return nil, time.Time{}, false
+}
+
+// Next returns the slot that starts first after t and when it starts
+func Next(slots []Slot, t time.Time) (*Slot, time.Time, bool) {
+	var next *Slot
+	var at time.Time
+	for i := range slots {
+		start := slots[i].Window.Next(t)
+		if !start.IsZero() && (next == nil || start.Before(at)) {
+			next, at = &slots[i], start
+		}
+	}
+	return next, at, next != nil
+}
+
+// Applied is the slot last started
+type Applied struct {
+	Slot string `json:"slot"`
+	// Start is the start of the occurrence that was applied
+	Start     time.Time `json:"start"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// Load reads the slot last applied, which is empty before the first
+func Load(dir *state.Dir) (*Applied, error) {
+	var applied Applied
+	if err := dir.Read(StoreName, &applied); err != nil {
+		return nil, err
+	}
+	return &applied, nil
+}
+
+// Due reports whether the occurrence of slot starting at start still needs
+// applying. Once applied it is left alone, so changing what plays halfway
+// through a slot sticks until the next one.
+func (a *Applied) Due(slot *Slot, start time.Time) bool {
+	return a.Slot != slot.Name || !a.Start.Equal(start)
+}
+
+// Record stores the slot just applied
+func Record(dir *state.Dir, applied Applied) error {
+	var data Applied
+	return dir.Update(StoreName, &data, func() error {
+		data = applied
+		return nil
+	})
+}
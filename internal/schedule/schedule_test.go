@@ -0,0 +1,136 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDays(t *testing.T) {
+	for _, tc := range []struct {
+		specs []string
+		want  string
+	}{
+		{nil, "daily"},
+		{[]string{"weekdays"}, "weekdays"},
+		{[]string{"Mon-Fri"}, "weekdays"},
+		{[]string{"sat", "sunday"}, "weekends"},
+		{[]string{"fri-mon"}, "mon,fri,sat,sun"},
+		{[]string{"tue,thu"}, "tue,thu"},
+		{[]string{"weekdays", "weekends"}, "daily"},
+	} {
+		days, err := ParseDays(tc.specs)
+		if err != nil {
+			t.Errorf("ParseDays(%v) failed: %v", tc.specs, err)
+			continue
+		}
+		if days.String() != tc.want {
+			t.Errorf("ParseDays(%v) = %s, expected %s", tc.specs, days, tc.want)
+		}
+	}
+
+	for _, bad := range []string{"mo", "montag", "mon-funday"} {
+		if _, err := ParseDays([]string{bad}); err == nil {
+			t.Errorf("Expected %q to fail", bad)
+		}
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	if c, err := ParseClock("7:30"); err != nil || c != 7*60+30 || c.String() != "07:30" {
+		t.Errorf("ParseClock(7:30) = %v, %v", c, err)
+	}
+	if c, err := ParseClock("24:00"); err != nil || c != Day {
+		t.Errorf("ParseClock(24:00) = %v, %v", c, err)
+	}
+	for _, bad := range []string{"7", "7:5", "25:00", "24:30", "12:60", "ab:cd"} {
+		if _, err := ParseClock(bad); err == nil {
+			t.Errorf("Expected %q to fail", bad)
+		}
+	}
+}
+
+// at is a time in the week of Monday 2 June 2025
+func at(day time.Weekday, hour, minute int) time.Time {
+	offset := (int(day) + 6) % 7
+	return time.Date(2025, 6, 2+offset, hour, minute, 0, 0, time.UTC)
+}
+
+func TestCovering(t *testing.T) {
+	morning, _ := NewWindow([]string{"weekdays"}, "07:00", "09:30")
+	if start, ok := morning.Covering(at(time.Tuesday, 8, 15)); !ok || !start.Equal(at(time.Tuesday, 7, 0)) {
+		t.Errorf("Expected Tuesday morning to be covered from 07:00, got %v %v", start, ok)
+	}
+	for _, outside := range []time.Time{at(time.Tuesday, 9, 30), at(time.Tuesday, 6, 59), at(time.Saturday, 8, 0)} {
+		if _, ok := morning.Covering(outside); ok {
+			t.Errorf("Expected %v not to be covered", outside)
+		}
+	}
+
+	// A Friday night window runs into Saturday, but not from Saturday
+	night, _ := NewWindow([]string{"fri"}, "22:00", "02:00")
+	if start, ok := night.Covering(at(time.Saturday, 1, 0)); !ok || !start.Equal(at(time.Friday, 22, 0)) {
+		t.Errorf("Expected early Saturday to be covered by Friday night, got %v %v", start, ok)
+	}
+	if _, ok := night.Covering(at(time.Saturday, 23, 0)); ok {
+		t.Error("Expected Saturday night not to be covered")
+	}
+
+	allDay, _ := NewWindow(nil, "00:00", "24:00")
+	if start, ok := allDay.Covering(at(time.Sunday, 23, 59)); !ok || !start.Equal(at(time.Sunday, 0, 0)) {
+		t.Errorf("Expected the whole of Sunday to be covered, got %v %v", start, ok)
+	}
+
+	if _, err := NewWindow(nil, "24:00", "02:00"); err == nil {
+		t.Error("Expected a window starting at 24:00 to fail")
+	}
+}
+
+func TestActiveAndNext(t *testing.T) {
+	window := func(days []string, start, end string) Window {
+		w, err := NewWindow(days, start, end)
+		if err != nil {
+			t.Fatalf("NewWindow failed: %v", err)
+		}
+		return w
+	}
+	slots := []Slot{
+		{Name: "friday", Window: window([]string{"fri"}, "17:00", "23:00")},
+		{Name: "evening", Window: window(nil, "18:00", "22:00")},
+		{Name: "morning", Window: window([]string{"weekdays"}, "07:00", "09:00")},
+	}
+
+	if slot, _, ok := Active(slots, at(time.Friday, 19, 0)); !ok || slot.Name != "friday" {
+		t.Errorf("Expected the first slot listed to win, got %v", slot)
+	}
+	if slot, _, ok := Active(slots, at(time.Thursday, 19, 0)); !ok || slot.Name != "evening" {
+		t.Errorf("Expected evening, got %v", slot)
+	}
+	if _, _, ok := Active(slots, at(time.Thursday, 12, 0)); ok {
+		t.Error("Expected no slot at noon")
+	}
+
+	slot, start, ok := Next(slots, at(time.Thursday, 12, 0))
+	if !ok || slot.Name != "evening" || !start.Equal(at(time.Thursday, 18, 0)) {
+		t.Errorf("Expected evening next at 18:00, got %v %v", slot, start)
+	}
+	slot, start, ok = Next(slots, at(time.Friday, 23, 30))
+	if !ok || slot.Name != "evening" || !start.Equal(at(time.Saturday, 18, 0)) {
+		t.Errorf("Expected Saturday evening next, got %v %v", slot, start)
+	}
+}
+
+func TestDue(t *testing.T) {
+	slot := &Slot{Name: "morning"}
+	start := at(time.Monday, 7, 0)
+	applied := &Applied{}
+	if !applied.Due(slot, start) {
+		t.Error("Expected a slot never applied to be due")
+	}
+	applied = &Applied{Slot: "morning", Start: start}
+	if applied.Due(slot, start) {
+		t.Error("Expected the applied occurrence not to be due")
+	}
+	if !applied.Due(slot, start.AddDate(0, 0, 1)) {
+		t.Error("Expected the next day's occurrence to be due")
+	}
+}
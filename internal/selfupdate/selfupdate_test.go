@@ -0,0 +1,69 @@
+package selfupdate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssetName(t *testing.T) {
+	if name := AssetName("linux", "amd64"); name != "spotify-cli-linux-amd64" {
+		t.Errorf("Unexpected asset name %q", name)
+	}
+	if name := AssetName("windows", "amd64"); name != "spotify-cli-windows-amd64.exe" {
+		t.Errorf("Expected .exe suffix on Windows, got %q", name)
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	binary := []byte("binary contents")
+	sum := SHA256(binary)
+	data := []byte(sum + "  spotify-cli-linux-amd64\n" + strings.Repeat("0", 64) + " *spotify-cli-windows-amd64.exe\n\n")
+
+	sums, err := ParseChecksums(data)
+	if err != nil {
+		t.Fatalf("ParseChecksums() error = %v", err)
+	}
+	if len(sums) != 2 {
+		t.Fatalf("Expected 2 checksums, got %d", len(sums))
+	}
+
+	if err := VerifyChecksum(sums, "spotify-cli-linux-amd64", sum); err != nil {
+		t.Errorf("Expected checksum to match, got %v", err)
+	}
+	if err := VerifyChecksum(sums, "spotify-cli-windows-amd64.exe", sum); err == nil {
+		t.Error("Expected checksum mismatch")
+	}
+	if err := VerifyChecksum(sums, "spotify-cli-plan9-386", sum); err == nil {
+		t.Error("Expected error for an asset without a checksum")
+	}
+
+	for _, bad := range []string{"abc  file\n", sum + "\n", sum + "  a b\n"} {
+		if _, err := ParseChecksums([]byte(bad)); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spotify-cli")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("Expected new binary, got %q, %v", data, err)
+	}
+	if sum, err := FileSHA256(path); err != nil || sum != SHA256([]byte("new")) {
+		t.Errorf("Unexpected file checksum %q, %v", sum, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected replaced binary to stay executable, got %v", info.Mode())
+	}
+}
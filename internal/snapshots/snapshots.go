@@ -0,0 +1,197 @@
+// Package snapshots keeps a local history of playlist contents. Spotify
+// only exposes the current version of a playlist, so every version seen is
+// appended here with its snapshot ID, which is what makes going back to an
+// earlier one possible.
+package snapshots
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding playlist snapshots, one per line
+const StoreName = "playlist_snapshots.jsonl"
+
+// Snapshot is the contents of a playlist at one snapshot ID
+type Snapshot struct {
+	PlaylistID string    `json:"playlist_id" yaml:"playlist_id"`
+	Name       string    `json:"name" yaml:"name"`
+	SnapshotID string    `json:"snapshot_id" yaml:"snapshot_id"`
+	RecordedAt time.Time `json:"recorded_at" yaml:"recorded_at"`
+	Tracks     []string  `json:"tracks" yaml:"tracks"`
+}
+
+// Change is how a snapshot differs from the one before it
+type Change struct {
+	Added   int `json:"added" yaml:"added"`
+	Removed int `json:"removed" yaml:"removed"`
+	// Reordered is set when the tracks are the same but not in the same order
+	Reordered bool `json:"reordered" yaml:"reordered"`
+}
+
+// Record appends a snapshot unless it is the latest one already kept for
+// its playlist. It reports whether the snapshot was new.
+func Record(dir *state.Dir, snapshot Snapshot) (bool, error) {
+	recorded := false
+	err := dir.WithLock(StoreName, func() error {
+		all, err := readUnlocked(dir)
+		if err != nil {
+			return err
+		}
+		for i := len(all) - 1; i >= 0; i-- {
+			if all[i].PlaylistID == snapshot.PlaylistID {
+				if all[i].SnapshotID == snapshot.SnapshotID {
+					return nil
+				}
+				break
+			}
+		}
+
+		if snapshot.RecordedAt.IsZero() {
+			snapshot.RecordedAt = time.Now()
+		}
+		snapshot.RecordedAt = snapshot.RecordedAt.UTC()
+		if snapshot.Tracks == nil {
+			snapshot.Tracks = []string{}
+		}
+		line, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to encode snapshot: %w", err)
+		}
+
+		file, err := os.OpenFile(dir.Path(StoreName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open snapshot history: %w", err)
+		}
+		if _, err := file.Write(append(line, '\n')); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write snapshot history: %w", err)
+		}
+		recorded = true
+		return file.Close()
+	})
+	return recorded, err
+}
+
+// History returns the snapshots of a playlist, oldest first
+func History(dir *state.Dir, playlistID string) ([]Snapshot, error) {
+	var history []Snapshot
+	err := dir.WithLock(StoreName, func() error {
+		all, err := readUnlocked(dir)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range all {
+			if snapshot.PlaylistID == playlistID {
+				history = append(history, snapshot)
+			}
+		}
+		return nil
+	})
+	return history, err
+}
+
+// Latest returns the snapshot ID last recorded for each playlist
+func Latest(dir *state.Dir) (map[string]string, error) {
+	latest := make(map[string]string)
+	err := dir.WithLock(StoreName, func() error {
+		all, err := readUnlocked(dir)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range all {
+			latest[snapshot.PlaylistID] = snapshot.SnapshotID
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// At returns the snapshot in effect at t, the last one recorded at or before
+// it, or nil if history starts later
+func At(history []Snapshot, t time.Time) *Snapshot {
+	var found *Snapshot
+	for i := range history {
+		if history[i].RecordedAt.After(t) {
+			break
+		}
+		found = &history[i]
+	}
+	return found
+}
+
+// Find returns the snapshot with the given snapshot ID
+func Find(history []Snapshot, snapshotID string) *Snapshot {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].SnapshotID == snapshotID {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+// Diff returns how next differs from previous, counting each occurrence of
+// a track
+func Diff(previous, next []string) Change {
+	counts := make(map[string]int)
+	for _, uri := range previous {
+		counts[uri]++
+	}
+	var change Change
+	for _, uri := range next {
+		if counts[uri] > 0 {
+			counts[uri]--
+		} else {
+			change.Added++
+		}
+	}
+	for _, n := range counts {
+		change.Removed += n
+	}
+
+	if change.Added == 0 && change.Removed == 0 {
+		for i := range previous {
+			if previous[i] != next[i] {
+				change.Reordered = true
+				break
+			}
+		}
+	}
+	return change
+}
+
+func readUnlocked(dir *state.Dir) ([]Snapshot, error) {
+	data, err := os.ReadFile(dir.Path(StoreName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot history: %w", err)
+	}
+
+	var all []Snapshot
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(text, &snapshot); err != nil {
+			// A torn final line from a crash should not hide the rest
+			continue
+		}
+		all = append(all, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot history: %w", err)
+	}
+	return all, nil
+}
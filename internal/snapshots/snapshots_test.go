@@ -0,0 +1,66 @@
+package snapshots
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+func TestRecordAndHistory(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, tt := range []struct {
+		playlist, snapshot string
+		tracks             []string
+		want               bool
+	}{
+		{"p1", "s1", []string{"a", "b"}, true},
+		{"p2", "x1", []string{"z"}, true},
+		{"p1", "s1", []string{"a", "b"}, false},
+		{"p1", "s2", []string{"a", "b", "c"}, true},
+	} {
+		recorded, err := Record(dir, Snapshot{PlaylistID: tt.playlist, SnapshotID: tt.snapshot, Tracks: tt.tracks, RecordedAt: start.Add(time.Duration(i) * time.Hour)})
+		if err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if recorded != tt.want {
+			t.Errorf("Record(%s, %s) = %v, want %v", tt.playlist, tt.snapshot, recorded, tt.want)
+		}
+	}
+
+	history, err := History(dir, "p1")
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) != 2 || history[0].SnapshotID != "s1" || history[1].SnapshotID != "s2" {
+		t.Fatalf("Unexpected history: %+v", history)
+	}
+
+	if snapshot := At(history, start.Add(30*time.Minute)); snapshot == nil || snapshot.SnapshotID != "s1" {
+		t.Errorf("Expected s1 to be in effect after the first hour, got %+v", snapshot)
+	}
+	if snapshot := At(history, start.Add(-time.Minute)); snapshot != nil {
+		t.Errorf("Expected nothing before history starts, got %+v", snapshot)
+	}
+	if snapshot := Find(history, "s2"); snapshot == nil || len(snapshot.Tracks) != 3 {
+		t.Errorf("Expected to find s2, got %+v", snapshot)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if change := Diff([]string{"a", "b", "c"}, []string{"b", "d"}); change.Added != 1 || change.Removed != 2 {
+		t.Errorf("Unexpected change: %+v", change)
+	}
+	if change := Diff([]string{"a", "b"}, []string{"b", "a"}); !change.Reordered {
+		t.Errorf("Expected a reorder, got %+v", change)
+	}
+	if change := Diff([]string{"a", "a"}, []string{"a"}); change.Removed != 1 {
+		t.Errorf("Expected a duplicate removal, got %+v", change)
+	}
+}
@@ -47,7 +47,8 @@ func (s *AlbumsService) GetAlbum(ctx context.Context, albumID string, market str
 	return &album, nil
 }
 
-// GetAlbums gets multiple albums by their IDs
+// GetAlbums gets multiple albums by their IDs. Missing albums are left out
+// and reported in a *PartialError returned with the rest.
 func (s *AlbumsService) GetAlbums(ctx context.Context, albumIDs []string, market string) ([]models.Album, error) {
 	if len(albumIDs) == 0 {
 		return nil, errors.NewValidationError("album IDs cannot be empty")
@@ -75,7 +76,7 @@ func (s *AlbumsService) GetAlbums(ctx context.Context, albumIDs []string, market
 	}
 
 	var response struct {
-		Albums []models.Album `json:"albums"`
+		Albums []*models.Album `json:"albums"`
 	}
 
 	err = s.client.Get(ctx, "/albums", params, &response)
@@ -83,7 +84,7 @@ func (s *AlbumsService) GetAlbums(ctx context.Context, albumIDs []string, market
 		return nil, errors.WrapAPIError(err, "failed to get albums")
 	}
 
-	return response.Albums, nil
+	return collectItems("album", normalizedIDs, response.Albums)
 }
 
 // GetAlbumTracks gets tracks for an album with pagination
@@ -116,6 +117,14 @@ func (s *AlbumsService) GetAlbumTracks(ctx context.Context, albumID string, opti
 	return &tracks, pagination, nil
 }
 
+// AlbumTracksPager returns a pager over an album's tracks
+func (s *AlbumsService) AlbumTracksPager(albumID string, market string) *api.Pager[models.Track] {
+	return api.NewPager(50, 0, func(ctx context.Context, offset, limit int) (*models.Paging[models.Track], error) {
+		tracks, _, err := s.GetAlbumTracks(ctx, albumID, &api.PaginationOptions{Limit: limit, Offset: offset}, market)
+		return tracks, err
+	})
+}
+
 // GetNewReleases gets new album releases
 func (s *AlbumsService) GetNewReleases(ctx context.Context, options *NewReleasesOptions) (*models.Paging[models.Album], *api.PaginationInfo, error) {
 	params := api.QueryParams{}
@@ -3,7 +3,9 @@ package spotify
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/errors"
@@ -14,6 +16,29 @@ import (
 type ArtistsService struct {
 	client    *api.RequestBuilder
 	validator *api.Validator
+
+	// related caches related-artist lists by artist ID for the life of the
+	// service, since expansions from different seeds overlap heavily
+	relatedMu sync.Mutex
+	related   map[string][]models.Artist
+}
+
+// MaxRelatedDepth is the deepest related-artist expansion allowed
+const MaxRelatedDepth = 3
+
+// RelatedFrontier is how many artists are expanded at each level after the
+// first. Every artist has about twenty related artists, so expanding all of
+// them would grow twentyfold per level.
+const RelatedFrontier = 20
+
+// RelatedArtist is an artist found by related-artist expansion
+type RelatedArtist struct {
+	Artist models.Artist `json:"artist" yaml:"artist"`
+	// Depth is 1 for artists related to a seed, 2 for artists related to
+	// those, and so on
+	Depth int `json:"depth" yaml:"depth"`
+	// Links is how many of the expanded artists list this one as related
+	Links int `json:"links" yaml:"links"`
 }
 
 // NewArtistsService creates a new artists service
@@ -39,7 +64,8 @@ func (s *ArtistsService) GetArtist(ctx context.Context, artistID string) (*model
 	return &artist, nil
 }
 
-// GetArtists gets multiple artists by their IDs
+// GetArtists gets multiple artists by their IDs. Missing artists are left
+// out and reported in a *PartialError returned with the rest.
 func (s *ArtistsService) GetArtists(ctx context.Context, artistIDs []string) ([]models.Artist, error) {
 	if len(artistIDs) == 0 {
 		return nil, errors.NewValidationError("artist IDs cannot be empty")
@@ -60,7 +86,7 @@ func (s *ArtistsService) GetArtists(ctx context.Context, artistIDs []string) ([]
 	}
 
 	var response struct {
-		Artists []models.Artist `json:"artists"`
+		Artists []*models.Artist `json:"artists"`
 	}
 
 	err = s.client.Get(ctx, "/artists", params, &response)
@@ -68,7 +94,7 @@ func (s *ArtistsService) GetArtists(ctx context.Context, artistIDs []string) ([]
 		return nil, errors.WrapAPIError(err, "failed to get artists")
 	}
 
-	return response.Artists, nil
+	return collectItems("artist", normalizedIDs, response.Artists)
 }
 
 // GetArtistAlbums gets albums for an artist with filtering options
@@ -165,6 +191,117 @@ func (s *ArtistsService) GetRelatedArtists(ctx context.Context, artistID string)
 	return response.Artists, nil
 }
 
+// GetRelatedForMany expands the related artists of several seed artists,
+// breadth first, to the given depth. Every seed is expanded; at deeper
+// levels only the RelatedFrontier best-linked artists found at the level
+// before are. Each artist appears once, at the depth it was first found,
+// and seeds are never returned. Results are ordered by depth, then by links
+// and popularity. Related lists are cached on the service, so overlapping
+// expansions cost one request per distinct artist.
+func (s *ArtistsService) GetRelatedForMany(ctx context.Context, ids []string, depth int) ([]RelatedArtist, error) {
+	if len(ids) == 0 {
+		return nil, errors.NewValidationError("artist IDs cannot be empty")
+	}
+	if depth < 1 || depth > MaxRelatedDepth {
+		return nil, errors.NewValidationError(fmt.Sprintf("depth must be between 1 and %d", MaxRelatedDepth))
+	}
+
+	seeds, err := s.validator.NormalizeAndValidateIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	found := make(map[string]*RelatedArtist)
+	var frontier []string
+	for _, id := range seeds {
+		if !seen[id] {
+			seen[id] = true
+			frontier = append(frontier, id)
+		}
+	}
+
+	var results []*RelatedArtist
+	for level := 1; level <= depth && len(frontier) > 0; level++ {
+		var discovered []*RelatedArtist
+		for _, id := range frontier {
+			related, err := s.relatedArtists(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			for _, artist := range related {
+				if existing, ok := found[artist.ID]; ok {
+					existing.Links++
+					continue
+				}
+				if seen[artist.ID] {
+					continue
+				}
+				seen[artist.ID] = true
+				entry := &RelatedArtist{Artist: artist, Depth: level, Links: 1}
+				found[artist.ID] = entry
+				discovered = append(discovered, entry)
+			}
+		}
+
+		sortRelated(discovered)
+		results = append(results, discovered...)
+
+		frontier = frontier[:0]
+		for _, entry := range discovered {
+			if len(frontier) == RelatedFrontier {
+				break
+			}
+			frontier = append(frontier, entry.Artist.ID)
+		}
+	}
+
+	// Links found at later levels can reorder earlier ones
+	sortRelated(results)
+	artists := make([]RelatedArtist, len(results))
+	for i, entry := range results {
+		artists[i] = *entry
+	}
+	return artists, nil
+}
+
+// relatedArtists returns the related artists of one artist, from the cache
+// if it was asked for before
+func (s *ArtistsService) relatedArtists(ctx context.Context, artistID string) ([]models.Artist, error) {
+	s.relatedMu.Lock()
+	related, ok := s.related[artistID]
+	s.relatedMu.Unlock()
+	if ok {
+		return related, nil
+	}
+
+	related, err := s.GetRelatedArtists(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.relatedMu.Lock()
+	if s.related == nil {
+		s.related = make(map[string][]models.Artist)
+	}
+	s.related[artistID] = related
+	s.relatedMu.Unlock()
+	return related, nil
+}
+
+func sortRelated(artists []*RelatedArtist) {
+	sort.SliceStable(artists, func(i, j int) bool {
+		a, b := artists[i], artists[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		if a.Links != b.Links {
+			return a.Links > b.Links
+		}
+		return a.Artist.Popularity > b.Artist.Popularity
+	})
+}
+
 // validateIncludeGroups validates album include groups
 func (s *ArtistsService) validateIncludeGroups(groups []string) error {
 	validGroups := map[string]bool{
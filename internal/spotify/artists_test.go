@@ -2,6 +2,7 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -402,6 +403,82 @@ func TestArtistsService_GetRelatedArtists(t *testing.T) {
 	}
 }
 
+func TestArtistsService_GetRelatedForMany(t *testing.T) {
+	id := func(letter string) string { return strings.Repeat(letter, 22) }
+	artist := func(letter string, popularity int) string {
+		return fmt.Sprintf(`{"id": %q, "name": %q, "type": "artist", "popularity": %d}`, id(letter), strings.ToUpper(letter), popularity)
+	}
+	graph := map[string][]string{
+		id("a"): {artist("c", 10), artist("d", 50)},
+		id("b"): {artist("c", 10), artist("e", 40)},
+		id("c"): {artist("a", 90), artist("f", 30)},
+		id("d"): {artist("f", 30)},
+		id("e"): {},
+	}
+
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		artistID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/artists/"), "/related-artists")
+		related, ok := graph[artistID]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"status": 404, "message": "Artist not found"}}`))
+			return
+		}
+		requests[artistID]++
+		w.Write([]byte(`{"artists": [` + strings.Join(related, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	c := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	c.SetBaseURL(server.URL)
+	c.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewArtistsService(api.NewRequestBuilder(c))
+
+	ctx := context.Background()
+	artists, err := service.GetRelatedForMany(ctx, []string{id("a"), "spotify:artist:" + id("b"), id("a")}, 2)
+	if err != nil {
+		t.Fatalf("GetRelatedForMany failed: %v", err)
+	}
+
+	want := []RelatedArtist{
+		{Depth: 1, Links: 2}, // C, related to both seeds
+		{Depth: 1, Links: 1}, // D, more popular than E
+		{Depth: 1, Links: 1}, // E
+		{Depth: 2, Links: 2}, // F; A is a seed and never returned
+	}
+	wantIDs := []string{id("c"), id("d"), id("e"), id("f")}
+	if len(artists) != len(want) {
+		t.Fatalf("Expected %d artists, got %d: %+v", len(want), len(artists), artists)
+	}
+	for i, got := range artists {
+		if got.Artist.ID != wantIDs[i] || got.Depth != want[i].Depth || got.Links != want[i].Links {
+			t.Errorf("Artist %d: expected %s at depth %d with %d links, got %s at depth %d with %d links",
+				i, wantIDs[i], want[i].Depth, want[i].Links, got.Artist.ID, got.Depth, got.Links)
+		}
+	}
+
+	// Expanding again, from a seed reached before, is served from the cache
+	if _, err := service.GetRelatedForMany(ctx, []string{id("c")}, 1); err != nil {
+		t.Fatalf("GetRelatedForMany failed: %v", err)
+	}
+	for artistID, n := range requests {
+		if n != 1 {
+			t.Errorf("Expected related artists of %s to be requested once, got %d", artistID, n)
+		}
+	}
+	if len(requests) != 5 {
+		t.Errorf("Expected 5 related-artist requests, got %d", len(requests))
+	}
+
+	if _, err := service.GetRelatedForMany(ctx, []string{id("a")}, 0); err == nil {
+		t.Error("Expected error for depth 0")
+	}
+	if _, err := service.GetRelatedForMany(ctx, nil, 1); err == nil {
+		t.Error("Expected error for no seeds")
+	}
+}
+
 func TestArtistsService_ValidationErrors(t *testing.T) {
 	service, server := createTestArtistsService()
 	defer server.Close()
@@ -0,0 +1,223 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// BrowseService handles browse category operations
+type BrowseService struct {
+	client    *api.RequestBuilder
+	validator *api.Validator
+}
+
+// NewBrowseService creates a new browse service
+func NewBrowseService(client *api.RequestBuilder) *BrowseService {
+	return &BrowseService{
+		client:    client,
+		validator: api.NewValidator(),
+	}
+}
+
+// GetCategories gets the browse categories available in a market
+func (s *BrowseService) GetCategories(ctx context.Context, options *CategoriesOptions) (*models.Paging[models.Category], *api.PaginationInfo, error) {
+	params := api.QueryParams{}
+
+	if options != nil {
+		if err := s.addLocaleParams(params, options.Country, options.Locale); err != nil {
+			return nil, nil, err
+		}
+		if err := s.addPageParams(params, options.Limit, options.Offset); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var response struct {
+		Categories models.Paging[models.Category] `json:"categories"`
+	}
+
+	err := s.client.Get(ctx, "/browse/categories", params, &response)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get categories")
+	}
+
+	return &response.Categories, pagingInfo(&response.Categories), nil
+}
+
+// GetCategory gets a single browse category
+func (s *BrowseService) GetCategory(ctx context.Context, categoryID string, country, locale string) (*models.Category, error) {
+	if err := validateCategoryID(categoryID); err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{}
+	if err := s.addLocaleParams(params, country, locale); err != nil {
+		return nil, err
+	}
+
+	var category models.Category
+	err := s.client.Get(ctx, fmt.Sprintf("/browse/categories/%s", url.PathEscape(categoryID)), params, &category)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get category")
+	}
+
+	return &category, nil
+}
+
+// GetCategoryPlaylists gets the playlists tagged with a browse category
+func (s *BrowseService) GetCategoryPlaylists(ctx context.Context, categoryID string, options *CategoryPlaylistsOptions) (*models.Paging[models.SimplePlaylist], *api.PaginationInfo, error) {
+	if err := validateCategoryID(categoryID); err != nil {
+		return nil, nil, err
+	}
+
+	params := api.QueryParams{}
+	if options != nil {
+		if err := s.addLocaleParams(params, options.Country, ""); err != nil {
+			return nil, nil, err
+		}
+		if err := s.addPageParams(params, options.Limit, options.Offset); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var response models.CategoryPlaylists
+	err := s.client.Get(ctx, fmt.Sprintf("/browse/categories/%s/playlists", url.PathEscape(categoryID)), params, &response)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get category playlists")
+	}
+
+	return &response.Playlists, pagingInfo(&response.Playlists), nil
+}
+
+// GetFeaturedPlaylists gets Spotify's editorial playlists for a market, as
+// shown at the given time of day
+func (s *BrowseService) GetFeaturedPlaylists(ctx context.Context, options *FeaturedPlaylistsOptions) (*models.FeaturedPlaylists, *api.PaginationInfo, error) {
+	params := api.QueryParams{}
+
+	if options != nil {
+		if err := s.addLocaleParams(params, options.Country, options.Locale); err != nil {
+			return nil, nil, err
+		}
+		if err := s.addPageParams(params, options.Limit, options.Offset); err != nil {
+			return nil, nil, err
+		}
+		if !options.Timestamp.IsZero() {
+			// Spotify reads the local time of day, so the zone offset is dropped
+			params["timestamp"] = options.Timestamp.Format("2006-01-02T15:04:05")
+		}
+	}
+
+	var featured models.FeaturedPlaylists
+	err := s.client.Get(ctx, "/browse/featured-playlists", params, &featured)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get featured playlists")
+	}
+
+	return &featured, pagingInfo(&featured.Playlists), nil
+}
+
+func (s *BrowseService) addLocaleParams(params api.QueryParams, country, locale string) error {
+	if country != "" {
+		if err := s.validator.ValidateMarket(country); err != nil {
+			return err
+		}
+		params["country"] = country
+	}
+
+	if locale != "" {
+		if err := validateLocale(locale); err != nil {
+			return err
+		}
+		params["locale"] = locale
+	}
+
+	return nil
+}
+
+func (s *BrowseService) addPageParams(params api.QueryParams, limit, offset int) error {
+	if limit > 0 {
+		if err := s.validator.ValidateLimit(limit, 1, 50); err != nil {
+			return err
+		}
+		params["limit"] = limit
+	}
+
+	if offset > 0 {
+		if err := s.validator.ValidateOffset(offset); err != nil {
+			return err
+		}
+		params["offset"] = offset
+	}
+
+	return nil
+}
+
+// validateCategoryID checks a category ID. Category IDs are not base-62
+// Spotify IDs: older ones are readable names such as "toplists".
+func validateCategoryID(categoryID string) error {
+	if categoryID == "" {
+		return errors.NewValidationError("category ID cannot be empty")
+	}
+
+	if strings.ContainsAny(categoryID, "/?# ") {
+		return errors.NewValidationError(fmt.Sprintf("invalid category ID: %s", categoryID))
+	}
+
+	return nil
+}
+
+// validateLocale checks a locale is an ISO 639-1 language code with an
+// ISO 3166-1 country code, such as "es_MX"
+func validateLocale(locale string) error {
+	language, country, ok := strings.Cut(locale, "_")
+	if !ok || len(language) != 2 || len(country) != 2 ||
+		strings.ToLower(language) != language || strings.ToUpper(country) != country {
+		return errors.NewValidationError(fmt.Sprintf("invalid locale %q: expected a language and country such as es_MX", locale))
+	}
+
+	return nil
+}
+
+// pagingInfo builds pagination info from a paging object nested inside a
+// response, which GetPaginated cannot see
+func pagingInfo[T any](page *models.Paging[T]) *api.PaginationInfo {
+	return &api.PaginationInfo{
+		Current:  page.Href,
+		Next:     page.Next,
+		Previous: page.Previous,
+		Total:    page.Total,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+}
+
+// CategoriesOptions contains options for listing browse categories
+type CategoriesOptions struct {
+	Country string `json:"country,omitempty"`
+	Locale  string `json:"locale,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+}
+
+// FeaturedPlaylistsOptions contains options for getting featured playlists
+type FeaturedPlaylistsOptions struct {
+	Country   string    `json:"country,omitempty"`
+	Locale    string    `json:"locale,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Limit     int       `json:"limit,omitempty"`
+	Offset    int       `json:"offset,omitempty"`
+}
+
+// CategoryPlaylistsOptions contains options for listing a category's playlists
+type CategoryPlaylistsOptions struct {
+	Country string `json:"country,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+}
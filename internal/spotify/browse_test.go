@@ -0,0 +1,168 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
+	"github.com/bambithedeer/spotify-api/internal/client"
+)
+
+// Mock browse responses
+var mockCategoriesResponse = `{
+	"categories": {
+		"href": "https://api.spotify.com/v1/browse/categories?offset=0&limit=2",
+		"items": [
+			{"href": "https://api.spotify.com/v1/browse/categories/toplists", "icons": [{"url": "https://example.com/toplists.jpg", "height": 275, "width": 275}], "id": "toplists", "name": "Top Lists"},
+			{"href": "https://api.spotify.com/v1/browse/categories/0JQ5DAqbMKFQ00XGBls6ym", "icons": [], "id": "0JQ5DAqbMKFQ00XGBls6ym", "name": "Hip-Hop"}
+		],
+		"limit": 2,
+		"next": "https://api.spotify.com/v1/browse/categories?offset=2&limit=2",
+		"offset": 0,
+		"previous": null,
+		"total": 40
+	}
+}`
+
+var mockCategoryPlaylistsResponse = `{
+	"message": "Top Lists",
+	"playlists": {
+		"href": "https://api.spotify.com/v1/browse/categories/toplists/playlists",
+		"items": [{"id": "37i9dQZF1DXcBWIGoYBM5M", "name": "Today's Top Hits", "owner": {"id": "spotify", "display_name": "Spotify"}, "tracks": {"total": 50}, "type": "playlist"}],
+		"limit": 20,
+		"next": null,
+		"offset": 0,
+		"previous": null,
+		"total": 1
+	}
+}`
+
+func createTestBrowseService() (*BrowseService, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"status": 401, "message": "Unauthorized"}}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/browse/categories":
+			if r.URL.Query().Get("locale") != "" && r.URL.Query().Get("locale") != "sv_SE" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(mockCategoriesResponse))
+		case "/browse/categories/toplists":
+			w.Write([]byte(`{"href": "https://api.spotify.com/v1/browse/categories/toplists", "icons": [], "id": "toplists", "name": "Top Lists"}`))
+		case "/browse/categories/toplists/playlists":
+			w.Write([]byte(mockCategoryPlaylistsResponse))
+		case "/browse/featured-playlists":
+			if ts := r.URL.Query().Get("timestamp"); ts != "" && ts != "2024-06-01T08:00:00" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"status": 400, "message": "Bad timestamp"}}`))
+				return
+			}
+			w.Write([]byte(strings.Replace(mockCategoryPlaylistsResponse, "Top Lists", "Good morning", 1)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"status": 404, "message": "Not found"}}`))
+		}
+	}))
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+
+	return NewBrowseService(api.NewRequestBuilder(client)), server
+}
+
+func TestBrowseService_GetCategories(t *testing.T) {
+	service, server := createTestBrowseService()
+	defer server.Close()
+
+	categories, pagination, err := service.GetCategories(context.Background(), &CategoriesOptions{Country: "SE", Locale: "sv_SE", Limit: 2})
+	if err != nil {
+		t.Fatalf("GetCategories failed: %v", err)
+	}
+
+	if len(categories.Items) != 2 || categories.Items[0].ID != "toplists" || len(categories.Items[0].Icons) != 1 {
+		t.Errorf("Unexpected categories: %+v", categories.Items)
+	}
+	if pagination.Total != 40 || pagination.GetNextOffset() != 2 {
+		t.Errorf("Expected pagination from the nested paging object, got %+v", pagination)
+	}
+
+	if _, _, err := service.GetCategories(context.Background(), &CategoriesOptions{Locale: "swedish"}); err == nil {
+		t.Error("Expected error for invalid locale")
+	}
+	if _, _, err := service.GetCategories(context.Background(), &CategoriesOptions{Limit: 51}); err == nil {
+		t.Error("Expected error for limit over 50")
+	}
+}
+
+func TestBrowseService_GetCategory(t *testing.T) {
+	service, server := createTestBrowseService()
+	defer server.Close()
+
+	category, err := service.GetCategory(context.Background(), "toplists", "", "")
+	if err != nil {
+		t.Fatalf("GetCategory failed: %v", err)
+	}
+	if category.Name != "Top Lists" {
+		t.Errorf("Expected Top Lists, got %q", category.Name)
+	}
+
+	for _, id := range []string{"", "top/lists"} {
+		if _, err := service.GetCategory(context.Background(), id, "", ""); err == nil {
+			t.Errorf("Expected error for category ID %q", id)
+		}
+	}
+}
+
+func TestBrowseService_GetCategoryPlaylists(t *testing.T) {
+	service, server := createTestBrowseService()
+	defer server.Close()
+
+	playlists, pagination, err := service.GetCategoryPlaylists(context.Background(), "toplists", &CategoryPlaylistsOptions{Country: "US"})
+	if err != nil {
+		t.Fatalf("GetCategoryPlaylists failed: %v", err)
+	}
+
+	if len(playlists.Items) != 1 || playlists.Items[0].Tracks.Total != 50 {
+		t.Errorf("Unexpected playlists: %+v", playlists.Items)
+	}
+	if pagination.HasNext() {
+		t.Error("Expected no next page")
+	}
+}
+
+func TestBrowseService_GetFeaturedPlaylists(t *testing.T) {
+	service, server := createTestBrowseService()
+	defer server.Close()
+
+	timestamp := time.Date(2024, 6, 1, 8, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	featured, pagination, err := service.GetFeaturedPlaylists(context.Background(), &FeaturedPlaylistsOptions{
+		Country:   "SE",
+		Locale:    "sv_SE",
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		t.Fatalf("GetFeaturedPlaylists failed: %v", err)
+	}
+
+	if featured.Message != "Good morning" || len(featured.Playlists.Items) != 1 {
+		t.Errorf("Unexpected featured playlists: %+v", featured)
+	}
+	if pagination.Total != 1 {
+		t.Errorf("Expected pagination total 1, got %d", pagination.Total)
+	}
+}
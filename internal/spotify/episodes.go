@@ -0,0 +1,86 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// EpisodesService handles podcast episode operations
+type EpisodesService struct {
+	client    *api.RequestBuilder
+	validator *api.Validator
+}
+
+// NewEpisodesService creates a new episodes service
+func NewEpisodesService(client *api.RequestBuilder) *EpisodesService {
+	return &EpisodesService{
+		client:    client,
+		validator: api.NewValidator(),
+	}
+}
+
+// GetEpisode gets an episode by ID
+func (s *EpisodesService) GetEpisode(ctx context.Context, episodeID string, market string) (*models.Episode, error) {
+	if err := s.validator.ValidateSpotifyID(episodeID); err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{}
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, err
+		}
+		params["market"] = market
+	}
+
+	var episode models.Episode
+	err := s.client.Get(ctx, fmt.Sprintf("/episodes/%s", episodeID), params, &episode)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get episode")
+	}
+
+	return &episode, nil
+}
+
+// GetEpisodes gets multiple episodes by their IDs
+func (s *EpisodesService) GetEpisodes(ctx context.Context, episodeIDs []string, market string) ([]models.Episode, error) {
+	if len(episodeIDs) == 0 {
+		return nil, errors.NewValidationError("episode IDs cannot be empty")
+	}
+
+	if len(episodeIDs) > 50 {
+		return nil, errors.NewValidationError("cannot request more than 50 episodes at once")
+	}
+
+	normalizedIDs, err := s.validator.NormalizeAndValidateIDs(episodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{
+		"ids": strings.Join(normalizedIDs, ","),
+	}
+
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, err
+		}
+		params["market"] = market
+	}
+
+	var response struct {
+		Episodes []models.Episode `json:"episodes"`
+	}
+
+	err = s.client.Get(ctx, "/episodes", params, &response)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get episodes")
+	}
+
+	return response.Episodes, nil
+}
@@ -2,7 +2,9 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/errors"
@@ -42,6 +44,49 @@ func (s *LibraryService) GetSavedTracks(ctx context.Context, options *api.Pagina
 	return &tracks, pagination, nil
 }
 
+// SavedTracksPager returns a pager over the user's saved tracks. The limit in
+// options sets the page size and the offset where paging starts.
+func (s *LibraryService) SavedTracksPager(options *api.PaginationOptions) *api.Pager[models.SavedTrack] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.SavedTrack], error) {
+		tracks, _, err := s.GetSavedTracks(ctx, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return tracks, err
+	})
+}
+
+// GetSavedTracksSince returns the tracks saved after since, newest first.
+// The library is paged newest first and paging stops at the first page
+// reaching a track saved at or before since, so only the change since the
+// last look is fetched. A zero since returns the whole library.
+func (s *LibraryService) GetSavedTracksSince(ctx context.Context, since time.Time) ([]models.SavedTrack, error) {
+	var tracks []models.SavedTrack
+	pager := s.SavedTracksPager(nil)
+	for {
+		page, err := pager.Next(ctx)
+		if err == api.ErrNoMorePages {
+			return tracks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, track := range page {
+			addedAt, err := time.Parse(time.RFC3339, track.AddedAt)
+			if err == nil && !addedAt.After(since) {
+				return tracks, nil
+			}
+			tracks = append(tracks, track)
+		}
+	}
+}
+
 // SaveTracks saves tracks to the user's library
 func (s *LibraryService) SaveTracks(ctx context.Context, trackIDs []string) error {
 	if len(trackIDs) == 0 {
@@ -158,6 +203,24 @@ func (s *LibraryService) GetSavedAlbums(ctx context.Context, options *SavedAlbum
 	return &albums, pagination, nil
 }
 
+// SavedAlbumsPager returns a pager over the user's saved albums
+func (s *LibraryService) SavedAlbumsPager(options *SavedAlbumsOptions) *api.Pager[models.SavedAlbum] {
+	opts := SavedAlbumsOptions{Limit: 50}
+	if options != nil {
+		opts = *options
+		if opts.Limit == 0 {
+			opts.Limit = 50
+		}
+	}
+
+	return api.NewPager(opts.Limit, opts.Offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.SavedAlbum], error) {
+		page := opts
+		page.Offset, page.Limit = offset, limit
+		albums, _, err := s.GetSavedAlbums(ctx, &page)
+		return albums, err
+	})
+}
+
 // SaveAlbums saves albums to the user's library
 func (s *LibraryService) SaveAlbums(ctx context.Context, albumIDs []string) error {
 	if len(albumIDs) == 0 {
@@ -239,6 +302,196 @@ func (s *LibraryService) CheckSavedAlbums(ctx context.Context, albumIDs []string
 	return saved, nil
 }
 
+// GetSavedEpisodes gets the podcast episodes saved in the user's library.
+// The market is optional; episodes unavailable there are left out.
+func (s *LibraryService) GetSavedEpisodes(ctx context.Context, options *api.PaginationOptions, market string) (*models.Paging[models.SavedEpisode], *api.PaginationInfo, error) {
+	params := api.QueryParams{}
+	if options != nil {
+		params = options.Merge(params)
+		if err := options.ValidateLimit(1, 50); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, nil, err
+		}
+		params["market"] = market
+	}
+
+	var episodes models.Paging[models.SavedEpisode]
+	pagination, err := s.client.GetPaginated(ctx, "/me/episodes", params, &episodes)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get saved episodes")
+	}
+
+	return &episodes, pagination, nil
+}
+
+// SavedEpisodesPager returns a pager over the user's saved episodes
+func (s *LibraryService) SavedEpisodesPager(options *api.PaginationOptions, market string) *api.Pager[models.SavedEpisode] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.SavedEpisode], error) {
+		episodes, _, err := s.GetSavedEpisodes(ctx, &api.PaginationOptions{Limit: limit, Offset: offset}, market)
+		return episodes, err
+	})
+}
+
+// SaveEpisodes saves podcast episodes to the user's library
+func (s *LibraryService) SaveEpisodes(ctx context.Context, episodeIDs []string) error {
+	normalizedIDs, err := s.validateIDs(episodeIDs, "episode", "save")
+	if err != nil {
+		return err
+	}
+
+	endpoint := "/me/episodes?ids=" + strings.Join(normalizedIDs, ",")
+	if err := s.client.Put(ctx, endpoint, nil, nil); err != nil {
+		return errors.WrapAPIError(err, "failed to save episodes")
+	}
+
+	return nil
+}
+
+// RemoveEpisodes removes podcast episodes from the user's library
+func (s *LibraryService) RemoveEpisodes(ctx context.Context, episodeIDs []string) error {
+	normalizedIDs, err := s.validateIDs(episodeIDs, "episode", "remove")
+	if err != nil {
+		return err
+	}
+
+	endpoint := "/me/episodes?ids=" + strings.Join(normalizedIDs, ",")
+	if err := s.client.Delete(ctx, endpoint, nil); err != nil {
+		return errors.WrapAPIError(err, "failed to remove episodes")
+	}
+
+	return nil
+}
+
+// CheckSavedEpisodes checks if podcast episodes are saved in the user's library
+func (s *LibraryService) CheckSavedEpisodes(ctx context.Context, episodeIDs []string) ([]bool, error) {
+	normalizedIDs, err := s.validateIDs(episodeIDs, "episode", "check")
+	if err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{
+		"ids": strings.Join(normalizedIDs, ","),
+	}
+
+	var saved []bool
+	if err := s.client.Get(ctx, "/me/episodes/contains", params, &saved); err != nil {
+		return nil, errors.WrapAPIError(err, "failed to check saved episodes")
+	}
+
+	return saved, nil
+}
+
+// GetSavedShows gets the shows saved in the user's library
+func (s *LibraryService) GetSavedShows(ctx context.Context, options *api.PaginationOptions) (*models.Paging[models.SavedShow], *api.PaginationInfo, error) {
+	params := api.QueryParams{}
+	if options != nil {
+		params = options.Merge(params)
+		if err := options.ValidateLimit(1, 50); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var shows models.Paging[models.SavedShow]
+	pagination, err := s.client.GetPaginated(ctx, "/me/shows", params, &shows)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get saved shows")
+	}
+
+	return &shows, pagination, nil
+}
+
+// SavedShowsPager returns a pager over the user's saved shows
+func (s *LibraryService) SavedShowsPager(options *api.PaginationOptions) *api.Pager[models.SavedShow] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.SavedShow], error) {
+		shows, _, err := s.GetSavedShows(ctx, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return shows, err
+	})
+}
+
+// SaveShows saves shows to the user's library
+func (s *LibraryService) SaveShows(ctx context.Context, showIDs []string) error {
+	normalizedIDs, err := s.validateIDs(showIDs, "show", "save")
+	if err != nil {
+		return err
+	}
+
+	endpoint := "/me/shows?ids=" + strings.Join(normalizedIDs, ",")
+	if err := s.client.Put(ctx, endpoint, nil, nil); err != nil {
+		return errors.WrapAPIError(err, "failed to save shows")
+	}
+
+	return nil
+}
+
+// RemoveShows removes shows from the user's library
+func (s *LibraryService) RemoveShows(ctx context.Context, showIDs []string) error {
+	normalizedIDs, err := s.validateIDs(showIDs, "show", "remove")
+	if err != nil {
+		return err
+	}
+
+	endpoint := "/me/shows?ids=" + strings.Join(normalizedIDs, ",")
+	if err := s.client.Delete(ctx, endpoint, nil); err != nil {
+		return errors.WrapAPIError(err, "failed to remove shows")
+	}
+
+	return nil
+}
+
+// CheckSavedShows checks if shows are saved in the user's library
+func (s *LibraryService) CheckSavedShows(ctx context.Context, showIDs []string) ([]bool, error) {
+	normalizedIDs, err := s.validateIDs(showIDs, "show", "check")
+	if err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{
+		"ids": strings.Join(normalizedIDs, ","),
+	}
+
+	var saved []bool
+	if err := s.client.Get(ctx, "/me/shows/contains", params, &saved); err != nil {
+		return nil, errors.WrapAPIError(err, "failed to check saved shows")
+	}
+
+	return saved, nil
+}
+
+// validateIDs checks a batch of IDs for the library endpoints, which take at
+// most 50 at a time
+func (s *LibraryService) validateIDs(ids []string, kind, action string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, errors.NewValidationError(fmt.Sprintf("%s IDs cannot be empty", kind))
+	}
+
+	if len(ids) > 50 {
+		return nil, errors.NewValidationError(fmt.Sprintf("cannot %s more than 50 %ss at once", action, kind))
+	}
+
+	return s.validator.NormalizeAndValidateIDs(ids)
+}
+
 // SavedAlbumsOptions contains options for getting saved albums
 type SavedAlbumsOptions struct {
 	Market string `json:"market,omitempty"`
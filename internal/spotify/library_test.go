@@ -2,12 +2,16 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
 	"github.com/bambithedeer/spotify-api/internal/client"
 )
 
@@ -270,4 +274,127 @@ func TestLibraryService_ValidationSuccess(t *testing.T) {
 	if err != nil && strings.Contains(err.Error(), "invalid") {
 		t.Errorf("Expected network error, got validation error: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestLibraryService_SavedEpisodes(t *testing.T) {
+	var saved, removed string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/me/episodes" && r.Method == "GET":
+			if r.URL.Query().Get("market") != "GB" {
+				t.Errorf("Expected market GB, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"items": [{"added_at": "2024-03-01T00:00:00Z", "episode": {"id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 2", "resume_point": {"fully_played": true}, "show": {"id": "38bS44xjbVVZ3No3ByF1dJ", "name": "Test Show"}}}], "limit": 20, "offset": 0, "total": 1}`))
+		case r.URL.Path == "/me/episodes" && r.Method == "PUT":
+			saved = r.URL.Query().Get("ids")
+		case r.URL.Path == "/me/episodes" && r.Method == "DELETE":
+			removed = r.URL.Query().Get("ids")
+		case r.URL.Path == "/me/episodes/contains":
+			w.Write([]byte(`[false, true]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"status": 400, "message": "Bad request"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	service := NewLibraryService(api.NewRequestBuilder(client))
+	ctx := context.Background()
+
+	episodes, _, err := service.GetSavedEpisodes(ctx, &api.PaginationOptions{Limit: 20}, "GB")
+	if err != nil {
+		t.Fatalf("GetSavedEpisodes failed: %v", err)
+	}
+	if len(episodes.Items) != 1 || episodes.Items[0].Episode.Show.Name != "Test Show" || !episodes.Items[0].Episode.ResumePoint.FullyPlayed {
+		t.Errorf("Unexpected saved episodes: %+v", episodes.Items)
+	}
+
+	ids := []string{"spotify:episode:512ojhOuo1ktJprKbVcKyQ", "612ojhOuo1ktJprKbVcKyQ"}
+	if err := service.SaveEpisodes(ctx, ids); err != nil {
+		t.Errorf("SaveEpisodes failed: %v", err)
+	}
+	if saved != "512ojhOuo1ktJprKbVcKyQ,612ojhOuo1ktJprKbVcKyQ" {
+		t.Errorf("Expected normalized IDs in the save query, got %q", saved)
+	}
+	if err := service.RemoveEpisodes(ctx, ids); err != nil {
+		t.Errorf("RemoveEpisodes failed: %v", err)
+	}
+	if removed != saved {
+		t.Errorf("Expected the same IDs removed as saved, got %q", removed)
+	}
+
+	contains, err := service.CheckSavedEpisodes(ctx, ids)
+	if err != nil {
+		t.Fatalf("CheckSavedEpisodes failed: %v", err)
+	}
+	if len(contains) != 2 || contains[0] || !contains[1] {
+		t.Errorf("Unexpected check results: %v", contains)
+	}
+
+	if err := service.SaveEpisodes(ctx, nil); err == nil {
+		t.Error("Expected error for empty episode IDs")
+	}
+	if _, _, err := service.GetSavedEpisodes(ctx, nil, "INVALID_MARKET_CODE"); err == nil {
+		t.Error("Expected error for invalid market code")
+	}
+}
+
+func TestLibraryService_GetSavedTracksSince(t *testing.T) {
+	// 120 tracks, one saved each hour, newest first
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offsets = append(offsets, strconv.Itoa(offset))
+
+		var items []string
+		for i := offset; i < offset+limit && i < 120; i++ {
+			addedAt := start.Add(time.Duration(120-i) * time.Hour).Format(time.RFC3339)
+			items = append(items, fmt.Sprintf(`{"added_at": %q, "track": {"id": "track%d", "uri": "spotify:track:track%d"}}`, addedAt, i, i))
+		}
+		next := "null"
+		if offset+limit < 120 {
+			next = fmt.Sprintf(`"https://api.spotify.com/v1/me/tracks?offset=%d"`, offset+limit)
+		}
+		fmt.Fprintf(w, `{"items": [%s], "limit": %d, "offset": %d, "total": 120, "next": %s}`, strings.Join(items, ","), limit, offset, next)
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	service := NewLibraryService(api.NewRequestBuilder(client))
+	ctx := context.Background()
+
+	tracks, err := service.GetSavedTracksSince(ctx, start.Add(117*time.Hour))
+	if err != nil {
+		t.Fatalf("GetSavedTracksSince failed: %v", err)
+	}
+	if len(tracks) != 3 || tracks[0].Track.ID != "track0" || tracks[2].Track.ID != "track2" {
+		t.Errorf("Expected the three newest tracks, got %+v", tracks)
+	}
+	if len(offsets) != 1 {
+		t.Errorf("Expected one page to be fetched, got offsets %v", offsets)
+	}
+
+	offsets = nil
+	tracks, err = service.GetSavedTracksSince(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("GetSavedTracksSince failed: %v", err)
+	}
+	if len(tracks) != 120 || len(offsets) != 3 {
+		t.Errorf("Expected the whole library in three pages for a zero watermark, got %d tracks from offsets %v", len(tracks), offsets)
+	}
+}
@@ -0,0 +1,52 @@
+package spotify
+
+import (
+	"context"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+)
+
+// MarketsService handles market availability operations
+type MarketsService struct {
+	client    *api.RequestBuilder
+	validator *api.Validator
+}
+
+// NewMarketsService creates a new markets service
+func NewMarketsService(client *api.RequestBuilder) *MarketsService {
+	return &MarketsService{
+		client:    client,
+		validator: api.NewValidator(),
+	}
+}
+
+// GetAvailableMarkets gets the ISO 3166-1 alpha-2 codes of the countries
+// where Spotify is available
+func (s *MarketsService) GetAvailableMarkets(ctx context.Context) ([]string, error) {
+	var response struct {
+		Markets []string `json:"markets"`
+	}
+
+	err := s.client.Get(ctx, "/markets", nil, &response)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get available markets")
+	}
+
+	return response.Markets, nil
+}
+
+// CheckMarket returns a validation error unless market is one of the
+// markets Spotify currently reports as available
+func (s *MarketsService) CheckMarket(ctx context.Context, market string) error {
+	if err := s.validator.ValidateMarket(market); err != nil {
+		return err
+	}
+
+	markets, err := s.GetAvailableMarkets(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.validator.ValidateMarketAvailable(market, markets)
+}
@@ -0,0 +1,66 @@
+package spotify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// How long results that commands ask for repeatedly are reused. The
+// profile changes only through the Spotify apps; devices come and go and
+// report their volume, so they are only kept long enough to share between
+// the steps of one command.
+const (
+	ProfileTTL = 5 * time.Minute
+	DevicesTTL = 10 * time.Second
+)
+
+// memo holds the last result of a call for ttl. The response cache on
+// disk leaves user data alone, so this is what stops one invocation, or a
+// long-running command such as serve, fetching the same profile again and
+// again.
+type memo[T any] struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	value     *T
+	fetchedAt time.Time
+}
+
+func newMemo[T any](ttl time.Duration) *memo[T] {
+	return &memo[T]{ttl: ttl}
+}
+
+// get returns a copy of the held value while it is fresh, and otherwise
+// calls fetch and holds its result. Errors are not held. A nil memo always
+// fetches.
+func (m *memo[T]) get(ctx context.Context, fetch func(context.Context) (*T, error)) (*T, error) {
+	if m == nil || m.ttl <= 0 {
+		return fetch(ctx)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.value != nil && time.Since(m.fetchedAt) < m.ttl {
+		value := *m.value
+		return &value, nil
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	held := *value
+	m.value, m.fetchedAt = &held, time.Now()
+	return value, nil
+}
+
+// reset drops the held value, for calls that change it
+func (m *memo[T]) reset() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.value = nil
+	m.mu.Unlock()
+}
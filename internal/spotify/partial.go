@@ -0,0 +1,72 @@
+package spotify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ItemError is one ID of a multi-ID request that Spotify returned null
+// for: the item was removed, or is not available in the market asked for
+type ItemError struct {
+	Type string
+	ID   string
+	// Index is the ID's position in the request
+	Index int
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("%s %s not found: removed, or not available in this market", e.Type, e.ID)
+}
+
+// PartialError is returned alongside the items that were found when some
+// IDs of a multi-ID request were not. Callers that can live without the
+// missing items check for it with errors.As and carry on.
+type PartialError struct {
+	Missing []*ItemError
+}
+
+func (e *PartialError) Error() string {
+	if len(e.Missing) == 1 {
+		return e.Missing[0].Error()
+	}
+	return fmt.Sprintf("%d %ss not found: %s", len(e.Missing), e.Missing[0].Type, strings.Join(e.MissingIDs(), ", "))
+}
+
+// Unwrap returns the error for each missing item
+func (e *PartialError) Unwrap() []error {
+	errs := make([]error, len(e.Missing))
+	for i, missing := range e.Missing {
+		errs[i] = missing
+	}
+	return errs
+}
+
+// MissingIDs returns the IDs that were not found, in request order
+func (e *PartialError) MissingIDs() []string {
+	ids := make([]string, len(e.Missing))
+	for i, missing := range e.Missing {
+		ids[i] = missing.ID
+	}
+	return ids
+}
+
+// collectItems drops the nulls from a multi-ID response, which lines up
+// with the IDs requested, and reports them as a *PartialError
+func collectItems[T any](itemType string, ids []string, items []*T) ([]T, error) {
+	found := make([]T, 0, len(items))
+	var partial *PartialError
+	for i, id := range ids {
+		if i < len(items) && items[i] != nil {
+			found = append(found, *items[i])
+			continue
+		}
+		if partial == nil {
+			partial = &PartialError{}
+		}
+		partial.Missing = append(partial.Missing, &ItemError{Type: itemType, ID: id, Index: i})
+	}
+	if partial != nil {
+		return found, partial
+	}
+	return found, nil
+}
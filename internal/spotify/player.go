@@ -2,10 +2,14 @@ package spotify
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/duration"
 	"github.com/bambithedeer/spotify-api/internal/errors"
 	"github.com/bambithedeer/spotify-api/internal/models"
 )
@@ -14,6 +18,10 @@ import (
 type PlayerService struct {
 	client    *api.RequestBuilder
 	validator *api.Validator
+
+	// devices holds the device list for DevicesTTL, so resolving a device
+	// and then checking what it supports is one request
+	devices *memo[models.DevicesResponse]
 }
 
 // NewPlayerService creates a new player service
@@ -21,6 +29,7 @@ func NewPlayerService(client *api.RequestBuilder) *PlayerService {
 	return &PlayerService{
 		client:    client,
 		validator: api.NewValidator(),
+		devices:   newMemo[models.DevicesResponse](DevicesTTL),
 	}
 }
 
@@ -71,15 +80,18 @@ func (s *PlayerService) GetCurrentlyPlaying(ctx context.Context, options *Curren
 	return &playing, nil
 }
 
-// GetDevices gets the user's available devices
+// GetDevices gets the user's available devices. The list is reused for
+// DevicesTTL, or until playback is transferred or the volume set.
 func (s *PlayerService) GetDevices(ctx context.Context) (*models.DevicesResponse, error) {
-	var devices models.DevicesResponse
-	err := s.client.Get(ctx, "/me/player/devices", nil, &devices)
-	if err != nil {
-		return nil, errors.WrapAPIError(err, "failed to get devices")
-	}
+	return s.devices.get(ctx, func(ctx context.Context) (*models.DevicesResponse, error) {
+		var devices models.DevicesResponse
+		err := s.client.Get(ctx, "/me/player/devices", nil, &devices)
+		if err != nil {
+			return nil, errors.WrapAPIError(err, "failed to get devices")
+		}
 
-	return &devices, nil
+		return &devices, nil
+	})
 }
 
 // Play starts or resumes playback
@@ -89,6 +101,10 @@ func (s *PlayerService) Play(ctx context.Context, options *PlayOptions) error {
 		params["device_id"] = options.DeviceID
 	}
 
+	if err := s.validatePlayOptions(options); err != nil {
+		return err
+	}
+
 	var body interface{}
 	if options != nil {
 		body = options
@@ -171,6 +187,55 @@ func (s *PlayerService) Seek(ctx context.Context, positionMs int, deviceID strin
 	return nil
 }
 
+// SeekOptions contains options for seeking within the current item
+type SeekOptions struct {
+	DeviceID string
+	// Clamp seeks to the last second of the item when the position is past
+	// its end, instead of failing
+	Clamp bool
+}
+
+// SeekInItem seeks within the track or episode playing now, checking the
+// position against its duration first: the API takes a position past the
+// end as a skip to the next track. It returns the position seeked to,
+// which differs from positionMs only when it was clamped.
+func (s *PlayerService) SeekInItem(ctx context.Context, positionMs int, options *SeekOptions) (int, error) {
+	if options == nil {
+		options = &SeekOptions{}
+	}
+	if positionMs < 0 {
+		return 0, errors.NewValidationError("position cannot be negative")
+	}
+
+	playing, err := s.GetCurrentlyPlaying(ctx, &CurrentlyPlayingOptions{AdditionalTypes: []string{"track", "episode"}})
+	if err != nil {
+		return 0, err
+	}
+	durationMs := 0
+	if playing != nil {
+		if item, ok := playing.Item.(map[string]interface{}); ok {
+			if duration, ok := item["duration_ms"].(float64); ok {
+				durationMs = int(duration)
+			}
+		}
+	}
+	if durationMs <= 0 {
+		return 0, errors.NewValidationError("nothing is playing to seek in")
+	}
+
+	if positionMs >= durationMs {
+		if !options.Clamp {
+			return 0, errors.NewValidationError(fmt.Sprintf("position %s is past the end of the item, which is %s long", duration.Format(positionMs), duration.Format(durationMs)))
+		}
+		positionMs = max(durationMs-1000, 0)
+	}
+
+	if err := s.Seek(ctx, positionMs, options.DeviceID); err != nil {
+		return 0, err
+	}
+	return positionMs, nil
+}
+
 // SetRepeat sets repeat mode
 func (s *PlayerService) SetRepeat(ctx context.Context, state string, deviceID string) error {
 	if err := s.validateRepeatState(state); err != nil {
@@ -222,6 +287,7 @@ func (s *PlayerService) SetVolume(ctx context.Context, volumePercent int, device
 	}
 
 	err := s.client.Put(ctx, endpoint, nil, nil)
+	s.devices.reset()
 	if err != nil {
 		return errors.WrapAPIError(err, "failed to set volume")
 	}
@@ -244,6 +310,7 @@ func (s *PlayerService) TransferPlayback(ctx context.Context, request *TransferP
 	}
 
 	err := s.client.Put(ctx, "/me/player", request, nil)
+	s.devices.reset()
 	if err != nil {
 		return errors.WrapAPIError(err, "failed to transfer playback")
 	}
@@ -274,6 +341,17 @@ func (s *PlayerService) AddToQueue(ctx context.Context, uri string, deviceID str
 	return nil
 }
 
+// GetQueue gets the currently playing item and the user's upcoming queue
+func (s *PlayerService) GetQueue(ctx context.Context) (*models.Queue, error) {
+	var queue models.Queue
+	err := s.client.Get(ctx, "/me/player/queue", nil, &queue)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get queue")
+	}
+
+	return &queue, nil
+}
+
 // GetRecentlyPlayed gets tracks from the user's recently played tracks
 func (s *PlayerService) GetRecentlyPlayed(ctx context.Context, options *RecentlyPlayedOptions) (*models.CursorPaging[models.PlayHistory], error) {
 	params := api.QueryParams{}
@@ -321,12 +399,22 @@ type PlayOptions struct {
 	PositionMs      int      `json:"position_ms,omitempty"`
 }
 
-// Offset represents playback offset
+// Offset is where in a context playback starts: the track with URI if it
+// is set, otherwise the track at Position, counting from 0
 type Offset struct {
 	Position int    `json:"position,omitempty"`
 	URI      string `json:"uri,omitempty"`
 }
 
+// MarshalJSON sends exactly one of the two fields. Position 0 is sent as
+// such, so starting at the first track is not taken for no offset.
+func (o Offset) MarshalJSON() ([]byte, error) {
+	if o.URI != "" {
+		return json.Marshal(map[string]string{"uri": o.URI})
+	}
+	return json.Marshal(map[string]int{"position": o.Position})
+}
+
 // TransferPlaybackRequest represents a request to transfer playback
 type TransferPlaybackRequest struct {
 	DeviceIDs []string `json:"device_ids"`
@@ -342,6 +430,37 @@ type RecentlyPlayedOptions struct {
 
 // Validation methods
 
+// validatePlayOptions checks that an offset has something to be an offset
+// into, and that it is a position or a track or episode URI
+func (s *PlayerService) validatePlayOptions(options *PlayOptions) error {
+	if options == nil {
+		return nil
+	}
+	if options.PositionMs < 0 {
+		return errors.NewValidationError("position cannot be negative")
+	}
+	if options.Offset == nil {
+		return nil
+	}
+
+	if options.ContextURI == "" && len(options.URIs) == 0 {
+		return errors.NewValidationError("an offset needs a context URI or URIs to play")
+	}
+	if options.Offset.URI != "" {
+		if !strings.HasPrefix(options.Offset.URI, "spotify:track:") && !strings.HasPrefix(options.Offset.URI, "spotify:episode:") {
+			return errors.NewValidationError("offset URI must be a track or episode URI")
+		}
+		return s.validator.ValidateSpotifyURI(options.Offset.URI)
+	}
+	if options.Offset.Position < 0 {
+		return errors.NewValidationError("offset position cannot be negative")
+	}
+	if len(options.URIs) > 0 && options.Offset.Position >= len(options.URIs) {
+		return errors.NewValidationError("offset position is past the end of the URIs")
+	}
+	return nil
+}
+
 func (s *PlayerService) validateAdditionalTypes(types []string) error {
 	validTypes := map[string]bool{
 		"track":   true,
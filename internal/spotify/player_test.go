@@ -2,12 +2,15 @@ package spotify
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
 	"github.com/bambithedeer/spotify-api/internal/client"
 )
 
@@ -364,4 +367,186 @@ func TestPlayerService_VolumeValidation(t *testing.T) {
 			t.Errorf("Expected validation error for invalid volume %d", volume)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestPlayerService_GetQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/player/queue" || r.Method != "GET" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"status": 404, "message": "Not Found"}}`))
+			return
+		}
+		w.Write([]byte(`{
+			"currently_playing": {"id": "4iV5W9uYEdYUVa79Axb7Rh", "name": "Now Playing", "type": "track", "uri": "spotify:track:4iV5W9uYEdYUVa79Axb7Rh", "artists": [{"name": "Test Artist"}]},
+			"queue": [
+				{"id": "5iV5W9uYEdYUVa79Axb7Rh", "name": "Up Next", "type": "track", "uri": "spotify:track:5iV5W9uYEdYUVa79Axb7Rh", "duration_ms": 180000},
+				{"id": "512ojhOuo1ktJprKbVcKyQ", "name": "An Episode", "type": "episode", "uri": "spotify:episode:512ojhOuo1ktJprKbVcKyQ", "duration_ms": 1800000}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	spotifyClient := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	spotifyClient.SetBaseURL(server.URL)
+	spotifyClient.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlayerService(api.NewRequestBuilder(spotifyClient))
+
+	queue, err := service.GetQueue(context.Background())
+	if err != nil {
+		t.Fatalf("GetQueue failed: %v", err)
+	}
+
+	if !queue.CurrentlyPlaying.IsTrack() || queue.CurrentlyPlaying.Name() != "Now Playing" {
+		t.Errorf("Unexpected currently playing item: %+v", queue.CurrentlyPlaying)
+	}
+	if len(queue.Queue) != 2 {
+		t.Fatalf("Expected 2 queued items, got %d", len(queue.Queue))
+	}
+	if !queue.Queue[0].IsTrack() || queue.Queue[0].DurationMs() != 180000 {
+		t.Errorf("Unexpected first queued item: %+v", queue.Queue[0])
+	}
+	if !queue.Queue[1].IsEpisode() {
+		t.Errorf("Expected the second queued item to be an episode, got %+v", queue.Queue[1])
+	}
+}
+
+func TestPlayerService_PlayOffset(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	spotifyClient := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	spotifyClient.SetBaseURL(server.URL)
+	spotifyClient.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlayerService(api.NewRequestBuilder(spotifyClient))
+	ctx := context.Background()
+
+	// Starting at the first track still sends the offset
+	err := service.Play(ctx, &PlayOptions{ContextURI: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", Offset: &Offset{}})
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if !strings.Contains(body, `"offset":{"position":0}`) {
+		t.Errorf("Expected position 0 in the offset, got %s", body)
+	}
+
+	err = service.Play(ctx, &PlayOptions{ContextURI: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", Offset: &Offset{Position: 3, URI: "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"}})
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if !strings.Contains(body, `"offset":{"uri":"spotify:track:4iV5W9uYEdYUVa79Axb7Rh"}`) {
+		t.Errorf("Expected only the URI in the offset, got %s", body)
+	}
+
+	invalid := []*PlayOptions{
+		{Offset: &Offset{Position: 1}},
+		{ContextURI: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", Offset: &Offset{Position: -1}},
+		{ContextURI: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy", Offset: &Offset{URI: "spotify:album:4aawyAB9vmqN3uQ7FjRGTy"}},
+		{URIs: []string{"spotify:track:4iV5W9uYEdYUVa79Axb7Rh"}, Offset: &Offset{Position: 1}},
+	}
+	for _, options := range invalid {
+		if err := service.Play(ctx, options); err == nil {
+			t.Errorf("Expected a validation error for %+v", options.Offset)
+		}
+	}
+}
+
+func TestPlayerService_SeekInItem(t *testing.T) {
+	var seeked string
+	playing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me/player/currently-playing":
+			if !playing {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Write([]byte(`{"is_playing": true, "progress_ms": 1000, "item": {"id": "4iV5W9uYEdYUVa79Axb7Rh", "type": "track", "duration_ms": 192000}}`))
+		case "/me/player/seek":
+			seeked = r.URL.Query().Get("position_ms")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spotifyClient := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	spotifyClient.SetBaseURL(server.URL)
+	spotifyClient.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlayerService(api.NewRequestBuilder(spotifyClient))
+	ctx := context.Background()
+
+	position, err := service.SeekInItem(ctx, 60000, nil)
+	if err != nil || position != 60000 || seeked != "60000" {
+		t.Errorf("Expected a seek to 60000, got %d (sent %q): %v", position, seeked, err)
+	}
+
+	seeked = ""
+	_, err = service.SeekInItem(ctx, 300000, nil)
+	if err == nil || !strings.Contains(err.Error(), "3:12") {
+		t.Errorf("Expected an error naming the 3:12 duration, got %v", err)
+	}
+	if seeked != "" {
+		t.Errorf("Expected no seek past the end, got %q", seeked)
+	}
+
+	position, err = service.SeekInItem(ctx, 300000, &SeekOptions{Clamp: true})
+	if err != nil || position != 191000 || seeked != "191000" {
+		t.Errorf("Expected a clamped seek to 191000, got %d (sent %q): %v", position, seeked, err)
+	}
+
+	playing = false
+	if _, err := service.SeekInItem(ctx, 1000, nil); err == nil {
+		t.Error("Expected an error with nothing playing")
+	}
+}
+
+func TestPlayerService_GetDevicesMemoized(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/me/player/devices" && r.Method == "GET":
+			fetches++
+			w.Write([]byte(`{"devices": [{"id": "abc", "name": "Speaker", "type": "Speaker", "volume_percent": 50}]}`))
+		case r.URL.Path == "/me/player" && r.Method == "PUT":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spotifyClient := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	spotifyClient.SetBaseURL(server.URL)
+	spotifyClient.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlayerService(api.NewRequestBuilder(spotifyClient))
+	ctx := context.Background()
+
+	first, err := service.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("GetDevices failed: %v", err)
+	}
+	first.Devices = nil
+	second, err := service.GetDevices(ctx)
+	if err != nil {
+		t.Fatalf("GetDevices failed: %v", err)
+	}
+	if fetches != 1 || len(second.Devices) != 1 {
+		t.Errorf("Expected one fetch shared by both calls, got %d fetches and %+v", fetches, second)
+	}
+
+	// Transferring playback changes which device is active
+	if err := service.TransferPlayback(ctx, &TransferPlaybackRequest{DeviceIDs: []string{"abc"}}); err != nil {
+		t.Fatalf("TransferPlayback failed: %v", err)
+	}
+	if _, err := service.GetDevices(ctx); err != nil {
+		t.Fatalf("GetDevices failed: %v", err)
+	}
+	if fetches != 2 {
+		t.Errorf("Expected a transfer to drop the held devices, got %d fetches", fetches)
+	}
+}
@@ -2,6 +2,7 @@ package spotify
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"strings"
 
@@ -110,6 +111,53 @@ func (s *PlaylistsService) GetPlaylistTracks(ctx context.Context, playlistID str
 	return &tracks, pagination, nil
 }
 
+// PlaylistTracksPager returns a pager over a playlist's tracks. Market, fields
+// and additional types from options apply to every page.
+func (s *PlaylistsService) PlaylistTracksPager(playlistID string, options *PlaylistTracksOptions) *api.Pager[models.PlaylistTrack] {
+	opts := PlaylistTracksOptions{Limit: 100}
+	if options != nil {
+		opts = *options
+		if opts.Limit == 0 {
+			opts.Limit = 100
+		}
+	}
+
+	return api.NewPager(opts.Limit, opts.Offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.PlaylistTrack], error) {
+		page := opts
+		page.Offset, page.Limit = offset, limit
+		tracks, _, err := s.GetPlaylistTracks(ctx, playlistID, &page)
+		return tracks, err
+	})
+}
+
+// GetPlaylistItem gets the entry at a zero-based position of a playlist, by
+// requesting a page of one, so positional operations need not read the
+// whole playlist. It also returns the number of entries in the playlist.
+// Fields and Limit/Offset from options are ignored; Market and
+// AdditionalTypes apply.
+func (s *PlaylistsService) GetPlaylistItem(ctx context.Context, playlistID string, position int, options *PlaylistTracksOptions) (*models.PlaylistTrack, int, error) {
+	if position < 0 {
+		return nil, 0, errors.NewValidationError("position cannot be negative")
+	}
+
+	opts := PlaylistTracksOptions{}
+	if options != nil {
+		opts = *options
+	}
+	opts.Fields = "total,items(added_at,added_by,is_local,track)"
+	opts.Limit = 1
+	opts.Offset = position
+
+	page, _, err := s.GetPlaylistTracks(ctx, playlistID, &opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(page.Items) == 0 {
+		return nil, page.Total, errors.NewValidationError(fmt.Sprintf("position %d is past the end of the playlist (%d entries)", position, page.Total))
+	}
+	return &page.Items[0], page.Total, nil
+}
+
 // GetUserPlaylists gets current user's playlists
 func (s *PlaylistsService) GetUserPlaylists(ctx context.Context, options *api.PaginationOptions) (*models.Paging[models.Playlist], *api.PaginationInfo, error) {
 	params := api.QueryParams{}
@@ -129,6 +177,22 @@ func (s *PlaylistsService) GetUserPlaylists(ctx context.Context, options *api.Pa
 	return &playlists, pagination, nil
 }
 
+// UserPlaylistsPager returns a pager over the current user's playlists
+func (s *PlaylistsService) UserPlaylistsPager(options *api.PaginationOptions) *api.Pager[models.Playlist] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Playlist], error) {
+		playlists, _, err := s.GetUserPlaylists(ctx, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return playlists, err
+	})
+}
+
 // GetUserPlaylistsByID gets playlists for a specific user
 func (s *PlaylistsService) GetUserPlaylistsByID(ctx context.Context, userID string, options *api.PaginationOptions) (*models.Paging[models.Playlist], *api.PaginationInfo, error) {
 	if userID == "" {
@@ -296,6 +360,119 @@ func (s *PlaylistsService) ReplacePlaylistTracks(ctx context.Context, playlistID
 	return &response, nil
 }
 
+// FollowPlaylist adds a playlist to the current user's library. With public
+// false the playlist is followed privately and does not appear on their
+// profile.
+func (s *PlaylistsService) FollowPlaylist(ctx context.Context, playlistID string, public bool) error {
+	if err := s.validator.ValidateSpotifyID(playlistID); err != nil {
+		return err
+	}
+
+	request := map[string]interface{}{
+		"public": public,
+	}
+
+	err := s.client.Put(ctx, fmt.Sprintf("/playlists/%s/followers", playlistID), request, nil)
+	if err != nil {
+		return errors.WrapAPIError(err, "failed to follow playlist")
+	}
+
+	return nil
+}
+
+// UnfollowPlaylist removes a playlist from the current user's library.
+// Unfollowing a playlist the user owns is how Spotify deletes it.
+func (s *PlaylistsService) UnfollowPlaylist(ctx context.Context, playlistID string) error {
+	if err := s.validator.ValidateSpotifyID(playlistID); err != nil {
+		return err
+	}
+
+	err := s.client.Delete(ctx, fmt.Sprintf("/playlists/%s/followers", playlistID), nil)
+	if err != nil {
+		return errors.WrapAPIError(err, "failed to unfollow playlist")
+	}
+
+	return nil
+}
+
+// CheckUsersFollowPlaylist checks whether each user follows a playlist. The
+// results are in the same order as userIDs.
+func (s *PlaylistsService) CheckUsersFollowPlaylist(ctx context.Context, playlistID string, userIDs []string) ([]bool, error) {
+	if err := s.validator.ValidateSpotifyID(playlistID); err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return nil, errors.NewValidationError("user IDs cannot be empty")
+	}
+
+	if len(userIDs) > 5 {
+		return nil, errors.NewValidationError("cannot check more than 5 users at once")
+	}
+
+	normalizedIDs, err := s.validator.NormalizeUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{
+		"ids": strings.Join(normalizedIDs, ","),
+	}
+
+	var following []bool
+	err = s.client.Get(ctx, fmt.Sprintf("/playlists/%s/followers/contains", playlistID), params, &following)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to check playlist followers")
+	}
+
+	return following, nil
+}
+
+// MaxCoverImageSize is the largest cover image the API accepts, measured
+// after base64 encoding
+const MaxCoverImageSize = 256 * 1024
+
+// GetPlaylistCoverImage gets the cover images of a playlist. Spotify returns
+// a mosaic of the first tracks' album art for playlists without a custom
+// cover, in up to three sizes.
+func (s *PlaylistsService) GetPlaylistCoverImage(ctx context.Context, playlistID string) ([]models.Image, error) {
+	if err := s.validator.ValidateSpotifyID(playlistID); err != nil {
+		return nil, err
+	}
+
+	var images []models.Image
+	err := s.client.Get(ctx, fmt.Sprintf("/playlists/%s/images", playlistID), nil, &images)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get playlist cover image")
+	}
+
+	return images, nil
+}
+
+// UploadPlaylistCoverImage replaces a playlist's cover with a JPEG image.
+// Requires the ugc-image-upload scope.
+func (s *PlaylistsService) UploadPlaylistCoverImage(ctx context.Context, playlistID string, jpeg []byte) error {
+	if err := s.validator.ValidateSpotifyID(playlistID); err != nil {
+		return err
+	}
+
+	if len(jpeg) < 3 || jpeg[0] != 0xFF || jpeg[1] != 0xD8 || jpeg[2] != 0xFF {
+		return errors.NewValidationError("cover image must be a JPEG")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(jpeg)
+	if len(encoded) > MaxCoverImageSize {
+		return errors.NewValidationError(fmt.Sprintf("cover image is too large: %d KB after base64 encoding, the limit is %d KB", len(encoded)/1024, MaxCoverImageSize/1024))
+	}
+
+	err := s.client.PutRaw(ctx, fmt.Sprintf("/playlists/%s/images", playlistID), []byte(encoded), "image/jpeg", nil)
+	if err != nil {
+		return errors.WrapAPIError(err, "failed to upload playlist cover image")
+	}
+
+	return nil
+}
+
 // Request and response types
 
 // PlaylistOptions contains options for getting a playlist
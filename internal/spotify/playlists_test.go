@@ -2,6 +2,8 @@ package spotify
 
 import (
 	"context"
+	"encoding/base64"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -360,14 +362,14 @@ func TestPlaylistsService_GetPlaylistTracks(t *testing.T) {
 		t.Errorf("Expected 2 tracks, got %d", len(tracks.Items))
 	}
 
-	track0, ok := tracks.Items[0].Track.(map[string]interface{})
-	if !ok || track0["id"] != "6iV5W9uYEdYUVa79Axb7Rh" {
-		t.Errorf("Expected first track ID '6iV5W9uYEdYUVa79Axb7Rh', got %v", track0["id"])
+	track0 := tracks.Items[0].Track
+	if !track0.IsTrack() || track0.Track.ID != "6iV5W9uYEdYUVa79Axb7Rh" {
+		t.Errorf("Expected first track ID '6iV5W9uYEdYUVa79Axb7Rh', got %v", track0.ID())
 	}
 
-	track1, ok := tracks.Items[1].Track.(map[string]interface{})
-	if !ok || track1["id"] != "7iV5W9uYEdYUVa79Axb7Rh" {
-		t.Errorf("Expected second track ID '7iV5W9uYEdYUVa79Axb7Rh', got %v", track1["id"])
+	track1 := tracks.Items[1].Track
+	if !track1.IsTrack() || track1.Track.ID != "7iV5W9uYEdYUVa79Axb7Rh" {
+		t.Errorf("Expected second track ID '7iV5W9uYEdYUVa79Axb7Rh', got %v", track1.ID())
 	}
 
 	if pagination == nil {
@@ -404,6 +406,44 @@ func TestPlaylistsService_GetPlaylistTracksWithOptions(t *testing.T) {
 	}
 }
 
+func TestPlaylistsService_PlaylistTracksPager(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset := r.URL.Query().Get("offset")
+		offsets = append(offsets, offset)
+
+		if r.URL.Query().Get("market") != "US" {
+			t.Errorf("Expected market to be sent with every page, got %q", r.URL.RawQuery)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if offset == "" {
+			w.Write([]byte(`{"items": [{"track": {"id": "a"}}, {"track": {"id": "b"}}], "limit": 2, "offset": 0, "total": 3, "next": "https://api.spotify.com/v1/playlists/37i9dQZF1DX0XUsuxWHRQd/tracks?offset=2&limit=2"}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"track": {"id": "c"}}], "limit": 2, "offset": 2, "total": 3, "next": null}`))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlaylistsService(api.NewRequestBuilder(client))
+
+	pager := service.PlaylistTracksPager("37i9dQZF1DX0XUsuxWHRQd", &PlaylistTracksOptions{Limit: 2, Market: "US"})
+	tracks, err := pager.All(context.Background())
+	if err != nil {
+		t.Fatalf("PlaylistTracksPager failed: %v", err)
+	}
+
+	if len(tracks) != 3 {
+		t.Errorf("Expected 3 tracks across pages, got %d", len(tracks))
+	}
+	if len(offsets) != 2 || offsets[1] != "2" {
+		t.Errorf("Expected requests at offsets 0 and 2, got %v", offsets)
+	}
+}
+
 func TestPlaylistsService_GetUserPlaylists(t *testing.T) {
 	service, server := createTestPlaylistsService()
 	defer server.Close()
@@ -734,4 +774,180 @@ func TestPlaylistsService_ReorderValidationErrors(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for invalid range length")
 	}
-}
\ No newline at end of file
+}
+func TestPlaylistsService_PlaylistCoverImage(t *testing.T) {
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE0, 'c', 'o', 'v', 'e', 'r'}
+
+	var uploaded []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/playlists/37i9dQZF1DX0XUsuxWHRQd/images" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[{"url": "https://mosaic.scdn.co/640/abc", "height": 640, "width": 640}]`))
+		case "PUT":
+			contentType = r.Header.Get("Content-Type")
+			uploaded, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlaylistsService(api.NewRequestBuilder(client))
+
+	ctx := context.Background()
+
+	images, err := service.GetPlaylistCoverImage(ctx, "37i9dQZF1DX0XUsuxWHRQd")
+	if err != nil {
+		t.Fatalf("GetPlaylistCoverImage failed: %v", err)
+	}
+	if len(images) != 1 || images[0].Width != 640 {
+		t.Errorf("Unexpected images: %+v", images)
+	}
+
+	if err := service.UploadPlaylistCoverImage(ctx, "37i9dQZF1DX0XUsuxWHRQd", jpeg); err != nil {
+		t.Fatalf("UploadPlaylistCoverImage failed: %v", err)
+	}
+	if contentType != "image/jpeg" {
+		t.Errorf("Expected Content-Type image/jpeg, got %q", contentType)
+	}
+	if string(uploaded) != base64.StdEncoding.EncodeToString(jpeg) {
+		t.Errorf("Expected the base64 encoded image to be uploaded, got %q", uploaded)
+	}
+
+	// Not a JPEG
+	if err := service.UploadPlaylistCoverImage(ctx, "37i9dQZF1DX0XUsuxWHRQd", []byte("\x89PNG")); err == nil {
+		t.Error("Expected error for a non-JPEG image")
+	}
+
+	// Too large once encoded
+	large := append(append([]byte{}, jpeg...), make([]byte, MaxCoverImageSize*3/4)...)
+	if err := service.UploadPlaylistCoverImage(ctx, "37i9dQZF1DX0XUsuxWHRQd", large); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Expected error for a large image, got %v", err)
+	}
+}
+
+func TestPlaylistsService_FollowPlaylist(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlaylistsService(api.NewRequestBuilder(client))
+
+	ctx := context.Background()
+
+	if err := service.FollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd", false); err != nil {
+		t.Fatalf("FollowPlaylist failed: %v", err)
+	}
+	if err := service.UnfollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd"); err != nil {
+		t.Fatalf("UnfollowPlaylist failed: %v", err)
+	}
+
+	expected := []string{
+		`PUT /playlists/37i9dQZF1DX0XUsuxWHRQd/followers {"public":false}`,
+		`DELETE /playlists/37i9dQZF1DX0XUsuxWHRQd/followers `,
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("Expected %d requests, got %v", len(expected), requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("Request %d = %q, expected %q", i, requests[i], expected[i])
+		}
+	}
+
+	if err := service.FollowPlaylist(ctx, "", true); err == nil {
+		t.Error("Expected error for empty playlist ID")
+	}
+}
+
+func TestPlaylistsService_CheckUsersFollowPlaylist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/playlists/37i9dQZF1DX0XUsuxWHRQd/followers/contains" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if ids := r.URL.Query().Get("ids"); ids != "alice,bob" {
+			t.Errorf("Expected ids alice,bob, got %q", ids)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[true, false]`))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlaylistsService(api.NewRequestBuilder(client))
+
+	ctx := context.Background()
+
+	following, err := service.CheckUsersFollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("CheckUsersFollowPlaylist failed: %v", err)
+	}
+	if len(following) != 2 || !following[0] || following[1] {
+		t.Errorf("Expected [true false], got %v", following)
+	}
+
+	if _, err := service.CheckUsersFollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd", nil); err == nil {
+		t.Error("Expected error for no user IDs")
+	}
+	if _, err := service.CheckUsersFollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd", []string{"a", "b", "c", "d", "e", "f"}); err == nil {
+		t.Error("Expected error for more than 5 user IDs")
+	}
+	if _, err := service.CheckUsersFollowPlaylist(ctx, "37i9dQZF1DX0XUsuxWHRQd", []string{"a,b"}); err == nil {
+		t.Error("Expected error for user ID with a comma")
+	}
+}
+
+func TestPlaylistsService_GetPlaylistItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("limit") != "1" || query.Get("fields") == "" {
+			t.Errorf("Expected a single item with fields, got %q", r.URL.RawQuery)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if query.Get("offset") == "5" {
+			w.Write([]byte(`{"items": [], "total": 3}`))
+			return
+		}
+		w.Write([]byte(`{"items": [{"added_at": "2024-01-01T00:00:00Z", "track": {"type": "track", "id": "6iV5W9uYEdYUVa79Axb7Rh", "name": "Third"}}], "total": 3}`))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewPlaylistsService(api.NewRequestBuilder(client))
+
+	ctx := context.Background()
+	item, total, err := service.GetPlaylistItem(ctx, "37i9dQZF1DX0XUsuxWHRQd", 2, nil)
+	if err != nil {
+		t.Fatalf("GetPlaylistItem failed: %v", err)
+	}
+	if total != 3 || item.Track == nil || item.Track.Name() != "Third" {
+		t.Errorf("Unexpected item %+v of %d", item, total)
+	}
+
+	if _, total, err := service.GetPlaylistItem(ctx, "37i9dQZF1DX0XUsuxWHRQd", 5, nil); err == nil || total != 3 {
+		t.Errorf("Expected an error past the end with the total, got %v, %d", err, total)
+	}
+	if _, _, err := service.GetPlaylistItem(ctx, "37i9dQZF1DX0XUsuxWHRQd", -1, nil); err == nil {
+		t.Error("Expected an error for a negative position")
+	}
+}
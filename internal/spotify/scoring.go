@@ -0,0 +1,352 @@
+package spotify
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// MatchOptions tune how search results are ranked
+type MatchOptions struct {
+	// Market prefers results that can be played there
+	Market string
+	// Strict fails with an *AmbiguousError unless one result is an exact
+	// match and no different result matches as well
+	Strict bool
+}
+
+// AmbiguousError is returned by a strict ranking when the query does not
+// single out one result
+type AmbiguousError struct {
+	Query string
+	// Matches are the results that tied for the best match, or the closest
+	// result when nothing matched exactly
+	Matches []string
+	Exact   bool
+}
+
+func (e *AmbiguousError) Error() string {
+	if !e.Exact {
+		return fmt.Sprintf("no exact match for %q; the closest is %s", e.Query, strings.Join(e.Matches, ", "))
+	}
+	return fmt.Sprintf("%q matches %d results equally well: %s", e.Query, len(e.Matches), strings.Join(e.Matches, "; "))
+}
+
+// Scores for each part of a match. The title and artist outweigh
+// popularity, which only runs to 20, so a popular near miss never beats an
+// exact match; a result that cannot be played in the market loses to
+// anything that can.
+const (
+	scoreExactName   = 100
+	scoreBaseName    = 60
+	scorePartialName = 20
+	scoreExactArtist = 50
+	scoreAvailable   = 20
+	scoreUnavailable = -200
+)
+
+// queryTerms are the parts of a search query the ranking compares
+// against: the field filters it names and the free text around them
+type queryTerms struct {
+	track, artist, album string
+	free                 string
+}
+
+func parseQueryTerms(query string) queryTerms {
+	var terms queryTerms
+	var free []string
+	for _, token := range splitQuery(query) {
+		field, value, ok := strings.Cut(token, ":")
+		if !ok {
+			free = append(free, token)
+			continue
+		}
+		switch strings.ToLower(field) {
+		case "track":
+			terms.track = value
+		case "artist":
+			terms.artist = value
+		case "album":
+			terms.album = value
+		case "year", "genre", "tag", "isrc", "upc":
+		default:
+			free = append(free, token)
+		}
+	}
+	terms.free = strings.Join(free, " ")
+	return terms
+}
+
+// splitQuery splits a query on spaces, keeping quoted values such as
+// artist:"Daft Punk" together
+func splitQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// normalizeTitle lowercases a title and reduces it to its words, so
+// punctuation and spacing do not stop an otherwise exact match
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// baseTitle drops the version a title names after a dash or in brackets,
+// as in "Song - Remastered 2011" or "Song (Live)"
+func baseTitle(title string) string {
+	if i := strings.Index(title, " - "); i > 0 {
+		title = title[:i]
+	}
+	for _, open := range []string{"(", "["} {
+		if i := strings.Index(title, open); i > 0 {
+			title = title[:i]
+		}
+	}
+	return normalizeTitle(title)
+}
+
+// candidate is a search result reduced to what the ranking looks at
+type candidate struct {
+	name       string
+	artists    []string
+	markets    []string
+	restricted bool
+	popularity int
+}
+
+func (c candidate) label() string {
+	if len(c.artists) == 0 {
+		return c.name
+	}
+	return fmt.Sprintf("%s by %s", c.name, c.artists[0])
+}
+
+// identity is what makes two results the same thing to a listener; the
+// single and the album release of a song are not ambiguous
+func (c candidate) identity() string {
+	artist := ""
+	if len(c.artists) > 0 {
+		artist = normalizeTitle(c.artists[0])
+	}
+	return baseTitle(c.name) + "\x00" + artist
+}
+
+type scored struct {
+	index int
+	// match is the score without popularity; ambiguity is judged on it
+	match int
+	exact bool
+	total int
+}
+
+// score rates how well c matches a name and artist term. With combined
+// set, free text that holds both the title and an artist also counts as
+// an exact match of each.
+func (c candidate) score(name, artist string, combined bool, market string) scored {
+	var s scored
+	wantName := normalizeTitle(name)
+	wantArtist := normalizeTitle(artist)
+	gotName := normalizeTitle(c.name)
+
+	artistMatched := false
+	for _, a := range c.artists {
+		got := normalizeTitle(a)
+		if wantArtist != "" && got == wantArtist {
+			artistMatched = true
+		}
+		if combined && !s.exact && wantArtist == "" && (wantName == gotName+" "+got || wantName == got+" "+gotName) {
+			s.match += scoreExactName + scoreExactArtist
+			s.exact = true
+		}
+	}
+
+	if !s.exact && wantName != "" {
+		switch {
+		case gotName == wantName:
+			s.match += scoreExactName
+			s.exact = true
+		case baseTitle(c.name) == wantName:
+			s.match += scoreBaseName
+		case strings.Contains(" "+gotName+" ", " "+wantName+" "):
+			s.match += scorePartialName
+		}
+	}
+	if wantName == "" {
+		// Only an artist was asked for
+		s.exact = artistMatched
+	}
+	if artistMatched {
+		s.match += scoreExactArtist
+	} else if wantArtist != "" {
+		s.exact = false
+	}
+
+	if market != "" {
+		switch {
+		case c.restricted:
+			s.match += scoreUnavailable
+		case len(c.markets) == 0:
+		case slices.Contains(c.markets, strings.ToUpper(market)):
+			s.match += scoreAvailable
+		default:
+			s.match += scoreUnavailable
+		}
+	}
+
+	s.total = s.match + c.popularity/5
+	return s
+}
+
+// rank orders candidates best first and returns their indices. Ties keep
+// the order the search returned them in, which is Spotify's own relevance.
+func rank(query string, candidates []candidate, name, artist string, combined bool, options *MatchOptions) ([]int, error) {
+	if options == nil {
+		options = &MatchOptions{}
+	}
+
+	scores := make([]scored, len(candidates))
+	for i, c := range candidates {
+		scores[i] = c.score(name, artist, combined, options.Market)
+		scores[i].index = i
+	}
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].total > scores[j].total })
+
+	order := make([]int, len(scores))
+	for i, s := range scores {
+		order[i] = s.index
+	}
+	if !options.Strict || len(scores) == 0 {
+		return order, nil
+	}
+
+	best := scores[0]
+	if !best.exact || best.match < 0 {
+		return order, &AmbiguousError{Query: query, Matches: []string{candidates[best.index].label()}}
+	}
+	matches := []string{candidates[best.index].label()}
+	seen := map[string]bool{candidates[best.index].identity(): true}
+	for _, s := range scores[1:] {
+		c := candidates[s.index]
+		if s.match < best.match || seen[c.identity()] {
+			continue
+		}
+		seen[c.identity()] = true
+		matches = append(matches, c.label())
+	}
+	if len(matches) > 1 {
+		return order, &AmbiguousError{Query: query, Matches: matches, Exact: true}
+	}
+	return order, nil
+}
+
+func simpleArtistNames(artists []models.SimpleArtist) []string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return names
+}
+
+// RankTracks re-orders track search results by how exactly they match the
+// query's title and artist, then by market availability and popularity, so
+// a caller taking the first result gets the one the query meant. The track:
+// and artist: filters in the query are matched on their own; free text is
+// matched as the title, or as a title and artist together.
+func RankTracks(query string, tracks []models.Track, options *MatchOptions) ([]models.Track, error) {
+	terms := parseQueryTerms(query)
+	name := terms.track
+	if name == "" {
+		name = terms.free
+	}
+
+	candidates := make([]candidate, len(tracks))
+	for i, track := range tracks {
+		candidates[i] = candidate{
+			name:       track.Name,
+			artists:    simpleArtistNames(track.Artists),
+			markets:    track.AvailableMarkets,
+			restricted: track.Restrictions != nil,
+			popularity: track.Popularity,
+		}
+	}
+
+	order, err := rank(query, candidates, name, terms.artist, terms.track == "", options)
+	ranked := make([]models.Track, len(order))
+	for i, index := range order {
+		ranked[i] = tracks[index]
+	}
+	return ranked, err
+}
+
+// RankArtists re-orders artist search results by exact name match, then
+// popularity. The name is the query's artist: filter, or its free text.
+func RankArtists(query string, artists []models.Artist, options *MatchOptions) ([]models.Artist, error) {
+	terms := parseQueryTerms(query)
+	name := terms.artist
+	if name == "" {
+		name = terms.free
+	}
+
+	candidates := make([]candidate, len(artists))
+	for i, artist := range artists {
+		candidates[i] = candidate{name: artist.Name, popularity: artist.Popularity}
+	}
+
+	order, err := rank(query, candidates, name, "", false, options)
+	ranked := make([]models.Artist, len(order))
+	for i, index := range order {
+		ranked[i] = artists[index]
+	}
+	return ranked, err
+}
+
+// RankAlbums re-orders album search results like RankTracks, matching the
+// query's album: filter or free text as the album name
+func RankAlbums(query string, albums []models.Album, options *MatchOptions) ([]models.Album, error) {
+	terms := parseQueryTerms(query)
+	name := terms.album
+	if name == "" {
+		name = terms.free
+	}
+
+	candidates := make([]candidate, len(albums))
+	for i, album := range albums {
+		candidates[i] = candidate{
+			name:       album.Name,
+			artists:    simpleArtistNames(album.Artists),
+			markets:    album.AvailableMarkets,
+			restricted: album.Restrictions != nil,
+			popularity: album.Popularity,
+		}
+	}
+
+	order, err := rank(query, candidates, name, terms.artist, terms.album == "", options)
+	ranked := make([]models.Album, len(order))
+	for i, index := range order {
+		ranked[i] = albums[index]
+	}
+	return ranked, err
+}
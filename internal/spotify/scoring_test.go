@@ -0,0 +1,97 @@
+package spotify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func scoringTrack(id, name, artist string, popularity int, markets ...string) models.Track {
+	return models.Track{
+		ID:               id,
+		Name:             name,
+		Artists:          []models.SimpleArtist{{Name: artist}},
+		Popularity:       popularity,
+		AvailableMarkets: markets,
+	}
+}
+
+func rankedIDs(tracks []models.Track) []string {
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	return ids
+}
+
+func TestRankTracks(t *testing.T) {
+	tracks := []models.Track{
+		scoringTrack("cover", "Hurt (Live)", "Cover Band", 90),
+		scoringTrack("cash", "Hurt", "Johnny Cash", 80),
+		scoringTrack("nin", "Hurt", "Nine Inch Nails", 60),
+		scoringTrack("other", "Hurts So Good", "John Mellencamp", 95),
+	}
+
+	ranked, err := RankTracks("hurt", tracks, nil)
+	if err != nil {
+		t.Fatalf("RankTracks() error = %v", err)
+	}
+	if ids := rankedIDs(ranked); ids[0] != "cash" || ids[1] != "nin" || ids[2] != "cover" || ids[3] != "other" {
+		t.Errorf("Expected exact titles by popularity, then the live version, got %v", ids)
+	}
+
+	// Naming the artist settles it, as a filter or in the free text
+	for _, query := range []string{"hurt artist:\"Nine Inch Nails\"", "nine inch nails hurt"} {
+		ranked, err := RankTracks(query, tracks, &MatchOptions{Strict: true})
+		if err != nil {
+			t.Fatalf("RankTracks(%q) strict error = %v", query, err)
+		}
+		if ranked[0].ID != "nin" {
+			t.Errorf("RankTracks(%q) first = %s, want nin", query, ranked[0].ID)
+		}
+	}
+
+	// Two different songs with the exact title are ambiguous
+	_, err = RankTracks("hurt", tracks, &MatchOptions{Strict: true})
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || !ambiguous.Exact || len(ambiguous.Matches) != 2 {
+		t.Fatalf("Expected both exact matches in an ambiguity error, got %v", err)
+	}
+
+	// Nothing matching exactly is an error too
+	if _, err := RankTracks("hurt so bad", tracks, &MatchOptions{Strict: true}); !errors.As(err, &ambiguous) || ambiguous.Exact {
+		t.Errorf("Expected a no exact match error, got %v", err)
+	}
+}
+
+func TestRankTracks_MarketAndDuplicates(t *testing.T) {
+	tracks := []models.Track{
+		scoringTrack("elsewhere", "Song", "Artist", 100, "GB"),
+		scoringTrack("single", "Song", "Artist", 50, "US"),
+		scoringTrack("album", "Song - Remastered", "Artist", 40, "US"),
+	}
+
+	ranked, err := RankTracks("song", tracks, &MatchOptions{Market: "us", Strict: true})
+	if err != nil {
+		t.Fatalf("Expected releases of one song not to be ambiguous, got %v", err)
+	}
+	if ranked[0].ID != "single" || ranked[len(ranked)-1].ID != "elsewhere" {
+		t.Errorf("Expected the playable release first and the unplayable one last, got %v", rankedIDs(ranked))
+	}
+}
+
+func TestRankArtists(t *testing.T) {
+	artists := []models.Artist{
+		{ID: "tribute", Name: "The Beatles Tribute", Popularity: 30},
+		{ID: "beatles", Name: "The Beatles", Popularity: 90},
+	}
+
+	ranked, err := RankArtists("artist:\"the beatles\"", artists, &MatchOptions{Strict: true})
+	if err != nil {
+		t.Fatalf("RankArtists() error = %v", err)
+	}
+	if ranked[0].ID != "beatles" {
+		t.Errorf("Expected the exact name first, got %s", ranked[0].ID)
+	}
+}
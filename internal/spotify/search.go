@@ -92,6 +92,23 @@ func (s *SearchService) SearchTracks(ctx context.Context, query string, options
 	return &result.Tracks, pagination, nil
 }
 
+// SearchTracksPager returns a pager over track search results. Spotify stops
+// returning results after the first 1000 matches.
+func (s *SearchService) SearchTracksPager(query string, options *api.PaginationOptions) *api.Pager[models.Track] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Track], error) {
+		results, _, err := s.SearchTracks(ctx, query, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return results, err
+	})
+}
+
 // SearchAlbums searches for albums only
 func (s *SearchService) SearchAlbums(ctx context.Context, query string, options *api.PaginationOptions) (*models.Paging[models.Album], *api.PaginationInfo, error) {
 	if err := s.validator.ValidateSearchQuery(query); err != nil {
@@ -122,6 +139,22 @@ func (s *SearchService) SearchAlbums(ctx context.Context, query string, options
 	return &result.Albums, pagination, nil
 }
 
+// SearchAlbumsPager returns a pager over album search results
+func (s *SearchService) SearchAlbumsPager(query string, options *api.PaginationOptions) *api.Pager[models.Album] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Album], error) {
+		results, _, err := s.SearchAlbums(ctx, query, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return results, err
+	})
+}
+
 // SearchArtists searches for artists only
 func (s *SearchService) SearchArtists(ctx context.Context, query string, options *api.PaginationOptions) (*models.Paging[models.Artist], *api.PaginationInfo, error) {
 	if err := s.validator.ValidateSearchQuery(query); err != nil {
@@ -152,6 +185,22 @@ func (s *SearchService) SearchArtists(ctx context.Context, query string, options
 	return &result.Artists, pagination, nil
 }
 
+// SearchArtistsPager returns a pager over artist search results
+func (s *SearchService) SearchArtistsPager(query string, options *api.PaginationOptions) *api.Pager[models.Artist] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Artist], error) {
+		results, _, err := s.SearchArtists(ctx, query, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return results, err
+	})
+}
+
 // SearchPlaylists searches for playlists only
 func (s *SearchService) SearchPlaylists(ctx context.Context, query string, options *api.PaginationOptions) (*models.Paging[models.Playlist], *api.PaginationInfo, error) {
 	if err := s.validator.ValidateSearchQuery(query); err != nil {
@@ -182,6 +231,22 @@ func (s *SearchService) SearchPlaylists(ctx context.Context, query string, optio
 	return &result.Playlists, pagination, nil
 }
 
+// SearchPlaylistsPager returns a pager over playlist search results
+func (s *SearchService) SearchPlaylistsPager(query string, options *api.PaginationOptions) *api.Pager[models.Playlist] {
+	pageSize, offset := 50, 0
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		offset = options.Offset
+	}
+
+	return api.NewPager(pageSize, offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Playlist], error) {
+		results, _, err := s.SearchPlaylists(ctx, query, &api.PaginationOptions{Limit: limit, Offset: offset})
+		return results, err
+	})
+}
+
 // validateSearchOptions validates search options
 func (s *SearchService) validateSearchOptions(options *SearchOptions) error {
 	if err := s.validator.ValidateSearchQuery(options.Query); err != nil {
@@ -0,0 +1,152 @@
+package spotify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// ShowsService handles podcast show operations
+type ShowsService struct {
+	client    *api.RequestBuilder
+	validator *api.Validator
+	library   *LibraryService
+}
+
+// NewShowsService creates a new shows service
+func NewShowsService(client *api.RequestBuilder) *ShowsService {
+	return &ShowsService{
+		client:    client,
+		validator: api.NewValidator(),
+		library:   NewLibraryService(client),
+	}
+}
+
+// GetShow gets a show by ID
+func (s *ShowsService) GetShow(ctx context.Context, showID string, market string) (*models.Show, error) {
+	if err := s.validator.ValidateSpotifyID(showID); err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{}
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, err
+		}
+		params["market"] = market
+	}
+
+	var show models.Show
+	err := s.client.Get(ctx, fmt.Sprintf("/shows/%s", showID), params, &show)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get show")
+	}
+
+	return &show, nil
+}
+
+// GetShows gets multiple shows by their IDs
+func (s *ShowsService) GetShows(ctx context.Context, showIDs []string, market string) ([]models.Show, error) {
+	if len(showIDs) == 0 {
+		return nil, errors.NewValidationError("show IDs cannot be empty")
+	}
+
+	if len(showIDs) > 50 {
+		return nil, errors.NewValidationError("cannot request more than 50 shows at once")
+	}
+
+	normalizedIDs, err := s.validator.NormalizeAndValidateIDs(showIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	params := api.QueryParams{
+		"ids": strings.Join(normalizedIDs, ","),
+	}
+
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, err
+		}
+		params["market"] = market
+	}
+
+	var response struct {
+		Shows []models.Show `json:"shows"`
+	}
+
+	err = s.client.Get(ctx, "/shows", params, &response)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get shows")
+	}
+
+	return response.Shows, nil
+}
+
+// GetShowEpisodes gets the episodes of a show with pagination
+func (s *ShowsService) GetShowEpisodes(ctx context.Context, showID string, options *api.PaginationOptions, market string) (*models.Paging[models.Episode], *api.PaginationInfo, error) {
+	if err := s.validator.ValidateSpotifyID(showID); err != nil {
+		return nil, nil, err
+	}
+
+	params := api.QueryParams{}
+	if market != "" {
+		if err := s.validator.ValidateMarket(market); err != nil {
+			return nil, nil, err
+		}
+		params["market"] = market
+	}
+
+	if options != nil {
+		params = options.Merge(params)
+		if err := options.ValidateLimit(1, 50); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var episodes models.Paging[models.Episode]
+	pagination, err := s.client.GetPaginated(ctx, fmt.Sprintf("/shows/%s/episodes", showID), params, &episodes)
+	if err != nil {
+		return nil, nil, errors.WrapAPIError(err, "failed to get show episodes")
+	}
+
+	return &episodes, pagination, nil
+}
+
+// ShowEpisodesPager returns a pager over a show's episodes, newest first
+func (s *ShowsService) ShowEpisodesPager(showID string, market string) *api.Pager[models.Episode] {
+	return api.NewPager(50, 0, func(ctx context.Context, offset, limit int) (*models.Paging[models.Episode], error) {
+		episodes, _, err := s.GetShowEpisodes(ctx, showID, &api.PaginationOptions{Limit: limit, Offset: offset}, market)
+		return episodes, err
+	})
+}
+
+// GetSavedShows gets the shows saved in the user's library. It is the same
+// as LibraryService.GetSavedShows.
+func (s *ShowsService) GetSavedShows(ctx context.Context, options *api.PaginationOptions) (*models.Paging[models.SavedShow], *api.PaginationInfo, error) {
+	return s.library.GetSavedShows(ctx, options)
+}
+
+// SavedShowsPager returns a pager over the user's saved shows
+func (s *ShowsService) SavedShowsPager(options *api.PaginationOptions) *api.Pager[models.SavedShow] {
+	return s.library.SavedShowsPager(options)
+}
+
+// SaveShows saves shows to the user's library
+func (s *ShowsService) SaveShows(ctx context.Context, showIDs []string) error {
+	return s.library.SaveShows(ctx, showIDs)
+}
+
+// RemoveShows removes shows from the user's library
+func (s *ShowsService) RemoveShows(ctx context.Context, showIDs []string) error {
+	return s.library.RemoveShows(ctx, showIDs)
+}
+
+// CheckSavedShows checks if shows are saved in the user's library
+func (s *ShowsService) CheckSavedShows(ctx context.Context, showIDs []string) ([]bool, error) {
+	return s.library.CheckSavedShows(ctx, showIDs)
+}
@@ -0,0 +1,203 @@
+package spotify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
+	"github.com/bambithedeer/spotify-api/internal/client"
+)
+
+// Mock show responses
+var mockShowResponse = `{
+	"id": "38bS44xjbVVZ3No3ByF1dJ",
+	"name": "Test Show",
+	"publisher": "Test Publisher",
+	"description": "A show about tests",
+	"media_type": "audio",
+	"total_episodes": 2,
+	"type": "show",
+	"uri": "spotify:show:38bS44xjbVVZ3No3ByF1dJ"
+}`
+
+var mockShowEpisodesResponse = `{
+	"href": "https://api.spotify.com/v1/shows/38bS44xjbVVZ3No3ByF1dJ/episodes",
+	"items": [
+		{"id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 2", "duration_ms": 1800000, "release_date": "2023-02-01", "type": "episode", "uri": "spotify:episode:512ojhOuo1ktJprKbVcKyQ", "resume_point": {"fully_played": false, "resume_position_ms": 60000}},
+		{"id": "612ojhOuo1ktJprKbVcKyQ", "name": "Episode 1", "duration_ms": 1200000, "release_date": "2023-01-01", "type": "episode", "uri": "spotify:episode:612ojhOuo1ktJprKbVcKyQ"}
+	],
+	"limit": 50,
+	"next": null,
+	"offset": 0,
+	"previous": null,
+	"total": 2
+}`
+
+var mockSavedShowsResponse = `{
+	"href": "https://api.spotify.com/v1/me/shows",
+	"items": [{"added_at": "2023-03-01T00:00:00Z", "show": {"id": "38bS44xjbVVZ3No3ByF1dJ", "name": "Test Show", "publisher": "Test Publisher", "type": "show"}}],
+	"limit": 20,
+	"next": null,
+	"offset": 0,
+	"previous": null,
+	"total": 1
+}`
+
+func createTestShowsService() (*ShowsService, *EpisodesService, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": {"status": 401, "message": "Unauthorized"}}`))
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/shows/38bS44xjbVVZ3No3ByF1dJ":
+			w.Write([]byte(mockShowResponse))
+		case r.URL.Path == "/shows" && strings.Contains(r.URL.RawQuery, "ids="):
+			w.Write([]byte(`{"shows": [` + mockShowResponse + `]}`))
+		case r.URL.Path == "/shows/38bS44xjbVVZ3No3ByF1dJ/episodes":
+			w.Write([]byte(mockShowEpisodesResponse))
+		case r.URL.Path == "/episodes/512ojhOuo1ktJprKbVcKyQ":
+			w.Write([]byte(`{"id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 2", "type": "episode", "show": {"id": "38bS44xjbVVZ3No3ByF1dJ", "name": "Test Show"}}`))
+		case r.URL.Path == "/episodes" && strings.Contains(r.URL.RawQuery, "ids="):
+			w.Write([]byte(`{"episodes": [{"id": "512ojhOuo1ktJprKbVcKyQ", "name": "Episode 2"}, {"id": "612ojhOuo1ktJprKbVcKyQ", "name": "Episode 1"}]}`))
+		case r.URL.Path == "/me/shows" && r.Method == "GET":
+			w.Write([]byte(mockSavedShowsResponse))
+		case r.URL.Path == "/me/shows" && (r.Method == "PUT" || r.Method == "DELETE"):
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/me/shows/contains":
+			w.Write([]byte(`[true, false]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"status": 400, "message": "Bad request"}}`))
+		}
+	}))
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+
+	builder := api.NewRequestBuilder(client)
+	return NewShowsService(builder), NewEpisodesService(builder), server
+}
+
+func TestShowsService_GetShow(t *testing.T) {
+	service, _, server := createTestShowsService()
+	defer server.Close()
+
+	show, err := service.GetShow(context.Background(), "38bS44xjbVVZ3No3ByF1dJ", "US")
+	if err != nil {
+		t.Fatalf("GetShow failed: %v", err)
+	}
+
+	if show.Name != "Test Show" || show.Publisher != "Test Publisher" || show.TotalEpisodes != 2 {
+		t.Errorf("Unexpected show: %+v", show)
+	}
+}
+
+func TestShowsService_GetShows(t *testing.T) {
+	service, _, server := createTestShowsService()
+	defer server.Close()
+
+	shows, err := service.GetShows(context.Background(), []string{"38bS44xjbVVZ3No3ByF1dJ"}, "")
+	if err != nil {
+		t.Fatalf("GetShows failed: %v", err)
+	}
+	if len(shows) != 1 || shows[0].ID != "38bS44xjbVVZ3No3ByF1dJ" {
+		t.Errorf("Unexpected shows: %+v", shows)
+	}
+
+	if _, err := service.GetShows(context.Background(), nil, ""); err == nil {
+		t.Error("Expected error for empty show IDs")
+	}
+}
+
+func TestShowsService_GetShowEpisodes(t *testing.T) {
+	service, _, server := createTestShowsService()
+	defer server.Close()
+
+	episodes, err := service.ShowEpisodesPager("38bS44xjbVVZ3No3ByF1dJ", "").All(context.Background())
+	if err != nil {
+		t.Fatalf("ShowEpisodesPager failed: %v", err)
+	}
+
+	if len(episodes) != 2 {
+		t.Fatalf("Expected 2 episodes, got %d", len(episodes))
+	}
+	if episodes[0].ResumePoint == nil || episodes[0].ResumePoint.ResumePositionMs != 60000 {
+		t.Errorf("Expected resume point on first episode, got %+v", episodes[0].ResumePoint)
+	}
+}
+
+func TestShowsService_SavedShows(t *testing.T) {
+	service, _, server := createTestShowsService()
+	defer server.Close()
+	ctx := context.Background()
+
+	saved, _, err := service.GetSavedShows(ctx, &api.PaginationOptions{Limit: 20})
+	if err != nil {
+		t.Fatalf("GetSavedShows failed: %v", err)
+	}
+	if len(saved.Items) != 1 || saved.Items[0].Show.Name != "Test Show" {
+		t.Errorf("Unexpected saved shows: %+v", saved.Items)
+	}
+
+	ids := []string{"38bS44xjbVVZ3No3ByF1dJ", "48bS44xjbVVZ3No3ByF1dJ"}
+	if err := service.SaveShows(ctx, ids); err != nil {
+		t.Errorf("SaveShows failed: %v", err)
+	}
+	if err := service.RemoveShows(ctx, ids); err != nil {
+		t.Errorf("RemoveShows failed: %v", err)
+	}
+
+	contains, err := service.CheckSavedShows(ctx, ids)
+	if err != nil {
+		t.Fatalf("CheckSavedShows failed: %v", err)
+	}
+	if len(contains) != 2 || !contains[0] || contains[1] {
+		t.Errorf("Unexpected check results: %v", contains)
+	}
+
+	tooMany := make([]string, 51)
+	for i := range tooMany {
+		tooMany[i] = "38bS44xjbVVZ3No3ByF1dJ"
+	}
+	if err := service.SaveShows(ctx, tooMany); err == nil {
+		t.Error("Expected error when saving more than 50 shows")
+	}
+}
+
+func TestEpisodesService_GetEpisode(t *testing.T) {
+	_, service, server := createTestShowsService()
+	defer server.Close()
+
+	episode, err := service.GetEpisode(context.Background(), "512ojhOuo1ktJprKbVcKyQ", "")
+	if err != nil {
+		t.Fatalf("GetEpisode failed: %v", err)
+	}
+	if episode.Name != "Episode 2" || episode.Show == nil || episode.Show.Name != "Test Show" {
+		t.Errorf("Unexpected episode: %+v", episode)
+	}
+
+	episodes, err := service.GetEpisodes(context.Background(), []string{"512ojhOuo1ktJprKbVcKyQ", "612ojhOuo1ktJprKbVcKyQ"}, "US")
+	if err != nil {
+		t.Fatalf("GetEpisodes failed: %v", err)
+	}
+	if len(episodes) != 2 {
+		t.Errorf("Expected 2 episodes, got %d", len(episodes))
+	}
+
+	if _, err := service.GetEpisode(context.Background(), "", ""); err == nil {
+		t.Error("Expected error for empty episode ID")
+	}
+}
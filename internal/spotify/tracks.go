@@ -47,7 +47,8 @@ func (s *TracksService) GetTrack(ctx context.Context, trackID string, market str
 	return &track, nil
 }
 
-// GetTracks gets multiple tracks by their IDs
+// GetTracks gets multiple tracks by their IDs. IDs Spotify has no track
+// for are left out and reported in a *PartialError returned with the rest.
 func (s *TracksService) GetTracks(ctx context.Context, trackIDs []string, market string) ([]models.Track, error) {
 	if len(trackIDs) == 0 {
 		return nil, errors.NewValidationError("track IDs cannot be empty")
@@ -75,7 +76,7 @@ func (s *TracksService) GetTracks(ctx context.Context, trackIDs []string, market
 	}
 
 	var response struct {
-		Tracks []models.Track `json:"tracks"`
+		Tracks []*models.Track `json:"tracks"`
 	}
 
 	err = s.client.Get(ctx, "/tracks", params, &response)
@@ -83,7 +84,7 @@ func (s *TracksService) GetTracks(ctx context.Context, trackIDs []string, market
 		return nil, errors.WrapAPIError(err, "failed to get tracks")
 	}
 
-	return response.Tracks, nil
+	return collectItems("track", normalizedIDs, response.Tracks)
 }
 
 // GetTrackAudioFeatures gets audio features for a track
@@ -133,6 +134,31 @@ func (s *TracksService) GetTracksAudioFeatures(ctx context.Context, trackIDs []s
 	return response.AudioFeatures, nil
 }
 
+// AudioFeaturesBatchSize is the most tracks the audio features endpoint
+// takes per request
+const AudioFeaturesBatchSize = 100
+
+// GetAllTracksAudioFeatures gets audio features for any number of tracks,
+// requesting them in batches. The result lines up with trackIDs; tracks
+// Spotify has no features for get a zero value with an empty ID.
+func (s *TracksService) GetAllTracksAudioFeatures(ctx context.Context, trackIDs []string) ([]models.AudioFeatures, error) {
+	if len(trackIDs) == 0 {
+		return nil, errors.NewValidationError("track IDs cannot be empty")
+	}
+
+	features := make([]models.AudioFeatures, 0, len(trackIDs))
+	for start := 0; start < len(trackIDs); start += AudioFeaturesBatchSize {
+		end := min(start+AudioFeaturesBatchSize, len(trackIDs))
+		batch, err := s.GetTracksAudioFeatures(ctx, trackIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, batch...)
+	}
+
+	return features, nil
+}
+
 // GetTrackAudioAnalysis gets audio analysis for a track
 func (s *TracksService) GetTrackAudioAnalysis(ctx context.Context, trackID string) (*models.AudioAnalysis, error) {
 	if err := s.validator.ValidateSpotifyID(trackID); err != nil {
@@ -158,6 +184,17 @@ func (s *TracksService) GetRecommendations(ctx context.Context, options *Recomme
 		return nil, err
 	}
 
+	// Genres are a fixed list, so check them before asking for recommendations
+	if len(options.SeedGenres) > 0 {
+		available, err := s.GetAvailableGenreSeeds(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validator.ValidateGenreSeeds(options.SeedGenres, available); err != nil {
+			return nil, err
+		}
+	}
+
 	params := s.buildRecommendationParams(options)
 
 	var recommendations models.Recommendations
@@ -169,6 +206,17 @@ func (s *TracksService) GetRecommendations(ctx context.Context, options *Recomme
 	return &recommendations, nil
 }
 
+// GetAvailableGenreSeeds gets the genres that can be used as recommendation seeds
+func (s *TracksService) GetAvailableGenreSeeds(ctx context.Context) ([]string, error) {
+	var genreSeeds models.AvailableGenreSeeds
+	err := s.client.Get(ctx, "/recommendations/available-genre-seeds", nil, &genreSeeds)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to get available genre seeds")
+	}
+
+	return genreSeeds.Genres, nil
+}
+
 // RecommendationOptions contains options for getting recommendations
 type RecommendationOptions struct {
 	SeedArtists  []string                   `json:"seed_artists,omitempty"`
@@ -2,6 +2,8 @@ package spotify
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -233,6 +235,9 @@ func createTestTracksService() (*TracksService, *httptest.Server) {
 		case r.URL.Path == "/audio-analysis/6iV5W9uYEdYUVa79Axb7Rh":
 			w.WriteHeader(http.StatusOK)
 			w.Write([]byte(mockAudioAnalysisResponse))
+		case r.URL.Path == "/recommendations/available-genre-seeds":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"genres": ["acoustic", "hip-hop", "pop", "rock"]}`))
 		case r.URL.Path == "/recommendations":
 			// Check for required seed parameters
 			query := r.URL.Query()
@@ -363,6 +368,42 @@ func TestTracksService_GetTracks(t *testing.T) {
 	}
 }
 
+func TestTracksService_GetTracksPartial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The second ID was removed from the catalog
+		w.Write([]byte(`{"tracks": [{"id": "6iV5W9uYEdYUVa79Axb7Rh", "name": "Test Track 1"}, null, {"id": "8iV5W9uYEdYUVa79Axb7Rh", "name": "Test Track 3"}]}`))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	service := NewTracksService(api.NewRequestBuilder(client))
+
+	trackIDs := []string{"6iV5W9uYEdYUVa79Axb7Rh", "7iV5W9uYEdYUVa79Axb7Rh", "8iV5W9uYEdYUVa79Axb7Rh"}
+	tracks, err := service.GetTracks(context.Background(), trackIDs, "")
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if ids := partial.MissingIDs(); len(ids) != 1 || ids[0] != "7iV5W9uYEdYUVa79Axb7Rh" || partial.Missing[0].Index != 1 {
+		t.Errorf("Expected the second ID to be reported missing, got %+v", partial.Missing)
+	}
+	var itemErr *ItemError
+	if !errors.As(err, &itemErr) || itemErr.Type != "track" {
+		t.Errorf("Expected the per-item error to be reachable, got %v", err)
+	}
+
+	if len(tracks) != 2 || tracks[0].Name != "Test Track 1" || tracks[1].Name != "Test Track 3" {
+		t.Errorf("Expected the found tracks in order, got %+v", tracks)
+	}
+}
+
 func TestTracksService_GetTrackAudioFeatures(t *testing.T) {
 	service, server := createTestTracksService()
 	defer server.Close()
@@ -422,6 +463,58 @@ func TestTracksService_GetTracksAudioFeatures(t *testing.T) {
 	}
 }
 
+func TestTracksService_GetAllTracksAudioFeatures(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ids := strings.Split(r.URL.Query().Get("ids"), ",")
+		if len(ids) > AudioFeaturesBatchSize {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		features := make([]string, len(ids))
+		for i, id := range ids {
+			features[i] = `{"id": "` + id + `", "tempo": 120}`
+		}
+		// Tracks without features come back as null
+		features[0] = "null"
+		w.Write([]byte(`{"audio_features": [` + strings.Join(features, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	service := NewTracksService(api.NewRequestBuilder(client))
+
+	trackIDs := make([]string, 250)
+	for i := range trackIDs {
+		trackIDs[i] = fmt.Sprintf("%022d", i)
+	}
+
+	features, err := service.GetAllTracksAudioFeatures(context.Background(), trackIDs)
+	if err != nil {
+		t.Fatalf("GetAllTracksAudioFeatures failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+	if len(features) != len(trackIDs) {
+		t.Fatalf("Expected %d features, got %d", len(trackIDs), len(features))
+	}
+	if features[0].ID != "" || features[100].ID != "" {
+		t.Error("Expected null features to stay in place as zero values")
+	}
+	if features[249].ID != trackIDs[249] {
+		t.Errorf("Expected features to line up with IDs, got %s", features[249].ID)
+	}
+}
+
 func TestTracksService_GetTrackAudioAnalysis(t *testing.T) {
 	service, server := createTestTracksService()
 	defer server.Close()
@@ -610,6 +703,14 @@ func TestTracksService_RecommendationValidationErrors(t *testing.T) {
 		t.Error("Expected error for empty genre seed")
 	}
 
+	// Test genre seed Spotify does not know
+	_, err = service.GetRecommendations(ctx, &RecommendationOptions{
+		SeedGenres: []string{"rock", "not-a-genre"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not-a-genre") {
+		t.Errorf("Expected error naming the unknown genre seed, got %v", err)
+	}
+
 	// Test invalid limit
 	_, err = service.GetRecommendations(ctx, &RecommendationOptions{
 		SeedGenres: []string{"rock"},
@@ -629,6 +730,20 @@ func TestTracksService_RecommendationValidationErrors(t *testing.T) {
 	}
 }
 
+func TestTracksService_GetAvailableGenreSeeds(t *testing.T) {
+	service, server := createTestTracksService()
+	defer server.Close()
+
+	genres, err := service.GetAvailableGenreSeeds(context.Background())
+	if err != nil {
+		t.Fatalf("GetAvailableGenreSeeds failed: %v", err)
+	}
+
+	if len(genres) != 4 || genres[1] != "hip-hop" {
+		t.Errorf("Unexpected genres: %v", genres)
+	}
+}
+
 func TestTracksService_BuildRecommendationParams(t *testing.T) {
 	service := &TracksService{
 		validator: api.NewValidator(),
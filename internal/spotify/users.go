@@ -2,17 +2,23 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
 	"github.com/bambithedeer/spotify-api/internal/errors"
 	"github.com/bambithedeer/spotify-api/internal/models"
+	"github.com/bambithedeer/spotify-api/internal/ratelimit"
 )
 
 // UsersService handles user profile and following operations
 type UsersService struct {
 	client    *api.RequestBuilder
 	validator *api.Validator
+
+	// profile holds the current user for ProfileTTL; playlist commands
+	// look it up at every step
+	profile *memo[models.User]
 }
 
 // NewUsersService creates a new users service
@@ -20,18 +26,22 @@ func NewUsersService(client *api.RequestBuilder) *UsersService {
 	return &UsersService{
 		client:    client,
 		validator: api.NewValidator(),
+		profile:   newMemo[models.User](ProfileTTL),
 	}
 }
 
-// GetCurrentUser gets the current user's profile
+// GetCurrentUser gets the current user's profile. The profile is reused
+// for ProfileTTL after it is fetched.
 func (s *UsersService) GetCurrentUser(ctx context.Context) (*models.User, error) {
-	var user models.User
-	err := s.client.Get(ctx, "/me", nil, &user)
-	if err != nil {
-		return nil, errors.WrapAPIError(err, "failed to get current user")
-	}
+	return s.profile.get(ctx, func(ctx context.Context) (*models.User, error) {
+		var user models.User
+		err := s.client.Get(ctx, "/me", nil, &user)
+		if err != nil {
+			return nil, errors.WrapAPIError(err, "failed to get current user")
+		}
 
-	return &user, nil
+		return &user, nil
+	})
 }
 
 // GetUser gets a user's profile by ID
@@ -80,93 +90,137 @@ func (s *UsersService) GetFollowedArtists(ctx context.Context, options *Followed
 	return &response.Artists, nil
 }
 
+// FollowedArtistsPager returns a pager over the user's followed artists
+func (s *UsersService) FollowedArtistsPager(options *FollowedArtistsOptions) *api.Pager[models.Artist] {
+	pageSize, after := 50, ""
+	if options != nil {
+		if options.Limit > 0 {
+			pageSize = options.Limit
+		}
+		after = options.After
+	}
+
+	return api.NewCursorPager(pageSize, after, func(ctx context.Context, after string, limit int) (*models.CursorPaging[models.Artist], error) {
+		return s.GetFollowedArtists(ctx, &FollowedArtistsOptions{Limit: limit, After: after})
+	})
+}
+
+
+// Follow types accepted by the following endpoints
+const (
+	FollowTypeArtist = "artist"
+	FollowTypeUser   = "user"
+)
 
 // FollowArtists follows one or more artists
 func (s *UsersService) FollowArtists(ctx context.Context, artistIDs []string) error {
-	if len(artistIDs) == 0 {
-		return errors.NewValidationError("artist IDs cannot be empty")
-	}
+	return s.Follow(ctx, FollowTypeArtist, artistIDs)
+}
 
-	if len(artistIDs) > 50 {
-		return errors.NewValidationError("cannot follow more than 50 artists at once")
-	}
+// UnfollowArtists unfollows one or more artists
+func (s *UsersService) UnfollowArtists(ctx context.Context, artistIDs []string) error {
+	return s.Unfollow(ctx, FollowTypeArtist, artistIDs)
+}
+
+// CheckFollowingArtists checks if the current user follows one or more artists
+func (s *UsersService) CheckFollowingArtists(ctx context.Context, artistIDs []string) ([]bool, error) {
+	return s.CheckFollowing(ctx, FollowTypeArtist, artistIDs)
+}
 
-	// Validate artist IDs
-	normalizedIDs, err := s.validator.NormalizeAndValidateIDs(artistIDs)
+// FollowUsers follows one or more users
+func (s *UsersService) FollowUsers(ctx context.Context, userIDs []string) error {
+	return s.Follow(ctx, FollowTypeUser, userIDs)
+}
+
+// UnfollowUsers unfollows one or more users
+func (s *UsersService) UnfollowUsers(ctx context.Context, userIDs []string) error {
+	return s.Unfollow(ctx, FollowTypeUser, userIDs)
+}
+
+// CheckFollowingUsers checks if the current user follows one or more users
+func (s *UsersService) CheckFollowingUsers(ctx context.Context, userIDs []string) ([]bool, error) {
+	return s.CheckFollowing(ctx, FollowTypeUser, userIDs)
+}
+
+// Follow follows up to 50 artists or users, depending on followType
+func (s *UsersService) Follow(ctx context.Context, followType string, ids []string) error {
+	params, err := s.followingParams(followType, ids, "follow")
 	if err != nil {
 		return err
 	}
 
-	params := api.QueryParams{
-		"type": "artist",
-		"ids":  strings.Join(normalizedIDs, ","),
-	}
-
-	err = s.client.Put(ctx, "/me/following", params, nil)
+	// PUT takes a JSON body, so the parameters go in the URL here
+	endpoint := "/me/following?" + params.ToURLValues().Encode()
+	err = s.client.Put(ctx, endpoint, nil, nil)
 	if err != nil {
-		return errors.WrapAPIError(err, "failed to follow artists")
+		return errors.WrapAPIError(err, "failed to follow "+followType+"s")
 	}
 
 	return nil
 }
 
-// UnfollowArtists unfollows one or more artists
-func (s *UsersService) UnfollowArtists(ctx context.Context, artistIDs []string) error {
-	if len(artistIDs) == 0 {
-		return errors.NewValidationError("artist IDs cannot be empty")
-	}
-
-	if len(artistIDs) > 50 {
-		return errors.NewValidationError("cannot unfollow more than 50 artists at once")
-	}
-
-	// Validate artist IDs
-	normalizedIDs, err := s.validator.NormalizeAndValidateIDs(artistIDs)
+// Unfollow unfollows up to 50 artists or users, depending on followType
+func (s *UsersService) Unfollow(ctx context.Context, followType string, ids []string) error {
+	params, err := s.followingParams(followType, ids, "unfollow")
 	if err != nil {
 		return err
 	}
 
-	params := api.QueryParams{
-		"type": "artist",
-		"ids":  strings.Join(normalizedIDs, ","),
-	}
-
 	err = s.client.Delete(ctx, "/me/following", params)
 	if err != nil {
-		return errors.WrapAPIError(err, "failed to unfollow artists")
+		return errors.WrapAPIError(err, "failed to unfollow "+followType+"s")
 	}
 
 	return nil
 }
 
-// CheckFollowingArtists checks if the current user follows one or more artists
-func (s *UsersService) CheckFollowingArtists(ctx context.Context, artistIDs []string) ([]bool, error) {
-	if len(artistIDs) == 0 {
-		return nil, errors.NewValidationError("artist IDs cannot be empty")
+// CheckFollowing checks if the current user follows up to 50 artists or
+// users, depending on followType. The results are in the same order as ids.
+func (s *UsersService) CheckFollowing(ctx context.Context, followType string, ids []string) ([]bool, error) {
+	params, err := s.followingParams(followType, ids, "check")
+	if err != nil {
+		return nil, err
 	}
 
-	if len(artistIDs) > 50 {
-		return nil, errors.NewValidationError("cannot check more than 50 artists at once")
+	var following []bool
+	err = s.client.Get(ctx, "/me/following/contains", params, &following)
+	if err != nil {
+		return nil, errors.WrapAPIError(err, "failed to check following "+followType+"s")
 	}
 
-	// Validate artist IDs
-	normalizedIDs, err := s.validator.NormalizeAndValidateIDs(artistIDs)
-	if err != nil {
-		return nil, err
+	return following, nil
+}
+
+// followingParams validates the IDs for a following request. Artists have
+// base62 IDs; users are validated as usernames.
+func (s *UsersService) followingParams(followType string, ids []string, action string) (api.QueryParams, error) {
+	if followType != FollowTypeArtist && followType != FollowTypeUser {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid follow type: %s (must be artist or user)", followType))
 	}
 
-	params := api.QueryParams{
-		"type": "artist",
-		"ids":  strings.Join(normalizedIDs, ","),
+	if len(ids) == 0 {
+		return nil, errors.NewValidationError(followType + " IDs cannot be empty")
 	}
 
-	var following []bool
-	err = s.client.Get(ctx, "/me/following/contains", params, &following)
+	if len(ids) > 50 {
+		return nil, errors.NewValidationError(fmt.Sprintf("cannot %s more than 50 %ss at once", action, followType))
+	}
+
+	var normalizedIDs []string
+	var err error
+	if followType == FollowTypeUser {
+		normalizedIDs, err = s.validator.NormalizeUserIDs(ids)
+	} else {
+		normalizedIDs, err = s.validator.NormalizeAndValidateIDs(ids)
+	}
 	if err != nil {
-		return nil, errors.WrapAPIError(err, "failed to check following artists")
+		return nil, err
 	}
 
-	return following, nil
+	return api.QueryParams{
+		"type": followType,
+		"ids":  strings.Join(normalizedIDs, ","),
+	}, nil
 }
 
 // GetTopArtists gets the current user's top artists
@@ -205,6 +259,24 @@ func (s *UsersService) GetTopArtists(ctx context.Context, options *TopItemsOptio
 	return &artists, pagination, nil
 }
 
+// TopArtistsPager returns a pager over the user's top artists
+func (s *UsersService) TopArtistsPager(options *TopItemsOptions) *api.Pager[models.Artist] {
+	opts := TopItemsOptions{Limit: 50}
+	if options != nil {
+		opts = *options
+		if opts.Limit == 0 {
+			opts.Limit = 50
+		}
+	}
+
+	return api.NewPager(opts.Limit, opts.Offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Artist], error) {
+		page := opts
+		page.Offset, page.Limit = offset, limit
+		items, _, err := s.GetTopArtists(ctx, &page)
+		return items, err
+	})
+}
+
 // GetTopTracks gets the current user's top tracks
 func (s *UsersService) GetTopTracks(ctx context.Context, options *TopItemsOptions) (*models.Paging[models.Track], *api.PaginationInfo, error) {
 	params := api.QueryParams{}
@@ -241,6 +313,110 @@ func (s *UsersService) GetTopTracks(ctx context.Context, options *TopItemsOption
 	return &tracks, pagination, nil
 }
 
+// TopTracksPager returns a pager over the user's top tracks
+func (s *UsersService) TopTracksPager(options *TopItemsOptions) *api.Pager[models.Track] {
+	opts := TopItemsOptions{Limit: 50}
+	if options != nil {
+		opts = *options
+		if opts.Limit == 0 {
+			opts.Limit = 50
+		}
+	}
+
+	return api.NewPager(opts.Limit, opts.Offset, func(ctx context.Context, offset, limit int) (*models.Paging[models.Track], error) {
+		page := opts
+		page.Offset, page.Limit = offset, limit
+		items, _, err := s.GetTopTracks(ctx, &page)
+		return items, err
+	})
+}
+
+// TopItemsCap is the most top items the API returns for a time range,
+// however they are paged
+const TopItemsCap = 1000
+
+// topItemsConcurrency is the most top item pages fetched at once; fewer are
+// while Spotify answers with rate limits or server errors
+const topItemsConcurrency = 4
+
+// TopItems is every top artist or track of a time range, fetched at once
+type TopItems[T any] struct {
+	Items     []T    `json:"items"`
+	TimeRange string `json:"time_range,omitempty"`
+	// Total is how many items the API reports for the time range
+	Total int `json:"total"`
+	// Truncated is set when fewer items were fetched than the API has
+	Truncated bool `json:"truncated"`
+}
+
+// GetAllTopArtists gets up to max of the user's top artists for a time range
+// ("" for the API default), fetching the pages after the first concurrently.
+// A max of zero or above TopItemsCap fetches up to TopItemsCap.
+func (s *UsersService) GetAllTopArtists(ctx context.Context, timeRange string, max int) (*TopItems[models.Artist], error) {
+	return fetchAllTopItems(ctx, timeRange, max, func(ctx context.Context, options *TopItemsOptions) (*models.Paging[models.Artist], error) {
+		page, _, err := s.GetTopArtists(ctx, options)
+		return page, err
+	})
+}
+
+// GetAllTopTracks gets up to max of the user's top tracks for a time range,
+// the same way as GetAllTopArtists
+func (s *UsersService) GetAllTopTracks(ctx context.Context, timeRange string, max int) (*TopItems[models.Track], error) {
+	return fetchAllTopItems(ctx, timeRange, max, func(ctx context.Context, options *TopItemsOptions) (*models.Paging[models.Track], error) {
+		page, _, err := s.GetTopTracks(ctx, options)
+		return page, err
+	})
+}
+
+// fetchAllTopItems reads the first page for the total, then the rest of the
+// pages with up to topItemsConcurrency requests at once, keeping API order
+func fetchAllTopItems[T any](ctx context.Context, timeRange string, max int, fetch func(context.Context, *TopItemsOptions) (*models.Paging[T], error)) (*TopItems[T], error) {
+	if max < 0 {
+		return nil, errors.NewValidationError("max cannot be negative")
+	}
+	if max == 0 || max > TopItemsCap {
+		max = TopItemsCap
+	}
+	const pageSize = 50
+
+	first, err := fetch(ctx, &TopItemsOptions{TimeRange: timeRange, Limit: min(pageSize, max)})
+	if err != nil {
+		return nil, err
+	}
+	want := min(first.Total, max)
+
+	var offsets []int
+	for offset := len(first.Items); offset < want; offset += pageSize {
+		offsets = append(offsets, offset)
+	}
+	pages := make([][]T, len(offsets))
+
+	limiter := ratelimit.NewAdaptiveLimiter(topItemsConcurrency)
+	err = limiter.ForEach(ctx, len(offsets), func(ctx context.Context, i int) error {
+		offset := offsets[i]
+		page, err := fetch(ctx, &TopItemsOptions{TimeRange: timeRange, Limit: min(pageSize, want-offset), Offset: offset})
+		if err != nil {
+			return err
+		}
+		pages[i] = page.Items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := append(make([]T, 0, want), first.Items...)
+	for _, page := range pages {
+		items = append(items, page...)
+	}
+	return &TopItems[T]{
+		Items:     items,
+		TimeRange: timeRange,
+		Total:     first.Total,
+		Truncated: len(items) < first.Total,
+	}, nil
+}
+
 // FollowedArtistsOptions contains options for getting followed artists
 type FollowedArtistsOptions struct {
 	Limit int    `json:"limit,omitempty"`
@@ -2,12 +2,17 @@ package spotify
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bambithedeer/spotify-api/internal/api"
+	"github.com/bambithedeer/spotify-api/internal/auth"
 	"github.com/bambithedeer/spotify-api/internal/client"
 )
 
@@ -309,4 +314,160 @@ func TestUsersService_TimeRangeValidation(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "invalid time range") {
 		t.Error("Expected validation error for invalid time range")
 	}
-}
\ No newline at end of file
+}
+
+func TestUsersService_FollowUsers(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		if r.Method == "GET" {
+			w.Write([]byte(`[true, false]`))
+		}
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewUsersService(api.NewRequestBuilder(client))
+
+	ctx := context.Background()
+	users := []string{"spotify:user:alice", "bob"}
+
+	if err := service.FollowUsers(ctx, users); err != nil {
+		t.Fatalf("FollowUsers failed: %v", err)
+	}
+	if err := service.UnfollowUsers(ctx, users); err != nil {
+		t.Fatalf("UnfollowUsers failed: %v", err)
+	}
+	following, err := service.CheckFollowingUsers(ctx, users)
+	if err != nil {
+		t.Fatalf("CheckFollowingUsers failed: %v", err)
+	}
+	if len(following) != 2 || !following[0] || following[1] {
+		t.Errorf("Expected [true false], got %v", following)
+	}
+
+	expected := []string{
+		"PUT /me/following?ids=alice%2Cbob&type=user",
+		"DELETE /me/following?ids=alice%2Cbob&type=user",
+		"GET /me/following/contains?ids=alice%2Cbob&type=user",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("Expected %d requests, got %v", len(expected), requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("Request %d = %q, expected %q", i, requests[i], expected[i])
+		}
+	}
+
+	// User IDs are not base62, but still cannot contain separators
+	if err := service.FollowUsers(ctx, []string{"a,b"}); err == nil {
+		t.Error("Expected error for invalid user ID")
+	}
+	if err := service.Follow(ctx, "show", users); err == nil {
+		t.Error("Expected error for unsupported follow type")
+	}
+}
+
+func TestUsersService_GetAllTopTracks(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	total := 120
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		if r.URL.Query().Get("time_range") != "long_term" {
+			t.Errorf("Expected time_range long_term, got %q", r.URL.RawQuery)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var items []string
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"id": "track%d", "name": "Track %d"}`, i, i))
+		}
+		fmt.Fprintf(w, `{"items": [%s], "limit": %d, "offset": %d, "total": %d}`, strings.Join(items, ","), limit, offset, total)
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{
+		AccessToken: "test_token",
+		TokenType:   "Bearer",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	service := NewUsersService(api.NewRequestBuilder(client))
+	ctx := context.Background()
+
+	top, err := service.GetAllTopTracks(ctx, "long_term", 0)
+	if err != nil {
+		t.Fatalf("GetAllTopTracks failed: %v", err)
+	}
+	if len(top.Items) != 120 || top.Total != 120 || top.Truncated || requests != 3 {
+		t.Fatalf("Expected 120 tracks in 3 requests, got %d (total %d, truncated %t) in %d", len(top.Items), top.Total, top.Truncated, requests)
+	}
+	for i, track := range top.Items {
+		if track.ID != fmt.Sprintf("track%d", i) {
+			t.Fatalf("Expected track%d at %d, got %s", i, i, track.ID)
+		}
+	}
+
+	requests = 0
+	top, err = service.GetAllTopTracks(ctx, "long_term", 70)
+	if err != nil {
+		t.Fatalf("GetAllTopTracks failed: %v", err)
+	}
+	if len(top.Items) != 70 || !top.Truncated || requests != 2 {
+		t.Errorf("Expected 70 tracks in 2 requests, got %d (truncated %t) in %d", len(top.Items), top.Truncated, requests)
+	}
+
+	total = 1500
+	top, err = service.GetAllTopTracks(ctx, "long_term", 5000)
+	if err != nil {
+		t.Fatalf("GetAllTopTracks failed: %v", err)
+	}
+	if len(top.Items) != TopItemsCap || !top.Truncated {
+		t.Errorf("Expected the %d item cap, got %d (truncated %t)", TopItemsCap, len(top.Items), top.Truncated)
+	}
+}
+
+func TestUsersService_GetCurrentUserMemoized(t *testing.T) {
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if fetches == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": {"status": 404, "message": "Not Found"}}`))
+			return
+		}
+		w.Write([]byte(mockCurrentUserResponse))
+	}))
+	defer server.Close()
+
+	client := client.NewClient("test_id", "test_secret", "http://localhost/callback")
+	client.SetBaseURL(server.URL)
+	client.SetToken(&auth.Token{AccessToken: "test_token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+	service := NewUsersService(api.NewRequestBuilder(client))
+	ctx := context.Background()
+
+	// Errors are not held
+	if _, err := service.GetCurrentUser(ctx); err == nil {
+		t.Fatal("Expected the first request to fail")
+	}
+	for i := 0; i < 3; i++ {
+		user, err := service.GetCurrentUser(ctx)
+		if err != nil {
+			t.Fatalf("GetCurrentUser failed: %v", err)
+		}
+		if user.ID != "testuser" {
+			t.Errorf("Expected testuser, got %s", user.ID)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("Expected the profile to be fetched once after the failure, got %d requests", fetches)
+	}
+}
@@ -0,0 +1,350 @@
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive sections
+const (
+	SectionConfig = "config"
+	SectionState  = "state"
+	SectionCache  = "cache"
+)
+
+// ArchiveVersion is the layout version of export archives
+const ArchiveVersion = 1
+
+const (
+	manifestName   = "manifest.json"
+	configFileName = "config.yaml"
+)
+
+// Manifest describes the contents of an export archive
+type Manifest struct {
+	Version         int       `json:"version" yaml:"version"`
+	CreatedAt       time.Time `json:"created_at" yaml:"created_at"`
+	Profile         string    `json:"profile" yaml:"profile"`
+	Sections        []string  `json:"sections" yaml:"sections"`
+	IncludesSecrets bool      `json:"includes_secrets" yaml:"includes_secrets"`
+	Stores          []string  `json:"stores,omitempty" yaml:"stores,omitempty"`
+	CacheEntries    int       `json:"cache_entries" yaml:"cache_entries"`
+}
+
+// Has reports whether the archive contains a section
+func (m *Manifest) Has(section string) bool {
+	for _, s := range m.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportOptions selects what goes into an export archive. Empty fields are
+// left out.
+type ExportOptions struct {
+	Profile         string
+	Config          []byte
+	IncludesSecrets bool
+	CacheDir        string
+}
+
+// Export writes a gzipped tar archive holding the config, every state store
+// and the response cache to w. Stores are locked while they are copied.
+func (d *Dir) Export(w io.Writer, opts ExportOptions) (*Manifest, error) {
+	manifest := &Manifest{
+		Version:         ArchiveVersion,
+		CreatedAt:       time.Now().UTC(),
+		Profile:         opts.Profile,
+		IncludesSecrets: opts.IncludesSecrets,
+	}
+
+	stores, err := d.Stores()
+	if err != nil {
+		return nil, err
+	}
+
+	var cacheFiles []string
+	if opts.CacheDir != "" {
+		cacheFiles, err = listFiles(opts.CacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache directory: %w", err)
+		}
+	}
+
+	if opts.Config != nil {
+		manifest.Sections = append(manifest.Sections, SectionConfig)
+	}
+	manifest.Sections = append(manifest.Sections, SectionState)
+	for _, store := range stores {
+		manifest.Stores = append(manifest.Stores, store.Name)
+	}
+	if opts.CacheDir != "" {
+		manifest.Sections = append(manifest.Sections, SectionCache)
+		manifest.CacheEntries = len(cacheFiles)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := writeArchiveFile(tw, manifestName, manifestData); err != nil {
+		return nil, err
+	}
+
+	if opts.Config != nil {
+		if err := writeArchiveFile(tw, configFileName, opts.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, store := range stores {
+		name := filepath.Base(store.Path)
+		err := d.WithLock(strings.TrimSuffix(name, ".json"), func() error {
+			return addToArchive(tw, store.Path, path.Join(SectionState, name))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", store.Name, err)
+		}
+	}
+
+	for _, file := range cacheFiles {
+		if err := addToArchive(tw, filepath.Join(opts.CacheDir, file), path.Join(SectionCache, file)); err != nil {
+			// Entries can be evicted while we export; losing one is harmless
+			if errors.Is(err, os.ErrNotExist) {
+				manifest.CacheEntries--
+				continue
+			}
+			return nil, fmt.Errorf("failed to export cache: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish export archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ImportOptions selects what is restored from an export archive
+type ImportOptions struct {
+	Sections []string
+	CacheDir string
+	// Overwrite replaces stores that already exist; otherwise they are skipped
+	Overwrite bool
+}
+
+func (o ImportOptions) wants(section string) bool {
+	if len(o.Sections) == 0 {
+		return true
+	}
+	for _, s := range o.Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportResult describes what Import restored
+type ImportResult struct {
+	Manifest     *Manifest
+	Config       []byte   // nil unless the config section was selected and present
+	Restored     []string // state stores written
+	Skipped      []string // state stores that already existed
+	CacheEntries int
+}
+
+// Import restores the selected sections of an export archive. State stores
+// are written under their locks; the config is returned rather than written
+// so the caller can decide how to merge it.
+func (d *Dir) Import(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an export archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	result := &ImportResult{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read export archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if result.Manifest == nil && name != manifestName {
+			return result, fmt.Errorf("not an export archive: missing %s", manifestName)
+		}
+
+		switch {
+		case name == manifestName:
+			if result.Manifest, err = decodeManifest(tr); err != nil {
+				return result, err
+			}
+
+		case name == configFileName:
+			if !opts.wants(SectionConfig) {
+				continue
+			}
+			if result.Config, err = io.ReadAll(tr); err != nil {
+				return result, fmt.Errorf("failed to read config from archive: %w", err)
+			}
+
+		case strings.HasPrefix(name, SectionState+"/"):
+			if !opts.wants(SectionState) {
+				continue
+			}
+			file := strings.TrimPrefix(name, SectionState+"/")
+			storeName := strings.TrimSuffix(file, ".json")
+			if strings.Contains(file, "/") || validateStoreName(storeName) != nil {
+				return result, fmt.Errorf("invalid store %q in export archive", name)
+			}
+
+			restored, err := d.importStore(storeName, d.Path(file), tr, opts.Overwrite)
+			if err != nil {
+				return result, err
+			}
+			if restored {
+				result.Restored = append(result.Restored, storeName)
+			} else {
+				result.Skipped = append(result.Skipped, storeName)
+			}
+
+		case strings.HasPrefix(name, SectionCache+"/"):
+			if !opts.wants(SectionCache) || opts.CacheDir == "" {
+				continue
+			}
+			file := strings.TrimPrefix(name, SectionCache+"/")
+			if strings.Contains(file, "/") || strings.HasPrefix(file, ".") {
+				return result, fmt.Errorf("invalid cache entry %q in export archive", name)
+			}
+			if err := copyToFile(filepath.Join(opts.CacheDir, file), tr); err != nil {
+				return result, fmt.Errorf("failed to restore cache: %w", err)
+			}
+			result.CacheEntries++
+		}
+	}
+
+	if result.Manifest == nil {
+		return result, fmt.Errorf("not an export archive: missing %s", manifestName)
+	}
+
+	return result, nil
+}
+
+// ReadManifest reads the manifest at the start of an export archive
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an export archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	if err != nil || path.Clean(header.Name) != manifestName {
+		return nil, fmt.Errorf("not an export archive: missing %s", manifestName)
+	}
+
+	return decodeManifest(tr)
+}
+
+func (d *Dir) importStore(name, dest string, r io.Reader, overwrite bool) (bool, error) {
+	restored := false
+	err := d.WithLock(name, func() error {
+		if _, err := os.Stat(dest); err == nil && !overwrite {
+			return nil
+		}
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from archive: %w", name, err)
+		}
+		if err := WriteFileAtomic(dest, data, 0600); err != nil {
+			return err
+		}
+		restored = true
+		return nil
+	})
+	return restored, err
+}
+
+func decodeManifest(r io.Reader) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := json.NewDecoder(r).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Version > ArchiveVersion {
+		return nil, fmt.Errorf("export archive version %d is newer than this version of spotify-cli supports (%d)", manifest.Version, ArchiveVersion)
+	}
+	return manifest, nil
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func listFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+			files = append(files, entry.Name())
+		}
+	}
+	return files, nil
+}
+
+func copyToFile(dest string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0700); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(dest, data, 0600)
+}
@@ -0,0 +1,100 @@
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupFileName returns the default file name for a backup taken at t
+func BackupFileName(t time.Time) string {
+	return fmt.Sprintf("state-%s.tar.gz", t.Format("20060102-150405"))
+}
+
+// Backup writes a gzipped tar archive of every store to w. Each store is
+// locked while it is copied, so the archive never contains a half-written
+// file.
+func (d *Dir) Backup(w io.Writer) ([]Store, error) {
+	stores, err := d.Stores()
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, store := range stores {
+		name := filepath.Base(store.Path)
+		lockName := strings.TrimSuffix(name, ".json")
+
+		err := d.WithLock(lockName, func() error {
+			return addToArchive(tw, store.Path, name)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", store.Name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+
+	return stores, nil
+}
+
+// BackupTo writes a backup archive to path, refusing to overwrite an existing file
+func (d *Dir) BackupTo(path string) ([]Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+
+	stores, err := d.Backup(file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write backup file: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	return stores, nil
+}
+
+func addToArchive(tw *tar.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, file)
+	return err
+}
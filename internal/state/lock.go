@@ -0,0 +1,37 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Lock is an exclusive lock on a store, held until Unlock is called or the
+// process exits
+type Lock struct {
+	file *os.File
+	path string
+}
+
+// Path returns the path of the lock file
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Unlock releases the lock
+func (l *Lock) Unlock() error {
+	if l.file == nil {
+		return nil
+	}
+
+	err := unlockFile(l)
+	l.file = nil
+	return err
+}
+
+// lockHolder describes the current process for the lock file contents, which
+// are shown when another process has to give up waiting
+func lockHolder() string {
+	return fmt.Sprintf("pid %d (%s) since %s\n", os.Getpid(), filepath.Base(os.Args[0]), time.Now().Format(time.RFC3339))
+}
@@ -0,0 +1,36 @@
+//go:build !unix
+
+package state
+
+import (
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to have
+// been left behind by a process that crashed
+const staleLockAge = 30 * time.Minute
+
+// tryLock creates path exclusively. Without flock the lock file itself is the
+// lock, so it is removed again on Unlock.
+func tryLock(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+		}
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file.WriteString(lockHolder())
+
+	return &Lock{file: file, path: path}, nil
+}
+
+func unlockFile(l *Lock) error {
+	l.file.Close()
+	return os.Remove(l.path)
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bambithedeer/spotify-api/internal/migrate"
+)
+
+// BackupsDirName is the directory inside the state directory that holds
+// copies of stores taken before they were migrated
+const BackupsDirName = "backups"
+
+// ReadMigrated reads a JSON object store into v after upgrading it with set.
+// If any migration applies, the original file is copied to the backups
+// directory and the migrated store is written back before v is decoded.
+// Stores written by a newer release return an error wrapping migrate.ErrTooNew.
+//
+// Versioned stores should carry a `schema_version` field so that Write keeps
+// the version stamped by the migration.
+func (d *Dir) ReadMigrated(name string, set *migrate.Set, v interface{}) (migrate.Result, error) {
+	var result migrate.Result
+
+	err := d.WithLock(name, func() error {
+		path := d.Path(name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) || (err == nil && len(data) == 0) {
+			result = migrate.Result{From: set.Latest(), To: set.Latest()}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		result, err = set.Migrate(doc)
+		if err != nil {
+			return err
+		}
+
+		if result.Changed() {
+			if _, err := migrate.BackupFile(path, filepath.Join(d.root, BackupsDirName), result.From); err != nil {
+				return err
+			}
+			if err := d.writeUnlocked(name, doc); err != nil {
+				return err
+			}
+			if data, err = json.Marshal(doc); err != nil {
+				return fmt.Errorf("failed to encode %s: %w", name, err)
+			}
+		}
+
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,253 @@
+// Package state manages the local databases kept by spotify-cli, such as
+// listening history and operation journals. Every store is a file in the
+// state directory guarded by its own lock file, so a daemon and an
+// interactive command can safely work on the same data.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirName is the name of the state directory inside a profile directory
+const DirName = "state"
+
+// DefaultLockTimeout is how long Lock waits for another process to release a store
+const DefaultLockTimeout = 10 * time.Second
+
+const lockSuffix = ".lock"
+
+// ErrLocked is returned when a store stays locked by another process for
+// longer than the lock timeout
+var ErrLocked = errors.New("state store is locked by another process")
+
+var storeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*(\.[a-z0-9]+)?$`)
+
+// Dir is a state directory
+type Dir struct {
+	root        string
+	lockTimeout time.Duration
+}
+
+// Open returns the state directory at root, creating it if needed
+func Open(root string) (*Dir, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return &Dir{root: root, lockTimeout: DefaultLockTimeout}, nil
+}
+
+// Root returns the path of the state directory
+func (d *Dir) Root() string {
+	return d.root
+}
+
+// SetLockTimeout changes how long Lock waits for a busy store
+func (d *Dir) SetLockTimeout(timeout time.Duration) {
+	d.lockTimeout = timeout
+}
+
+// Path returns the file path of a store. Names without an extension get ".json".
+func (d *Dir) Path(name string) string {
+	if filepath.Ext(name) == "" {
+		name += ".json"
+	}
+	return filepath.Join(d.root, name)
+}
+
+// Lock acquires the exclusive lock for a store, waiting up to the lock
+// timeout if another process holds it
+func (d *Dir) Lock(name string) (*Lock, error) {
+	if err := validateStoreName(name); err != nil {
+		return nil, err
+	}
+
+	path := d.Path(name) + lockSuffix
+	deadline := time.Now().Add(d.lockTimeout)
+
+	for {
+		lock, err := tryLock(path)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, ErrLocked) {
+			return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+
+		if time.Now().After(deadline) {
+			holder := strings.TrimSpace(readLockHolder(path))
+			if holder != "" {
+				return nil, fmt.Errorf("%w: %s (held by %s)", ErrLocked, name, holder)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrLocked, name)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// WithLock runs fn while holding the lock for a store
+func (d *Dir) WithLock(name string, fn func() error) error {
+	lock, err := d.Lock(name)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return fn()
+}
+
+// Read decodes a JSON store into v. A missing store leaves v untouched and
+// returns nil.
+func (d *Dir) Read(name string, v interface{}) error {
+	return d.WithLock(name, func() error {
+		return d.readUnlocked(name, v)
+	})
+}
+
+// Write atomically replaces a JSON store with v
+func (d *Dir) Write(name string, v interface{}) error {
+	return d.WithLock(name, func() error {
+		return d.writeUnlocked(name, v)
+	})
+}
+
+// Update reads a JSON store into v, calls fn to modify it and writes the
+// result back, holding the lock throughout so concurrent updates are not lost
+func (d *Dir) Update(name string, v interface{}, fn func() error) error {
+	return d.WithLock(name, func() error {
+		if err := d.readUnlocked(name, v); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+		return d.writeUnlocked(name, v)
+	})
+}
+
+// Store describes a file in the state directory
+type Store struct {
+	Name     string    `json:"name" yaml:"name"`
+	Path     string    `json:"path" yaml:"path"`
+	Size     int64     `json:"size" yaml:"size"`
+	Modified time.Time `json:"modified" yaml:"modified"`
+}
+
+// Stores lists the stores in the state directory, sorted by name
+func (d *Dir) Stores() ([]Store, error) {
+	entries, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state directory: %w", err)
+	}
+
+	var stores []Store
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, lockSuffix) || validateStoreName(strings.TrimSuffix(name, ".json")) != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		stores = append(stores, Store{
+			Name:     strings.TrimSuffix(name, ".json"),
+			Path:     filepath.Join(d.root, name),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	sort.Slice(stores, func(i, j int) bool { return stores[i].Name < stores[j].Name })
+	return stores, nil
+}
+
+func (d *Dir) readUnlocked(name string, v interface{}) error {
+	data, err := os.ReadFile(d.Path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func (d *Dir) writeUnlocked(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	return WriteFileAtomic(d.Path(name), data, 0600)
+}
+
+// WriteFileAtomic writes data to a temporary file and renames it over path,
+// so readers never see a partially written file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+func validateStoreName(name string) error {
+	if !storeNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid state store name %q", name)
+	}
+	return nil
+}
+
+func readLockHolder(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
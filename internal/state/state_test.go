@@ -0,0 +1,305 @@
+package state
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/migrate"
+)
+
+func TestDir_ReadWrite(t *testing.T) {
+	dir, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	var missing map[string]int
+	if err := dir.Read("history", &missing); err != nil {
+		t.Errorf("Expected missing store to read as empty, got %v", err)
+	}
+
+	if err := dir.Write("history", map[string]int{"plays": 3}); err != nil {
+		t.Fatalf("Failed to write store: %v", err)
+	}
+
+	var loaded map[string]int
+	if err := dir.Read("history", &loaded); err != nil {
+		t.Fatalf("Failed to read store: %v", err)
+	}
+	if loaded["plays"] != 3 {
+		t.Errorf("Expected 3 plays, got %v", loaded)
+	}
+
+	info, err := os.Stat(dir.Path("history"))
+	if err != nil {
+		t.Fatalf("Failed to stat store: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected store permissions 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestDir_LockTimeout(t *testing.T) {
+	dir, _ := Open(t.TempDir())
+	dir.SetLockTimeout(100 * time.Millisecond)
+
+	lock, err := dir.Lock("history")
+	if err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+
+	if _, err := dir.Lock("history"); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked while the store is held, got %v", err)
+	}
+
+	// Other stores are independent
+	other, err := dir.Lock("journal")
+	if err != nil {
+		t.Errorf("Expected a different store to lock, got %v", err)
+	} else {
+		other.Unlock()
+	}
+
+	lock.Unlock()
+
+	second, err := dir.Lock("history")
+	if err != nil {
+		t.Fatalf("Expected lock after release, got %v", err)
+	}
+	second.Unlock()
+}
+
+func TestDir_ConcurrentUpdates(t *testing.T) {
+	dir, _ := Open(t.TempDir())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var counter struct{ N int }
+			if err := dir.Update("counter", &counter, func() error {
+				counter.N++
+				return nil
+			}); err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var counter struct{ N int }
+	dir.Read("counter", &counter)
+	if counter.N != 20 {
+		t.Errorf("Expected 20 increments, got %d", counter.N)
+	}
+}
+
+func TestDir_InvalidStoreName(t *testing.T) {
+	dir, _ := Open(t.TempDir())
+
+	for _, name := range []string{"", "../escape", "Upper", "a/b"} {
+		if _, err := dir.Lock(name); err == nil {
+			t.Errorf("Expected error for store name %q", name)
+		}
+	}
+}
+
+func TestDir_Backup(t *testing.T) {
+	dir, _ := Open(t.TempDir())
+	dir.Write("history", []string{"a", "b"})
+	dir.Write("journal", map[string]bool{"ok": true})
+
+	// Held locks, foreign files and temp files are not part of the backup
+	lock, _ := dir.Lock("journal")
+	lock.Unlock()
+	os.WriteFile(filepath.Join(dir.Root(), ".history.json.tmp-1"), []byte("partial"), 0600)
+
+	stores, err := dir.Stores()
+	if err != nil {
+		t.Fatalf("Failed to list stores: %v", err)
+	}
+	if len(stores) != 2 || stores[0].Name != "history" || stores[1].Name != "journal" {
+		t.Fatalf("Unexpected stores: %+v", stores)
+	}
+
+	path := filepath.Join(t.TempDir(), "backups", BackupFileName(time.Now()))
+	if _, err := dir.BackupTo(path); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	if _, err := dir.BackupTo(path); err == nil {
+		t.Error("Expected backup not to overwrite an existing file")
+	}
+
+	data, _ := os.ReadFile(path)
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Backup is not gzipped: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read archive: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+
+	if len(names) != 2 || names[0] != "history.json" || names[1] != "journal.json" {
+		t.Errorf("Unexpected archive contents: %v", names)
+	}
+}
+
+func TestDir_ReadMigrated(t *testing.T) {
+	dir, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+
+	set := migrate.NewSet("history", migrate.Migration{
+		Version:     1,
+		Description: "rename count to plays",
+		Apply: func(doc map[string]interface{}) error {
+			doc["plays"] = doc["count"]
+			delete(doc, "count")
+			return nil
+		},
+	})
+
+	if err := dir.Write("history", map[string]int{"count": 3}); err != nil {
+		t.Fatalf("Failed to write store: %v", err)
+	}
+
+	var loaded struct {
+		SchemaVersion int `json:"schema_version"`
+		Plays         int `json:"plays"`
+	}
+	result, err := dir.ReadMigrated("history", set, &loaded)
+	if err != nil {
+		t.Fatalf("ReadMigrated failed: %v", err)
+	}
+	if !result.Changed() || loaded.Plays != 3 || loaded.SchemaVersion != 1 {
+		t.Errorf("Unexpected migration: result=%+v loaded=%+v", result, loaded)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir.Root(), BackupsDirName, "history.json.v0.*.bak"))
+	if len(backups) != 1 {
+		t.Errorf("Expected one pre-migration backup, got %v", backups)
+	}
+
+	if result, err := dir.ReadMigrated("history", set, &loaded); err != nil || result.Changed() {
+		t.Errorf("Expected migrated store to stay current, got %+v (err=%v)", result, err)
+	}
+
+	if err := dir.Write("history", map[string]int{"schema_version": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dir.ReadMigrated("history", set, &loaded); !errors.Is(err, migrate.ErrTooNew) {
+		t.Errorf("Expected ErrTooNew for a newer store, got %v", err)
+	}
+}
+
+func TestDir_ExportImport(t *testing.T) {
+	src, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	if err := src.Write("history", map[string]int{"plays": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Write("journal", map[string]int{"ops": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	cacheDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(cacheDir, "abc.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	manifest, err := src.Export(&archive, ExportOptions{Profile: "default", Config: []byte("client_id: abc\n"), CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(manifest.Stores) != 2 || manifest.CacheEntries != 1 || !manifest.Has(SectionConfig) {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+
+	read, err := ReadManifest(bytes.NewReader(archive.Bytes()))
+	if err != nil || read.Profile != "default" {
+		t.Errorf("Expected to read the manifest back, got %+v (err=%v)", read, err)
+	}
+
+	dest, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dest.Write("journal", map[string]int{"ops": 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Selective restore: state only, existing stores kept
+	result, err := dest.Import(bytes.NewReader(archive.Bytes()), ImportOptions{Sections: []string{SectionState}, CacheDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Config != nil || result.CacheEntries != 0 {
+		t.Errorf("Expected only state to be restored, got %+v", result)
+	}
+	if len(result.Restored) != 1 || result.Restored[0] != "history" || len(result.Skipped) != 1 {
+		t.Errorf("Expected history restored and journal skipped, got %+v", result)
+	}
+
+	var journal map[string]int
+	dest.Read("journal", &journal)
+	if journal["ops"] != 7 {
+		t.Errorf("Expected existing journal to be kept, got %v", journal)
+	}
+
+	// Full restore with overwrite
+	restoredCache := t.TempDir()
+	result, err = dest.Import(bytes.NewReader(archive.Bytes()), ImportOptions{CacheDir: restoredCache, Overwrite: true})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if string(result.Config) != "client_id: abc\n" || result.CacheEntries != 1 || len(result.Restored) != 2 {
+		t.Errorf("Unexpected full restore: %+v", result)
+	}
+	dest.Read("journal", &journal)
+	if journal["ops"] != 1 {
+		t.Errorf("Expected journal to be overwritten, got %v", journal)
+	}
+	if _, err := os.Stat(filepath.Join(restoredCache, "abc.json")); err != nil {
+		t.Errorf("Expected cache entry to be restored: %v", err)
+	}
+}
+
+func TestDir_ImportRejectsBackupArchive(t *testing.T) {
+	dir, err := Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir.Write("history", map[string]int{"plays": 1})
+
+	var backup bytes.Buffer
+	if _, err := dir.Backup(&backup); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dir.Import(&backup, ImportOptions{}); err == nil {
+		t.Error("Expected a plain backup to be rejected as an export archive")
+	}
+}
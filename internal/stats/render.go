@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// TimeRangeLabel describes a top items time range for people
+func TimeRangeLabel(timeRange string) string {
+	switch timeRange {
+	case "short_term":
+		return "the last 4 weeks"
+	case "long_term":
+		return "the last year"
+	default:
+		return "the last 6 months"
+	}
+}
+
+func artistNames(artists []models.SimpleArtist) string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// Markdown renders the report, to keep or to paste into a chat or a wiki
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Listening statistics\n\nTop items are from %s; generated %s.\n", TimeRangeLabel(r.TimeRange), r.GeneratedAt.Format("Jan 2, 2006"))
+
+	if len(r.TopArtists) > 0 {
+		b.WriteString("\n## Top artists\n\n")
+		for i, artist := range r.TopArtists {
+			fmt.Fprintf(&b, "%d. **%s**", i+1, artist.Name)
+			if len(artist.Genres) > 0 {
+				fmt.Fprintf(&b, " (%s)", strings.Join(artist.Genres, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(r.TopTracks) > 0 {
+		b.WriteString("\n## Top tracks\n\n")
+		for i, track := range r.TopTracks {
+			fmt.Fprintf(&b, "%d. **%s** — %s\n", i+1, track.Name, artistNames(track.Artists))
+		}
+	}
+
+	writeShares(&b, "Genres", "Genre", "Artists", r.Genres)
+	writeShares(&b, "Decades", "Decade", "Tracks", r.Decades)
+
+	if f := r.Features; f != nil {
+		fmt.Fprintf(&b, "\n## Audio features\n\nAverage of %d top tracks.\n\n", f.Tracks)
+		b.WriteString("| Feature | Average |\n|---|---:|\n")
+		for _, row := range f.Rows() {
+			fmt.Fprintf(&b, "| %s | %s |\n", row[0], row[1])
+		}
+	}
+
+	if len(r.Growth) > 0 {
+		fmt.Fprintf(&b, "\n## Library growth\n\n%d saved tracks.\n\n", r.LibrarySize)
+		b.WriteString("| Year | Added | Total |\n|---|---:|---:|\n")
+		for _, g := range r.Growth {
+			fmt.Fprintf(&b, "| %d | %d | %d |\n", g.Year, g.Added, g.Total)
+		}
+	}
+	return b.String()
+}
+
+func writeShares(b *strings.Builder, title, column, unit string, shares []Share) {
+	if len(shares) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n| %s | %s | Share |\n|---|---:|---:|\n", title, column, unit)
+	for _, share := range shares {
+		fmt.Fprintf(b, "| %s | %d | %.1f%% |\n", share.Name, share.Count, share.Percent)
+	}
+}
+
+// Rows returns the averages as label and formatted value pairs, in the
+// order reports show them
+func (f *Features) Rows() [][2]string {
+	return [][2]string{
+		{"Danceability", fmt.Sprintf("%.2f", f.Danceability)},
+		{"Energy", fmt.Sprintf("%.2f", f.Energy)},
+		{"Valence", fmt.Sprintf("%.2f", f.Valence)},
+		{"Acousticness", fmt.Sprintf("%.2f", f.Acousticness)},
+		{"Instrumentalness", fmt.Sprintf("%.2f", f.Instrumentalness)},
+		{"Speechiness", fmt.Sprintf("%.2f", f.Speechiness)},
+		{"Tempo", fmt.Sprintf("%.0f BPM", f.Tempo)},
+	}
+}
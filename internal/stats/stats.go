@@ -0,0 +1,183 @@
+// Package stats summarizes a library and the listening behind it: top
+// artists and tracks, how genres and release decades are spread, the
+// average audio features of what is played most, and how the library grew
+// year by year.
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// Share is one value of a distribution and how much of the whole it is
+type Share struct {
+	Name    string  `json:"name" yaml:"name"`
+	Count   int     `json:"count" yaml:"count"`
+	Percent float64 `json:"percent" yaml:"percent"`
+}
+
+// Features is the average of the audio features of a set of tracks. Tempo
+// is in beats per minute; the rest run from 0 to 1.
+type Features struct {
+	Tracks           int     `json:"tracks" yaml:"tracks"`
+	Danceability     float64 `json:"danceability" yaml:"danceability"`
+	Energy           float64 `json:"energy" yaml:"energy"`
+	Valence          float64 `json:"valence" yaml:"valence"`
+	Acousticness     float64 `json:"acousticness" yaml:"acousticness"`
+	Instrumentalness float64 `json:"instrumentalness" yaml:"instrumentalness"`
+	Speechiness      float64 `json:"speechiness" yaml:"speechiness"`
+	Tempo            float64 `json:"tempo" yaml:"tempo"`
+}
+
+// Growth is how many tracks were saved in one year, and the size of the
+// library at its end
+type Growth struct {
+	Year  int `json:"year" yaml:"year"`
+	Added int `json:"added" yaml:"added"`
+	Total int `json:"total" yaml:"total"`
+}
+
+// Report is the whole statistics report
+type Report struct {
+	GeneratedAt time.Time       `json:"generated_at" yaml:"generated_at"`
+	TimeRange   string          `json:"time_range" yaml:"time_range"`
+	TopArtists  []models.Artist `json:"top_artists" yaml:"top_artists"`
+	TopTracks   []models.Track  `json:"top_tracks" yaml:"top_tracks"`
+	Genres      []Share         `json:"genres" yaml:"genres"`
+	Decades     []Share         `json:"decades" yaml:"decades"`
+	// Features is nil when audio features could not be fetched
+	Features    *Features `json:"features,omitempty" yaml:"features,omitempty"`
+	LibrarySize int       `json:"library_size" yaml:"library_size"`
+	Growth      []Growth  `json:"growth" yaml:"growth"`
+}
+
+// Genres counts the artists tagged with each genre and returns the n most
+// common, with their share of the artists. Zero n returns every genre.
+func Genres(artists []models.Artist, n int) []Share {
+	counts := make(map[string]int)
+	for _, artist := range artists {
+		for _, genre := range artist.Genres {
+			counts[genre]++
+		}
+	}
+
+	shares := make([]Share, 0, len(counts))
+	for genre, count := range counts {
+		shares = append(shares, Share{Name: genre, Count: count, Percent: percent(count, len(artists))})
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Count != shares[j].Count {
+			return shares[i].Count > shares[j].Count
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	if n > 0 && len(shares) > n {
+		shares = shares[:n]
+	}
+	return shares
+}
+
+// Decades counts tracks by the decade their album was released in, oldest
+// first. Tracks without a usable release date are left out.
+func Decades(tracks []models.Track) []Share {
+	counts := make(map[int]int)
+	dated := 0
+	for _, track := range tracks {
+		if track.Album == nil {
+			continue
+		}
+		released, ok := track.Album.Released()
+		if !ok {
+			continue
+		}
+		counts[released.Year()/10*10]++
+		dated++
+	}
+
+	decades := make([]int, 0, len(counts))
+	for decade := range counts {
+		decades = append(decades, decade)
+	}
+	sort.Ints(decades)
+
+	shares := make([]Share, len(decades))
+	for i, decade := range decades {
+		shares[i] = Share{Name: fmt.Sprintf("%ds", decade), Count: counts[decade], Percent: percent(counts[decade], dated)}
+	}
+	return shares
+}
+
+// AverageFeatures averages audio features, or returns nil for none.
+// Entries without an ID are tracks the API had no features for.
+func AverageFeatures(features []models.AudioFeatures) *Features {
+	var avg Features
+	for _, f := range features {
+		if f.ID == "" {
+			continue
+		}
+		avg.Tracks++
+		avg.Danceability += f.Danceability
+		avg.Energy += f.Energy
+		avg.Valence += f.Valence
+		avg.Acousticness += f.Acousticness
+		avg.Instrumentalness += f.Instrumentalness
+		avg.Speechiness += f.Speechiness
+		avg.Tempo += f.Tempo
+	}
+	if avg.Tracks == 0 {
+		return nil
+	}
+
+	n := float64(avg.Tracks)
+	avg.Danceability /= n
+	avg.Energy /= n
+	avg.Valence /= n
+	avg.Acousticness /= n
+	avg.Instrumentalness /= n
+	avg.Speechiness /= n
+	avg.Tempo /= n
+	return &avg
+}
+
+// LibraryGrowth counts saved tracks by the year they were saved, oldest
+// first, with the running size of the library. Years in between with no
+// saves are included so the totals read as a timeline.
+func LibraryGrowth(saved []models.SavedTrack) []Growth {
+	counts := make(map[int]int)
+	first, last := 0, 0
+	for _, s := range saved {
+		added, err := time.Parse(time.RFC3339, s.AddedAt)
+		if err != nil {
+			continue
+		}
+		year := added.Year()
+		counts[year]++
+		if first == 0 || year < first {
+			first = year
+		}
+		if year > last {
+			last = year
+		}
+	}
+	if first == 0 {
+		return nil
+	}
+
+	growth := make([]Growth, 0, last-first+1)
+	total := 0
+	for year := first; year <= last; year++ {
+		total += counts[year]
+		growth = append(growth, Growth{Year: year, Added: counts[year], Total: total})
+	}
+	return growth
+}
+
+func percent(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(of)
+}
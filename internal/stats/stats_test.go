@@ -0,0 +1,139 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+func TestGenres(t *testing.T) {
+	artists := []models.Artist{
+		{Name: "A", Genres: []string{"indie", "rock"}},
+		{Name: "B", Genres: []string{"rock"}},
+		{Name: "C", Genres: []string{"jazz", "rock"}},
+		{Name: "D"},
+	}
+
+	genres := Genres(artists, 2)
+	if len(genres) != 2 {
+		t.Fatalf("Expected 2 genres, got %+v", genres)
+	}
+	if genres[0].Name != "rock" || genres[0].Count != 3 || genres[0].Percent != 75 {
+		t.Errorf("Expected rock on 3 of 4 artists first, got %+v", genres[0])
+	}
+	// Ties are broken by name
+	if genres[1].Name != "indie" {
+		t.Errorf("Expected indie second, got %+v", genres[1])
+	}
+
+	if all := Genres(artists, 0); len(all) != 3 {
+		t.Errorf("Expected every genre for n 0, got %+v", all)
+	}
+}
+
+func TestDecades(t *testing.T) {
+	album := func(date string) *models.SimpleAlbum {
+		return &models.SimpleAlbum{ReleaseDatePrecision: models.ReleaseDatePrecision{DateStr: date}}
+	}
+	tracks := []models.Track{
+		{Album: album("1994-05-01")},
+		{Album: album("1999")},
+		{Album: album("2021-03")},
+		{Album: album("")},
+		{},
+	}
+
+	decades := Decades(tracks)
+	if len(decades) != 2 {
+		t.Fatalf("Expected 2 decades, got %+v", decades)
+	}
+	if decades[0].Name != "1990s" || decades[0].Count != 2 {
+		t.Errorf("Expected 2 tracks from the 1990s first, got %+v", decades[0])
+	}
+	if decades[1].Name != "2020s" || decades[1].Count != 1 {
+		t.Errorf("Expected 1 track from the 2020s, got %+v", decades[1])
+	}
+	// Shares are of the dated tracks only
+	if decades[0].Percent+decades[1].Percent != 100 {
+		t.Errorf("Expected shares to add up to 100, got %+v", decades)
+	}
+}
+
+func TestAverageFeatures(t *testing.T) {
+	if AverageFeatures(nil) != nil {
+		t.Error("Expected no features for no tracks")
+	}
+
+	avg := AverageFeatures([]models.AudioFeatures{
+		{ID: "a", Energy: 0.2, Tempo: 100},
+		{ID: "b", Energy: 0.6, Tempo: 140},
+		{}, // no features for this track
+	})
+	if avg == nil || avg.Tracks != 2 {
+		t.Fatalf("Expected the average of 2 tracks, got %+v", avg)
+	}
+	if avg.Energy < 0.399 || avg.Energy > 0.401 || avg.Tempo != 120 {
+		t.Errorf("Expected energy 0.4 and tempo 120, got %+v", avg)
+	}
+}
+
+func TestLibraryGrowth(t *testing.T) {
+	saved := []models.SavedTrack{
+		{AddedAt: "2024-02-01T00:00:00Z"},
+		{AddedAt: "2021-06-01T00:00:00Z"},
+		{AddedAt: "2024-01-01T00:00:00Z"},
+		{AddedAt: "not a date"},
+	}
+
+	growth := LibraryGrowth(saved)
+	want := []Growth{
+		{Year: 2021, Added: 1, Total: 1},
+		{Year: 2022, Added: 0, Total: 1},
+		{Year: 2023, Added: 0, Total: 1},
+		{Year: 2024, Added: 2, Total: 3},
+	}
+	if len(growth) != len(want) {
+		t.Fatalf("Expected %d years, got %+v", len(want), growth)
+	}
+	for i := range want {
+		if growth[i] != want[i] {
+			t.Errorf("Year %d: expected %+v, got %+v", i, want[i], growth[i])
+		}
+	}
+
+	if LibraryGrowth(nil) != nil {
+		t.Error("Expected no growth for an empty library")
+	}
+}
+
+func TestReport_Markdown(t *testing.T) {
+	report := &Report{
+		GeneratedAt: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+		TimeRange:   "short_term",
+		TopArtists:  []models.Artist{{Name: "Artist", Genres: []string{"rock"}}},
+		TopTracks:   []models.Track{{Name: "Song", Artists: []models.SimpleArtist{{Name: "Artist"}}}},
+		Genres:      []Share{{Name: "rock", Count: 1, Percent: 100}},
+		Features:    &Features{Tracks: 1, Tempo: 120},
+		LibrarySize: 3,
+		Growth:      []Growth{{Year: 2024, Added: 3, Total: 3}},
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{
+		"Top items are from the last 4 weeks",
+		"1. **Artist** (rock)",
+		"1. **Song** — Artist",
+		"| rock | 1 | 100.0% |",
+		"| Tempo | 120 BPM |",
+		"| 2024 | 3 | 3 |",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected %q in:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "## Decades") {
+		t.Error("Expected sections without data to be left out")
+	}
+}
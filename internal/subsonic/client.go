@@ -0,0 +1,219 @@
+// Package subsonic is a client for the Subsonic API, as served by Navidrome
+// and most other self-hosted music servers.
+package subsonic
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIVersion is the version of the Subsonic API requests are made with.
+// 1.16.1 is the last one, and the one Navidrome implements.
+const APIVersion = "1.16.1"
+
+// ClientName identifies spotify-cli to the server
+const ClientName = "spotify-cli"
+
+// Client represents a Subsonic API client
+type Client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// Config holds Subsonic client configuration
+type Config struct {
+	BaseURL  string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// Error is a failure the server reported in its response
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("subsonic error %d: %s", e.Code, e.Message)
+}
+
+// Song is a track in the library
+type Song struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
+	// Duration is in seconds
+	Duration int `json:"duration"`
+	Track    int `json:"track"`
+}
+
+// Playlist is a playlist on the server. Entries are only filled in by
+// Playlist, not by Playlists.
+type Playlist struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Comment   string `json:"comment"`
+	SongCount int    `json:"songCount"`
+	Entries   []Song `json:"entry"`
+}
+
+// response is the body of every reply, wrapped in "subsonic-response"
+type response struct {
+	Status        string `json:"status"`
+	Error         *Error `json:"error"`
+	SearchResult3 *struct {
+		Songs []Song `json:"song"`
+	} `json:"searchResult3"`
+	Playlists *struct {
+		Playlists []Playlist `json:"playlist"`
+	} `json:"playlists"`
+	Playlist *Playlist `json:"playlist"`
+}
+
+// NewClient creates a new Subsonic API client
+func NewClient(config Config) *Client {
+	if config.Timeout == 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &Client{
+		baseURL:  strings.TrimSuffix(config.BaseURL, "/"),
+		username: config.Username,
+		password: config.Password,
+		httpClient: &http.Client{
+			Timeout: config.Timeout,
+		},
+	}
+}
+
+// call makes a request to a Subsonic endpoint. Requests are posted as a
+// form, so a playlist of thousands of songs does not overflow the URL, and
+// authenticate with a salted token rather than the password itself.
+func (c *Client) call(method string, params url.Values) (*response, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum([]byte(c.password + salt))
+
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("u", c.username)
+	form.Set("t", hex.EncodeToString(sum[:]))
+	form.Set("s", salt)
+	form.Set("v", APIVersion)
+	form.Set("c", ClientName)
+	form.Set("f", "json")
+
+	resp, err := c.httpClient.PostForm(fmt.Sprintf("%s/rest/%s", c.baseURL, method), form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Response response `json:"subsonic-response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if body.Response.Status != "ok" {
+		if body.Response.Error != nil {
+			return nil, body.Response.Error
+		}
+		return nil, fmt.Errorf("request failed with status %q", body.Response.Status)
+	}
+	return &body.Response, nil
+}
+
+func newSalt() (string, error) {
+	salt := make([]byte, 8)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return hex.EncodeToString(salt), nil
+}
+
+// Ping checks the server can be reached and accepts the credentials
+func (c *Client) Ping() error {
+	_, err := c.call("ping", nil)
+	return err
+}
+
+// Search returns up to count songs matching query
+func (c *Client) Search(query string, count int) ([]Song, error) {
+	resp, err := c.call("search3", url.Values{
+		"query":       {query},
+		"songCount":   {strconv.Itoa(count)},
+		"artistCount": {"0"},
+		"albumCount":  {"0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.SearchResult3 == nil {
+		return nil, nil
+	}
+	return resp.SearchResult3.Songs, nil
+}
+
+// Playlists returns the playlists the user can see
+func (c *Client) Playlists() ([]Playlist, error) {
+	resp, err := c.call("getPlaylists", nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Playlists == nil {
+		return nil, nil
+	}
+	return resp.Playlists.Playlists, nil
+}
+
+// Playlist returns a playlist with its songs
+func (c *Client) Playlist(id string) (*Playlist, error) {
+	resp, err := c.call("getPlaylist", url.Values{"id": {id}})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Playlist == nil {
+		return nil, fmt.Errorf("playlist %s not found", id)
+	}
+	return resp.Playlist, nil
+}
+
+// CreatePlaylist creates a playlist holding the songs, in order
+func (c *Client) CreatePlaylist(name string, songIDs []string) (*Playlist, error) {
+	resp, err := c.call("createPlaylist", url.Values{"name": {name}, "songId": songIDs})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Playlist == nil {
+		return nil, fmt.Errorf("server did not return the playlist it created")
+	}
+	return resp.Playlist, nil
+}
+
+// ReplacePlaylist sets the songs of an existing playlist, in order
+func (c *Client) ReplacePlaylist(id string, songIDs []string) error {
+	_, err := c.call("createPlaylist", url.Values{"playlistId": {id}, "songId": songIDs})
+	return err
+}
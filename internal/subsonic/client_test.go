@@ -0,0 +1,105 @@
+package subsonic
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		sum := md5.Sum([]byte("secret" + r.Form.Get("s")))
+		if r.Form.Get("u") != "alice" || r.Form.Get("t") != hex.EncodeToString(sum[:]) || r.Form.Get("p") != "" {
+			w.Write([]byte(`{"subsonic-response":{"status":"failed","error":{"code":40,"message":"Wrong username or password"}}}`))
+			return
+		}
+		if r.Form.Get("f") != "json" || r.Form.Get("v") != APIVersion {
+			t.Errorf("Unexpected format or version: %v", r.Form)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(Config{BaseURL: server.URL + "/", Username: "alice", Password: "secret"})
+}
+
+func TestPing(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/ping" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","version":"1.16.1"}}`))
+	})
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	client.password = "wrong"
+	err := client.Ping()
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 40 {
+		t.Errorf("Expected a Subsonic error 40 for a wrong password, got %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Form.Get("query") != "karma police" || r.Form.Get("albumCount") != "0" {
+			t.Errorf("Unexpected search: %v", r.Form)
+		}
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","searchResult3":{"song":[
+			{"id":"s1","title":"Karma Police","artist":"Radiohead","album":"OK Computer","duration":264}
+		]}}}`))
+	})
+
+	songs, err := client.Search("karma police", 20)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(songs) != 1 || songs[0].ID != "s1" || songs[0].Duration != 264 {
+		t.Errorf("Search() = %+v", songs)
+	}
+}
+
+func TestCreateAndReplacePlaylist(t *testing.T) {
+	var got []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected songs to be posted, got %s", r.Method)
+		}
+		got = append(got, r.Form.Get("name")+r.Form.Get("playlistId")+":"+strings.Join(r.Form["songId"], ","))
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","playlist":{"id":"p1","name":"Mix","songCount":2}}}`))
+	})
+
+	playlist, err := client.CreatePlaylist("Mix", []string{"s1", "s2"})
+	if err != nil {
+		t.Fatalf("CreatePlaylist() error = %v", err)
+	}
+	if playlist.ID != "p1" {
+		t.Errorf("CreatePlaylist() = %+v", playlist)
+	}
+	if err := client.ReplacePlaylist("p1", []string{"s2", "s3"}); err != nil {
+		t.Fatalf("ReplacePlaylist() error = %v", err)
+	}
+
+	want := []string{"Mix:s1,s2", "p1:s2,s3"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Requests = %v, want %v", got, want)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	if _, err := client.Playlists(); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected the status in the error, got %v", err)
+	}
+}
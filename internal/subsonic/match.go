@@ -0,0 +1,49 @@
+package subsonic
+
+import (
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+// searchCount is how many songs each search returns to choose a match from
+const searchCount = 20
+
+// FindSong searches the library for track and returns the best match, or
+// nil if no song matches. The search is by title and first artist, and by
+// title alone if that finds nothing, for servers that only search titles.
+func (c *Client) FindSong(track trackmatch.Track) (*Song, error) {
+	var queries []string
+	if len(track.Artists) > 0 {
+		queries = append(queries, track.Title+" "+track.Artists[0])
+	}
+	queries = append(queries, track.Title)
+
+	for _, query := range queries {
+		songs, err := c.Search(query, searchCount)
+		if err != nil {
+			return nil, err
+		}
+		if song := BestMatch(track, songs); song != nil {
+			return song, nil
+		}
+	}
+	return nil, nil
+}
+
+// BestMatch picks the song that is track, as trackmatch.Best does, or nil
+func BestMatch(track trackmatch.Track, songs []Song) *Song {
+	candidates := make([]trackmatch.Song, len(songs))
+	for i, song := range songs {
+		candidates[i] = trackmatch.Song{
+			Title:    song.Title,
+			Artist:   song.Artist,
+			Album:    song.Album,
+			Duration: time.Duration(song.Duration) * time.Second,
+		}
+	}
+	if i := trackmatch.Best(track, candidates); i >= 0 {
+		return &songs[i]
+	}
+	return nil
+}
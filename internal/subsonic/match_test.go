@@ -0,0 +1,35 @@
+package subsonic
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/trackmatch"
+)
+
+func TestFindSong(t *testing.T) {
+	var queries []string
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.Form.Get("query"))
+		// Like a server that only searches titles
+		if r.Form.Get("query") != "Hurt" {
+			w.Write([]byte(`{"subsonic-response":{"status":"ok","searchResult3":{}}}`))
+			return
+		}
+		w.Write([]byte(`{"subsonic-response":{"status":"ok","searchResult3":{"song":[
+			{"id":"nin","title":"Hurt","artist":"Nine Inch Nails","album":"The Downward Spiral","duration":373},
+			{"id":"cash","title":"Hurt","artist":"Johnny Cash","album":"American IV","duration":218}
+		]}}}`))
+	})
+
+	song, err := client.FindSong(trackmatch.Track{Title: "Hurt", Artists: []string{"Johnny Cash"}})
+	if err != nil {
+		t.Fatalf("FindSong() error = %v", err)
+	}
+	if song == nil || song.ID != "cash" {
+		t.Errorf("FindSong() = %+v, want the Johnny Cash song", song)
+	}
+	if len(queries) != 2 || queries[0] != "Hurt Johnny Cash" {
+		t.Errorf("Expected a search by title and artist, then by title, got %q", queries)
+	}
+}
@@ -0,0 +1,254 @@
+// Package tags keeps local labels on tracks, albums and playlists, such as
+// "chill" or "dinner", for organizing music beyond what Spotify offers. The
+// tags live in the state directory and can be used to pick sources for
+// queues and queries.
+package tags
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/errors"
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// StoreName is the state store holding tags
+const StoreName = "tags"
+
+// Kinds of item that can be tagged
+var Kinds = []string{"track", "album", "playlist"}
+
+var (
+	uriPattern = regexp.MustCompile(`^spotify:([a-z]+):[0-9A-Za-z]{22}$`)
+	tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,49}$`)
+)
+
+// Tagging is one tag on one item
+type Tagging struct {
+	URI      string    `json:"uri" yaml:"uri"`
+	Tag      string    `json:"tag" yaml:"tag"`
+	TaggedAt time.Time `json:"tagged_at" yaml:"tagged_at"`
+}
+
+// Kind returns the item type of the tagged URI, such as "album"
+func (t Tagging) Kind() string {
+	return Kind(t.URI)
+}
+
+// Kind returns the item type of a Spotify URI, or "" if it is not one
+func Kind(uri string) string {
+	matches := uriPattern.FindStringSubmatch(uri)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// ValidateURI checks that uri is a Spotify URI of a kind that can be tagged
+func ValidateURI(uri string) error {
+	kind := Kind(uri)
+	for _, k := range Kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	if kind == "" {
+		return errors.NewValidationError(fmt.Sprintf("invalid Spotify URI: %s", uri))
+	}
+	return errors.NewValidationError(fmt.Sprintf("%s URIs cannot be tagged (supported: %s)", kind, strings.Join(Kinds, ", ")))
+}
+
+// Normalize lowercases a tag and checks it is a single word of letters,
+// digits, '.', '_' or '-'
+func Normalize(tag string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(tag))
+	if !tagPattern.MatchString(normalized) {
+		return "", errors.NewValidationError(fmt.Sprintf("invalid tag %q: use up to 50 letters, digits, '.', '_' or '-'", tag))
+	}
+	return normalized, nil
+}
+
+func normalizeAll(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		n, err := Normalize(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, n)
+	}
+	return normalized, nil
+}
+
+type store struct {
+	Taggings []Tagging `json:"taggings"`
+}
+
+func (s *store) has(uri, tag string) bool {
+	for _, t := range s.Taggings {
+		if t.URI == uri && t.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Add tags an item and returns the tags it did not already have
+func Add(dir *state.Dir, uri string, tags []string, now time.Time) ([]string, error) {
+	if err := ValidateURI(uri); err != nil {
+		return nil, err
+	}
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	var data store
+	err = dir.Update(StoreName, &data, func() error {
+		for _, tag := range normalized {
+			if data.has(uri, tag) {
+				continue
+			}
+			data.Taggings = append(data.Taggings, Tagging{URI: uri, Tag: tag, TaggedAt: now.UTC()})
+			added = append(added, tag)
+		}
+		return nil
+	})
+	return added, err
+}
+
+// Remove untags an item and returns the tags it had
+func Remove(dir *state.Dir, uri string, tags []string) ([]string, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[string]bool, len(normalized))
+	for _, tag := range normalized {
+		remove[tag] = true
+	}
+
+	var removed []string
+	var data store
+	err = dir.Update(StoreName, &data, func() error {
+		kept := data.Taggings[:0]
+		for _, t := range data.Taggings {
+			if t.URI == uri && remove[t.Tag] {
+				removed = append(removed, t.Tag)
+				continue
+			}
+			kept = append(kept, t)
+		}
+		data.Taggings = kept
+		return nil
+	})
+	return removed, err
+}
+
+// All returns every tagging, oldest first
+func All(dir *state.Dir) ([]Tagging, error) {
+	var data store
+	if err := dir.Read(StoreName, &data); err != nil {
+		return nil, err
+	}
+	return data.Taggings, nil
+}
+
+// Of returns the tags on an item, sorted
+func Of(dir *state.Dir, uri string) ([]string, error) {
+	all, err := All(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, t := range all {
+		if t.URI == uri {
+			tags = append(tags, t.Tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// Find returns the URIs carrying every one of the given tags, in the order
+// they were first tagged. With kind set only URIs of that kind are returned.
+func Find(dir *state.Dir, tags []string, kind string) ([]string, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, errors.NewValidationError("at least one tag is required")
+	}
+
+	all, err := All(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]map[string]bool)
+	var order []string
+	for _, t := range all {
+		if kind != "" && t.Kind() != kind {
+			continue
+		}
+		if matched[t.URI] == nil {
+			matched[t.URI] = make(map[string]bool)
+			order = append(order, t.URI)
+		}
+		matched[t.URI][t.Tag] = true
+	}
+
+	var uris []string
+	for _, uri := range order {
+		if hasAll(matched[uri], normalized) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris, nil
+}
+
+func hasAll(have map[string]bool, want []string) bool {
+	for _, tag := range want {
+		if !have[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// Count is how many items carry a tag
+type Count struct {
+	Tag   string `json:"tag" yaml:"tag"`
+	Items int    `json:"items" yaml:"items"`
+}
+
+// Counts returns every tag in use with its number of items, most used first
+func Counts(dir *state.Dir) ([]Count, error) {
+	all, err := All(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byTag := make(map[string]int)
+	for _, t := range all {
+		byTag[t.Tag]++
+	}
+
+	counts := make([]Count, 0, len(byTag))
+	for tag, n := range byTag {
+		counts = append(counts, Count{Tag: tag, Items: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Items != counts[j].Items {
+			return counts[i].Items > counts[j].Items
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts, nil
+}
@@ -0,0 +1,85 @@
+package tags
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+const (
+	trackURI    = "spotify:track:4iV5W9uYEdYUVa79Axb7Rh"
+	albumURI    = "spotify:album:4aawyAB9vmqN3uQ7FjRGTy"
+	playlistURI = "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"
+)
+
+func TestTags(t *testing.T) {
+	dir, err := state.Open(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("Failed to open state dir: %v", err)
+	}
+	now := time.Now()
+
+	added, err := Add(dir, trackURI, []string{"Chill", "night"}, now)
+	if err != nil {
+		t.Fatalf("Failed to tag: %v", err)
+	}
+	if strings.Join(added, ",") != "chill,night" {
+		t.Errorf("Expected tags to be lowercased, got %v", added)
+	}
+	if added, _ := Add(dir, trackURI, []string{"chill"}, now); len(added) != 0 {
+		t.Errorf("Expected an existing tag not to be added again, got %v", added)
+	}
+	if _, err := Add(dir, albumURI, []string{"chill"}, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Add(dir, playlistURI, []string{"night"}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	uris, err := Find(dir, []string{"chill"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(uris, ",") != trackURI+","+albumURI {
+		t.Errorf("Expected the track then the album, got %v", uris)
+	}
+	if uris, _ := Find(dir, []string{"chill", "night"}, ""); len(uris) != 1 || uris[0] != trackURI {
+		t.Errorf("Expected only the track to have both tags, got %v", uris)
+	}
+	if uris, _ := Find(dir, []string{"night"}, "playlist"); len(uris) != 1 || uris[0] != playlistURI {
+		t.Errorf("Expected only the playlist, got %v", uris)
+	}
+
+	counts, err := Counts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 2 || counts[0].Items != 2 {
+		t.Errorf("Unexpected counts %+v", counts)
+	}
+
+	removed, err := Remove(dir, trackURI, []string{"chill", "unused"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("Expected one tag removed, got %v", removed)
+	}
+	if tags, _ := Of(dir, trackURI); strings.Join(tags, ",") != "night" {
+		t.Errorf("Expected only night left on the track, got %v", tags)
+	}
+}
+
+func TestValidation(t *testing.T) {
+	for _, tag := range []string{"", "two words", "-leading", strings.Repeat("x", 51)} {
+		if _, err := Normalize(tag); err == nil {
+			t.Errorf("Expected tag %q to be rejected", tag)
+		}
+	}
+	if err := ValidateURI("spotify:episode:4iV5W9uYEdYUVa79Axb7Rh"); err == nil {
+		t.Error("Expected episodes to be rejected")
+	}
+}
@@ -0,0 +1,127 @@
+// Package trackcache keeps the tracks of playlists and the library as they
+// were last read, with the version they were at. Reading a large playlist
+// takes a request per 100 tracks; checking its version takes one, so
+// searching it again while it has not changed is served from here.
+package trackcache
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bambithedeer/spotify-api/internal/state"
+)
+
+// Cache is a directory holding one JSON file per collection. A nil Cache
+// keeps nothing, for when caching is disabled.
+type Cache struct {
+	dir string
+}
+
+type entry struct {
+	Version   string          `json:"version"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Items     json.RawMessage `json:"items"`
+}
+
+// New returns the cache kept in dir. The directory is created when the
+// first collection is put.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Dir returns the directory the cache is kept in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, url.PathEscape(key)+".json")
+}
+
+// Get decodes the items kept for key into items and reports whether there
+// were any at version. A damaged entry is a miss, like a missing one.
+func (c *Cache) Get(key, version string, items any) (bool, error) {
+	if c == nil {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read track cache: %w", err)
+	}
+
+	var e entry
+	if json.Unmarshal(data, &e) != nil || e.Version != version {
+		return false, nil
+	}
+	if json.Unmarshal(e.Items, items) != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Put keeps items for key at version, replacing what was there
+func (c *Cache) Put(key, version string, items any) error {
+	if c == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to encode tracks: %w", err)
+	}
+	data, err := json.Marshal(entry{Version: version, FetchedAt: time.Now().UTC(), Items: encoded})
+	if err != nil {
+		return fmt.Errorf("failed to encode tracks: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return state.WriteFileAtomic(c.path(key), data, 0644)
+}
+
+func (c *Cache) files() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read track cache: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, filepath.Join(c.dir, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+// Len returns the number of collections in the cache
+func (c *Cache) Len() (int, error) {
+	files, err := c.files()
+	return len(files), err
+}
+
+// Clear removes every collection and returns how many there were
+func (c *Cache) Clear() (int, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to clear track cache: %w", err)
+		}
+	}
+	return len(files), nil
+}
@@ -0,0 +1,67 @@
+package trackcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type track struct {
+	Name string `json:"name"`
+}
+
+func TestGetAndPut(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "tracks"))
+
+	var got []track
+	if ok, err := c.Get("playlist:abc", "v1", &got); ok || err != nil {
+		t.Fatalf("Get() on an empty cache = %v, %v", ok, err)
+	}
+
+	want := []track{{Name: "One"}, {Name: "Two"}}
+	if err := c.Put("playlist:abc", "v1", want); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if ok, err := c.Get("playlist:abc", "v1", &got); !ok || err != nil {
+		t.Fatalf("Get() = %v, %v, want a hit", ok, err)
+	}
+	if len(got) != 2 || got[1].Name != "Two" {
+		t.Errorf("Get() decoded %+v, want %+v", got, want)
+	}
+
+	if ok, _ := c.Get("playlist:abc", "v2", &got); ok {
+		t.Error("Expected another version to miss")
+	}
+
+	if err := c.Put("library", "10@2024", want[:1]); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if n, err := c.Len(); n != 2 || err != nil {
+		t.Errorf("Len() = %d, %v, want 2", n, err)
+	}
+
+	if err := os.WriteFile(c.path("library"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.Get("library", "10@2024", &got); ok || err != nil {
+		t.Errorf("Get() of a damaged entry = %v, %v, want a miss", ok, err)
+	}
+
+	if n, err := c.Clear(); n != 2 || err != nil {
+		t.Errorf("Clear() = %d, %v, want 2", n, err)
+	}
+	if n, _ := c.Len(); n != 0 {
+		t.Errorf("Len() after Clear() = %d", n)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	if err := c.Put("library", "v1", []track{{Name: "One"}}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	var got []track
+	if ok, err := c.Get("library", "v1", &got); ok || err != nil {
+		t.Errorf("Get() on a nil cache = %v, %v", ok, err)
+	}
+}
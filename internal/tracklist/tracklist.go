@@ -0,0 +1,83 @@
+// Package tracklist is the plain text format playlists are written in for
+// keeping under version control. Each track is a line of its own: its URI,
+// a tab and "Artist - Title". Adding, removing or moving a track changes
+// only its line, so a git diff of two versions reads as the edit made.
+package tracklist
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// NameHeader starts the line naming the playlist, as it does in extended
+// M3U, so a track list can sit in the same folder as M3U files
+const NameHeader = "#PLAYLIST:"
+
+// Entry is a track of a list
+type Entry struct {
+	URI string
+	// Label describes the track for people reading the file; it is not
+	// read back
+	Label string
+}
+
+// List is a playlist as a track list
+type List struct {
+	Name string
+	// Comments are written after the name, one "# " line each. They are
+	// left out of canonical lists, for they hold what changes on every
+	// export, such as its time.
+	Comments []string
+	Entries  []Entry
+}
+
+// Label returns "Artist, Artist - Title", or the title alone without artists
+func Label(artists []string, title string) string {
+	if len(artists) == 0 {
+		return title
+	}
+	return strings.Join(artists, ", ") + " - " + title
+}
+
+// Line formats an entry. Whitespace in the label, tabs and line breaks
+// included, is collapsed to single spaces so the entry stays on its line.
+func Line(entry Entry) string {
+	label := strings.Join(strings.Fields(entry.Label), " ")
+	if label == "" {
+		return entry.URI
+	}
+	return entry.URI + "\t" + label
+}
+
+// Split returns the reference at the start of a line and the label after
+// it. The two are split at the first tab or, in lines typed by hand, the
+// first run of spaces.
+func Split(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if ref, label, ok := strings.Cut(line, "\t"); ok {
+		return strings.TrimSpace(ref), strings.TrimSpace(label)
+	}
+	if i := strings.IndexByte(line, ' '); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i:])
+	}
+	return line, ""
+}
+
+// Write writes a list. Canonical lists have only the name and the entries,
+// so a file rewritten from an unchanged playlist is byte for byte the same.
+func Write(w io.Writer, list *List, canonical bool) error {
+	bw := bufio.NewWriter(w)
+	if name := strings.Join(strings.Fields(list.Name), " "); name != "" {
+		bw.WriteString(NameHeader + name + "\n")
+	}
+	if !canonical {
+		for _, comment := range list.Comments {
+			bw.WriteString("# " + strings.Join(strings.Fields(comment), " ") + "\n")
+		}
+	}
+	for _, entry := range list.Entries {
+		bw.WriteString(Line(entry) + "\n")
+	}
+	return bw.Flush()
+}
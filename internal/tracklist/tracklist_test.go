@@ -0,0 +1,59 @@
+package tracklist
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	list := &List{
+		Name:     "Road  Trip",
+		Comments: []string{"Exported from spotify:playlist:x", "Snapshot abc"},
+		Entries: []Entry{
+			{URI: "spotify:track:4u7EnebtmKWzUH433cf5Qv", Label: Label([]string{"Queen"}, "Bohemian Rhapsody")},
+			{URI: "spotify:track:3n3Ppam7vgaVa1iaRUc9Lp", Label: "Tab\tin the\ntitle "},
+			{URI: "spotify:episode:512ojhOuo1ktJprKbVcKyQ"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, list, false); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := "#PLAYLIST:Road Trip\n" +
+		"# Exported from spotify:playlist:x\n" +
+		"# Snapshot abc\n" +
+		"spotify:track:4u7EnebtmKWzUH433cf5Qv\tQueen - Bohemian Rhapsody\n" +
+		"spotify:track:3n3Ppam7vgaVa1iaRUc9Lp\tTab in the title\n" +
+		"spotify:episode:512ojhOuo1ktJprKbVcKyQ\n"
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, list, true); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("# ")) {
+		t.Errorf("Expected a canonical list without comments, got:\n%s", buf.String())
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line, ref, label string
+	}{
+		{"spotify:track:x\tQueen - Bohemian Rhapsody", "spotify:track:x", "Queen - Bohemian Rhapsody"},
+		{"  spotify:track:x   typed by hand ", "spotify:track:x", "typed by hand"},
+		{"spotify:track:x", "spotify:track:x", ""},
+		{"spotify:track:x\t", "spotify:track:x", ""},
+	}
+	for _, tt := range tests {
+		if ref, label := Split(tt.line); ref != tt.ref || label != tt.label {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", tt.line, ref, label, tt.ref, tt.label)
+		}
+	}
+	if got := Label(nil, "Intro"); got != "Intro" {
+		t.Errorf("Label() = %q, want the title alone", got)
+	}
+}
@@ -0,0 +1,118 @@
+// Package trackmatch finds a Spotify track among the songs of a local music
+// library, whose tags seldom agree with Spotify's to the letter.
+package trackmatch
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Track is a track from Spotify, described well enough to find it elsewhere
+type Track struct {
+	Title    string
+	Artists  []string
+	Album    string
+	Duration time.Duration
+}
+
+// Song is a candidate from the library being searched
+type Song struct {
+	Title  string
+	Artist string
+	Album  string
+	// Duration is zero when the library does not report it
+	Duration time.Duration
+}
+
+// durationSlack is how far apart two lengths can be and still count as the
+// same recording
+const durationSlack = 3 * time.Second
+
+// Best returns the index of the song that is track, or -1 if none is. A song
+// matches when its title is the track's and its artist is one of the
+// track's, ignoring case, punctuation and additions such as "(Live)" or
+// "- 2011 Remaster". A track with no artists, as a hand-written playlist
+// line may be, matches a song by any artist. Among several, one on the same
+// album and then one of about the same length is preferred, and the first
+// of those that tie.
+func Best(track Track, songs []Song) int {
+	title := Normalize(track.Title)
+	if title == "" {
+		return -1
+	}
+	album := Normalize(track.Album)
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		if n := Normalize(artist); n != "" {
+			artists = append(artists, n)
+		}
+	}
+
+	best, bestScore := -1, -1
+	for i, song := range songs {
+		if Normalize(song.Title) != title || !artistMatches(Normalize(song.Artist), artists) {
+			continue
+		}
+
+		score := 0
+		if album != "" && Normalize(song.Album) == album {
+			score += 2
+		}
+		if track.Duration > 0 && song.Duration > 0 {
+			diff := track.Duration - song.Duration
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff <= durationSlack {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// artistMatches reports whether a song's artist is one of the track's. The
+// song's artist may hold several names, as in "Artist feat. Guest".
+func artistMatches(songArtist string, artists []string) bool {
+	if len(artists) == 0 {
+		return true
+	}
+	for _, artist := range artists {
+		if songArtist == artist || strings.Contains(" "+songArtist+" ", " "+artist+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// bracketed matches additions in brackets, such as "(Live)" or "[Remix]"
+	bracketed = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	// dashSuffix matches additions after a dash, such as "- 2011 Remaster"
+	dashSuffix = regexp.MustCompile(`\s+-\s+.*$`)
+)
+
+// BaseTitle returns a title without its bracketed or dashed additions, for
+// libraries whose search wants the words of the title as they are tagged
+func BaseTitle(s string) string {
+	s = bracketed.ReplaceAllString(s, " ")
+	s = dashSuffix.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Normalize reduces a title or name to lowercase words of letters and
+// digits, without bracketed or dashed additions
+func Normalize(s string) string {
+	s = strings.ToLower(BaseTitle(s))
+	s = strings.ReplaceAll(s, "&", " and ")
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,76 @@
+package trackmatch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Karma Police":                         "karma police",
+		"Here Comes the Sun - 2019 Mix":        "here comes the sun",
+		"Hurt (Live) [Remastered]":             "hurt",
+		"Don't Stop Me Now":                    "don t stop me now",
+		"Simon & Garfunkel":                    "simon and garfunkel",
+		"  Beyoncé  ":                          "beyoncé",
+		"(I Can't Get No) Satisfaction":        "satisfaction",
+		"Mr. Brightside":                       "mr brightside",
+		"Smells Like Teen Spirit – Remastered": "smells like teen spirit remastered",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBaseTitle(t *testing.T) {
+	tests := map[string]string{
+		"Don't Stop Me Now - Remastered 2011": "Don't Stop Me Now",
+		"Hurt (Live)  ":                       "Hurt",
+		"(I Can't Get No) Satisfaction":       "Satisfaction",
+		"Mr. Brightside":                      "Mr. Brightside",
+	}
+	for in, want := range tests {
+		if got := BaseTitle(in); got != want {
+			t.Errorf("BaseTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBest(t *testing.T) {
+	track := Track{
+		Title:    "Karma Police - Remastered",
+		Artists:  []string{"Radiohead"},
+		Album:    "OK Computer",
+		Duration: 264 * time.Second,
+	}
+	songs := []Song{
+		{Title: "Karma Police", Artist: "Some Tribute Band", Album: "OK Computer", Duration: 264 * time.Second},
+		{Title: "Karma Police (Live)", Artist: "Radiohead", Album: "I Might Be Wrong", Duration: 290 * time.Second},
+		{Title: "Karma Police", Artist: "Radiohead", Album: "OK Computer OKNOTOK 1997 2017", Duration: 263 * time.Second},
+		{Title: "Karma Police", Artist: "Radiohead", Album: "OK Computer", Duration: 263 * time.Second},
+		{Title: "Airbag", Artist: "Radiohead", Album: "OK Computer", Duration: 284 * time.Second},
+	}
+
+	if got := Best(track, songs); got != 3 {
+		t.Errorf("Best() = %d, want the song on the same album", got)
+	}
+	if got := Best(track, songs[:3]); got != 2 {
+		t.Errorf("Best() = %d, want the song of the same length", got)
+	}
+	if got := Best(track, songs[:1]); got != -1 {
+		t.Errorf("Best() = %d, want no match for another artist", got)
+	}
+
+	featured := Track{Title: "Stay", Artists: []string{"Rihanna", "Mikky Ekko"}}
+	if got := Best(featured, []Song{{Title: "Stay", Artist: "Rihanna feat. Mikky Ekko"}}); got != 0 {
+		t.Error("Expected a song crediting several artists to match")
+	}
+	if got := Best(Track{Title: "Hurt"}, []Song{{Title: "Hurt", Artist: "Johnny Cash"}}); got != 0 {
+		t.Errorf("Expected a track with no artists to match any artist, got %d", got)
+	}
+	if got := Best(Track{Title: "[untitled]"}, []Song{{Title: "(untitled)"}}); got != -1 {
+		t.Errorf("Expected a title with no words to match nothing, got %d", got)
+	}
+}
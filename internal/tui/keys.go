@@ -0,0 +1,96 @@
+package tui
+
+import "unicode/utf8"
+
+// KeyCode identifies a key press that is not plain text
+type KeyCode int
+
+const (
+	// KeyRune is a printable character, held in Key.Rune
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	KeyTab
+	KeyBackTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyCtrlC
+)
+
+// Key is one key press
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// escapes are the terminal sequences for special keys, with and without
+// application cursor mode
+var escapes = map[string]KeyCode{
+	"\x1b[A": KeyUp,
+	"\x1b[B": KeyDown,
+	"\x1b[C": KeyRight,
+	"\x1b[D": KeyLeft,
+	"\x1bOA": KeyUp,
+	"\x1bOB": KeyDown,
+	"\x1bOC": KeyRight,
+	"\x1bOD": KeyLeft,
+	"\x1b[Z": KeyBackTab,
+}
+
+// ParseKeys splits raw terminal input into key presses. A read can hold
+// several keys when typing fast or pasting. Unknown escape sequences are
+// dropped.
+func ParseKeys(input []byte) []Key {
+	var keys []Key
+	for len(input) > 0 {
+		if input[0] == 0x1b {
+			if len(input) == 1 {
+				keys = append(keys, Key{Code: KeyEscape})
+				break
+			}
+			if len(input) >= 3 {
+				if code, ok := escapes[string(input[:3])]; ok {
+					keys = append(keys, Key{Code: code})
+					input = input[3:]
+					continue
+				}
+			}
+			if input[1] != '[' && input[1] != 'O' {
+				keys = append(keys, Key{Code: KeyEscape})
+				input = input[1:]
+				continue
+			}
+			// Skip a CSI sequence such as a function key: parameters up to
+			// the final byte in the @ to ~ range
+			end := 2
+			for end < len(input) && (input[end] < 0x40 || input[end] > 0x7e) {
+				end++
+			}
+			input = input[min(end+1, len(input)):]
+			continue
+		}
+
+		switch input[0] {
+		case '\r', '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case '\t':
+			keys = append(keys, Key{Code: KeyTab})
+		case 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		default:
+			r, size := utf8.DecodeRune(input)
+			if r != utf8.RuneError && r >= ' ' {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bambithedeer/spotify-api/internal/duration"
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+const (
+	bold    = "\033[1m"
+	dim     = "\033[2m"
+	reverse = "\033[7m"
+	reset   = "\033[0m"
+)
+
+// Render draws the whole screen as width by height lines, separated by
+// "\r\n" since the terminal is in raw mode
+func (a *App) Render(width, height int) string {
+	var lines []string
+	lines = append(lines, a.nowPlaying(width)...)
+	lines = append(lines, "", a.tabs(), dim+strings.Repeat("─", width)+reset)
+
+	// Whatever is left between the tabs and the footer holds the pane
+	rows := max(1, height-len(lines)-2)
+	lines = append(lines, a.pane.render(a, width, rows)...)
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	footer := help
+	if a.status != "" {
+		footer = a.status
+	}
+	lines = append(lines[:height-1], dim+fit(footer, width)+reset)
+
+	return strings.Join(lines, "\r\n")
+}
+
+func (a *App) nowPlaying(width int) []string {
+	if a.item == nil {
+		return []string{bold + " Nothing playing" + reset, "", "", a.deviceLine(width)}
+	}
+
+	status := "▶"
+	if a.state == nil || !a.state.IsPlaying {
+		status = "⏸"
+	}
+
+	details := strings.Join(a.item.ArtistNames(), ", ")
+	if album := a.item.AlbumName(); album != "" {
+		details += " — " + album
+	}
+
+	progress, length := 0, a.item.DurationMs()
+	if a.state != nil {
+		progress = a.state.ProgressMs
+	}
+	barWidth := max(10, min(50, width-20))
+
+	return []string{
+		bold + fit(" "+status+" "+a.item.Name(), width) + reset,
+		fit("   "+details, width),
+		fmt.Sprintf("   %s %s %s", duration.Format(progress), bar(progress, length, barWidth), duration.Format(length)),
+		a.deviceLine(width),
+	}
+}
+
+func (a *App) deviceLine(width int) string {
+	if a.state == nil || a.state.Device.Name == "" {
+		return dim + "   No active device" + reset
+	}
+	shuffle := "off"
+	if a.state.ShuffleState {
+		shuffle = "on"
+	}
+	repeat := a.state.RepeatState
+	if repeat == "" {
+		repeat = "off"
+	}
+	line := fmt.Sprintf("   %s · volume %d%% · shuffle %s · repeat %s",
+		a.state.Device.Name, a.state.Device.VolumePercent, shuffle, repeat)
+	return dim + fit(line, width) + reset
+}
+
+func (a *App) tabs() string {
+	var b strings.Builder
+	for pane := Pane(0); pane < paneCount; pane++ {
+		label := fmt.Sprintf(" %d %s ", pane+1, pane)
+		if pane == a.pane {
+			label = reverse + label + reset
+		}
+		b.WriteString(" " + label)
+	}
+	return b.String()
+}
+
+// render draws the rows of a pane, scrolled to keep the cursor in view
+func (p Pane) render(a *App, width, rows int) []string {
+	var lines []string
+	if p == PaneSearch {
+		prompt := "Search: " + a.query
+		if a.editing {
+			prompt = "Search: " + a.input + "█"
+		}
+		lines = append(lines, fit(" "+prompt, width))
+		rows--
+	}
+
+	n := a.length(p)
+	if n == 0 {
+		empty := map[Pane]string{
+			PaneQueue:     " The queue is empty",
+			PanePlaylists: " No playlists",
+			PaneSearch:    " Press / to search",
+		}
+		return append(lines, dim+empty[p]+reset)
+	}
+
+	cursor := a.selected()
+	start := 0
+	if cursor >= rows {
+		start = cursor - rows + 1
+	}
+	for i := start; i < n && i < start+rows; i++ {
+		line := fit(" "+a.row(p, i), width)
+		if i == cursor {
+			line = reverse + line + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(line))) + reset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// row describes item i of a pane on one line
+func (a *App) row(p Pane, i int) string {
+	switch p {
+	case PaneQueue:
+		item := a.queue[i]
+		return describe(item.Name(), item.ArtistNames(), item.DurationMs())
+	case PanePlaylists:
+		playlist := a.lists[i]
+		return fmt.Sprintf("%s  (%d tracks, by %s)", playlist.Name, playlist.Tracks.Total, playlist.Owner.DisplayName)
+	default:
+		track := a.results[i]
+		item := models.PlaylistItem{Track: &track}
+		return describe(item.Name(), item.ArtistNames(), track.DurationMs)
+	}
+}
+
+func describe(name string, artists []string, durationMs int) string {
+	line := name
+	if len(artists) > 0 {
+		line += " — " + strings.Join(artists, ", ")
+	}
+	return line + "  " + duration.Format(durationMs)
+}
+
+// fit cuts s to width characters, marking the cut with an ellipsis
+func fit(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+func bar(progressMs, durationMs, width int) string {
+	filled := 0
+	if durationMs > 0 {
+		filled = min(width, max(0, progressMs*width/durationMs))
+	}
+	return strings.Repeat("━", filled) + strings.Repeat("─", width-filled)
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms where the terminal cannot be put
+// in raw mode
+var ErrUnsupported = errors.New("the terminal interface is not supported on this platform")
+
+// ErrNotTerminal is returned when input or output is not a terminal
+var ErrNotTerminal = errors.New("the terminal interface needs an interactive terminal")
+
+// Run shows the interface on the terminal until the user quits or ctx is
+// done. The playback state is refreshed every interval. The terminal is
+// switched to its alternate screen while running and restored afterwards.
+func Run(ctx context.Context, app *App, in, out *os.File, interval time.Duration) error {
+	if !Supported {
+		return ErrUnsupported
+	}
+	if !isTerminal(in) || !isTerminal(out) {
+		return ErrNotTerminal
+	}
+
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer restore()
+
+	// Alternate screen and hidden cursor, undone in reverse on the way out
+	fmt.Fprint(out, "\033[?1049h\033[?25l")
+	defer fmt.Fprint(out, "\033[?25h\033[?1049l")
+
+	input := make(chan []byte)
+	go func() {
+		defer close(input)
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				return
+			}
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			select {
+			case input <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	draw := func() {
+		width, height, err := terminalSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		width, height = max(width, 20), max(height, 10)
+		// Clearing each line instead of the whole screen avoids flicker
+		view := strings.ReplaceAll(app.Render(width, height), "\r\n", "\033[K\r\n")
+		fmt.Fprint(out, "\033[H"+view+"\033[K\033[J")
+	}
+
+	draw()
+	app.Load(ctx)
+	draw()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			app.RefreshPlayback(ctx)
+		case data, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, key := range ParseKeys(data) {
+				app.HandleKey(ctx, key)
+				if app.Quit() {
+					return nil
+				}
+			}
+		}
+		draw()
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
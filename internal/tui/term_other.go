@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package tui
+
+// Supported reports whether Run can drive the terminal on this platform
+const Supported = false
+
+func makeRaw(fd int) (func(), error) {
+	return nil, ErrUnsupported
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, ErrUnsupported
+}
@@ -0,0 +1,313 @@
+// Package tui is the full-screen terminal interface: now playing at the top,
+// and panes for the queue, playlists and search below, driven from the
+// keyboard. The App holds the screen state and is independent of the
+// terminal, so it can be tested without one; Run connects it to a terminal.
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// VolumeStep is how far + and - move the volume
+const VolumeStep = 10
+
+// Backend is the Spotify API the interface drives
+type Backend interface {
+	State(ctx context.Context) (*models.PlaybackState, error)
+	// Queue returns the tracks and episodes queued after the current one
+	Queue(ctx context.Context) ([]models.PlaylistItem, error)
+	Playlists(ctx context.Context) ([]models.Playlist, error)
+	Search(ctx context.Context, query string) ([]models.Track, error)
+	// Resume continues playback where it was paused
+	Resume(ctx context.Context) error
+	Pause(ctx context.Context) error
+	Next(ctx context.Context) error
+	Previous(ctx context.Context) error
+	SetVolume(ctx context.Context, percent int) error
+	// PlayContext plays an album, artist or playlist from the start
+	PlayContext(ctx context.Context, uri string) error
+	PlayTracks(ctx context.Context, uris []string) error
+	Enqueue(ctx context.Context, uri string) error
+}
+
+// Pane is one of the lists below now playing
+type Pane int
+
+const (
+	PaneQueue Pane = iota
+	PanePlaylists
+	PaneSearch
+	paneCount
+)
+
+func (p Pane) String() string {
+	switch p {
+	case PaneQueue:
+		return "Queue"
+	case PanePlaylists:
+		return "Playlists"
+	default:
+		return "Search"
+	}
+}
+
+// help lists the keys in the footer
+const help = "space play/pause  n/p next/prev  +/- volume  tab pane  / search  enter play  a queue  r refresh  q quit"
+
+// App is the state of the interface
+type App struct {
+	backend Backend
+
+	state   *models.PlaybackState
+	item    *models.PlaylistItem
+	queue   []models.PlaylistItem
+	lists   []models.Playlist
+	results []models.Track
+	query   string
+
+	pane   Pane
+	cursor [paneCount]int
+
+	// editing is set while typing a search, with the text so far in input
+	editing bool
+	input   string
+
+	status string
+	quit   bool
+}
+
+// NewApp creates an interface driving backend
+func NewApp(backend Backend) *App {
+	return &App{backend: backend}
+}
+
+// Quit reports whether the user asked to leave
+func (a *App) Quit() bool {
+	return a.quit
+}
+
+// Load fetches everything shown: playback, the queue and playlists
+func (a *App) Load(ctx context.Context) {
+	a.RefreshPlayback(ctx)
+
+	lists, err := a.backend.Playlists(ctx)
+	if err != nil {
+		a.status = fmt.Sprintf("Failed to load playlists: %v", err)
+		return
+	}
+	a.lists = lists
+}
+
+// RefreshPlayback fetches the playback state and queue, which change as
+// tracks play
+func (a *App) RefreshPlayback(ctx context.Context) {
+	state, err := a.backend.State(ctx)
+	if err != nil {
+		a.status = fmt.Sprintf("Failed to get playback state: %v", err)
+		return
+	}
+	a.state = state
+	a.item = decodeItem(state.Item)
+
+	queue, err := a.backend.Queue(ctx)
+	if err != nil {
+		a.status = fmt.Sprintf("Failed to get queue: %v", err)
+		return
+	}
+	a.queue = queue
+}
+
+// decodeItem converts the loosely typed item of a playback state to a
+// track or episode
+func decodeItem(raw interface{}) *models.PlaylistItem {
+	if raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var item models.PlaylistItem
+	if err := json.Unmarshal(data, &item); err != nil || item.URI() == "" {
+		return nil
+	}
+	return &item
+}
+
+// HandleKey applies a key press. Playback keys call the backend and then
+// refresh the playback state to show the result.
+func (a *App) HandleKey(ctx context.Context, key Key) {
+	if a.editing {
+		a.handleInput(ctx, key)
+		return
+	}
+
+	switch {
+	case key.Code == KeyCtrlC || key.Rune == 'q':
+		a.quit = true
+	case key.Code == KeyTab:
+		a.pane = (a.pane + 1) % paneCount
+	case key.Code == KeyBackTab:
+		a.pane = (a.pane + paneCount - 1) % paneCount
+	case key.Rune >= '1' && key.Rune <= '3':
+		a.pane = Pane(key.Rune - '1')
+	case key.Code == KeyUp || key.Rune == 'k':
+		a.move(-1)
+	case key.Code == KeyDown || key.Rune == 'j':
+		a.move(1)
+	case key.Rune == '/':
+		a.pane = PaneSearch
+		a.editing = true
+		a.input = a.query
+	case key.Rune == 'r':
+		a.status = ""
+		a.Load(ctx)
+	case key.Rune == ' ':
+		a.playback(ctx, "toggle playback", a.togglePlayback)
+	case key.Rune == 'n' || key.Code == KeyRight:
+		a.playback(ctx, "skip", a.backend.Next)
+	case key.Rune == 'p' || key.Code == KeyLeft:
+		a.playback(ctx, "go back", a.backend.Previous)
+	case key.Rune == '+' || key.Rune == '=':
+		a.changeVolume(ctx, VolumeStep)
+	case key.Rune == '-':
+		a.changeVolume(ctx, -VolumeStep)
+	case key.Code == KeyEnter:
+		a.activate(ctx)
+	case key.Rune == 'a':
+		a.enqueue(ctx)
+	}
+}
+
+func (a *App) handleInput(ctx context.Context, key Key) {
+	switch key.Code {
+	case KeyEscape, KeyCtrlC:
+		a.editing = false
+	case KeyEnter:
+		a.editing = false
+		a.search(ctx, strings.TrimSpace(a.input))
+	case KeyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(a.input); size > 0 {
+			a.input = a.input[:len(a.input)-size]
+		}
+	case KeyRune:
+		a.input += string(key.Rune)
+	}
+}
+
+func (a *App) search(ctx context.Context, query string) {
+	if query == "" {
+		return
+	}
+	results, err := a.backend.Search(ctx, query)
+	if err != nil {
+		a.status = fmt.Sprintf("Search failed: %v", err)
+		return
+	}
+	a.query, a.results = query, results
+	a.cursor[PaneSearch] = 0
+	a.status = fmt.Sprintf("%d results for %q", len(results), query)
+}
+
+// length is the number of rows in a pane
+func (a *App) length(pane Pane) int {
+	switch pane {
+	case PaneQueue:
+		return len(a.queue)
+	case PanePlaylists:
+		return len(a.lists)
+	default:
+		return len(a.results)
+	}
+}
+
+func (a *App) move(delta int) {
+	n := a.length(a.pane)
+	if n == 0 {
+		return
+	}
+	a.cursor[a.pane] = min(n-1, max(0, a.cursor[a.pane]+delta))
+}
+
+// selected returns the cursor position in the current pane, or -1 for an
+// empty pane
+func (a *App) selected() int {
+	n := a.length(a.pane)
+	if n == 0 {
+		return -1
+	}
+	return min(a.cursor[a.pane], n-1)
+}
+
+func (a *App) togglePlayback(ctx context.Context) error {
+	if a.state != nil && a.state.IsPlaying {
+		return a.backend.Pause(ctx)
+	}
+	return a.backend.Resume(ctx)
+}
+
+func (a *App) changeVolume(ctx context.Context, delta int) {
+	if a.state == nil || a.state.Device.ID == "" {
+		a.status = "No active device"
+		return
+	}
+	volume := min(100, max(0, a.state.Device.VolumePercent+delta))
+	a.playback(ctx, "set volume", func(ctx context.Context) error {
+		return a.backend.SetVolume(ctx, volume)
+	})
+}
+
+// playback runs a player command and refreshes the state to show its effect
+func (a *App) playback(ctx context.Context, action string, run func(context.Context) error) {
+	if err := run(ctx); err != nil {
+		a.status = fmt.Sprintf("Failed to %s: %v", action, err)
+		return
+	}
+	a.status = ""
+	a.RefreshPlayback(ctx)
+}
+
+// activate plays the selected playlist or search result
+func (a *App) activate(ctx context.Context) {
+	i := a.selected()
+	if i < 0 {
+		return
+	}
+
+	switch a.pane {
+	case PanePlaylists:
+		playlist := a.lists[i]
+		a.playback(ctx, "play playlist", func(ctx context.Context) error {
+			return a.backend.PlayContext(ctx, playlist.URI)
+		})
+	case PaneSearch:
+		track := a.results[i]
+		a.playback(ctx, "play track", func(ctx context.Context) error {
+			return a.backend.PlayTracks(ctx, []string{track.URI})
+		})
+	default:
+		a.status = "Queued items play in order; press n to skip ahead"
+	}
+}
+
+// enqueue adds the selected search result to the queue
+func (a *App) enqueue(ctx context.Context) {
+	i := a.selected()
+	if a.pane != PaneSearch || i < 0 {
+		return
+	}
+
+	track := a.results[i]
+	if err := a.backend.Enqueue(ctx, track.URI); err != nil {
+		a.status = fmt.Sprintf("Failed to add to queue: %v", err)
+		return
+	}
+	a.RefreshPlayback(ctx)
+	a.status = fmt.Sprintf("Added %s to the queue", track.Name)
+}
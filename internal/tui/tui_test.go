@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bambithedeer/spotify-api/internal/models"
+)
+
+// fakeBackend records the commands it receives
+type fakeBackend struct {
+	state    models.PlaybackState
+	results  []models.Track
+	played   []string
+	queued   []string
+	commands []string
+}
+
+func (f *fakeBackend) State(ctx context.Context) (*models.PlaybackState, error) {
+	state := f.state
+	return &state, nil
+}
+
+func (f *fakeBackend) Queue(ctx context.Context) ([]models.PlaylistItem, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) Playlists(ctx context.Context) ([]models.Playlist, error) {
+	return []models.Playlist{{Name: "Mix", URI: "spotify:playlist:mix"}}, nil
+}
+
+func (f *fakeBackend) Search(ctx context.Context, query string) ([]models.Track, error) {
+	return f.results, nil
+}
+
+func (f *fakeBackend) Resume(ctx context.Context) error {
+	f.commands = append(f.commands, "resume")
+	f.state.IsPlaying = true
+	return nil
+}
+
+func (f *fakeBackend) Pause(ctx context.Context) error {
+	f.commands = append(f.commands, "pause")
+	f.state.IsPlaying = false
+	return nil
+}
+
+func (f *fakeBackend) Next(ctx context.Context) error {
+	f.commands = append(f.commands, "next")
+	return nil
+}
+
+func (f *fakeBackend) Previous(ctx context.Context) error {
+	f.commands = append(f.commands, "previous")
+	return nil
+}
+
+func (f *fakeBackend) SetVolume(ctx context.Context, percent int) error {
+	f.state.Device.VolumePercent = percent
+	return nil
+}
+
+func (f *fakeBackend) PlayContext(ctx context.Context, uri string) error {
+	f.played = append(f.played, uri)
+	return nil
+}
+
+func (f *fakeBackend) PlayTracks(ctx context.Context, uris []string) error {
+	f.played = append(f.played, uris...)
+	return nil
+}
+
+func (f *fakeBackend) Enqueue(ctx context.Context, uri string) error {
+	f.queued = append(f.queued, uri)
+	return nil
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys([]byte("a\x1b[A\r\x7f\x1b[Z\x1b[15~é\x03"))
+	want := []Key{
+		{Code: KeyRune, Rune: 'a'},
+		{Code: KeyUp},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyBackTab},
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyCtrlC},
+	}
+
+	if len(keys) != len(want) {
+		t.Fatalf("Expected %d keys, got %v", len(want), keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("Key %d = %+v, expected %+v", i, keys[i], want[i])
+		}
+	}
+
+	if keys := ParseKeys([]byte("\x1b")); len(keys) != 1 || keys[0].Code != KeyEscape {
+		t.Errorf("Expected a lone escape, got %v", keys)
+	}
+}
+
+func TestApp_Playback(t *testing.T) {
+	backend := &fakeBackend{state: models.PlaybackState{
+		Device: models.Device{ID: "desk", Name: "Desktop", VolumePercent: 95},
+		Item:   map[string]interface{}{"uri": "spotify:track:1", "name": "Song", "type": "track"},
+	}}
+	app := NewApp(backend)
+	ctx := context.Background()
+	app.Load(ctx)
+
+	for _, key := range ParseKeys([]byte(" n ++")) {
+		app.HandleKey(ctx, key)
+	}
+
+	if strings.Join(backend.commands, ",") != "resume,next,pause" {
+		t.Errorf("Unexpected commands %v", backend.commands)
+	}
+	if backend.state.Device.VolumePercent != 100 {
+		t.Errorf("Expected the volume to stop at 100, got %d", backend.state.Device.VolumePercent)
+	}
+
+	screen := app.Render(80, 20)
+	if !strings.Contains(screen, "Song") || !strings.Contains(screen, "volume 100%") {
+		t.Errorf("Expected now playing in the screen:\n%s", screen)
+	}
+	if lines := strings.Count(screen, "\r\n") + 1; lines != 20 {
+		t.Errorf("Expected 20 lines, got %d", lines)
+	}
+}
+
+func TestApp_HourLongItem(t *testing.T) {
+	backend := &fakeBackend{state: models.PlaybackState{
+		Device:     models.Device{ID: "desk", Name: "Desktop"},
+		ProgressMs: 61000,
+		Item:       map[string]interface{}{"uri": "spotify:episode:1", "name": "Episode", "type": "episode", "duration_ms": 3661000},
+	}}
+	app := NewApp(backend)
+	app.Load(context.Background())
+
+	screen := app.Render(80, 20)
+	if !strings.Contains(screen, " 1:01 ") || !strings.Contains(screen, " 1:01:01") {
+		t.Errorf("Expected the progress as M:SS and the length as H:MM:SS:\n%s", screen)
+	}
+}
+
+func TestApp_SearchAndPlay(t *testing.T) {
+	backend := &fakeBackend{results: []models.Track{
+		{Name: "First", URI: "spotify:track:first"},
+		{Name: "Second", URI: "spotify:track:second"},
+	}}
+	app := NewApp(backend)
+	ctx := context.Background()
+	app.Load(ctx)
+
+	// While typing, shortcut keys are part of the query
+	for _, key := range ParseKeys([]byte("/queen\r")) {
+		app.HandleKey(ctx, key)
+	}
+	if app.query != "queen" || app.pane != PaneSearch {
+		t.Fatalf("Expected a search for 'queen', got %q in pane %s", app.query, app.pane)
+	}
+	if app.Quit() {
+		t.Fatal("Expected q in a search not to quit")
+	}
+
+	for _, key := range ParseKeys([]byte("j\ra")) {
+		app.HandleKey(ctx, key)
+	}
+	if len(backend.played) != 1 || backend.played[0] != "spotify:track:second" {
+		t.Errorf("Expected the second result to play, got %v", backend.played)
+	}
+	if len(backend.queued) != 1 || backend.queued[0] != "spotify:track:second" {
+		t.Errorf("Expected the second result to be queued, got %v", backend.queued)
+	}
+
+	app.HandleKey(ctx, Key{Code: KeyTab})
+	app.HandleKey(ctx, Key{Code: KeyTab})
+	app.HandleKey(ctx, Key{Code: KeyEnter})
+	if backend.played[len(backend.played)-1] != "spotify:playlist:mix" {
+		t.Errorf("Expected the playlist to play, got %v", backend.played)
+	}
+
+	app.HandleKey(ctx, Key{Code: KeyRune, Rune: 'q'})
+	if !app.Quit() {
+		t.Error("Expected q to quit")
+	}
+}